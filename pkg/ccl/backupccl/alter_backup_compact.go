@@ -0,0 +1,550 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/types"
+)
+
+type backupCompactionResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &backupCompactionResumer{}
+
+// startBackupCompactionJob starts a job compacting the incremental layers of
+// the backup in subdir of collection whose end times are within [start, end],
+// and returns its ID. Either bound may be empty to leave that side unbounded.
+// The layers are selected when the job is created, so that invalid bounds are
+// reported by the statement.
+func startBackupCompactionJob(
+	ctx context.Context,
+	p sql.PlanHookState,
+	collection string,
+	subdir string,
+	backup string,
+	start, end hlc.Timestamp,
+	resultsCh chan<- tree.Datums,
+) error {
+	if len(backup) < 1 {
+		return errors.New("invalid base backup specified")
+	}
+	execCfg := p.ExecCfg()
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+
+	baseStore, err := mkStore(ctx, backup, p.User())
+	if err != nil {
+		return errors.Wrapf(err, "failed to open backup storage location")
+	}
+	defer baseStore.Close()
+
+	if _, err := backupencryption.ReadEncryptionOptions(ctx, baseStore); err == nil {
+		return errors.New("compacting encrypted backups is not supported")
+	} else if !errors.Is(err, backupencryption.ErrEncryptionInfoRead) {
+		return err
+	}
+
+	fullyResolvedIncrementalsDirectory, err := backupdest.ResolveIncrementalsBackupLocation(
+		ctx, p.User(), execCfg, nil /* explicitIncrementalCollections */, []string{collection}, subdir)
+	if err != nil {
+		return err
+	}
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	_, manifests, localityInfo, memReserved, err := resolveBackupManifests(
+		ctx, &mem, []cloud.ExternalStorage{baseStore}, mkStore, []string{backup},
+		fullyResolvedIncrementalsDirectory, hlc.Timestamp{}, nil /* encryption */, p.User())
+	if err != nil {
+		return err
+	}
+	defer mem.Shrink(ctx, memReserved)
+
+	for i := range localityInfo {
+		if len(localityInfo[i].URIsByOriginalLocalityKV) > 0 {
+			return errors.New("compacting locality aware backups is not supported")
+		}
+	}
+
+	first, last, err := selectLayersToCompact(manifests, start, end)
+	if err != nil {
+		return err
+	}
+	details := jobspb.BackupCompactionDetails{
+		FullBackupURIs:         []string{backup},
+		IncrementalDirectories: fullyResolvedIncrementalsDirectory,
+		StartTime:              manifests[first].StartTime,
+		EndTime:                manifests[last].EndTime,
+	}
+	description, err := backupCompactionDescription(backup, details.StartTime, details.EndTime)
+	if err != nil {
+		return err
+	}
+	record := jobs.Record{
+		Description: description,
+		Username:    p.User(),
+		Details:     details,
+		Progress:    jobspb.BackupCompactionProgress{},
+	}
+	jobID := execCfg.JobRegistry.MakeJobID()
+	if _, err := execCfg.JobRegistry.CreateAdoptableJobWithTxn(ctx, record, jobID, p.Txn()); err != nil {
+		return err
+	}
+	resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+	return nil
+}
+
+// selectLayersToCompact returns the indexes of the first and last of the
+// incremental layers whose end times are within [start, end]. Either bound may
+// be empty to leave that side unbounded.
+func selectLayersToCompact(
+	manifests []backuppb.BackupManifest, start, end hlc.Timestamp,
+) (first, last int, _ error) {
+	// The layers are sorted by their end times, so the selected layers are
+	// contiguous. The full backup is never compacted.
+	first, last = 0, -1
+	for i := 1; i < len(manifests); i++ {
+		layerEnd := manifests[i].EndTime
+		if (start.IsEmpty() || start.LessEq(layerEnd)) && (end.IsEmpty() || layerEnd.LessEq(end)) {
+			if first == 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if found := last - first + 1; found < 2 {
+		return 0, 0, errors.Errorf("at least 2 incremental layers are required to compact, found %d", found)
+	}
+	return first, last, nil
+}
+
+// compactedLayerSubdir returns the directory, relative to the directory of the
+// incremental layers of the backup, of the layer into which the layers from
+// start to end are compacted. It is named after the end time like the layers
+// it replaces, so that it is found alongside them.
+func compactedLayerSubdir(start, end hlc.Timestamp) string {
+	return end.GoTime().Format(backupbase.DateBasedIncFolderName) +
+		start.GoTime().Format(backupbase.DateBasedCompactedIncFolderSuffix)
+}
+
+// backupCompactionDescription returns the description of the job compacting
+// the layers from start to end of the backup chain of the given full backup.
+func backupCompactionDescription(fullBackupURI string, start, end hlc.Timestamp) (string, error) {
+	uri, err := cloud.SanitizeExternalStorageURI(fullBackupURI, nil /* extraParams */)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("compaction of backup %s from %s to %s",
+		uri, start.GoTime().UTC(), end.GoTime().UTC()), nil
+}
+
+// Resume is part of the jobs.Resumer interface.
+//
+// The job merges the files of the layers selected when it was created into a
+// new incremental layer. The new layer is written next to the layers it was
+// compacted from, which are left in place. When resolving the layers of a
+// backup, elideCompactedLayers then replaces the compacted layers with it. The
+// job only reads from and writes to external storage and does not touch the
+// cluster.
+func (r *backupCompactionResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.BackupCompactionDetails)
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+
+	compactedURI, err := url.Parse(details.IncrementalDirectories[0])
+	if err != nil {
+		return err
+	}
+	compactedSubdir := compactedLayerSubdir(details.StartTime, details.EndTime)
+	compactedURI.Path = backuputils.JoinURLPath(compactedURI.Path, compactedSubdir)
+	dest, err := mkStore(ctx, compactedURI.String(), p.User())
+	if err != nil {
+		return errors.Wrapf(err, "failed to open backup storage location")
+	}
+	defer dest.Close()
+
+	// The manifest of the compacted layer is written last, so if it exists the
+	// job was resumed after it compacted the layers.
+	if manifest, err := dest.ReadFile(ctx, backupbase.BackupManifestName); err == nil {
+		manifest.Close(ctx)
+		return nil
+	} else if !errors.Is(err, cloud.ErrFileDoesNotExist) {
+		return err
+	}
+
+	baseStore, err := mkStore(ctx, details.FullBackupURIs[0], p.User())
+	if err != nil {
+		return errors.Wrapf(err, "failed to open backup storage location")
+	}
+	defer baseStore.Close()
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+
+	defaultURIs, manifests, _, memReserved, err := resolveBackupManifests(
+		ctx, &mem, []cloud.ExternalStorage{baseStore}, mkStore, details.FullBackupURIs,
+		details.IncrementalDirectories, hlc.Timestamp{}, nil /* encryption */, p.User())
+	if err != nil {
+		return err
+	}
+	defer mem.Shrink(ctx, memReserved)
+
+	first, last, err := selectLayersToCompact(manifests, details.StartTime.Next(), details.EndTime)
+	if err != nil {
+		return err
+	}
+	if !manifests[first].StartTime.Equal(details.StartTime) || !manifests[last].EndTime.Equal(details.EndTime) {
+		return errors.Newf("the layers of the backup from %s to %s are no longer in the backup",
+			details.StartTime, details.EndTime)
+	}
+	layers := manifests[first : last+1]
+
+	// Set the directories of the layers, which are used to open their files.
+	for i := range layers {
+		if err := func() error {
+			store, err := mkStore(ctx, defaultURIs[first+i], p.User())
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+			layers[i].Dir = store.Conf()
+			return nil
+		}(); err != nil {
+			return errors.Wrapf(err, "failed to open backup storage location")
+		}
+	}
+
+	compacted := makeCompactedManifest(layers)
+	if err := compactLayerFiles(ctx, execCfg, layers, dest, &compacted); err != nil {
+		return errors.Wrap(err, "compacting backup files")
+	}
+
+	statistics, err := copyCompactedStatistics(ctx, mkStore, p.User(), defaultURIs[last], dest, &compacted)
+	if err != nil {
+		return err
+	}
+	if writeMetadataSST.Get(&execCfg.Settings.SV) {
+		if err := writeBackupMetadataSST(ctx, dest, nil /* encryption */, &compacted, statistics); err != nil {
+			return errors.Wrap(err, "writing forward-compat metadata sst")
+		}
+	}
+
+	// The manifest is written last since its presence makes the new layer
+	// visible to restores.
+	if err := writeBackupManifest(
+		ctx, execCfg.Settings, dest, backupbase.BackupManifestName, nil /* encryption */, &compacted,
+	); err != nil {
+		return err
+	}
+	log.Infof(ctx, "compacted %d incremental backup layers from %s to %s into %s",
+		len(layers), compacted.StartTime, compacted.EndTime, compactedSubdir)
+	return nil
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. The files written by
+// the job are not visible without the manifest of the compacted layer, so they
+// are left in place.
+func (r *backupCompactionResumer) OnFailOrCancel(context.Context, interface{}) error {
+	return nil
+}
+
+// makeCompactedManifest returns the manifest, without its files, of the layer
+// into which the given consecutive incremental layers are compacted.
+func makeCompactedManifest(layers []backuppb.BackupManifest) backuppb.BackupManifest {
+	firstLayer, lastLayer := &layers[0], &layers[len(layers)-1]
+	compacted := backuppb.BackupManifest{
+		StartTime:           firstLayer.StartTime,
+		EndTime:             lastLayer.EndTime,
+		MVCCFilter:          backuppb.MVCCFilter_All,
+		RevisionStartTime:   firstLayer.RevisionStartTime,
+		Descriptors:         lastLayer.Descriptors,
+		Tenants:             lastLayer.Tenants,
+		CompleteDbs:         lastLayer.CompleteDbs,
+		FormatVersion:       lastLayer.FormatVersion,
+		BuildInfo:           lastLayer.BuildInfo,
+		ClusterVersion:      lastLayer.ClusterVersion,
+		ClusterID:           lastLayer.ClusterID,
+		StatisticsFilenames: lastLayer.StatisticsFilenames,
+		DescriptorCoverage:  lastLayer.DescriptorCoverage,
+		ID:                  uuid.MakeV4(),
+	}
+	var spans, introducedSpans roachpb.SpanGroup
+	for i := range layers {
+		if layers[i].MVCCFilter != backuppb.MVCCFilter_All {
+			compacted.MVCCFilter = backuppb.MVCCFilter_Latest
+		}
+		compacted.DescriptorChanges = append(compacted.DescriptorChanges, layers[i].DescriptorChanges...)
+		spans.Add(layers[i].Spans...)
+		introducedSpans.Add(layers[i].IntroducedSpans...)
+	}
+	// Revisions are only kept if every compacted layer has them.
+	if compacted.MVCCFilter != backuppb.MVCCFilter_All {
+		compacted.DescriptorChanges = nil
+		compacted.RevisionStartTime = hlc.Timestamp{}
+	}
+	compacted.Spans = spans.Slice()
+	compacted.IntroducedSpans = introducedSpans.Slice()
+	return compacted
+}
+
+// compactLayerFiles merges the files of the layers into new files written to
+// dest, which are added to the compacted manifest. If the compacted manifest
+// has revision history all the revisions of each key are kept, otherwise only
+// its latest revision, which may be a deletion tombstone, is kept.
+func compactLayerFiles(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	layers []backuppb.BackupManifest,
+	dest cloud.ExternalStorage,
+	compacted *backuppb.BackupManifest,
+) error {
+	pkIDs := make(map[uint64]bool)
+	for i := range compacted.Descriptors {
+		if t, _, _, _, _ := descpb.FromDescriptor(&compacted.Descriptors[i]); t != nil {
+			pkIDs[roachpb.BulkOpSummaryID(uint64(t.ID), uint64(t.PrimaryIndex.ID))] = true
+		}
+	}
+	cover := makeSimpleImportSpans(compacted.Spans, layers, nil /* backupLocalityMap */, nil /* lowWaterMark */)
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+	progCh := make(chan execinfrapb.RemoteProducerMetadata_BulkProcessorProgress)
+	sink, err := makeFileSSTSink(ctx, sstSinkConf{
+		progCh:   progCh,
+		id:       execCfg.NodeID.SQLInstanceID(),
+		settings: &execCfg.Settings.SV,
+	}, dest, &mem)
+	if err != nil {
+		return err
+	}
+
+	collectFiles := func(ctx context.Context) error {
+		for progress := range progCh {
+			var progDetails backuppb.BackupManifest_Progress
+			if err := types.UnmarshalAny(&progress.ProgressDetails, &progDetails); err != nil {
+				return err
+			}
+			for _, file := range progDetails.Files {
+				compacted.Files = append(compacted.Files, file)
+				compacted.EntryCounts.Add(file.EntryCounts)
+			}
+		}
+		return nil
+	}
+	writeFiles := func(ctx context.Context) error {
+		defer close(progCh)
+		defer sink.Close()
+		for _, entry := range cover {
+			if err := compactSpanEntry(ctx, execCfg, entry, compacted.MVCCFilter, pkIDs, sink); err != nil {
+				return err
+			}
+		}
+		return sink.flush(ctx)
+	}
+	return ctxgroup.GoAndWait(ctx, collectFiles, writeFiles)
+}
+
+// compactSpanEntry merges the files of a restore span entry into SSTs, of
+// about the target backup file size, which are pushed to the sink.
+func compactSpanEntry(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	entry execinfrapb.RestoreSpanEntry,
+	filter backuppb.MVCCFilter,
+	pkIDs map[uint64]bool,
+	sink *fileSSTSink,
+) error {
+	var iters []storage.SimpleMVCCIterator
+	defer func() {
+		for _, iter := range iters {
+			iter.Close()
+		}
+	}()
+	for _, file := range entry.Files {
+		dir, err := execCfg.DistSQLSrv.ExternalStorage(ctx, file.Dir)
+		if err != nil {
+			return err
+		}
+		defer dir.Close()
+		iter, err := storageccl.ExternalSSTReader(ctx, dir, file.Path, nil /* encryption */)
+		if err != nil {
+			return err
+		}
+		iters = append(iters, iter)
+	}
+	iter := storage.MakeMultiIterator(iters)
+	defer iter.Close()
+
+	var sstFile *storage.MemFile
+	var sst storage.SSTWriter
+	defer func() {
+		if sstFile != nil {
+			sst.Close()
+		}
+	}()
+	var counter storage.RowCounter
+	spanStart := entry.Span.Key
+	var prevKey roachpb.Key
+	push := func(spanEnd roachpb.Key) error {
+		if err := sst.Finish(); err != nil {
+			return err
+		}
+		sst.Close()
+		data := sstFile.Data()
+		sstFile = nil
+		err := sink.push(ctx, exportedSpan{
+			metadata: backuppb.BackupManifest_File{
+				Span:        roachpb.Span{Key: spanStart, EndKey: spanEnd},
+				EntryCounts: countRows(counter.BulkOpSummary, pkIDs),
+			},
+			dataSST:       data,
+			atKeyBoundary: true,
+		})
+		counter = storage.RowCounter{}
+		spanStart = spanEnd
+		return err
+	}
+
+	targetSize := targetFileSize.Get(&execCfg.Settings.SV)
+	for iter.SeekGE(storage.MVCCKey{Key: entry.Span.Key}); ; {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		key := iter.UnsafeKey()
+		if key.Key.Compare(entry.Span.EndKey) >= 0 {
+			break
+		}
+		newKey := !key.Key.Equal(prevKey)
+		if newKey {
+			// Files are only split between keys, so that all the kept revisions of a
+			// key are in the same file.
+			if sstFile != nil && int64(sstFile.Len()) >= targetSize {
+				if err := push(key.Key.Clone()); err != nil {
+					return err
+				}
+			}
+			prevKey = append(prevKey[:0], key.Key...)
+		}
+		if sstFile == nil {
+			sstFile = &storage.MemFile{}
+			sst = storage.MakeBackupSSTWriter(ctx, execCfg.Settings, sstFile)
+		}
+		value := iter.UnsafeValue()
+		if key.Timestamp.IsEmpty() {
+			if err := sst.PutUnversioned(key.Key, value); err != nil {
+				return err
+			}
+		} else if err := sst.PutRawMVCC(key, value); err != nil {
+			return err
+		}
+		if err := counter.Count(key.Key); err != nil {
+			return err
+		}
+		counter.DataSize += int64(len(key.Key) + len(value))
+
+		if filter == backuppb.MVCCFilter_All {
+			iter.Next()
+		} else {
+			iter.NextKey()
+		}
+	}
+	if sstFile == nil {
+		return nil
+	}
+	return push(entry.Span.EndKey)
+}
+
+// copyCompactedStatistics copies the table statistics of the last compacted
+// layer, which are those of the compacted layer, to dest. Statistics which
+// cannot be read are dropped, as they can be recomputed after a restore.
+func copyCompactedStatistics(
+	ctx context.Context,
+	mkStore cloud.ExternalStorageFromURIFactory,
+	user username.SQLUsername,
+	lastLayerURI string,
+	dest cloud.ExternalStorage,
+	compacted *backuppb.BackupManifest,
+) ([]*stats.TableStatisticProto, error) {
+	if len(compacted.StatisticsFilenames) == 0 {
+		return nil, nil
+	}
+	store, err := mkStore(ctx, lastLayerURI, user)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open backup storage location")
+	}
+	defer store.Close()
+
+	var statistics []*stats.TableStatisticProto
+	copied := make(map[string]bool)
+	filenames := make(map[descpb.ID]string, len(compacted.StatisticsFilenames))
+	for id, filename := range compacted.StatisticsFilenames {
+		if ok, seen := copied[filename]; seen {
+			if ok {
+				filenames[id] = filename
+			}
+			continue
+		}
+		statsTable, err := readTableStatistics(ctx, store, filename, nil /* encryption */)
+		if err == nil {
+			err = writeTableStatistics(ctx, dest, filename, nil /* encryption */, statsTable)
+		}
+		if err != nil {
+			log.Warningf(ctx, "failed to copy backup table statistics %s: %v", filename, err)
+			copied[filename] = false
+			continue
+		}
+		copied[filename] = true
+		filenames[id] = filename
+		statistics = append(statistics, statsTable.Statistics...)
+	}
+	compacted.StatisticsFilenames = filenames
+	return statistics, nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeBackupCompaction,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &backupCompactionResumer{
+				job: job,
+			}
+		},
+	)
+}
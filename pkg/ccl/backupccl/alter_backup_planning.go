@@ -17,10 +17,13 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
 	"github.com/cockroachdb/cockroach/pkg/featureflag"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/asof"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/errors"
 )
 
@@ -56,6 +59,8 @@ func alterBackupPlanHook(
 
 	var newKmsFn func() ([]string, error)
 	var oldKmsFn func() ([]string, error)
+	var compact bool
+	var compactStartFn, compactEndFn func() (string, error)
 
 	for _, cmd := range alterBackupStmt.Cmds {
 		switch v := cmd.(type) {
@@ -68,6 +73,18 @@ func alterBackupPlanHook(
 			if err != nil {
 				return nil, nil, nil, false, err
 			}
+		case *tree.AlterBackupCompact:
+			compact = true
+			if v.Start != nil {
+				compactStartFn, err = p.TypeAsString(ctx, v.Start, "ALTER BACKUP")
+				if err != nil {
+					return nil, nil, nil, false, err
+				}
+				compactEndFn, err = p.TypeAsString(ctx, v.End, "ALTER BACKUP")
+				if err != nil {
+					return nil, nil, nil, false, err
+				}
+			}
 		}
 	}
 
@@ -82,6 +99,7 @@ func alterBackupPlanHook(
 			return err
 		}
 
+		collection := backup
 		if subdir != "" {
 			if strings.EqualFold(subdir, "LATEST") {
 				// set subdir to content of latest file
@@ -107,24 +125,63 @@ func alterBackupPlanHook(
 			}
 		}
 
-		var newKms []string
-		newKms, err = newKmsFn()
-		if err != nil {
-			return err
-		}
+		if newKmsFn != nil {
+			var newKms []string
+			newKms, err = newKmsFn()
+			if err != nil {
+				return err
+			}
 
-		var oldKms []string
-		oldKms, err = oldKmsFn()
-		if err != nil {
-			return err
+			var oldKms []string
+			oldKms, err = oldKmsFn()
+			if err != nil {
+				return err
+			}
+
+			if err := doAlterBackupPlan(ctx, alterBackupStmt, p, backup, newKms, oldKms); err != nil {
+				return err
+			}
 		}
 
-		return doAlterBackupPlan(ctx, alterBackupStmt, p, backup, newKms, oldKms)
+		if compact {
+			var start, end hlc.Timestamp
+			if compactStartFn != nil {
+				if start, err = compactionBound(p, compactStartFn); err != nil {
+					return err
+				}
+				if end, err = compactionBound(p, compactEndFn); err != nil {
+					return err
+				}
+			}
+			if subdir == "" {
+				collection, subdir = backupdest.CollectionAndSubdir(backup, subdir)
+			}
+			return startBackupCompactionJob(ctx, p, collection, subdir, backup, start, end, resultsCh)
+		}
+		return nil
 	}
 
+	if compact {
+		return fn, jobs.DetachedJobExecutionResultHeader, nil, false, nil
+	}
 	return fn, nil, nil, false, nil
 }
 
+// compactionBound evaluates a bound of the layers of an ALTER BACKUP ...
+// COMPACT, which accepts the same values as AS OF SYSTEM TIME.
+func compactionBound(p sql.PlanHookState, boundFn func() (string, error)) (hlc.Timestamp, error) {
+	bound, err := boundFn()
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	evalCtx := &p.ExtendedEvalContext().Context
+	ts, err := asof.DatumToHLC(evalCtx, evalCtx.GetStmtTimestamp(), tree.NewDString(bound))
+	if err != nil {
+		return hlc.Timestamp{}, errors.Wrap(err, "ALTER BACKUP ... COMPACT")
+	}
+	return ts, nil
+}
+
 func doAlterBackupPlan(
 	ctx context.Context,
 	alterBackupStmt *tree.AlterBackup,
@@ -11,13 +11,24 @@ package backupccl
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupdest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -210,3 +221,180 @@ func TestAlterBackupIncremental(t *testing.T) {
 	sqlDB.Exec(t, query)
 	sqlDB.ExecRowsAffected(t, 2, "SELECT * FROM bank")
 }
+
+// TestAlterBackupCompact tests that ALTER BACKUP ... COMPACT merges incremental
+// layers into a single layer which replaces them when restoring.
+func TestAlterBackupCompact(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const numAccounts = 0
+	ctx := context.Background()
+	tc, sqlDB, dir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts, InitManualReplication)
+	defer cleanupFn()
+	registry := tc.Server(0).JobRegistry().(*jobs.Registry)
+
+	const collection = "'nodelocal://0/compact'"
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (k INT PRIMARY KEY, v STRING)`)
+
+	// backup takes a backup of d into the collection and returns its end time.
+	backup := func(into string) string {
+		var ts string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&ts)
+		sqlDB.Exec(t, fmt.Sprintf(`BACKUP DATABASE d INTO %s%s AS OF SYSTEM TIME %s`, into, collection, ts))
+		return ts
+	}
+	// numLayers returns the number of layers shown by SHOW BACKUP, after checking
+	// that each of them starts at the end of the previous one.
+	numLayers := func() int {
+		t.Helper()
+		layers := sqlDB.QueryStr(t, fmt.Sprintf(`SELECT DISTINCT
+			COALESCE(start_time::STRING, '') AS start, end_time::STRING AS end, end_time
+			FROM [SHOW BACKUP LATEST IN %s] ORDER BY end_time`, collection))
+		for i := 1; i < len(layers); i++ {
+			require.Equal(t, layers[i-1][1], layers[i][0], "overlapping or missing layers: %v", layers)
+		}
+		return len(layers)
+	}
+	// compact runs an ALTER BACKUP ... COMPACT statement and waits for its job to
+	// succeed.
+	compact := func(query string) {
+		t.Helper()
+		var jobID jobspb.JobID
+		sqlDB.QueryRow(t, query).Scan(&jobID)
+		testutils.SucceedsSoon(t, func() error {
+			registry.TestingNudgeAdoptionQueue()
+			var status, jobErr string
+			sqlDB.QueryRow(t, `SELECT status, error FROM [SHOW JOB $1]`, jobID).Scan(&status, &jobErr)
+			if status == string(jobs.StatusFailed) {
+				t.Fatalf("compaction job %d failed: %s", jobID, jobErr)
+			}
+			if status != string(jobs.StatusSucceeded) {
+				return errors.Newf("compaction job %d is %s", jobID, status)
+			}
+			return nil
+		})
+	}
+	checkRestore := func(name string, asOf string) {
+		query := fmt.Sprintf(`RESTORE DATABASE d FROM LATEST IN %s`, collection)
+		if asOf != "" {
+			query += ` AS OF SYSTEM TIME ` + asOf
+		}
+		sqlDB.Exec(t, query+fmt.Sprintf(` WITH new_db_name = '%s'`, name))
+		expected := `SELECT * FROM d.t`
+		if asOf != "" {
+			expected += ` AS OF SYSTEM TIME ` + asOf
+		}
+		sqlDB.CheckQueryResults(t, fmt.Sprintf(`SELECT * FROM %s.t`, name), sqlDB.QueryStr(t, expected))
+	}
+
+	sqlDB.Exec(t, `INSERT INTO d.t SELECT i, 'a' FROM generate_series(1, 10) AS g(i)`)
+	backup("")
+	sqlDB.Exec(t, `UPDATE d.t SET v = 'b' WHERE k <= 3`)
+	inc1 := backup("LATEST IN ")
+	sqlDB.Exec(t, `DELETE FROM d.t WHERE k IN (4, 5)`)
+	sqlDB.Exec(t, `INSERT INTO d.t VALUES (11, 'c'), (12, 'c')`)
+	inc2 := backup("LATEST IN ")
+	sqlDB.Exec(t, `UPDATE d.t SET v = 'd' WHERE k = 11`)
+	sqlDB.Exec(t, `DELETE FROM d.t WHERE k = 1`)
+	inc3 := backup("LATEST IN ")
+	require.Equal(t, 4, numLayers())
+
+	sqlDB.ExpectErr(t, "at least 2 incremental layers are required to compact, found 1",
+		fmt.Sprintf(`ALTER BACKUP LATEST IN %s COMPACT BETWEEN '%s' AND '%s'`, collection, inc1, inc1))
+
+	// Compact the last two layers, then all of them including the compacted one.
+	compact(fmt.Sprintf(`ALTER BACKUP LATEST IN %s COMPACT BETWEEN '%s' AND '%s'`,
+		collection, inc2, inc3))
+	require.Equal(t, 3, numLayers())
+	compact(fmt.Sprintf(`ALTER BACKUP LATEST IN %s COMPACT`, collection))
+	require.Equal(t, 2, numLayers())
+
+	// Restoring to the end of a compacted layer uses the original layers.
+	checkRestore("r1", inc2)
+
+	layerDirs, err := filepath.Glob(filepath.Join(dir, "compact", "incrementals", "*", "*", "*", "*", "*"))
+	require.NoError(t, err)
+	var originalDirs []string
+	for _, layerDir := range layerDirs {
+		if !strings.Contains(filepath.Base(layerDir), "-compacted-") {
+			originalDirs = append(originalDirs, layerDir)
+		}
+	}
+	require.Len(t, originalDirs, 3)
+
+	// The next incremental backup is chained to the compacted layer instead of
+	// to the layers it replaces.
+	execCfg := tc.Server(0).ExecutorConfig().(sql.ExecutorConfig)
+	_, _, _, _, prevURIs, err := backupdest.ResolveDest(ctx, username.RootUserName(),
+		jobspb.BackupDetails_Destination{
+			To: []string{"nodelocal://0/compact"}, Subdir: backupbase.LatestFileName, Exists: true,
+		},
+		execCfg.Clock.Now(), nil /* incrementalFrom */, &execCfg)
+	require.NoError(t, err)
+	require.Len(t, prevURIs, 6)
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+	prevBackups, _, _, err := fetchPreviousBackups(ctx, &mem, username.RootUserName(),
+		execCfg.DistSQLSrv.ExternalStorageFromURI, prevURIs, jobspb.BackupEncryptionOptions{Mode: jobspb.EncryptionMode_None},
+		&backupencryption.BackupKMSEnv{Settings: execCfg.Settings, Conf: &execCfg.ExternalIODirConfig})
+	require.NoError(t, err)
+	require.Len(t, prevBackups, 2)
+	require.Equal(t, prevBackups[0].EndTime, prevBackups[1].StartTime)
+
+	// Incremental backups can still be appended to the compacted chain.
+	sqlDB.Exec(t, `INSERT INTO d.t VALUES (13, 'e')`)
+	sqlDB.Exec(t, `UPDATE d.t SET v = 'e' WHERE k = 2`)
+	backup("LATEST IN ")
+	require.Equal(t, 3, numLayers())
+
+	// Remove the original layers to check that restores only read the full
+	// backup, the compacted layer and the tail.
+	for _, originalDir := range originalDirs {
+		require.NoError(t, os.RemoveAll(originalDir))
+	}
+	checkRestore("r2", "")
+	checkRestore("r3", inc3)
+}
+
+// TestSelectLayersToCompact tests that the bounds of ALTER BACKUP ... COMPACT
+// compare the full timestamps of the layers, including their logical part.
+func TestSelectLayersToCompact(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := func(wallTime int64, logical int32) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: wallTime, Logical: logical}
+	}
+	// A full backup and incremental layers ending at 10, 10.1, 10.2 and 20.
+	ends := []hlc.Timestamp{ts(5, 0), ts(10, 0), ts(10, 1), ts(10, 2), ts(20, 0)}
+	manifests := make([]backuppb.BackupManifest, len(ends))
+	for i := range ends {
+		if i > 0 {
+			manifests[i].StartTime = ends[i-1]
+		}
+		manifests[i].EndTime = ends[i]
+	}
+
+	for _, tc := range []struct {
+		start, end  hlc.Timestamp
+		first, last int
+		err         string
+	}{
+		{first: 1, last: 4},
+		{start: ts(10, 1), first: 2, last: 4},
+		{end: ts(10, 1), first: 1, last: 2},
+		{start: ts(10, 1), end: ts(10, 2), first: 2, last: 3},
+		{start: ts(10, 1), end: ts(10, 1), err: "found 1"},
+		{start: ts(10, 3), end: ts(19, 0), err: "found 0"},
+	} {
+		first, last, err := selectLayersToCompact(manifests, tc.start, tc.end)
+		if tc.err != "" {
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, [2]int{tc.first, tc.last}, [2]int{first, last}, "start %s end %s", tc.start, tc.end)
+	}
+}
@@ -184,7 +184,7 @@ func writeDescsToMetadata(
 			k := encodeDescSSTKey(i.ID)
 			var b []byte
 			if i.Desc != nil {
-				t, _, _, _ := descpb.FromDescriptor(i.Desc)
+				t, _, _, _, _ := descpb.FromDescriptor(i.Desc)
 				if t == nil || !t.Dropped() {
 					bytes, err := protoutil.Marshal(i.Desc)
 					if err != nil {
@@ -309,12 +309,16 @@ func writeNamesToMetadata(
 		}
 	}
 
-	names := make(namespace, len(revs))
+	names := make(namespace, 0, len(revs))
 
-	for i, rev := range revs {
-		names[i].id = rev.ID
-		names[i].ts = rev.Time
-		tb, db, typ, sc := descpb.FromDescriptor(rev.Desc)
+	for _, rev := range revs {
+		tb, db, typ, sc, fn := descpb.FromDescriptor(rev.Desc)
+		if fn != nil {
+			// Functions do not have namespace entries.
+			continue
+		}
+		i := len(names)
+		names = append(names, name{id: rev.ID, ts: rev.Time})
 		if db != nil {
 			names[i].name = db.Name
 		} else if sc != nil {
@@ -447,6 +451,8 @@ func descID(in descpb.Descriptor) descpb.ID {
 		return i.Type.ID
 	case *descpb.Descriptor_Schema:
 		return i.Schema.ID
+	case *descpb.Descriptor_Function:
+		return i.Function.ID
 	default:
 		panic(fmt.Sprintf("unknown desc %T", in))
 	}
@@ -1014,8 +1020,8 @@ func (di *DescIterator) Next(desc *descpb.Descriptor) bool {
 			return false
 		}
 
-		tbl, db, typ, sc := descpb.FromDescriptor(desc)
-		if tbl != nil || db != nil || typ != nil || sc != nil {
+		tbl, db, typ, sc, fn := descpb.FromDescriptor(desc)
+		if tbl != nil || db != nil || typ != nil || sc != nil || fn != nil {
 			return true
 		}
 	}
@@ -0,0 +1,437 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/nstree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+// maxBackupVerificationProblems is the maximum number of problems recorded in
+// the progress of a backup verification job. Problems beyond it are only
+// counted.
+const maxBackupVerificationProblems = 100
+
+// backupVerificationProgressInterval is the minimum interval between two
+// updates of the progress of a backup verification job.
+const backupVerificationProgressInterval = 10 * time.Second
+
+type backupVerificationResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &backupVerificationResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+//
+// The job verifies, for each layer of the backup chain, that the layers up to
+// it cover its spans without gaps, that its descriptors are valid and that
+// each of its data files can be read, which verifies the checksums of their
+// blocks, and holds keys within the spans of the file, in order and no newer
+// than the layer. The manifests themselves are verified against their
+// checksums when they are read. The problems that are found are recorded in
+// the progress of the job, which fails if there are any.
+func (r *backupVerificationResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.BackupVerificationDetails)
+	mkStore := execCfg.DistSQLSrv.ExternalStorageFromURI
+
+	baseStores := make([]cloud.ExternalStorage, len(details.FullBackupURIs))
+	for i := range details.FullBackupURIs {
+		store, err := mkStore(ctx, details.FullBackupURIs[i], p.User())
+		if err != nil {
+			return errors.Wrapf(err, "failed to open backup storage location")
+		}
+		defer store.Close()
+		baseStores[i] = store
+	}
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+	defaultURIs, manifests, localityInfo, memReserved, err := resolveBackupManifests(
+		ctx, &mem, baseStores, mkStore, details.FullBackupURIs, details.IncrementalDirectories,
+		details.EndTime, details.EncryptionOptions, p.User())
+	if err != nil {
+		return err
+	}
+	defer mem.Shrink(ctx, memReserved)
+
+	var encryption *roachpb.FileEncryptionOptions
+	if details.EncryptionOptions != nil {
+		key, err := backupencryption.GetEncryptionKey(ctx, details.EncryptionOptions,
+			execCfg.Settings, baseStores[0].ExternalIOConf())
+		if err != nil {
+			return err
+		}
+		encryption = &roachpb.FileEncryptionOptions{Key: key}
+	}
+
+	v := &backupVerifier{
+		job:         r.job,
+		mkStore:     mkStore,
+		user:        p.User(),
+		settings:    execCfg.Settings,
+		encryption:  encryption,
+		defaultURIs: defaultURIs,
+		manifests:   manifests,
+		localities:  localityInfo,
+	}
+	for i := range manifests {
+		v.totalFiles += len(dataFiles(&manifests[i]))
+	}
+	for i := range manifests {
+		v.verifyCoverage(ctx, i)
+		v.verifyDescriptors(ctx, i)
+		if err := v.verifyFiles(ctx, i); err != nil {
+			return err
+		}
+	}
+	if err := v.updateProgress(ctx, 1.0); err != nil {
+		return err
+	}
+	if v.progress.NumProblems > 0 {
+		return errors.Newf("backup verification found %d problem(s); "+
+			"they are listed in crdb_internal.backup_verifications", v.progress.NumProblems)
+	}
+	return nil
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface.
+func (r *backupVerificationResumer) OnFailOrCancel(context.Context, interface{}) error {
+	return nil
+}
+
+// backupVerifier verifies the layers of a backup chain.
+type backupVerifier struct {
+	job         *jobs.Job
+	mkStore     cloud.ExternalStorageFromURIFactory
+	user        username.SQLUsername
+	settings    *cluster.Settings
+	encryption  *roachpb.FileEncryptionOptions
+	defaultURIs []string
+	manifests   []backuppb.BackupManifest
+	localities  []jobspb.RestoreDetails_BackupLocalityInfo
+
+	totalFiles   int
+	filesRead    int
+	lastProgress time.Time
+	progress     jobspb.BackupVerificationProgress
+}
+
+// report records a problem found in the file at path in the given layer. The
+// path is empty for problems which are not specific to a file.
+func (v *backupVerifier) report(ctx context.Context, layer int, path string, problem error) {
+	layerURI, err := cloud.SanitizeExternalStorageURI(v.defaultURIs[layer], nil /* extraParams */)
+	if err != nil {
+		layerURI = strings.Split(v.defaultURIs[layer], "?")[0]
+	}
+	log.Warningf(ctx, "backup verification problem in %s %s: %v", layerURI, path, problem)
+	v.progress.NumProblems++
+	if len(v.progress.Problems) < maxBackupVerificationProblems {
+		v.progress.Problems = append(v.progress.Problems, jobspb.BackupVerificationProgress_Problem{
+			Layer:       layerURI,
+			Path:        path,
+			Description: problem.Error(),
+		})
+	}
+}
+
+// updateProgress persists the progress of the verification.
+func (v *backupVerifier) updateProgress(ctx context.Context, fraction float32) error {
+	v.lastProgress = timeutil.Now()
+	return v.job.FractionProgressed(ctx, nil, /* txn */
+		func(ctx context.Context, details jobspb.ProgressDetails) float32 {
+			prog := details.(*jobspb.Progress_BackupVerification).BackupVerification
+			*prog = v.progress
+			return fraction
+		})
+}
+
+// verifyCoverage verifies that the spans of the given layer are covered by it
+// and the layers before it.
+func (v *backupVerifier) verifyCoverage(ctx context.Context, layer int) {
+	if err := checkCoverage(ctx, v.manifests[layer].Spans, v.manifests[:layer+1]); err != nil {
+		v.report(ctx, layer, "" /* path */, errors.Wrap(err, "span coverage"))
+	}
+}
+
+// verifyDescriptors validates the descriptors of the given layer. The
+// references between descriptors are only validated if the layer is a cluster
+// backup, since backups of specific tables or databases may reference
+// descriptors which they do not contain.
+func (v *backupVerifier) verifyDescriptors(ctx context.Context, layer int) {
+	m := &v.manifests[layer]
+	var c nstree.MutableCatalog
+	descs := make([]catalog.Descriptor, 0, len(m.Descriptors))
+	for i := range m.Descriptors {
+		b := descbuilder.NewBuilder(&m.Descriptors[i])
+		if b == nil {
+			v.report(ctx, layer, "" /* path */, errors.Newf("descriptor %d is empty", i))
+			continue
+		}
+		desc := b.BuildImmutable()
+		c.UpsertDescriptorEntry(desc)
+		descs = append(descs, desc)
+	}
+	var level catalog.ValidationLevel = catalog.ValidationLevelSelfOnly
+	if m.DescriptorCoverage == tree.AllDescriptors {
+		level = catalog.ValidationLevelCrossReferences
+	}
+	version := v.settings.Version.ActiveVersion(ctx)
+	for _, desc := range descs {
+		if err := c.Validate(
+			ctx, version, catalog.NoValidationTelemetry, level, desc,
+		).CombinedError(); err != nil {
+			v.report(ctx, layer, "" /* path */, err)
+		}
+	}
+}
+
+// dataFile is an SST of a backup layer. Since the entries of the files of a
+// backup manifest are each for a span of an SST, an SST may have multiple
+// entries.
+type dataFile struct {
+	path       string
+	localityKV string
+	spans      roachpb.SpanGroup
+}
+
+// dataFiles returns the SSTs of the backup layer, in the order of their first
+// entry.
+func dataFiles(m *backuppb.BackupManifest) []*dataFile {
+	var files []*dataFile
+	byPath := make(map[[2]string]*dataFile)
+	for i := range m.Files {
+		f := &m.Files[i]
+		key := [2]string{f.LocalityKV, f.Path}
+		file, ok := byPath[key]
+		if !ok {
+			file = &dataFile{path: f.Path, localityKV: f.LocalityKV}
+			byPath[key] = file
+			files = append(files, file)
+		}
+		file.spans.Add(f.Span)
+	}
+	return files
+}
+
+// verifyFiles reads the data files of the given layer. It only returns an error
+// if the progress of the job cannot be updated.
+func (v *backupVerifier) verifyFiles(ctx context.Context, layer int) error {
+	m := &v.manifests[layer]
+	stores := make(map[string]cloud.ExternalStorage)
+	defer func() {
+		for _, store := range stores {
+			if err := store.Close(); err != nil {
+				log.Warningf(ctx, "close export storage failed %v", err)
+			}
+		}
+	}()
+	getStore := func(localityKV string) (cloud.ExternalStorage, error) {
+		if store, ok := stores[localityKV]; ok {
+			return store, nil
+		}
+		uri := v.defaultURIs[layer]
+		if localityURI, ok := v.localities[layer].URIsByOriginalLocalityKV[localityKV]; ok {
+			uri = localityURI
+		}
+		store, err := v.mkStore(ctx, uri, v.user)
+		if err != nil {
+			return nil, err
+		}
+		stores[localityKV] = store
+		return store, nil
+	}
+
+	var spans roachpb.SpanGroup
+	spans.Add(m.Spans...)
+	for _, f := range dataFiles(m) {
+		if fileSpans := f.spans.Slice(); !spans.Encloses(fileSpans...) {
+			v.report(ctx, layer, f.path,
+				errors.Newf("file spans %v are not within the spans of the backup", fileSpans))
+		}
+		store, err := getStore(f.localityKV)
+		if err == nil {
+			err = v.verifyFile(ctx, store, f, m.EndTime)
+		}
+		if err != nil {
+			v.report(ctx, layer, f.path, err)
+		} else {
+			v.progress.FilesVerified++
+		}
+		v.filesRead++
+		if timeutil.Since(v.lastProgress) > backupVerificationProgressInterval {
+			fraction := float32(v.filesRead) / float32(v.totalFiles)
+			if err := v.updateProgress(ctx, fraction); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// verifyFile reads all the keys of a data file and verifies that they are
+// within its spans, strictly ordered and no newer than the end time of the
+// layer.
+func (v *backupVerifier) verifyFile(
+	ctx context.Context, store cloud.ExternalStorage, f *dataFile, endTime hlc.Timestamp,
+) error {
+	iter, err := storageccl.ExternalSSTReader(ctx, store, f.path, v.encryption)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	var prev storage.MVCCKey
+	for iter.SeekGE(storage.MVCCKey{Key: roachpb.KeyMin}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		key := iter.UnsafeKey()
+		if !f.spans.Contains(key.Key) {
+			return errors.Newf("key %s is outside of the file spans %v", key, f.spans.Slice())
+		}
+		if prev.Key != nil && prev.Compare(key) >= 0 {
+			return errors.Newf("key %s is not ordered after %s", key, prev)
+		}
+		if endTime.Less(key.Timestamp) {
+			return errors.Newf("key %s is newer than the backup end time %s", key, endTime)
+		}
+		prev.Key = append(prev.Key[:0], key.Key...)
+		prev.Timestamp = key.Timestamp
+	}
+}
+
+// backupVerificationDescription returns the description of the job verifying
+// the backup chain of the given full backup.
+func backupVerificationDescription(fullBackupURI string, endTime hlc.Timestamp) (string, error) {
+	uri, err := cloud.SanitizeExternalStorageURI(fullBackupURI, nil /* extraParams */)
+	if err != nil {
+		return "", err
+	}
+	description := fmt.Sprintf("verification of backup %s", uri)
+	if !endTime.IsEmpty() {
+		description += fmt.Sprintf(" as of %s", endTime.GoTime().UTC())
+	}
+	return description, nil
+}
+
+// maybeStartScheduledBackupVerification starts a job verifying the backup
+// chain of a succeeded backup job of the given schedule, if the schedule
+// verifies its backups.
+func maybeStartScheduledBackupVerification(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	scheduleID int64,
+	backupJob *jobs.Job,
+	txn *kv.Txn,
+) error {
+	schedule, err := jobs.LoadScheduledJob(ctx, env, scheduleID, execCfg.InternalExecutor, txn)
+	if err != nil {
+		if jobs.HasScheduledJobNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	args := &backuppb.ScheduledBackupExecutionArgs{}
+	if err := pbtypes.UnmarshalAny(schedule.ExecutionArgs().Args, args); err != nil {
+		return errors.Wrap(err, "un-marshaling args")
+	}
+	if !args.VerifyBackups {
+		return nil
+	}
+
+	backupDetails := backupJob.Details().(jobspb.BackupDetails)
+	if len(backupDetails.URIsByLocalityKV) > 0 {
+		log.Warningf(ctx, "not verifying locality aware backup of job %d scheduled by %d",
+			backupJob.ID(), scheduleID)
+		return nil
+	}
+	fullBackupURIs, err := backuputils.AppendPaths(
+		[]string{backupDetails.CollectionURI}, backupDetails.Destination.Subdir)
+	if err != nil {
+		return err
+	}
+	details := jobspb.BackupVerificationDetails{
+		FullBackupURIs:    fullBackupURIs,
+		EndTime:           backupDetails.EndTime,
+		EncryptionOptions: backupDetails.EncryptionOptions,
+	}
+	if !backupDetails.StartTime.IsEmpty() {
+		// Incremental backups are written in a directory named after their end
+		// time in the directory holding all the incremental backups of the chain.
+		incURI, err := url.Parse(backupDetails.URI)
+		if err != nil {
+			return err
+		}
+		partName := backupDetails.EndTime.GoTime().Format(backupbase.DateBasedIncFolderName)
+		incURI.Path = strings.TrimSuffix(incURI.Path, partName)
+		details.IncrementalDirectories = []string{incURI.String()}
+	}
+	description, err := backupVerificationDescription(fullBackupURIs[0], details.EndTime)
+	if err != nil {
+		return err
+	}
+	record := jobs.Record{
+		Description: description,
+		Username:    backupJob.Payload().UsernameProto.Decode(),
+		Details:     details,
+		Progress:    jobspb.BackupVerificationProgress{},
+	}
+	jobID := execCfg.JobRegistry.MakeJobID()
+	if _, err := execCfg.JobRegistry.CreateAdoptableJobWithTxn(ctx, record, jobID, txn); err != nil {
+		return err
+	}
+	log.Infof(ctx, "started job %d to verify backup job %d scheduled by %d",
+		jobID, backupJob.ID(), scheduleID)
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeBackupVerification,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &backupVerificationResumer{
+				job: job,
+			}
+		},
+	)
+}
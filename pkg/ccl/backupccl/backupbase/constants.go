@@ -32,6 +32,13 @@ const (
 	// It is exported for testing backup inspection tooling.
 	DateBasedIncFolderName = "/20060102/150405.00"
 
+	// DateBasedCompactedIncFolderSuffix is the date format of the suffix, based
+	// on the start time of the compacted layers, which is appended to the
+	// DateBasedIncFolderName of the end time of an incremental layer created by
+	// ALTER BACKUP ... COMPACT. The result still ends like DateBasedIncFolderName
+	// so compacted layers are found alongside the layers they replace.
+	DateBasedCompactedIncFolderSuffix = "-compacted-20060102-150405.00"
+
 	// DateBasedIntoFolderName is the date format used when creating sub-directories
 	// for storing backups in a collection.
 	// Also exported for testing backup inspection tooling.
@@ -26,6 +26,8 @@ import (
 
 // The default subdirectory for incremental backups.
 const (
+	// incBackupSubdirGlob also matches the subdirectories of compacted layers,
+	// see backupbase.DateBasedCompactedIncFolderSuffix.
 	incBackupSubdirGlob = "/[0-9]*/[0-9]*.[0-9][0-9]/"
 
 	// listingDelimDataSlash is used when listing to find backups and groups all the
@@ -407,6 +407,30 @@ func DescriptorsMatchingTargets(
 			ret.Descs = append(ret.Descs, r.DescByID[id])
 		}
 	}
+	alreadyRequestedFunctions := make(map[descpb.ID]struct{})
+	maybeAddFunctionDesc := func(id descpb.ID) error {
+		if _, ok := alreadyRequestedFunctions[id]; ok {
+			return nil
+		}
+		desc, ok := r.DescByID[id]
+		if !ok {
+			return errors.Newf("function with ID %d not found", id)
+		}
+		alreadyRequestedFunctions[id] = struct{}{}
+		ret.Descs = append(ret.Descs, desc)
+		return nil
+	}
+	// maybeAddTriggerFunctionDescs requests the functions run by the triggers
+	// of the given table. Like types, functions referenced by a table's
+	// triggers live in the same database as the table.
+	maybeAddTriggerFunctionDescs := func(tableDesc catalog.TableDescriptor) error {
+		for _, t := range tableDesc.GetTriggers() {
+			if err := maybeAddFunctionDesc(t.FuncID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	getTypeByID := func(id descpb.ID) (catalog.TypeDescriptor, error) {
 		desc, ok := r.DescByID[id]
 		if !ok {
@@ -495,6 +519,9 @@ func DescriptorsMatchingTargets(
 			for _, id := range typeIDs {
 				maybeAddTypeDesc(id)
 			}
+			if err := maybeAddTriggerFunctionDescs(tableDesc); err != nil {
+				return ret, err
+			}
 
 		case *tree.AllTablesSelector:
 			// We should only back up targets in the scoped schema if the table
@@ -590,6 +617,9 @@ func DescriptorsMatchingTargets(
 				for _, id := range typeIDs {
 					maybeAddTypeDesc(id)
 				}
+				if err := maybeAddTriggerFunctionDescs(desc); err != nil {
+					return err
+				}
 			case catalog.TypeDescriptor:
 				maybeAddTypeDesc(desc.GetID())
 			}
@@ -612,6 +642,17 @@ func DescriptorsMatchingTargets(
 					return ret, err
 				}
 			}
+			// Functions do not have namespace entries, so they are not part of
+			// the objects mapped by name and are requested separately when their
+			// whole database is expanded.
+			for _, desc := range descriptors {
+				if fn, ok := desc.(catalog.FunctionDescriptor); ok &&
+					fn.GetParentID() == dbID && fn.Public() {
+					if err := maybeAddFunctionDesc(fn.GetID()); err != nil {
+						return ret, err
+					}
+				}
+			}
 		} else {
 			for schemaName := range requestedSchemas {
 				schemas := r.ObjsByName[dbID][schemaName]
@@ -46,6 +46,7 @@ const (
 	optOnPreviousRunning       = "on_previous_running"
 	optIgnoreExistingBackups   = "ignore_existing_backups"
 	optUpdatesLastBackupMetric = "updates_cluster_last_backup_time_metric"
+	optVerifyBackups           = "verify_backups"
 )
 
 var scheduledBackupOptionExpectValues = map[string]sql.KVStringOptValidate{
@@ -54,6 +55,7 @@ var scheduledBackupOptionExpectValues = map[string]sql.KVStringOptValidate{
 	optOnPreviousRunning:       sql.KVStringOptRequireValue,
 	optIgnoreExistingBackups:   sql.KVStringOptRequireNoValue,
 	optUpdatesLastBackupMetric: sql.KVStringOptRequireNoValue,
+	optVerifyBackups:           sql.KVStringOptRequireNoValue,
 }
 
 // scheduledBackupGCProtectionEnabled is used to enable and disable the chaining
@@ -378,6 +380,8 @@ func doCreateBackupSchedules(
 		}
 	}
 
+	_, verifyBackups := scheduleOptions[optVerifyBackups]
+
 	evalCtx := &p.ExtendedEvalContext().Context
 	firstRun, err := scheduleFirstRun(evalCtx, scheduleOptions)
 	if err != nil {
@@ -413,7 +417,7 @@ func doCreateBackupSchedules(
 		}
 		inc, incScheduledBackupArgs, err = makeBackupSchedule(
 			env, p.User(), scheduleLabel, incRecurrence, details, unpauseOnSuccessID,
-			updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords)
+			updateMetricOnSuccess, verifyBackups, backupNode, chainProtectedTimestampRecords)
 		if err != nil {
 			return err
 		}
@@ -437,7 +441,7 @@ func doCreateBackupSchedules(
 	var fullScheduledBackupArgs *backuppb.ScheduledBackupExecutionArgs
 	full, fullScheduledBackupArgs, err := makeBackupSchedule(
 		env, p.User(), scheduleLabel, fullRecurrence, details, unpauseOnSuccessID,
-		updateMetricOnSuccess, backupNode, chainProtectedTimestampRecords)
+		updateMetricOnSuccess, verifyBackups, backupNode, chainProtectedTimestampRecords)
 	if err != nil {
 		return err
 	}
@@ -541,6 +545,7 @@ func makeBackupSchedule(
 	details jobspb.ScheduleDetails,
 	unpauseOnSuccess int64,
 	updateLastMetricOnSuccess bool,
+	verifyBackups bool,
 	backupNode *tree.Backup,
 	chainProtectedTimestampRecords bool,
 ) (*jobs.ScheduledJob, *backuppb.ScheduledBackupExecutionArgs, error) {
@@ -553,6 +558,7 @@ func makeBackupSchedule(
 		UnpauseOnSuccess:               unpauseOnSuccess,
 		UpdatesLastBackupMetric:        updateLastMetricOnSuccess,
 		ChainProtectedTimestampRecords: chainProtectedTimestampRecords,
+		VerifyBackups:                  verifyBackups,
 	}
 	if backupNode.AppendToLatest {
 		args.BackupType = backuppb.ScheduledBackupExecutionArgs_INCREMENTAL
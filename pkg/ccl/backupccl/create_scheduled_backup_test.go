@@ -937,6 +937,78 @@ func TestCreateBackupScheduleInExplicitTxnRollback(t *testing.T) {
 
 // Normally, we issue backups with AOST set to be the scheduled nextRun.
 // But if the schedule time is way in the past, the backup will fail.
+// This test verifies that schedules created with the verify_backups option
+// start a verification job after each successful backup.
+func TestScheduledBackupVerification(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	th, cleanup := newTestHelper(t)
+	defer cleanup()
+
+	th.sqlDB.Exec(t, `
+CREATE DATABASE db;
+USE db;
+CREATE TABLE t(a int);
+INSERT INTO t values (1), (10), (100);
+`)
+
+	// We'll be manipulating schedule time via th.env, but we can't fool actual backup
+	// when it comes to AsOf time.  So, override AsOf backup clause to be the current time.
+	knobs := th.cfg.TestingKnobs.(*jobs.TestingKnobs)
+	knobs.OverrideAsOfClause = func(clause *tree.AsOfClause) {
+		expr, err := tree.MakeDTimestampTZ(th.cfg.DB.Clock().PhysicalTime(), time.Microsecond)
+		require.NoError(t, err)
+		clause.Expr = expr
+	}
+	defer func() { knobs.OverrideAsOfClause = nil }()
+
+	schedules, err := th.createBackupSchedule(t,
+		"CREATE SCHEDULE FOR BACKUP INTO $1 RECURRING '*/5 * * * *' WITH SCHEDULE OPTIONS verify_backups",
+		"nodelocal://0/backup/verify")
+	require.NoError(t, err)
+	require.Equal(t, 2, len(schedules))
+
+	// Order schedules so that the full schedule is the first one
+	fullID, incID := schedules[0].ScheduleID(), schedules[1].ScheduleID()
+	if schedules[0].IsPaused() {
+		fullID, incID = incID, fullID
+	}
+
+	waitForVerifications := func(expected int) {
+		testutils.SucceedsSoon(t, func() error {
+			th.server.JobRegistry().(*jobs.Registry).TestingNudgeAdoptionQueue()
+			var succeeded, problems int
+			th.sqlDB.QueryRow(t, `
+SELECT count(*), COALESCE(sum(num_problems), 0)
+  FROM crdb_internal.backup_verifications
+ WHERE status = 'succeeded' AND files_verified > 0`).Scan(&succeeded, &problems)
+			require.Zero(t, problems)
+			if succeeded != expected {
+				return errors.Newf("expected %d successful verifications, found %d", expected, succeeded)
+			}
+			return nil
+		})
+	}
+
+	for i, id := range []int64{fullID, incID} {
+		s := th.loadSchedule(t, id)
+		s.SetNextRun(th.env.Now().Add(-1 * time.Minute))
+		require.NoError(t, s.Update(context.Background(), th.cfg.InternalExecutor, nil))
+		require.NoError(t, th.executeSchedules())
+		th.waitForSuccessfulScheduledJob(t, id)
+		waitForVerifications(i + 1)
+	}
+
+	// The verification jobs are not created by the schedules, so that they do
+	// not hold back the next scheduled backup.
+	th.sqlDB.CheckQueryResults(t, `
+SELECT count(*) FROM system.jobs
+ WHERE id IN (SELECT job_id FROM crdb_internal.backup_verifications)
+   AND created_by_id IS NULL`,
+		[][]string{{"2"}})
+}
+
 // This test verifies that scheduled backups will start working
 // (eventually), even after the cluster has been down for a long period.
 func TestScheduleBackupRecoversFromClusterDown(t *testing.T) {
@@ -1167,6 +1239,9 @@ func constructExpectedScheduledBackupNode(
 			Value: tree.NewDString(wait),
 		},
 	}
+	if args.VerifyBackups {
+		scheduleOptions = append(scheduleOptions, tree.KVOption{Key: optVerifyBackups})
+	}
 	sb := &tree.ScheduledBackup{
 		ScheduleLabelSpec: tree.ScheduleLabelSpec{
 			IfNotExists: false,
@@ -1219,6 +1294,12 @@ func TestShowCreateScheduleStatement(t *testing.T) {
 			fullRecurrence: "@daily",
 			recurrence:     "@hourly",
 		},
+		{
+			name:           "full-incremental-schedule-with-verification",
+			query:          `CREATE SCHEDULE FOR BACKUP INTO '%s' RECURRING '@hourly' FULL BACKUP '@daily' WITH SCHEDULE OPTIONS verify_backups`,
+			fullRecurrence: "@daily",
+			recurrence:     "@hourly",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -833,6 +833,9 @@ func resolveBackupManifests(
 	totalMemSize := ownedMemSize
 	ownedMemSize = 0
 
+	defaultURIs, mainBackupManifests, localityInfo = elideCompactedLayers(
+		defaultURIs, mainBackupManifests, localityInfo, endTime)
+
 	validatedDefaultURIs, validatedMainBackupManifests, validatedLocalityInfo, err := validateEndTimeAndTruncate(
 		defaultURIs, mainBackupManifests, localityInfo, endTime)
 
@@ -842,6 +845,68 @@ func resolveBackupManifests(
 	return validatedDefaultURIs, validatedMainBackupManifests, validatedLocalityInfo, totalMemSize, nil
 }
 
+// elideCompactedLayers removes the incremental layers of a backup chain which
+// have been compacted by ALTER BACKUP ... COMPACT, i.e. those whose time
+// interval is contained in that of a larger layer, as the compacted layer
+// replaces them. Compacted layers which end after the requested endTime are
+// removed instead, since the layers they were compacted from are still needed
+// to restore to a time before their end. localityInfo may be nil.
+func elideCompactedLayers(
+	defaultURIs []string,
+	mainBackupManifests []backuppb.BackupManifest,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+	endTime hlc.Timestamp,
+) ([]string, []backuppb.BackupManifest, []jobspb.RestoreDetails_BackupLocalityInfo) {
+	contains := func(outer, inner *backuppb.BackupManifest) bool {
+		if outer.StartTime.Equal(inner.StartTime) && outer.EndTime.Equal(inner.EndTime) {
+			return false
+		}
+		return outer.StartTime.LessEq(inner.StartTime) && inner.EndTime.LessEq(outer.EndTime)
+	}
+
+	// The full backup is never elided, so only the incremental layers are
+	// considered.
+	elided := make([]bool, len(mainBackupManifests))
+	for i := 1; i < len(mainBackupManifests); i++ {
+		if endTime.IsEmpty() || !endTime.Less(mainBackupManifests[i].EndTime) {
+			continue
+		}
+		for j := 1; j < len(mainBackupManifests); j++ {
+			if contains(&mainBackupManifests[i], &mainBackupManifests[j]) {
+				elided[i] = true
+				break
+			}
+		}
+	}
+	for i := 1; i < len(mainBackupManifests); i++ {
+		if elided[i] {
+			continue
+		}
+		for j := 1; j < len(mainBackupManifests); j++ {
+			if !elided[j] && contains(&mainBackupManifests[i], &mainBackupManifests[j]) {
+				elided[j] = true
+			}
+		}
+	}
+
+	n := 0
+	for i := range mainBackupManifests {
+		if elided[i] {
+			continue
+		}
+		defaultURIs[n] = defaultURIs[i]
+		mainBackupManifests[n] = mainBackupManifests[i]
+		if localityInfo != nil {
+			localityInfo[n] = localityInfo[i]
+		}
+		n++
+	}
+	if localityInfo != nil {
+		localityInfo = localityInfo[:n]
+	}
+	return defaultURIs[:n], mainBackupManifests[:n], localityInfo
+}
+
 func validateEndTimeAndTruncate(
 	defaultURIs []string,
 	mainBackupManifests []backuppb.BackupManifest,
@@ -978,7 +1043,7 @@ func loadSQLDescsFromBackupsAtTime(
 				continue
 			}
 			isObject = true
-		case catalog.TypeDescriptor, catalog.SchemaDescriptor:
+		case catalog.TypeDescriptor, catalog.SchemaDescriptor, catalog.FunctionDescriptor:
 			isObject = true
 		}
 		if isObject && byID[desc.GetParentID()] == nil {
@@ -1148,6 +1213,10 @@ func fetchPreviousBackups(
 	if err != nil {
 		return nil, nil, 0, err
 	}
+	// The next layer is appended to the layers which replace those compacted by
+	// ALTER BACKUP ... COMPACT.
+	_, prevBackups, _ = elideCompactedLayers(
+		append([]string(nil), prevBackupURIs...), prevBackups, nil /* localityInfo */, hlc.Timestamp{})
 
 	return prevBackups, encryptionOptions, size, nil
 }
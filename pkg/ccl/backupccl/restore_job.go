@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/ingesting"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/nstree"
@@ -627,7 +628,7 @@ func spansForAllRestoreTableIndexes(
 		// entire interval. DROPPED tables should never later become PUBLIC.
 		// TODO(pbardea): Consider and test the interaction between revision_history
 		// backups and OFFLINE tables.
-		rawTbl, _, _, _ := descpb.FromDescriptor(rev.Desc)
+		rawTbl, _, _, _, _ := descpb.FromDescriptor(rev.Desc)
 		if rawTbl != nil && !rawTbl.Dropped() {
 			tbl := tabledesc.NewBuilder(rawTbl).BuildImmutableTable()
 			// We only import spans for physical tables.
@@ -682,6 +683,7 @@ func createImportingDescriptors(
 	var writtenTypes []catalog.TypeDescriptor
 	var schemas []*schemadesc.Mutable
 	var types []*typedesc.Mutable
+	var functions []*funcdesc.Mutable
 	// Store the tables as both the concrete mutable structs and the interface
 	// to deal with the lack of slice covariance in go. We want the slice of
 	// mutable descriptors for rewriting but ultimately want to return the
@@ -724,6 +726,10 @@ func createImportingDescriptors(
 			mut := typedesc.NewBuilder(desc.TypeDesc()).BuildCreatedMutableType()
 			types = append(types, mut)
 			allMutableDescs = append(allMutableDescs, mut)
+		case catalog.FunctionDescriptor:
+			mut := funcdesc.NewBuilder(desc.FuncDesc()).BuildCreatedMutableFunction()
+			functions = append(functions, mut)
+			allMutableDescs = append(allMutableDescs, mut)
 		}
 	}
 
@@ -819,6 +825,18 @@ func createImportingDescriptors(
 		return nil, nil, err
 	}
 
+	// Assign new IDs to the functions, and update all references to use the new
+	// IDs.
+	if err := rewrite.FunctionDescs(
+		functions, details.DescriptorRewrites, details.OverrideDB,
+	); err != nil {
+		return nil, nil, err
+	}
+	writtenFunctions := make([]catalog.FunctionDescriptor, len(functions))
+	for i, fn := range functions {
+		writtenFunctions[i] = fn
+	}
+
 	// Finally, clean up / update any schema changer state inside descriptors
 	// globally.
 	if err := rewrite.MaybeClearSchemaChangerStateInDescs(allMutableDescs); err != nil {
@@ -835,6 +853,9 @@ func createImportingDescriptors(
 	for _, desc := range schemasToWrite {
 		desc.SetOffline("restoring")
 	}
+	for _, desc := range functions {
+		desc.SetOffline("restoring")
+	}
 	for _, desc := range mutableDatabases {
 		desc.SetOffline("restoring")
 	}
@@ -946,7 +967,7 @@ func createImportingDescriptors(
 			// Write the new descriptors which are set in the OFFLINE state.
 			if err := ingesting.WriteDescriptors(
 				ctx, p.ExecCfg().Codec, txn, p.User(), descsCol, databases, writtenSchemas, tables, writtenTypes,
-				details.DescriptorCoverage, nil /* extra */, restoreTempSystemDB,
+				writtenFunctions, details.DescriptorCoverage, nil /* extra */, restoreTempSystemDB,
 			); err != nil {
 				return errors.Wrapf(err, "restoring %d TableDescriptors from %d databases", len(tables), len(databases))
 			}
@@ -980,6 +1001,46 @@ func createImportingDescriptors(
 				}
 			}
 
+			// For new functions with existing parent schemas, the functions map on
+			// the schema descriptor needs to be updated.
+			newSchemaIDs := make(map[descpb.ID]struct{}, len(writtenSchemas))
+			for _, sc := range writtenSchemas {
+				newSchemaIDs[sc.GetID()] = struct{}{}
+			}
+			existingSchemasWithNewFunctions := make(map[descpb.ID][]*funcdesc.Mutable)
+			for _, fn := range functions {
+				parentSchemaID := fn.GetParentSchemaID()
+				if _, ok := newSchemaIDs[parentSchemaID]; !ok {
+					existingSchemasWithNewFunctions[parentSchemaID] = append(
+						existingSchemasWithNewFunctions[parentSchemaID], fn)
+				}
+			}
+			for scID, fns := range existingSchemasWithNewFunctions {
+				log.Infof(ctx, "writing %d function entries to schema %d", len(fns), scID)
+				desc, err := descsCol.GetMutableDescriptorByID(ctx, txn, scID)
+				if err != nil {
+					return err
+				}
+				sc, ok := desc.(*schemadesc.Mutable)
+				if !ok {
+					return errors.AssertionFailedf(
+						"cannot restore functions into schema %d of type %T", scID, desc)
+				}
+				for _, fn := range fns {
+					sc.AddFunction(fn.GetName(), descpb.SchemaDescriptor_FunctionOverload{
+						ID:         fn.GetID(),
+						ArgTypes:   fn.ArgTypes(),
+						ReturnType: fn.ReturnType.Type,
+						ReturnSet:  fn.ReturnType.ReturnSet,
+					})
+				}
+				if err := descsCol.WriteDescToBatch(
+					ctx, false /* kvTrace */, sc, b,
+				); err != nil {
+					return err
+				}
+			}
+
 			// We could be restoring tables that point to existing types. We need to
 			// ensure that those existing types are updated with back references pointing
 			// to the new tables being restored.
@@ -1103,6 +1164,10 @@ func createImportingDescriptors(
 			for i := range schemasToWrite {
 				details.SchemaDescs[i] = schemasToWrite[i].SchemaDesc()
 			}
+			details.FunctionDescs = make([]*descpb.FunctionDescriptor, len(functions))
+			for i := range functions {
+				details.FunctionDescs[i] = functions[i].FuncDesc()
+			}
 
 			// Update the job once all descs have been prepared for ingestion.
 			err := r.job.SetDetails(ctx, txn, details)
@@ -1781,6 +1846,7 @@ func (r *restoreResumer) publishDescriptors(
 	newTables := make([]*descpb.TableDescriptor, 0, len(details.TableDescs))
 	newTypes := make([]*descpb.TypeDescriptor, 0, len(details.TypeDescs))
 	newSchemas := make([]*descpb.SchemaDescriptor, 0, len(details.SchemaDescs))
+	newFunctions := make([]*descpb.FunctionDescriptor, 0, len(details.FunctionDescs))
 	newDBs := make([]*descpb.DatabaseDescriptor, 0, len(details.DatabaseDescs))
 
 	// Go through the descriptors and find any declarative schema change jobs
@@ -1872,6 +1938,10 @@ func (r *restoreResumer) publishDescriptors(
 		sc := all.LookupDescriptorEntry(details.SchemaDescs[i].GetID()).(catalog.SchemaDescriptor)
 		newSchemas = append(newSchemas, sc.SchemaDesc())
 	}
+	for i := range details.FunctionDescs {
+		fn := all.LookupDescriptorEntry(details.FunctionDescs[i].GetID()).(catalog.FunctionDescriptor)
+		newFunctions = append(newFunctions, fn.FuncDesc())
+	}
 	for i := range details.DatabaseDescs {
 		db := all.LookupDescriptorEntry(details.DatabaseDescs[i].GetID()).(catalog.DatabaseDescriptor)
 		newDBs = append(newDBs, db.DatabaseDesc())
@@ -1901,6 +1971,7 @@ func (r *restoreResumer) publishDescriptors(
 	details.TableDescs = newTables
 	details.TypeDescs = newTypes
 	details.SchemaDescs = newSchemas
+	details.FunctionDescs = newFunctions
 	details.DatabaseDescs = newDBs
 	if err := r.job.SetDetails(ctx, txn, details); err != nil {
 		return errors.Wrap(err,
@@ -1934,6 +2005,10 @@ func prefetchDescriptors(
 		expVersion[details.SchemaDescs[i].GetID()] = details.SchemaDescs[i].GetVersion()
 		allDescIDs.Add(details.SchemaDescs[i].GetID())
 	}
+	for i := range details.FunctionDescs {
+		expVersion[details.FunctionDescs[i].GetID()] = details.FunctionDescs[i].GetVersion()
+		allDescIDs.Add(details.FunctionDescs[i].GetID())
+	}
 	for i := range details.DatabaseDescs {
 		expVersion[details.DatabaseDescs[i].GetID()] = details.DatabaseDescs[i].GetVersion()
 		allDescIDs.Add(details.DatabaseDescs[i].GetID())
@@ -2142,6 +2217,55 @@ func (r *restoreResumer) dropDescriptors(
 		descsCol.AddDeletedDescriptor(mutType.GetID())
 	}
 
+	// Drop the function descriptors that this restore created. Like types,
+	// functions don't have a GC job process, so we can just delete them here.
+	// Functions restored into existing schemas also need to be removed from the
+	// functions mapping of those schemas.
+	restoredSchemaIDs := make(map[descpb.ID]struct{}, len(details.SchemaDescs))
+	for _, sc := range details.SchemaDescs {
+		restoredSchemaIDs[sc.ID] = struct{}{}
+	}
+	existingSchemasWithNewFunctions := make(map[descpb.ID][]*descpb.FunctionDescriptor)
+	for i := range details.FunctionDescs {
+		fnDesc := details.FunctionDescs[i]
+		mutFn, err := descsCol.GetMutableFunctionByID(ctx, txn, fnDesc.ID, tree.ObjectLookupFlags{
+			CommonLookupFlags: tree.CommonLookupFlags{
+				AvoidLeased:    true,
+				IncludeOffline: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		mutFn.SetDropped()
+		b.Del(catalogkeys.MakeDescMetadataKey(codec, fnDesc.ID))
+		descsCol.AddDeletedDescriptor(mutFn.GetID())
+		if _, ok := restoredSchemaIDs[fnDesc.ParentSchemaID]; !ok {
+			existingSchemasWithNewFunctions[fnDesc.ParentSchemaID] = append(
+				existingSchemasWithNewFunctions[fnDesc.ParentSchemaID], fnDesc)
+		}
+	}
+	for scID, fns := range existingSchemasWithNewFunctions {
+		log.Infof(ctx, "deleting %d function entries from schema %d", len(fns), scID)
+		desc, err := descsCol.GetMutableDescriptorByID(ctx, txn, scID)
+		if err != nil {
+			return err
+		}
+		sc, ok := desc.(*schemadesc.Mutable)
+		if !ok {
+			return errors.AssertionFailedf(
+				"unexpected descriptor of type %T for schema %d", desc, scID)
+		}
+		for _, fn := range fns {
+			sc.RemoveFunction(fn.Name, fn.ID)
+		}
+		if err := descsCol.WriteDescToBatch(
+			ctx, false /* kvTrace */, sc, b,
+		); err != nil {
+			return err
+		}
+	}
+
 	// Queue a GC job.
 	gcDetails := jobspb.SchemaChangeGCDetails{}
 	for _, tableID := range tablesToGC {
@@ -2175,6 +2299,9 @@ func (r *restoreResumer) dropDescriptors(
 	for _, schema := range details.SchemaDescs {
 		ignoredChildDescIDs[schema.ID] = struct{}{}
 	}
+	for _, fn := range details.FunctionDescs {
+		ignoredChildDescIDs[fn.ID] = struct{}{}
+	}
 	all, err := descsCol.GetAllDescriptors(ctx, txn)
 	if err != nil {
 		return err
@@ -41,6 +41,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/rewrite"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
@@ -70,6 +71,7 @@ const (
 	restoreOptSkipLocalitiesCheck       = "skip_localities_check"
 	restoreOptDebugPauseOn              = "debug_pause_on"
 	restoreOptAsTenant                  = "tenant"
+	restoreOptDroppedColumns            = "dropped_columns"
 
 	// The temporary database system tables will be restored into for full
 	// cluster backups.
@@ -170,6 +172,7 @@ func allocateDescriptorRewrites(
 	schemasByID map[descpb.ID]*schemadesc.Mutable,
 	tablesByID map[descpb.ID]*tabledesc.Mutable,
 	typesByID map[descpb.ID]*typedesc.Mutable,
+	functionsByID map[descpb.ID]*funcdesc.Mutable,
 	restoreDBs []catalog.DatabaseDescriptor,
 	descriptorCoverage tree.DescriptorCoverage,
 	opts tree.RestoreOptions,
@@ -250,6 +253,16 @@ func allocateDescriptorRewrites(
 			}
 		}
 
+		// Check that the functions run by the table's triggers exist.
+		for i := range table.Triggers {
+			if _, ok := functionsByID[table.Triggers[i].FuncID]; !ok {
+				return nil, errors.Errorf(
+					"cannot restore table %q without referenced function %d",
+					table.Name, table.Triggers[i].FuncID,
+				)
+			}
+		}
+
 		// Handle sequence ownership dependencies.
 		if table.IsSequence() && table.SequenceOpts.HasOwner() {
 			if _, ok := tablesByID[table.SequenceOpts.SequenceOwner.OwnerTableID]; !ok {
@@ -286,6 +299,22 @@ func allocateDescriptorRewrites(
 		}
 	}
 
+	// Include the function descriptors when calculating the max ID, and check
+	// that the relations referenced by the function bodies are restored too.
+	for _, fn := range functionsByID {
+		if int64(fn.ID) > maxDescIDInBackup {
+			maxDescIDInBackup = int64(fn.ID)
+		}
+		for _, id := range fn.DependsOn {
+			if _, ok := tablesByID[id]; !ok {
+				return nil, errors.Errorf(
+					"cannot restore function %q without referenced table %d",
+					fn.Name, id,
+				)
+			}
+		}
+	}
+
 	needsNewParentIDs := make(map[string][]descpb.ID)
 
 	// Increment the DescIDSequenceKey so that it is higher than both the max desc ID
@@ -603,15 +632,18 @@ func allocateDescriptorRewrites(
 					// Create a rewrite entry for the type.
 					descriptorRewrites[typ.ID] = &jobspb.DescriptorRewrite{ParentID: parentID}
 
-					// Ensure that there isn't a collision with the array type name.
-					arrTyp := typesByID[typ.ArrayTypeID]
-					typeName := tree.NewUnqualifiedTypeName(arrTyp.GetName())
-					err = col.Direct().CheckObjectCollision(ctx, txn, parentID, getParentSchemaID(typ), typeName)
-					if err != nil {
-						return errors.Wrapf(err, "name collision for %q's array type", typ.Name)
+					// Domains do not have an array type.
+					if typ.ArrayTypeID != descpb.InvalidID {
+						// Ensure that there isn't a collision with the array type name.
+						arrTyp := typesByID[typ.ArrayTypeID]
+						typeName := tree.NewUnqualifiedTypeName(arrTyp.GetName())
+						err = col.Direct().CheckObjectCollision(ctx, txn, parentID, getParentSchemaID(typ), typeName)
+						if err != nil {
+							return errors.Wrapf(err, "name collision for %q's array type", typ.Name)
+						}
+						// Create the rewrite entry for the array type as well.
+						descriptorRewrites[arrTyp.ID] = &jobspb.DescriptorRewrite{ParentID: parentID}
 					}
-					// Create the rewrite entry for the array type as well.
-					descriptorRewrites[arrTyp.ID] = &jobspb.DescriptorRewrite{ParentID: parentID}
 				} else {
 					// If there was a name collision, we'll try to see if we can remap
 					// this type to the type existing in the cluster.
@@ -639,10 +671,12 @@ func allocateDescriptorRewrites(
 						ID:         existingType.GetID(),
 						ToExisting: true,
 					}
-					descriptorRewrites[typ.ArrayTypeID] = &jobspb.DescriptorRewrite{
-						ParentID:   existingType.GetParentID(),
-						ID:         existingType.GetArrayTypeID(),
-						ToExisting: true,
+					if typ.ArrayTypeID != descpb.InvalidID {
+						descriptorRewrites[typ.ArrayTypeID] = &jobspb.DescriptorRewrite{
+							ParentID:   existingType.GetParentID(),
+							ID:         existingType.GetArrayTypeID(),
+							ToExisting: true,
+						}
 					}
 				}
 				// If we're restoring to a public schema of database that already exists
@@ -652,7 +686,76 @@ func allocateDescriptorRewrites(
 					typ.GetParentSchemaID() == descpb.InvalidID {
 					publicSchemaID := parentDB.GetSchemaID(tree.PublicSchema)
 					descriptorRewrites[typ.ID].ParentSchemaID = publicSchemaID
-					descriptorRewrites[typ.ArrayTypeID].ParentSchemaID = publicSchemaID
+					if typ.ArrayTypeID != descpb.InvalidID {
+						descriptorRewrites[typ.ArrayTypeID].ParentSchemaID = publicSchemaID
+					}
+				}
+			}
+		}
+
+		// Construct rewrites for functions. Functions do not have namespace
+		// entries, so there are no name collisions to check for, but a function
+		// cannot be restored into a schema which already has an overload of the
+		// function with the same argument types.
+		for _, fn := range functionsByID {
+			// If a descriptor has already been assigned a rewrite, then move on.
+			if _, ok := descriptorRewrites[fn.ID]; ok {
+				continue
+			}
+
+			targetDB, err := resolveTargetDB(ctx, txn, p, databasesByID, intoDB, descriptorCoverage, fn)
+			if err != nil {
+				return err
+			}
+
+			if _, ok := restoreDBNames[targetDB]; ok {
+				needsNewParentIDs[targetDB] = append(needsNewParentIDs[targetDB], fn.ID)
+			} else if descriptorCoverage == tree.AllDescriptors {
+				descriptorRewrites[fn.ID] = &jobspb.DescriptorRewrite{ParentID: fn.ParentID}
+			} else {
+				parentID, parentDB, err := getDatabaseIDAndDesc(ctx, txn, col, targetDB)
+				if err != nil {
+					return err
+				}
+				if err := p.CheckPrivilege(ctx, parentDB, privilege.CREATE); err != nil {
+					return err
+				}
+				descriptorRewrites[fn.ID] = &jobspb.DescriptorRewrite{ParentID: parentID}
+
+				// If the function is restored into an existing schema, check that
+				// the schema does not already have the same overload.
+				parentSchemaID := fn.GetParentSchemaID()
+				if rw, ok := descriptorRewrites[parentSchemaID]; ok {
+					if !rw.ToExisting {
+						continue
+					}
+					parentSchemaID = rw.ID
+				} else {
+					parentSchemaID = parentDB.GetSchemaID(tree.PublicSchema)
+					descriptorRewrites[fn.ID].ParentSchemaID = parentSchemaID
+				}
+				sc, err := col.Direct().MustGetSchemaDescByID(ctx, txn, parentSchemaID)
+				if err != nil {
+					return err
+				}
+				existing, ok := sc.GetFunction(fn.GetName())
+				if !ok {
+					continue
+				}
+				argTypes := fn.ArgTypes()
+			overloads:
+				for _, overload := range existing.Overloads {
+					if len(overload.ArgTypes) != len(argTypes) {
+						continue
+					}
+					for i := range argTypes {
+						if !argTypes[i].Equivalent(overload.ArgTypes[i]) {
+							continue overloads
+						}
+					}
+					return pgerror.Newf(pgcode.DuplicateFunction,
+						"function %q already exists with same argument types in schema %q",
+						fn.GetName(), sc.GetName())
 				}
 			}
 		}
@@ -733,6 +836,16 @@ func allocateDescriptorRewrites(
 		}
 	}
 
+	// Update remapping information for function descriptors.
+	for _, fn := range functionsByID {
+		if descriptorCoverage == tree.AllDescriptors {
+			// The function doesn't need to be remapped.
+			descriptorRewrites[fn.ID].ID = fn.ID
+		} else {
+			descriptorsToRemap = append(descriptorsToRemap, fn)
+		}
+	}
+
 	// Update remapping information for schema descriptors.
 	for _, sc := range schemasByID {
 		if descriptorCoverage == tree.AllDescriptors {
@@ -781,6 +894,9 @@ func allocateDescriptorRewrites(
 	for _, typ := range typesByID {
 		rewriteObject(typ)
 	}
+	for _, fn := range functionsByID {
+		rewriteObject(fn)
+	}
 
 	return descriptorRewrites, nil
 }
@@ -993,6 +1109,9 @@ func restoreJobDescription(
 		AsOf:               restore.AsOf,
 		Targets:            restore.Targets,
 		From:               make([]tree.StringOrPlaceholderOptList, len(restore.From)),
+		Where:              restore.Where,
+		Into:               restore.Into,
+		UpsertInto:         restore.UpsertInto,
 	}
 
 	var options tree.RestoreOptions
@@ -1033,6 +1152,15 @@ func restorePlanHook(
 		return nil, nil, nil, false, err
 	}
 
+	if restoreStmt.Where != nil {
+		if err := checkRestoreRowsOptions(restoreStmt); err != nil {
+			return nil, nil, nil, false, err
+		}
+	} else if restoreStmt.Options.DroppedColumns {
+		return nil, nil, nil, false, errors.Newf(
+			"option %q can only be used with RESTORE ... WHERE ... INTO", restoreOptDroppedColumns)
+	}
+
 	fromFns := make([]func() ([]string, error), len(restoreStmt.From))
 	for i := range restoreStmt.From {
 		fromFn, err := p.TypeAsStringArray(ctx, tree.Exprs(restoreStmt.From[i]), "RESTORE")
@@ -1555,7 +1683,7 @@ func doRestorePlan(
 	for _, m := range mainBackupManifests {
 		spans := roachpb.Spans(m.Spans)
 		for i := range m.Descriptors {
-			table, _, _, _ := descpb.FromDescriptor(&m.Descriptors[i])
+			table, _, _, _, _ := descpb.FromDescriptor(&m.Descriptors[i])
 			if table == nil {
 				continue
 			}
@@ -1588,6 +1716,30 @@ func doRestorePlan(
 				"use SHOW BACKUP to find correct targets")
 	}
 
+	var fromDescription [][]string
+	if len(from) == 1 {
+		fromDescription = [][]string{fullyResolvedBaseDirectory}
+	} else {
+		fromDescription = from
+	}
+	description, err := restoreJobDescription(
+		p,
+		restoreStmt,
+		fromDescription,
+		fullyResolvedIncrementalsDirectory,
+		restoreStmt.Options,
+		intoDB,
+		newDBName,
+		kms)
+	if err != nil {
+		return err
+	}
+
+	if restoreStmt.Where != nil {
+		return restoreRows(ctx, p, restoreStmt, sqlDescs, defaultURIs, mainBackupManifests,
+			localityInfo, encryption, endTime, description, resultsCh)
+	}
+
 	var revalidateIndexes []jobspb.RestoreDetails_RevalidateIndex
 	for _, desc := range sqlDescs {
 		tbl, ok := desc.(catalog.TableDescriptor)
@@ -1667,6 +1819,7 @@ func doRestorePlan(
 	schemasByID := make(map[descpb.ID]*schemadesc.Mutable)
 	tablesByID := make(map[descpb.ID]*tabledesc.Mutable)
 	typesByID := make(map[descpb.ID]*typedesc.Mutable)
+	functionsByID := make(map[descpb.ID]*funcdesc.Mutable)
 
 	for _, desc := range sqlDescs {
 		switch desc := desc.(type) {
@@ -1678,6 +1831,8 @@ func doRestorePlan(
 			tablesByID[desc.ID] = desc
 		case *typedesc.Mutable:
 			typesByID[desc.ID] = desc
+		case *funcdesc.Mutable:
+			functionsByID[desc.ID] = desc
 		}
 	}
 
@@ -1729,6 +1884,7 @@ func doRestorePlan(
 		schemasByID,
 		filteredTablesByID,
 		typesByID,
+		functionsByID,
 		restoreDBs,
 		restoreStmt.DescriptorCoverage,
 		restoreStmt.Options,
@@ -1738,24 +1894,6 @@ func doRestorePlan(
 	if err != nil {
 		return err
 	}
-	var fromDescription [][]string
-	if len(from) == 1 {
-		fromDescription = [][]string{fullyResolvedBaseDirectory}
-	} else {
-		fromDescription = from
-	}
-	description, err := restoreJobDescription(
-		p,
-		restoreStmt,
-		fromDescription,
-		fullyResolvedIncrementalsDirectory,
-		restoreStmt.Options,
-		intoDB,
-		newDBName,
-		kms)
-	if err != nil {
-		return err
-	}
 
 	var databases []*dbdesc.Mutable
 	for i := range databasesByID {
@@ -1775,6 +1913,10 @@ func doRestorePlan(
 	for _, desc := range typesByID {
 		types = append(types, desc)
 	}
+	var functions []*funcdesc.Mutable
+	for _, desc := range functionsByID {
+		functions = append(functions, desc)
+	}
 
 	// We attempt to rewrite ID's in the collected type and table descriptors
 	// to catch errors during this process here, rather than in the job itself.
@@ -1790,6 +1932,9 @@ func doRestorePlan(
 	if err := rewrite.TypeDescs(types, descriptorRewrites); err != nil {
 		return err
 	}
+	if err := rewrite.FunctionDescs(functions, descriptorRewrites, intoDB); err != nil {
+		return err
+	}
 	for i := range revalidateIndexes {
 		revalidateIndexes[i].TableID = descriptorRewrites[revalidateIndexes[i].TableID].ID
 	}
@@ -1841,7 +1986,21 @@ func doRestorePlan(
 		Progress: jobspb.RestoreProgress{},
 	}
 
-	if restoreStmt.Options.Detached {
+	return createRestoreJob(ctx, p, jr, restoreStmt.Options.Detached, collectTelemetry, resultsCh)
+}
+
+// createRestoreJob creates the job of a RESTORE in the transaction of the
+// statement. Unless the RESTORE is detached, it then commits the transaction,
+// starts the job and waits for it to complete.
+func createRestoreJob(
+	ctx context.Context,
+	p sql.PlanHookState,
+	jr jobs.Record,
+	detached bool,
+	collectTelemetry func(),
+	resultsCh chan<- tree.Datums,
+) error {
+	if detached {
 		// When running in detached mode, we simply create the job record.
 		// We do not wait for the job to finish.
 		jobID := p.ExecCfg().JobRegistry.MakeJobID()
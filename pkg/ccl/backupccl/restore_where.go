@@ -0,0 +1,810 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupencryption"
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// restoreRowsBatchSize is the number of rows which are decoded, and then
+// written, at a time by `RESTORE TABLE ... WHERE ... INTO`. Each batch of
+// written rows is committed in its own transaction.
+const restoreRowsBatchSize = 100
+
+// checkRestoreRowsOptions returns an error if the given `RESTORE TABLE ...
+// WHERE ... INTO` uses options which do not apply to it.
+func checkRestoreRowsOptions(restoreStmt *tree.Restore) error {
+	if restoreStmt.DescriptorCoverage == tree.AllDescriptors || restoreStmt.SystemUsers ||
+		restoreStmt.Targets.TenantID.IsSet() || len(restoreStmt.Targets.Databases) > 0 ||
+		len(restoreStmt.Targets.Tables.TablePatterns) != 1 {
+		return errors.New("RESTORE ... WHERE can only be used to restore a single table")
+	}
+	opts := restoreStmt.Options
+	if opts.DroppedColumns && restoreStmt.UpsertInto {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"option %q cannot be used with RESTORE ... WHERE ... UPSERT INTO", restoreOptDroppedColumns)
+	}
+	var unsupported string
+	switch {
+	case opts.IntoDB != nil:
+		unsupported = restoreOptIntoDB
+	case opts.NewDBName != nil:
+		unsupported = "new_db_name"
+	case opts.SkipMissingFKs:
+		unsupported = restoreOptSkipMissingFKs
+	case opts.SkipMissingSequences:
+		unsupported = restoreOptSkipMissingSequences
+	case opts.SkipMissingSequenceOwners:
+		unsupported = restoreOptSkipMissingSequenceOwners
+	case opts.SkipMissingViews:
+		unsupported = restoreOptSkipMissingViews
+	case opts.DebugPauseOn != nil:
+		unsupported = restoreOptDebugPauseOn
+	case opts.AsTenant != nil:
+		unsupported = restoreOptAsTenant
+	default:
+		return nil
+	}
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"option %q cannot be used with RESTORE ... WHERE", unsupported)
+}
+
+// restoreRows plans `RESTORE TABLE ... WHERE <predicate> INTO <table>`, which
+// restores the rows of the single table in sqlDescs which satisfy the
+// predicate into a new table, or upserts them into an existing table. The new
+// table is created in the transaction of the statement, along with a RESTORE
+// ROWS job which writes the rows, and which is run like the job of a RESTORE.
+// With the dropped_columns option, only the primary key and the columns
+// dropped from the table since are restored.
+func restoreRows(
+	ctx context.Context,
+	p sql.PlanHookState,
+	restoreStmt *tree.Restore,
+	sqlDescs []catalog.Descriptor,
+	defaultURIs []string,
+	manifests []backuppb.BackupManifest,
+	localityInfo []jobspb.RestoreDetails_BackupLocalityInfo,
+	encryption *jobspb.BackupEncryptionOptions,
+	endTime hlc.Timestamp,
+	description string,
+	resultsCh chan<- tree.Datums,
+) error {
+	var table catalog.TableDescriptor
+	dbNames := make(map[descpb.ID]string)
+	schemaNames := map[descpb.ID]string{keys.PublicSchemaID: tree.PublicSchema}
+	for _, desc := range sqlDescs {
+		switch desc := desc.(type) {
+		case catalog.TableDescriptor:
+			if table != nil {
+				return errors.New("RESTORE ... WHERE can only be used to restore a single table")
+			}
+			table = desc
+		case catalog.DatabaseDescriptor:
+			dbNames[desc.GetID()] = desc.GetName()
+		case catalog.SchemaDescriptor:
+			schemaNames[desc.GetID()] = desc.GetName()
+		}
+	}
+	if table == nil || !table.IsPhysicalTable() || table.IsSequence() {
+		return errors.New("RESTORE ... WHERE can only be used to restore the rows of a table")
+	}
+	if table.ContainsUserDefinedTypes() {
+		return unimplemented.New("restore where user defined types",
+			"RESTORE ... WHERE cannot restore tables with columns of user defined types")
+	}
+
+	// The predicate is validated against the table in the backup, and stored
+	// with its column names unqualified so that the job can resolve them
+	// without the names of the database and schema of the table.
+	tn := tree.MakeTableNameWithSchema(tree.Name(dbNames[table.GetParentID()]),
+		tree.Name(schemaNames[table.GetParentSchemaID()]), tree.Name(table.GetName()))
+	semaCtx := *p.SemaCtx()
+	predicate, _, _, err := schemaexpr.DequalifyAndValidateExpr(
+		ctx, table, restoreStmt.Where.Expr, types.Bool, "RESTORE ... WHERE", &semaCtx,
+		volatility.Stable, &tn,
+	)
+	if err != nil {
+		return err
+	}
+
+	if restoreStmt.Options.DroppedColumns {
+		if err := createDroppedColumnsTable(ctx, p, restoreStmt, table, &tn); err != nil {
+			return err
+		}
+	} else if !restoreStmt.UpsertInto {
+		if err := createRestoreRowsTable(ctx, p, restoreStmt, table, manifests, endTime); err != nil {
+			return err
+		}
+	}
+	target := restoreStmt.Into.ToTableName()
+	_, targetDesc, err := resolver.ResolveExistingTableObject(
+		ctx, p, &target, tree.ObjectLookupFlagsWithRequiredTableKind(tree.ResolveRequireTableDesc),
+	)
+	if err != nil {
+		return err
+	}
+	cols, _, _ := restoreRowsColumns(table)
+	if _, _, err := restoreRowsTargetColumns(targetDesc, cols); err != nil {
+		return err
+	}
+
+	jr := jobs.Record{
+		Description:   description,
+		Username:      p.User(),
+		DescriptorIDs: descpb.IDs{targetDesc.GetID()},
+		Details: jobspb.RestoreRowsDetails{
+			URIs:               defaultURIs,
+			BackupLocalityInfo: localityInfo,
+			EndTime:            endTime,
+			Encryption:         encryption,
+			TableID:            table.GetID(),
+			Predicate:          predicate,
+			TargetID:           targetDesc.GetID(),
+			Upsert:             restoreStmt.UpsertInto,
+		},
+		Progress: jobspb.RestoreRowsProgress{},
+	}
+	collectTelemetry := func() {
+		telemetry.Count("restore.rows.started")
+	}
+	return createRestoreJob(ctx, p, jr, restoreStmt.Options.Detached, collectTelemetry, resultsCh)
+}
+
+// restoreRowsColumns returns the columns of the given table which are stored
+// in its primary index, which are those the rows restored from it are decoded
+// into, along with their IDs and their ordinals in the decoded rows.
+func restoreRowsColumns(
+	table catalog.TableDescriptor,
+) (cols []catalog.Column, colIDs []descpb.ColumnID, colOrdinals catalog.TableColMap) {
+	for _, col := range table.PublicColumns() {
+		if !col.IsVirtual() {
+			colOrdinals.Set(col.GetID(), len(cols))
+			cols = append(cols, col)
+			colIDs = append(colIDs, col.GetID())
+		}
+	}
+	return cols, colIDs, colOrdinals
+}
+
+type restoreRowsResumer struct {
+	job *jobs.Job
+	// rows is the number of rows restored by the job, reported once it
+	// completes.
+	rows int64
+}
+
+var _ jobs.Resumer = &restoreRowsResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+//
+// The job scans the primary index of the table in the backup, rewriting its
+// keys into the keyspace of the target table, decodes its rows, and writes
+// those satisfying the predicate. Each batch of written rows is committed
+// along with the key of the last row read in the backup, after which a
+// resumed job starts.
+func (r *restoreRowsResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	details := r.job.Details().(jobspb.RestoreRowsDetails)
+	progress := r.job.Progress().Details.(*jobspb.Progress_RestoreRows).RestoreRows
+
+	mem := execCfg.RootMemoryMonitor.MakeBoundAccount()
+	defer mem.Close(ctx)
+	manifests, memSize, err := loadBackupManifests(ctx, &mem, details.URIs, p.User(),
+		execCfg.DistSQLSrv.ExternalStorageFromURI, details.Encryption)
+	if err != nil {
+		return err
+	}
+	defer mem.Shrink(ctx, memSize)
+
+	allDescs, _ := loadSQLDescsFromBackupsAtTime(manifests, details.EndTime)
+	var tableDescs []catalog.Descriptor
+	for _, desc := range allDescs {
+		if desc.GetID() == details.TableID {
+			tableDescs = append(tableDescs, desc)
+		}
+	}
+	if len(tableDescs) != 1 {
+		return errors.Newf("table %d is not in the backup", details.TableID)
+	}
+	if err := maybeUpgradeDescriptors(tableDescs, true /* skipFKsWithNoMatchingTable */); err != nil {
+		return err
+	}
+	table, ok := tableDescs[0].(catalog.TableDescriptor)
+	if !ok {
+		return errors.AssertionFailedf("descriptor %d in the backup is not a table", details.TableID)
+	}
+	var targetDesc catalog.TableDescriptor
+	if err := sql.DescsTxn(ctx, execCfg, func(
+		ctx context.Context, txn *kv.Txn, col *descs.Collection,
+	) (err error) {
+		targetDesc, err = col.GetImmutableTableByID(
+			ctx, txn, details.TargetID, tree.ObjectLookupFlagsWithRequired(),
+		)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	cols, colIDs, colOrdinals := restoreRowsColumns(table)
+	predicate, err := parser.ParseExpr(details.Predicate)
+	if err != nil {
+		return err
+	}
+	tn := tree.MakeUnqualifiedTableName(tree.Name(table.GetName()))
+	evalCtx := p.ExtendedEvalContext().Context.Copy()
+	semaCtx := tree.MakeSemaContext()
+	filter, err := schemaexpr.MakeRowFilterExpr(
+		ctx, predicate, "RESTORE ... WHERE", table, &tn, cols, evalCtx, &semaCtx,
+	)
+	if err != nil {
+		return err
+	}
+	w, err := makeRestoreRowsWriter(execCfg, p.User(), details.Upsert, targetDesc, cols)
+	if err != nil {
+		return err
+	}
+	w.rows = progress.Rows
+
+	// The keys of the backup are rewritten into the keyspace of the target
+	// table, under the schema of the table in the backup, and decoded by it.
+	rewritten := tabledesc.NewBuilder(table.TableDesc()).BuildCreatedMutableTable()
+	rewritten.ID = targetDesc.GetID()
+	backupCodec := keys.SystemSQLCodec
+	backupTenantID := roachpb.SystemTenantID
+	if m := manifests[len(manifests)-1]; len(m.Spans) != 0 && !m.HasTenants() {
+		_, backupTenantID, err = keys.DecodeTenantPrefix(m.Spans[0].Key)
+		if err != nil {
+			return err
+		}
+		backupCodec = keys.MakeSQLCodec(backupTenantID)
+	}
+	var tenantRekeys []execinfrapb.TenantRekey
+	if backupTenantID == roachpb.SystemTenantID {
+		tenantRekeys = append(tenantRekeys, isBackupFromSystemTenantRekey)
+	}
+	kr, err := makeKeyRewriter(execCfg.Codec,
+		map[descpb.ID]catalog.TableDescriptor{table.GetID(): rewritten}, tenantRekeys,
+		false /* restoreTenantFromStream */)
+	if err != nil {
+		return err
+	}
+
+	var spec descpb.IndexFetchSpec
+	if err := rowenc.InitIndexFetchSpec(
+		&spec, execCfg.Codec, rewritten, rewritten.GetPrimaryIndex(), colIDs,
+	); err != nil {
+		return err
+	}
+	var rf row.Fetcher
+	if err := rf.Init(ctx, row.FetcherInitArgs{Alloc: &tree.DatumAlloc{}, Spec: &spec}); err != nil {
+		return err
+	}
+	defer rf.Close(ctx)
+
+	var encryptionOpts *roachpb.FileEncryptionOptions
+	if details.Encryption != nil {
+		key, err := backupencryption.GetEncryptionKey(ctx, details.Encryption, execCfg.Settings,
+			execCfg.ExternalIODirConfig)
+		if err != nil {
+			return err
+		}
+		encryptionOpts = &roachpb.FileEncryptionOptions{Key: key}
+	}
+
+	// The files of each layer are in the directory of its default URI, unless
+	// they are in one of its locality URIs.
+	for i := range manifests {
+		manifests[i].Dir, err = cloud.ExternalStorageConfFromURI(details.URIs[i], p.User())
+		if err != nil {
+			return err
+		}
+	}
+	span := table.PrimaryIndexSpan(backupCodec)
+	if err := checkCoverage(ctx, []roachpb.Span{span}, manifests); err != nil {
+		return err
+	}
+	localityMap, err := makeBackupLocalityMap(details.BackupLocalityInfo, p.User())
+	if err != nil {
+		return err
+	}
+	rr := &rowRestorer{
+		job:        r.job,
+		execCfg:    execCfg,
+		kr:         kr,
+		rf:         &rf,
+		w:          w,
+		evalCtx:    evalCtx,
+		filter:     filter,
+		ivars:      &schemaexpr.RowIndexedVarContainer{Cols: cols, Mapping: colOrdinals},
+		encryption: encryptionOpts,
+		endTime:    details.EndTime,
+		resumeKey:  progress.ResumeKey,
+	}
+	entries := makeSimpleImportSpans(
+		[]roachpb.Span{span}, manifests, localityMap, nil, /* lowWaterMark */
+	)
+	for i, entry := range entries {
+		rr.fractionCompleted = float32(i) / float32(len(entries))
+		if err := rr.restoreEntry(ctx, entry); err != nil {
+			return err
+		}
+	}
+	if err := rr.flush(ctx); err != nil {
+		return err
+	}
+	r.rows = w.rows
+	return nil
+}
+
+// ReportResults implements JobResultsReporter interface.
+func (r *restoreRowsResumer) ReportResults(ctx context.Context, resultsCh chan<- tree.Datums) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case resultsCh <- tree.Datums{
+		tree.NewDInt(tree.DInt(r.job.ID())),
+		tree.NewDString(string(jobs.StatusSucceeded)),
+		tree.NewDFloat(tree.DFloat(1.0)),
+		tree.NewDInt(tree.DInt(r.rows)),
+		tree.NewDInt(0),
+		tree.NewDInt(0),
+	}:
+		return nil
+	}
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. The rows which were
+// committed before the job failed or was canceled are left in the target
+// table.
+func (r *restoreRowsResumer) OnFailOrCancel(context.Context, interface{}) error {
+	return nil
+}
+
+// createRestoreRowsTable creates the table into which `RESTORE TABLE ... WHERE
+// ... INTO` restores rows, with the schema of the table in the backup, but
+// without its foreign keys.
+func createRestoreRowsTable(
+	ctx context.Context,
+	p sql.PlanHookState,
+	restoreStmt *tree.Restore,
+	table catalog.TableDescriptor,
+	manifests []backuppb.BackupManifest,
+	endTime hlc.Timestamp,
+) error {
+	for _, col := range table.PublicColumns() {
+		if col.NumUsesSequences() > 0 {
+			return unimplemented.New("restore where sequences",
+				"RESTORE ... WHERE cannot restore tables with columns which use sequences into a new table")
+		}
+	}
+	allDescs, _ := loadSQLDescsFromBackupsAtTime(manifests, endTime)
+	descProtos := make([]descpb.Descriptor, len(allDescs))
+	for i, desc := range allDescs {
+		descProtos[i] = *desc.DescriptorProto()
+	}
+	create, err := p.ShowCreate(ctx, "" /* dbPrefix */, descProtos, table, sql.ShowCreateDisplayOptions{
+		FKDisplayMode:  sql.OmitFKClausesFromCreate,
+		IgnoreComments: true,
+	})
+	if err != nil {
+		return err
+	}
+	parsed, err := parser.ParseOne(create)
+	if err != nil {
+		return err
+	}
+	createStmt, ok := parsed.AST.(*tree.CreateTable)
+	if !ok {
+		return errors.AssertionFailedf("unexpected statement %s creating table", parsed.SQL)
+	}
+	createStmt.Table = restoreStmt.Into.ToTableName()
+	return execRestoreRowsCreateTable(ctx, p, createStmt)
+}
+
+// createDroppedColumnsTable creates the table into which `RESTORE TABLE ...
+// WHERE ... INTO ... WITH dropped_columns` restores rows: a side table of the
+// primary key columns of the table in the backup, and those of its columns
+// which have since been dropped from the table, which is the table in the
+// cluster with the same ID.
+func createDroppedColumnsTable(
+	ctx context.Context,
+	p sql.PlanHookState,
+	restoreStmt *tree.Restore,
+	table catalog.TableDescriptor,
+	tn *tree.TableName,
+) error {
+	live, err := p.ExtendedEvalContext().Descs.GetImmutableTableByID(
+		ctx, p.Txn(), table.GetID(), tree.ObjectLookupFlagsWithRequired(),
+	)
+	if err != nil {
+		return errors.Wrapf(err, "looking up the columns of table %s", tn)
+	}
+	createStmt := &tree.CreateTable{Table: restoreStmt.Into.ToTableName()}
+	var pkCols tree.IndexElemList
+	addColumn := func(col catalog.Column) {
+		def := &tree.ColumnTableDef{Name: tree.Name(col.GetName()), Type: col.GetType()}
+		if !col.IsNullable() {
+			def.Nullable.Nullability = tree.NotNull
+		}
+		createStmt.Defs = append(createStmt.Defs, def)
+	}
+	pk := table.GetPrimaryIndex()
+	for i := 0; i < pk.NumKeyColumns(); i++ {
+		col, err := table.FindColumnWithID(pk.GetKeyColumnID(i))
+		if err != nil {
+			return err
+		}
+		// Virtual key columns, such as the shard columns of hash sharded primary
+		// keys, are not restored.
+		if col.IsVirtual() {
+			continue
+		}
+		addColumn(col)
+		pkCols = append(pkCols, tree.IndexElem{Column: tree.Name(col.GetName())})
+	}
+	pkColIDs := pk.CollectKeyColumnIDs()
+	var dropped int
+	for _, col := range table.PublicColumns() {
+		if col.IsVirtual() || pkColIDs.Contains(col.GetID()) {
+			continue
+		}
+		if liveCol, err := live.FindColumnWithID(col.GetID()); err == nil && liveCol.Public() {
+			continue
+		}
+		addColumn(col)
+		dropped++
+	}
+	if dropped == 0 {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"none of the columns of table %s in the backup have been dropped", tn)
+	}
+	createStmt.Defs = append(createStmt.Defs, &tree.UniqueConstraintTableDef{
+		IndexTableDef: tree.IndexTableDef{Columns: pkCols},
+		PrimaryKey:    true,
+	})
+	return execRestoreRowsCreateTable(ctx, p, createStmt)
+}
+
+// execRestoreRowsCreateTable creates the table into which `RESTORE TABLE ...
+// WHERE ... INTO` restores rows in the transaction of the statement, which
+// also creates the job restoring them.
+func execRestoreRowsCreateTable(
+	ctx context.Context, p sql.PlanHookState, createStmt *tree.CreateTable,
+) error {
+	_, err := p.ExecCfg().InternalExecutor.ExecEx(
+		ctx, "restore-where-create-table", p.Txn(),
+		sessiondata.InternalExecutorOverride{
+			User:       p.User(),
+			Database:   p.SessionData().Database,
+			SearchPath: &p.SessionData().SearchPath,
+		},
+		tree.AsString(createStmt),
+	)
+	return err
+}
+
+// restoreRowsWriter writes the rows restored by `RESTORE TABLE ... WHERE ...
+// INTO` to the target table through the internal executor.
+type restoreRowsWriter struct {
+	execCfg *sql.ExecutorConfig
+	user    username.SQLUsername
+	// prefix is the start of the statement writing rows, up to VALUES.
+	prefix string
+	// ords are the ordinals in the restored rows of the columns written.
+	ords []int
+
+	pending []tree.Datums
+	rows    int64
+}
+
+func makeRestoreRowsWriter(
+	execCfg *sql.ExecutorConfig,
+	user username.SQLUsername,
+	upsert bool,
+	targetDesc catalog.TableDescriptor,
+	cols []catalog.Column,
+) (*restoreRowsWriter, error) {
+	ords, names, err := restoreRowsTargetColumns(targetDesc, cols)
+	if err != nil {
+		return nil, err
+	}
+	verb := "INSERT"
+	if upsert {
+		verb = "UPSERT"
+	}
+	// The target table is referenced by ID, so that it can be renamed while
+	// the rows are restored.
+	return &restoreRowsWriter{
+		execCfg: execCfg,
+		user:    user,
+		prefix: fmt.Sprintf("%s INTO [%d AS t] (%s) VALUES ",
+			verb, targetDesc.GetID(), strings.Join(names, ", ")),
+		ords: ords,
+	}, nil
+}
+
+// restoreRowsTargetColumns returns the ordinals in the restored rows, and the
+// names, of the columns which are written to the target table.
+func restoreRowsTargetColumns(
+	targetDesc catalog.TableDescriptor, cols []catalog.Column,
+) (ords []int, names []string, _ error) {
+	for i, col := range cols {
+		// Computed columns are computed by the target table, and columns which are
+		// not in the target table are not restored.
+		targetCol, err := targetDesc.FindColumnWithName(col.ColName())
+		if err != nil || targetCol.IsComputed() || !targetCol.Public() {
+			continue
+		}
+		ords = append(ords, i)
+		names = append(names, tree.NameString(col.GetName()))
+	}
+	if len(names) == 0 {
+		return nil, nil, errors.Newf("table %s has none of the columns of the restored table",
+			tree.Name(targetDesc.GetName()))
+	}
+	return ords, names, nil
+}
+
+// write writes the buffered rows in the given transaction.
+func (w *restoreRowsWriter) write(ctx context.Context, txn *kv.Txn) error {
+	var stmt strings.Builder
+	stmt.WriteString(w.prefix)
+	args := make([]interface{}, 0, len(w.pending)*len(w.ords))
+	for i, datums := range w.pending {
+		if i > 0 {
+			stmt.WriteString(", ")
+		}
+		stmt.WriteString("(")
+		for j, ord := range w.ords {
+			if j > 0 {
+				stmt.WriteString(", ")
+			}
+			args = append(args, datums[ord])
+			fmt.Fprintf(&stmt, "$%d", len(args))
+		}
+		stmt.WriteString(")")
+	}
+	_, err := w.execCfg.InternalExecutor.ExecEx(
+		ctx, "restore-where-write-rows", txn,
+		sessiondata.InternalExecutorOverride{User: w.user}, stmt.String(), args...,
+	)
+	return errors.Wrap(err, "writing restored rows")
+}
+
+// rowRestorer reads the rows of a table from the files of a backup, and writes
+// those satisfying a predicate.
+type rowRestorer struct {
+	job        *jobs.Job
+	execCfg    *sql.ExecutorConfig
+	kr         *KeyRewriter
+	rf         *row.Fetcher
+	w          *restoreRowsWriter
+	evalCtx    *eval.Context
+	filter     tree.TypedExpr
+	ivars      *schemaexpr.RowIndexedVarContainer
+	encryption *roachpb.FileEncryptionOptions
+	endTime    hlc.Timestamp
+	// resumeKey is the key in the backup of the last row whose batch was
+	// committed by a previous run of the job, if any.
+	resumeKey roachpb.Key
+	// fractionCompleted is the fraction of the entries of the cover of the
+	// table which were restored before the current one.
+	fractionCompleted float32
+
+	// kvs are the rewritten KVs of the rows which have been read but not yet
+	// decoded, rows is the number of those rows, and lastRow is the key in the
+	// backup of the last of them.
+	kvs     []roachpb.KeyValue
+	rows    int
+	lastRow roachpb.Key
+}
+
+// restoreEntry reads the KVs of the span of the given entry of the cover of the
+// table as of the end time, from all of its files.
+func (r *rowRestorer) restoreEntry(ctx context.Context, entry execinfrapb.RestoreSpanEntry) error {
+	// The rows up to the resume key were restored by a previous run of the job.
+	startKey := entry.Span.Key
+	if r.resumeKey != nil {
+		resumeAfter := r.resumeKey.PrefixEnd()
+		if entry.Span.EndKey.Compare(resumeAfter) <= 0 {
+			return nil
+		}
+		if startKey.Compare(resumeAfter) < 0 {
+			startKey = resumeAfter
+		}
+	}
+
+	iters := make([]storage.SimpleMVCCIterator, 0, len(entry.Files))
+	defer func() {
+		for _, iter := range iters {
+			iter.Close()
+		}
+	}()
+	for _, file := range entry.Files {
+		store, err := r.execCfg.DistSQLSrv.ExternalStorage(ctx, file.Dir)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := store.Close(); err != nil {
+				log.Warningf(ctx, "close export storage failed %v", err)
+			}
+		}()
+		iter, err := storageccl.ExternalSSTReader(ctx, store, file.Path, r.encryption)
+		if err != nil {
+			return err
+		}
+		iters = append(iters, iter)
+	}
+	iter := storage.NewReadAsOfIterator(storage.MakeMultiIterator(iters), r.endTime)
+
+	endKey := storage.MVCCKey{Key: entry.Span.EndKey}
+	for iter.SeekGE(storage.MVCCKey{Key: startKey}); ; iter.NextKey() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok || !iter.UnsafeKey().Less(endKey) {
+			return nil
+		}
+		rowKey, err := keys.EnsureSafeSplitKey(iter.UnsafeKey().Key)
+		if err != nil {
+			return err
+		}
+		key, ok, err := r.kr.RewriteKey(append(roachpb.Key(nil), iter.UnsafeKey().Key...))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if !rowKey.Equal(r.lastRow) {
+			// Only decode whole rows, so the KVs are decoded once a batch of rows
+			// has been read and the next row starts.
+			if r.rows >= restoreRowsBatchSize {
+				if err := r.decode(ctx); err != nil {
+					return err
+				}
+			}
+			r.lastRow = append(r.lastRow[:0], rowKey...)
+			r.rows++
+		}
+		value := roachpb.Value{RawBytes: append([]byte(nil), iter.UnsafeValue()...)}
+		// Rewriting the key means the checksum needs to be updated.
+		value.ClearChecksum()
+		value.InitChecksum(key)
+		r.kvs = append(r.kvs, roachpb.KeyValue{Key: key, Value: value})
+	}
+}
+
+// decode decodes the rows which have been read, and buffers those satisfying
+// the predicate, which are committed once there are enough of them.
+func (r *rowRestorer) decode(ctx context.Context) error {
+	if len(r.kvs) == 0 {
+		return nil
+	}
+	if err := r.rf.StartScanFrom(ctx, &row.SpanKVFetcher{KVs: r.kvs}, false /* traceKV */); err != nil {
+		return err
+	}
+	r.evalCtx.PushIVarContainer(r.ivars)
+	defer r.evalCtx.PopIVarContainer()
+	for {
+		datums, err := r.rf.NextRowDecoded(ctx)
+		if err != nil {
+			return err
+		}
+		if datums == nil {
+			break
+		}
+		r.ivars.CurSourceRow = datums
+		res, err := eval.Expr(r.evalCtx, r.filter)
+		if err != nil {
+			return err
+		}
+		if res != tree.DBoolTrue {
+			continue
+		}
+		r.w.pending = append(r.w.pending, append(tree.Datums(nil), datums...))
+	}
+	r.kvs, r.rows = nil, 0
+	if len(r.w.pending) < restoreRowsBatchSize {
+		return nil
+	}
+	return r.commit(ctx)
+}
+
+// commit writes the buffered rows, and records the last row read in the
+// progress of the job, in one transaction.
+func (r *rowRestorer) commit(ctx context.Context) error {
+	resumeKey := append(roachpb.Key(nil), r.lastRow...)
+	if err := r.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		if err := r.w.write(ctx, txn); err != nil {
+			return err
+		}
+		return r.job.Update(ctx, txn, func(
+			txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+		) error {
+			if err := md.CheckRunningOrReverting(); err != nil {
+				return err
+			}
+			progress := md.Progress.GetRestoreRows()
+			progress.ResumeKey = resumeKey
+			progress.Rows += int64(len(r.w.pending))
+			md.Progress.Progress = &jobspb.Progress_FractionCompleted{
+				FractionCompleted: r.fractionCompleted,
+			}
+			ju.UpdateProgress(md.Progress)
+			return nil
+		})
+	}); err != nil {
+		return err
+	}
+	r.w.rows += int64(len(r.w.pending))
+	r.w.pending = r.w.pending[:0]
+	return r.execCfg.JobRegistry.CheckPausepoint("restorerows.after_commit")
+}
+
+// flush decodes and commits the remaining rows.
+func (r *rowRestorer) flush(ctx context.Context) error {
+	if err := r.decode(ctx); err != nil {
+		return err
+	}
+	if len(r.w.pending) == 0 {
+		return nil
+	}
+	return r.commit(ctx)
+}
+
+func init() {
+	jobs.RegisterConstructor(
+		jobspb.TypeRestoreRows,
+		func(job *jobs.Job, _ *cluster.Settings) jobs.Resumer {
+			return &restoreRowsResumer{
+				job: job,
+			}
+		},
+	)
+}
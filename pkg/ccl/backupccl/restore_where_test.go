@@ -0,0 +1,232 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// restoreWhere runs a RESTORE TABLE ... WHERE ... INTO statement and returns
+// the number of rows restored by its job.
+func restoreWhere(t *testing.T, sqlDB *sqlutils.SQLRunner, query string, args ...interface{}) int {
+	t.Helper()
+	var jobID jobspb.JobID
+	var status string
+	var fractionCompleted float64
+	var rows, indexEntries, bytes int
+	sqlDB.QueryRow(t, query, args...).Scan(
+		&jobID, &status, &fractionCompleted, &rows, &indexEntries, &bytes,
+	)
+	require.Equal(t, string(jobs.StatusSucceeded), status)
+	return rows
+}
+
+// numCommits returns the number of transactions which wrote the rows of the
+// given table.
+func numCommits(t *testing.T, sqlDB *sqlutils.SQLRunner, table string) int {
+	t.Helper()
+	var n int
+	sqlDB.QueryRow(t, `SELECT count(DISTINCT crdb_internal_mvcc_timestamp) FROM `+table).Scan(&n)
+	return n
+}
+
+// TestRestoreWhere verifies that RESTORE TABLE ... WHERE ... INTO restores
+// the rows satisfying the predicate from a chain of backups into a new table,
+// and upserts them into the live table.
+func TestRestoreWhere(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tc, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, 0, InitManualReplication)
+	defer cleanupFn()
+	registry := tc.Server(0).JobRegistry().(*jobs.Registry)
+
+	// waitForJob waits for the given job to reach the given status.
+	waitForJob := func(jobID jobspb.JobID, expected jobs.Status) {
+		t.Helper()
+		testutils.SucceedsSoon(t, func() error {
+			registry.TestingNudgeAdoptionQueue()
+			var status, jobErr string
+			sqlDB.QueryRow(t, `SELECT status, error FROM [SHOW JOB $1]`, jobID).Scan(&status, &jobErr)
+			if status == string(jobs.StatusFailed) {
+				t.Fatalf("job %d failed: %s", jobID, jobErr)
+			}
+			if status != string(expected) {
+				return errors.Newf("job %d is %s", jobID, status)
+			}
+			return nil
+		})
+	}
+
+	const collection = `nodelocal://0/where`
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (
+	k INT PRIMARY KEY,
+	v STRING,
+	w INT AS (k * 10) STORED,
+	FAMILY (k, v),
+	FAMILY (w)
+)`)
+	sqlDB.Exec(t, `INSERT INTO d.t SELECT i, 'v' || i::STRING FROM generate_series(1, 500) AS g(i)`)
+	var fullEndTime string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&fullEndTime)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO $1 AS OF SYSTEM TIME `+fullEndTime, collection)
+	sqlDB.Exec(t, `UPDATE d.t SET v = 'updated' WHERE k = 7`)
+	sqlDB.Exec(t, `DELETE FROM d.t WHERE k = 8`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO LATEST IN $1`, collection)
+
+	// A bad migration deletes and modifies rows.
+	sqlDB.Exec(t, `DELETE FROM d.t WHERE k % 2 = 0`)
+	sqlDB.Exec(t, `UPDATE d.t SET v = 'bad' WHERE k < 10`)
+
+	t.Run("into new table", func(t *testing.T) {
+		require.Equal(t, 149, restoreWhere(t, sqlDB,
+			`RESTORE TABLE d.t FROM LATEST IN $1 WHERE k % 2 = 0 AND k <= 300 INTO d.restored`, collection))
+		sqlDB.CheckQueryResults(t, `SELECT count(*), min(k), max(k) FROM d.restored`,
+			[][]string{{"149", "2", "300"}})
+		sqlDB.CheckQueryResults(t, `SELECT k, v, w FROM d.restored WHERE k IN (2, 8, 10)`,
+			[][]string{{"2", "v2", "20"}, {"10", "v10", "100"}})
+		var create, restoredCreate string
+		sqlDB.QueryRow(t, `SELECT create_statement FROM [SHOW CREATE TABLE d.t]`).Scan(&create)
+		sqlDB.QueryRow(t, `SELECT create_statement FROM [SHOW CREATE TABLE d.restored]`).Scan(&restoredCreate)
+		require.Equal(t, strings.Replace(create, "TABLE public.t", "TABLE public.restored", 1), restoredCreate)
+	})
+
+	t.Run("upsert into live table", func(t *testing.T) {
+		require.Equal(t, 8, restoreWhere(t, sqlDB,
+			`RESTORE TABLE d.t FROM LATEST IN $1 WHERE d.t.k < 10 UPSERT INTO d.t`, collection))
+		sqlDB.CheckQueryResults(t, `SELECT k, v, w FROM d.t WHERE k <= 10 ORDER BY k`,
+			[][]string{
+				{"1", "v1", "10"}, {"2", "v2", "20"}, {"3", "v3", "30"}, {"4", "v4", "40"},
+				{"5", "v5", "50"}, {"6", "v6", "60"}, {"7", "updated", "70"}, {"9", "v9", "90"},
+			})
+	})
+
+	t.Run("as of the full backup", func(t *testing.T) {
+		sqlDB.Exec(t, `DELETE FROM d.t WHERE k IN (7, 8)`)
+		require.Equal(t, 2, restoreWhere(t, sqlDB,
+			`RESTORE TABLE d.t FROM LATEST IN $1 AS OF SYSTEM TIME `+fullEndTime+
+				` WHERE k IN (7, 8) UPSERT INTO d.t`, collection))
+		sqlDB.CheckQueryResults(t, `SELECT k, v FROM d.t WHERE k IN (7, 8)`,
+			[][]string{{"7", "v7"}, {"8", "v8"}})
+	})
+
+	t.Run("in a transaction", func(t *testing.T) {
+		sqlDB.Exec(t, `BEGIN`)
+		sqlDB.ExpectErr(t, `RESTORE cannot be used inside a multi-statement transaction without DETACHED option`,
+			`RESTORE TABLE d.t FROM LATEST IN $1 WHERE k = 500 INTO d.txn`, collection)
+		sqlDB.Exec(t, `ROLLBACK`)
+
+		// Neither the table nor the job of a detached RESTORE ... WHERE are
+		// created if the transaction is rolled back.
+		sqlDB.Exec(t, `BEGIN`)
+		var jobID jobspb.JobID
+		sqlDB.QueryRow(t, `RESTORE TABLE d.t FROM LATEST IN $1 WHERE k = 500 INTO d.txn WITH detached`,
+			collection).Scan(&jobID)
+		sqlDB.Exec(t, `ROLLBACK`)
+		sqlDB.CheckQueryResults(t, `SELECT count(*) FROM [SHOW TABLES FROM d] WHERE table_name = 'txn'`,
+			[][]string{{"0"}})
+		var numJobs int
+		sqlDB.QueryRow(t, `SELECT count(*) FROM [SHOW JOBS] WHERE job_id = $1`, jobID).Scan(&numJobs)
+		require.Zero(t, numJobs)
+
+		sqlDB.Exec(t, `BEGIN`)
+		sqlDB.QueryRow(t, `RESTORE TABLE d.t FROM LATEST IN $1 WHERE k = 500 INTO d.txn WITH detached`,
+			collection).Scan(&jobID)
+		sqlDB.Exec(t, `COMMIT`)
+		waitForJob(jobID, jobs.StatusSucceeded)
+		sqlDB.CheckQueryResults(t, `SELECT k, v FROM d.txn`, [][]string{{"500", "v500"}})
+	})
+
+	t.Run("resumed", func(t *testing.T) {
+		// Pause the job after it commits its first batch of rows, then check that
+		// the resumed job restores the remaining rows, and none of them twice.
+		sqlDB.Exec(t, `SET CLUSTER SETTING jobs.debug.pausepoints = 'restorerows.after_commit'`)
+		var jobID jobspb.JobID
+		sqlDB.QueryRow(t, `RESTORE TABLE d.t FROM LATEST IN $1 WHERE k % 2 = 1 INTO d.resumed WITH detached`,
+			collection).Scan(&jobID)
+		waitForJob(jobID, jobs.StatusPaused)
+		var committed int
+		sqlDB.QueryRow(t, `SELECT count(*) FROM d.resumed`).Scan(&committed)
+		require.Greater(t, committed, 0)
+		require.Less(t, committed, 250)
+
+		sqlDB.Exec(t, `RESET CLUSTER SETTING jobs.debug.pausepoints`)
+		sqlDB.Exec(t, `RESUME JOB $1`, jobID)
+		waitForJob(jobID, jobs.StatusSucceeded)
+		sqlDB.CheckQueryResults(t, `SELECT count(*), count(DISTINCT k), min(k), max(k) FROM d.resumed`,
+			[][]string{{"250", "250", "1", "499"}})
+		sqlDB.CheckQueryResults(t, `SELECT k, v FROM d.resumed WHERE k IN (7, 9)`,
+			[][]string{{"7", "updated"}, {"9", "v9"}})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		sqlDB.ExpectErr(t, `column "nope" does not exist`,
+			`RESTORE TABLE d.t FROM LATEST IN $1 WHERE nope = 1 INTO d.t2`, collection)
+		sqlDB.ExpectErr(t, `expected RESTORE ... WHERE expression to have type bool`,
+			`RESTORE TABLE d.t FROM LATEST IN $1 WHERE k INTO d.t2`, collection)
+		sqlDB.ExpectErr(t, `relation "d.public.restored" already exists`,
+			`RESTORE TABLE d.t FROM LATEST IN $1 WHERE k = 1 INTO d.restored`, collection)
+		sqlDB.ExpectErr(t, `relation "d.missing" does not exist`,
+			`RESTORE TABLE d.t FROM LATEST IN $1 WHERE k = 1 UPSERT INTO d.missing`, collection)
+		sqlDB.ExpectErr(t, `option "skip_missing_foreign_keys" cannot be used with RESTORE ... WHERE`,
+			`RESTORE TABLE d.t FROM LATEST IN $1 WHERE k = 1 INTO d.t2 WITH skip_missing_foreign_keys`,
+			collection)
+		sqlDB.ExpectErr(t, `can only be used to restore a single table`,
+			`RESTORE TABLE d.t, d.restored FROM LATEST IN $1 WHERE k = 1 INTO d.t2`, collection)
+	})
+}
+
+// TestRestoreWhereDroppedColumns verifies that RESTORE TABLE ... WHERE ...
+// INTO ... WITH dropped_columns restores the data of the columns dropped from
+// a table since the backup into a side table.
+func TestRestoreWhereDroppedColumns(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, 0, InitManualReplication)
+	defer cleanupFn()
+
+	const collection = `nodelocal://0/dropped`
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (k INT PRIMARY KEY, a INT, b STRING NOT NULL, c INT AS (a + 1) STORED)`)
+	sqlDB.Exec(t, `INSERT INTO d.t (k, a, b) SELECT i, i * 2, 'b' || i::STRING FROM generate_series(1, 300) AS g(i)`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO $1 WITH revision_history`, collection)
+	var beforeDrop string
+	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&beforeDrop)
+	sqlDB.Exec(t, `ALTER TABLE d.t DROP COLUMN b, DROP COLUMN c`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO LATEST IN $1 WITH revision_history`, collection)
+
+	require.Equal(t, 290, restoreWhere(t, sqlDB,
+		`RESTORE TABLE d.t FROM LATEST IN $1 AS OF SYSTEM TIME `+beforeDrop+
+			` WHERE k > 10 INTO d.t_dropped WITH dropped_columns`, collection))
+	sqlDB.CheckQueryResults(t, `SELECT k, b, c FROM d.t_dropped WHERE k IN (11, 300)`,
+		[][]string{{"11", "b11", "23"}, {"300", "b300", "601"}})
+	// The rows are committed in batches rather than in one transaction.
+	require.Greater(t, numCommits(t, sqlDB, "d.t_dropped"), 1)
+	sqlDB.CheckQueryResults(t, `SELECT column_name FROM [SHOW COLUMNS FROM d.t_dropped]`,
+		[][]string{{"k"}, {"b"}, {"c"}})
+
+	sqlDB.ExpectErr(t, `none of the columns of table d.public.t in the backup have been dropped`,
+		`RESTORE TABLE d.t FROM LATEST IN $1 WHERE true INTO d.t_none WITH dropped_columns`, collection)
+	sqlDB.ExpectErr(t, `option "dropped_columns" cannot be used with RESTORE ... WHERE ... UPSERT INTO`,
+		`RESTORE TABLE d.t FROM LATEST IN $1 WHERE true UPSERT INTO d.t WITH dropped_columns`, collection)
+	sqlDB.ExpectErr(t, `option "dropped_columns" can only be used with RESTORE ... WHERE ... INTO`,
+		`RESTORE TABLE d.t FROM LATEST IN $1 WITH dropped_columns`, collection)
+}
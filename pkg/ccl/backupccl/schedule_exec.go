@@ -274,6 +274,9 @@ func (e *scheduledBackupExecutor) GetCreateScheduleStatement(
 			Value: tree.NewDString(wait),
 		},
 	}
+	if args.VerifyBackups {
+		scheduleOptions = append(scheduleOptions, tree.KVOption{Key: optVerifyBackups})
+	}
 
 	var destinations []string
 	for i := range backupNode.To {
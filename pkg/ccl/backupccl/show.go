@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuputils"
 	"github.com/cockroachdb/cockroach/pkg/ccl/storageccl"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -225,6 +226,7 @@ func showBackupPlanHook(
 		backupOptDebugMetadataSST: sql.KVStringOptRequireNoValue,
 		backupOptEncDir:           sql.KVStringOptRequireValue,
 		backupOptCheckFiles:       sql.KVStringOptRequireNoValue,
+		backupOptVerify:           sql.KVStringOptRequireNoValue,
 	}
 	optsFn, err := p.TypeAsStringOpts(ctx, backup.Options, expected)
 	if err != nil {
@@ -235,6 +237,11 @@ func showBackupPlanHook(
 		return nil, nil, nil, false, err
 	}
 
+	_, verify := opts[backupOptVerify]
+	if verify && (backup.Details != tree.BackupDefaultDetails || hasShowOnlyOption(opts)) {
+		return nil, nil, nil, false, errors.Newf("the %s option cannot be used to show a backup", backupOptVerify)
+	}
+
 	var infoReader backupInfoReader
 	if _, dumpSST := opts[backupOptDebugMetadataSST]; dumpSST {
 		infoReader = metadataSSTInfoReader{}
@@ -249,6 +256,8 @@ func showBackupPlanHook(
 			shower = backupShowerFileSetup(backup.InCollection)
 		case tree.BackupSchemaDetails:
 			shower = backupShowerDefault(ctx, p, true, opts)
+		case tree.BackupSchemaHistoryDetails:
+			shower = backupShowerSchemaHistory
 		default:
 			shower = backupShowerDefault(ctx, p, false, opts)
 		}
@@ -405,6 +414,11 @@ you must pass the 'encryption_info_dir' parameter that points to the directory o
 				return err
 			}
 		}
+		if verify {
+			return startBackupVerificationJob(
+				ctx, p, fullyResolvedDest, fullyResolvedIncrementalsDirectory, encryption, resultsCh)
+		}
+
 		mem := p.ExecCfg().RootMemoryMonitor.MakeBoundAccount()
 		defer mem.Close(ctx)
 
@@ -481,9 +495,59 @@ you must pass the 'encryption_info_dir' parameter that points to the directory o
 		return nil
 	}
 
+	if verify {
+		return fn, jobs.DetachedJobExecutionResultHeader, nil, false, nil
+	}
 	return fn, infoReader.header(), nil, false, nil
 }
 
+// hasShowOnlyOption returns whether the SHOW BACKUP options include any option
+// which only applies to showing a backup.
+func hasShowOnlyOption(opts map[string]string) bool {
+	for _, opt := range []string{
+		backupOptWithPrivileges, backupOptAsJSON, backupOptWithDebugIDs,
+		backupOptDebugMetadataSST, backupOptCheckFiles,
+	} {
+		if _, ok := opts[opt]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// startBackupVerificationJob starts a job verifying the backup chain of the
+// given full backup, and returns its ID.
+func startBackupVerificationJob(
+	ctx context.Context,
+	p sql.PlanHookState,
+	fullBackupURIs []string,
+	incrementalDirectories []string,
+	encryption *jobspb.BackupEncryptionOptions,
+	resultsCh chan<- tree.Datums,
+) error {
+	description, err := backupVerificationDescription(fullBackupURIs[0], hlc.Timestamp{})
+	if err != nil {
+		return err
+	}
+	record := jobs.Record{
+		Description: description,
+		Username:    p.User(),
+		Details: jobspb.BackupVerificationDetails{
+			FullBackupURIs:         fullBackupURIs,
+			IncrementalDirectories: incrementalDirectories,
+			EncryptionOptions:      encryption,
+		},
+		Progress: jobspb.BackupVerificationProgress{},
+	}
+	jobID := p.ExecCfg().JobRegistry.MakeJobID()
+	if _, err := p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(ctx, record, jobID, p.Txn()); err != nil {
+		return err
+	}
+	telemetry.Count("show-backup.verify")
+	resultsCh <- tree.Datums{tree.NewDInt(tree.DInt(jobID))}
+	return nil
+}
+
 // checkBackupFiles validates that each SST is in its expected storage location
 func checkBackupFiles(
 	ctx context.Context,
@@ -667,7 +731,7 @@ func backupShowerDefault(
 				schemaIDToName := make(map[descpb.ID]string)
 				schemaIDToName[keys.PublicSchemaIDForBackup] = catconstants.PublicSchemaName
 				for i := range manifest.Descriptors {
-					_, db, _, schema := descpb.FromDescriptor(&manifest.Descriptors[i])
+					_, db, _, schema, _ := descpb.FromDescriptor(&manifest.Descriptors[i])
 					if db != nil {
 						if _, ok := dbIDToName[db.ID]; !ok {
 							dbIDToName[db.ID] = db.Name
@@ -733,6 +797,12 @@ func backupShowerDefault(
 						dbID = desc.GetParentID()
 						parentSchemaName = schemaIDToName[desc.GetParentSchemaID()]
 						parentSchemaID = desc.GetParentSchemaID()
+					case catalog.FunctionDescriptor:
+						descriptorType = "function"
+						dbName = dbIDToName[desc.GetParentID()]
+						dbID = desc.GetParentID()
+						parentSchemaName = schemaIDToName[desc.GetParentSchemaID()]
+						parentSchemaID = desc.GetParentSchemaID()
 					case catalog.TableDescriptor:
 						descriptorType = "table"
 						dbName = dbIDToName[desc.GetParentID()]
@@ -938,6 +1008,8 @@ func showPrivileges(descriptor *descpb.Descriptor) string {
 		objectType = privilege.Type
 	case catalog.Schema:
 		objectType = privilege.Schema
+	case catalog.Function:
+		objectType = privilege.Function
 	default:
 		return ""
 	}
@@ -0,0 +1,281 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package backupccl
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backuppb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// The changes to the schema of a table reported by SHOW BACKUP SCHEMA HISTORY.
+const (
+	schemaChangeInitial         = "initial"
+	schemaChangeCreateTable     = "create table"
+	schemaChangeDropTable       = "drop table"
+	schemaChangeRenameTable     = "rename table"
+	schemaChangeAddColumn       = "add column"
+	schemaChangeDropColumn      = "drop column"
+	schemaChangeRenameColumn    = "rename column"
+	schemaChangeAddIndex        = "add index"
+	schemaChangeDropIndex       = "drop index"
+	schemaChangeRenameIndex     = "rename index"
+	schemaChangeAlterPrimaryKey = "alter primary key"
+)
+
+// tableRevision is the state of a table as of a time covered by a backup. A
+// nil table means the table did not exist, or was dropped, as of that time.
+type tableRevision struct {
+	time  hlc.Timestamp
+	table catalog.TableDescriptor
+}
+
+// schemaChange is a change to the schema of a table between two of its
+// revisions. The table is the revision after the change, or the one before it
+// if the table was dropped.
+type schemaChange struct {
+	time         hlc.Timestamp
+	id           descpb.ID
+	table        catalog.TableDescriptor
+	dropped      bool
+	change       string
+	elementName  string
+	previousName string
+}
+
+// backupShowerSchemaHistory lists the changes to the schemas of the tables in
+// a chain of backups. Backups taken with revision_history capture every
+// revision of the descriptors of the tables; otherwise, only the state of the
+// tables at the end time of each backup is known.
+var backupShowerSchemaHistory = backupShower{
+	header: colinfo.ResultColumns{
+		{Name: "database_name", Typ: types.String},
+		{Name: "parent_schema_name", Typ: types.String},
+		{Name: "object_name", Typ: types.String},
+		{Name: "object_id", Typ: types.Int},
+		{Name: "revision_time", Typ: types.Decimal},
+		{Name: "descriptor_version", Typ: types.Int},
+		{Name: "change", Typ: types.String},
+		{Name: "element_name", Typ: types.String},
+		{Name: "previous_name", Typ: types.String},
+	},
+
+	fn: func(info backupInfo) ([]tree.Datums, error) {
+		dbNames := make(map[descpb.ID]string)
+		schemaNames := map[descpb.ID]string{keys.PublicSchemaIDForBackup: catconstants.PublicSchemaName}
+		addNames := func(desc *descpb.Descriptor) {
+			if desc == nil {
+				return
+			}
+			_, db, _, schema, _ := descpb.FromDescriptor(desc)
+			if db != nil {
+				dbNames[db.ID] = db.Name
+			} else if schema != nil {
+				schemaNames[schema.ID] = schema.Name
+			}
+		}
+		for _, manifest := range info.manifests {
+			for i := range manifest.Descriptors {
+				addNames(&manifest.Descriptors[i])
+			}
+			for _, rev := range manifest.DescriptorChanges {
+				addNames(rev.Desc)
+			}
+		}
+
+		revisions := tableRevisionsFromBackups(info.manifests)
+		ids := make([]descpb.ID, 0, len(revisions))
+		for id := range revisions {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		var changes []schemaChange
+		for _, id := range ids {
+			changes = append(changes, diffTableRevisions(id, revisions[id])...)
+		}
+		sort.SliceStable(changes, func(i, j int) bool {
+			return changes[i].time.Less(changes[j].time)
+		})
+
+		rows := make([]tree.Datums, 0, len(changes))
+		for _, c := range changes {
+			version := tree.DNull
+			if !c.dropped {
+				version = tree.NewDInt(tree.DInt(c.table.GetVersion()))
+			}
+			rows = append(rows, tree.Datums{
+				nullIfEmpty(dbNames[c.table.GetParentID()]),
+				nullIfEmpty(schemaNames[c.table.GetParentSchemaID()]),
+				tree.NewDString(c.table.GetName()),
+				tree.NewDInt(tree.DInt(c.id)),
+				eval.TimestampToDecimalDatum(c.time),
+				version,
+				tree.NewDString(c.change),
+				nullIfEmpty(c.elementName),
+				nullIfEmpty(c.previousName),
+			})
+		}
+		return rows, nil
+	},
+}
+
+// tableRevisionsFromBackups returns the revisions of each table in the given
+// chain of backups, ordered by time.
+func tableRevisionsFromBackups(
+	manifests []backuppb.BackupManifest,
+) map[descpb.ID][]tableRevision {
+	revisions := make(map[descpb.ID][]tableRevision)
+	addRevision := func(id descpb.ID, time hlc.Timestamp, desc *descpb.Descriptor) {
+		var table catalog.TableDescriptor
+		if desc != nil {
+			if tbl, _, _, _, _ := descpb.FromDescriptor(desc); tbl != nil {
+				table = tabledesc.NewBuilder(tbl).BuildImmutableTable()
+			} else {
+				return
+			}
+		}
+		if table != nil && table.Dropped() {
+			table = nil
+		}
+		revs := revisions[id]
+		if table == nil && (len(revs) == 0 || revs[len(revs)-1].table == nil) {
+			// There is nothing to drop.
+			return
+		}
+		revisions[id] = append(revs, tableRevision{time: time, table: table})
+	}
+
+	for _, manifest := range manifests {
+		if len(manifest.DescriptorChanges) > 0 {
+			// The changes of each backup are ordered by time, and those of an
+			// incremental backup start with the state of the descriptors as of
+			// its start time, which the diff of the revisions ignores.
+			for _, rev := range manifest.DescriptorChanges {
+				addRevision(rev.ID, rev.Time, rev.Desc)
+			}
+			continue
+		}
+		// Without the revisions of the descriptors, a table which is not in a
+		// backup was dropped by its end time.
+		inBackup := make(map[descpb.ID]struct{})
+		for i := range manifest.Descriptors {
+			desc := &manifest.Descriptors[i]
+			if id := descpb.GetDescriptorID(desc); id != descpb.InvalidID {
+				inBackup[id] = struct{}{}
+				addRevision(id, manifest.EndTime, desc)
+			}
+		}
+		for id := range revisions {
+			if _, ok := inBackup[id]; !ok {
+				addRevision(id, manifest.EndTime, nil /* desc */)
+			}
+		}
+	}
+	return revisions
+}
+
+// diffTableRevisions returns the changes to the schema of a table between
+// each of its consecutive revisions: tables which are created, dropped or
+// renamed, and public columns and indexes which are added, dropped or
+// renamed.
+func diffTableRevisions(id descpb.ID, revisions []tableRevision) []schemaChange {
+	var changes []schemaChange
+	var prev catalog.TableDescriptor
+	for _, rev := range revisions {
+		cur := rev.table
+		add := func(change, elementName, previousName string) {
+			c := schemaChange{
+				time: rev.time, id: id, table: cur, change: change,
+				elementName: elementName, previousName: previousName,
+			}
+			if cur == nil {
+				c.table, c.dropped = prev, true
+			}
+			changes = append(changes, c)
+		}
+		switch {
+		case cur == nil:
+			add(schemaChangeDropTable, prev.GetName(), "" /* previousName */)
+		case prev == nil:
+			// The first revision of a table in a backup is its creation only if it
+			// is the first version of the table; otherwise the table was created
+			// before the time covered by the backup, or the revision has been
+			// garbage collected.
+			change := schemaChangeInitial
+			if cur.GetVersion() == 1 {
+				change = schemaChangeCreateTable
+			}
+			add(change, cur.GetName(), "" /* previousName */)
+		default:
+			if cur.GetName() != prev.GetName() {
+				add(schemaChangeRenameTable, cur.GetName(), prev.GetName())
+			}
+			for _, col := range prev.PublicColumns() {
+				if curCol, err := cur.FindColumnWithID(col.GetID()); err != nil || !curCol.Public() {
+					add(schemaChangeDropColumn, col.GetName(), "" /* previousName */)
+				}
+			}
+			for _, col := range cur.PublicColumns() {
+				prevCol, err := prev.FindColumnWithID(col.GetID())
+				if err != nil || !prevCol.Public() {
+					add(schemaChangeAddColumn, col.GetName(), "" /* previousName */)
+				} else if col.GetName() != prevCol.GetName() {
+					add(schemaChangeRenameColumn, col.GetName(), prevCol.GetName())
+				}
+			}
+			// Adding and dropping columns rebuilds the primary index, so only a
+			// change of its key columns changes the primary key.
+			if !sameKeyColumns(cur.GetPrimaryIndex(), prev.GetPrimaryIndex()) {
+				add(schemaChangeAlterPrimaryKey, cur.GetPrimaryIndex().GetName(),
+					prev.GetPrimaryIndex().GetName())
+			}
+			for _, idx := range prev.PublicNonPrimaryIndexes() {
+				if curIdx, err := cur.FindIndexWithID(idx.GetID()); err != nil || !curIdx.Public() ||
+					curIdx.Primary() {
+					add(schemaChangeDropIndex, idx.GetName(), "" /* previousName */)
+				}
+			}
+			for _, idx := range cur.PublicNonPrimaryIndexes() {
+				prevIdx, err := prev.FindIndexWithID(idx.GetID())
+				if err != nil || !prevIdx.Public() || prevIdx.Primary() {
+					add(schemaChangeAddIndex, idx.GetName(), "" /* previousName */)
+				} else if idx.GetName() != prevIdx.GetName() {
+					add(schemaChangeRenameIndex, idx.GetName(), prevIdx.GetName())
+				}
+			}
+		}
+		prev = cur
+	}
+	return changes
+}
+
+// sameKeyColumns returns whether the given indexes have the same key columns,
+// in the same order and directions.
+func sameKeyColumns(a, b catalog.Index) bool {
+	if a.NumKeyColumns() != b.NumKeyColumns() {
+		return false
+	}
+	for i := 0; i < a.NumKeyColumns(); i++ {
+		if a.GetKeyColumnID(i) != b.GetKeyColumnID(i) ||
+			a.GetKeyColumnDirection(i) != b.GetKeyColumnDirection(i) {
+			return false
+		}
+	}
+	return true
+}
@@ -24,6 +24,8 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/ccl/backupccl/backupbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
@@ -36,6 +38,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -1005,3 +1009,117 @@ func TestShowBackupCheckFiles(t *testing.T) {
 		}
 	}
 }
+
+// TestShowBackupVerify verifies that SHOW BACKUP with the verify option starts
+// a job which verifies the backup chain and reports a corrupt backup file in
+// crdb_internal.backup_verifications.
+func TestShowBackupVerify(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	const numAccounts = 11
+
+	tc, sqlDB, tempDir, cleanupFn := backupRestoreTestSetup(t, singleNode, numAccounts,
+		InitManualReplication)
+	defer cleanupFn()
+	registry := tc.Server(0).JobRegistry().(*jobs.Registry)
+
+	const collection = `nodelocal://0/verify`
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO $1`, collection)
+	sqlDB.Exec(t, `INSERT INTO data.bank VALUES (1000, 1000, 'a')`)
+	sqlDB.Exec(t, `BACKUP DATABASE data INTO LATEST IN $1`, collection)
+
+	sqlDB.ExpectErr(t, "the verify option cannot be used to show a backup",
+		`SHOW BACKUP FROM LATEST IN $1 WITH verify, as_json`, collection)
+
+	verify := func(expectedStatus jobs.Status) (filesVerified, numProblems int, problems []string) {
+		t.Helper()
+		var jobID jobspb.JobID
+		sqlDB.QueryRow(t, `SHOW BACKUP FROM LATEST IN $1 WITH verify`, collection).Scan(&jobID)
+		testutils.SucceedsSoon(t, func() error {
+			registry.TestingNudgeAdoptionQueue()
+			var status string
+			sqlDB.QueryRow(t, `SELECT status, files_verified, num_problems, problems
+FROM crdb_internal.backup_verifications WHERE job_id = $1`, jobID).Scan(
+				&status, &filesVerified, &numProblems, pq.Array(&problems))
+			if jobs.Status(status) != expectedStatus {
+				return errors.Newf("job %d has status %s, expected %s", jobID, status, expectedStatus)
+			}
+			return nil
+		})
+		return filesVerified, numProblems, problems
+	}
+
+	filesVerified, numProblems, _ := verify(jobs.StatusSucceeded)
+	require.Equal(t, 0, numProblems)
+	require.Greater(t, filesVerified, 0)
+
+	// Corrupt a data file of the full backup.
+	files, err := filepath.Glob(filepath.Join(tempDir, "verify", "*", "*", "*", "data", "*.sst"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	data, err := ioutil.ReadFile(files[0])
+	require.NoError(t, err)
+	for i := len(data) / 4; i < len(data)/2; i++ {
+		data[i] ^= 0xff
+	}
+	require.NoError(t, ioutil.WriteFile(files[0], data, 0644))
+
+	corruptFilesVerified, numProblems, problems := verify(jobs.StatusFailed)
+	require.Equal(t, 1, numProblems)
+	require.Equal(t, filesVerified-1, corruptFilesVerified)
+	require.Len(t, problems, 1)
+	require.Contains(t, problems[0], filepath.Base(files[0]))
+}
+
+// TestShowBackupSchemaHistory verifies that SHOW BACKUP SCHEMA HISTORY lists
+// the changes to the schemas of the tables in a chain of backups, from the
+// revisions of their descriptors.
+func TestShowBackupSchemaHistory(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	_, sqlDB, _, cleanupFn := backupRestoreTestSetup(t, singleNode, 0, InitManualReplication)
+	defer cleanupFn()
+
+	const collection = `nodelocal://0/schema_history`
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	sqlDB.Exec(t, `CREATE TABLE d.t (k INT PRIMARY KEY, a INT, b STRING)`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO $1 WITH revision_history`, collection)
+	sqlDB.Exec(t, `ALTER TABLE d.t ADD COLUMN c INT`)
+	sqlDB.Exec(t, `CREATE INDEX t_b_idx ON d.t (b)`)
+	sqlDB.Exec(t, `ALTER TABLE d.t DROP COLUMN a`)
+	sqlDB.Exec(t, `ALTER TABLE d.t RENAME COLUMN c TO c2`)
+	sqlDB.Exec(t, `CREATE TABLE d.v (x INT PRIMARY KEY)`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO LATEST IN $1 WITH revision_history`, collection)
+	sqlDB.Exec(t, `DROP INDEX d.t@t_b_idx`)
+	sqlDB.Exec(t, `ALTER TABLE d.t RENAME TO d.u`)
+	sqlDB.Exec(t, `DROP TABLE d.v`)
+	sqlDB.Exec(t, `BACKUP DATABASE d INTO LATEST IN $1 WITH revision_history`, collection)
+
+	const query = `SELECT database_name, parent_schema_name, object_name, change, element_name, previous_name
+FROM [SHOW BACKUP SCHEMA HISTORY FROM LATEST IN $1]`
+	expected := [][]string{
+		{"d", "public", "t", "create table", "t", "NULL"},
+		{"d", "public", "t", "add column", "c", "NULL"},
+		{"d", "public", "t", "add index", "t_b_idx", "NULL"},
+		{"d", "public", "t", "drop column", "a", "NULL"},
+		{"d", "public", "t", "rename column", "c2", "c"},
+		{"d", "public", "v", "create table", "v", "NULL"},
+		{"d", "public", "t", "drop index", "t_b_idx", "NULL"},
+		{"d", "public", "u", "rename table", "u", "t"},
+		{"d", "public", "v", "drop table", "v", "NULL"},
+	}
+	require.Equal(t, expected, sqlDB.QueryStr(t, query, collection))
+
+	// Without revision history, the changes are only known as of the end time
+	// of each backup.
+	const noRevisions = `nodelocal://0/schema_history_no_revisions`
+	sqlDB.Exec(t, `CREATE TABLE d.w (k INT PRIMARY KEY, a INT)`)
+	sqlDB.Exec(t, `BACKUP TABLE d.w INTO $1`, noRevisions)
+	sqlDB.Exec(t, `ALTER TABLE d.w DROP COLUMN a`)
+	sqlDB.Exec(t, `BACKUP TABLE d.w INTO LATEST IN $1`, noRevisions)
+	require.Equal(t, [][]string{
+		{"d", "public", "w", "create table", "w", "NULL"},
+		{"d", "public", "w", "drop column", "a", "NULL"},
+	}, sqlDB.QueryStr(t, query, noRevisions))
+}
@@ -285,6 +285,48 @@ func TestAlterChangefeedUnsetDiffOption(t *testing.T) {
 	cdcTest(t, testFn, feedTestForceSink("kafka"))
 }
 
+func TestAlterChangefeedSetQuotaOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+
+		testFeed := feed(t, f, `CREATE CHANGEFEED FOR foo WITH throughput_limit = '1MiB'`)
+		defer closeFeed(t, testFeed)
+
+		feed, ok := testFeed.(cdctest.EnterpriseTestFeed)
+		require.True(t, ok)
+
+		sqlDB.Exec(t, `PAUSE JOB $1`, feed.JobID())
+		waitForJobStatus(sqlDB, t, feed.JobID(), `paused`)
+
+		sqlDB.Exec(t, fmt.Sprintf(
+			`ALTER CHANGEFEED %d SET memory_quota = '32MiB', flush_weight = '5' UNSET throughput_limit`,
+			feed.JobID()))
+
+		sqlDB.Exec(t, fmt.Sprintf(`RESUME JOB %d`, feed.JobID()))
+		waitForJobStatus(sqlDB, t, feed.JobID(), `running`)
+
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		metrics := registry.MetricsStruct().Changefeed.(*Metrics).QuotaMetrics
+		testutils.SucceedsSoon(t, func() error {
+			if v := metrics.MemoryLimit.Value(); v != 32<<20 {
+				return errors.Newf("expected memory quota of 32MiB, found %d", v)
+			}
+			return nil
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+		assertPayloads(t, testFeed, []string{
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
 func TestAlterChangefeedErrors(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -357,6 +399,11 @@ func TestAlterChangefeedErrors(t *testing.T) {
 			fmt.Sprintf(`ALTER CHANGEFEED %d UNSET end_time`, feed.JobID()),
 		)
 
+		sqlDB.ExpectErr(t,
+			`memory_quota must be a positive number of bytes, found "0"`,
+			fmt.Sprintf(`ALTER CHANGEFEED %d SET memory_quota = '0'`, feed.JobID()),
+		)
+
 		sqlDB.ExpectErr(t,
 			`cannot unset option "sink"`,
 			fmt.Sprintf(`ALTER CHANGEFEED %d UNSET sink`, feed.JobID()),
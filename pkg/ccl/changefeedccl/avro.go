@@ -168,6 +168,9 @@ type avroMetadata map[string]interface{}
 type avroEnvelopeOpts struct {
 	beforeField, afterField     bool
 	updatedField, resolvedField bool
+	// debeziumFields adds the source, op and ts_ms fields of Debezium change
+	// events.
+	debeziumFields bool
 }
 
 // avroEnvelopeRecord is an `avroRecord` that wraps a changed SQL row and some
@@ -177,6 +180,7 @@ type avroEnvelopeRecord struct {
 
 	opts          avroEnvelopeOpts
 	before, after *avroDataRecord
+	source        *avroRecord
 }
 
 // typeToAvroSchema converts a database type to an avro field
@@ -908,6 +912,26 @@ func envelopeToAvroSchema(
 		}
 		schema.Fields = append(schema.Fields, resolvedField)
 	}
+	if opts.debeziumFields {
+		schema.source = debeziumSourceAvroSchema(namespace)
+		schema.Fields = append(schema.Fields,
+			&avroSchemaField{
+				SchemaType: []avroSchemaType{avroSchemaNull, schema.source},
+				Name:       `source`,
+				Default:    nil,
+			},
+			&avroSchemaField{
+				SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaString},
+				Name:       `op`,
+				Default:    nil,
+			},
+			&avroSchemaField{
+				SchemaType: []avroSchemaType{avroSchemaNull, avroSchemaLong},
+				Name:       `ts_ms`,
+				Default:    nil,
+			},
+		)
+	}
 
 	schemaJSON, err := json.Marshal(schema)
 	if err != nil {
@@ -972,6 +996,36 @@ func (r *avroEnvelopeRecord) BinaryFromRow(
 			native[`resolved`] = goavro.Union(avroUnionKey(avroSchemaString), ts.AsOfSystemTime())
 		}
 	}
+	if r.opts.debeziumFields {
+		native[`source`] = nil
+		if s, ok := meta[`source`]; ok {
+			delete(meta, `source`)
+			source, ok := s.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf(`unknown metadata source type: %T`, s)
+			}
+			sourceNative := make(map[string]interface{}, len(r.source.Fields))
+			for _, field := range r.source.Fields {
+				sourceNative[field.Name] = nil
+				if v, ok := source[field.Name]; ok {
+					typ := field.SchemaType.([]avroSchemaType)[1]
+					sourceNative[field.Name] = goavro.Union(avroUnionKey(typ), v)
+				}
+			}
+			native[`source`] = goavro.Union(avroUnionKey(r.source), sourceNative)
+		}
+		for _, k := range []string{`op`, `ts_ms`} {
+			native[k] = nil
+			if v, ok := meta[k]; ok {
+				delete(meta, k)
+				typ := avroSchemaString
+				if k == `ts_ms` {
+					typ = avroSchemaLong
+				}
+				native[k] = goavro.Union(typ, v)
+			}
+		}
+	}
 	for k := range meta {
 		return nil, errors.AssertionFailedf(`unhandled meta key: %s`, k)
 	}
@@ -1030,3 +1084,33 @@ func ratToDecimal(rat big.Rat, scale int32) apd.Decimal {
 	dec := apd.NewWithBigInt(&coeff, -scale)
 	return *dec
 }
+
+// debeziumSourceAvroSchema returns the schema of the source metadata of
+// Debezium change events, as returned by debeziumSource.
+func debeziumSourceAvroSchema(namespace string) *avroRecord {
+	schema := &avroRecord{
+		Name:       `debezium_source`,
+		SchemaType: `record`,
+		Namespace:  namespace,
+	}
+	for _, f := range []struct {
+		name string
+		typ  avroSchemaType
+	}{
+		{`connector`, avroSchemaString},
+		{`cluster`, avroSchemaString},
+		{`db`, avroSchemaString},
+		{`schema`, avroSchemaString},
+		{`table`, avroSchemaString},
+		{`ts_ms`, avroSchemaLong},
+		{`snapshot`, avroSchemaString},
+		{`mvcc_timestamp`, avroSchemaString},
+	} {
+		schema.Fields = append(schema.Fields, &avroSchemaField{
+			SchemaType: []avroSchemaType{avroSchemaNull, f.typ},
+			Name:       f.name,
+			Default:    nil,
+		})
+	}
+	return schema
+}
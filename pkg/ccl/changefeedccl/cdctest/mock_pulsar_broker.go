@@ -0,0 +1,278 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdctest
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/pulsarclient"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/gogo/protobuf/proto"
+)
+
+// MockPulsarBroker is an in-process Pulsar broker which implements the part of
+// the protocol used by producers. It owns every topic, and keeps the messages
+// sent to it in memory.
+type MockPulsarBroker struct {
+	ln net.Listener
+	wg sync.WaitGroup
+
+	mu struct {
+		syncutil.Mutex
+		// partitions maps a partitioned topic to its number of partitions.
+		partitions map[string]int
+		// messages maps a topic, or a partition of a topic, to the messages
+		// sent to it.
+		messages map[string][]pulsarclient.Message
+		// batches maps a topic to the number of batches sent to it.
+		batches  map[string]int
+		sendHook func(topic string, msgs []pulsarclient.Message) error
+		conns    map[net.Conn]struct{}
+		closed   bool
+	}
+}
+
+// StartMockPulsarBroker starts a MockPulsarBroker which listens on a random local port.
+func StartMockPulsarBroker() (*MockPulsarBroker, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	b := &MockPulsarBroker{ln: ln}
+	b.mu.partitions = make(map[string]int)
+	b.mu.messages = make(map[string][]pulsarclient.Message)
+	b.mu.batches = make(map[string]int)
+	b.mu.conns = make(map[net.Conn]struct{})
+	b.wg.Add(1)
+	go b.accept()
+	return b, nil
+}
+
+// URL returns the service URL of the broker.
+func (b *MockPulsarBroker) URL() string {
+	return pulsarclient.SchemePulsar + "://" + b.ln.Addr().String()
+}
+
+// SetPartitions makes the topic a partitioned topic with the given number of
+// partitions.
+func (b *MockPulsarBroker) SetPartitions(topic string, partitions int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mu.partitions[topic] = partitions
+}
+
+// SetSendHook installs a function which is called with every batch before it
+// is acknowledged. If it returns an error, the batch is rejected. Receipts of
+// later batches on the same connection are held up while it blocks.
+func (b *MockPulsarBroker) SetSendHook(fn func(topic string, msgs []pulsarclient.Message) error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mu.sendHook = fn
+}
+
+// Messages returns the messages which were acknowledged by the broker for the
+// given topic, or partition of a topic, in the order they were sent.
+func (b *MockPulsarBroker) Messages(topic string) []pulsarclient.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]pulsarclient.Message(nil), b.mu.messages[topic]...)
+}
+
+// Topics returns every topic, or partition of a topic, which messages were
+// sent to.
+func (b *MockPulsarBroker) Topics() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	topics := make([]string, 0, len(b.mu.messages))
+	for topic := range b.mu.messages {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// NumBatches returns the number of batches which were acknowledged by the
+// broker for the given topic, or partition of a topic.
+func (b *MockPulsarBroker) NumBatches(topic string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.mu.batches[topic]
+}
+
+// CloseConnections closes every open connection to the broker.
+func (b *MockPulsarBroker) CloseConnections() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for c := range b.mu.conns {
+		_ = c.Close()
+	}
+}
+
+// Close stops the broker and waits for its goroutines to exit.
+func (b *MockPulsarBroker) Close() {
+	b.mu.Lock()
+	b.mu.closed = true
+	b.mu.Unlock()
+	_ = b.ln.Close()
+	b.CloseConnections()
+	b.wg.Wait()
+}
+
+func (b *MockPulsarBroker) accept() {
+	defer b.wg.Done()
+	for {
+		c, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		if b.mu.closed {
+			b.mu.Unlock()
+			_ = c.Close()
+			return
+		}
+		b.mu.conns[c] = struct{}{}
+		b.wg.Add(1)
+		b.mu.Unlock()
+		go b.serve(c)
+	}
+}
+
+// serve handles the commands sent on a connection until it is closed.
+func (b *MockPulsarBroker) serve(c net.Conn) {
+	defer b.wg.Done()
+	defer func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.mu.conns, c)
+		_ = c.Close()
+	}()
+
+	// producers maps the ID of every producer created on the connection to its
+	// topic.
+	producers := make(map[uint64]string)
+	r := bufio.NewReader(c)
+	write := func(cmd *pulsarclient.BaseCommand) bool {
+		buf, err := pulsarclient.EncodeCommand(cmd)
+		if err != nil {
+			return false
+		}
+		_, err = c.Write(buf)
+		return err == nil
+	}
+	for {
+		cmd, msgs, err := pulsarclient.ReadCommand(r)
+		if err != nil {
+			return
+		}
+		var resp *pulsarclient.BaseCommand
+		switch cmd.GetType() {
+		case pulsarclient.BaseCommand_CONNECT:
+			resp = &pulsarclient.BaseCommand{
+				Type: pulsarclient.BaseCommand_CONNECTED.Enum(),
+				Connected: &pulsarclient.CommandConnected{
+					ServerVersion:   "mock",
+					ProtocolVersion: cmd.Connect.ProtocolVersion,
+					MaxMessageSize:  proto.Int32(pulsarclient.DefaultMaxMessageSize),
+				},
+			}
+		case pulsarclient.BaseCommand_PING:
+			resp = &pulsarclient.BaseCommand{Type: pulsarclient.BaseCommand_PONG.Enum(), Pong: &pulsarclient.CommandPong{}}
+		case pulsarclient.BaseCommand_PARTITIONED_METADATA:
+			b.mu.Lock()
+			partitions := b.mu.partitions[cmd.PartitionMetadata.Topic]
+			b.mu.Unlock()
+			resp = &pulsarclient.BaseCommand{
+				Type: pulsarclient.BaseCommand_PARTITIONED_METADATA_RESPONSE.Enum(),
+				PartitionMetadataResponse: &pulsarclient.CommandPartitionedTopicMetadataResponse{
+					RequestID:  cmd.PartitionMetadata.RequestID,
+					Partitions: proto.Uint32(uint32(partitions)),
+					Response:   pulsarclient.CommandPartitionedTopicMetadataResponse_Success.Enum(),
+				},
+			}
+		case pulsarclient.BaseCommand_LOOKUP:
+			resp = &pulsarclient.BaseCommand{
+				Type: pulsarclient.BaseCommand_LOOKUP_RESPONSE.Enum(),
+				LookupTopicResponse: &pulsarclient.CommandLookupTopicResponse{
+					RequestID:        cmd.LookupTopic.RequestID,
+					BrokerServiceURL: proto.String(b.URL()),
+					Response:         pulsarclient.CommandLookupTopicResponse_Connect.Enum(),
+					Authoritative:    proto.Bool(true),
+				},
+			}
+		case pulsarclient.BaseCommand_PRODUCER:
+			producers[cmd.Producer.ProducerID] = cmd.Producer.Topic
+			resp = &pulsarclient.BaseCommand{
+				Type: pulsarclient.BaseCommand_PRODUCER_SUCCESS.Enum(),
+				ProducerSuccess: &pulsarclient.CommandProducerSuccess{
+					RequestID:      cmd.Producer.RequestID,
+					ProducerName:   fmt.Sprintf("mock-%d", cmd.Producer.ProducerID),
+					LastSequenceID: proto.Int64(-1),
+				},
+			}
+		case pulsarclient.BaseCommand_CLOSE_PRODUCER:
+			delete(producers, cmd.CloseProducer.ProducerID)
+			resp = &pulsarclient.BaseCommand{
+				Type:    pulsarclient.BaseCommand_SUCCESS.Enum(),
+				Success: &pulsarclient.CommandSuccess{RequestID: cmd.CloseProducer.RequestID},
+			}
+		case pulsarclient.BaseCommand_SEND:
+			resp = b.handleSend(cmd.Send, msgs, producers)
+		default:
+			return
+		}
+		if !write(resp) {
+			return
+		}
+	}
+}
+
+// handleSend stores the messages of a batch and returns the receipt, or the
+// error if the batch is rejected.
+func (b *MockPulsarBroker) handleSend(
+	send *pulsarclient.CommandSend, msgs []pulsarclient.Message, producers map[uint64]string,
+) *pulsarclient.BaseCommand {
+	sendError := func(code pulsarclient.ServerError, msg string) *pulsarclient.BaseCommand {
+		return &pulsarclient.BaseCommand{
+			Type: pulsarclient.BaseCommand_SEND_ERROR.Enum(),
+			SendError: &pulsarclient.CommandSendError{
+				ProducerID: send.ProducerID,
+				SequenceID: send.SequenceID,
+				Error:      code,
+				Message:    msg,
+			},
+		}
+	}
+	topic, ok := producers[send.ProducerID]
+	if !ok {
+		return sendError(pulsarclient.ServerError_UnknownError, "unknown producer")
+	}
+	b.mu.Lock()
+	hook := b.mu.sendHook
+	b.mu.Unlock()
+	if hook != nil {
+		if err := hook(topic, msgs); err != nil {
+			return sendError(pulsarclient.ServerError_PersistenceError, err.Error())
+		}
+	}
+
+	b.mu.Lock()
+	b.mu.messages[topic] = append(b.mu.messages[topic], msgs...)
+	b.mu.batches[topic]++
+	b.mu.Unlock()
+	return &pulsarclient.BaseCommand{
+		Type: pulsarclient.BaseCommand_SEND_RECEIPT.Enum(),
+		SendReceipt: &pulsarclient.CommandSendReceipt{
+			ProducerID: send.ProducerID,
+			SequenceID: send.SequenceID,
+		},
+	}
+}
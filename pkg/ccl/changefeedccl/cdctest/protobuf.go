@@ -0,0 +1,214 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package cdctest
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// protoMessage is a message of a proto3 schema generated by the protobuf
+// changefeed encoder.
+type protoMessage struct {
+	name   string
+	nested map[string]*protoMessage
+	fields map[protowire.Number]protoField
+}
+
+type protoField struct {
+	name string
+	typ  string
+}
+
+// parseProtoSchema parses the subset of the proto3 language used by the
+// schemas of the protobuf changefeed encoder, which is the syntax statement
+// and messages of optional scalar and nested message fields. It returns the
+// top-level messages in order.
+func parseProtoSchema(schema string) ([]*protoMessage, error) {
+	for _, punct := range []string{`{`, `}`, `;`, `=`} {
+		schema = strings.ReplaceAll(schema, punct, ` `+punct+` `)
+	}
+	p := &protoParser{tokens: strings.Fields(schema)}
+	var msgs []*protoMessage
+	for p.more() {
+		switch tok := p.next(); tok {
+		case `syntax`:
+			if err := p.expect(`=`, `"proto3"`, `;`); err != nil {
+				return nil, err
+			}
+		case `message`:
+			msg, err := p.parseMessage()
+			if err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, msg)
+		default:
+			return nil, errors.Errorf(`unexpected token %q`, tok)
+		}
+	}
+	return msgs, nil
+}
+
+type protoParser struct {
+	tokens []string
+}
+
+func (p *protoParser) more() bool {
+	return len(p.tokens) > 0
+}
+
+func (p *protoParser) next() string {
+	if !p.more() {
+		return ``
+	}
+	tok := p.tokens[0]
+	p.tokens = p.tokens[1:]
+	return tok
+}
+
+func (p *protoParser) expect(tokens ...string) error {
+	for _, expected := range tokens {
+		if tok := p.next(); tok != expected {
+			return errors.Errorf(`expected %q, found %q`, expected, tok)
+		}
+	}
+	return nil
+}
+
+// parseMessage parses a message after the message keyword.
+func (p *protoParser) parseMessage() (*protoMessage, error) {
+	msg := &protoMessage{
+		name:   p.next(),
+		nested: make(map[string]*protoMessage),
+		fields: make(map[protowire.Number]protoField),
+	}
+	if err := p.expect(`{`); err != nil {
+		return nil, err
+	}
+	for {
+		switch tok := p.next(); tok {
+		case `}`:
+			return msg, nil
+		case `message`:
+			nested, err := p.parseMessage()
+			if err != nil {
+				return nil, err
+			}
+			msg.nested[nested.name] = nested
+		case ``:
+			return nil, errors.Errorf(`unterminated message %s`, msg.name)
+		default:
+			typ := tok
+			if typ == `optional` {
+				typ = p.next()
+			}
+			name := p.next()
+			if err := p.expect(`=`); err != nil {
+				return nil, err
+			}
+			num, err := strconv.Atoi(p.next())
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(`;`); err != nil {
+				return nil, err
+			}
+			msg.fields[protowire.Number(num)] = protoField{name: name, typ: typ}
+		}
+	}
+}
+
+// protobufToNative decodes a protobuf message in the confluent wire format,
+// after the schema ID, into a map from field names to go native values. The
+// fields which are not set are nil.
+func protobufToNative(schema string, b []byte) (map[string]interface{}, error) {
+	msgs, err := parseProtoSchema(schema)
+	if err != nil {
+		return nil, errors.Wrapf(err, `parsing schema %s`, schema)
+	}
+	// The message indexes are a zigzag encoded array, which is encoded as a
+	// single zero byte for the first message.
+	n, l := protowire.ConsumeVarint(b)
+	if l < 0 {
+		return nil, protowire.ParseError(l)
+	}
+	if n != 0 || len(msgs) == 0 {
+		return nil, errors.Errorf(`only the first message of a schema is supported`)
+	}
+	return decodeProtoMessage(msgs[0], b[l:])
+}
+
+func decodeProtoMessage(msg *protoMessage, b []byte) (map[string]interface{}, error) {
+	native := make(map[string]interface{}, len(msg.fields))
+	for _, field := range msg.fields {
+		native[field.name] = nil
+	}
+	for len(b) > 0 {
+		num, wireType, l := protowire.ConsumeTag(b)
+		if l < 0 {
+			return nil, protowire.ParseError(l)
+		}
+		b = b[l:]
+		field, ok := msg.fields[num]
+		if !ok {
+			return nil, errors.Errorf(`unknown field %d of message %s`, num, msg.name)
+		}
+		var v interface{}
+		switch wireType {
+		case protowire.VarintType:
+			var x uint64
+			x, l = protowire.ConsumeVarint(b)
+			switch field.typ {
+			case `bool`:
+				v = protowire.DecodeBool(x)
+			case `int64`:
+				v = int64(x)
+			default:
+				return nil, errors.Errorf(`unexpected varint for %s field %s`, field.typ, field.name)
+			}
+		case protowire.Fixed64Type:
+			var x uint64
+			x, l = protowire.ConsumeFixed64(b)
+			if field.typ != `double` {
+				return nil, errors.Errorf(`unexpected fixed64 for %s field %s`, field.typ, field.name)
+			}
+			v = math.Float64frombits(x)
+		case protowire.BytesType:
+			var x []byte
+			x, l = protowire.ConsumeBytes(b)
+			switch field.typ {
+			case `string`:
+				v = string(x)
+			case `bytes`:
+				v = append([]byte(nil), x...)
+			default:
+				nested, ok := msg.nested[field.typ]
+				if !ok {
+					return nil, errors.Errorf(`unknown type %s of field %s`, field.typ, field.name)
+				}
+				var err error
+				if v, err = decodeProtoMessage(nested, x); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, errors.Errorf(`unexpected wire type %d for field %s`, wireType, field.name)
+		}
+		if l < 0 {
+			return nil, protowire.ParseError(l)
+		}
+		b = b[l:]
+		native[field.name] = v
+	}
+	return native, nil
+}
@@ -28,9 +28,10 @@ type SchemaRegistry struct {
 	server *httptest.Server
 	mu     struct {
 		syncutil.Mutex
-		idAlloc  int32
-		schemas  map[int32]string
-		subjects map[string]int32
+		idAlloc     int32
+		schemas     map[int32]string
+		schemaTypes map[int32]string
+		subjects    map[string]int32
 	}
 }
 
@@ -58,6 +59,7 @@ func StartTestSchemaRegistryWithTLS(certificate *tls.Certificate) (*SchemaRegist
 func makeTestSchemaRegistry() *SchemaRegistry {
 	r := &SchemaRegistry{}
 	r.mu.schemas = make(map[int32]string)
+	r.mu.schemaTypes = make(map[int32]string)
 	r.mu.subjects = make(map[string]int32)
 	r.server = httptest.NewUnstartedServer(http.HandlerFunc(r.requestHandler))
 	return r
@@ -90,13 +92,22 @@ func (r *SchemaRegistry) SchemaForSubject(subject string) string {
 	return r.mu.schemas[r.mu.subjects[subject]]
 }
 
-func (r *SchemaRegistry) registerSchema(subject string, schema string) int32 {
+// SchemaTypeForSubject returns the type of the schema for the specified
+// subject, which is empty for Avro schemas.
+func (r *SchemaRegistry) SchemaTypeForSubject(subject string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mu.schemaTypes[r.mu.subjects[subject]]
+}
+
+func (r *SchemaRegistry) registerSchema(subject string, schema string, schemaType string) int32 {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	id := r.mu.idAlloc
 	r.mu.idAlloc++
 	r.mu.schemas[id] = schema
+	r.mu.schemaTypes[id] = schemaType
 	r.mu.subjects[subject] = id
 	return id
 }
@@ -130,7 +141,8 @@ func (r *SchemaRegistry) requestHandler(hw http.ResponseWriter, hr *http.Request
 // register is an http handler for the underlying server which registers schemas.
 func (r *SchemaRegistry) register(hw http.ResponseWriter, hr *http.Request) (err error) {
 	type confluentSchemaVersionRequest struct {
-		Schema string `json:"schema"`
+		Schema     string `json:"schema"`
+		SchemaType string `json:"schemaType"`
 	}
 	type confluentSchemaVersionResponse struct {
 		ID int32 `json:"id"`
@@ -146,7 +158,12 @@ func (r *SchemaRegistry) register(hw http.ResponseWriter, hr *http.Request) (err
 	}
 
 	subject := strings.Split(hr.URL.Path, "/")[2]
-	id := r.registerSchema(subject, req.Schema)
+	switch req.SchemaType {
+	case ``, `AVRO`, `PROTOBUF`, `JSON`:
+	default:
+		return errors.Errorf(`unknown schema type %q`, req.SchemaType)
+	}
+	id := r.registerSchema(subject, req.Schema, req.SchemaType)
 	res, err := json.Marshal(confluentSchemaVersionResponse{ID: id})
 	if err != nil {
 		return err
@@ -165,18 +182,26 @@ func (r *SchemaRegistry) mode(hw http.ResponseWriter, _ *http.Request) error {
 	return err
 }
 
-// EncodedAvroToNative decodes bytes that were previously encoded by
-// confluent avro encoder, into GO native representation.
-func (r *SchemaRegistry) EncodedAvroToNative(b []byte) (interface{}, error) {
+// decodeWireHeader splits bytes in the confluent wire format into the
+// registry id of their schema and the encoded message.
+func decodeWireHeader(b []byte) (int32, []byte, error) {
 	if len(b) == 0 || b[0] != changefeedbase.ConfluentAvroWireFormatMagic {
-		return ``, errors.Errorf(`bad magic byte`)
+		return 0, nil, errors.Errorf(`bad magic byte`)
 	}
 	b = b[1:]
 	if len(b) < 4 {
-		return ``, errors.Errorf(`missing registry id`)
+		return 0, nil, errors.Errorf(`missing registry id`)
+	}
+	return int32(binary.BigEndian.Uint32(b[:4])), b[4:], nil
+}
+
+// EncodedAvroToNative decodes bytes that were previously encoded by
+// confluent avro encoder, into GO native representation.
+func (r *SchemaRegistry) EncodedAvroToNative(b []byte) (interface{}, error) {
+	id, b, err := decodeWireHeader(b)
+	if err != nil {
+		return ``, err
 	}
-	id := int32(binary.BigEndian.Uint32(b[:4]))
-	b = b[4:]
 
 	r.mu.Lock()
 	jsonSchema := r.mu.schemas[id]
@@ -203,3 +228,32 @@ func (r *SchemaRegistry) AvroToJSON(avroBytes []byte) ([]byte, error) {
 	// which sorts its object keys and so is deterministic.
 	return json.Marshal(native)
 }
+
+// EncodedToJSON converts bytes in the confluent wire format to their JSON
+// representation, according to the type of their schema. Avro and protobuf
+// messages are converted with json.Marshal, which sorts their object keys.
+// JSON Schema messages are already JSON.
+func (r *SchemaRegistry) EncodedToJSON(b []byte) ([]byte, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	id, msg, err := decodeWireHeader(b)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	schema, schemaType := r.mu.schemas[id], r.mu.schemaTypes[id]
+	r.mu.Unlock()
+	switch schemaType {
+	case `PROTOBUF`:
+		native, err := protobufToNative(schema, msg)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(native)
+	case `JSON`:
+		return msg, nil
+	default:
+		return r.AvroToJSON(b)
+	}
+}
@@ -31,7 +31,13 @@ type Throttler struct {
 	messageLimiter *quotapool.RateLimiter
 	byteLimiter    *quotapool.RateLimiter
 	flushLimiter   *quotapool.RateLimiter
-	metrics        *Metrics
+
+	messagesPushback, bytesPushback, flushPushback pushbackCounter
+}
+
+// pushbackCounter counts the nanoseconds spent waiting for quota.
+type pushbackCounter interface {
+	Inc(int64)
 }
 
 // AcquireMessageQuota acquires quota for a message with the specified size.
@@ -46,10 +52,10 @@ func (t *Throttler) AcquireMessageQuota(ctx context.Context, sz int) error {
 	ctx, span = tracing.ChildSpan(ctx, fmt.Sprintf("quota-wait-%s", t.name))
 	defer span.Finish()
 
-	if err := waitQuota(ctx, 1, t.messageLimiter, t.metrics.MessagesPushbackNanos); err != nil {
+	if err := waitQuota(ctx, 1, t.messageLimiter, t.messagesPushback); err != nil {
 		return err
 	}
-	return waitQuota(ctx, int64(sz), t.byteLimiter, t.metrics.BytesPushbackNanos)
+	return waitQuota(ctx, int64(sz), t.byteLimiter, t.bytesPushback)
 }
 
 // AcquireFlushQuota acquires quota for a message with the specified size.
@@ -63,7 +69,7 @@ func (t *Throttler) AcquireFlushQuota(ctx context.Context) error {
 	var span *tracing.Span
 	ctx, span = tracing.ChildSpan(ctx, fmt.Sprintf("quota-wait-flush-%s", t.name))
 	defer span.Finish()
-	return waitQuota(ctx, 1, t.flushLimiter, t.metrics.FlushPushbackNanos)
+	return waitQuota(ctx, 1, t.flushLimiter, t.flushPushback)
 }
 
 func (t *Throttler) updateConfig(config changefeedbase.SinkThrottleConfig) {
@@ -88,6 +94,14 @@ func (t *Throttler) updateConfig(config changefeedbase.SinkThrottleConfig) {
 
 // NewThrottler creates a new throttler with the specified configuration.
 func NewThrottler(name string, config changefeedbase.SinkThrottleConfig, m *Metrics) *Throttler {
+	return newThrottler(name, config, m.MessagesPushbackNanos, m.BytesPushbackNanos, m.FlushPushbackNanos)
+}
+
+func newThrottler(
+	name string,
+	config changefeedbase.SinkThrottleConfig,
+	messagesPushback, bytesPushback, flushPushback pushbackCounter,
+) *Throttler {
 	logSlowAcquisition := quotapool.OnSlowAcquisition(500*time.Millisecond, quotapool.LogSlowAcquisition)
 	t := &Throttler{
 		name: name,
@@ -100,12 +114,24 @@ func NewThrottler(name string, config changefeedbase.SinkThrottleConfig, m *Metr
 		flushLimiter: quotapool.NewRateLimiter(
 			fmt.Sprintf("%s-flushes", name), 0, 0, logSlowAcquisition,
 		),
-		metrics: m,
+		messagesPushback: messagesPushback,
+		bytesPushback:    bytesPushback,
+		flushPushback:    flushPushback,
 	}
 	t.updateConfig(config)
 	return t
 }
 
+// NewByteThrottler creates a new throttler which limits the rate of bytes to
+// the specified number of bytes per second. The time spent throttled is
+// counted by the pushback counter.
+func NewByteThrottler(name string, bytesPerSecond int64, pushback pushbackCounter) *Throttler {
+	return newThrottler(name, changefeedbase.SinkThrottleConfig{
+		ByteRate:  float64(bytesPerSecond),
+		ByteBurst: float64(bytesPerSecond),
+	}, pushback, pushback, pushback)
+}
+
 var nodeSinkThrottle = struct {
 	sync.Once
 	*Throttler
@@ -170,7 +196,7 @@ var _ metric.Struct = (*Metrics)(nil)
 func (m Metrics) MetricStruct() {}
 
 func waitQuota(
-	ctx context.Context, n int64, limit *quotapool.RateLimiter, c *metric.Counter,
+	ctx context.Context, n int64, limit *quotapool.RateLimiter, c pushbackCounter,
 ) error {
 	start := timeutil.Now()
 	defer func() {
@@ -37,6 +37,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logcrash"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/aggmetric"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 	"github.com/cockroachdb/cockroach/pkg/util/span"
@@ -62,6 +63,11 @@ type changeAggregator struct {
 	kvFeedDoneCh chan struct{}
 	kvFeedMemMon *mon.BytesMonitor
 
+	// quotas are the resource quotas of the changefeed.
+	quotas changefeedQuotas
+	// quotaMetrics, if non-nil, are the metrics of the changefeed's quotas.
+	quotaMetrics *jobQuotaMetrics
+
 	// encoder is the Encoder to use for key and value serialization.
 	encoder Encoder
 	// sink is the Sink to write rows to. Resolved timestamps are never written
@@ -78,6 +84,10 @@ type changeAggregator struct {
 	// recentKVCount contains the number of emits since the last time a resolved
 	// span was forwarded to the frontier
 	recentKVCount uint64
+	// reportedEmittedMessages is the number of messages emitted by the
+	// eventConsumer as of the last time a resolved span was forwarded to the
+	// frontier.
+	reportedEmittedMessages uint64
 
 	// eventProducer produces the next event from the kv feed.
 	eventProducer kvevent.Reader
@@ -234,6 +244,13 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		ca.knobs = *cfKnobs
 	}
 
+	ca.quotas, err = makeChangefeedQuotas(ca.spec.Feed.Opts)
+	if err != nil {
+		ca.MoveToDraining(err)
+		ca.cancel()
+		return
+	}
+
 	// TODO(yevgeniy): Introduce separate changefeed monitor that's a parent
 	// for all changefeeds to control memory allocated to all changefeeds.
 	pool := ca.flowCtx.Cfg.BackfillerMonitor
@@ -241,6 +258,9 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		pool = ca.knobs.MemMonitor
 	}
 	limit := changefeedbase.PerChangefeedMemLimit.Get(&ca.flowCtx.Cfg.Settings.SV)
+	if ca.quotas.memory > 0 {
+		limit = ca.quotas.memory
+	}
 	kvFeedMemMon := mon.NewMonitorInheritWithLimit("kvFeed", limit, pool)
 	kvFeedMemMon.Start(ctx, pool, mon.BoundAccount{})
 	ca.kvFeedMemMon = kvFeedMemMon
@@ -249,6 +269,10 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 	// runs. They're all stored as the `metric.Struct` interface because of
 	// dependency cycles.
 	ca.metrics = ca.flowCtx.Cfg.JobRegistry.MetricsStruct().Changefeed.(*Metrics)
+	if ca.quotas.isSet() {
+		ca.quotaMetrics = ca.metrics.QuotaMetrics.acquireJob(ca.spec.JobID)
+		ca.quotaMetrics.MemoryLimit.Update(ca.quotas.memory)
+	}
 	ca.sliMetrics, err = ca.metrics.getSLIMetrics(ca.spec.Feed.Opts[changefeedbase.OptMetricsScope])
 	if err != nil {
 		ca.MoveToDraining(err)
@@ -273,7 +297,17 @@ func (ca *changeAggregator) Start(ctx context.Context) {
 		ca.changedRowBuf = &b.buf
 	}
 
-	ca.sink = &errorWrapperSink{wrapped: ca.sink}
+	if ca.quotas.throughput > 0 {
+		ca.sink = newThroughputLimitSink(ca.sink,
+			cdcutils.NewByteThrottler(fmt.Sprintf("cf.%d.throttle", ca.spec.JobID),
+				ca.quotas.throughput, ca.quotaMetrics.ThroughputPushback))
+	}
+	var flushWaitNanos *aggmetric.Counter
+	if ca.quotaMetrics != nil {
+		flushWaitNanos = ca.quotaMetrics.FlushWaitNanos
+	}
+	ca.sink = makeErrorWrapperSink(newFairFlushSink(ca.sink,
+		ca.metrics.getFlushScheduler(&ca.flowCtx.Cfg.Settings.SV), ca.quotas.flushWeight, flushWaitNanos))
 
 	// If the initial scan was disabled the highwater would've already been forwarded
 	needsInitialScan := ca.frontier.Frontier().IsEmpty()
@@ -314,8 +348,13 @@ func (ca *changeAggregator) startKVFeed(
 	endTime hlc.Timestamp,
 ) (kvevent.Reader, error) {
 	cfg := ca.flowCtx.Cfg
+	memQuota := kvevent.MemQuota{Limit: ca.quotas.memory}
+	if ca.quotaMetrics != nil {
+		memQuota.Usage = ca.quotaMetrics.MemoryUsage
+	}
 	buf := kvevent.NewThrottlingBuffer(
-		kvevent.NewMemBuffer(ca.kvFeedMemMon.MakeBoundAccount(), &cfg.Settings.SV, &ca.metrics.KVFeedMetrics),
+		kvevent.NewMemBufferWithQuota(ca.kvFeedMemMon.MakeBoundAccount(), &cfg.Settings.SV,
+			&ca.metrics.KVFeedMetrics, memQuota),
 		cdcutils.NodeLevelThrottler(&cfg.Settings.SV, &ca.metrics.ThrottleMetrics))
 
 	// KVFeed takes ownership of the kvevent.Writer portion of the buffer, while
@@ -358,6 +397,9 @@ func (ca *changeAggregator) makeKVFeedCfg(
 	schemaChangePolicy := changefeedbase.SchemaChangePolicy(
 		ca.spec.Feed.Opts[changefeedbase.OptSchemaChangePolicy])
 	_, withDiff := ca.spec.Feed.Opts[changefeedbase.OptDiff]
+	// Under the backfill_changed_columns policy, the consumer compares each row
+	// with its previous value to skip the rows rewritten by schema changes.
+	withDiff = withDiff || schemaChangePolicy == changefeedbase.OptSchemaChangePolicyBackfillChangedColumns
 	cfg := ca.flowCtx.Cfg
 
 	var sf schemafeed.SchemaFeed
@@ -464,6 +506,9 @@ func (ca *changeAggregator) close() {
 	if ca.kvFeedMemMon != nil {
 		ca.kvFeedMemMon.Stop(ca.Ctx)
 	}
+	if ca.quotaMetrics != nil {
+		ca.metrics.QuotaMetrics.releaseJob(ca.spec.JobID)
+	}
 	ca.MemMonitor.Stop(ca.Ctx)
 	ca.InternalClose()
 }
@@ -534,6 +579,8 @@ func (ca *changeAggregator) tick() error {
 		}
 	case kvevent.TypeFlush:
 		return ca.sink.Flush(ca.Ctx)
+	case kvevent.TypeSchemaChange:
+		return ca.eventConsumer.ConsumeSchemaChange(ca.Ctx, event)
 	}
 
 	return nil
@@ -626,7 +673,8 @@ func (ca *changeAggregator) emitResolved(batch jobspb.ResolvedSpans) error {
 	progressUpdate := jobspb.ResolvedSpans{
 		ResolvedSpans: batch.ResolvedSpans,
 		Stats: jobspb.ResolvedSpans_Stats{
-			RecentKvCount: ca.recentKVCount,
+			RecentKvCount:         ca.recentKVCount,
+			RecentEmittedMessages: ca.eventConsumer.emittedMessages - ca.reportedEmittedMessages,
 		},
 	}
 	updateBytes, err := protoutil.Marshal(&progressUpdate)
@@ -641,6 +689,7 @@ func (ca *changeAggregator) emitResolved(batch jobspb.ResolvedSpans) error {
 	})
 
 	ca.recentKVCount = 0
+	ca.reportedEmittedMessages = ca.eventConsumer.emittedMessages
 	return nil
 }
 
@@ -729,6 +778,8 @@ type jobState struct {
 	checkpointDuration time.Duration
 	// Flag set if we skip some updates due to rapid progress update requests.
 	progressUpdatesSkipped bool
+	// The number of messages emitted since the last job progress update.
+	pendingEmittedMessages uint64
 }
 
 func newJobState(
@@ -907,7 +958,7 @@ func (cf *changeFrontier) Start(ctx context.Context) {
 		cf.resolvedBuf = &b.buf
 	}
 
-	cf.sink = &errorWrapperSink{wrapped: cf.sink}
+	cf.sink = makeErrorWrapperSink(cf.sink)
 
 	cf.highWaterAtStart = cf.spec.Feed.StatementTime
 	if cf.spec.JobID != 0 {
@@ -1073,6 +1124,9 @@ func (cf *changeFrontier) noteAggregatorProgress(d rowenc.EncDatum) error {
 		}
 
 		cf.maybeMarkJobIdle(resolvedSpans.Stats.RecentKvCount)
+		if cf.js != nil {
+			cf.js.pendingEmittedMessages += resolvedSpans.Stats.RecentEmittedMessages
+		}
 	} else { // TODO(smiskin): Remove post-22.2
 		// Progress used to be sent as individual ResolvedSpans
 		var resolved jobspb.ResolvedSpan
@@ -1217,6 +1271,11 @@ func (cf *changeFrontier) checkpointJobProgress(
 	}
 	cf.metrics.FrontierUpdates.Inc(1)
 
+	defer func() {
+		if err == nil {
+			cf.js.pendingEmittedMessages = 0
+		}
+	}()
 	return cf.js.job.Update(cf.Ctx, nil, func(
 		txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
 	) error {
@@ -1232,6 +1291,7 @@ func (cf *changeFrontier) checkpointJobProgress(
 
 		changefeedProgress := progress.Details.(*jobspb.Progress_Changefeed).Changefeed
 		changefeedProgress.Checkpoint = &checkpoint
+		changefeedProgress.EmittedMessages += cf.js.pendingEmittedMessages
 
 		timestampManager := cf.manageProtectedTimestamps
 		// TODO(samiskin): Remove this conditional and the associated deprecated
@@ -1310,7 +1370,8 @@ func (cf *changeFrontier) deprecatedManageProtectedTimestamps(
 	}
 
 	schemaChangePolicy := changefeedbase.SchemaChangePolicy(cf.spec.Feed.Opts[changefeedbase.OptSchemaChangePolicy])
-	shouldProtectBoundaries := schemaChangePolicy == changefeedbase.OptSchemaChangePolicyBackfill
+	shouldProtectBoundaries := schemaChangePolicy == changefeedbase.OptSchemaChangePolicyBackfill ||
+		schemaChangePolicy == changefeedbase.OptSchemaChangePolicyBackfillChangedColumns
 	if cf.frontier.schemaChangeBoundaryReached() && shouldProtectBoundaries {
 		highWater := cf.frontier.Frontier()
 		ptr := createProtectedTimestampRecord(ctx, cf.flowCtx.Codec(), cf.spec.JobID, AllTargets(cf.spec.Feed), highWater, progress)
@@ -0,0 +1,313 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcutils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/aggmetric"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// maxFlushWeight is the largest flush_weight of a changefeed.
+const maxFlushWeight = 100
+
+// defaultFlushWeight is the flush_weight of changefeeds which do not specify
+// one.
+const defaultFlushWeight = 1
+
+// changefeedQuotas are the resource quotas of a changefeed, as specified by
+// its options.
+type changefeedQuotas struct {
+	// memory is the number of bytes the changefeed may buffer, or zero if
+	// changefeed.memory.per_changefeed_limit applies.
+	memory int64
+	// throughput is the number of bytes per second the changefeed may emit, or
+	// zero if unlimited.
+	throughput int64
+	// flushWeight is the share of the node's sink flushes of the changefeed,
+	// relative to the other changefeeds.
+	flushWeight int64
+}
+
+// makeChangefeedQuotas parses the quota options of a changefeed.
+func makeChangefeedQuotas(opts map[string]string) (changefeedQuotas, error) {
+	q := changefeedQuotas{flushWeight: defaultFlushWeight}
+	parseBytes := func(opt string) (int64, error) {
+		v, ok := opts[opt]
+		if !ok {
+			return 0, nil
+		}
+		n, err := humanizeutil.ParseBytes(v)
+		if err != nil {
+			return 0, errors.Wrapf(err, `invalid %s`, opt)
+		}
+		if n <= 0 {
+			return 0, errors.Errorf(`%s must be a positive number of bytes, found %q`, opt, v)
+		}
+		return n, nil
+	}
+	var err error
+	if q.memory, err = parseBytes(changefeedbase.OptMemoryQuota); err != nil {
+		return changefeedQuotas{}, err
+	}
+	if q.throughput, err = parseBytes(changefeedbase.OptThroughputLimit); err != nil {
+		return changefeedQuotas{}, err
+	}
+	if v, ok := opts[changefeedbase.OptFlushWeight]; ok {
+		if q.flushWeight, err = strconv.ParseInt(v, 10, 64); err != nil ||
+			q.flushWeight < 1 || q.flushWeight > maxFlushWeight {
+			return changefeedQuotas{}, errors.Errorf(`%s must be an integer between 1 and %d, found %q`,
+				changefeedbase.OptFlushWeight, maxFlushWeight, v)
+		}
+	}
+	return q, nil
+}
+
+// isSet returns whether the changefeed specified any quota.
+func (q changefeedQuotas) isSet() bool {
+	return q.memory > 0 || q.throughput > 0 || q.flushWeight != defaultFlushWeight
+}
+
+// flushScheduler shares the concurrent sink flushes of the changefeeds on a
+// node among them, in proportion to their flush weights.
+//
+// Every flush slot is worth maxFlushWeight units of the pool, and a flush of a
+// changefeed acquires maxFlushWeight/flush_weight units. Hence, a changefeed
+// may run as many concurrent flushes as its weight in the time any other
+// changefeed runs one flush per unit of its own weight. The pool grants the
+// units in FIFO order, so that no changefeed is starved.
+type flushScheduler struct {
+	sv   *settings.Values
+	pool *quotapool.IntPool
+}
+
+func newFlushScheduler(sv *settings.Values) *flushScheduler {
+	s := &flushScheduler{
+		sv:   sv,
+		pool: quotapool.NewIntPool("changefeed-flushes", flushSchedulerCapacity(sv)),
+	}
+	changefeedbase.SinkFlushConcurrency.SetOnChange(sv, func(ctx context.Context) {
+		s.pool.UpdateCapacity(flushSchedulerCapacity(sv))
+	})
+	return s
+}
+
+func flushSchedulerCapacity(sv *settings.Values) uint64 {
+	if c := changefeedbase.SinkFlushConcurrency.Get(sv); c > 0 {
+		return uint64(c) * maxFlushWeight
+	}
+	// The pool is not used when flushes are not limited.
+	return maxFlushWeight
+}
+
+// acquire blocks until a changefeed with the specified weight may flush its
+// sink, and returns the function which releases the flush quota. The time
+// spent waiting is counted by waitNanos, if non-nil.
+func (s *flushScheduler) acquire(
+	ctx context.Context, weight int64, waitNanos *aggmetric.Counter,
+) (release func(), _ error) {
+	if changefeedbase.SinkFlushConcurrency.Get(s.sv) == 0 {
+		return func() {}, nil
+	}
+	start := timeutil.Now()
+	alloc, err := s.pool.Acquire(ctx, uint64(maxFlushWeight/weight))
+	if waitNanos != nil {
+		waitNanos.Inc(timeutil.Since(start).Nanoseconds())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return alloc.Release, nil
+}
+
+// fairFlushSink delegates to another sink and waits for the changefeed's
+// share of the node's sink flushes before flushing it.
+type fairFlushSink struct {
+	wrapped   Sink
+	scheduler *flushScheduler
+	weight    int64
+	waitNanos *aggmetric.Counter
+}
+
+// fairFlushEncoderSink is a fairFlushSink of a sink which encodes rows.
+type fairFlushEncoderSink struct {
+	*fairFlushSink
+}
+
+var _ SinkWithEncoder = fairFlushEncoderSink{}
+
+// newFairFlushSink returns a fairFlushSink of the given sink, which implements
+// SinkWithEncoder if the sink does. The time spent waiting to flush is counted
+// by waitNanos, if non-nil.
+func newFairFlushSink(
+	wrapped Sink, scheduler *flushScheduler, weight int64, waitNanos *aggmetric.Counter,
+) Sink {
+	s := &fairFlushSink{
+		wrapped:   wrapped,
+		scheduler: scheduler,
+		weight:    weight,
+		waitNanos: waitNanos,
+	}
+	if _, ok := wrapped.(SinkWithEncoder); ok {
+		return fairFlushEncoderSink{s}
+	}
+	return s
+}
+
+// EmitRow implements Sink interface.
+func (s *fairFlushSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	return s.wrapped.EmitRow(ctx, topic, key, value, updated, mvcc, alloc)
+}
+
+// EncodeAndEmitRow implements SinkWithEncoder interface.
+func (s fairFlushEncoderSink) EncodeAndEmitRow(
+	ctx context.Context,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	return s.wrapped.(SinkWithEncoder).EncodeAndEmitRow(ctx, updatedRow, prevRow, topic, updated, mvcc, alloc)
+}
+
+// EmitResolvedTimestamp implements Sink interface.
+func (s *fairFlushSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	return s.wrapped.EmitResolvedTimestamp(ctx, encoder, resolved)
+}
+
+// Flush implements Sink interface.
+func (s *fairFlushSink) Flush(ctx context.Context) error {
+	release, err := s.scheduler.acquire(ctx, s.weight, s.waitNanos)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return s.wrapped.Flush(ctx)
+}
+
+// Close implements Sink interface.
+func (s *fairFlushSink) Close() error {
+	return s.wrapped.Close()
+}
+
+// Dial implements Sink interface.
+func (s *fairFlushSink) Dial() error {
+	return s.wrapped.Dial()
+}
+
+// throughputLimitSink delegates to another sink and limits the rate of the
+// bytes emitted to it to the changefeed's throughput_limit.
+type throughputLimitSink struct {
+	wrapped  Sink
+	throttle *cdcutils.Throttler
+}
+
+// throughputLimitEncoderSink is a throughputLimitSink of a sink which encodes
+// rows.
+type throughputLimitEncoderSink struct {
+	*throughputLimitSink
+}
+
+var _ SinkWithEncoder = throughputLimitEncoderSink{}
+
+// newThroughputLimitSink returns a throughputLimitSink of the given sink, which
+// implements SinkWithEncoder if the sink does.
+func newThroughputLimitSink(wrapped Sink, throttle *cdcutils.Throttler) Sink {
+	s := &throughputLimitSink{wrapped: wrapped, throttle: throttle}
+	if _, ok := wrapped.(SinkWithEncoder); ok {
+		return throughputLimitEncoderSink{s}
+	}
+	return s
+}
+
+// EmitRow implements Sink interface.
+func (s *throughputLimitSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	if err := s.throttle.AcquireMessageQuota(ctx, len(key)+len(value)); err != nil {
+		return err
+	}
+	return s.wrapped.EmitRow(ctx, topic, key, value, updated, mvcc, alloc)
+}
+
+// EncodeAndEmitRow implements SinkWithEncoder interface. The rows are encoded
+// by the wrapped sink, so the size of their datums is throttled instead.
+func (s throughputLimitEncoderSink) EncodeAndEmitRow(
+	ctx context.Context,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	var size int
+	addSize := func(d tree.Datum, _ cdcevent.ResultColumn) error {
+		size += int(d.Size())
+		return nil
+	}
+	if err := updatedRow.ForEachColumn().Datum(addSize); err != nil {
+		return err
+	}
+	if prevRow.IsInitialized() {
+		if err := prevRow.ForEachColumn().Datum(addSize); err != nil {
+			return err
+		}
+	}
+	if err := s.throttle.AcquireMessageQuota(ctx, size); err != nil {
+		return err
+	}
+	return s.wrapped.(SinkWithEncoder).EncodeAndEmitRow(ctx, updatedRow, prevRow, topic, updated, mvcc, alloc)
+}
+
+// EmitResolvedTimestamp implements Sink interface.
+func (s *throughputLimitSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	return s.wrapped.EmitResolvedTimestamp(ctx, encoder, resolved)
+}
+
+// Flush implements Sink interface.
+func (s *throughputLimitSink) Flush(ctx context.Context) error {
+	return s.wrapped.Flush(ctx)
+}
+
+// Close implements Sink interface.
+func (s *throughputLimitSink) Close() error {
+	return s.wrapped.Close()
+}
+
+// Dial implements Sink interface.
+func (s *throughputLimitSink) Dial() error {
+	return s.wrapped.Dial()
+}
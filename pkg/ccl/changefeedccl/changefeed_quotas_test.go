@@ -0,0 +1,209 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcutils"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/aggmetric"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeChangefeedQuotas(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		opts     map[string]string
+		expected changefeedQuotas
+		err      string
+	}{
+		{
+			opts:     map[string]string{},
+			expected: changefeedQuotas{flushWeight: defaultFlushWeight},
+		},
+		{
+			opts: map[string]string{
+				changefeedbase.OptMemoryQuota:     `64MiB`,
+				changefeedbase.OptThroughputLimit: `1KB`,
+				changefeedbase.OptFlushWeight:     `10`,
+			},
+			expected: changefeedQuotas{memory: 64 << 20, throughput: 1000, flushWeight: 10},
+		},
+		{
+			opts: map[string]string{changefeedbase.OptMemoryQuota: `lots`},
+			err:  `invalid memory_quota`,
+		},
+		{
+			opts: map[string]string{changefeedbase.OptThroughputLimit: `0`},
+			err:  `throughput_limit must be a positive number of bytes, found "0"`,
+		},
+		{
+			opts: map[string]string{changefeedbase.OptFlushWeight: `101`},
+			err:  `flush_weight must be an integer between 1 and 100, found "101"`,
+		},
+	} {
+		t.Run(fmt.Sprint(tc.opts), func(t *testing.T) {
+			q, err := makeChangefeedQuotas(tc.opts)
+			if tc.err != `` {
+				require.Regexp(t, tc.err, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, q)
+		})
+	}
+}
+
+func TestFlushSchedulerWeights(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	s := newFlushScheduler(&st.SV)
+
+	// Flushes are not limited if the concurrency is zero.
+	changefeedbase.SinkFlushConcurrency.Override(ctx, &st.SV, 0)
+	for i := 0; i < 2*maxFlushWeight; i++ {
+		_, err := s.acquire(ctx, 1, nil)
+		require.NoError(t, err)
+	}
+
+	// A flush waits for its changefeed's share of the node's flushes.
+	changefeedbase.SinkFlushConcurrency.Override(ctx, &st.SV, 1)
+	mustBlock := func(weight int64) {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := s.acquire(ctx, weight, nil)
+		require.True(t, errors.Is(err, context.DeadlineExceeded), "expected flush to block, got %v", err)
+	}
+
+	// A changefeed with weight 4 runs 4 concurrent flushes in the time a
+	// changefeed with weight 1 runs a single one.
+	var releases []func()
+	for i := 0; i < 4; i++ {
+		release, err := s.acquire(ctx, 4, nil)
+		require.NoError(t, err)
+		releases = append(releases, release)
+	}
+	mustBlock(4)
+	mustBlock(1)
+	for _, release := range releases {
+		release()
+	}
+	release, err := s.acquire(ctx, 1, nil)
+	require.NoError(t, err)
+	mustBlock(4)
+	release()
+
+	// Increasing the concurrency makes room for more flushes.
+	changefeedbase.SinkFlushConcurrency.Override(ctx, &st.SV, 2)
+	for i := 0; i < 2; i++ {
+		_, err := s.acquire(ctx, 1, nil)
+		require.NoError(t, err)
+	}
+	mustBlock(maxFlushWeight)
+}
+
+func TestThroughputLimitSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	pushback := aggmetric.NewCounter(metric.Metadata{Name: "pushback"}, "job_id").AddChild("1")
+	sink := newThroughputLimitSink(&nullSink{metrics: (*sliMetrics)(nil)},
+		cdcutils.NewByteThrottler("test", 1000, pushback))
+	emit := func(ctx context.Context, size int) error {
+		return sink.EmitRow(ctx, nil, nil, make([]byte, size), hlc.Timestamp{}, hlc.Timestamp{}, kvevent.Alloc{})
+	}
+
+	// The bytes emitted to the sink are throttled once they exceed the
+	// throughput_limit.
+	require.NoError(t, emit(ctx, 1000))
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	err := emit(timeoutCtx, 1000)
+	require.True(t, errors.Is(err, context.DeadlineExceeded), "expected row to be throttled, got %v", err)
+	require.Greater(t, pushback.Value(), int64(0))
+}
+
+func TestQuotaSinksEncodeRows(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	st := cluster.MakeTestingClusterSettings()
+	throttle := cdcutils.NewByteThrottler("test", 1000, nil)
+	wrap := func(sink Sink) Sink {
+		return newFairFlushSink(newThroughputLimitSink(sink, throttle), newFlushScheduler(&st.SV), 1, nil)
+	}
+
+	// The sinks only implement SinkWithEncoder if the sinks they wrap do.
+	_, ok := wrap(&bufferSink{}).(SinkWithEncoder)
+	require.False(t, ok)
+	_, ok = wrap(encodingTestSink{&bufferSink{}}).(SinkWithEncoder)
+	require.True(t, ok)
+}
+
+func TestChangefeedQuotas(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		changefeedbase.SinkFlushConcurrency.Override(
+			context.Background(), &s.Server.ClusterSettings().SV, 4)
+
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo
+WITH memory_quota='16MiB', throughput_limit='1MiB', flush_weight='10'`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "b"}}`,
+		})
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (2, 'c')`)
+		assertPayloads(t, foo, []string{
+			`foo: [2]->{"after": {"a": 2, "b": "c"}}`,
+		})
+
+		// The quotas of the changefeed are tracked by the job's metrics, which
+		// are removed once the changefeed stops.
+		registry := s.Server.JobRegistry().(*jobs.Registry)
+		metrics := registry.MetricsStruct().Changefeed.(*Metrics).QuotaMetrics
+		require.Equal(t, int64(16<<20), metrics.MemoryLimit.Value())
+		closeFeed(t, foo)
+		testutils.SucceedsSoon(t, func() error {
+			if v := metrics.MemoryLimit.Value(); v != 0 {
+				return errors.Newf("expected no memory quota, found %d", v)
+			}
+			return nil
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
@@ -441,6 +441,31 @@ func createChangefeedJobRecord(
 		return nil, err
 	}
 
+	if changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]) == changefeedbase.OptFormatParquet &&
+		!isCloudStorageSink(parsedSink) {
+		return nil, errors.Errorf(`%s=%s is only usable with cloud storage sinks`,
+			changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
+
+	if changefeedbase.SchemaChangePolicy(details.Opts[changefeedbase.OptSchemaChangePolicy]) ==
+		changefeedbase.OptSchemaChangePolicyBackfillChangedColumns {
+		if err := validateBackfillChangedColumns(details, parsedSink); err != nil {
+			return nil, err
+		}
+	}
+
+	if isPostgresSink(parsedSink) {
+		if format := changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]); format != changefeedbase.OptFormatJSON {
+			return nil, errors.Errorf(`%s=%s is not usable with postgres sinks, which write rows to tables`,
+				changefeedbase.OptFormat, format)
+		}
+		for _, target := range AllTargets(details) {
+			if target.Type == jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY {
+				return nil, errors.Errorf(`postgres sinks do not support watching column families`)
+			}
+		}
+	}
+
 	if isCloudStorageSink(parsedSink) || isWebhookSink(parsedSink) {
 		details.Opts[changefeedbase.OptKeyInValue] = ``
 	}
@@ -661,6 +686,15 @@ func getTargetsAndTables(
 				StatementTimeName: tables[td.GetID()].StatementTimeName,
 			}
 		}
+		if changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeDebezium &&
+			targets[i].StatementTimeDatabaseName == "" {
+			tbName, err := getQualifiedTableNameObj(ctx, p.ExecCfg(), p.Txn(), td)
+			if err != nil {
+				return nil, nil, err
+			}
+			targets[i].StatementTimeDatabaseName = tbName.Catalog()
+			targets[i].StatementTimeSchemaName = tbName.Schema()
+		}
 		if dup, isDup := seen[targets[i]]; isDup {
 			return nil, nil, errors.Errorf(
 				"CHANGEFEED targets %s and %s are duplicates",
@@ -792,6 +826,9 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 			}
 		}
 	}
+	if _, err := makeChangefeedQuotas(details.Opts); err != nil {
+		return jobspb.ChangefeedDetails{}, err
+	}
 	{
 		const opt = changefeedbase.OptSchemaChangeEvents
 		switch v := changefeedbase.SchemaChangeEventClass(details.Opts[opt]); v {
@@ -813,6 +850,8 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 			// No-op
 		case changefeedbase.OptSchemaChangePolicyStop:
 			// No-op
+		case changefeedbase.OptSchemaChangePolicyBackfillChangedColumns:
+			// No-op
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
 				`unknown %s: %s`, opt, v)
@@ -827,6 +866,11 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeKeyOnly)
 		case ``, changefeedbase.OptEnvelopeWrapped:
 			details.Opts[opt] = string(changefeedbase.OptEnvelopeWrapped)
+		case changefeedbase.OptEnvelopeDebezium:
+			details.Opts[opt] = string(changefeedbase.OptEnvelopeDebezium)
+			// Debezium change events always carry the before-image of the row,
+			// which requires the previous values of every row.
+			details.Opts[changefeedbase.OptDiff] = ``
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
 				`unknown %s: %s`, opt, v)
@@ -844,8 +888,11 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 					changefeedbase.OptFormat, changefeedbase.OptFormatCSV, changefeedbase.OptInitialScan)
 			}
 			details.Opts[opt] = string(changefeedbase.OptFormatCSV)
-		case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro:
+		case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro,
+			changefeedbase.OptFormatProtobuf, changefeedbase.OptFormatJSONSchema:
 			// No-op.
+		case changefeedbase.OptFormatParquet:
+			details.Opts[opt] = string(changefeedbase.OptFormatParquet)
 		default:
 			return jobspb.ChangefeedDetails{}, errors.Errorf(
 				`unknown %s: %s`, opt, v)
@@ -898,6 +945,30 @@ func validateDetails(details jobspb.ChangefeedDetails) (jobspb.ChangefeedDetails
 	return details, nil
 }
 
+// validateBackfillChangedColumns returns an error if the changefeed cannot use
+// the backfill_changed_columns schema change policy, whose backfills emit
+// partial rows and whose schema change events are only encoded in JSON.
+func validateBackfillChangedColumns(details jobspb.ChangefeedDetails, sinkURL *url.URL) error {
+	policy := fmt.Sprintf(`%s=%s`, changefeedbase.OptSchemaChangePolicy,
+		changefeedbase.OptSchemaChangePolicyBackfillChangedColumns)
+	if format := changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]); format != changefeedbase.OptFormatJSON {
+		return errors.Errorf(`%s is only usable with %s=%s`,
+			policy, changefeedbase.OptFormat, changefeedbase.OptFormatJSON)
+	}
+	if isPostgresSink(sinkURL) {
+		return errors.Errorf(`%s is not usable with postgres sinks`, policy)
+	}
+	if _, split := details.Opts[changefeedbase.OptSplitColumnFamilies]; split {
+		return errors.Errorf(`%s cannot be used with %s`, policy, changefeedbase.OptSplitColumnFamilies)
+	}
+	for _, target := range AllTargets(details) {
+		if target.Type == jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY {
+			return errors.Errorf(`%s cannot be used with column family targets`, policy)
+		}
+	}
+	return nil
+}
+
 func validatePrimaryKeyFilterExpression(
 	ctx context.Context,
 	execCtx sql.JobExecContext,
@@ -337,6 +337,143 @@ func TestChangefeedDiff(t *testing.T) {
 	cdcTest(t, testFn)
 }
 
+func TestChangefeedDebezium(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH envelope=debezium`)
+		defer closeFeed(t, foo)
+
+		// The timestamps in the values are stripped, and the cluster is checked
+		// to be set before it is stripped.
+		assertDebezium := func(expected []string) {
+			t.Helper()
+			msgs, err := readNextMessages(foo, len(expected))
+			require.NoError(t, err)
+			var actual []string
+			for _, m := range msgs {
+				if len(m.Value) == 0 {
+					actual = append(actual, fmt.Sprintf(`%s: %s->`, m.Topic, m.Key))
+					continue
+				}
+				var value map[string]interface{}
+				require.NoError(t, json.Unmarshal(m.Value, &value))
+				delete(value, `ts_ms`)
+				source := value[`source`].(map[string]interface{})
+				require.NotEmpty(t, source[`cluster`])
+				for _, k := range []string{`cluster`, `ts_ms`, `mvcc_timestamp`} {
+					delete(source, k)
+				}
+				formatted, err := reformatJSON(value)
+				require.NoError(t, err)
+				actual = append(actual, fmt.Sprintf(`%s: %s->%s`, m.Topic, m.Key, formatted))
+			}
+			require.Equal(t, expected, actual)
+		}
+		source := func(snapshot bool) string {
+			return fmt.Sprintf(`"source": {"connector": "cockroachdb", "db": "d", "schema": "public", `+
+				`"snapshot": "%t", "table": "foo"}`, snapshot)
+		}
+
+		assertDebezium([]string{
+			`foo: [0]->{"after": {"a": 0, "b": "initial"}, "before": null, "op": "r", ` + source(true) + `}`,
+		})
+
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+		assertDebezium([]string{
+			`foo: [1]->{"after": {"a": 1, "b": "a"}, "before": null, "op": "c", ` + source(false) + `}`,
+		})
+
+		sqlDB.Exec(t, `UPDATE foo SET b = 'b' WHERE a = 1`)
+		assertDebezium([]string{
+			`foo: [1]->{"after": {"a": 1, "b": "b"}, "before": {"a": 1, "b": "a"}, "op": "u", ` + source(false) + `}`,
+		})
+
+		// Deletes are followed by a tombstone.
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+		assertDebezium([]string{
+			`foo: [1]->{"after": null, "before": {"a": 1, "b": "b"}, "op": "d", ` + source(false) + `}`,
+			`foo: [1]->`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestRestrictSinks("kafka", "sinkless"))
+}
+
+func TestChangefeedParquet(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// readPayloads reads n rows from the feed. The timestamp columns are not
+	// deterministic, so they are checked for presence and removed.
+	readPayloads := func(t *testing.T, f cdctest.TestFeed, n int, timestampCols ...string) []string {
+		var payloads []string
+		for len(payloads) < n {
+			m, err := f.Next()
+			require.NoError(t, err)
+			if len(m.Value) == 0 {
+				continue
+			}
+			var row map[string]interface{}
+			require.NoError(t, json.Unmarshal(m.Value, &row))
+			for _, col := range timestampCols {
+				require.NotEmpty(t, row[col], "%s is missing from %s", col, m.Value)
+				delete(row, col)
+			}
+			value, err := json.Marshal(row)
+			require.NoError(t, err)
+			payloads = append(payloads, fmt.Sprintf("%s: %s", m.Topic, value))
+		}
+		sort.Strings(payloads)
+		return payloads
+	}
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (0, 'initial')`)
+
+		t.Run("diff", func(t *testing.T) {
+			foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH format = parquet, diff`)
+			defer closeFeed(t, foo)
+
+			require.Equal(t, []string{
+				`foo: {"__crdb__event_type":"insert","a":0,"b":"initial"}`,
+			}, readPayloads(t, foo, 1, `__crdb__mvcc_timestamp`))
+
+			sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a')`)
+			sqlDB.Exec(t, `UPDATE foo SET b = 'updated' WHERE a = 0`)
+			require.Equal(t, []string{
+				`foo: {"__crdb__event_type":"insert","a":1,"b":"a"}`,
+				`foo: {"__crdb__event_type":"update","a":0,"b":"updated"}`,
+			}, readPayloads(t, foo, 2, `__crdb__mvcc_timestamp`))
+
+			sqlDB.Exec(t, `DELETE FROM foo WHERE a = 1`)
+			require.Equal(t, []string{
+				`foo: {"__crdb__event_type":"delete","a":1}`,
+			}, readPayloads(t, foo, 1, `__crdb__mvcc_timestamp`))
+		})
+
+		t.Run("updated", func(t *testing.T) {
+			foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH format = parquet, updated, compression = gzip`)
+			defer closeFeed(t, foo)
+
+			sqlDB.Exec(t, `UPSERT INTO foo VALUES (2, 'b')`)
+			require.Equal(t, []string{
+				`foo: {"__crdb__event_type":"upsert","a":0,"b":"updated"}`,
+				`foo: {"__crdb__event_type":"upsert","a":2,"b":"b"}`,
+			}, readPayloads(t, foo, 2, `__crdb__mvcc_timestamp`, `__crdb__updated`))
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("cloudstorage"))
+}
+
 func TestChangefeedTenants(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -1678,6 +1815,53 @@ func TestChangefeedSchemaChangeAllowBackfill(t *testing.T) {
 	}
 }
 
+func TestChangefeedSchemaChangeBackfillChangedColumns(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		disableDeclarativeSchemaChangesForTest(t, sqlDB)
+
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, '1'), (2, '2')`)
+		foo := feed(t, f, `CREATE CHANGEFEED FOR foo WITH updated, schema_change_policy='backfill_changed_columns'`)
+		defer closeFeed(t, foo)
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "1"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": "2"}}`,
+		})
+
+		// The rows rewritten by the schema change's backfill are not emitted.
+		// The changefeed level backfill for an added column only emits the
+		// primary key and the added column, after a compact schema change
+		// message.
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN c INT DEFAULT 7`)
+		assertPayloadsPerKeyOrderedStripTs(t, foo, []string{
+			`foo: []->{"schema_change": {"added_columns": ["c"], "dropped_columns": [], "table": "foo"}}`,
+			`foo: [1]->{"after": {"a": 1, "c": 7}}`,
+			`foo: [2]->{"after": {"a": 2, "c": 7}}`,
+		})
+
+		// Rows which change afterwards are emitted in full.
+		sqlDB.Exec(t, `UPDATE foo SET b = 'uno' WHERE a = 1`)
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "uno", "c": 7}}`,
+		})
+
+		// A dropped column is announced without a backfill of the rows, and
+		// the rows rewritten by the schema change are not emitted either.
+		sqlDB.Exec(t, `ALTER TABLE foo DROP COLUMN b`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 3)`)
+		assertPayloadsPerKeyOrderedStripTs(t, foo, []string{
+			`foo: []->{"schema_change": {"added_columns": [], "dropped_columns": ["b"], "table": "foo"}}`,
+			`foo: [3]->{"after": {"a": 3, "c": 3}}`,
+		})
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
 // Test schema changes that require a backfill on only some watched tables within a changefeed.
 func TestChangefeedSchemaChangeBackfillScope(t *testing.T) {
 	defer leaktest.AfterTest(t)()
@@ -1787,7 +1971,7 @@ func fetchDescVersionModificationTime(
 			if err := value.GetProto(&desc); err != nil {
 				t.Fatal(err)
 			}
-			if tableDesc, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, k.Timestamp); tableDesc != nil {
+			if tableDesc, _, _, _, _ := descpb.FromDescriptorWithMVCCTimestamp(&desc, k.Timestamp); tableDesc != nil {
 				if int(tableDesc.Version) == version {
 					return tableDesc.ModificationTime
 				}
@@ -3724,6 +3908,26 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH topic_in_value, format='experimental_avro'`,
 		`kafka://nope`,
 	)
+	sqlDB.ExpectErr(
+		t, `WITH option confluent_schema_registry is required for format=protobuf`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format=protobuf`,
+		`kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `envelope=row is not supported with format=protobuf`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format=protobuf, envelope=row, confluent_schema_registry='http://nope'`,
+		`kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `WITH option confluent_schema_registry is required for format=json_schema`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format=json_schema`,
+		`kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `envelope=debezium is not supported with format=json_schema`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format=json_schema, envelope=debezium, confluent_schema_registry='http://nope'`,
+		`kafka://nope`,
+	)
 
 	// The topics option should not be exposed to users since it is used
 	// internally to display topics in the show changefeed jobs query
@@ -3744,6 +3948,31 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH envelope='key_only'`,
 		`experimental-nodelocal://0/bar`,
 	)
+	sqlDB.ExpectErr(
+		t, `unsupported compression codec "brotli"`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH compression='brotli'`,
+		`experimental-nodelocal://0/bar`,
+	)
+	sqlDB.ExpectErr(
+		t, `compression level for "zstd" must be between 1 and 22, found 25`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH compression='zstd', compression_level='25'`,
+		`experimental-nodelocal://0/bar`,
+	)
+	sqlDB.ExpectErr(
+		t, `compression codec "lz4" does not support compression levels`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH compression='lz4', compression_level='3'`,
+		`experimental-nodelocal://0/bar`,
+	)
+	sqlDB.ExpectErr(
+		t, `compression_level requires a compression codec`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH compression_level='3'`,
+		`experimental-nodelocal://0/bar`,
+	)
+	sqlDB.ExpectErr(
+		t, `compression codec "zstd" is not supported with format=parquet`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format='parquet', compression='zstd'`,
+		`experimental-nodelocal://0/bar`,
+	)
 
 	// WITH key_in_value requires envelope=wrapped
 	sqlDB.ExpectErr(
@@ -3861,6 +4090,10 @@ func TestChangefeedErrors(t *testing.T) {
 		t, `format=csv is only usable with initial_scan='only'`,
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH format = csv`, `kafka://nope`,
 	)
+	sqlDB.ExpectErr(
+		t, `format=parquet is only usable with cloud storage sinks`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format = parquet`, `kafka://nope`,
+	)
 
 	var tsCurrent string
 	sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&tsCurrent)
@@ -3961,6 +4194,46 @@ func TestChangefeedErrors(t *testing.T) {
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH compression='gzip'`,
 		`webhook-https://fake-host`,
 	)
+	sqlDB.ExpectErr(
+		t, `format=csv is not usable with postgres sinks`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH format=csv, initial_scan='only'`,
+		`postgres://nope/d`,
+	)
+	sqlDB.ExpectErr(
+		t, `split_column_families is not supported by the postgres sink`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH split_column_families`,
+		`postgres://nope/d`,
+	)
+	sqlDB.ExpectErr(
+		t, `this sink is incompatible with option compression`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH compression='gzip'`,
+		`postgresql://nope/d`,
+	)
+	sqlDB.ExpectErr(
+		t, `flush_weight must be an integer between 1 and 100, found "0"`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH flush_weight='0'`,
+		`kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `invalid throughput_limit`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH throughput_limit='fast'`,
+		`kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `schema_change_policy=backfill_changed_columns is only usable with format=json`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH schema_change_policy='backfill_changed_columns', format=avro, confluent_schema_registry='http://nope'`,
+		`kafka://nope`,
+	)
+	sqlDB.ExpectErr(
+		t, `schema_change_policy=backfill_changed_columns is not usable with postgres sinks`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH schema_change_policy='backfill_changed_columns'`,
+		`postgres://nope/d`,
+	)
+	sqlDB.ExpectErr(
+		t, `schema_change_policy=backfill_changed_columns cannot be used with split_column_families`,
+		`CREATE CHANGEFEED FOR foo INTO $1 WITH schema_change_policy='backfill_changed_columns', split_column_families`,
+		`kafka://nope`,
+	)
 	sqlDB.ExpectErr(
 		t, `max retries must be either a positive int or 'inf' for infinite retries.`,
 		`CREATE CHANGEFEED FOR foo INTO $1 WITH webhook_sink_config='{"Retry": {"Max": "not valid"}}'`,
@@ -5785,6 +6058,60 @@ func TestChangefeedEndTimeWithCursor(t *testing.T) {
 	cdcTest(t, testFn, feedTestEnterpriseSinks)
 }
 
+func TestChangefeedReplayWindow(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		knobs := s.TestingKnobs.
+			DistSQL.(*execinfra.TestingKnobs).
+			Changefeed.(*TestingKnobs)
+		var rangefeedStarted int32
+		knobs.FeedKnobs.OnRangeFeedStart = func(spans []kvcoord.SpanTimePair) {
+			atomic.StoreInt32(&rangefeedStarted, 1)
+		}
+
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'a'), (2, 'b')`)
+
+		var tsCursor string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&tsCursor)
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (1, 'c')`)
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (1, 'd')`)
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'e')`)
+		var tsEnd string
+		sqlDB.QueryRow(t, `SELECT cluster_logical_timestamp()`).Scan(&tsEnd)
+		sqlDB.Exec(t, `UPSERT INTO foo VALUES (3, 'f')`)
+
+		// Every revision in the window is replayed, in order, along with the
+		// revision which preceded it.
+		feed := feed(t, f, `CREATE CHANGEFEED FOR foo WITH cursor = $1, end_time = $2, diff, updated`,
+			tsCursor, tsEnd)
+		defer closeFeed(t, feed)
+		assertPayloadsPerKeyOrderedStripTs(t, feed, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "c"}, "before": {"a": 1, "b": "a"}}`,
+			`foo: [1]->{"after": {"a": 1, "b": "d"}, "before": {"a": 1, "b": "c"}}`,
+			`foo: [2]->{"after": null, "before": {"a": 2, "b": "b"}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "e"}, "before": null}`,
+		})
+
+		testFeed := feed.(cdctest.EnterpriseTestFeed)
+		require.NoError(t, testFeed.WaitForStatus(func(s jobs.Status) bool {
+			return s == jobs.StatusSucceeded
+		}))
+		require.Equal(t, int32(0), atomic.LoadInt32(&rangefeedStarted))
+
+		var emitted int
+		sqlDB.QueryRow(t, `SELECT emitted_messages FROM [SHOW CHANGEFEED JOB $1]`,
+			testFeed.JobID()).Scan(&emitted)
+		require.Equal(t, 4, emitted)
+	}
+
+	cdcTest(t, testFn, feedTestEnterpriseSinks)
+}
+
 func TestChangefeedOnlyInitialScan(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
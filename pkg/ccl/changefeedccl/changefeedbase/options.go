@@ -62,6 +62,7 @@ const (
 	OptMVCCTimestamps           = `mvcc_timestamp`
 	OptDiff                     = `diff`
 	OptCompression              = `compression`
+	OptCompressionLevel         = `compression_level`
 	OptSchemaChangeEvents       = `schema_change_events`
 	OptSchemaChangePolicy       = `schema_change_policy`
 	OptSplitColumnFamilies      = `split_column_families`
@@ -72,6 +73,9 @@ const (
 	OptMetricsScope             = `metrics_label`
 	OptVirtualColumns           = `virtual_columns`
 	OptPrimaryKeyFilter         = `primary_key_filter`
+	OptMemoryQuota              = `memory_quota`
+	OptThroughputLimit          = `throughput_limit`
+	OptFlushWeight              = `flush_weight`
 
 	OptVirtualColumnsOmitted VirtualColumnVisibility = `omitted`
 	OptVirtualColumnsNull    VirtualColumnVisibility = `null`
@@ -86,6 +90,10 @@ const (
 	// OptSchemaChangePolicyBackfill indicates that when a schema change event
 	// occurs, a full table backfill should occur.
 	OptSchemaChangePolicyBackfill SchemaChangePolicy = `backfill`
+	// OptSchemaChangePolicyBackfillChangedColumns indicates that when a column
+	// is added or dropped, an event describing the change should be emitted,
+	// followed by a backfill of only the added columns of each row.
+	OptSchemaChangePolicyBackfillChangedColumns SchemaChangePolicy = `backfill_changed_columns`
 	// OptSchemaChangePolicyNoBackfill indicates that when a schema change event occurs
 	// no backfill should occur and the changefeed should continue.
 	OptSchemaChangePolicyNoBackfill SchemaChangePolicy = `nobackfill`
@@ -116,10 +124,14 @@ const (
 	OptEnvelopeRow           EnvelopeType = `row`
 	OptEnvelopeDeprecatedRow EnvelopeType = `deprecated_row`
 	OptEnvelopeWrapped       EnvelopeType = `wrapped`
+	OptEnvelopeDebezium      EnvelopeType = `debezium`
 
-	OptFormatJSON FormatType = `json`
-	OptFormatAvro FormatType = `avro`
-	OptFormatCSV  FormatType = `csv`
+	OptFormatJSON       FormatType = `json`
+	OptFormatAvro       FormatType = `avro`
+	OptFormatCSV        FormatType = `csv`
+	OptFormatParquet    FormatType = `parquet`
+	OptFormatProtobuf   FormatType = `protobuf`
+	OptFormatJSONSchema FormatType = `json_schema`
 
 	OptOnErrorFail  OnErrorType = `fail`
 	OptOnErrorPause OnErrorType = `pause`
@@ -135,6 +147,8 @@ const (
 	// OptKafkaSinkConfig is a JSON configuration for kafka sink (kafkaSinkConfig).
 	OptKafkaSinkConfig   = `kafka_sink_config`
 	OptWebhookSinkConfig = `webhook_sink_config`
+	// OptPulsarSinkConfig is a JSON configuration for pulsar sink (pulsarSinkConfig).
+	OptPulsarSinkConfig = `pulsar_sink_config`
 
 	// OptSink allows users to alter the Sink URI of an existing changefeed.
 	// Note that this option is only allowed for alter changefeed statements.
@@ -161,6 +175,10 @@ const (
 	SinkSchemeHTTPS                 = `https`
 	SinkSchemeKafka                 = `kafka`
 	SinkSchemeNull                  = `null`
+	SinkSchemePostgres              = `postgres`
+	SinkSchemePostgresql            = `postgresql`
+	SinkSchemePulsar                = `pulsar`
+	SinkSchemePulsarTLS             = `pulsar+ssl`
 	SinkSchemeWebhookHTTP           = `webhook-http`
 	SinkSchemeWebhookHTTPS          = `webhook-https`
 	SinkParamSASLEnabled            = `sasl_enabled`
@@ -168,6 +186,9 @@ const (
 	SinkParamSASLUser               = `sasl_user`
 	SinkParamSASLPassword           = `sasl_password`
 	SinkParamSASLMechanism          = `sasl_mechanism`
+	SinkParamAuthToken              = `auth_token`
+	SinkParamPulsarTenant           = `tenant`
+	SinkParamPulsarNamespace        = `namespace`
 
 	RegistryParamCACert = `ca_cert`
 
@@ -195,6 +216,7 @@ var ChangefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	OptMVCCTimestamps:           sql.KVStringOptRequireNoValue,
 	OptDiff:                     sql.KVStringOptRequireNoValue,
 	OptCompression:              sql.KVStringOptRequireValue,
+	OptCompressionLevel:         sql.KVStringOptRequireValue,
 	OptSchemaChangeEvents:       sql.KVStringOptRequireValue,
 	OptSchemaChangePolicy:       sql.KVStringOptRequireValue,
 	OptSplitColumnFamilies:      sql.KVStringOptRequireNoValue,
@@ -204,12 +226,16 @@ var ChangefeedOptionExpectValues = map[string]sql.KVStringOptValidate{
 	OptProtectDataFromGCOnPause: sql.KVStringOptRequireNoValue,
 	OptKafkaSinkConfig:          sql.KVStringOptRequireValue,
 	OptWebhookSinkConfig:        sql.KVStringOptRequireValue,
+	OptPulsarSinkConfig:         sql.KVStringOptRequireValue,
 	OptWebhookAuthHeader:        sql.KVStringOptRequireValue,
 	OptWebhookClientTimeout:     sql.KVStringOptRequireValue,
 	OptOnError:                  sql.KVStringOptRequireValue,
 	OptMetricsScope:             sql.KVStringOptRequireValue,
 	OptVirtualColumns:           sql.KVStringOptRequireValue,
 	OptPrimaryKeyFilter:         sql.KVStringOptRequireValue,
+	OptMemoryQuota:              sql.KVStringOptRequireValue,
+	OptThroughputLimit:          sql.KVStringOptRequireValue,
+	OptFlushWeight:              sql.KVStringOptRequireValue,
 }
 
 func makeStringSet(opts ...string) map[string]struct{} {
@@ -229,7 +255,8 @@ var CommonOptions = makeStringSet(OptCursor, OptEndTime, OptEnvelope,
 	OptSchemaChangeEvents, OptSchemaChangePolicy,
 	OptProtectDataFromGCOnPause, OptOnError,
 	OptInitialScan, OptNoInitialScan, OptInitialScanOnly,
-	OptMinCheckpointFrequency, OptMetricsScope, OptVirtualColumns, Topics, OptPrimaryKeyFilter)
+	OptMinCheckpointFrequency, OptMetricsScope, OptVirtualColumns, Topics, OptPrimaryKeyFilter,
+	OptMemoryQuota, OptThroughputLimit, OptFlushWeight)
 
 // SQLValidOptions is options exclusive to SQL sink
 var SQLValidOptions map[string]struct{} = nil
@@ -238,7 +265,7 @@ var SQLValidOptions map[string]struct{} = nil
 var KafkaValidOptions = makeStringSet(OptAvroSchemaPrefix, OptConfluentSchemaRegistry, OptKafkaSinkConfig)
 
 // CloudStorageValidOptions is options exclusive to cloud storage sink
-var CloudStorageValidOptions = makeStringSet(OptCompression)
+var CloudStorageValidOptions = makeStringSet(OptCompression, OptCompressionLevel)
 
 // WebhookValidOptions is options exclusive to webhook sink
 var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTimeout, OptWebhookSinkConfig)
@@ -246,6 +273,12 @@ var WebhookValidOptions = makeStringSet(OptWebhookAuthHeader, OptWebhookClientTi
 // PubsubValidOptions is options exclusice to pubsub sink
 var PubsubValidOptions = makeStringSet()
 
+// PulsarValidOptions is options exclusive to pulsar sink
+var PulsarValidOptions = makeStringSet(OptPulsarSinkConfig)
+
+// PostgresValidOptions is options exclusive to postgres sink
+var PostgresValidOptions = makeStringSet()
+
 // CaseInsensitiveOpts options which supports case Insensitive value
 var CaseInsensitiveOpts = makeStringSet(OptFormat, OptEnvelope, OptCompression, OptSchemaChangeEvents, OptSchemaChangePolicy, OptOnError)
 
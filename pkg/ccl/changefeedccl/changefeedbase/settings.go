@@ -50,6 +50,18 @@ var PerChangefeedMemLimit = settings.RegisterByteSizeSetting(
 	1<<30,
 )
 
+// SinkFlushConcurrency controls how many sink flushes the changefeeds on a node
+// may perform concurrently.
+var SinkFlushConcurrency = settings.RegisterIntSetting(
+	settings.TenantWritable,
+	"changefeed.sink_flush_concurrency",
+	"controls the number of concurrent sink flushes of the changefeeds on a node, "+
+		"which are shared among the changefeeds in proportion to their flush_weight; "+
+		"0 disables the limit",
+	64,
+	settings.NonNegativeInt,
+)
+
 // SlowSpanLogThreshold controls when we will log slow spans.
 var SlowSpanLogThreshold = settings.RegisterDurationSetting(
 	settings.TenantWritable,
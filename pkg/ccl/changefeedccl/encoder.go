@@ -10,9 +10,11 @@ package changefeedccl
 
 import (
 	"context"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/errors"
@@ -42,6 +44,15 @@ type Encoder interface {
 	EncodeResolvedTimestamp(context.Context, string, hlc.Timestamp) ([]byte, error)
 }
 
+// schemaChangeEncoder is implemented by the encoders of the formats which
+// support the backfill_changed_columns schema change policy.
+type schemaChangeEncoder interface {
+	// EncodeSchemaChange encodes the key and value of a schema change payload.
+	// The key never matches the key of a row. The returned bytes are only valid
+	// until the next call to Encode*.
+	EncodeSchemaChange(context.Context, kvevent.SchemaChange) (key, value []byte, _ error)
+}
+
 func getEncoder(
 	opts map[string]string, targets []jobspb.ChangefeedTargetSpecification,
 ) (Encoder, error) {
@@ -50,9 +61,64 @@ func getEncoder(
 		return makeJSONEncoder(opts, targets)
 	case changefeedbase.OptFormatAvro, changefeedbase.DeprecatedOptFormatAvro:
 		return newConfluentAvroEncoder(opts, targets)
+	case changefeedbase.OptFormatProtobuf:
+		return newConfluentProtobufEncoder(opts, targets)
+	case changefeedbase.OptFormatJSONSchema:
+		return newConfluentJSONSchemaEncoder(opts, targets)
 	case changefeedbase.OptFormatCSV:
 		return newCSVEncoder(opts), nil
+	case changefeedbase.OptFormatParquet:
+		return parquetEncoder{}, nil
 	default:
 		return nil, errors.Errorf(`unknown %s: %s`, changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
 	}
 }
+
+// debeziumConnector is the connector named in the source metadata of
+// envelope=debezium change events.
+const debeziumConnector = `cockroachdb`
+
+// debeziumOp returns the operation of the envelope=debezium change event for
+// the given row: `r` for rows read by an initial scan or backfill, `c` for
+// inserts, `u` for updates and `d` for deletes.
+func debeziumOp(evCtx eventContext, updatedRow, prevRow cdcevent.Row) string {
+	switch {
+	case updatedRow.IsDeleted():
+		return `d`
+	case evCtx.backfill:
+		return `r`
+	case prevRow.HasValues() && !prevRow.IsDeleted():
+		return `u`
+	default:
+		return `c`
+	}
+}
+
+// debeziumSource returns the source metadata of the envelope=debezium change
+// event for the given row. The database and schema are the ones the table was
+// in when the changefeed was created.
+func debeziumSource(
+	evCtx eventContext, row cdcevent.Row, targets []jobspb.ChangefeedTargetSpecification,
+) map[string]interface{} {
+	var db, schema string
+	for _, target := range targets {
+		if target.TableID == row.TableID {
+			db, schema = target.StatementTimeDatabaseName, target.StatementTimeSchemaName
+			break
+		}
+	}
+	snapshot := `false`
+	if evCtx.backfill {
+		snapshot = `true`
+	}
+	return map[string]interface{}{
+		`connector`:      debeziumConnector,
+		`cluster`:        evCtx.cluster,
+		`db`:             db,
+		`schema`:         schema,
+		`table`:          row.TableName,
+		`ts_ms`:          evCtx.updated.WallTime / int64(time.Millisecond),
+		`snapshot`:       snapshot,
+		`mvcc_timestamp`: evCtx.mvcc.AsOfSystemTime(),
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
@@ -19,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/util/cache"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -29,13 +31,13 @@ const (
 
 // confluentAvroEncoder encodes changefeed entries as Avro's binary or textual
 // JSON format. Keys are the primary key columns in a record. Values are all
-// columns in a record.
+// columns in a record, or Debezium change events with envelope=debezium.
 type confluentAvroEncoder struct {
-	schemaRegistry                     schemaRegistry
-	schemaPrefix                       string
-	updatedField, beforeField, keyOnly bool
-	virtualColumnVisibility            string
-	targets                            []jobspb.ChangefeedTargetSpecification
+	schemaRegistry                               schemaRegistry
+	schemaPrefix                                 string
+	updatedField, beforeField, keyOnly, debezium bool
+	virtualColumnVisibility                      string
+	targets                                      []jobspb.ChangefeedTargetSpecification
 
 	keyCache   *cache.UnorderedCache // [tableIDAndVersion]confluentRegisteredKeySchema
 	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]confluentRegisteredEnvelopeSchema
@@ -85,6 +87,8 @@ func newConfluentAvroEncoder(
 	case string(changefeedbase.OptEnvelopeKeyOnly):
 		e.keyOnly = true
 	case string(changefeedbase.OptEnvelopeWrapped):
+	case string(changefeedbase.OptEnvelopeDebezium):
+		e.debezium = true
 	default:
 		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
 			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope], changefeedbase.OptFormat, changefeedbase.OptFormatAvro)
@@ -95,6 +99,9 @@ func newConfluentAvroEncoder(
 			changefeedbase.OptUpdatedTimestamps, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 	}
 	_, e.beforeField = opts[changefeedbase.OptDiff]
+	// Debezium change events always have a before field, which is null when the
+	// previous values are unknown.
+	e.beforeField = e.beforeField || e.debezium
 	if e.beforeField && e.keyOnly {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptDiff, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
@@ -128,19 +135,27 @@ func newConfluentAvroEncoder(
 // Get the raw SQL-formatted string for a table name
 // and apply full_table_name and avro_schema_prefix options
 func (e *confluentAvroEncoder) rawTableName(eventMeta cdcevent.Metadata) (string, error) {
-	for _, target := range e.targets {
+	return confluentRawTableName(e.targets, e.schemaPrefix, eventMeta)
+}
+
+// confluentRawTableName returns the raw SQL-formatted name of the table of
+// the event, which names its schemas and subjects in the schema registry.
+func confluentRawTableName(
+	targets []jobspb.ChangefeedTargetSpecification, schemaPrefix string, eventMeta cdcevent.Metadata,
+) (string, error) {
+	for _, target := range targets {
 		if target.TableID == eventMeta.TableID {
 			switch target.Type {
 			case jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY:
-				return e.schemaPrefix + target.StatementTimeName, nil
+				return schemaPrefix + target.StatementTimeName, nil
 			case jobspb.ChangefeedTargetSpecification_EACH_FAMILY:
-				return fmt.Sprintf("%s%s.%s", e.schemaPrefix, target.StatementTimeName, eventMeta.FamilyName), nil
+				return fmt.Sprintf("%s%s.%s", schemaPrefix, target.StatementTimeName, eventMeta.FamilyName), nil
 			case jobspb.ChangefeedTargetSpecification_COLUMN_FAMILY:
 				if eventMeta.FamilyName != target.FamilyName {
 					// Not the right target specification for this family
 					continue
 				}
-				return fmt.Sprintf("%s%s.%s", e.schemaPrefix, target.StatementTimeName, target.FamilyName), nil
+				return fmt.Sprintf("%s%s.%s", schemaPrefix, target.StatementTimeName, target.FamilyName), nil
 			default:
 				// fall through to error
 			}
@@ -150,6 +165,14 @@ func (e *confluentAvroEncoder) rawTableName(eventMeta cdcevent.Metadata) (string
 	return eventMeta.TableName, errors.Newf("Could not find TargetSpecification for %s", eventMeta)
 }
 
+// confluentSubject returns the schema registry subject of the schemas of the
+// named table or topic with the given suffix.
+func confluentSubject(name, suffix string) string {
+	// NB: This uses the kafka name escaper because it has to match the name
+	// of the kafka topic.
+	return SQLNameToKafkaName(name) + suffix
+}
+
 // EncodeKey implements the Encoder interface.
 func (e *confluentAvroEncoder) EncodeKey(ctx context.Context, row cdcevent.Row) ([]byte, error) {
 	// No familyID in the cache key for keys because it's the same schema for all families
@@ -173,9 +196,7 @@ func (e *confluentAvroEncoder) EncodeKey(ctx context.Context, row cdcevent.Row)
 			return nil, err
 		}
 
-		// NB: This uses the kafka name escaper because it has to match the name
-		// of the kafka topic.
-		subject := SQLNameToKafkaName(tableName) + confluentSubjectSuffixKey
+		subject := confluentSubject(tableName, confluentSubjectSuffixKey)
 		registered.registryID, err = e.register(ctx, &registered.schema.avroRecord, subject)
 		if err != nil {
 			return nil, err
@@ -230,6 +251,14 @@ func (e *confluentAvroEncoder) EncodeValue(
 			if err != nil {
 				return nil, err
 			}
+		} else if e.debezium {
+			// Without the previous values, the before field of Debezium change
+			// events is always null but still needs a schema.
+			var err error
+			beforeDataSchema, err = tableToAvroSchema(updatedRow, `before`, e.schemaPrefix)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		afterDataSchema, err := tableToAvroSchema(updatedRow, avroSchemaNoSuffix, e.schemaPrefix)
@@ -237,7 +266,12 @@ func (e *confluentAvroEncoder) EncodeValue(
 			return nil, err
 		}
 
-		opts := avroEnvelopeOpts{afterField: true, beforeField: e.beforeField, updatedField: e.updatedField}
+		opts := avroEnvelopeOpts{
+			afterField:     true,
+			beforeField:    e.beforeField,
+			updatedField:   e.updatedField,
+			debeziumFields: e.debezium,
+		}
 		name, err := e.rawTableName(updatedRow.Metadata)
 		if err != nil {
 			return nil, err
@@ -248,9 +282,7 @@ func (e *confluentAvroEncoder) EncodeValue(
 			return nil, err
 		}
 
-		// NB: This uses the kafka name escaper because it has to match the name
-		// of the kafka topic.
-		subject := SQLNameToKafkaName(name) + confluentSubjectSuffixValue
+		subject := confluentSubject(name, confluentSubjectSuffixValue)
 		registered.registryID, err = e.register(ctx, &registered.schema.avroRecord, subject)
 		if err != nil {
 			return nil, err
@@ -258,11 +290,14 @@ func (e *confluentAvroEncoder) EncodeValue(
 		e.valueCache.Add(cacheKey, registered)
 	}
 
-	var meta avroMetadata
+	meta := avroMetadata{}
 	if registered.schema.opts.updatedField {
-		meta = map[string]interface{}{
-			`updated`: evCtx.updated,
-		}
+		meta[`updated`] = evCtx.updated
+	}
+	if registered.schema.opts.debeziumFields {
+		meta[`source`] = debeziumSource(evCtx, updatedRow, e.targets)
+		meta[`op`] = debeziumOp(evCtx, updatedRow, prevRow)
+		meta[`ts_ms`] = timeutil.Now().UnixNano() / int64(time.Millisecond)
 	}
 
 	// https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
@@ -287,9 +322,7 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 			return nil, err
 		}
 
-		// NB: This uses the kafka name escaper because it has to match the name
-		// of the kafka topic.
-		subject := SQLNameToKafkaName(topic) + confluentSubjectSuffixValue
+		subject := confluentSubject(topic, confluentSubjectSuffixValue)
 		registered.registryID, err = e.register(ctx, &registered.schema.avroRecord, subject)
 		if err != nil {
 			return nil, err
@@ -316,5 +349,6 @@ func (e *confluentAvroEncoder) EncodeResolvedTimestamp(
 func (e *confluentAvroEncoder) register(
 	ctx context.Context, schema *avroRecord, subject string,
 ) (int32, error) {
-	return e.schemaRegistry.RegisterSchemaForSubject(ctx, subject, schema.codec.Schema())
+	return e.schemaRegistry.RegisterSchemaForSubject(
+		ctx, subject, schema.codec.Schema(), confluentSchemaTypeAvro)
 }
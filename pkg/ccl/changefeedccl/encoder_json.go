@@ -16,12 +16,14 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 )
 
@@ -29,8 +31,10 @@ import (
 // columns in a JSON array. Values are a JSON object mapping every column name
 // to its value. Updated timestamps in rows and resolved timestamp payloads are
 // stored in a sub-object under the `__crdb__` key in the top-level JSON object.
+// With envelope=debezium, values follow the Debezium change event format
+// instead.
 type jsonEncoder struct {
-	updatedField, mvccTimestampField, beforeField, wrapped, keyOnly, keyInValue, topicInValue bool
+	updatedField, mvccTimestampField, beforeField, wrapped, debezium, keyOnly, keyInValue, topicInValue bool
 
 	targets                 []jobspb.ChangefeedTargetSpecification
 	buf                     bytes.Buffer
@@ -46,12 +50,13 @@ func makeJSONEncoder(
 		targets:                 targets,
 		keyOnly:                 changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeKeyOnly,
 		wrapped:                 changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeWrapped,
+		debezium:                changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeDebezium,
 		virtualColumnVisibility: opts[changefeedbase.OptVirtualColumns],
 	}
 	_, e.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
 	_, e.mvccTimestampField = opts[changefeedbase.OptMVCCTimestamps]
 	_, e.beforeField = opts[changefeedbase.OptDiff]
-	if e.beforeField && !e.wrapped {
+	if e.beforeField && !e.wrapped && !e.debezium {
 		return nil, errors.Errorf(`%s is only usable with %s=%s`,
 			changefeedbase.OptDiff, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
 	}
@@ -119,7 +124,7 @@ func rowAsGoNative(row cdcevent.Row) (map[string]interface{}, error) {
 func (e *jsonEncoder) EncodeValue(
 	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
 ) ([]byte, error) {
-	if e.keyOnly || (!e.wrapped && updatedRow.IsDeleted()) {
+	if e.keyOnly || (!e.wrapped && !e.debezium && updatedRow.IsDeleted()) {
 		return nil, nil
 	}
 
@@ -157,13 +162,27 @@ func (e *jsonEncoder) EncodeValue(
 		if e.topicInValue {
 			jsonEntries[`topic`] = evCtx.topic
 		}
+	} else if e.debezium {
+		jsonEntries = map[string]interface{}{
+			`before`: nil,
+			`after`:  nil,
+			`source`: debeziumSource(evCtx, updatedRow, e.targets),
+			`op`:     debeziumOp(evCtx, updatedRow, prevRow),
+			`ts_ms`:  timeutil.Now().UnixNano() / int64(time.Millisecond),
+		}
+		if before != nil {
+			jsonEntries[`before`] = before
+		}
+		if after != nil {
+			jsonEntries[`after`] = after
+		}
 	} else {
 		jsonEntries = after
 	}
 
 	if e.updatedField || e.mvccTimestampField {
 		var meta map[string]interface{}
-		if e.wrapped {
+		if e.wrapped || e.debezium {
 			meta = jsonEntries
 		} else {
 			meta = make(map[string]interface{}, 1)
@@ -194,7 +213,7 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 		`resolved`: eval.TimestampToDecimalDatum(resolved).Decimal.String(),
 	}
 	var jsonEntries interface{}
-	if e.wrapped {
+	if e.wrapped || e.debezium {
 		jsonEntries = meta
 	} else {
 		jsonEntries = map[string]interface{}{
@@ -203,3 +222,39 @@ func (e *jsonEncoder) EncodeResolvedTimestamp(
 	}
 	return gojson.Marshal(jsonEntries)
 }
+
+// EncodeSchemaChange implements the schemaChangeEncoder interface.
+func (e *jsonEncoder) EncodeSchemaChange(
+	_ context.Context, sc kvevent.SchemaChange,
+) (key, value []byte, _ error) {
+	added, dropped := sc.AddedColumns, sc.DroppedColumns
+	if added == nil {
+		added = []string{}
+	}
+	if dropped == nil {
+		dropped = []string{}
+	}
+	meta := map[string]interface{}{
+		`schema_change`: map[string]interface{}{
+			`table`:           sc.TableName,
+			`added_columns`:   added,
+			`dropped_columns`: dropped,
+		},
+		`updated`: eval.TimestampToDecimalDatum(sc.Timestamp).Decimal.String(),
+	}
+	var jsonEntries interface{}
+	if e.wrapped || e.debezium {
+		jsonEntries = meta
+	} else {
+		jsonEntries = map[string]interface{}{
+			jsonMetaSentinel: meta,
+		}
+	}
+	// Row keys always hold the primary key columns, so an empty key array
+	// distinguishes schema changes from rows.
+	value, err := gojson.Marshal(jsonEntries)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []byte(`[]`), value, nil
+}
@@ -0,0 +1,306 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gojson "encoding/json"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+)
+
+const jsonSchemaDraft = `http://json-schema.org/draft-07/schema#`
+
+// confluentJSONSchemaEncoder encodes changefeed entries as the JSON of
+// format=json in the confluent wire format. The JSON Schemas of the entries
+// are generated from the table descriptors and registered with a confluent
+// schema registry.
+type confluentJSONSchemaEncoder struct {
+	jsonEncoder    *jsonEncoder
+	schemaRegistry schemaRegistry
+
+	keyCache   *cache.UnorderedCache // [tableIDAndVersion]int32
+	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]int32
+
+	// resolvedCache doesn't need to be bounded like the other caches because
+	// the number of topics is fixed per changefeed.
+	resolvedCache map[string]int32
+}
+
+var _ Encoder = &confluentJSONSchemaEncoder{}
+
+func newConfluentJSONSchemaEncoder(
+	opts map[string]string, targets []jobspb.ChangefeedTargetSpecification,
+) (*confluentJSONSchemaEncoder, error) {
+	if changefeedbase.EnvelopeType(opts[changefeedbase.OptEnvelope]) == changefeedbase.OptEnvelopeDebezium {
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope], changefeedbase.OptFormat, changefeedbase.OptFormatJSONSchema)
+	}
+	if len(opts[changefeedbase.OptConfluentSchemaRegistry]) == 0 {
+		return nil, errors.Errorf(`WITH option %s is required for %s=%s`,
+			changefeedbase.OptConfluentSchemaRegistry, changefeedbase.OptFormat, changefeedbase.OptFormatJSONSchema)
+	}
+	jsonEncoder, err := makeJSONEncoder(opts, targets)
+	if err != nil {
+		return nil, err
+	}
+	reg, err := newConfluentSchemaRegistry(opts[changefeedbase.OptConfluentSchemaRegistry])
+	if err != nil {
+		return nil, err
+	}
+	return &confluentJSONSchemaEncoder{
+		jsonEncoder:    jsonEncoder,
+		schemaRegistry: reg,
+		keyCache:       cache.NewUnorderedCache(encoderCacheConfig),
+		valueCache:     cache.NewUnorderedCache(encoderCacheConfig),
+		resolvedCache:  make(map[string]int32),
+	}, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *confluentJSONSchemaEncoder) EncodeKey(
+	ctx context.Context, row cdcevent.Row,
+) ([]byte, error) {
+	// No familyID in the cache key for keys because it's the same schema for all families
+	cacheKey := tableIDAndVersion{tableID: row.TableID, version: row.Version}
+
+	var registryID int32
+	if v, ok := e.keyCache.Get(cacheKey); ok {
+		registryID = v.(int32)
+	} else {
+		tableName, err := confluentRawTableName(e.jsonEncoder.targets, `` /* schemaPrefix */, row.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := jsonSchemaForKey(row)
+		if err != nil {
+			return nil, err
+		}
+		schema[`$schema`] = jsonSchemaDraft
+		schema[`title`] = SQLNameToAvroName(tableName) + `_key`
+
+		subject := confluentSubject(tableName, confluentSubjectSuffixKey)
+		if registryID, err = e.register(ctx, subject, schema); err != nil {
+			return nil, err
+		}
+		e.keyCache.Add(cacheKey, registryID)
+	}
+
+	key, err := e.jsonEncoder.EncodeKey(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	return append(confluentWireHeader(registryID), key...), nil
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *confluentJSONSchemaEncoder) EncodeValue(
+	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) ([]byte, error) {
+	value, err := e.jsonEncoder.EncodeValue(ctx, evCtx, updatedRow, prevRow)
+	if err != nil || value == nil {
+		return value, err
+	}
+
+	// The before field is declared even if the previous values are unknown,
+	// with the columns of the updated row.
+	beforeRow := updatedRow
+	if prevRow.IsInitialized() {
+		beforeRow = prevRow
+	}
+
+	var cacheKey tableIDAndVersionPair
+	if e.jsonEncoder.beforeField {
+		cacheKey[0] = tableIDAndVersion{
+			tableID: beforeRow.TableID, version: beforeRow.Version, familyID: beforeRow.FamilyID,
+		}
+	}
+	cacheKey[1] = tableIDAndVersion{
+		tableID: updatedRow.TableID, version: updatedRow.Version, familyID: updatedRow.FamilyID,
+	}
+
+	var registryID int32
+	if v, ok := e.valueCache.Get(cacheKey); ok {
+		registryID = v.(int32)
+	} else {
+		name, err := confluentRawTableName(e.jsonEncoder.targets, `` /* schemaPrefix */, updatedRow.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := e.valueSchema(updatedRow, beforeRow)
+		if err != nil {
+			return nil, err
+		}
+		schema[`$schema`] = jsonSchemaDraft
+		schema[`title`] = SQLNameToAvroName(name) + `_envelope`
+
+		subject := confluentSubject(name, confluentSubjectSuffixValue)
+		if registryID, err = e.register(ctx, subject, schema); err != nil {
+			return nil, err
+		}
+		e.valueCache.Add(cacheKey, registryID)
+	}
+	return append(confluentWireHeader(registryID), value...), nil
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *confluentJSONSchemaEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	registryID, ok := e.resolvedCache[topic]
+	if !ok {
+		schema := e.metaSchema(map[string]interface{}{
+			`resolved`: map[string]interface{}{`type`: `string`},
+		})
+		schema[`$schema`] = jsonSchemaDraft
+		schema[`title`] = SQLNameToAvroName(topic) + `_envelope`
+
+		subject := confluentSubject(topic, confluentSubjectSuffixValue)
+		var err error
+		if registryID, err = e.register(ctx, subject, schema); err != nil {
+			return nil, err
+		}
+		e.resolvedCache[topic] = registryID
+	}
+
+	value, err := e.jsonEncoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+	if err != nil {
+		return nil, err
+	}
+	return append(confluentWireHeader(registryID), value...), nil
+}
+
+func (e *confluentJSONSchemaEncoder) register(
+	ctx context.Context, subject string, schema map[string]interface{},
+) (int32, error) {
+	b, err := gojson.Marshal(schema)
+	if err != nil {
+		return 0, err
+	}
+	return e.schemaRegistry.RegisterSchemaForSubject(ctx, subject, string(b), confluentSchemaTypeJSONSchema)
+}
+
+// valueSchema returns the JSON Schema of the values of format=json for the
+// updated and previous rows.
+func (e *confluentJSONSchemaEncoder) valueSchema(
+	updatedRow, beforeRow cdcevent.Row,
+) (map[string]interface{}, error) {
+	after, err := jsonSchemaForColumns(updatedRow.ForEachColumn())
+	if err != nil {
+		return nil, err
+	}
+	meta := make(map[string]interface{})
+	if e.jsonEncoder.updatedField {
+		meta[`updated`] = map[string]interface{}{`type`: `string`}
+	}
+	if e.jsonEncoder.mvccTimestampField {
+		meta[`mvcc_timestamp`] = map[string]interface{}{`type`: `string`}
+	}
+	if !e.jsonEncoder.wrapped {
+		if len(meta) > 0 {
+			after[jsonMetaSentinel] = map[string]interface{}{`type`: `object`, `properties`: meta}
+		}
+		return map[string]interface{}{`type`: `object`, `properties`: after}, nil
+	}
+
+	props := meta
+	props[`after`] = map[string]interface{}{`type`: []string{`object`, `null`}, `properties`: after}
+	if e.jsonEncoder.beforeField {
+		before, err := jsonSchemaForColumns(beforeRow.ForEachColumn())
+		if err != nil {
+			return nil, err
+		}
+		props[`before`] = map[string]interface{}{`type`: []string{`object`, `null`}, `properties`: before}
+	}
+	if e.jsonEncoder.keyInValue {
+		if props[`key`], err = jsonSchemaForKey(updatedRow); err != nil {
+			return nil, err
+		}
+	}
+	if e.jsonEncoder.topicInValue {
+		props[`topic`] = map[string]interface{}{`type`: `string`}
+	}
+	return map[string]interface{}{`type`: `object`, `properties`: props}, nil
+}
+
+// metaSchema returns the JSON Schema of an object with the given metadata
+// properties, which are nested under the `__crdb__` key unless the envelope
+// is wrapped.
+func (e *confluentJSONSchemaEncoder) metaSchema(meta map[string]interface{}) map[string]interface{} {
+	if e.jsonEncoder.wrapped {
+		return map[string]interface{}{`type`: `object`, `properties`: meta}
+	}
+	return map[string]interface{}{
+		`type`: `object`,
+		`properties`: map[string]interface{}{
+			jsonMetaSentinel: map[string]interface{}{`type`: `object`, `properties`: meta},
+		},
+	}
+}
+
+// jsonSchemaForKey returns the JSON Schema of the JSON array of the primary
+// key columns of the row.
+func jsonSchemaForKey(row cdcevent.Row) (map[string]interface{}, error) {
+	var items []interface{}
+	if err := row.ForEachKeyColumn().Col(func(col cdcevent.ResultColumn) error {
+		item := jsonSchemaForType(col.Typ, false /* nullable */)
+		item[`title`] = col.Name
+		items = append(items, item)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{`type`: `array`, `items`: items}, nil
+}
+
+// jsonSchemaForColumns returns the JSON Schemas of the columns, keyed by their
+// names.
+func jsonSchemaForColumns(it cdcevent.Iterator) (map[string]interface{}, error) {
+	props := make(map[string]interface{})
+	err := it.Col(func(col cdcevent.ResultColumn) error {
+		props[col.Name] = jsonSchemaForType(col.Typ, true /* nullable */)
+		return nil
+	})
+	return props, err
+}
+
+// jsonSchemaForType returns the JSON Schema of the JSON encoding of datums of
+// the given type. Types whose datums may be encoded as any JSON, such as
+// JSONB, have an empty schema.
+func jsonSchemaForType(typ *types.T, nullable bool) map[string]interface{} {
+	var jsonType string
+	schema := make(map[string]interface{})
+	switch typ.Family() {
+	case types.BoolFamily:
+		jsonType = `boolean`
+	case types.IntFamily:
+		jsonType = `integer`
+	case types.FloatFamily, types.DecimalFamily:
+		jsonType = `number`
+	case types.ArrayFamily:
+		jsonType = `array`
+		schema[`items`] = jsonSchemaForType(typ.ArrayContents(), true /* nullable */)
+	case types.JsonFamily, types.TupleFamily, types.GeometryFamily, types.GeographyFamily:
+		return schema
+	default:
+		jsonType = `string`
+	}
+	if nullable {
+		schema[`type`] = []string{jsonType, `null`}
+	} else {
+		schema[`type`] = jsonType
+	}
+	return schema
+}
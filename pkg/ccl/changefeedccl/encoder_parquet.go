@@ -0,0 +1,222 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gojson "encoding/json"
+	"io"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/sql/parquetenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// The names of the metadata columns which are added to every row written in
+// the parquet format.
+const (
+	parquetEventTypeColName     = `__crdb__event_type`
+	parquetMVCCTimestampColName = `__crdb__mvcc_timestamp`
+	parquetUpdatedColName       = `__crdb__updated`
+)
+
+// The values of the event type column. An upsert is emitted if the changefeed
+// does not have the diff option, since insertions can then not be told apart
+// from updates.
+const (
+	parquetEventTypeInsert = `insert`
+	parquetEventTypeUpdate = `update`
+	parquetEventTypeUpsert = `upsert`
+	parquetEventTypeDelete = `delete`
+)
+
+// parquetEncoder is the Encoder for the parquet format. Rows are not encoded
+// one at a time, since a parquet file is written in column chunks, so rows are
+// handed to the sink directly (see SinkWithEncoder) which batches them into a
+// parquetWriter per file. The encoder is only used for resolved timestamps.
+type parquetEncoder struct{}
+
+var _ Encoder = parquetEncoder{}
+
+// EncodeKey implements the Encoder interface.
+func (parquetEncoder) EncodeKey(context.Context, cdcevent.Row) ([]byte, error) {
+	return nil, errors.AssertionFailedf("rows are encoded by the sink in the %s format",
+		changefeedbase.OptFormatParquet)
+}
+
+// EncodeValue implements the Encoder interface.
+func (parquetEncoder) EncodeValue(
+	context.Context, eventContext, cdcevent.Row, cdcevent.Row,
+) ([]byte, error) {
+	return nil, errors.AssertionFailedf("rows are encoded by the sink in the %s format",
+		changefeedbase.OptFormatParquet)
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface. Resolved
+// timestamps are written in the same JSON format as the wrapped envelope uses.
+func (parquetEncoder) EncodeResolvedTimestamp(
+	_ context.Context, _ string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	return gojson.Marshal(map[string]interface{}{
+		`resolved`: eval.TimestampToDecimalDatum(resolved).Decimal.String(),
+	})
+}
+
+// parquetWriter writes the rows of a single table and schema version to a
+// parquet file. The schema of the file is built from the columns of the first
+// row, followed by the metadata columns.
+type parquetWriter struct {
+	// columns maps the name of a column of the row to the parquet column it is
+	// written to. Columns are looked up by name since the rows of a projection
+	// only contain some of the columns, at different ordinals.
+	columns     map[string]parquetenc.Column
+	withUpdated bool
+
+	writer *goparquet.FileWriter
+	record map[string]interface{}
+}
+
+// parquetCompressionCodec returns the parquet compression codec for the given
+// codec. Parquet files are compressed by the parquet writer, which supports
+// fewer codecs than the files of the other formats.
+func parquetCompressionCodec(codec cloud.CompressionCodec) (parquet.CompressionCodec, error) {
+	switch codec {
+	case cloud.NoCompression:
+		return parquet.CompressionCodec_UNCOMPRESSED, nil
+	case cloud.CompressionGzip:
+		return parquet.CompressionCodec_GZIP, nil
+	case cloud.CompressionSnappy:
+		return parquet.CompressionCodec_SNAPPY, nil
+	default:
+		return 0, errors.Errorf(`compression codec %q is not supported with %s=%s`,
+			codec, changefeedbase.OptFormat, changefeedbase.OptFormatParquet)
+	}
+}
+
+// newParquetWriter creates a parquetWriter which writes rows shaped like the
+// given row to w.
+func newParquetWriter(
+	row cdcevent.Row, w io.Writer, compression parquet.CompressionCodec, withUpdated bool,
+) (*parquetWriter, error) {
+	pw := &parquetWriter{
+		columns:     make(map[string]parquetenc.Column),
+		withUpdated: withUpdated,
+	}
+	var schemaColumns []parquetenc.Column
+	addColumn := func(col cdcevent.ResultColumn) error {
+		if _, ok := pw.columns[col.Name]; ok {
+			return nil
+		}
+		// Every column is nullable since the row of a deletion only contains the
+		// primary key.
+		parquetCol, err := parquetenc.NewColumn(col.Typ, col.Name, true /* nullable */)
+		if err != nil {
+			return err
+		}
+		pw.columns[col.Name] = parquetCol
+		schemaColumns = append(schemaColumns, parquetCol)
+		return nil
+	}
+	if err := row.ForEachColumn().Col(addColumn); err != nil {
+		return nil, err
+	}
+	// The primary key columns are always part of the file, even if they are not
+	// in the watched column family.
+	if err := row.ForEachKeyColumn().Col(addColumn); err != nil {
+		return nil, err
+	}
+
+	metaColumns := []string{parquetEventTypeColName, parquetMVCCTimestampColName}
+	if withUpdated {
+		metaColumns = append(metaColumns, parquetUpdatedColName)
+	}
+	for _, name := range metaColumns {
+		parquetCol, err := parquetenc.NewColumn(types.String, name, false /* nullable */)
+		if err != nil {
+			return nil, err
+		}
+		schemaColumns = append(schemaColumns, parquetCol)
+	}
+
+	pw.writer = goparquet.NewFileWriter(w,
+		goparquet.WithCompressionCodec(compression),
+		goparquet.WithSchemaDefinition(parquetenc.NewSchema(schemaColumns)),
+	)
+	pw.record = make(map[string]interface{}, len(schemaColumns))
+	return pw, nil
+}
+
+// addRow buffers the given row in the current row group of the file.
+func (w *parquetWriter) addRow(updatedRow, prevRow cdcevent.Row, updated, mvcc hlc.Timestamp) error {
+	// The record is reused across rows. Clear it so that a column which is not
+	// set for this row, such as a non-key column of a deletion, is written as
+	// NULL instead of keeping the value of the previous row.
+	for k := range w.record {
+		delete(w.record, k)
+	}
+	setDatum := func(d tree.Datum, col cdcevent.ResultColumn) error {
+		parquetCol, ok := w.columns[col.Name]
+		if !ok {
+			return errors.AssertionFailedf("unexpected column %s", col.Name)
+		}
+		v, err := parquetCol.EncodeDatum(d)
+		if err != nil {
+			return err
+		}
+		w.record[parquetCol.Name()] = v
+		return nil
+	}
+	if err := updatedRow.ForEachColumn().Datum(setDatum); err != nil {
+		return err
+	}
+	if err := updatedRow.ForEachKeyColumn().Datum(setDatum); err != nil {
+		return err
+	}
+
+	w.record[parquetEventTypeColName] = []byte(parquetEventType(updatedRow, prevRow))
+	w.record[parquetMVCCTimestampColName] = []byte(eval.TimestampToDecimalDatum(mvcc).Decimal.String())
+	if w.withUpdated {
+		w.record[parquetUpdatedColName] = []byte(eval.TimestampToDecimalDatum(updated).Decimal.String())
+	}
+	return w.writer.AddData(w.record)
+}
+
+// size returns an estimate of the size of the file, including the buffered
+// row group.
+func (w *parquetWriter) size() int64 {
+	return w.writer.CurrentFileSize() + w.writer.CurrentRowGroupSize()
+}
+
+// close flushes the buffered rows and writes the footer of the file.
+func (w *parquetWriter) close() error {
+	return w.writer.Close()
+}
+
+// parquetEventType returns the value of the event type column for the given
+// row.
+func parquetEventType(updatedRow, prevRow cdcevent.Row) string {
+	switch {
+	case updatedRow.IsDeleted():
+		return parquetEventTypeDelete
+	case !prevRow.IsInitialized():
+		return parquetEventTypeUpsert
+	case prevRow.IsDeleted():
+		return parquetEventTypeInsert
+	default:
+		return parquetEventTypeUpdate
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParquetWriterNulls(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c INT)`)
+	require.NoError(t, err)
+	encRows, err := parseValues(tableDesc, `VALUES (1, 'a', NULL), (2, NULL, 2), (3, 'c', NULL), (4, NULL, 4), (5, 'e', 5)`)
+	require.NoError(t, err)
+	var rows []cdcevent.Row
+	for _, encRow := range encRows {
+		rows = append(rows, cdcevent.TestingMakeEventRow(tableDesc, 0, encRow, false))
+	}
+	// The projection of the last row leaves out b, which must be written as
+	// NULL even though the row before it was written with a value for b.
+	projected, err := projectColumns(rows[len(rows)-1], []string{`c`})
+	require.NoError(t, err)
+	rows = append(rows, projected)
+
+	var buf bytes.Buffer
+	w, err := newParquetWriter(rows[0], &buf, parquet.CompressionCodec_UNCOMPRESSED, false /* withUpdated */)
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NoError(t, w.addRow(row, cdcevent.Row{}, hlc.Timestamp{}, hlc.Timestamp{WallTime: 1}))
+	}
+	require.NoError(t, w.close())
+
+	r, err := goparquet.NewFileReader(bytes.NewReader(buf.Bytes()), `a`, `b`, `c`)
+	require.NoError(t, err)
+	var actual []map[string]interface{}
+	for {
+		row, err := r.NextRow()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		actual = append(actual, row)
+	}
+	require.Equal(t, []map[string]interface{}{
+		{`a`: int64(1), `b`: []byte(`a`)},
+		{`a`: int64(2), `c`: int64(2)},
+		{`a`: int64(3), `b`: []byte(`c`)},
+		{`a`: int64(4), `c`: int64(4)},
+		{`a`: int64(5), `b`: []byte(`e`), `c`: int64(5)},
+		{`a`: int64(5), `c`: int64(5)},
+	}, actual)
+}
@@ -0,0 +1,361 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/cache"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The field numbers of the envelope messages. Rows and resolved timestamps
+// share the envelope message, so that every schema registered for the value
+// subject of a topic is compatible with the others.
+const (
+	protobufAfterField         protowire.Number = 1
+	protobufBeforeField        protowire.Number = 2
+	protobufUpdatedField       protowire.Number = 3
+	protobufMVCCTimestampField protowire.Number = 4
+	protobufResolvedField      protowire.Number = 5
+)
+
+// confluentProtobufEncoder encodes changefeed entries as protobuf messages in
+// the confluent wire format. The schemas of the messages are proto3 files
+// generated from the table descriptors and registered with a confluent schema
+// registry. Keys are messages holding the primary key columns. Values are
+// envelope messages holding the row in their after field.
+//
+// The number of the field of every column is its column ID, which is never
+// reused by the table. Hence, the schemas registered as columns are added or
+// dropped are compatible with each other.
+type confluentProtobufEncoder struct {
+	schemaRegistry                                         schemaRegistry
+	targets                                                []jobspb.ChangefeedTargetSpecification
+	updatedField, mvccTimestampField, beforeField, keyOnly bool
+
+	keyCache   *cache.UnorderedCache // [tableIDAndVersion]int32
+	valueCache *cache.UnorderedCache // [tableIDAndVersionPair]int32
+
+	// resolvedCache doesn't need to be bounded like the other caches because
+	// the number of topics is fixed per changefeed.
+	resolvedCache map[string]int32
+}
+
+var _ Encoder = &confluentProtobufEncoder{}
+
+func newConfluentProtobufEncoder(
+	opts map[string]string, targets []jobspb.ChangefeedTargetSpecification,
+) (*confluentProtobufEncoder, error) {
+	e := &confluentProtobufEncoder{targets: targets}
+
+	switch opts[changefeedbase.OptEnvelope] {
+	case string(changefeedbase.OptEnvelopeKeyOnly):
+		e.keyOnly = true
+	case string(changefeedbase.OptEnvelopeWrapped):
+	default:
+		return nil, errors.Errorf(`%s=%s is not supported with %s=%s`,
+			changefeedbase.OptEnvelope, opts[changefeedbase.OptEnvelope], changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	_, e.updatedField = opts[changefeedbase.OptUpdatedTimestamps]
+	if e.updatedField && e.keyOnly {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			changefeedbase.OptUpdatedTimestamps, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
+	_, e.mvccTimestampField = opts[changefeedbase.OptMVCCTimestamps]
+	if e.mvccTimestampField && e.keyOnly {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			changefeedbase.OptMVCCTimestamps, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
+	_, e.beforeField = opts[changefeedbase.OptDiff]
+	if e.beforeField && e.keyOnly {
+		return nil, errors.Errorf(`%s is only usable with %s=%s`,
+			changefeedbase.OptDiff, changefeedbase.OptEnvelope, changefeedbase.OptEnvelopeWrapped)
+	}
+
+	if _, ok := opts[changefeedbase.OptKeyInValue]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptKeyInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	if _, ok := opts[changefeedbase.OptTopicInValue]; ok {
+		return nil, errors.Errorf(`%s is not supported with %s=%s`,
+			changefeedbase.OptTopicInValue, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+	if len(opts[changefeedbase.OptConfluentSchemaRegistry]) == 0 {
+		return nil, errors.Errorf(`WITH option %s is required for %s=%s`,
+			changefeedbase.OptConfluentSchemaRegistry, changefeedbase.OptFormat, changefeedbase.OptFormatProtobuf)
+	}
+
+	reg, err := newConfluentSchemaRegistry(opts[changefeedbase.OptConfluentSchemaRegistry])
+	if err != nil {
+		return nil, err
+	}
+
+	e.schemaRegistry = reg
+	e.keyCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.valueCache = cache.NewUnorderedCache(encoderCacheConfig)
+	e.resolvedCache = make(map[string]int32)
+	return e, nil
+}
+
+// EncodeKey implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeKey(ctx context.Context, row cdcevent.Row) ([]byte, error) {
+	// No familyID in the cache key for keys because it's the same schema for all families
+	cacheKey := tableIDAndVersion{tableID: row.TableID, version: row.Version}
+
+	var registryID int32
+	if v, ok := e.keyCache.Get(cacheKey); ok {
+		registryID = v.(int32)
+	} else {
+		tableName, err := confluentRawTableName(e.targets, `` /* schemaPrefix */, row.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		var schema strings.Builder
+		schema.WriteString(protobufSchemaHeader)
+		fmt.Fprintf(&schema, "message %s_key {\n", SQLNameToAvroName(tableName))
+		if err := writeProtobufColumnFields(&schema, `  `, row.ForEachKeyColumn()); err != nil {
+			return nil, err
+		}
+		schema.WriteString("}\n")
+
+		subject := confluentSubject(tableName, confluentSubjectSuffixKey)
+		registryID, err = e.schemaRegistry.RegisterSchemaForSubject(
+			ctx, subject, schema.String(), confluentSchemaTypeProtobuf)
+		if err != nil {
+			return nil, err
+		}
+		e.keyCache.Add(cacheKey, registryID)
+	}
+
+	return appendProtobufColumns(confluentProtobufHeader(registryID), row.ForEachKeyColumn())
+}
+
+// EncodeValue implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeValue(
+	ctx context.Context, evCtx eventContext, updatedRow cdcevent.Row, prevRow cdcevent.Row,
+) ([]byte, error) {
+	if e.keyOnly {
+		return nil, nil
+	}
+
+	// The before field is declared even if the previous values are unknown,
+	// with the columns of the updated row.
+	beforeRow := updatedRow
+	if prevRow.IsInitialized() {
+		beforeRow = prevRow
+	}
+
+	var cacheKey tableIDAndVersionPair
+	if e.beforeField {
+		cacheKey[0] = tableIDAndVersion{
+			tableID: beforeRow.TableID, version: beforeRow.Version, familyID: beforeRow.FamilyID,
+		}
+	}
+	cacheKey[1] = tableIDAndVersion{
+		tableID: updatedRow.TableID, version: updatedRow.Version, familyID: updatedRow.FamilyID,
+	}
+
+	var registryID int32
+	if v, ok := e.valueCache.Get(cacheKey); ok {
+		registryID = v.(int32)
+	} else {
+		name, err := confluentRawTableName(e.targets, `` /* schemaPrefix */, updatedRow.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		var schema strings.Builder
+		schema.WriteString(protobufSchemaHeader)
+		fmt.Fprintf(&schema, "message %s_envelope {\n", SQLNameToAvroName(name))
+		schema.WriteString("  message After {\n")
+		if err := writeProtobufColumnFields(&schema, `    `, updatedRow.ForEachColumn()); err != nil {
+			return nil, err
+		}
+		schema.WriteString("  }\n")
+		if e.beforeField {
+			schema.WriteString("  message Before {\n")
+			if err := writeProtobufColumnFields(&schema, `    `, beforeRow.ForEachColumn()); err != nil {
+				return nil, err
+			}
+			schema.WriteString("  }\n")
+		}
+		fmt.Fprintf(&schema, "  After after = %d;\n", protobufAfterField)
+		if e.beforeField {
+			fmt.Fprintf(&schema, "  Before before = %d;\n", protobufBeforeField)
+		}
+		if e.updatedField {
+			fmt.Fprintf(&schema, "  optional string updated = %d;\n", protobufUpdatedField)
+		}
+		if e.mvccTimestampField {
+			fmt.Fprintf(&schema, "  optional string mvcc_timestamp = %d;\n", protobufMVCCTimestampField)
+		}
+		schema.WriteString("}\n")
+
+		subject := confluentSubject(name, confluentSubjectSuffixValue)
+		registryID, err = e.schemaRegistry.RegisterSchemaForSubject(
+			ctx, subject, schema.String(), confluentSchemaTypeProtobuf)
+		if err != nil {
+			return nil, err
+		}
+		e.valueCache.Add(cacheKey, registryID)
+	}
+
+	b := confluentProtobufHeader(registryID)
+	var err error
+	if b, err = appendProtobufRow(b, protobufAfterField, updatedRow); err != nil {
+		return nil, err
+	}
+	if e.beforeField {
+		if b, err = appendProtobufRow(b, protobufBeforeField, prevRow); err != nil {
+			return nil, err
+		}
+	}
+	if e.updatedField {
+		b = protowire.AppendTag(b, protobufUpdatedField, protowire.BytesType)
+		b = protowire.AppendString(b, evCtx.updated.AsOfSystemTime())
+	}
+	if e.mvccTimestampField {
+		b = protowire.AppendTag(b, protobufMVCCTimestampField, protowire.BytesType)
+		b = protowire.AppendString(b, evCtx.mvcc.AsOfSystemTime())
+	}
+	return b, nil
+}
+
+// EncodeResolvedTimestamp implements the Encoder interface.
+func (e *confluentProtobufEncoder) EncodeResolvedTimestamp(
+	ctx context.Context, topic string, resolved hlc.Timestamp,
+) ([]byte, error) {
+	registryID, ok := e.resolvedCache[topic]
+	if !ok {
+		var schema strings.Builder
+		schema.WriteString(protobufSchemaHeader)
+		fmt.Fprintf(&schema, "message %s_envelope {\n", SQLNameToAvroName(topic))
+		fmt.Fprintf(&schema, "  optional string resolved = %d;\n", protobufResolvedField)
+		schema.WriteString("}\n")
+
+		subject := confluentSubject(topic, confluentSubjectSuffixValue)
+		var err error
+		registryID, err = e.schemaRegistry.RegisterSchemaForSubject(
+			ctx, subject, schema.String(), confluentSchemaTypeProtobuf)
+		if err != nil {
+			return nil, err
+		}
+		e.resolvedCache[topic] = registryID
+	}
+
+	b := confluentProtobufHeader(registryID)
+	b = protowire.AppendTag(b, protobufResolvedField, protowire.BytesType)
+	return protowire.AppendString(b, resolved.AsOfSystemTime()), nil
+}
+
+const protobufSchemaHeader = "syntax = \"proto3\";\n\n"
+
+// confluentProtobufHeader returns the header of protobuf messages in the
+// confluent wire format. After the schema ID, the header holds the indexes
+// of the message type in the schema, which are always [0], encoded as a
+// single zero byte, since every schema holds a single top-level message.
+func confluentProtobufHeader(registryID int32) []byte {
+	return append(confluentWireHeader(registryID), 0)
+}
+
+// protobufColumnType returns the protobuf type of the field of a column of
+// the given type. Types without a protobuf counterpart are encoded as their
+// string representation.
+func protobufColumnType(typ *types.T) string {
+	switch typ.Family() {
+	case types.BoolFamily:
+		return `bool`
+	case types.IntFamily:
+		return `int64`
+	case types.FloatFamily:
+		return `double`
+	case types.BytesFamily:
+		return `bytes`
+	default:
+		return `string`
+	}
+}
+
+// writeProtobufColumnFields writes the proto3 declarations of the fields of
+// the columns to the schema. The fields are optional, so that NULLs are
+// distinguished from the default values of the fields.
+func writeProtobufColumnFields(schema *strings.Builder, indent string, it cdcevent.Iterator) error {
+	return it.Col(func(col cdcevent.ResultColumn) error {
+		fmt.Fprintf(schema, "%soptional %s %s = %d;\n",
+			indent, protobufColumnType(col.Typ), SQLNameToAvroName(col.Name), col.PGAttributeNum)
+		return nil
+	})
+}
+
+// appendProtobufRow appends the columns of the row to b as the message field
+// with the given number. Nothing is appended if the row has no values or is
+// deleted.
+func appendProtobufRow(b []byte, num protowire.Number, row cdcevent.Row) ([]byte, error) {
+	if !row.HasValues() || row.IsDeleted() {
+		return b, nil
+	}
+	msg, err := appendProtobufColumns(nil, row.ForEachColumn())
+	if err != nil {
+		return nil, err
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg), nil
+}
+
+// appendProtobufColumns appends the fields of the columns to b. NULLs are
+// omitted.
+func appendProtobufColumns(b []byte, it cdcevent.Iterator) ([]byte, error) {
+	err := it.Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		d = tree.UnwrapDOidWrapper(d)
+		if d == tree.DNull {
+			return nil
+		}
+		num := protowire.Number(col.PGAttributeNum)
+		switch protobufColumnType(col.Typ) {
+		case `bool`:
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			b = protowire.AppendVarint(b, protowire.EncodeBool(bool(tree.MustBeDBool(d))))
+		case `int64`:
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			b = protowire.AppendVarint(b, uint64(tree.MustBeDInt(d)))
+		case `double`:
+			b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+			b = protowire.AppendFixed64(b, math.Float64bits(float64(tree.MustBeDFloat(d))))
+		case `bytes`:
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			b = protowire.AppendBytes(b, []byte(tree.MustBeDBytes(d)))
+		default:
+			var s string
+			switch t := d.(type) {
+			case *tree.DString:
+				s = string(*t)
+			case *tree.DCollatedString:
+				s = t.Contents
+			case *tree.DEnum:
+				s = t.LogicalRep
+			default:
+				s = tree.AsStringWithFlags(d, tree.FmtBareStrings)
+			}
+			b = protowire.AppendTag(b, num, protowire.BytesType)
+			b = protowire.AppendString(b, s)
+		}
+		return nil
+	})
+	return b, err
+}
@@ -11,6 +11,7 @@ package changefeedccl
 import (
 	"context"
 	gosql "database/sql"
+	gojson "encoding/json"
 	"fmt"
 	"net/url"
 	"testing"
@@ -252,6 +253,153 @@ func TestEncoders(t *testing.T) {
 	}
 }
 
+func TestDebeziumEncoders(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	tableDesc, err := parseTableDesc(`CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+	require.NoError(t, err)
+	row := func(b string) rowenc.EncDatumRow {
+		return rowenc.EncDatumRow{
+			rowenc.EncDatum{Datum: tree.NewDInt(1)},
+			rowenc.EncDatum{Datum: tree.NewDString(b)},
+		}
+	}
+	targets := []jobspb.ChangefeedTargetSpecification{{
+		Type:                      jobspb.ChangefeedTargetSpecification_PRIMARY_FAMILY_ONLY,
+		TableID:                   tableDesc.GetID(),
+		StatementTimeName:         tableDesc.GetName(),
+		StatementTimeDatabaseName: `d`,
+		StatementTimeSchemaName:   `public`,
+	}}
+	evCtx := eventContext{
+		updated: hlc.Timestamp{WallTime: 2e6, Logical: 1},
+		mvcc:    hlc.Timestamp{WallTime: 1e6},
+		cluster: `c`,
+	}
+	backfillCtx := evCtx
+	backfillCtx.backfill = true
+
+	for _, tc := range []struct {
+		name          string
+		evCtx         eventContext
+		updated, prev cdcevent.Row
+		json, avro    string
+	}{
+		{
+			name:    `insert`,
+			evCtx:   evCtx,
+			updated: cdcevent.TestingMakeEventRow(tableDesc, 0, row(`bar`), false),
+			prev:    cdcevent.TestingMakeEventRow(tableDesc, 0, row(`bar`), true),
+			json: `{"after": {"a": 1, "b": "bar"}, "before": null, "op": "c", "source": {"cluster": "c", ` +
+				`"connector": "cockroachdb", "db": "d", "mvcc_timestamp": "1000000.0000000000", "schema": "public", ` +
+				`"snapshot": "false", "table": "foo", "ts_ms": 2}}`,
+			avro: `{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},"before":null,"op":{"string":"c"},` +
+				`"source":{"debezium_source":{"cluster":{"string":"c"},"connector":{"string":"cockroachdb"},` +
+				`"db":{"string":"d"},"mvcc_timestamp":{"string":"1000000.0000000000"},"schema":{"string":"public"},` +
+				`"snapshot":{"string":"false"},"table":{"string":"foo"},"ts_ms":{"long":2}}}}`,
+		},
+		{
+			name:    `update`,
+			evCtx:   evCtx,
+			updated: cdcevent.TestingMakeEventRow(tableDesc, 0, row(`baz`), false),
+			prev:    cdcevent.TestingMakeEventRow(tableDesc, 0, row(`bar`), false),
+			json: `{"after": {"a": 1, "b": "baz"}, "before": {"a": 1, "b": "bar"}, "op": "u", "source": {"cluster": "c", ` +
+				`"connector": "cockroachdb", "db": "d", "mvcc_timestamp": "1000000.0000000000", "schema": "public", ` +
+				`"snapshot": "false", "table": "foo", "ts_ms": 2}}`,
+			avro: `{"after":{"foo":{"a":{"long":1},"b":{"string":"baz"}}},` +
+				`"before":{"foo_before":{"a":{"long":1},"b":{"string":"bar"}}},"op":{"string":"u"},` +
+				`"source":{"debezium_source":{"cluster":{"string":"c"},"connector":{"string":"cockroachdb"},` +
+				`"db":{"string":"d"},"mvcc_timestamp":{"string":"1000000.0000000000"},"schema":{"string":"public"},` +
+				`"snapshot":{"string":"false"},"table":{"string":"foo"},"ts_ms":{"long":2}}}}`,
+		},
+		{
+			name:    `delete`,
+			evCtx:   evCtx,
+			updated: cdcevent.TestingMakeEventRow(tableDesc, 0, row(`baz`), true),
+			prev:    cdcevent.TestingMakeEventRow(tableDesc, 0, row(`baz`), false),
+			json: `{"after": null, "before": {"a": 1, "b": "baz"}, "op": "d", "source": {"cluster": "c", ` +
+				`"connector": "cockroachdb", "db": "d", "mvcc_timestamp": "1000000.0000000000", "schema": "public", ` +
+				`"snapshot": "false", "table": "foo", "ts_ms": 2}}`,
+			avro: `{"after":null,"before":{"foo_before":{"a":{"long":1},"b":{"string":"baz"}}},"op":{"string":"d"},` +
+				`"source":{"debezium_source":{"cluster":{"string":"c"},"connector":{"string":"cockroachdb"},` +
+				`"db":{"string":"d"},"mvcc_timestamp":{"string":"1000000.0000000000"},"schema":{"string":"public"},` +
+				`"snapshot":{"string":"false"},"table":{"string":"foo"},"ts_ms":{"long":2}}}}`,
+		},
+		{
+			name:    `backfill`,
+			evCtx:   backfillCtx,
+			updated: cdcevent.TestingMakeEventRow(tableDesc, 0, row(`bar`), false),
+			json: `{"after": {"a": 1, "b": "bar"}, "before": null, "op": "r", "source": {"cluster": "c", ` +
+				`"connector": "cockroachdb", "db": "d", "mvcc_timestamp": "1000000.0000000000", "schema": "public", ` +
+				`"snapshot": "true", "table": "foo", "ts_ms": 2}}`,
+			avro: `{"after":{"foo":{"a":{"long":1},"b":{"string":"bar"}}},"before":null,"op":{"string":"r"},` +
+				`"source":{"debezium_source":{"cluster":{"string":"c"},"connector":{"string":"cockroachdb"},` +
+				`"db":{"string":"d"},"mvcc_timestamp":{"string":"1000000.0000000000"},"schema":{"string":"public"},` +
+				`"snapshot":{"string":"true"},"table":{"string":"foo"},"ts_ms":{"long":2}}}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			// ts_ms is the time the event was encoded, so it is checked and removed
+			// before comparing the rest of the value.
+			checkValue := func(t *testing.T, value []byte, expected string, tsMs func(interface{}) int64) {
+				var decoded map[string]interface{}
+				require.NoError(t, gojson.Unmarshal(value, &decoded))
+				require.Less(t, int64(0), tsMs(decoded[`ts_ms`]))
+				delete(decoded, `ts_ms`)
+				actual, err := gojson.Marshal(decoded)
+				require.NoError(t, err)
+				var expectedDecoded map[string]interface{}
+				require.NoError(t, gojson.Unmarshal([]byte(expected), &expectedDecoded))
+				expectedJSON, err := gojson.Marshal(expectedDecoded)
+				require.NoError(t, err)
+				require.Equal(t, string(expectedJSON), string(actual))
+			}
+
+			t.Run(`json`, func(t *testing.T) {
+				opts := map[string]string{
+					changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+					changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeDebezium),
+				}
+				e, err := getEncoder(opts, targets)
+				require.NoError(t, err)
+				value, err := e.EncodeValue(ctx, tc.evCtx, tc.updated, tc.prev)
+				require.NoError(t, err)
+				checkValue(t, value, tc.json, func(v interface{}) int64 { return int64(v.(float64)) })
+			})
+
+			t.Run(`avro`, func(t *testing.T) {
+				reg := cdctest.StartTestSchemaRegistry()
+				defer reg.Close()
+				opts := map[string]string{
+					changefeedbase.OptFormat:                  string(changefeedbase.OptFormatAvro),
+					changefeedbase.OptEnvelope:                string(changefeedbase.OptEnvelopeDebezium),
+					changefeedbase.OptConfluentSchemaRegistry: reg.URL(),
+				}
+				e, err := getEncoder(opts, targets)
+				require.NoError(t, err)
+				value, err := e.EncodeValue(ctx, tc.evCtx, tc.updated, tc.prev)
+				require.NoError(t, err)
+				checkValue(t, avroToJSON(t, reg, value), tc.avro, func(v interface{}) int64 {
+					return int64(v.(map[string]interface{})[`long`].(float64))
+				})
+			})
+		})
+	}
+
+	// Debezium change events are incompatible with the options which add fields
+	// to the wrapped envelope.
+	for _, opt := range []string{changefeedbase.OptKeyInValue, changefeedbase.OptTopicInValue} {
+		_, err := getEncoder(map[string]string{
+			changefeedbase.OptFormat:   string(changefeedbase.OptFormatJSON),
+			changefeedbase.OptEnvelope: string(changefeedbase.OptEnvelopeDebezium),
+			opt:                        ``,
+		}, targets)
+		require.EqualError(t, err, fmt.Sprintf(`%s is only usable with envelope=wrapped`, opt))
+	}
+}
+
 func TestAvroEncoder(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -303,6 +451,154 @@ func TestAvroEncoder(t *testing.T) {
 	cdcTest(t, testFn, feedTestForceSink("kafka"))
 }
 
+func TestProtobufEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c FLOAT, d BYTES, e BOOL, f DECIMAL)`)
+		var ts1 string
+		sqlDB.QueryRow(t,
+			`INSERT INTO foo VALUES (1, 'bar', 1.5, 'baz', true, 2.50), (2, NULL, NULL, NULL, NULL, NULL)
+RETURNING cluster_logical_timestamp()`,
+		).Scan(&ts1)
+
+		foo := feed(t, f, fmt.Sprintf(`CREATE CHANGEFEED FOR foo `+
+			`WITH format=%s, diff, resolved`, changefeedbase.OptFormatProtobuf))
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: {"a":1}->{"after":{"a":1,"b":"bar","c":1.5,"d":"YmF6","e":true,"f":"2.50"},"before":null}`,
+			`foo: {"a":2}->{"after":{"a":2,"b":null,"c":null,"d":null,"e":null,"f":null},"before":null}`,
+		})
+		resolved, _ := expectResolvedTimestamp(t, foo)
+		if ts := parseTimeToHLC(t, ts1); resolved.LessEq(ts) {
+			t.Fatalf(`expected a resolved timestamp greater than %s got %s`, ts, resolved)
+		}
+
+		sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+		assertPayloads(t, foo, []string{
+			`foo: {"a":2}->{"after":null,"before":{"a":2,"b":null,"c":null,"d":null,"e":null,"f":null}}`,
+		})
+
+		reg := foo.(*kafkaFeed).registry
+		assertRegisteredSubjects(t, reg, []string{`foo-key`, `foo-value`})
+		require.Equal(t, `PROTOBUF`, reg.SchemaTypeForSubject(`foo-key`))
+		require.Equal(t, `syntax = "proto3";
+
+message foo_key {
+  optional int64 a = 1;
+}
+`, reg.SchemaForSubject(`foo-key`))
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestProtobufSchemaEvolution(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'bar')`)
+
+		foo := feed(t, f, fmt.Sprintf(`CREATE CHANGEFEED FOR foo `+
+			`WITH format=%s, updated`, changefeedbase.OptFormatProtobuf))
+		defer closeFeed(t, foo)
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: {"a":1}->{"after": {"a": 1, "b": "bar"}}`,
+		})
+		reg := foo.(*kafkaFeed).registry
+
+		// Every column keeps its field number, so that the schemas registered as
+		// columns are added and dropped are compatible with each other.
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN c INT`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (2, 'baz', 3)`)
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: {"a":1}->{"after": {"a": 1, "b": "bar", "c": null}}`,
+			`foo: {"a":2}->{"after": {"a": 2, "b": "baz", "c": 3}}`,
+		})
+		require.Equal(t, `syntax = "proto3";
+
+message foo_envelope {
+  message After {
+    optional int64 a = 1;
+    optional string b = 2;
+    optional int64 c = 3;
+  }
+  After after = 1;
+  optional string updated = 3;
+}
+`, reg.SchemaForSubject(`foo-value`))
+
+		sqlDB.Exec(t, `ALTER TABLE foo DROP COLUMN b`)
+		assertPayloadsStripTs(t, foo, []string{
+			`foo: {"a":1}->{"after": {"a": 1, "c": null}}`,
+			`foo: {"a":2}->{"after": {"a": 2, "c": 3}}`,
+		})
+		require.Equal(t, `syntax = "proto3";
+
+message foo_envelope {
+  message After {
+    optional int64 a = 1;
+    optional int64 c = 3;
+  }
+  After after = 1;
+  optional string updated = 3;
+}
+`, reg.SchemaForSubject(`foo-value`))
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
+func TestJSONSchemaEncoder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testFn := func(t *testing.T, s TestServer, f cdctest.TestFeedFactory) {
+		sqlDB := sqlutils.MakeSQLRunner(s.DB)
+		sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING)`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 'bar'), (2, NULL)`)
+
+		foo := feed(t, f, fmt.Sprintf(`CREATE CHANGEFEED FOR foo `+
+			`WITH format=%s, diff, resolved`, changefeedbase.OptFormatJSONSchema))
+		defer closeFeed(t, foo)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "bar"}, "before": null}`,
+			`foo: [2]->{"after": {"a": 2, "b": null}, "before": null}`,
+		})
+		expectResolvedTimestamp(t, foo)
+
+		reg := foo.(*kafkaFeed).registry
+		assertRegisteredSubjects(t, reg, []string{`foo-key`, `foo-value`})
+		require.Equal(t, `JSON`, reg.SchemaTypeForSubject(`foo-value`))
+		require.Equal(t, `{"$schema":"http://json-schema.org/draft-07/schema#",`+
+			`"items":[{"title":"a","type":"integer"}],"title":"foo_key","type":"array"}`,
+			reg.SchemaForSubject(`foo-key`))
+
+		// Adding a column registers a new version of the value schema.
+		sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN c INT`)
+		sqlDB.Exec(t, `INSERT INTO foo VALUES (3, 'baz', 3)`)
+		assertPayloads(t, foo, []string{
+			`foo: [1]->{"after": {"a": 1, "b": "bar", "c": null}, "before": {"a": 1, "b": "bar"}}`,
+			`foo: [2]->{"after": {"a": 2, "b": null, "c": null}, "before": {"a": 2, "b": null}}`,
+			`foo: [3]->{"after": {"a": 3, "b": "baz", "c": 3}, "before": null}`,
+		})
+		require.Equal(t, `{"$schema":"http://json-schema.org/draft-07/schema#","properties":{`+
+			`"after":{"properties":{"a":{"type":["integer","null"]},"b":{"type":["string","null"]},`+
+			`"c":{"type":["integer","null"]}},"type":["object","null"]},`+
+			`"before":{"properties":{"a":{"type":["integer","null"]},"b":{"type":["string","null"]},`+
+			`"c":{"type":["integer","null"]}},"type":["object","null"]}},`+
+			`"title":"foo_envelope","type":"object"}`,
+			reg.SchemaForSubject(`foo-value`))
+	}
+
+	cdcTest(t, testFn, feedTestForceSink("kafka"))
+}
+
 func TestAvroEncoderWithTLS(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -16,7 +16,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -29,6 +32,12 @@ type eventContext struct {
 	updated, mvcc hlc.Timestamp
 	// topic is set to the string to be included if TopicInValue is true
 	topic string
+	// backfill is true if the event was read by an initial scan or a schema
+	// change backfill rather than emitted by a rangefeed.
+	backfill bool
+	// cluster is set to the logical cluster ID if the envelope includes it in
+	// the source metadata of events.
+	cluster string
 }
 
 type kvEventToRowConsumer struct {
@@ -40,9 +49,29 @@ type kvEventToRowConsumer struct {
 	knobs    TestingKnobs
 	decoder  cdcevent.Decoder
 	details  jobspb.ChangefeedDetails
+	envelope changefeedbase.EnvelopeType
+	// encodeRows is set if rows are emitted to a sink which encodes them.
+	encodeRows bool
+	// cluster is the logical cluster ID, which is only set if the envelope
+	// includes it in events.
+	cluster string
 
 	topicDescriptorCache map[TopicIdentifier]TopicDescriptor
 	topicNamer           *TopicNamer
+
+	// backfillColumns holds the latest schema change of each table, whose
+	// backfilled rows are limited to the added columns.
+	backfillColumns map[descpb.ID]*kvevent.SchemaChange
+	// skipUnchangedRows is set under the backfill_changed_columns policy, where
+	// the rows whose columns are unchanged are not emitted. Such rows are
+	// rewritten by the backfills of schema changes, and only hold changes to
+	// columns which aren't visible yet or anymore.
+	skipUnchangedRows bool
+	// evalCtx is used to compare the datums of rows with their previous values.
+	evalCtx eval.Context
+
+	// emittedMessages is the number of messages emitted to the sink.
+	emittedMessages uint64
 }
 
 func newKVEventToRowConsumer(
@@ -61,6 +90,11 @@ func newKVEventToRowConsumer(
 	if err != nil {
 		return nil, err
 	}
+	envelope := changefeedbase.EnvelopeType(details.Opts[changefeedbase.OptEnvelope])
+	var cluster string
+	if envelope == changefeedbase.OptEnvelopeDebezium {
+		cluster = cfg.LogicalClusterID.Get().String()
+	}
 	return &kvEventToRowConsumer{
 		frontier:             frontier,
 		encoder:              encoder,
@@ -68,9 +102,15 @@ func newKVEventToRowConsumer(
 		sink:                 sink,
 		cursor:               cursor,
 		details:              details,
+		envelope:             envelope,
+		encodeRows:           sinkEncodesRows(details),
+		cluster:              cluster,
 		knobs:                knobs,
 		topicDescriptorCache: make(map[TopicIdentifier]TopicDescriptor),
 		topicNamer:           topicNamer,
+		backfillColumns:      make(map[descpb.ID]*kvevent.SchemaChange),
+		skipUnchangedRows: changefeedbase.SchemaChangePolicy(details.Opts[changefeedbase.OptSchemaChangePolicy]) ==
+			changefeedbase.OptSchemaChangePolicyBackfillChangedColumns,
 	}, nil
 }
 
@@ -119,8 +159,9 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 	}
 
 	// Get prev value, if necessary.
+	_, withDiff := c.details.Opts[changefeedbase.OptDiff]
 	prevRow, err := func() (cdcevent.Row, error) {
-		if _, withDiff := c.details.Opts[changefeedbase.OptDiff]; !withDiff {
+		if !withDiff && !c.skipUnchangedRows {
 			return cdcevent.Row{}, nil
 		}
 		prevKV := roachpb.KeyValue{Key: ev.KV().Key, Value: ev.PrevValue()}
@@ -135,6 +176,34 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		return err
 	}
 
+	if c.skipUnchangedRows && ev.BackfillTimestamp().IsEmpty() {
+		unchanged, err := c.rowUnchanged(updatedRow, prevRow)
+		if err != nil {
+			return err
+		}
+		if unchanged {
+			a := ev.DetachAlloc()
+			a.Release(ctx)
+			return nil
+		}
+	}
+	if !withDiff {
+		prevRow = cdcevent.Row{}
+	}
+
+	// The rows backfilled for a schema change under the backfill_changed_columns
+	// policy only include the columns which the schema change added.
+	if sc, ok := c.backfillColumns[updatedRow.TableID]; ok && sc.Timestamp.Equal(ev.BackfillTimestamp()) {
+		if updatedRow, err = projectColumns(updatedRow, sc.AddedColumns); err != nil {
+			return err
+		}
+		if prevRow.IsInitialized() {
+			if prevRow, err = projectColumns(prevRow, sc.AddedColumns); err != nil {
+				return err
+			}
+		}
+	}
+
 	topic, err := c.topicForEvent(updatedRow.Metadata)
 	if err != nil {
 		return err
@@ -153,8 +222,10 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 	}
 
 	evCtx := eventContext{
-		updated: schemaTimestamp,
-		mvcc:    mvccTimestamp,
+		updated:  schemaTimestamp,
+		mvcc:     mvccTimestamp,
+		backfill: !ev.BackfillTimestamp().IsEmpty(),
+		cluster:  c.cluster,
 	}
 
 	if c.topicNamer != nil {
@@ -165,6 +236,10 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 		evCtx.topic = topic
 	}
 
+	if c.encodeRows {
+		return c.encodeAndEmitRow(ctx, updatedRow, prevRow, topic, schemaTimestamp, mvccTimestamp, ev)
+	}
+
 	var keyCopy, valueCopy []byte
 	encodedKey, err := c.encoder.EncodeKey(ctx, updatedRow)
 	if err != nil {
@@ -182,14 +257,165 @@ func (c *kvEventToRowConsumer) ConsumeEvent(ctx context.Context, ev kvevent.Even
 			return err
 		}
 	}
+	alloc := ev.DetachAlloc()
+	// Debezium consumers expect a delete event to be followed by a tombstone,
+	// which is a message with the same key and a null value, so that log
+	// compaction can eventually remove every message for the key. The memory
+	// of the event is released once the tombstone is emitted.
+	tombstone := c.envelope == changefeedbase.OptEnvelopeDebezium && updatedRow.IsDeleted()
+	if tombstone {
+		if err := c.sink.EmitRow(
+			ctx, topic,
+			keyCopy, valueCopy, schemaTimestamp, mvccTimestamp, kvevent.Alloc{},
+		); err != nil {
+			return err
+		}
+		c.emittedMessages++
+		valueCopy = nil
+	}
 	if err := c.sink.EmitRow(
 		ctx, topic,
-		keyCopy, valueCopy, schemaTimestamp, mvccTimestamp, ev.DetachAlloc(),
+		keyCopy, valueCopy, schemaTimestamp, mvccTimestamp, alloc,
 	); err != nil {
 		return err
 	}
+	c.emittedMessages++
 	if log.V(3) {
 		log.Infof(ctx, `r %s: %s -> %s`, updatedRow.TableName, keyCopy, valueCopy)
 	}
 	return nil
 }
+
+// ConsumeSchemaChange records the columns which the rows backfilled for a
+// schema change are limited to, and emits the schema change to the sink if
+// this aggregator announces it.
+func (c *kvEventToRowConsumer) ConsumeSchemaChange(ctx context.Context, ev kvevent.Event) error {
+	if ev.Type() != kvevent.TypeSchemaChange {
+		return errors.AssertionFailedf("expected schema change ev, got %v", ev.Type())
+	}
+	sc := ev.SchemaChange()
+	c.backfillColumns[sc.TableID] = sc
+	if !sc.Announce {
+		a := ev.DetachAlloc()
+		a.Release(ctx)
+		return nil
+	}
+
+	encoder, ok := c.encoder.(schemaChangeEncoder)
+	if !ok {
+		return errors.AssertionFailedf("%T cannot encode schema changes", c.encoder)
+	}
+	topic, err := c.topicForEvent(cdcevent.Metadata{
+		TableID:   sc.TableID,
+		TableName: sc.TableName,
+		Version:   sc.Version,
+		SchemaTS:  sc.Timestamp,
+	})
+	if err != nil {
+		return err
+	}
+	encodedKey, encodedValue, err := encoder.EncodeSchemaChange(ctx, *sc)
+	if err != nil {
+		return err
+	}
+	var keyCopy, valueCopy []byte
+	c.scratch, keyCopy = c.scratch.Copy(encodedKey, 0 /* extraCap */)
+	c.scratch, valueCopy = c.scratch.Copy(encodedValue, 0 /* extraCap */)
+	if err := c.sink.EmitRow(
+		ctx, topic, keyCopy, valueCopy, sc.Timestamp, sc.Timestamp, ev.DetachAlloc(),
+	); err != nil {
+		return err
+	}
+	c.emittedMessages++
+	return nil
+}
+
+// rowUnchanged returns whether the row has a previous value with the same
+// datums in every column.
+func (c *kvEventToRowConsumer) rowUnchanged(updatedRow, prevRow cdcevent.Row) (bool, error) {
+	if !prevRow.IsInitialized() || prevRow.IsDeleted() || updatedRow.IsDeleted() {
+		return false, nil
+	}
+	var prevDatums []tree.Datum
+	if err := prevRow.ForEachColumn().Datum(func(d tree.Datum, _ cdcevent.ResultColumn) error {
+		prevDatums = append(prevDatums, d)
+		return nil
+	}); err != nil {
+		return false, err
+	}
+	unchanged, i := true, 0
+	if err := updatedRow.ForEachColumn().Datum(func(d tree.Datum, _ cdcevent.ResultColumn) error {
+		if i >= len(prevDatums) || d.Compare(&c.evalCtx, prevDatums[i]) != 0 {
+			unchanged = false
+		}
+		i++
+		return nil
+	}); err != nil {
+		return false, err
+	}
+	return unchanged && i == len(prevDatums), nil
+}
+
+// projectColumns returns the projection of the row onto its primary key and
+// the given columns. The primary key columns are part of both the key and the
+// value of the projection.
+func projectColumns(row cdcevent.Row, columns []string) (cdcevent.Row, error) {
+	p := cdcevent.MakeProjection(row.EventDescriptor)
+	keyColumns := make(map[string]struct{})
+	if err := row.ForEachKeyColumn().Col(func(col cdcevent.ResultColumn) error {
+		keyColumns[col.Name] = struct{}{}
+		return nil
+	}); err != nil {
+		return cdcevent.Row{}, err
+	}
+	var datums []tree.Datum
+	if err := row.ForEachColumn().Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		include := false
+		if _, ok := keyColumns[col.Name]; ok {
+			include = true
+		}
+		for _, name := range columns {
+			if col.Name == name {
+				include = true
+			}
+		}
+		if include {
+			p.AddValueColumn(col.Name, col.Typ)
+			datums = append(datums, d)
+		}
+		return nil
+	}); err != nil {
+		return cdcevent.Row{}, err
+	}
+	for i, d := range datums {
+		if err := p.SetValueDatumAt(i, d); err != nil {
+			return cdcevent.Row{}, err
+		}
+	}
+	return p.Project(row)
+}
+
+// encodeAndEmitRow emits the row to a sink which encodes rows itself, which is
+// required by formats that cannot be encoded one row at a time.
+func (c *kvEventToRowConsumer) encodeAndEmitRow(
+	ctx context.Context,
+	updatedRow, prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	ev kvevent.Event,
+) error {
+	sink, ok := c.sink.(SinkWithEncoder)
+	if !ok {
+		return errors.AssertionFailedf("expected a sink which encodes rows, found %T", c.sink)
+	}
+	if c.knobs.BeforeEmitRow != nil {
+		if err := c.knobs.BeforeEmitRow(ctx); err != nil {
+			return err
+		}
+	}
+	if err := sink.EncodeAndEmitRow(ctx, updatedRow, prevRow, topic, updated, mvcc, ev.DetachAlloc()); err != nil {
+		return err
+	}
+	c.emittedMessages++
+	return nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/log/logcrash"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/aggmetric"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
@@ -31,6 +32,7 @@ type blockingBuffer struct {
 	metrics  *Metrics
 	qp       allocPool     // Pool for memory allocations.
 	signalCh chan struct{} // Signal when new events are available.
+	limit    int64         // Maximum allocation of a single event, if non-zero.
 
 	mu struct {
 		syncutil.Mutex
@@ -48,6 +50,27 @@ type blockingBuffer struct {
 // account, an error will be returned when attempting to buffer it.
 func NewMemBuffer(
 	acc mon.BoundAccount, sv *settings.Values, metrics *Metrics, opts ...quotapool.Option,
+) Buffer {
+	return NewMemBufferWithQuota(acc, sv, metrics, MemQuota{}, opts...)
+}
+
+// MemQuota configures the memory quota of a changefeed's buffer.
+type MemQuota struct {
+	// Limit is the largest number of bytes the buffer allocates for a single
+	// event. If zero, changefeed.memory.per_changefeed_limit is used.
+	Limit int64
+	// Usage, if non-nil, tracks the bytes currently allocated by the buffer.
+	Usage *aggmetric.Gauge
+}
+
+// NewMemBufferWithQuota is like NewMemBuffer, but the buffer abides by the
+// specified memory quota rather than the cluster wide per changefeed limit.
+func NewMemBufferWithQuota(
+	acc mon.BoundAccount,
+	sv *settings.Values,
+	metrics *Metrics,
+	quota MemQuota,
+	opts ...quotapool.Option,
 ) Buffer {
 	const slowAcquisitionThreshold = 5 * time.Second
 
@@ -62,10 +85,11 @@ func NewMemBuffer(
 		signalCh: make(chan struct{}, 1),
 		metrics:  metrics,
 		sv:       sv,
+		limit:    quota.Limit,
 	}
-	quota := &memQuota{acc: acc, notifyOutOfQuota: b.notifyOutOfQuota}
+	mq := &memQuota{acc: acc, usage: quota.Usage, notifyOutOfQuota: b.notifyOutOfQuota}
 	b.qp = allocPool{
-		AbstractPool: quotapool.New("changefeed", quota, opts...),
+		AbstractPool: quotapool.New("changefeed", mq, opts...),
 		metrics:      metrics,
 	}
 
@@ -198,7 +222,11 @@ func (b *blockingBuffer) Add(ctx context.Context, e Event) error {
 
 	// Acquire the quota first.
 	alloc := int64(changefeedbase.EventMemoryMultiplier.Get(b.sv) * float64(e.approxSize))
-	if l := changefeedbase.PerChangefeedMemLimit.Get(b.sv); alloc > l {
+	l := b.limit
+	if l == 0 {
+		l = changefeedbase.PerChangefeedMemLimit.Get(b.sv)
+	}
+	if alloc > l {
 		return errors.Newf("event size %d exceeds per changefeed limit %d", alloc, l)
 	}
 	e.alloc = Alloc{
@@ -268,6 +296,9 @@ func (b *blockingBuffer) CloseWithReason(ctx context.Context, reason error) erro
 		quota := r.(*memQuota)
 		quota.closed = true
 		quota.acc.Close(ctx)
+		if quota.usage != nil {
+			quota.usage.Dec(quota.allocated)
+		}
 		return false
 	})
 
@@ -314,6 +345,9 @@ type memQuota struct {
 	// times for a single request that's blocked.
 	notifyOutOfQuota func()
 
+	// usage, if non-nil, tracks the allocated bytes.
+	usage *aggmetric.Gauge
+
 	acc mon.BoundAccount
 }
 
@@ -384,6 +418,9 @@ func (be *bufferEntry) acquireQuota(
 
 	quota.allocated += be.e.alloc.bytes
 	quota.canAllocateBelow = 0
+	if quota.usage != nil {
+		quota.usage.Inc(be.e.alloc.bytes)
+	}
 	return true, 0
 }
 
@@ -434,6 +471,9 @@ func (ap allocPool) Release(ctx context.Context, bytes, entries int64) {
 		}
 		quota.acc.Shrink(ctx, bytes)
 		quota.allocated -= bytes
+		if quota.usage != nil {
+			quota.usage.Dec(bytes)
+		}
 		ap.metrics.BufferEntriesMemReleased.Inc(bytes)
 		ap.metrics.BufferEntriesReleased.Inc(entries)
 		return true
@@ -26,6 +26,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/cockroach/pkg/util/metric/aggmetric"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
@@ -159,3 +161,38 @@ func TestBlockingBufferNotifiesConsumerWhenOutOfMemory(t *testing.T) {
 
 	stopProducer()
 }
+
+func TestBlockingBufferWithQuota(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	metrics := kvevent.MakeMetrics(time.Minute)
+	ba, release := getBoundAccountWithBudget(1 << 20)
+	defer release()
+
+	st := cluster.MakeTestingClusterSettings()
+	usage := aggmetric.NewGauge(metric.Metadata{Name: "usage"}, "job_id").AddChild("1")
+	buf := kvevent.NewMemBufferWithQuota(ba, &st.SV, &metrics, kvevent.MemQuota{Limit: 4096, Usage: usage})
+
+	// Events are admitted until their allocation exceeds the quota's limit.
+	rnd, _ := randutil.NewTestRand()
+	require.NoError(t, buf.Add(ctx, kvevent.MakeKVEvent(makeKV(t, rnd), roachpb.Value{}, hlc.Timestamp{})))
+	require.Greater(t, usage.Value(), int64(0))
+	largeKV := makeKV(t, rnd)
+	largeKV.Value.RawBytes = randutil.RandBytes(rnd, 4096)
+	require.Regexp(t, "exceeds per changefeed limit 4096",
+		buf.Add(ctx, kvevent.MakeKVEvent(largeKV, roachpb.Value{}, hlc.Timestamp{})))
+
+	// Released allocations are no longer counted as used.
+	e, err := buf.Get(ctx)
+	require.NoError(t, err)
+	a := e.DetachAlloc()
+	a.Release(ctx)
+	require.Equal(t, int64(0), usage.Value())
+
+	// Closing the buffer releases everything it allocated.
+	require.NoError(t, buf.Add(ctx, kvevent.MakeKVEvent(makeKV(t, rnd), roachpb.Value{}, hlc.Timestamp{})))
+	require.NoError(t, buf.CloseWithReason(ctx, nil))
+	require.Equal(t, int64(0), usage.Value())
+}
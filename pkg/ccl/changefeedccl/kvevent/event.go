@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -82,17 +83,22 @@ const (
 	// for more memory.
 	TypeFlush
 
+	// TypeSchemaChange indicates that the SchemaChange method on the Event will
+	// be meaningful.
+	TypeSchemaChange
+
 	// TypeUnknown indicates the event could not be parsed. Will fail the feed.
 	TypeUnknown
 )
 
-// Event represents an event emitted by a kvfeed. It is either a KV or a
-// resolved timestamp.
+// Event represents an event emitted by a kvfeed. It is either a KV, a
+// resolved timestamp or a schema change.
 type Event struct {
 	kv                 roachpb.KeyValue
 	prevVal            roachpb.Value
 	flush              bool
 	resolved           *jobspb.ResolvedSpan
+	schemaChange       *SchemaChange
 	backfillTimestamp  hlc.Timestamp
 	bufferAddTimestamp time.Time
 	approxSize         int
@@ -110,6 +116,9 @@ func (b *Event) Type() Type {
 	if b.flush {
 		return TypeFlush
 	}
+	if b.schemaChange != nil {
+		return TypeSchemaChange
+	}
 	return TypeUnknown
 }
 
@@ -136,6 +145,11 @@ func (b *Event) Resolved() *jobspb.ResolvedSpan {
 	return b.resolved
 }
 
+// SchemaChange returns the schema change of a schema change event.
+func (b *Event) SchemaChange() *SchemaChange {
+	return b.schemaChange
+}
+
 // BackfillTimestamp overrides the timestamp of the schema that should be
 // used to interpret this KV. If set and prevVal is provided, the previous
 // timestamp will be used to interpret the previous value.
@@ -166,6 +180,8 @@ func (b *Event) Timestamp() hlc.Timestamp {
 		return b.kv.Value.Timestamp
 	case TypeFlush:
 		return hlc.Timestamp{}
+	case TypeSchemaChange:
+		return b.schemaChange.Timestamp
 	default:
 		log.Warningf(context.TODO(),
 			"setting empty timestamp for unknown event type")
@@ -184,6 +200,8 @@ func (b *Event) MVCCTimestamp() hlc.Timestamp {
 		return b.kv.Value.Timestamp
 	case TypeFlush:
 		return hlc.Timestamp{}
+	case TypeSchemaChange:
+		return b.schemaChange.Timestamp
 	default:
 		log.Warningf(context.TODO(),
 			"setting empty timestamp for unknown event type")
@@ -212,6 +230,41 @@ func MakeResolvedEvent(
 	}
 }
 
+// SchemaChange describes a change to the columns of a watched table. Schema
+// change events are emitted by the kvfeed before it backfills the columns
+// which were added to a table, when the schema_change_policy is
+// backfill_changed_columns.
+type SchemaChange struct {
+	TableID   descpb.ID
+	TableName string
+	Version   descpb.DescriptorVersion
+	// Timestamp is the time of the schema change, which is also the backfill
+	// timestamp of the rows emitted by its backfill.
+	Timestamp hlc.Timestamp
+	// AddedColumns and DroppedColumns are the names of the columns added to
+	// and dropped from the table by the schema change.
+	AddedColumns   []string
+	DroppedColumns []string
+	// Announce is set in the schema change event of exactly one of the kvfeeds
+	// of a changefeed, whose aggregator emits the schema change to the sink.
+	Announce bool
+}
+
+// MakeSchemaChangeEvent returns schema change event.
+func MakeSchemaChangeEvent(sc SchemaChange) Event {
+	size := len(sc.TableName) + sc.Timestamp.Size()
+	for _, col := range sc.AddedColumns {
+		size += len(col)
+	}
+	for _, col := range sc.DroppedColumns {
+		size += len(col)
+	}
+	return Event{
+		schemaChange: &sc,
+		approxSize:   size,
+	}
+}
+
 // MakeKVEvent returns KV event.
 func MakeKVEvent(
 	kv roachpb.KeyValue, prevVal roachpb.Value, backfillTimestamp hlc.Timestamp,
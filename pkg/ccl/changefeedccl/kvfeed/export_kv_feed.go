@@ -0,0 +1,287 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package kvfeed
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/gossip"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/limit"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// exportFeed is a physical feed which replays the MVCC history of its spans
+// up to an end time which has already passed. Rather than running rangefeeds
+// and waiting for their catch-up scans and checkpoints, it exports every
+// revision of the spans with ExportRequests, which iterate the revisions with
+// an MVCCIncrementalIterator, and then resolves the spans at the end time.
+type exportFeed struct {
+	settings *cluster.Settings
+	gossip   gossip.OptionalGossip
+	db       *kv.DB
+	endTime  hlc.Timestamp
+}
+
+var _ physicalFeedFactory = (*exportFeed)(nil)
+
+// Run implements the physicalFeedFactory interface. Unlike rangefeeds, it
+// returns once all of the spans are resolved at the end time.
+func (p *exportFeed) Run(ctx context.Context, sink kvevent.Writer, cfg rangeFeedConfig) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if log.V(2) {
+		log.Infof(ctx, "replaying %v until %v withDiff %v", cfg.Spans, p.endTime, cfg.WithDiff)
+	}
+
+	sender := p.db.NonTransactionalSender()
+	distSender := sender.(*kv.CrossRangeTxnWrapperSender).Wrapped().(*kvcoord.DistSender)
+	exportLim := limit.MakeConcurrentRequestLimiter(
+		"changefeedExportRequestLimiter", maxConcurrentScanRequests(p.gossip, &p.settings.SV))
+
+	g := ctxgroup.WithContext(ctx)
+	for _, stp := range cfg.Spans {
+		startAfter := stp.StartAfter
+		// The spans are split along range boundaries, so that every request
+		// is served by a single range.
+		spans, err := getSpansToProcess(ctx, distSender, []roachpb.Span{stp.Span})
+		if err != nil {
+			cancel()
+			return errors.CombineErrors(err, g.Wait())
+		}
+		for _, span := range spans {
+			span := span
+			limAlloc, err := exportLim.Begin(ctx)
+			if err != nil {
+				cancel()
+				return errors.CombineErrors(err, g.Wait())
+			}
+			g.GoCtx(func(ctx context.Context) error {
+				defer limAlloc.Release()
+				return p.exportSpan(ctx, sink, span, startAfter, cfg.WithDiff)
+			})
+		}
+	}
+	return g.Wait()
+}
+
+// exportSpan writes every revision of the span after startAfter and before
+// the end time to the sink, and then resolves the span at the end time. With
+// withDiff, the previous values of the first revisions are exported from the
+// span as of startAfter.
+func (p *exportFeed) exportSpan(
+	ctx context.Context,
+	sink kvevent.Writer,
+	span roachpb.Span,
+	startAfter hlc.Timestamp,
+	withDiff bool,
+) error {
+	// The end time is exclusive, just like it is for rangefeeds.
+	header := roachpb.Header{Timestamp: p.endTime.Prev(), TargetBytes: 1}
+	for remaining := &span; remaining != nil; {
+		if log.V(2) {
+			log.Infof(ctx, `sending ExportRequest %s (%s, %s]`, *remaining, startAfter, header.Timestamp)
+		}
+		req := &roachpb.ExportRequest{
+			RequestHeader:                       roachpb.RequestHeaderFromSpan(*remaining),
+			StartTime:                           startAfter,
+			MVCCFilter:                          roachpb.MVCCFilter_All,
+			ReturnSST:                           true,
+			EnableTimeBoundIteratorOptimization: true, // NB: Must set for 22.1 compatibility.
+		}
+		resp, pErr := kv.SendWrappedWith(ctx, p.db.NonTransactionalSender(), header, req)
+		if pErr != nil {
+			return errors.Wrapf(pErr.GoError(), `exporting changes for %s`, *remaining)
+		}
+		res := resp.(*roachpb.ExportResponse)
+		var prevValues map[string][]byte
+		if withDiff {
+			exported := *remaining
+			if res.ResumeSpan != nil {
+				exported.EndKey = res.ResumeSpan.Key
+			}
+			var err error
+			if prevValues, err = p.exportPrevValues(ctx, exported, startAfter, res.Files); err != nil {
+				return err
+			}
+		}
+		for _, file := range res.Files {
+			if err := slurpExportedFile(ctx, sink, file.SST, startAfter, withDiff, prevValues); err != nil {
+				return errors.Wrapf(err, `buffering changes for %s`, file.Span)
+			}
+		}
+		if res.ResumeSpan != nil {
+			if !res.ResumeSpan.Valid() {
+				return errors.Errorf("invalid resume span: %s", res.ResumeSpan)
+			}
+			consumed := roachpb.Span{Key: remaining.Key, EndKey: res.ResumeSpan.Key}
+			if err := sink.Add(
+				ctx, kvevent.MakeResolvedEvent(consumed, p.endTime, jobspb.ResolvedSpan_NONE),
+			); err != nil {
+				return err
+			}
+		}
+		remaining = res.ResumeSpan
+	}
+	return sink.Add(ctx, kvevent.MakeResolvedEvent(span, p.endTime, jobspb.ResolvedSpan_NONE))
+}
+
+// exportPrevValues returns the values as of ts of the keys with revisions in
+// the exported files, which are the previous values of their first revisions.
+// Only the latest revisions of the span as of ts are exported, rather than its
+// whole MVCC history. Keys which did not exist at ts have no value.
+func (p *exportFeed) exportPrevValues(
+	ctx context.Context, span roachpb.Span, ts hlc.Timestamp, files []roachpb.ExportResponse_File,
+) (map[string][]byte, error) {
+	prevValues := make(map[string][]byte)
+	for _, file := range files {
+		if err := iterateExportedFile(file.SST, func(k storage.MVCCKey, _ []byte) error {
+			prevValues[string(k.Key)] = nil
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if len(prevValues) == 0 || ts.IsEmpty() {
+		return prevValues, nil
+	}
+
+	header := roachpb.Header{Timestamp: ts, TargetBytes: 1}
+	for remaining := &span; remaining != nil; {
+		if log.V(2) {
+			log.Infof(ctx, `sending ExportRequest %s [%s, %s]`, *remaining, ts, ts)
+		}
+		req := &roachpb.ExportRequest{
+			RequestHeader:                       roachpb.RequestHeaderFromSpan(*remaining),
+			MVCCFilter:                          roachpb.MVCCFilter_Latest,
+			ReturnSST:                           true,
+			EnableTimeBoundIteratorOptimization: true, // NB: Must set for 22.1 compatibility.
+		}
+		resp, pErr := kv.SendWrappedWith(ctx, p.db.NonTransactionalSender(), header, req)
+		if pErr != nil {
+			return nil, errors.Wrapf(pErr.GoError(), `exporting previous values for %s`, *remaining)
+		}
+		res := resp.(*roachpb.ExportResponse)
+		for _, file := range res.Files {
+			if err := iterateExportedFile(file.SST, func(k storage.MVCCKey, v []byte) error {
+				if _, ok := prevValues[string(k.Key)]; !ok {
+					return nil
+				}
+				mvccValue, err := storage.DecodeMVCCValue(v)
+				if err != nil {
+					return errors.Wrapf(err, "decoding mvcc value: %v", k)
+				}
+				if !mvccValue.IsTombstone() {
+					prevValues[string(k.Key)] = append([]byte(nil), mvccValue.Value.RawBytes...)
+				}
+				return nil
+			}); err != nil {
+				return nil, errors.Wrapf(err, `buffering previous values for %s`, file.Span)
+			}
+		}
+		remaining = res.ResumeSpan
+	}
+	return prevValues, nil
+}
+
+// iterateExportedFile invokes fn with every key and unsafe value in the
+// exported SST.
+func iterateExportedFile(sst []byte, fn func(k storage.MVCCKey, v []byte) error) error {
+	it, err := storage.NewMemSSTIterator(sst, false /* verify */)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.SeekGE(storage.NilKey); ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+		if err := fn(it.UnsafeKey(), it.UnsafeValue()); err != nil {
+			return err
+		}
+	}
+}
+
+// slurpExportedFile writes the revisions in the exported SST which are after
+// startAfter to the sink. The revisions of every key are written in
+// chronological order and, if withDiff is set, along with the revision which
+// preceded them; the previous values of the first revisions are taken from
+// prevValues.
+func slurpExportedFile(
+	ctx context.Context,
+	sink kvevent.Writer,
+	sst []byte,
+	startAfter hlc.Timestamp,
+	withDiff bool,
+	prevValues map[string][]byte,
+) error {
+	it, err := storage.NewMemSSTIterator(sst, false /* verify */)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	// The SST holds the revisions of every key in reverse chronological order.
+	// Buffer the revisions of a key until the next key is found, and then write
+	// them out in reverse.
+	var revisions []roachpb.KeyValue
+	flush := func() error {
+		for i := len(revisions) - 1; i >= 0; i-- {
+			var prevVal roachpb.Value
+			if withDiff && i+1 < len(revisions) {
+				prevVal.RawBytes = revisions[i+1].Value.RawBytes
+			} else if withDiff {
+				prevVal.RawBytes = prevValues[string(revisions[i].Key)]
+			}
+			if revisions[i].Value.Timestamp.LessEq(startAfter) {
+				continue
+			}
+			if err := sink.Add(ctx, kvevent.MakeKVEvent(revisions[i], prevVal, hlc.Timestamp{})); err != nil {
+				return err
+			}
+		}
+		revisions = revisions[:0]
+		return nil
+	}
+	for it.SeekGE(storage.NilKey); ; it.Next() {
+		if ok, err := it.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		k := it.UnsafeKey()
+		if len(revisions) > 0 && !bytes.Equal(k.Key, revisions[0].Key) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		v, err := storage.DecodeMVCCValue(it.UnsafeValue())
+		if err != nil {
+			return errors.Wrapf(err, "decoding mvcc value: %v", k)
+		}
+		kv := roachpb.KeyValue{Key: k.Key.Clone(), Value: v.Value}
+		kv.Value.RawBytes = append([]byte(nil), v.Value.RawBytes...)
+		kv.Value.Timestamp = k.Timestamp
+		revisions = append(revisions, kv)
+	}
+	return flush()
+}
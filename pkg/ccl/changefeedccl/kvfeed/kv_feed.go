@@ -9,7 +9,8 @@
 // Package kvfeed provides an abstraction to stream kvs to a buffer.
 //
 // The kvfeed coordinated performing logical backfills in the face of schema
-// changes and then running rangefeeds.
+// changes and then running rangefeeds, or replaying the history of the spans
+// with export requests once the end time of the feed has passed.
 package kvfeed
 
 import (
@@ -67,7 +68,9 @@ type Config struct {
 
 	// If the end time is set, the changefeed will run until the frontier
 	// progresses past the end time. Once the frontier has progressed past the end
-	// time, the changefeed job will end with a successful status.
+	// time, the changefeed job will end with a successful status. If the end
+	// time has already passed when the feed starts, its history up to the end
+	// time is replayed with export requests rather than rangefeeds.
 	EndTime hlc.Timestamp
 
 	// Knobs are kvfeed testing knobs.
@@ -88,7 +91,16 @@ func Run(ctx context.Context, cfg Config) error {
 		}
 	}
 	var pff physicalFeedFactory
-	{
+	if !cfg.EndTime.IsEmpty() && cfg.EndTime.Less(cfg.Clock.Now()) {
+		// The feed replays history which is already written, which does not
+		// require rangefeeds.
+		pff = &exportFeed{
+			settings: cfg.Settings,
+			gossip:   cfg.Gossip,
+			db:       cfg.DB,
+			endTime:  cfg.EndTime,
+		}
+	} else {
 		sender := cfg.DB.NonTransactionalSender()
 		distSender := sender.(*kv.CrossRangeTxnWrapperSender).Wrapped().(*kvcoord.DistSender)
 		pff = rangefeedFactory(distSender.RangeFeedSpans)
@@ -356,18 +368,31 @@ func (f *kvFeed) scanIfShould(
 			if schemafeed.IsOnlyPrimaryIndexChange(ev) {
 				continue
 			}
+			if !scanTime.Equal(ev.After.GetModificationTime()) {
+				return nil, hlc.Timestamp{}, errors.AssertionFailedf(
+					"found event in shouldScan which did not occur at the scan time %v: %v",
+					scanTime, ev)
+			}
 			tablePrefix := f.codec.TablePrefix(uint32(ev.After.GetID()))
 			tableSpan := roachpb.Span{Key: tablePrefix, EndKey: tablePrefix.PrefixEnd()}
+			var tableSpans []roachpb.Span
 			for _, sp := range f.spans {
 				if tableSpan.Overlaps(sp) {
-					spansToScan = append(spansToScan, sp)
+					tableSpans = append(tableSpans, sp)
 				}
 			}
-			if !scanTime.Equal(ev.After.GetModificationTime()) {
-				return nil, hlc.Timestamp{}, errors.AssertionFailedf(
-					"found event in shouldScan which did not occur at the scan time %v: %v",
-					scanTime, ev)
+			if f.schemaChangePolicy == changefeedbase.OptSchemaChangePolicyBackfillChangedColumns {
+				// Only the columns added to the table are backfilled, after the
+				// consumer was told which columns those are.
+				needsBackfill, err := f.emitSchemaChange(ctx, ev, tableSpans)
+				if err != nil {
+					return nil, hlc.Timestamp{}, err
+				}
+				if !needsBackfill {
+					continue
+				}
 			}
+			spansToScan = append(spansToScan, tableSpans...)
 		}
 	} else {
 		return nil, hlc.Timestamp{}, nil
@@ -405,6 +430,40 @@ func (f *kvFeed) scanIfShould(
 	return spansToScan, scanTime, nil
 }
 
+// emitSchemaChange emits a schema change event describing the columns which
+// the table event adds and drops, and returns whether the added columns need
+// to be backfilled. The event is emitted ahead of the backfill so that the
+// consumer only emits the added columns of the backfilled rows. Exactly one of
+// the kvfeeds of a changefeed, the one watching the start of the primary index
+// of the table, announces the schema change.
+func (f *kvFeed) emitSchemaChange(
+	ctx context.Context, ev schemafeed.TableEvent, tableSpans []roachpb.Span,
+) (needsBackfill bool, _ error) {
+	added, dropped := schemafeed.ChangedColumns(ev)
+	if len(tableSpans) == 0 || (len(added) == 0 && len(dropped) == 0) {
+		return false, nil
+	}
+	indexStart := f.codec.IndexPrefix(uint32(ev.After.GetID()), uint32(ev.After.GetPrimaryIndexID()))
+	var announce bool
+	for _, sp := range tableSpans {
+		if sp.ContainsKey(indexStart) {
+			announce = true
+		}
+	}
+	if err := f.writer.Add(ctx, kvevent.MakeSchemaChangeEvent(kvevent.SchemaChange{
+		TableID:        ev.After.GetID(),
+		TableName:      ev.After.GetName(),
+		Version:        ev.After.GetVersion(),
+		Timestamp:      ev.After.GetModificationTime(),
+		AddedColumns:   added,
+		DroppedColumns: dropped,
+		Announce:       announce,
+	})); err != nil {
+		return false, err
+	}
+	return len(added) > 0, nil
+}
+
 func (f *kvFeed) runUntilTableEvent(
 	ctx context.Context, resumeFrontier *span.Frontier,
 ) (err error) {
@@ -65,6 +65,9 @@ func (p rangefeedFactory) Run(ctx context.Context, sink kvevent.Writer, cfg rang
 	// `SchemaFeed` is responsible for detecting and enforcing these , but the
 	// after-KVFeed buffer doesn't have access to any of this state. A cleanup is
 	// in order.
+	if cfg.Knobs.OnRangeFeedStart != nil {
+		cfg.Knobs.OnRangeFeedStart(cfg.Spans)
+	}
 	feed := rangefeed{
 		memBuf: sink,
 		cfg:    cfg,
@@ -10,6 +10,7 @@ package changefeedccl
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -18,7 +19,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/schemafeed"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/multitenant"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
@@ -490,9 +493,113 @@ func (a *AggMetrics) getOrCreateScope(scope string) (*sliMetrics, error) {
 	return sm, nil
 }
 
+// QuotaMetrics are metrics of the per changefeed quotas, broken down by the
+// jobs which specified any quota.
+type QuotaMetrics struct {
+	MemoryLimit        *aggmetric.AggGauge
+	MemoryUsage        *aggmetric.AggGauge
+	ThroughputPushback *aggmetric.AggCounter
+	FlushWaitNanos     *aggmetric.AggCounter
+
+	mu struct {
+		syncutil.Mutex
+		jobs map[jobspb.JobID]*jobQuotaMetrics
+	}
+}
+
+// MetricStruct implements metric.Struct interface.
+func (*QuotaMetrics) MetricStruct() {}
+
+// jobQuotaMetrics are the quota metrics of a single job, which are shared by
+// all of the job's processors running on the node.
+type jobQuotaMetrics struct {
+	MemoryLimit        *aggmetric.Gauge
+	MemoryUsage        *aggmetric.Gauge
+	ThroughputPushback *aggmetric.Counter
+	FlushWaitNanos     *aggmetric.Counter
+
+	refs int
+}
+
+func newQuotaMetrics() *QuotaMetrics {
+	metaMemoryLimit := metric.Metadata{
+		Name:        "changefeed.quota.memory_limit",
+		Help:        "Memory quota of changefeeds which specify memory_quota",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaMemoryUsage := metric.Metadata{
+		Name:        "changefeed.quota.memory_usage",
+		Help:        "Memory used by the buffers of changefeeds which specify a quota",
+		Measurement: "Bytes",
+		Unit:        metric.Unit_BYTES,
+	}
+	metaThroughputPushback := metric.Metadata{
+		Name:        "changefeed.quota.throughput_pushback_nanos",
+		Help:        "Total time changefeeds spent throttled by their throughput_limit",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	metaFlushWait := metric.Metadata{
+		Name:        "changefeed.quota.flush_wait_nanos",
+		Help:        "Total time changefeeds spent waiting for their share of the node's sink flushes",
+		Measurement: "Nanoseconds",
+		Unit:        metric.Unit_NANOSECONDS,
+	}
+	b := aggmetric.MakeBuilder("job_id")
+	m := &QuotaMetrics{
+		MemoryLimit:        b.Gauge(metaMemoryLimit),
+		MemoryUsage:        b.Gauge(metaMemoryUsage),
+		ThroughputPushback: b.Counter(metaThroughputPushback),
+		FlushWaitNanos:     b.Counter(metaFlushWait),
+	}
+	m.mu.jobs = make(map[jobspb.JobID]*jobQuotaMetrics)
+	return m
+}
+
+// acquireJob returns the quota metrics of the specified job. The returned
+// metrics must be released once the caller no longer uses them.
+func (m *QuotaMetrics) acquireJob(jobID jobspb.JobID) *jobQuotaMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jm, ok := m.mu.jobs[jobID]
+	if !ok {
+		label := strconv.FormatInt(int64(jobID), 10)
+		jm = &jobQuotaMetrics{
+			MemoryLimit:        m.MemoryLimit.AddChild(label),
+			MemoryUsage:        m.MemoryUsage.AddChild(label),
+			ThroughputPushback: m.ThroughputPushback.AddChild(label),
+			FlushWaitNanos:     m.FlushWaitNanos.AddChild(label),
+		}
+		m.mu.jobs[jobID] = jm
+	}
+	jm.refs++
+	return jm
+}
+
+// releaseJob releases the quota metrics of the specified job, and removes them
+// once they are no longer used.
+func (m *QuotaMetrics) releaseJob(jobID jobspb.JobID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jm, ok := m.mu.jobs[jobID]
+	if !ok {
+		return
+	}
+	if jm.refs--; jm.refs > 0 {
+		return
+	}
+	jm.MemoryLimit.Destroy()
+	jm.MemoryUsage.Destroy()
+	jm.ThroughputPushback.Destroy()
+	jm.FlushWaitNanos.Destroy()
+	delete(m.mu.jobs, jobID)
+}
+
 // Metrics are for production monitoring of changefeeds.
 type Metrics struct {
 	AggMetrics          *AggMetrics
+	QuotaMetrics        *QuotaMetrics
 	KVFeedMetrics       kvevent.Metrics
 	SchemaFeedMetrics   schemafeed.Metrics
 	Failures            *metric.Counter
@@ -506,6 +613,9 @@ type Metrics struct {
 		syncutil.Mutex
 		id       int
 		resolved map[int]hlc.Timestamp
+		// flushScheduler shares the sink flushes of the server among its
+		// changefeeds. It is created by the first changefeed which flushes.
+		flushScheduler *flushScheduler
 	}
 	MaxBehindNanos *metric.Gauge
 }
@@ -518,10 +628,22 @@ func (m *Metrics) getSLIMetrics(scope string) (*sliMetrics, error) {
 	return m.AggMetrics.getOrCreateScope(scope)
 }
 
+// getFlushScheduler returns the flushScheduler of the server which owns the
+// metrics.
+func (m *Metrics) getFlushScheduler(sv *settings.Values) *flushScheduler {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mu.flushScheduler == nil {
+		m.mu.flushScheduler = newFlushScheduler(sv)
+	}
+	return m.mu.flushScheduler
+}
+
 // MakeMetrics makes the metrics for changefeed monitoring.
 func MakeMetrics(histogramWindow time.Duration) metric.Struct {
 	m := &Metrics{
 		AggMetrics:        newAggregateMetrics(histogramWindow),
+		QuotaMetrics:      newQuotaMetrics(),
 		KVFeedMetrics:     kvevent.MakeMetrics(histogramWindow),
 		SchemaFeedMetrics: schemafeed.MakeMetrics(histogramWindow),
 		ResolvedMessages:  metric.NewCounter(metaChangefeedForwardedResolvedMessages),
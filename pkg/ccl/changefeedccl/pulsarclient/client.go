@@ -0,0 +1,332 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package pulsarclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/proto"
+)
+
+// The URL schemes of Pulsar brokers.
+const (
+	SchemePulsar    = `pulsar`
+	SchemePulsarTLS = `pulsar+ssl`
+
+	defaultPort    = `6650`
+	defaultTLSPort = `6651`
+)
+
+const (
+	// clientVersion is sent to brokers when connecting.
+	clientVersion = `cockroachdb-changefeed`
+	// protocolVersion is the version of the protocol which is implemented by
+	// the client. Version 13 is supported by every maintained broker release.
+	protocolVersion = 13
+	// maxLookupRedirects bounds the number of redirects which are followed
+	// when looking up the broker which owns a topic.
+	maxLookupRedirects = 20
+
+	defaultOperationTimeout = 30 * time.Second
+)
+
+// Options configures a Client.
+type Options struct {
+	// TLSConfig is used to connect to brokers with the pulsar+ssl scheme.
+	TLSConfig *tls.Config
+	// AuthToken, if set, authenticates the client using the token
+	// authentication provider of the brokers.
+	AuthToken string
+	// OperationTimeout bounds connecting to a broker and every request sent to
+	// it, and defaults to 30 seconds.
+	OperationTimeout time.Duration
+}
+
+// Client is a client of a Pulsar cluster which can produce messages. It keeps
+// a connection to every broker which owns a topic it produces to.
+type Client struct {
+	serviceURL *url.URL
+	opts       Options
+
+	nextProducerID uint64 // accessed atomically
+
+	mu struct {
+		syncutil.Mutex
+		// conns maps the host and port of a broker to the connection to it.
+		conns  map[string]*conn
+		closed bool
+	}
+}
+
+// NewClient returns a Client of the Pulsar cluster at the given service URL.
+// Connections are established lazily.
+func NewClient(serviceURL string, opts Options) (*Client, error) {
+	u, err := parseBrokerURL(serviceURL)
+	if err != nil {
+		return nil, err
+	}
+	if opts.OperationTimeout == 0 {
+		opts.OperationTimeout = defaultOperationTimeout
+	}
+	c := &Client{serviceURL: u, opts: opts}
+	c.mu.conns = make(map[string]*conn)
+	return c, nil
+}
+
+// parseBrokerURL parses the URL of a broker, and adds the default port if it
+// has none.
+func parseBrokerURL(brokerURL string) (*url.URL, error) {
+	u, err := url.Parse(brokerURL)
+	if err != nil {
+		return nil, err
+	}
+	port := defaultPort
+	switch u.Scheme {
+	case SchemePulsar:
+	case SchemePulsarTLS:
+		port = defaultTLSPort
+	default:
+		return nil, errors.Errorf("unsupported scheme %q for pulsar broker URL", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("pulsar broker URL %q has no host", brokerURL)
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), port)
+	}
+	return u, nil
+}
+
+// getConn returns a connection to the broker at the given URL, connecting to
+// it if there is no connection yet or the previous one failed.
+func (c *Client) getConn(ctx context.Context, u *url.URL) (*conn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.mu.closed {
+		return nil, errors.New("pulsar client is closed")
+	}
+	if cn, ok := c.mu.conns[u.Host]; ok {
+		if cn.err() == nil {
+			return cn, nil
+		}
+		delete(c.mu.conns, u.Host)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.opts.OperationTimeout)
+	defer cancel()
+	var tlsConfig *tls.Config
+	if u.Scheme == SchemePulsarTLS {
+		tlsConfig = c.opts.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+	}
+	cn, err := dial(ctx, u.Host, tlsConfig, c.opts.AuthToken)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connecting to pulsar broker %s", u.Host)
+	}
+	c.mu.conns[u.Host] = cn
+	return cn, nil
+}
+
+// request sends the command to the broker and waits for the response.
+func (c *Client) request(
+	ctx context.Context, cn *conn, cmd *BaseCommand, setRequestID func(uint64),
+) (*BaseCommand, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.OperationTimeout)
+	defer cancel()
+	return cn.request(ctx, cmd, setRequestID)
+}
+
+// PartitionedTopicMetadata returns the number of partitions of the topic, or
+// zero if the topic is not partitioned.
+func (c *Client) PartitionedTopicMetadata(ctx context.Context, topic string) (int, error) {
+	cn, err := c.getConn(ctx, c.serviceURL)
+	if err != nil {
+		return 0, err
+	}
+	cmd := &BaseCommand{
+		Type:              BaseCommand_PARTITIONED_METADATA.Enum(),
+		PartitionMetadata: &CommandPartitionedTopicMetadata{Topic: topic},
+	}
+	resp, err := c.request(ctx, cn, cmd, func(id uint64) { cmd.PartitionMetadata.RequestID = id })
+	if err != nil {
+		return 0, err
+	}
+	r := resp.PartitionMetadataResponse
+	if r == nil {
+		return 0, unexpectedResponse(resp)
+	}
+	if r.Response != nil && *r.Response == CommandPartitionedTopicMetadataResponse_Failed {
+		return 0, newServerError(r.Error, r.Message, "fetching partitions of topic %s", topic)
+	}
+	if r.Partitions == nil {
+		return 0, nil
+	}
+	return int(*r.Partitions), nil
+}
+
+// lookup returns a connection to the broker which owns the topic.
+func (c *Client) lookup(ctx context.Context, topic string) (*conn, error) {
+	u := c.serviceURL
+	authoritative := false
+	for i := 0; i < maxLookupRedirects; i++ {
+		cn, err := c.getConn(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		cmd := &BaseCommand{
+			Type: BaseCommand_LOOKUP.Enum(),
+			LookupTopic: &CommandLookupTopic{
+				Topic:         topic,
+				Authoritative: proto.Bool(authoritative),
+			},
+		}
+		resp, err := c.request(ctx, cn, cmd, func(id uint64) { cmd.LookupTopic.RequestID = id })
+		if err != nil {
+			return nil, err
+		}
+		r := resp.LookupTopicResponse
+		if r == nil {
+			return nil, unexpectedResponse(resp)
+		}
+		lookupType := CommandLookupTopicResponse_Redirect
+		if r.Response != nil {
+			lookupType = *r.Response
+		}
+		if lookupType == CommandLookupTopicResponse_Failed {
+			return nil, newServerError(r.Error, r.Message, "looking up topic %s", topic)
+		}
+
+		brokerURL := r.BrokerServiceURL
+		if c.serviceURL.Scheme == SchemePulsarTLS {
+			brokerURL = r.BrokerServiceURLTLS
+		}
+		if brokerURL == nil || *brokerURL == "" {
+			return nil, errors.Errorf("lookup of topic %s returned no broker URL", topic)
+		}
+		next, err := parseBrokerURL(*brokerURL)
+		if err != nil {
+			return nil, err
+		}
+		if lookupType == CommandLookupTopicResponse_Connect {
+			return c.getConn(ctx, next)
+		}
+		u = next
+		authoritative = r.Authoritative != nil && *r.Authoritative
+	}
+	return nil, errors.Errorf("too many redirects when looking up topic %s", topic)
+}
+
+// CreateProducer creates a producer of a (non-partitioned) topic, or of a
+// single partition of a partitioned topic.
+func (c *Client) CreateProducer(ctx context.Context, topic string) (*Producer, error) {
+	cn, err := c.lookup(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+	p := &Producer{
+		client: c,
+		conn:   cn,
+		id:     atomic.AddUint64(&c.nextProducerID, 1),
+		topic:  topic,
+	}
+	p.mu.pending = make(map[uint64]func(error))
+	cn.registerProducer(p)
+
+	cmd := &BaseCommand{
+		Type: BaseCommand_PRODUCER.Enum(),
+		Producer: &CommandProducer{
+			Topic:      topic,
+			ProducerID: p.id,
+		},
+	}
+	resp, err := c.request(ctx, cn, cmd, func(id uint64) { cmd.Producer.RequestID = id })
+	if err != nil {
+		cn.unregisterProducer(p.id)
+		return nil, errors.Wrapf(err, "creating producer of topic %s", topic)
+	}
+	r := resp.ProducerSuccess
+	if r == nil {
+		cn.unregisterProducer(p.id)
+		return nil, unexpectedResponse(resp)
+	}
+	p.name = r.ProducerName
+	if r.LastSequenceID != nil && *r.LastSequenceID >= 0 {
+		p.mu.nextSequenceID = uint64(*r.LastSequenceID) + 1
+	}
+	return p, nil
+}
+
+// Close closes the connections of the client. Producers which have not been
+// closed fail their pending messages.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.closed = true
+	for host, cn := range c.mu.conns {
+		cn.close(errors.New("pulsar client is closed"))
+		delete(c.mu.conns, host)
+	}
+	return nil
+}
+
+// serverError is an error returned by a broker.
+type serverError struct {
+	code    ServerError
+	message string
+}
+
+func (e *serverError) Error() string {
+	return e.code.String() + ": " + e.message
+}
+
+// newServerError wraps the error returned by a broker.
+func newServerError(code *ServerError, message *string, format string, args ...interface{}) error {
+	e := &serverError{code: ServerError_UnknownError}
+	if code != nil {
+		e.code = *code
+	}
+	if message != nil {
+		e.message = *message
+	}
+	return errors.Wrapf(e, format, args...)
+}
+
+// ServerErrorCode returns the code of the error returned by a broker, if the
+// error was returned by a broker.
+func ServerErrorCode(err error) (ServerError, bool) {
+	var e *serverError
+	if errors.As(err, &e) {
+		return e.code, true
+	}
+	return ServerError_UnknownError, false
+}
+
+func unexpectedResponse(resp *BaseCommand) error {
+	if resp.Error != nil {
+		return newServerError(&resp.Error.Error, &resp.Error.Message, "request failed")
+	}
+	return errors.Errorf("unexpected response of type %s", resp.GetType())
+}
+
+// GetType returns the type of the command.
+func (m *BaseCommand) GetType() BaseCommand_Type {
+	if m.Type == nil {
+		return 0
+	}
+	return *m.Type
+}
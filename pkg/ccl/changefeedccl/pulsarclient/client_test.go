@@ -0,0 +1,140 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package pulsarclient_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/pulsarclient"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProducer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ctx := context.Background()
+	broker, err := cdctest.StartMockPulsarBroker()
+	require.NoError(t, err)
+	defer broker.Close()
+	broker.SetPartitions("persistent://public/default/p", 3)
+
+	client, err := pulsarclient.NewClient(broker.URL(), pulsarclient.Options{})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, client.Close()) }()
+
+	partitions, err := client.PartitionedTopicMetadata(ctx, "persistent://public/default/p")
+	require.NoError(t, err)
+	require.Equal(t, 3, partitions)
+	partitions, err = client.PartitionedTopicMetadata(ctx, "persistent://public/default/np")
+	require.NoError(t, err)
+	require.Equal(t, 0, partitions)
+
+	producer, err := client.CreateProducer(ctx, "persistent://public/default/np")
+	require.NoError(t, err)
+
+	errCh := make(chan error, 10)
+	var expected []pulsarclient.Message
+	for i := 0; i < 10; i++ {
+		batch := []pulsarclient.Message{
+			{Key: fmt.Sprint(i), Payload: []byte(fmt.Sprintf("a%d", i))},
+			{Key: fmt.Sprint(i), Payload: []byte(fmt.Sprintf("b%d", i))},
+		}
+		expected = append(expected, batch...)
+		producer.SendAsync(batch, func(err error) { errCh <- err })
+	}
+	for i := 0; i < 10; i++ {
+		require.NoError(t, <-errCh)
+	}
+	require.Equal(t, expected, broker.Messages("persistent://public/default/np"))
+	require.Equal(t, 10, broker.NumBatches("persistent://public/default/np"))
+
+	// Batches which are rejected by the broker fail.
+	broker.SetSendHook(func(topic string, msgs []pulsarclient.Message) error {
+		return errors.New("boom")
+	})
+	producer.SendAsync([]pulsarclient.Message{{Payload: []byte("x")}}, func(err error) { errCh <- err })
+	err = <-errCh
+	require.Regexp(t, "PersistenceError: boom", err)
+	code, ok := pulsarclient.ServerErrorCode(err)
+	require.True(t, ok)
+	require.Equal(t, pulsarclient.ServerError_PersistenceError, code)
+	broker.SetSendHook(nil)
+	require.NoError(t, producer.Close(ctx))
+
+	// Closed producers fail new batches.
+	producer.SendAsync([]pulsarclient.Message{{Payload: []byte("x")}}, func(err error) { errCh <- err })
+	require.Regexp(t, "is closed", <-errCh)
+
+	// Batches which are pending when the connection fails are failed.
+	producer, err = client.CreateProducer(ctx, "persistent://public/default/np")
+	require.NoError(t, err)
+	unblock := make(chan struct{})
+	broker.SetSendHook(func(topic string, msgs []pulsarclient.Message) error {
+		<-unblock
+		return nil
+	})
+	producer.SendAsync([]pulsarclient.Message{{Payload: []byte("x")}}, func(err error) { errCh <- err })
+	broker.CloseConnections()
+	require.Error(t, <-errCh)
+	close(unblock)
+
+	// The client reconnects once a connection failed.
+	broker.SetSendHook(nil)
+	producer, err = client.CreateProducer(ctx, "persistent://public/default/np")
+	require.NoError(t, err)
+	producer.SendAsync([]pulsarclient.Message{{Payload: []byte("y")}}, func(err error) { errCh <- err })
+	require.NoError(t, <-errCh)
+	require.NoError(t, producer.Close(ctx))
+}
+
+// TestProducerWithBroker produces to a real Pulsar broker, whose service URL
+// must be set in the PULSAR_SERVICE_URL environment variable. A standalone
+// broker can be started with:
+//
+//   docker run -p 6650:6650 apachepulsar/pulsar bin/pulsar standalone
+func TestProducerWithBroker(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	serviceURL := os.Getenv("PULSAR_SERVICE_URL")
+	if serviceURL == "" {
+		skip.IgnoreLint(t, "PULSAR_SERVICE_URL env var must be set")
+	}
+
+	ctx := context.Background()
+	client, err := pulsarclient.NewClient(serviceURL, pulsarclient.Options{})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, client.Close()) }()
+
+	topic := fmt.Sprintf("persistent://public/default/cockroach-test-%d", timeutil.Now().UnixNano())
+	partitions, err := client.PartitionedTopicMetadata(ctx, topic)
+	require.NoError(t, err)
+	require.Equal(t, 0, partitions)
+
+	producer, err := client.CreateProducer(ctx, topic)
+	require.NoError(t, err)
+	errCh := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		producer.SendAsync([]pulsarclient.Message{
+			{Key: fmt.Sprint(i), Payload: []byte(fmt.Sprintf("a%d", i))},
+			{Payload: []byte(fmt.Sprintf("b%d", i))},
+		}, func(err error) { errCh <- err })
+	}
+	for i := 0; i < 10; i++ {
+		require.NoError(t, <-errCh)
+	}
+	require.NoError(t, producer.Close(ctx))
+}
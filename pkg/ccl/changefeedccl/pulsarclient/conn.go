@@ -0,0 +1,281 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package pulsarclient
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"net"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/proto"
+)
+
+// conn is a connection to a broker. Responses to requests are matched to the
+// requests by their request ID, and receipts of sent messages are routed to
+// the producer which sent them.
+type conn struct {
+	netConn        net.Conn
+	maxMessageSize int
+
+	writeMu syncutil.Mutex
+
+	mu struct {
+		syncutil.Mutex
+		nextRequestID uint64
+		requests      map[uint64]chan *BaseCommand
+		producers     map[uint64]*Producer
+		// err is set once the connection failed or was closed.
+		err error
+	}
+	// done is closed once the connection failed or was closed.
+	done chan struct{}
+}
+
+// dial connects to the broker at the given address and performs the
+// handshake.
+func dial(ctx context.Context, addr string, tlsConfig *tls.Config, authToken string) (*conn, error) {
+	var d net.Dialer
+	netConn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		if tlsConfig.ServerName == "" {
+			tlsConfig = tlsConfig.Clone()
+			tlsConfig.ServerName, _, _ = net.SplitHostPort(addr)
+		}
+		tlsConn := tls.Client(netConn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = netConn.Close()
+			return nil, err
+		}
+		netConn = tlsConn
+	}
+
+	connect := &CommandConnect{
+		ClientVersion:   clientVersion,
+		ProtocolVersion: proto.Int32(protocolVersion),
+	}
+	if authToken != "" {
+		connect.AuthMethodName = proto.String("token")
+		connect.AuthData = []byte(authToken)
+	}
+	connected, err := handshake(ctx, netConn, connect)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+
+	c := &conn{
+		netConn:        netConn,
+		maxMessageSize: DefaultMaxMessageSize,
+		done:           make(chan struct{}),
+	}
+	if connected.MaxMessageSize != nil && *connected.MaxMessageSize > 0 {
+		c.maxMessageSize = int(*connected.MaxMessageSize)
+	}
+	c.mu.requests = make(map[uint64]chan *BaseCommand)
+	c.mu.producers = make(map[uint64]*Producer)
+	go c.readLoop(bufio.NewReader(netConn))
+	return c, nil
+}
+
+// handshake sends the CONNECT command and waits for the broker to accept it.
+func handshake(
+	ctx context.Context, netConn net.Conn, connect *CommandConnect,
+) (*CommandConnected, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := netConn.SetDeadline(deadline); err != nil {
+			return nil, err
+		}
+		defer func() { _ = netConn.SetDeadline(time.Time{}) }()
+	}
+	buf, err := encodeFrame(&BaseCommand{Type: BaseCommand_CONNECT.Enum(), Connect: connect}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := netConn.Write(buf); err != nil {
+		return nil, err
+	}
+	f, err := readFrame(netConn, DefaultMaxMessageSize+frameOverhead)
+	if err != nil {
+		return nil, err
+	}
+	if f.cmd.Connected == nil {
+		return nil, unexpectedResponse(&f.cmd)
+	}
+	return f.cmd.Connected, nil
+}
+
+// err returns the error the connection failed with, if any.
+func (c *conn) err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.err
+}
+
+// write writes a frame to the connection.
+func (c *conn) write(cmd *BaseCommand, metadata *MessageMetadata, payload []byte) error {
+	buf, err := encodeFrame(cmd, metadata, payload)
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if err := c.err(); err != nil {
+		return err
+	}
+	if _, err := c.netConn.Write(buf); err != nil {
+		c.close(errors.Wrap(err, "writing to pulsar broker"))
+		return err
+	}
+	return nil
+}
+
+// request assigns the next request ID to the command, sends it and waits for
+// the response.
+func (c *conn) request(
+	ctx context.Context, cmd *BaseCommand, setRequestID func(uint64),
+) (*BaseCommand, error) {
+	ch := make(chan *BaseCommand, 1)
+	c.mu.Lock()
+	if c.mu.err != nil {
+		c.mu.Unlock()
+		return nil, c.mu.err
+	}
+	id := c.mu.nextRequestID
+	c.mu.nextRequestID++
+	c.mu.requests[id] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.mu.requests, id)
+	}()
+
+	setRequestID(id)
+	if err := c.write(cmd, nil, nil); err != nil {
+		return nil, err
+	}
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return nil, newServerError(&resp.Error.Error, &resp.Error.Message, "%s request failed", cmd.GetType())
+		}
+		return resp, nil
+	case <-c.done:
+		return nil, c.err()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *conn) registerProducer(p *Producer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mu.producers[p.id] = p
+}
+
+func (c *conn) unregisterProducer(id uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.mu.producers, id)
+}
+
+func (c *conn) producer(id uint64) *Producer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mu.producers[id]
+}
+
+// readLoop reads and dispatches the frames sent by the broker until the
+// connection fails.
+func (c *conn) readLoop(r *bufio.Reader) {
+	for {
+		f, err := readFrame(r, c.maxMessageSize+frameOverhead)
+		if err != nil {
+			c.close(errors.Wrap(err, "reading from pulsar broker"))
+			return
+		}
+		cmd := &f.cmd
+		switch cmd.GetType() {
+		case BaseCommand_PING:
+			// The broker closes connections which do not answer pings.
+			_ = c.write(&BaseCommand{Type: BaseCommand_PONG.Enum(), Pong: &CommandPong{}}, nil, nil)
+		case BaseCommand_PONG:
+		case BaseCommand_SEND_RECEIPT:
+			if p := c.producer(cmd.SendReceipt.ProducerID); p != nil {
+				p.complete(cmd.SendReceipt.SequenceID, nil)
+			}
+		case BaseCommand_SEND_ERROR:
+			r := cmd.SendError
+			if p := c.producer(r.ProducerID); p != nil {
+				p.complete(r.SequenceID, newServerError(&r.Error, &r.Message, "sending to topic %s", p.topic))
+			}
+		case BaseCommand_CLOSE_PRODUCER:
+			if p := c.producer(cmd.CloseProducer.ProducerID); p != nil {
+				c.unregisterProducer(p.id)
+				p.fail(errors.Errorf("producer of topic %s was closed by the broker", p.topic))
+			}
+		default:
+			id, ok := responseRequestID(cmd)
+			if !ok {
+				continue
+			}
+			c.mu.Lock()
+			ch := c.mu.requests[id]
+			c.mu.Unlock()
+			if ch != nil {
+				ch <- cmd
+			}
+		}
+	}
+}
+
+// responseRequestID returns the request ID of a response.
+func responseRequestID(cmd *BaseCommand) (uint64, bool) {
+	switch {
+	case cmd.Success != nil:
+		return cmd.Success.RequestID, true
+	case cmd.Error != nil:
+		return cmd.Error.RequestID, true
+	case cmd.ProducerSuccess != nil:
+		return cmd.ProducerSuccess.RequestID, true
+	case cmd.PartitionMetadataResponse != nil:
+		return cmd.PartitionMetadataResponse.RequestID, true
+	case cmd.LookupTopicResponse != nil:
+		return cmd.LookupTopicResponse.RequestID, true
+	default:
+		return 0, false
+	}
+}
+
+// close closes the connection, failing pending requests and the producers
+// using the connection with the given error. Only the first error is kept.
+func (c *conn) close(err error) {
+	c.mu.Lock()
+	if c.mu.err != nil {
+		c.mu.Unlock()
+		return
+	}
+	c.mu.err = err
+	producers := c.mu.producers
+	c.mu.producers = make(map[uint64]*Producer)
+	close(c.done)
+	c.mu.Unlock()
+
+	_ = c.netConn.Close()
+	for _, p := range producers {
+		p.fail(err)
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package pulsarclient
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Frames of the Pulsar binary protocol are either simple commands:
+//
+//   [TOTAL_SIZE] [CMD_SIZE] [CMD]
+//
+// or commands which carry a payload, such as SEND:
+//
+//   [TOTAL_SIZE] [CMD_SIZE] [CMD] [MAGIC] [CHECKSUM] [METADATA_SIZE] [METADATA] [PAYLOAD]
+//
+// All sizes are 4 byte big-endian integers and exclude the size field itself.
+// The checksum is a CRC32-C of everything following it.
+
+const (
+	// magicCRC32C marks the checksum of a payload command.
+	magicCRC32C = 0x0e01
+	// DefaultMaxMessageSize is the maximum size of a message which is accepted
+	// by a broker, unless it advertises a different size when connecting.
+	DefaultMaxMessageSize = 5 << 20
+	// frameOverhead is the space reserved in a frame for the commands and
+	// metadata, on top of the maximum size of a message.
+	frameOverhead = 10 << 10
+)
+
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// frame is a decoded frame.
+type frame struct {
+	cmd BaseCommand
+	// metadata and payload are only set for payload commands.
+	metadata *MessageMetadata
+	payload  []byte
+}
+
+// encodeFrame encodes the command, and the metadata and payload if the
+// metadata is set.
+func encodeFrame(cmd *BaseCommand, metadata *MessageMetadata, payload []byte) ([]byte, error) {
+	cmdSize := cmd.Size()
+	totalSize := 4 + cmdSize
+	var metadataSize int
+	if metadata != nil {
+		metadataSize = metadata.Size()
+		totalSize += 2 + 4 + 4 + metadataSize + len(payload)
+	}
+	buf := make([]byte, 4+totalSize)
+	binary.BigEndian.PutUint32(buf, uint32(totalSize))
+	binary.BigEndian.PutUint32(buf[4:], uint32(cmdSize))
+	if _, err := cmd.MarshalTo(buf[8:]); err != nil {
+		return nil, err
+	}
+	if metadata == nil {
+		return buf, nil
+	}
+	offset := 8 + cmdSize
+	binary.BigEndian.PutUint16(buf[offset:], magicCRC32C)
+	checksumOffset := offset + 2
+	offset = checksumOffset + 4
+	binary.BigEndian.PutUint32(buf[offset:], uint32(metadataSize))
+	if _, err := metadata.MarshalTo(buf[offset+4:]); err != nil {
+		return nil, err
+	}
+	copy(buf[offset+4+metadataSize:], payload)
+	binary.BigEndian.PutUint32(buf[checksumOffset:], crc32.Checksum(buf[offset:], crc32cTable))
+	return buf, nil
+}
+
+// readFrame reads and decodes a frame which is at most maxSize bytes long.
+func readFrame(r io.Reader, maxSize int) (frame, error) {
+	var f frame
+	var sizeBuf [4]byte
+	if _, err := io.ReadFull(r, sizeBuf[:]); err != nil {
+		return f, err
+	}
+	totalSize := int(binary.BigEndian.Uint32(sizeBuf[:]))
+	if totalSize > maxSize {
+		return f, errors.Errorf("frame size %d exceeds the maximum of %d", totalSize, maxSize)
+	}
+	buf := make([]byte, totalSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return f, err
+	}
+	if len(buf) < 4 {
+		return f, errors.Errorf("frame of size %d is truncated", totalSize)
+	}
+	cmdSize := int(binary.BigEndian.Uint32(buf))
+	if 4+cmdSize > len(buf) {
+		return f, errors.Errorf("command size %d exceeds frame size %d", cmdSize, totalSize)
+	}
+	if err := f.cmd.Unmarshal(buf[4 : 4+cmdSize]); err != nil {
+		return f, errors.Wrap(err, "decoding command")
+	}
+	rest := buf[4+cmdSize:]
+	if len(rest) == 0 {
+		return f, nil
+	}
+
+	// The checksum is optional in the protocol.
+	if len(rest) >= 6 && binary.BigEndian.Uint16(rest) == magicCRC32C {
+		checksum := binary.BigEndian.Uint32(rest[2:])
+		rest = rest[6:]
+		if crc32.Checksum(rest, crc32cTable) != checksum {
+			return f, errors.New("checksum mismatch")
+		}
+	}
+	if len(rest) < 4 {
+		return f, errors.New("metadata of frame is truncated")
+	}
+	metadataSize := int(binary.BigEndian.Uint32(rest))
+	if 4+metadataSize > len(rest) {
+		return f, errors.Errorf("metadata size %d exceeds frame size %d", metadataSize, totalSize)
+	}
+	f.metadata = &MessageMetadata{}
+	if err := f.metadata.Unmarshal(rest[4 : 4+metadataSize]); err != nil {
+		return f, errors.Wrap(err, "decoding metadata")
+	}
+	f.payload = rest[4+metadataSize:]
+	return f, nil
+}
+
+// ReadCommand reads a frame sent by a client and returns its command, along
+// with the messages of the batch carried by SEND commands. Together with
+// EncodeCommand, it allows brokers to be mocked in tests.
+func ReadCommand(r io.Reader) (*BaseCommand, []Message, error) {
+	f, err := readFrame(r, DefaultMaxMessageSize+frameOverhead)
+	if err != nil {
+		return nil, nil, err
+	}
+	if f.metadata == nil {
+		return &f.cmd, nil, nil
+	}
+	n := 1
+	if f.metadata.NumMessagesInBatch != nil {
+		n = int(*f.metadata.NumMessagesInBatch)
+	}
+	msgs, err := decodeBatch(f.payload, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &f.cmd, msgs, nil
+}
+
+// EncodeCommand encodes a frame which carries a command without a payload.
+func EncodeCommand(cmd *BaseCommand) ([]byte, error) {
+	return encodeFrame(cmd, nil, nil)
+}
+
+// Message is a message which is sent by a Producer.
+type Message struct {
+	// Key is the partition key of the message.
+	Key string
+	// Payload is the content of the message.
+	Payload []byte
+}
+
+// encodeBatch encodes the messages as the payload of a batch, in which every
+// message is preceded by the size of its metadata and the metadata.
+func encodeBatch(msgs []Message) ([]byte, error) {
+	var size int
+	metadata := make([]SingleMessageMetadata, len(msgs))
+	for i := range msgs {
+		metadata[i].PayloadSize = int32(len(msgs[i].Payload))
+		if msgs[i].Key != "" {
+			key := msgs[i].Key
+			metadata[i].PartitionKey = &key
+		}
+		size += 4 + metadata[i].Size() + len(msgs[i].Payload)
+	}
+	buf := make([]byte, size)
+	offset := 0
+	for i := range msgs {
+		metadataSize := metadata[i].Size()
+		binary.BigEndian.PutUint32(buf[offset:], uint32(metadataSize))
+		offset += 4
+		if _, err := metadata[i].MarshalTo(buf[offset:]); err != nil {
+			return nil, err
+		}
+		offset += metadataSize
+		offset += copy(buf[offset:], msgs[i].Payload)
+	}
+	return buf, nil
+}
+
+// decodeBatch decodes the payload of a batch of n messages.
+func decodeBatch(payload []byte, n int) ([]Message, error) {
+	msgs := make([]Message, n)
+	for i := range msgs {
+		if len(payload) < 4 {
+			return nil, errors.Errorf("message %d of batch is truncated", i)
+		}
+		metadataSize := int(binary.BigEndian.Uint32(payload))
+		payload = payload[4:]
+		if metadataSize > len(payload) {
+			return nil, errors.Errorf("metadata of message %d of batch is truncated", i)
+		}
+		var metadata SingleMessageMetadata
+		if err := metadata.Unmarshal(payload[:metadataSize]); err != nil {
+			return nil, err
+		}
+		payload = payload[metadataSize:]
+		if int(metadata.PayloadSize) > len(payload) {
+			return nil, errors.Errorf("payload of message %d of batch is truncated", i)
+		}
+		if metadata.PartitionKey != nil {
+			msgs[i].Key = *metadata.PartitionKey
+		}
+		msgs[i].Payload = payload[:metadata.PayloadSize]
+		payload = payload[metadata.PayloadSize:]
+	}
+	return msgs, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package pulsarclient
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	msgs := []Message{
+		{Key: "a", Payload: []byte("first")},
+		{Payload: []byte("no key")},
+		{Key: "c", Payload: nil},
+	}
+	payload, err := encodeBatch(msgs)
+	require.NoError(t, err)
+	cmd := &BaseCommand{
+		Type: BaseCommand_SEND.Enum(),
+		Send: &CommandSend{ProducerID: 1, SequenceID: 2, NumMessages: proto.Int32(3)},
+	}
+	metadata := &MessageMetadata{ProducerName: "p", SequenceID: 2, NumMessagesInBatch: proto.Int32(3)}
+	buf, err := encodeFrame(cmd, metadata, payload)
+	require.NoError(t, err)
+
+	f, err := readFrame(bytes.NewReader(buf), len(buf))
+	require.NoError(t, err)
+	require.Equal(t, *cmd, f.cmd)
+	require.Equal(t, metadata, f.metadata)
+	decoded, err := decodeBatch(f.payload, 3)
+	require.NoError(t, err)
+	require.Equal(t, []Message{
+		{Key: "a", Payload: []byte("first")},
+		{Payload: []byte("no key")},
+		{Key: "c", Payload: []byte{}},
+	}, decoded)
+
+	// A corrupted payload fails the checksum.
+	buf[len(buf)-1] ^= 0xff
+	_, err = readFrame(bytes.NewReader(buf), len(buf))
+	require.EqualError(t, err, "checksum mismatch")
+
+	// Frames which exceed the maximum size are rejected.
+	_, err = readFrame(bytes.NewReader(buf), len(buf)-5)
+	require.Error(t, err)
+}
+
+// TestFrameEncoding checks the encoding of frames byte for byte against
+// frames laid out by hand as described in the documentation of the Pulsar
+// binary protocol, so that changes to the encoding or to the field numbers in
+// api.proto which would break the interoperability with brokers are caught.
+func TestFrameEncoding(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		name     string
+		cmd      *BaseCommand
+		metadata *MessageMetadata
+		payload  []byte
+		expected []byte
+	}{
+		{
+			name:     "ping",
+			cmd:      &BaseCommand{Type: BaseCommand_PING.Enum(), Ping: &CommandPing{}},
+			expected: []byte{
+				0x00, 0x00, 0x00, 0x09, // total size
+				0x00, 0x00, 0x00, 0x05, // command size
+				0x08, 0x12, // type = PING (18)
+				0x92, 0x01, 0x00, // ping (field 18) = {}
+			},
+		},
+		{
+			name: "send",
+			cmd: &BaseCommand{
+				Type: BaseCommand_SEND.Enum(),
+				Send: &CommandSend{ProducerID: 1, SequenceID: 2, NumMessages: proto.Int32(1)},
+			},
+			metadata: &MessageMetadata{ProducerName: "p", SequenceID: 2, PublishTime: 3},
+			payload:  []byte("hi"),
+			expected: []byte{
+				0x00, 0x00, 0x00, 0x21, // total size
+				0x00, 0x00, 0x00, 0x0a, // command size
+				0x08, 0x06, // type = SEND (6)
+				0x32, 0x06, 0x08, 0x01, 0x10, 0x02, 0x18, 0x01, // send (field 6)
+				0x0e, 0x01, // magic
+				0x41, 0x95, 0x52, 0x13, // CRC32-C of the rest of the frame
+				0x00, 0x00, 0x00, 0x07, // metadata size
+				0x0a, 0x01, 0x70, 0x10, 0x02, 0x18, 0x03, // metadata
+				0x68, 0x69, // payload
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			buf, err := encodeFrame(tc.cmd, tc.metadata, tc.payload)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, buf)
+
+			f, err := readFrame(bytes.NewReader(tc.expected), len(tc.expected))
+			require.NoError(t, err)
+			require.Equal(t, *tc.cmd, f.cmd)
+			require.Equal(t, tc.metadata, f.metadata)
+			if tc.metadata != nil {
+				require.Equal(t, tc.payload, f.payload)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package pulsarclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Producer sends batches of messages to a topic. Batches are persisted by the
+// broker in the order they are sent.
+type Producer struct {
+	client *Client
+	conn   *conn
+	id     uint64
+	name   string
+	topic  string
+
+	mu struct {
+		syncutil.Mutex
+		nextSequenceID uint64
+		// pending maps the sequence ID of every batch which has not been
+		// acknowledged yet to its callback.
+		pending map[uint64]func(error)
+		// err is set once the producer failed or was closed.
+		err error
+	}
+}
+
+// Topic returns the topic of the producer.
+func (p *Producer) Topic() string {
+	return p.topic
+}
+
+// SendAsync sends a batch of messages. The callback is called exactly once,
+// from a goroutine of the client, once the broker acknowledged or rejected the
+// batch, or the producer failed. It must not block.
+func (p *Producer) SendAsync(msgs []Message, callback func(error)) {
+	if len(msgs) == 0 {
+		callback(nil)
+		return
+	}
+	payload, err := encodeBatch(msgs)
+	if err != nil {
+		callback(err)
+		return
+	}
+	if len(payload) > p.conn.maxMessageSize {
+		callback(errors.Errorf("batch of %d bytes exceeds the maximum message size of %d bytes",
+			len(payload), p.conn.maxMessageSize))
+		return
+	}
+
+	p.mu.Lock()
+	if p.mu.err != nil {
+		err := p.mu.err
+		p.mu.Unlock()
+		callback(err)
+		return
+	}
+	sequenceID := p.mu.nextSequenceID
+	p.mu.nextSequenceID += uint64(len(msgs))
+	p.mu.pending[sequenceID] = callback
+	p.mu.Unlock()
+
+	numMessages := int32(len(msgs))
+	cmd := &BaseCommand{
+		Type: BaseCommand_SEND.Enum(),
+		Send: &CommandSend{
+			ProducerID:  p.id,
+			SequenceID:  sequenceID,
+			NumMessages: proto.Int32(numMessages),
+		},
+	}
+	metadata := &MessageMetadata{
+		ProducerName:       p.name,
+		SequenceID:         sequenceID,
+		PublishTime:        uint64(time.Now().UnixNano() / int64(time.Millisecond)),
+		NumMessagesInBatch: proto.Int32(numMessages),
+	}
+	// A write error closes the connection, which fails the producer and with
+	// it the callback.
+	_ = p.conn.write(cmd, metadata, payload)
+}
+
+// complete calls the callback of the batch with the given sequence ID.
+func (p *Producer) complete(sequenceID uint64, err error) {
+	p.mu.Lock()
+	callback, ok := p.mu.pending[sequenceID]
+	delete(p.mu.pending, sequenceID)
+	p.mu.Unlock()
+	if ok {
+		callback(err)
+	}
+}
+
+// fail fails the producer and the batches which have not been acknowledged.
+func (p *Producer) fail(err error) {
+	p.mu.Lock()
+	if p.mu.err == nil {
+		p.mu.err = err
+	}
+	pending := p.mu.pending
+	p.mu.pending = make(map[uint64]func(error))
+	p.mu.Unlock()
+	for _, callback := range pending {
+		callback(err)
+	}
+}
+
+// Close closes the producer. Batches which have not been acknowledged are
+// failed.
+func (p *Producer) Close(ctx context.Context) error {
+	p.mu.Lock()
+	closed := p.mu.err != nil
+	p.mu.Unlock()
+	defer p.fail(errors.Errorf("producer of topic %s is closed", p.topic))
+	if closed {
+		return nil
+	}
+
+	p.conn.unregisterProducer(p.id)
+	cmd := &BaseCommand{
+		Type:          BaseCommand_CLOSE_PRODUCER.Enum(),
+		CloseProducer: &CommandCloseProducer{ProducerID: p.id},
+	}
+	_, err := p.client.request(ctx, p.conn, cmd, func(id uint64) { cmd.CloseProducer.RequestID = id })
+	return err
+}
@@ -11,6 +11,7 @@ package changefeedccl
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -28,21 +29,36 @@ import (
 
 const confluentSchemaContentType = `application/vnd.schemaregistry.v1+json`
 
+// confluentSchemaType is the type of a schema registered with a confluent
+// schema registry.
+type confluentSchemaType string
+
+const (
+	confluentSchemaTypeAvro       confluentSchemaType = `AVRO`
+	confluentSchemaTypeProtobuf   confluentSchemaType = `PROTOBUF`
+	confluentSchemaTypeJSONSchema confluentSchemaType = `JSON`
+)
+
 type schemaRegistry interface {
 	// Ping tests the connectivity to the schema registry. A nil
 	// error is returned if the schema registry appears to be
 	// available.
 	Ping(ctx context.Context) error
 
-	// RegisterSchemaForSubject registers the given schema for the
-	// given subject. The returned int32 is a schema ID that can
-	// be used in Avro wire messages or in other calls to the
-	// schema registry.
-	RegisterSchemaForSubject(ctx context.Context, subject string, schema string) (int32, error)
+	// RegisterSchemaForSubject registers the given schema of the
+	// given type for the given subject. The returned int32 is a
+	// schema ID that can be used in confluent wire messages or in
+	// other calls to the schema registry.
+	RegisterSchemaForSubject(
+		ctx context.Context, subject string, schema string, schemaType confluentSchemaType,
+	) (int32, error)
 }
 
 type confluentSchemaVersionRequest struct {
 	Schema string `json:"schema"`
+	// SchemaType is omitted for Avro schemas, which registries which predate
+	// the other schema types expect.
+	SchemaType confluentSchemaType `json:"schemaType,omitempty"`
 }
 
 type confluentSchemaVersionResponse struct {
@@ -139,19 +155,22 @@ func (r *confluentSchemaRegistry) Ping(ctx context.Context) error {
 }
 
 // RegisterSchemaForSubject registers the given schema for the given
-// subject. The schema type is assumed to be AVRO.
+// subject.
 //
 //   https://docs.confluent.io/platform/current/schema-registry/develop/api.html#post--subjects-(string-%20subject)-versions
 //
 func (r *confluentSchemaRegistry) RegisterSchemaForSubject(
-	ctx context.Context, subject string, schema string,
+	ctx context.Context, subject string, schema string, schemaType confluentSchemaType,
 ) (int32, error) {
 	u := r.urlForPath(fmt.Sprintf("subjects/%s/versions", subject))
 	if log.V(1) {
-		log.Infof(ctx, "registering avro schema %s %s", u, schema)
+		log.Infof(ctx, "registering %s schema %s %s", schemaType, u, schema)
 	}
 
 	req := confluentSchemaVersionRequest{Schema: schema}
+	if schemaType != confluentSchemaTypeAvro {
+		req.SchemaType = schemaType
+	}
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(req); err != nil {
 		return 0, err
@@ -181,6 +200,20 @@ func (r *confluentSchemaRegistry) RegisterSchemaForSubject(
 	return id, nil
 }
 
+// confluentWireHeader returns the header of the confluent wire format, which
+// precedes messages encoded with the schema with the given registry ID.
+//
+//   https://docs.confluent.io/current/schema-registry/docs/serializer-formatter.html#wire-format
+//
+func confluentWireHeader(registryID int32) []byte {
+	header := []byte{
+		changefeedbase.ConfluentAvroWireFormatMagic,
+		0, 0, 0, 0, // Placeholder for the ID.
+	}
+	binary.BigEndian.PutUint32(header[1:5], uint32(registryID))
+	return header
+}
+
 func (r *confluentSchemaRegistry) doWithRetry(ctx context.Context, fn func() error) error {
 	// Since network services are often a source of flakes, add a few retries here
 	// before we give up and return an error that will bubble up and tear down the
@@ -13,6 +13,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/errors"
 )
@@ -226,3 +227,25 @@ func IsRegionalByRowChange(e TableEvent) bool {
 	et := classifyTableEvent(e)
 	return et.Contains(tableEventLocalityRegionalByRowChange)
 }
+
+// ChangedColumns returns the names of the visible columns which the event adds
+// to and drops from the table.
+func ChangedColumns(e TableEvent) (added, dropped []string) {
+	before := make(map[descpb.ColumnID]struct{}, len(e.Before.VisibleColumns()))
+	for _, col := range e.Before.VisibleColumns() {
+		before[col.GetID()] = struct{}{}
+	}
+	for _, col := range e.After.VisibleColumns() {
+		if _, ok := before[col.GetID()]; ok {
+			delete(before, col.GetID())
+		} else {
+			added = append(added, col.GetName())
+		}
+	}
+	for _, col := range e.Before.VisibleColumns() {
+		if _, ok := before[col.GetID()]; ok {
+			dropped = append(dropped, col.GetName())
+		}
+	}
+	return added, dropped
+}
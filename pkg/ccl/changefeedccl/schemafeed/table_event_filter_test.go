@@ -233,6 +233,54 @@ func TestTableEventIsOnlyPrimaryIndexChange(t *testing.T) {
 	}
 }
 
+func TestTableEventChangedColumns(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	ts := func(seconds int) hlc.Timestamp {
+		return hlc.Timestamp{WallTime: (time.Duration(seconds) * time.Second).Nanoseconds()}
+	}
+	var (
+		mkTableDesc     = schematestutils.MakeTableDesc
+		addColBackfill  = schematestutils.AddNewColumnBackfillMutation
+		dropColBackfill = schematestutils.AddColumnDropBackfillMutation
+	)
+	for _, c := range []struct {
+		name           string
+		e              TableEvent
+		added, dropped []string
+	}{
+		{
+			name: "add non-NULL column",
+			e: TableEvent{
+				Before: addColBackfill(mkTableDesc(42, 3, ts(2), 1, 1)),
+				After:  mkTableDesc(42, 4, ts(4), 2, 1),
+			},
+			added: []string{"c2"},
+		},
+		{
+			name: "drop column",
+			e: TableEvent{
+				Before: mkTableDesc(42, 1, ts(2), 2, 1),
+				After:  dropColBackfill(mkTableDesc(42, 2, ts(3), 1, 1)),
+			},
+			dropped: []string{"c2"},
+		},
+		{
+			name: "unknown table event",
+			e: TableEvent{
+				Before: mkTableDesc(42, 1, ts(2), 2, 1),
+				After:  mkTableDesc(42, 1, ts(2), 2, 1),
+			},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			added, dropped := ChangedColumns(c.e)
+			require.Equal(t, c.added, added)
+			require.Equal(t, c.dropped, dropped)
+		})
+	}
+}
+
 func TestTableEventFilterErrorsWithIncompletePolicy(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
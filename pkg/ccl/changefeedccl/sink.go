@@ -14,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
@@ -61,6 +62,34 @@ type SinkWithTopics interface {
 	Topics() []string
 }
 
+// SinkWithEncoder extends the Sink interface to include a method that encodes
+// and emits a row. It is implemented by sinks that write formats which cannot
+// be produced by an Encoder one row at a time, such as parquet.
+type SinkWithEncoder interface {
+	Sink
+	// EncodeAndEmitRow encodes the given row and enqueues it for asynchronous
+	// delivery on the sink, like EmitRow.
+	EncodeAndEmitRow(
+		ctx context.Context,
+		updatedRow cdcevent.Row,
+		prevRow cdcevent.Row,
+		topic TopicDescriptor,
+		updated, mvcc hlc.Timestamp,
+		alloc kvevent.Alloc,
+	) error
+}
+
+// sinkEncodesRows returns true if the rows of the changefeed must be emitted
+// with EncodeAndEmitRow, either because the sink writes the rows themselves or
+// because the format cannot be encoded one row at a time.
+func sinkEncodesRows(details jobspb.ChangefeedDetails) bool {
+	if changefeedbase.FormatType(details.Opts[changefeedbase.OptFormat]) == changefeedbase.OptFormatParquet {
+		return true
+	}
+	u, err := url.Parse(details.SinkURI)
+	return err == nil && isPostgresSink(u)
+}
+
 func getSink(
 	ctx context.Context,
 	serverCfg *execinfra.ServerConfig,
@@ -115,6 +144,14 @@ func getSink(
 				return makeWebhookSink(ctx, sinkURL{URL: u}, feedCfg.Opts,
 					defaultWorkerCount(), timeutil.DefaultTimeSource{}, metricsBuilder)
 			})
+		case isPulsarSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.PulsarValidOptions, func() (Sink, error) {
+				return makePulsarSink(ctx, sinkURL{URL: u}, AllTargets(feedCfg), feedCfg.Opts, metricsBuilder)
+			})
+		case isPostgresSink(u):
+			return validateOptionsAndMakeSink(changefeedbase.PostgresValidOptions, func() (Sink, error) {
+				return makePostgresSink(sinkURL{URL: u}, feedCfg.Opts, metricsBuilder)
+			})
 		case isPubsubSink(u):
 			// TODO: add metrics to pubsubsink
 			return validateOptionsAndMakeSink(changefeedbase.PubsubValidOptions, func() (Sink, error) {
@@ -241,6 +278,22 @@ type errorWrapperSink struct {
 	wrapped Sink
 }
 
+// errorWrapperEncoderSink is an errorWrapperSink of a sink which encodes rows.
+type errorWrapperEncoderSink struct {
+	errorWrapperSink
+}
+
+var _ SinkWithEncoder = errorWrapperEncoderSink{}
+
+// makeErrorWrapperSink returns an errorWrapperSink of the given sink, which
+// implements SinkWithEncoder if the sink does.
+func makeErrorWrapperSink(wrapped Sink) Sink {
+	if _, ok := wrapped.(SinkWithEncoder); ok {
+		return errorWrapperEncoderSink{errorWrapperSink{wrapped: wrapped}}
+	}
+	return errorWrapperSink{wrapped: wrapped}
+}
+
 // EmitRow implements Sink interface.
 func (s errorWrapperSink) EmitRow(
 	ctx context.Context,
@@ -255,6 +308,21 @@ func (s errorWrapperSink) EmitRow(
 	return nil
 }
 
+// EncodeAndEmitRow implements SinkWithEncoder interface.
+func (s errorWrapperEncoderSink) EncodeAndEmitRow(
+	ctx context.Context,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	if err := s.wrapped.(SinkWithEncoder).EncodeAndEmitRow(ctx, updatedRow, prevRow, topic, updated, mvcc, alloc); err != nil {
+		return changefeedbase.MarkRetryableError(err)
+	}
+	return nil
+}
+
 // EmitResolvedTimestamp implements Sink interface.
 func (s errorWrapperSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
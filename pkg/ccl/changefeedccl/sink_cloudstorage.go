@@ -10,7 +10,6 @@ package changefeedccl
 
 import (
 	"bytes"
-	"compress/gzip"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
@@ -18,11 +17,13 @@ import (
 	"io"
 	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
@@ -69,6 +70,10 @@ type cloudStorageSinkFile struct {
 	buf         bytes.Buffer
 	alloc       kvevent.Alloc
 	oldestMVCC  hlc.Timestamp
+
+	// parquetWriter is set for files in the parquet format, which are written
+	// by it rather than through Write.
+	parquetWriter *parquetWriter
 }
 
 var _ io.Writer = &cloudStorageSinkFile{}
@@ -288,8 +293,11 @@ type cloudStorageSink struct {
 
 	ext          string
 	rowDelimiter []byte
+	format       changefeedbase.FormatType
+	withUpdated  bool
 
-	compression string
+	compression      cloud.CompressionCodec
+	compressionLevel int
 
 	es cloud.ExternalStorage
 
@@ -309,7 +317,7 @@ type cloudStorageSink struct {
 	metrics           metricsRecorder
 }
 
-const sinkCompressionGzip = "gzip"
+var _ SinkWithEncoder = (*cloudStorageSink)(nil)
 
 var cloudStorageSinkIDAtomic int64
 
@@ -385,7 +393,8 @@ func makeCloudStorageSink(
 		s.dataFilePartition = s.timestampOracle.inclusiveLowerBoundTS().GoTime().Format(s.partitionFormat)
 	}
 
-	switch changefeedbase.FormatType(opts[changefeedbase.OptFormat]) {
+	s.format = changefeedbase.FormatType(opts[changefeedbase.OptFormat])
+	switch s.format {
 	case changefeedbase.OptFormatJSON:
 		// TODO(dan): It seems like these should be on the encoder, but that
 		// would require a bit of refactoring.
@@ -396,6 +405,9 @@ func makeCloudStorageSink(
 		// would require a bit of refactoring.
 		s.ext = `.csv`
 		s.rowDelimiter = []byte{'\n'}
+	case changefeedbase.OptFormatParquet:
+		s.ext = `.parquet`
+		_, s.withUpdated = opts[changefeedbase.OptUpdatedTimestamps]
 	default:
 		return nil, errors.Errorf(`this sink is incompatible with %s=%s`,
 			changefeedbase.OptFormat, opts[changefeedbase.OptFormat])
@@ -413,11 +425,29 @@ func makeCloudStorageSink(
 	}
 
 	if codec, ok := opts[changefeedbase.OptCompression]; ok && codec != "" {
-		if strings.EqualFold(codec, "gzip") {
-			s.compression = sinkCompressionGzip
-			s.ext = s.ext + ".gz"
+		if s.compression, err = cloud.ParseCompressionCodec(codec); err != nil {
+			return nil, err
+		}
+		// Parquet files are compressed by the parquet writer, which keeps the
+		// file readable by parquet consumers.
+		if s.format == changefeedbase.OptFormatParquet {
+			if _, err := parquetCompressionCodec(s.compression); err != nil {
+				return nil, err
+			}
 		} else {
-			return nil, errors.Errorf(`unsupported compression codec %q`, codec)
+			s.ext = s.ext + s.compression.Extension()
+		}
+	}
+	if level, ok := opts[changefeedbase.OptCompressionLevel]; ok {
+		if s.compression == cloud.NoCompression || s.format == changefeedbase.OptFormatParquet {
+			return nil, errors.Errorf(`%s requires a %s codec and a format other than %s`,
+				changefeedbase.OptCompressionLevel, changefeedbase.OptCompression, changefeedbase.OptFormatParquet)
+		}
+		if s.compressionLevel, err = strconv.Atoi(level); err != nil {
+			return nil, errors.Wrapf(err, `invalid %s`, changefeedbase.OptCompressionLevel)
+		}
+		if err := s.compression.ValidateLevel(s.compressionLevel); err != nil {
+			return nil, err
 		}
 	}
 
@@ -436,7 +466,7 @@ func makeCloudStorageSink(
 
 func (s *cloudStorageSink) getOrCreateFile(
 	topic TopicDescriptor, eventMVCC hlc.Timestamp,
-) *cloudStorageSinkFile {
+) (*cloudStorageSinkFile, error) {
 	name, _ := s.topicNamer.Name(topic)
 	key := cloudStorageSinkKey{name, int64(topic.GetVersion())}
 	if item := s.files.Get(key); item != nil {
@@ -444,19 +474,21 @@ func (s *cloudStorageSink) getOrCreateFile(
 		if eventMVCC.Less(f.oldestMVCC) {
 			f.oldestMVCC = eventMVCC
 		}
-		return f
+		return f, nil
 	}
 	f := &cloudStorageSinkFile{
 		created:             timeutil.Now(),
 		cloudStorageSinkKey: key,
 		oldestMVCC:          eventMVCC,
 	}
-	switch s.compression {
-	case sinkCompressionGzip:
-		f.codec = gzip.NewWriter(&f.buf)
+	if s.compression != cloud.NoCompression && s.format != changefeedbase.OptFormatParquet {
+		var err error
+		if f.codec, err = cloud.NewCompressor(&f.buf, s.compression, s.compressionLevel); err != nil {
+			return nil, err
+		}
 	}
 	s.files.ReplaceOrInsert(f)
-	return f
+	return f, nil
 }
 
 // EmitRow implements the Sink interface.
@@ -472,7 +504,10 @@ func (s *cloudStorageSink) EmitRow(
 	}
 
 	s.metrics.recordMessageSize(int64(len(key) + len(value)))
-	file := s.getOrCreateFile(topic, mvcc)
+	file, err := s.getOrCreateFile(topic, mvcc)
+	if err != nil {
+		return err
+	}
 	file.alloc.Merge(&alloc)
 
 	if _, err := file.Write(value); err != nil {
@@ -490,6 +525,56 @@ func (s *cloudStorageSink) EmitRow(
 	return nil
 }
 
+// EncodeAndEmitRow implements the SinkWithEncoder interface. It is used for
+// the parquet format, which buffers the rows of each file in a parquetWriter.
+func (s *cloudStorageSink) EncodeAndEmitRow(
+	ctx context.Context,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	if s.files == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+	if s.format != changefeedbase.OptFormatParquet {
+		return errors.AssertionFailedf("rows in the %s format must be encoded before they are emitted", s.format)
+	}
+
+	file, err := s.getOrCreateFile(topic, mvcc)
+	if err != nil {
+		return err
+	}
+	file.alloc.Merge(&alloc)
+
+	if file.parquetWriter == nil {
+		compression, err := parquetCompressionCodec(s.compression)
+		if err != nil {
+			return err
+		}
+		file.parquetWriter, err = newParquetWriter(updatedRow, &file.buf, compression, s.withUpdated)
+		if err != nil {
+			return err
+		}
+	}
+	prevSize := file.parquetWriter.size()
+	if err := file.parquetWriter.addRow(updatedRow, prevRow, updated, mvcc); err != nil {
+		return err
+	}
+	rowSize := file.parquetWriter.size() - prevSize
+	s.metrics.recordMessageSize(rowSize)
+	file.rawSize += int(rowSize)
+	file.numMessages++
+
+	if file.parquetWriter.size() > s.targetMaxFileSize {
+		if err := s.flushTopicVersions(ctx, file.topic, file.schemaID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // EmitResolvedTimestamp implements the Sink interface.
 func (s *cloudStorageSink) EmitResolvedTimestamp(
 	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
@@ -592,6 +677,11 @@ func (s *cloudStorageSink) flushFile(ctx context.Context, file *cloudStorageSink
 			return err
 		}
 	}
+	if file.parquetWriter != nil {
+		if err := file.parquetWriter.close(); err != nil {
+			return err
+		}
+	}
 
 	// We use this monotonically increasing fileID to ensure correct ordering
 	// among files emitted at the same timestamp during the same job session.
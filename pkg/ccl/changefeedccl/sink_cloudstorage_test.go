@@ -10,7 +10,6 @@ package changefeedccl
 
 import (
 	"bytes"
-	"compress/gzip"
 	"context"
 	"fmt"
 	"io/ioutil"
@@ -72,8 +71,8 @@ func TestCloudStorageSink(t *testing.T) {
 	dir, dirCleanupFn := testutils.TempDir(t)
 	defer dirCleanupFn()
 
-	gzipDecompress := func(t *testing.T, compressed []byte) []byte {
-		r, err := gzip.NewReader(bytes.NewReader(compressed))
+	decompress := func(t *testing.T, codec cloud.CompressionCodec, compressed []byte) []byte {
+		r, err := cloud.NewDecompressingReader(bytes.NewReader(compressed), codec)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -139,8 +138,12 @@ func TestCloudStorageSink(t *testing.T) {
 			if err != nil {
 				return err
 			}
-			if strings.HasSuffix(path, ".gz") {
-				file = gzipDecompress(t, file)
+			for _, codec := range []cloud.CompressionCodec{
+				cloud.CompressionGzip, cloud.CompressionZstd, cloud.CompressionSnappy, cloud.CompressionLZ4,
+			} {
+				if strings.HasSuffix(path, codec.Extension()) {
+					file = decompress(t, codec, file)
+				}
 			}
 			files = append(files, string(file))
 			return nil
@@ -226,7 +229,7 @@ func TestCloudStorageSink(t *testing.T) {
 		defer func() {
 			opts[changefeedbase.OptCompression] = before
 		}()
-		for _, compression := range []string{"", "gzip"} {
+		for _, compression := range []string{"", "gzip", "zstd", "snappy", "lz4"} {
 			opts[changefeedbase.OptCompression] = compression
 			t.Run("compress="+compression, func(t *testing.T) {
 				t1 := makeTopic(`t1`)
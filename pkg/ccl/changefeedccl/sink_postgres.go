@@ -0,0 +1,559 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	gosql "database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	// Register the postgres driver used to connect to the target database.
+	_ "github.com/lib/pq"
+)
+
+// postgresMaxStatementParams bounds the number of placeholders in each
+// statement applied by the postgres sink. Postgres rejects statements with
+// more than 65535 placeholders.
+const postgresMaxStatementParams = 1 << 14
+
+func isPostgresSink(u *url.URL) bool {
+	switch u.Scheme {
+	case changefeedbase.SinkSchemePostgres, changefeedbase.SinkSchemePostgresql:
+		return true
+	default:
+		return false
+	}
+}
+
+// postgresSink mirrors the watched tables into tables of the same name and
+// schema in a database which speaks the Postgres wire protocol, such as
+// another CockroachDB cluster. Updated rows are upserted into the mirror
+// table, and deleted rows are deleted from it.
+//
+// Rows are buffered, keeping only the latest change of each key, and applied
+// in a single transaction by Flush, which the changefeed calls before it emits
+// each resolved timestamp. The mirror tables are therefore consistent as of a
+// resolved timestamp, up to the duplicates of at-least-once delivery, which
+// are harmless since each change is idempotent.
+//
+// The mirror tables are created when the first row of a table is emitted.
+// When rows with a new version of a table descriptor are emitted, columns
+// which were added to or dropped from the watched table are added to or
+// dropped from the mirror table. The schema_change_policy decides which rows
+// are emitted then: with backfill, every row is emitted again, which populates
+// the added columns; with nobackfill, the added columns are only written when
+// rows are updated; with stop, the changefeed stops before any row with the
+// new schema is emitted. Changes to the primary key or to the type of a column
+// are not applied. The columns created by the sink are marked with a comment,
+// and only those columns are dropped, so that the columns which were added to
+// the mirror tables on the target are preserved.
+//
+// The mirror tables are created in the current schema of the connection to the
+// target, and are always referred to by their fully qualified names.
+type postgresSink struct {
+	db  *gosql.DB
+	uri string
+	// database and schema are the current database and schema of the
+	// connection to the target.
+	database, schema string
+
+	tables map[descpb.ID]*postgresMirrorTable
+	// pending holds the tables with buffered changes, in the order in which
+	// they were first changed.
+	pending []*postgresMirrorTable
+	alloc   kvevent.Alloc
+
+	metrics metricsRecorder
+}
+
+// postgresMirrorTable is a table the postgres sink writes to, along with the
+// changes buffered for it.
+type postgresMirrorTable struct {
+	// name is the fully qualified name of the mirror table.
+	name    string
+	version descpb.DescriptorVersion
+	// keyColumns holds the names of the primary key columns.
+	keyColumns []string
+
+	// rows holds the latest buffered change of each key, and rowIdx maps the
+	// encoded key of each change to its index in rows.
+	rows   []postgresMirrorRow
+	rowIdx map[string]int
+	// upsertColumns holds the names of the columns written by the buffered
+	// upserts, in the order of their values.
+	upsertColumns []string
+}
+
+// postgresMirrorRow is a change buffered by the postgres sink. Deleted rows
+// only hold the values of the primary key columns.
+type postgresMirrorRow struct {
+	deleted bool
+	values  []interface{}
+}
+
+func makePostgresSink(
+	u sinkURL, opts map[string]string, mb metricsRecorderBuilder,
+) (Sink, error) {
+	if _, ok := opts[changefeedbase.OptSplitColumnFamilies]; ok {
+		return nil, errors.Errorf(`%s is not supported by the %s sink`,
+			changefeedbase.OptSplitColumnFamilies, u.Scheme)
+	}
+	if strings.TrimPrefix(u.Path, `/`) == `` {
+		return nil, errors.Errorf(`must specify database`)
+	}
+
+	uri := u.String()
+	u.consumeParam(`sslcert`)
+	u.consumeParam(`sslkey`)
+	u.consumeParam(`sslmode`)
+	u.consumeParam(`sslrootcert`)
+	u.consumeParam(`application_name`)
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown postgres sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	return &postgresSink{
+		uri:     uri,
+		tables:  make(map[descpb.ID]*postgresMirrorTable),
+		metrics: mb(requiresResourceAccounting),
+	}, nil
+}
+
+// Dial implements the Sink interface.
+func (s *postgresSink) Dial() error {
+	db, err := gosql.Open(`postgres`, s.uri)
+	if err != nil {
+		return err
+	}
+	var schema gosql.NullString
+	if err := db.QueryRow(`SELECT current_database(), current_schema()`).Scan(
+		&s.database, &schema,
+	); err != nil {
+		return errors.CombineErrors(err, db.Close())
+	}
+	if !schema.Valid {
+		return errors.CombineErrors(
+			errors.New(`the search_path of the postgres sink does not contain any existing schema`),
+			db.Close(),
+		)
+	}
+	s.schema = schema.String
+	s.db = db
+	return nil
+}
+
+// EmitRow implements the Sink interface.
+func (s *postgresSink) EmitRow(
+	ctx context.Context,
+	topic TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	return errors.AssertionFailedf("rows must be emitted to the postgres sink with EncodeAndEmitRow")
+}
+
+// EncodeAndEmitRow implements the SinkWithEncoder interface.
+func (s *postgresSink) EncodeAndEmitRow(
+	ctx context.Context,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	if s.db == nil {
+		return errors.New(`cannot EmitRow on a closed sink`)
+	}
+	s.alloc.Merge(&alloc)
+
+	table, err := s.getOrCreateTable(ctx, updatedRow)
+	if err != nil {
+		return err
+	}
+
+	var encodedKey strings.Builder
+	var row postgresMirrorRow
+	var size int
+	if err := updatedRow.ForEachKeyColumn().Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+		encodedKey.WriteString(tree.AsString(d))
+		encodedKey.WriteByte(',')
+		if updatedRow.IsDeleted() {
+			v := postgresValue(d)
+			size += postgresValueSize(v)
+			row.values = append(row.values, v)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if updatedRow.IsDeleted() {
+		row.deleted = true
+	} else {
+		var columns []string
+		if err := updatedRow.ForEachColumn().Datum(func(d tree.Datum, col cdcevent.ResultColumn) error {
+			v := postgresValue(d)
+			size += postgresValueSize(v)
+			columns = append(columns, col.Name)
+			row.values = append(row.values, v)
+			return nil
+		}); err != nil {
+			return err
+		}
+		table.upsertColumns = columns
+	}
+	defer s.metrics.recordOneMessage()(mvcc, size, sinkDoesNotCompress)
+
+	if len(table.rows) == 0 {
+		s.pending = append(s.pending, table)
+	}
+	if idx, ok := table.rowIdx[encodedKey.String()]; ok {
+		table.rows[idx] = row
+	} else {
+		table.rowIdx[encodedKey.String()] = len(table.rows)
+		table.rows = append(table.rows, row)
+	}
+	return nil
+}
+
+// getOrCreateTable returns the mirror table of the row, creating the table on
+// the target or applying schema changes to it if the row is the first one
+// emitted with its table descriptor version.
+func (s *postgresSink) getOrCreateTable(
+	ctx context.Context, row cdcevent.Row,
+) (*postgresMirrorTable, error) {
+	prev, ok := s.tables[row.TableID]
+	if ok && prev.version == row.Version {
+		return prev, nil
+	}
+	if ok && len(prev.rows) > 0 {
+		// The buffered changes were made with the previous version of the
+		// table descriptor, so they are applied before the mirror table is
+		// altered.
+		if err := s.Flush(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	tn := tree.MakeTableNameWithSchema(
+		tree.Name(s.database), tree.Name(s.schema), tree.Name(row.TableName),
+	)
+	name := tn.FQString()
+	existing, err := s.mirrorColumns(ctx, row.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	table := &postgresMirrorTable{
+		name:    name,
+		version: row.Version,
+		rowIdx:  make(map[string]int),
+	}
+	if err := row.ForEachKeyColumn().Col(func(col cdcevent.ResultColumn) error {
+		table.keyColumns = append(table.keyColumns, col.Name)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(existing) == 0 {
+		var stmt strings.Builder
+		fmt.Fprintf(&stmt, `CREATE TABLE IF NOT EXISTS %s (`, name)
+		var comments []string
+		if err := row.ForEachColumn().Col(func(col cdcevent.ResultColumn) error {
+			fmt.Fprintf(&stmt, `%s %s, `, tree.NameString(col.Name), postgresColumnType(col.Typ))
+			comments = append(comments, postgresMarkColumn(name, col.Name))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&stmt, `PRIMARY KEY (%s))`, postgresColumnList(table.keyColumns))
+		for _, stmt := range append([]string{stmt.String()}, comments...) {
+			if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+				return nil, errors.Wrapf(err, `creating mirror table %s`, name)
+			}
+		}
+		s.tables[row.TableID] = table
+		return table, nil
+	}
+
+	var alterations []string
+	if err := row.ForEachColumn().Col(func(col cdcevent.ResultColumn) error {
+		if _, ok := existing[col.Name]; ok {
+			delete(existing, col.Name)
+		} else {
+			alterations = append(alterations, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s`,
+				name, tree.NameString(col.Name), postgresColumnType(col.Typ)))
+			alterations = append(alterations, postgresMarkColumn(name, col.Name))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	// The remaining existing columns are either columns which were dropped from
+	// the watched table, or columns which were added to the mirror table on
+	// the target.
+	for col, created := range existing {
+		if !created {
+			continue
+		}
+		alterations = append(alterations, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN IF EXISTS %s`,
+			name, tree.NameString(col)))
+	}
+	for _, stmt := range alterations {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return nil, errors.Wrapf(err, `altering mirror table %s`, name)
+		}
+	}
+	s.tables[row.TableID] = table
+	return table, nil
+}
+
+// postgresCreatedColumnComment is the comment of the columns of the mirror
+// tables which were created by the sink. It outlives the sink, so that the
+// columns are known to be owned by the changefeed after it restarts.
+const postgresCreatedColumnComment = `created by a CockroachDB changefeed`
+
+// postgresMarkColumn returns the statement which marks a column of the mirror
+// table with the given fully qualified name as created by the sink.
+func postgresMarkColumn(tableName, column string) string {
+	return fmt.Sprintf(`COMMENT ON COLUMN %s.%s IS %s`, tableName, tree.NameString(column),
+		lexbase.EscapeSQLString(postgresCreatedColumnComment))
+}
+
+// mirrorColumns returns the names of the columns of the mirror table with the
+// given name, which are empty if the table does not exist, along with whether
+// each column was created by the sink.
+func (s *postgresSink) mirrorColumns(
+	ctx context.Context, tableName string,
+) (map[string]bool, error) {
+	// The pg_catalog tables only describe the current database.
+	rows, err := s.db.QueryContext(ctx, `SELECT a.attname, col_description(a.attrelid, a.attnum)
+FROM pg_catalog.pg_attribute AS a
+JOIN pg_catalog.pg_class AS c ON c.oid = a.attrelid
+JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind = 'r'
+AND a.attnum > 0 AND NOT a.attisdropped`,
+		s.schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var col string
+		var comment gosql.NullString
+		if err := rows.Scan(&col, &comment); err != nil {
+			return nil, err
+		}
+		columns[col] = comment.String == postgresCreatedColumnComment
+	}
+	return columns, rows.Err()
+}
+
+// EmitResolvedTimestamp implements the Sink interface. Resolved timestamps are
+// not written to the target; the changes buffered before a resolved timestamp
+// are applied by the Flush that precedes it.
+func (s *postgresSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+	return nil
+}
+
+// Flush implements the Sink interface.
+func (s *postgresSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+
+	if len(s.pending) == 0 {
+		return nil
+	}
+	txn, err := s.db.BeginTx(ctx, nil /* opts */)
+	if err != nil {
+		return err
+	}
+	for _, table := range s.pending {
+		if err := applyMirrorChanges(ctx, txn, table); err != nil {
+			return errors.CombineErrors(err, txn.Rollback())
+		}
+	}
+	if err := txn.Commit(); err != nil {
+		return err
+	}
+
+	for _, table := range s.pending {
+		table.rows = table.rows[:0]
+		table.rowIdx = make(map[string]int)
+	}
+	s.pending = s.pending[:0]
+	s.alloc.Release(ctx)
+	return nil
+}
+
+// applyMirrorChanges applies the changes buffered for the table in the
+// transaction, batching the deletes and the upserts into as few statements as
+// possible.
+func applyMirrorChanges(ctx context.Context, txn *gosql.Tx, table *postgresMirrorTable) error {
+	var deletes, upserts [][]interface{}
+	for _, row := range table.rows {
+		if row.deleted {
+			deletes = append(deletes, row.values)
+		} else {
+			upserts = append(upserts, row.values)
+		}
+	}
+
+	deletePrefix := fmt.Sprintf(`DELETE FROM %s WHERE (%s) IN (`,
+		table.name, postgresColumnList(table.keyColumns))
+	if err := execBatches(ctx, txn, deletePrefix, `)`, len(table.keyColumns), deletes); err != nil {
+		return errors.Wrapf(err, `deleting from mirror table %s`, table.name)
+	}
+
+	if len(upserts) == 0 {
+		return nil
+	}
+	upsertPrefix := fmt.Sprintf(`INSERT INTO %s (%s) VALUES `,
+		table.name, postgresColumnList(table.upsertColumns))
+	keys := make(map[string]struct{}, len(table.keyColumns))
+	for _, col := range table.keyColumns {
+		keys[col] = struct{}{}
+	}
+	var updates []string
+	for _, col := range table.upsertColumns {
+		if _, ok := keys[col]; !ok {
+			updates = append(updates, fmt.Sprintf(`%[1]s = excluded.%[1]s`, tree.NameString(col)))
+		}
+	}
+	upsertSuffix := fmt.Sprintf(` ON CONFLICT (%s) DO NOTHING`, postgresColumnList(table.keyColumns))
+	if len(updates) > 0 {
+		upsertSuffix = fmt.Sprintf(` ON CONFLICT (%s) DO UPDATE SET %s`,
+			postgresColumnList(table.keyColumns), strings.Join(updates, `, `))
+	}
+	if err := execBatches(ctx, txn, upsertPrefix, upsertSuffix, len(table.upsertColumns), upserts); err != nil {
+		return errors.Wrapf(err, `upserting into mirror table %s`, table.name)
+	}
+	return nil
+}
+
+// execBatches executes statements made of the prefix, a list of tuples of
+// placeholders and the suffix, which together bind the values of every row.
+func execBatches(
+	ctx context.Context, txn *gosql.Tx, prefix, suffix string, width int, rows [][]interface{},
+) error {
+	batchSize := postgresMaxStatementParams / width
+	for len(rows) > 0 {
+		batch := rows
+		if len(batch) > batchSize {
+			batch = batch[:batchSize]
+		}
+		rows = rows[len(batch):]
+
+		var stmt strings.Builder
+		stmt.WriteString(prefix)
+		args := make([]interface{}, 0, len(batch)*width)
+		for i, row := range batch {
+			if i > 0 {
+				stmt.WriteString(`, `)
+			}
+			stmt.WriteString(`(`)
+			for j, v := range row {
+				if j > 0 {
+					stmt.WriteString(`, `)
+				}
+				args = append(args, v)
+				fmt.Fprintf(&stmt, `$%d`, len(args))
+			}
+			stmt.WriteString(`)`)
+		}
+		stmt.WriteString(suffix)
+		if _, err := txn.ExecContext(ctx, stmt.String(), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements the Sink interface.
+func (s *postgresSink) Close() error {
+	s.alloc.Release(context.Background())
+	if s.db == nil {
+		return nil
+	}
+	err := s.db.Close()
+	s.db = nil
+	return err
+}
+
+// postgresColumnList returns the quoted and comma separated column names.
+func postgresColumnList(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = tree.NameString(col)
+	}
+	return strings.Join(quoted, `, `)
+}
+
+// postgresColumnType returns the type of the mirror column of a column with
+// the given type. User defined types, which may not exist on the target, are
+// mirrored as text.
+func postgresColumnType(t *types.T) string {
+	switch {
+	case t.UserDefined():
+		return `text`
+	case t.Family() == types.ArrayFamily && t.ArrayContents().UserDefined():
+		return `text[]`
+	default:
+		return t.SQLStandardName()
+	}
+}
+
+// postgresValue returns the value of a placeholder which binds the datum.
+// Values are sent in the text format, which both Postgres and CockroachDB
+// parse according to the type of the column they are written to.
+func postgresValue(d tree.Datum) interface{} {
+	if d == tree.DNull {
+		return nil
+	}
+	switch t := tree.UnwrapDOidWrapper(d).(type) {
+	case *tree.DBytes:
+		return []byte(*t)
+	case *tree.DString:
+		return string(*t)
+	case *tree.DCollatedString:
+		return t.Contents
+	case *tree.DEnum:
+		return t.LogicalRep
+	default:
+		return tree.AsStringWithFlags(d, tree.FmtPgwireText)
+	}
+}
+
+func postgresValueSize(v interface{}) int {
+	switch t := v.(type) {
+	case []byte:
+		return len(t)
+	case string:
+		return len(t)
+	default:
+		return 0
+	}
+}
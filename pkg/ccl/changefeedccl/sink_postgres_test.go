@@ -0,0 +1,181 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// startPostgresSinkTarget starts a second server which changefeeds of the test
+// mirror tables into, and returns the URI of its database d.
+func startPostgresSinkTarget(t *testing.T) (*sqlutils.SQLRunner, string, func()) {
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{UseDatabase: `d`})
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE DATABASE d`)
+	pgURL, cleanupURL := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(username.RootUser))
+	pgURL.Path = `d`
+	return sqlDB, pgURL.String(), func() {
+		cleanupURL()
+		s.Stopper().Stop(context.Background())
+	}
+}
+
+func checkMirrorResults(t *testing.T, target *sqlutils.SQLRunner, query string, expected [][]string) {
+	t.Helper()
+	testutils.SucceedsSoon(t, func() error {
+		rows, err := target.DB.QueryContext(context.Background(), query)
+		if err != nil {
+			return err
+		}
+		actual, err := sqlutils.RowsToStrMatrix(rows)
+		if err != nil {
+			return err
+		}
+		if fmt.Sprint(actual) != fmt.Sprint(expected) {
+			return errors.Newf("expected %v, found %v", expected, actual)
+		}
+		return nil
+	})
+}
+
+func TestPostgresSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	_, db, stopServer := startTestFullServer(t, feedTestOptions{})
+	defer stopServer()
+	target, targetURI, stopTarget := startPostgresSinkTarget(t)
+	defer stopTarget()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE foo (a INT PRIMARY KEY, b STRING, c BYTES, d DECIMAL, e TIMESTAMPTZ)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES
+		(1, 'one', 'x', 1.5, '2022-01-01 00:00:00+00'),
+		(2, 'it''s', NULL, NULL, NULL),
+		(3, 'three', NULL, NULL, NULL)`)
+
+	var jobID int
+	sqlDB.QueryRow(t, `CREATE CHANGEFEED FOR foo INTO $1 WITH resolved='10ms'`, targetURI).Scan(&jobID)
+	defer sqlDB.Exec(t, `CANCEL JOB $1`, jobID)
+
+	checkMirrorResults(t, target, `SELECT * FROM foo ORDER BY a`, [][]string{
+		{`1`, `one`, `x`, `1.5`, `2022-01-01 00:00:00 +0000 UTC`},
+		{`2`, `it's`, `NULL`, `NULL`, `NULL`},
+		{`3`, `three`, `NULL`, `NULL`, `NULL`},
+	})
+
+	// Updates are upserted and deletes are applied.
+	sqlDB.Exec(t, `UPDATE foo SET b = 'uno', d = 2 WHERE a = 1`)
+	sqlDB.Exec(t, `DELETE FROM foo WHERE a = 2`)
+	sqlDB.Exec(t, `INSERT INTO foo (a, b) VALUES (4, 'four')`)
+	checkMirrorResults(t, target, `SELECT a, b, d FROM foo ORDER BY a`, [][]string{
+		{`1`, `uno`, `2`},
+		{`3`, `three`, `NULL`},
+		{`4`, `four`, `NULL`},
+	})
+
+	// Added and dropped columns are applied to the mirror table, and the
+	// backfill populates the added columns. Columns which were not created by
+	// the sink are preserved.
+	target.Exec(t, `ALTER TABLE foo ADD COLUMN note STRING`)
+	sqlDB.Exec(t, `ALTER TABLE foo ADD COLUMN f INT DEFAULT 7`)
+	checkMirrorResults(t, target, `SELECT a, b, f FROM foo ORDER BY a`, [][]string{
+		{`1`, `uno`, `7`},
+		{`3`, `three`, `7`},
+		{`4`, `four`, `7`},
+	})
+	sqlDB.Exec(t, `ALTER TABLE foo DROP COLUMN c`)
+	sqlDB.Exec(t, `INSERT INTO foo (a, b) VALUES (5, 'five')`)
+	checkMirrorResults(t, target,
+		`SELECT column_name FROM [SHOW COLUMNS FROM foo] ORDER BY column_name`,
+		[][]string{{`a`}, {`b`}, {`d`}, {`e`}, {`f`}, {`note`}},
+	)
+	checkMirrorResults(t, target, `SELECT a, b, f FROM foo ORDER BY a`, [][]string{
+		{`1`, `uno`, `7`},
+		{`3`, `three`, `7`},
+		{`4`, `four`, `7`},
+		{`5`, `five`, `7`},
+	})
+}
+
+func TestPostgresSinkNoBackfill(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	_, db, stopServer := startTestFullServer(t, feedTestOptions{})
+	defer stopServer()
+	target, targetURI, stopTarget := startPostgresSinkTarget(t)
+	defer stopTarget()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE bar (a INT PRIMARY KEY, b STRING)`)
+	sqlDB.Exec(t, `INSERT INTO bar VALUES (1, 'one'), (2, 'two')`)
+
+	var jobID int
+	sqlDB.QueryRow(t,
+		`CREATE CHANGEFEED FOR bar INTO $1 WITH resolved='10ms', schema_change_policy='nobackfill'`,
+		targetURI,
+	).Scan(&jobID)
+	defer sqlDB.Exec(t, `CANCEL JOB $1`, jobID)
+
+	checkMirrorResults(t, target, `SELECT * FROM bar ORDER BY a`, [][]string{{`1`, `one`}, {`2`, `two`}})
+
+	// The added column is added to the mirror table, but it is only written
+	// for the rows which are changed afterwards.
+	sqlDB.Exec(t, `ALTER TABLE bar ADD COLUMN c INT DEFAULT 7`)
+	sqlDB.Exec(t, `UPDATE bar SET b = 'dos' WHERE a = 2`)
+	checkMirrorResults(t, target, `SELECT * FROM bar ORDER BY a`, [][]string{
+		{`1`, `one`, `NULL`},
+		{`2`, `dos`, `7`},
+	})
+}
+
+func TestPostgresSinkOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		uri  string
+		opts map[string]string
+		err  string
+	}{
+		{uri: `postgres://localhost`, err: `must specify database`},
+		{uri: `postgres://localhost/d?foo=bar`, err: `unknown postgres sink query parameters: foo`},
+		{uri: `postgresql://localhost/d?sslmode=disable&application_name=cdc`},
+		{
+			uri:  `postgres://localhost/d`,
+			opts: map[string]string{`split_column_families`: ``},
+			err:  `split_column_families is not supported by the postgres sink`,
+		},
+	} {
+		t.Run(tc.uri, func(t *testing.T) {
+			u, err := url.Parse(tc.uri)
+			require.NoError(t, err)
+			_, err = makePostgresSink(sinkURL{URL: u}, tc.opts, nilMetricsRecorderBuilder)
+			if tc.err == `` {
+				require.NoError(t, err)
+			} else {
+				require.Regexp(t, tc.err, err)
+			}
+		})
+	}
+}
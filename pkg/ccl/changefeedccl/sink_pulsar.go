@@ -0,0 +1,515 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/pulsarclient"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/bufalloc"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	defaultPulsarTenant    = `public`
+	defaultPulsarNamespace = `default`
+
+	// pulsarMaxBatchMessages and pulsarMaxBatchBytes bound the size of a batch,
+	// regardless of the flush configuration, so that batches stay well below
+	// the maximum message size of brokers.
+	pulsarMaxBatchMessages = 1000
+	pulsarMaxBatchBytes    = 1 << 20
+)
+
+func isPulsarSink(u *url.URL) bool {
+	switch u.Scheme {
+	case changefeedbase.SinkSchemePulsar, changefeedbase.SinkSchemePulsarTLS:
+		return true
+	default:
+		return false
+	}
+}
+
+// pulsarSinkConfig is the JSON configuration of the pulsar sink. If every
+// value is zero, every message is sent as soon as it is emitted.
+//
+//	{
+//	  "Flush": {
+//	    "Messages":  ...,
+//	    "Bytes":     ...,
+//	    "Frequency": ...,
+//	  }
+//	}
+type pulsarSinkConfig struct {
+	Flush batchConfig `json:",omitempty"`
+}
+
+func getPulsarSinkConfig(opts map[string]string) (batchConfig, error) {
+	var cfg pulsarSinkConfig
+	if configStr, ok := opts[changefeedbase.OptPulsarSinkConfig]; ok {
+		if err := json.Unmarshal([]byte(configStr), &cfg); err != nil {
+			return cfg.Flush, errors.Wrapf(err, "error unmarshalling json")
+		}
+	}
+	if cfg.Flush.Messages < 0 || cfg.Flush.Bytes < 0 || cfg.Flush.Frequency < 0 {
+		return cfg.Flush, errors.Errorf("invalid option value %s, all config values must be non-negative",
+			changefeedbase.OptPulsarSinkConfig)
+	}
+	// Batches which are not full would never be sent without a frequency.
+	if (cfg.Flush.Messages > 0 || cfg.Flush.Bytes > 0) && cfg.Flush.Frequency == 0 {
+		return cfg.Flush, errors.Errorf("invalid option value %s, flush frequency is not set, messages may never be sent",
+			changefeedbase.OptPulsarSinkConfig)
+	}
+	return cfg.Flush, nil
+}
+
+// pulsarBatch is the batch of messages which is buffered for a single
+// partition of a topic.
+type pulsarBatch struct {
+	msgs  []pulsarclient.Message
+	meta  []messageMetadata
+	bytes int
+}
+
+// pulsarSink emits to Apache Pulsar. Messages are routed to the partitions of
+// a topic by their key, and are sent in batches by one producer per partition,
+// which preserves the order of the messages of each key. Flush waits until the
+// brokers acknowledged every message. It is not concurrency-safe; all calls to
+// Emit and Flush should be from the same goroutine.
+type pulsarSink struct {
+	ctx         context.Context
+	serviceURL  string
+	clientOpts  pulsarclient.Options
+	topics      *TopicNamer
+	topicPrefix string
+	batchCfg    batchConfig
+	metrics     metricsRecorder
+	scratch     bufalloc.ByteAllocator
+
+	client *pulsarclient.Client
+	// producers maps the name of every topic to its producers, one per
+	// partition. It is only accessed by the client goroutine.
+	producers map[string][]*pulsarclient.Producer
+
+	stopWorkerCh chan struct{}
+	worker       sync.WaitGroup
+
+	// batchMu is held while batches are buffered and sent, which keeps the
+	// batches of a partition in order when the worker goroutine sends them.
+	batchMu struct {
+		syncutil.Mutex
+		batches map[*pulsarclient.Producer]*pulsarBatch
+	}
+
+	// Synchronized between the client goroutine and the acknowledgements.
+	mu struct {
+		syncutil.Mutex
+		inflight int64
+		flushErr error
+		flushCh  chan struct{}
+	}
+}
+
+var _ Sink = (*pulsarSink)(nil)
+
+func makePulsarSink(
+	ctx context.Context,
+	u sinkURL,
+	targets []jobspb.ChangefeedTargetSpecification,
+	opts map[string]string,
+	mb metricsRecorderBuilder,
+) (Sink, error) {
+	topicPrefix := u.consumeParam(changefeedbase.SinkParamTopicPrefix)
+	topicName := u.consumeParam(changefeedbase.SinkParamTopicName)
+	tenant := u.consumeParam(changefeedbase.SinkParamPulsarTenant)
+	if tenant == `` {
+		tenant = defaultPulsarTenant
+	}
+	namespace := u.consumeParam(changefeedbase.SinkParamPulsarNamespace)
+	if namespace == `` {
+		namespace = defaultPulsarNamespace
+	}
+
+	var clientOpts pulsarclient.Options
+	clientOpts.AuthToken = u.consumeParam(changefeedbase.SinkParamAuthToken)
+	var tlsSkipVerify bool
+	if _, err := u.consumeBool(changefeedbase.SinkParamSkipTLSVerify, &tlsSkipVerify); err != nil {
+		return nil, err
+	}
+	var caCert []byte
+	if err := u.decodeBase64(changefeedbase.SinkParamCACert, &caCert); err != nil {
+		return nil, err
+	}
+	if u.Scheme == changefeedbase.SinkSchemePulsarTLS {
+		clientOpts.TLSConfig = &tls.Config{InsecureSkipVerify: tlsSkipVerify}
+		if caCert != nil {
+			caCertPool := x509.NewCertPool()
+			caCertPool.AppendCertsFromPEM(caCert)
+			clientOpts.TLSConfig.RootCAs = caCertPool
+		}
+	} else if caCert != nil || tlsSkipVerify {
+		return nil, errors.Errorf(`%s and %s require the %s scheme`,
+			changefeedbase.SinkParamCACert, changefeedbase.SinkParamSkipTLSVerify,
+			changefeedbase.SinkSchemePulsarTLS)
+	}
+
+	batchCfg, err := getPulsarSinkConfig(opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error processing option %s", changefeedbase.OptPulsarSinkConfig)
+	}
+
+	topics, err := MakeTopicNamer(
+		targets,
+		WithPrefix(topicPrefix), WithSingleName(topicName), WithSanitizeFn(SQLNameToKafkaName))
+	if err != nil {
+		return nil, err
+	}
+
+	if unknownParams := u.remainingQueryParams(); len(unknownParams) > 0 {
+		return nil, errors.Errorf(
+			`unknown pulsar sink query parameters: %s`, strings.Join(unknownParams, ", "))
+	}
+
+	// Only the scheme and the address of the broker are kept, since the query
+	// parameters may contain credentials.
+	serviceURL := url.URL{Scheme: u.Scheme, Host: u.Host}
+	return &pulsarSink{
+		ctx:         ctx,
+		serviceURL:  serviceURL.String(),
+		clientOpts:  clientOpts,
+		topics:      topics,
+		topicPrefix: fmt.Sprintf("persistent://%s/%s/", tenant, namespace),
+		batchCfg:    batchCfg,
+		metrics:     mb(requiresResourceAccounting),
+	}, nil
+}
+
+// Dial implements the Sink interface.
+func (s *pulsarSink) Dial() error {
+	client, err := pulsarclient.NewClient(s.serviceURL, s.clientOpts)
+	if err != nil {
+		return err
+	}
+	s.client = client
+	s.producers = make(map[string][]*pulsarclient.Producer)
+	s.batchMu.batches = make(map[*pulsarclient.Producer]*pulsarBatch)
+	s.stopWorkerCh = make(chan struct{})
+
+	if err := s.topics.Each(func(topic string) error {
+		_, err := s.getProducers(s.ctx, topic)
+		return err
+	}); err != nil {
+		s.closeProducers()
+		_ = s.client.Close()
+		s.client = nil
+		return pgerror.Wrapf(err, pgcode.CannotConnectNow,
+			`connecting to pulsar: %s`, s.serviceURL)
+	}
+
+	if s.batchCfg.Frequency > 0 {
+		s.worker.Add(1)
+		go s.workerLoop()
+	}
+	return nil
+}
+
+// getProducers returns the producers of every partition of the topic,
+// creating them if needed.
+func (s *pulsarSink) getProducers(
+	ctx context.Context, topic string,
+) ([]*pulsarclient.Producer, error) {
+	if producers, ok := s.producers[topic]; ok {
+		return producers, nil
+	}
+	fullName := s.topicPrefix + topic
+	partitions, err := s.client.PartitionedTopicMetadata(ctx, fullName)
+	if err != nil {
+		return nil, err
+	}
+	// A topic which is not partitioned has a single producer.
+	names := []string{fullName}
+	if partitions > 0 {
+		names = make([]string, partitions)
+		for i := range names {
+			names[i] = fmt.Sprintf("%s-partition-%d", fullName, i)
+		}
+	}
+	producers := make([]*pulsarclient.Producer, 0, len(names))
+	for _, name := range names {
+		p, err := s.client.CreateProducer(ctx, name)
+		if err != nil {
+			for _, p := range producers {
+				_ = p.Close(ctx)
+			}
+			return nil, err
+		}
+		producers = append(producers, p)
+	}
+	s.producers[topic] = producers
+	return producers, nil
+}
+
+// pulsarPartition returns the partition a message with the given key is
+// routed to. It matches the JavaStringHash router, the default router of the
+// Java client, so that consumers can locate the partition of a key: the sign
+// bit of the hash of the key is cleared before it is reduced modulo the number
+// of partitions.
+func pulsarPartition(key []byte, numPartitions int) int {
+	return int(javaStringHash(key)&math.MaxInt32) % numPartitions
+}
+
+// javaStringHash returns the java.lang.String.hashCode of the key, which is
+// computed over its UTF-16 code units with signed 32-bit arithmetic.
+func javaStringHash(key []byte) int32 {
+	var h int32
+	for _, c := range utf16.Encode([]rune(string(key))) {
+		h = 31*h + int32(c)
+	}
+	return h
+}
+
+// EmitRow implements the Sink interface.
+func (s *pulsarSink) EmitRow(
+	ctx context.Context,
+	topicDescr TopicDescriptor,
+	key, value []byte,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	topic, err := s.topics.Name(topicDescr)
+	if err != nil {
+		return err
+	}
+	producers, err := s.getProducers(ctx, topic)
+	if err != nil {
+		return err
+	}
+	p := producers[pulsarPartition(key, len(producers))]
+	s.startInflightMessages(1)
+	s.addToBatch(p, pulsarclient.Message{Key: string(key), Payload: value},
+		messageMetadata{alloc: alloc, mvcc: mvcc, updateMetrics: s.metrics.recordOneMessage()})
+	return nil
+}
+
+// EmitResolvedTimestamp implements the Sink interface.
+func (s *pulsarSink) EmitResolvedTimestamp(
+	ctx context.Context, encoder Encoder, resolved hlc.Timestamp,
+) error {
+	defer s.metrics.recordResolvedCallback()()
+
+	return s.topics.Each(func(topic string) error {
+		payload, err := encoder.EncodeResolvedTimestamp(ctx, topic, resolved)
+		if err != nil {
+			return err
+		}
+		s.scratch, payload = s.scratch.Copy(payload, 0 /* extraCap */)
+
+		producers, err := s.getProducers(ctx, topic)
+		if err != nil {
+			return err
+		}
+		// Resolved timestamps are sent to every partition, since every
+		// partition is consumed independently.
+		for _, p := range producers {
+			s.startInflightMessages(1)
+			s.addToBatch(p, pulsarclient.Message{Payload: payload}, messageMetadata{})
+		}
+		return nil
+	})
+}
+
+// addToBatch buffers the message in the batch of the producer, and sends the
+// batch once it is full.
+func (s *pulsarSink) addToBatch(
+	p *pulsarclient.Producer, msg pulsarclient.Message, meta messageMetadata,
+) {
+	size := len(msg.Key) + len(msg.Payload)
+
+	s.batchMu.Lock()
+	defer s.batchMu.Unlock()
+	b, ok := s.batchMu.batches[p]
+	if !ok {
+		b = &pulsarBatch{}
+		s.batchMu.batches[p] = b
+	}
+	if len(b.msgs) > 0 && b.bytes+size > pulsarMaxBatchBytes {
+		s.sendBatchLocked(p, b)
+	}
+	b.msgs = append(b.msgs, msg)
+	b.meta = append(b.meta, meta)
+	b.bytes += size
+	if s.batchFull(b) {
+		s.sendBatchLocked(p, b)
+	}
+}
+
+// batchFull returns whether the batch should be sent without waiting for the
+// flush frequency.
+func (s *pulsarSink) batchFull(b *pulsarBatch) bool {
+	if len(b.msgs) >= pulsarMaxBatchMessages || b.bytes >= pulsarMaxBatchBytes {
+		return true
+	}
+	if s.batchCfg.Frequency == 0 {
+		return true
+	}
+	return (s.batchCfg.Messages > 0 && len(b.msgs) >= s.batchCfg.Messages) ||
+		(s.batchCfg.Bytes > 0 && b.bytes >= s.batchCfg.Bytes)
+}
+
+// sendBatchLocked sends the batch of the producer and resets it. The
+// acknowledgement of the batch releases its messages.
+func (s *pulsarSink) sendBatchLocked(p *pulsarclient.Producer, b *pulsarBatch) {
+	if len(b.msgs) == 0 {
+		return
+	}
+	msgs, meta, bytes := b.msgs, b.meta, b.bytes
+	*b = pulsarBatch{}
+	p.SendAsync(msgs, func(err error) {
+		s.finishBatch(p.Topic(), msgs, meta, bytes, err)
+	})
+}
+
+// sendAllBatches sends the batches of every producer.
+func (s *pulsarSink) sendAllBatches() {
+	s.batchMu.Lock()
+	defer s.batchMu.Unlock()
+	for p, b := range s.batchMu.batches {
+		s.sendBatchLocked(p, b)
+	}
+}
+
+// workerLoop sends the buffered batches at the flush frequency.
+func (s *pulsarSink) workerLoop() {
+	defer s.worker.Done()
+	ticker := time.NewTicker(time.Duration(s.batchCfg.Frequency))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopWorkerCh:
+			return
+		case <-ticker.C:
+			s.sendAllBatches()
+		}
+	}
+}
+
+func (s *pulsarSink) startInflightMessages(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.inflight += n
+}
+
+// finishBatch releases the messages of a batch once it was acknowledged or
+// failed. It is called by the goroutines of the client.
+func (s *pulsarSink) finishBatch(
+	topic string, msgs []pulsarclient.Message, meta []messageMetadata, bytes int, err error,
+) {
+	for i, m := range meta {
+		if err == nil && m.updateMetrics != nil {
+			m.updateMetrics(m.mvcc, len(msgs[i].Key)+len(msgs[i].Payload), sinkDoesNotCompress)
+		}
+		m.alloc.Release(s.ctx)
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "while sending %d messages of %d bytes to %s", len(meta), bytes, topic)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.inflight -= int64(len(meta))
+	if s.mu.flushErr == nil && err != nil {
+		s.mu.flushErr = err
+	}
+	if s.mu.inflight == 0 && s.mu.flushCh != nil {
+		s.mu.flushCh <- struct{}{}
+		s.mu.flushCh = nil
+	}
+}
+
+// Flush implements the Sink interface.
+func (s *pulsarSink) Flush(ctx context.Context) error {
+	defer s.metrics.recordFlushRequestCallback()()
+
+	s.sendAllBatches()
+
+	flushCh := make(chan struct{}, 1)
+	s.mu.Lock()
+	inflight := s.mu.inflight
+	flushErr := s.mu.flushErr
+	s.mu.flushErr = nil
+	immediateFlush := inflight == 0 || flushErr != nil
+	if !immediateFlush {
+		s.mu.flushCh = flushCh
+	}
+	s.mu.Unlock()
+
+	if immediateFlush {
+		return flushErr
+	}
+
+	if log.V(1) {
+		log.Infof(ctx, "flush waiting for %d inflight messages", inflight)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-flushCh:
+		s.mu.Lock()
+		flushErr := s.mu.flushErr
+		s.mu.flushErr = nil
+		s.mu.Unlock()
+		return flushErr
+	}
+}
+
+// closeProducers closes the producers of every topic.
+func (s *pulsarSink) closeProducers() {
+	for _, producers := range s.producers {
+		for _, p := range producers {
+			// If we're shutting down, we don't care what happens to the
+			// outstanding messages, so ignore this error.
+			_ = p.Close(s.ctx)
+		}
+	}
+}
+
+// Close implements the Sink interface.
+func (s *pulsarSink) Close() error {
+	if s.client == nil {
+		return nil
+	}
+	close(s.stopWorkerCh)
+	s.worker.Wait()
+	s.closeProducers()
+	return s.client.Close()
+}
+
+// Topics gives the names of all topics that have been initialized
+// and will receive resolved timestamps.
+func (s *pulsarSink) Topics() []string {
+	return s.topics.DisplayNamesSlice()
+}
@@ -0,0 +1,245 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package changefeedccl
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/pulsarclient"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func makeTestPulsarSink(
+	t testing.TB, broker *cdctest.MockPulsarBroker, opts map[string]string, targetNames ...string,
+) (s *pulsarSink, cleanup func()) {
+	u, err := url.Parse(broker.URL())
+	require.NoError(t, err)
+	sink, err := makePulsarSink(context.Background(), sinkURL{URL: u},
+		makeChangefeedTargets(targetNames...), opts, nilMetricsRecorderBuilder)
+	require.NoError(t, err)
+	require.NoError(t, sink.Dial())
+	s = sink.(*pulsarSink)
+	return s, func() {
+		require.NoError(t, s.Close())
+	}
+}
+
+func TestPulsarSink(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	broker, err := cdctest.StartMockPulsarBroker()
+	require.NoError(t, err)
+	defer broker.Close()
+	const partitions = 3
+	broker.SetPartitions("persistent://public/default/t", partitions)
+	partition := func(i int) string {
+		return fmt.Sprintf("persistent://public/default/t-partition-%d", i)
+	}
+
+	sink, cleanup := makeTestPulsarSink(t, broker, nil, "t")
+	defer cleanup()
+
+	// No inflight
+	require.NoError(t, sink.Flush(ctx))
+
+	// Every message of a key is routed to the same partition, in order.
+	var pool testAllocPool
+	expected := make(map[string][]pulsarclient.Message)
+	for i := 0; i < 30; i++ {
+		key := fmt.Sprintf(`[%d]`, i%10)
+		value := fmt.Sprintf(`{"v":%d}`, i)
+		require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(key), []byte(value), zeroTS, zeroTS, pool.alloc()))
+		p := partition(pulsarPartition([]byte(key), partitions))
+		expected[p] = append(expected[p], pulsarclient.Message{Key: key, Payload: []byte(value)})
+	}
+	require.NoError(t, sink.Flush(ctx))
+	require.EqualValues(t, 0, pool.used())
+	require.Len(t, expected, partitions)
+	for p, msgs := range expected {
+		require.Equal(t, msgs, broker.Messages(p))
+	}
+
+	// Resolved timestamps are sent to every partition.
+	resolved := hlc.Timestamp{WallTime: 1}
+	require.NoError(t, sink.EmitResolvedTimestamp(ctx, &testEncoder{}, resolved))
+	require.NoError(t, sink.Flush(ctx))
+	for i := 0; i < partitions; i++ {
+		msgs := broker.Messages(partition(i))
+		require.Equal(t, pulsarclient.Message{Payload: []byte(resolved.String())}, msgs[len(msgs)-1])
+	}
+
+	// Flush waits for the messages to be acknowledged.
+	unblock := make(chan struct{})
+	broker.SetSendHook(func(topic string, msgs []pulsarclient.Message) error {
+		<-unblock
+		return nil
+	})
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[1]`), nil, zeroTS, zeroTS, pool.alloc()))
+	for i := 0; i < 2; i++ {
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+		defer cancel()
+		if err := sink.Flush(timeoutCtx); !testutils.IsError(err, `context deadline exceeded`) {
+			t.Fatalf(`expected "context deadline exceeded" error got: %+v`, err)
+		}
+	}
+	require.EqualValues(t, 1, pool.used())
+	close(unblock)
+	require.NoError(t, sink.Flush(ctx))
+	require.EqualValues(t, 0, pool.used())
+
+	// Rejected messages fail the flush.
+	broker.SetSendHook(func(topic string, msgs []pulsarclient.Message) error {
+		if string(msgs[0].Payload) == `m3` {
+			return errors.New("m3")
+		}
+		return nil
+	})
+	for _, v := range []string{`m2`, `m3`, `m4`} {
+		require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[2]`), []byte(v), zeroTS, zeroTS, pool.alloc()))
+	}
+	if err := sink.Flush(ctx); !testutils.IsError(err, `m3`) {
+		t.Fatalf(`expected "m3" error got: %+v`, err)
+	}
+	require.EqualValues(t, 0, pool.used())
+
+	// Check simple success again after error
+	broker.SetSendHook(nil)
+	require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[2]`), []byte(`m5`), zeroTS, zeroTS, pool.alloc()))
+	require.NoError(t, sink.Flush(ctx))
+	require.EqualValues(t, 0, pool.used())
+}
+
+func TestPulsarPartition(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// The hash codes of the keys in Java, and the partitions out of 100 which
+	// the JavaStringHash router of the Java client picks for them, i.e.
+	// (key.hashCode() & Integer.MAX_VALUE) % 100.
+	for _, tc := range []struct {
+		key      string
+		hashCode int32
+		expected int
+	}{
+		{key: `[1]`, hashCode: 89063, expected: 63},
+		{key: `Hello World`, hashCode: -862545276, expected: 72},
+		{key: `polygenelubricants`, hashCode: math.MinInt32, expected: 0},
+		{key: `["a long key which overflows the hash"]`, hashCode: -1131895031, expected: 17},
+		{key: `["ünïcödé"]`, hashCode: 247967465, expected: 65},
+		{key: `["😀"]`, hashCode: 45442245, expected: 45},
+	} {
+		t.Run(tc.key, func(t *testing.T) {
+			require.Equal(t, tc.hashCode, javaStringHash([]byte(tc.key)))
+			require.Equal(t, tc.expected, pulsarPartition([]byte(tc.key), 100))
+		})
+	}
+}
+
+func TestPulsarSinkBatching(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	broker, err := cdctest.StartMockPulsarBroker()
+	require.NoError(t, err)
+	defer broker.Close()
+
+	opts := map[string]string{
+		changefeedbase.OptPulsarSinkConfig: `{"Flush": {"Messages": 10, "Frequency": "1h"}}`,
+	}
+	sink, cleanup := makeTestPulsarSink(t, broker, opts, "t")
+	defer cleanup()
+
+	var pool testAllocPool
+	for i := 0; i < 25; i++ {
+		require.NoError(t, sink.EmitRow(ctx, topic(`t`), []byte(`[1]`), []byte(fmt.Sprint(i)), zeroTS, zeroTS, pool.alloc()))
+	}
+	// Full batches are sent without waiting for the flush frequency.
+	testutils.SucceedsSoon(t, func() error {
+		if n := broker.NumBatches("persistent://public/default/t"); n != 2 {
+			return errors.Newf("expected 2 batches, found %d", n)
+		}
+		if used := pool.used(); used != 5 {
+			return errors.Newf("expected 5 unacknowledged messages, found %d", used)
+		}
+		return nil
+	})
+
+	require.NoError(t, sink.Flush(ctx))
+	require.Equal(t, 3, broker.NumBatches("persistent://public/default/t"))
+	require.Len(t, broker.Messages("persistent://public/default/t"), 25)
+	require.EqualValues(t, 0, pool.used())
+}
+
+func TestPulsarSinkOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		uri  string
+		opts map[string]string
+		err  string
+	}{
+		{uri: `pulsar://localhost?foo=bar`, err: `unknown pulsar sink query parameters: foo`},
+		{uri: `pulsar://localhost?ca_cert=Zm9v`, err: `require the pulsar\+ssl scheme`},
+		{uri: `pulsar+ssl://localhost?ca_cert=Zm9v&insecure_tls_skip_verify=true&auth_token=t`},
+		{
+			uri:  `pulsar://localhost`,
+			opts: map[string]string{changefeedbase.OptPulsarSinkConfig: `{"Flush": {"Messages": 10}}`},
+			err:  `flush frequency is not set`,
+		},
+		{
+			uri:  `pulsar://localhost`,
+			opts: map[string]string{changefeedbase.OptPulsarSinkConfig: `{"Flush": {"Messages": -1}}`},
+			err:  `must be non-negative`,
+		},
+	} {
+		t.Run(tc.uri, func(t *testing.T) {
+			u, err := url.Parse(tc.uri)
+			require.NoError(t, err)
+			_, err = makePulsarSink(context.Background(), sinkURL{URL: u},
+				makeChangefeedTargets("t"), tc.opts, nilMetricsRecorderBuilder)
+			if tc.err == `` {
+				require.NoError(t, err)
+			} else {
+				require.Regexp(t, tc.err, err)
+			}
+		})
+	}
+
+	// Sinks with a tenant and namespace produce to topics in that namespace.
+	broker, err := cdctest.StartMockPulsarBroker()
+	require.NoError(t, err)
+	defer broker.Close()
+	u, err := url.Parse(broker.URL() + `?tenant=acme&namespace=cdc&topic_prefix=crdb_`)
+	require.NoError(t, err)
+	s, err := makePulsarSink(context.Background(), sinkURL{URL: u},
+		makeChangefeedTargets("t"), nil, nilMetricsRecorderBuilder)
+	require.NoError(t, err)
+	require.NoError(t, s.Dial())
+	defer func() { require.NoError(t, s.Close()) }()
+	ctx := context.Background()
+	require.NoError(t, s.EmitRow(ctx, topic(`t`), []byte(`[1]`), []byte(`v`), zeroTS, zeroTS, zeroAlloc))
+	require.NoError(t, s.Flush(ctx))
+	require.Equal(t, []string{"persistent://acme/cdc/crdb_t"}, broker.Topics())
+}
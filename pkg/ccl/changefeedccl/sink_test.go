@@ -700,3 +700,27 @@ func TestKafkaSinkTracksMemory(t *testing.T) {
 	require.EqualValues(t, 0, p.outstanding())
 	require.EqualValues(t, 0, pool.used())
 }
+
+// encodingTestSink is a sink which encodes the rows emitted to it.
+type encodingTestSink struct {
+	Sink
+}
+
+var _ SinkWithEncoder = encodingTestSink{}
+
+// EncodeAndEmitRow implements the SinkWithEncoder interface.
+func (encodingTestSink) EncodeAndEmitRow(
+	context.Context, cdcevent.Row, cdcevent.Row, TopicDescriptor, hlc.Timestamp, hlc.Timestamp, kvevent.Alloc,
+) error {
+	return nil
+}
+
+func TestErrorWrapperSinkEncodesRows(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	_, ok := makeErrorWrapperSink(&bufferSink{}).(SinkWithEncoder)
+	require.False(t, ok)
+	_, ok = makeErrorWrapperSink(encodingTestSink{&bufferSink{}}).(SinkWithEncoder)
+	require.True(t, ok)
+}
@@ -16,6 +16,7 @@ import (
 	"encoding/base64"
 	gojson "encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/rand"
 	"net/url"
@@ -29,8 +30,10 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/cockroachdb/cockroach-go/v2/crdb"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdcevent"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/cdctest"
 	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/changefeedbase"
+	"github.com/cockroachdb/cockroach/pkg/ccl/changefeedccl/kvevent"
 	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
@@ -50,6 +53,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
+	goparquet "github.com/fraugster/parquet-go"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -479,7 +483,23 @@ func (s *notifyFlushSink) Flush(ctx context.Context) error {
 	return s.Sink.Flush(ctx)
 }
 
-var _ Sink = (*notifyFlushSink)(nil)
+// EncodeAndEmitRow implements the SinkWithEncoder interface.
+func (s *notifyFlushSink) EncodeAndEmitRow(
+	ctx context.Context,
+	updatedRow cdcevent.Row,
+	prevRow cdcevent.Row,
+	topic TopicDescriptor,
+	updated, mvcc hlc.Timestamp,
+	alloc kvevent.Alloc,
+) error {
+	sink, ok := s.Sink.(SinkWithEncoder)
+	if !ok {
+		return errors.AssertionFailedf("sink %T does not encode rows", s.Sink)
+	}
+	return sink.EncodeAndEmitRow(ctx, updatedRow, prevRow, topic, updated, mvcc, alloc)
+}
+
+var _ SinkWithEncoder = (*notifyFlushSink)(nil)
 
 // feedInjectable is the subset of the
 // TestServerInterface/TestTenantInterface needed for depInjector to
@@ -946,6 +966,13 @@ func (c *cloudFeed) Next() (*cdctest.TestFeedMessage, error) {
 					return m, nil
 				case changefeedbase.OptFormatCSV:
 					return m, nil
+				case changefeedbase.OptFormatParquet:
+					// The rows of a parquet file include their MVCC timestamp, so
+					// files which are read again are de-duplicated.
+					if isNew := c.markSeen(m); !isNew {
+						continue
+					}
+					return m, nil
 				default:
 					return nil, errors.Errorf(`unknown %s: %s`, changefeedbase.OptFormat, v)
 				}
@@ -1017,6 +1044,9 @@ func (c *cloudFeed) walkDir(path string, info os.FileInfo, err error) error {
 		return err
 	}
 	defer f.Close()
+	if strings.HasSuffix(path, `.parquet`) {
+		return c.appendParquetRows(f, topic)
+	}
 	// NB: This is the logic for JSON. Avro will involve parsing an
 	// "Object Container File".
 	s := bufio.NewScanner(f)
@@ -1029,6 +1059,34 @@ func (c *cloudFeed) walkDir(path string, info os.FileInfo, err error) error {
 	return nil
 }
 
+// appendParquetRows reads the rows of the given parquet file, and appends each
+// of them as a JSON object to the rows of the feed.
+func (c *cloudFeed) appendParquetRows(f io.ReadSeeker, topic string) error {
+	r, err := goparquet.NewFileReader(f)
+	if err != nil {
+		return err
+	}
+	for {
+		row, err := r.NextRow()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for k, v := range row {
+			if b, ok := v.([]byte); ok {
+				row[k] = string(b)
+			}
+		}
+		value, err := gojson.Marshal(row)
+		if err != nil {
+			return err
+		}
+		c.rows = append(c.rows, cloudFeedEntry{topic: topic, value: value})
+	}
+}
+
 // teeGroup facilitates reading messages from input channel
 // and sending them to one or more output channels.
 type teeGroup struct {
@@ -1201,7 +1259,9 @@ func (k *kafkaFeedFactory) Feed(create string, args ...interface{}) (cdctest.Tes
 			if err != nil {
 				return nil, err
 			}
-			if format == string(changefeedbase.OptFormatAvro) {
+			switch changefeedbase.FormatType(format) {
+			case changefeedbase.OptFormatAvro, changefeedbase.OptFormatProtobuf,
+				changefeedbase.OptFormatJSONSchema:
 				// Must use confluent schema registry so that we register our schema
 				// in order to be able to decode kafka messages.
 				registry = cdctest.StartTestSchemaRegistry()
@@ -1210,8 +1270,8 @@ func (k *kafkaFeedFactory) Feed(create string, args ...interface{}) (cdctest.Tes
 					Value: tree.NewStrVal(registry.URL()),
 				}
 				createStmt.Options = append(createStmt.Options, registryOption)
-				break
 			}
+			break
 		}
 	}
 
@@ -1255,7 +1315,8 @@ type kafkaFeed struct {
 	source chan *sarama.ProducerMessage
 	tg     *teeGroup
 
-	// Registry is set if we're emitting avro.
+	// Registry is set if we're emitting a format which registers its schemas
+	// with the confluent schema registry.
 	registry *cdctest.SchemaRegistry
 }
 
@@ -1295,8 +1356,8 @@ func (k *kafkaFeed) Next() (*cdctest.TestFeedMessage, error) {
 			if k.registry == nil {
 				*dest = decoded
 			} else {
-				// Convert avro record to json.
-				jsonBytes, err := k.registry.AvroToJSON(decoded)
+				// Convert the record to json.
+				jsonBytes, err := k.registry.EncodedToJSON(decoded)
 				if err != nil {
 					return err
 				}
@@ -48,6 +48,11 @@ type Client interface {
 	// can be used to interact with this stream in the future.
 	Create(ctx context.Context, tenantID roachpb.TenantID) (streaming.StreamID, error)
 
+	// CreateForPublication initializes a stream for the tables of the specified
+	// publication in the source database and returns an ID which can be used to
+	// interact with this stream in the future.
+	CreateForPublication(ctx context.Context, publication string) (streaming.StreamID, error)
+
 	// Destroy informs the source of the stream that it may terminate production
 	// and release resources such as protected timestamps.
 	// Destroy(ID StreamID) error
@@ -66,6 +71,12 @@ type Client interface {
 	// TODO(dt): separate target argument from address argument.
 	Plan(ctx context.Context, streamID streaming.StreamID) (Topology, error)
 
+	// SourceTables returns the current descriptors of the tables replicated by a
+	// stream created with CreateForPublication.
+	SourceTables(
+		ctx context.Context, streamID streaming.StreamID,
+	) ([]streampb.ReplicationStreamSpec_SourceTable, error)
+
 	// Subscribe opens and returns a subscription for the specified partition from
 	// the specified remote address. This is used by each consumer processor to
 	// open its subscription to its partition of a larger stream.
@@ -33,6 +33,13 @@ func (sc testStreamClient) Create(
 	return streaming.StreamID(1), nil
 }
 
+// CreateForPublication implements the Client interface.
+func (sc testStreamClient) CreateForPublication(
+	ctx context.Context, publication string,
+) (streaming.StreamID, error) {
+	return streaming.StreamID(1), nil
+}
+
 // Plan implements the Client interface.
 func (sc testStreamClient) Plan(ctx context.Context, ID streaming.StreamID) (Topology, error) {
 	return Topology([]PartitionInfo{
@@ -75,6 +82,13 @@ func (sc testStreamClient) Subscribe(
 	}, nil
 }
 
+// SourceTables implements the Client interface.
+func (sc testStreamClient) SourceTables(
+	ctx context.Context, streamID streaming.StreamID,
+) ([]streampb.ReplicationStreamSpec_SourceTable, error) {
+	return nil, nil
+}
+
 // Complete implements the streamclient.Client interface.
 func (sc testStreamClient) Complete(ctx context.Context, streamID streaming.StreamID) error {
 	return nil
@@ -72,6 +72,23 @@ func (p *partitionedStreamClient) Create(
 	return streamID, err
 }
 
+// CreateForPublication implements Client interface.
+func (p *partitionedStreamClient) CreateForPublication(
+	ctx context.Context, publication string,
+) (streaming.StreamID, error) {
+	streamID := streaming.InvalidStreamID
+
+	row := p.srcDB.QueryRowContext(ctx,
+		`SELECT crdb_internal.start_replication_stream_for_publication($1)`, publication)
+	if row.Err() != nil {
+		return streamID, errors.Wrapf(row.Err(),
+			"Error in creating replication stream for publication %s", publication)
+	}
+
+	err := row.Scan(&streamID)
+	return streamID, err
+}
+
 // Heartbeat implements Client interface.
 func (p *partitionedStreamClient) Heartbeat(
 	ctx context.Context, streamID streaming.StreamID, consumed hlc.Timestamp,
@@ -110,14 +127,15 @@ func (p *partitionedStreamClient) postgresURL(servingAddr string) (url.URL, erro
 	return res, nil
 }
 
-// Plan implements Client interface.
-func (p *partitionedStreamClient) Plan(
+// getSpec fetches the current replication stream spec of the stream.
+func (p *partitionedStreamClient) getSpec(
 	ctx context.Context, streamID streaming.StreamID,
-) (Topology, error) {
+) (*streampb.ReplicationStreamSpec, error) {
 	conn, err := p.srcDB.Conn(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer conn.Close()
 
 	row := conn.QueryRowContext(ctx, `SELECT crdb_internal.replication_stream_spec($1)`, streamID)
 	if row.Err() != nil {
@@ -132,6 +150,17 @@ func (p *partitionedStreamClient) Plan(
 	if err := protoutil.Unmarshal(rawSpec, &spec); err != nil {
 		return nil, err
 	}
+	return &spec, nil
+}
+
+// Plan implements Client interface.
+func (p *partitionedStreamClient) Plan(
+	ctx context.Context, streamID streaming.StreamID,
+) (Topology, error) {
+	spec, err := p.getSpec(ctx, streamID)
+	if err != nil {
+		return nil, err
+	}
 
 	topology := Topology{}
 	for _, sp := range spec.Partitions {
@@ -154,6 +183,17 @@ func (p *partitionedStreamClient) Plan(
 	return topology, nil
 }
 
+// SourceTables implements Client interface.
+func (p *partitionedStreamClient) SourceTables(
+	ctx context.Context, streamID streaming.StreamID,
+) ([]streampb.ReplicationStreamSpec_SourceTable, error) {
+	spec, err := p.getSpec(ctx, streamID)
+	if err != nil {
+		return nil, err
+	}
+	return spec.SourceTables, nil
+}
+
 // Close implements Client interface.
 func (p *partitionedStreamClient) Close() error {
 	p.mu.Lock()
@@ -233,7 +273,15 @@ func parseEvent(streamEvent *streampb.StreamEvent) streamingccl.Event {
 	}
 
 	if streamEvent.Checkpoint != nil {
-		event := streamingccl.MakeCheckpointEvent(streamEvent.Checkpoint.Spans[0].Timestamp)
+		// The partition is resolved up to the minimum timestamp across all of
+		// its spans.
+		resolved := streamEvent.Checkpoint.Spans[0].Timestamp
+		for _, sp := range streamEvent.Checkpoint.Spans[1:] {
+			if sp.Timestamp.Less(resolved) {
+				resolved = sp.Timestamp
+			}
+		}
+		event := streamingccl.MakeCheckpointEvent(resolved)
 		streamEvent.Checkpoint = nil
 		return event
 	}
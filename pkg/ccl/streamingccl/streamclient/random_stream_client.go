@@ -36,6 +36,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/randutil"
 	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
 )
 
 const (
@@ -258,6 +259,13 @@ func (m *randomStreamClient) Create(
 	return streaming.StreamID(target.ToUint64()), nil
 }
 
+// CreateForPublication implements the Client interface.
+func (m *randomStreamClient) CreateForPublication(
+	ctx context.Context, publication string,
+) (streaming.StreamID, error) {
+	return streaming.InvalidStreamID, errors.New("random stream client does not support publications")
+}
+
 // Heartbeat implements the Client interface.
 func (m *randomStreamClient) Heartbeat(
 	ctx context.Context, _ streaming.StreamID, _ hlc.Timestamp,
@@ -436,6 +444,13 @@ func (m *randomStreamClient) Subscribe(
 	}, nil
 }
 
+// SourceTables implements the Client interface.
+func (m *randomStreamClient) SourceTables(
+	ctx context.Context, _ streaming.StreamID,
+) ([]streampb.ReplicationStreamSpec_SourceTable, error) {
+	return nil, nil
+}
+
 // Complete implements the streamclient.Client interface.
 func (m *randomStreamClient) Complete(ctx context.Context, streamID streaming.StreamID) error {
 	return nil
@@ -68,6 +68,13 @@ func (m *mockStreamClient) Create(
 	panic("unimplemented")
 }
 
+// CreateForPublication implements the Client interface.
+func (m *mockStreamClient) CreateForPublication(
+	ctx context.Context, publication string,
+) (streaming.StreamID, error) {
+	panic("unimplemented")
+}
+
 // Heartbeat implements the Client interface.
 func (m *mockStreamClient) Heartbeat(
 	ctx context.Context, ID streaming.StreamID, _ hlc.Timestamp,
@@ -82,6 +89,13 @@ func (m *mockStreamClient) Plan(
 	panic("unimplemented mock method")
 }
 
+// SourceTables implements the Client interface.
+func (m *mockStreamClient) SourceTables(
+	ctx context.Context, _ streaming.StreamID,
+) ([]streampb.ReplicationStreamSpec_SourceTable, error) {
+	panic("unimplemented mock method")
+}
+
 type mockSubscription struct {
 	eventsCh chan streamingccl.Event
 }
@@ -0,0 +1,597 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streampb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// subscriptionApplyBatchSize is the maximum number of row changes applied
+// in a single transaction.
+const subscriptionApplyBatchSize = 1000
+
+// subscribedTable decodes the KVs of a table on the publishing cluster and
+// knows how to apply the resulting row changes to the corresponding table of
+// the subscribing cluster.
+type subscribedTable struct {
+	desc catalog.TableDescriptor
+	// dstName is the fully-qualified name of the destination table.
+	dstName string
+	// cols are the public columns of the source table, in the order in which
+	// they are fetched.
+	cols []catalog.Column
+	// colNames are the quoted names of cols.
+	colNames []string
+	// pkOrdinals are the ordinals in cols of the primary key columns.
+	pkOrdinals []int
+	// familyOrdinals are, for every column family, the ordinals in cols of
+	// the writable non-primary key columns stored in the family.
+	familyOrdinals map[descpb.FamilyID][]int
+
+	// The fetcher is initialized lazily, once the codec of the publishing
+	// cluster is known.
+	fetcherInitialized bool
+	fetcher            row.Fetcher
+	kvFetcher          row.SpanKVFetcher
+	alloc              tree.DatumAlloc
+}
+
+func newSubscribedTable(
+	src streampb.ReplicationStreamSpec_SourceTable, dbName string,
+) (*subscribedTable, error) {
+	desc := tabledesc.NewBuilder(&src.Desc).BuildImmutableTable()
+	dstName := tree.MakeTableNameWithSchema(
+		tree.Name(dbName), tree.Name(src.SchemaName), tree.Name(desc.GetName()),
+	)
+	t := &subscribedTable{
+		desc:           desc,
+		dstName:        dstName.FQString(),
+		cols:           desc.PublicColumns(),
+		familyOrdinals: make(map[descpb.FamilyID][]int),
+	}
+
+	ordinals := make(map[descpb.ColumnID]int, len(t.cols))
+	t.colNames = make([]string, len(t.cols))
+	for i, col := range t.cols {
+		ordinals[col.GetID()] = i
+		t.colNames[i] = tree.NameString(col.GetName())
+	}
+	var pkCols util.FastIntSet
+	for i := 0; i < desc.GetPrimaryIndex().NumKeyColumns(); i++ {
+		ord, ok := ordinals[desc.GetPrimaryIndex().GetKeyColumnID(i)]
+		if !ok {
+			return nil, errors.AssertionFailedf(
+				"primary key column %d of table %q is not public", desc.GetPrimaryIndex().GetKeyColumnID(i), desc.GetName())
+		}
+		t.pkOrdinals = append(t.pkOrdinals, ord)
+		pkCols.Add(ord)
+	}
+	if err := desc.ForeachFamily(func(family *descpb.ColumnFamilyDescriptor) error {
+		for _, id := range family.ColumnIDs {
+			ord, ok := ordinals[id]
+			if !ok || pkCols.Contains(ord) || t.cols[ord].IsComputed() {
+				continue
+			}
+			t.familyOrdinals[family.ID] = append(t.familyOrdinals[family.ID], ord)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// checkDestinationTable returns an error if the rows of the published table
+// src cannot be applied to the table dst of the subscribing cluster: every
+// column written by the subscription must exist in dst with the same type,
+// and the primary keys of both tables must consist of the same columns.
+func checkDestinationTable(src, dst catalog.TableDescriptor) error {
+	mismatch := func(format string, args ...interface{}) error {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.InvalidTableDefinition,
+				"cannot replicate published table %q into table %q: %s",
+				src.GetName(), dst.GetName(), fmt.Sprintf(format, args...)),
+			"The schema of the destination table must match the schema of the published table.",
+		)
+	}
+	for _, col := range src.PublicColumns() {
+		if col.IsComputed() {
+			continue
+		}
+		dstCol, err := dst.FindColumnWithName(col.ColName())
+		if err != nil || !dstCol.Public() {
+			return mismatch("column %q does not exist", col.GetName())
+		}
+		if !dstCol.GetType().Identical(col.GetType()) {
+			return mismatch("column %q has type %s instead of %s",
+				col.GetName(), dstCol.GetType().SQLString(), col.GetType().SQLString())
+		}
+	}
+	srcPK, dstPK := src.GetPrimaryIndex(), dst.GetPrimaryIndex()
+	same := srcPK.NumKeyColumns() == dstPK.NumKeyColumns()
+	for i := 0; same && i < srcPK.NumKeyColumns(); i++ {
+		same = srcPK.GetKeyColumnName(i) == dstPK.GetKeyColumnName(i)
+	}
+	if !same {
+		return mismatch("primary key (%s) differs from (%s)",
+			strings.Join(dstPK.IndexDesc().KeyColumnNames, ", "),
+			strings.Join(srcPK.IndexDesc().KeyColumnNames, ", "))
+	}
+	return nil
+}
+
+// rowEncoding describes the primary index of a table and how rows are encoded
+// in it, which is what the subscription relies on to decode replicated KVs.
+func rowEncoding(desc catalog.TableDescriptor) string {
+	var buf strings.Builder
+	for _, col := range desc.PublicColumns() {
+		fmt.Fprintf(&buf, "%d:%s:%s,", col.GetID(), col.GetName(), col.GetType().SQLString())
+	}
+	fmt.Fprintf(&buf, "pk%d%v,", desc.GetPrimaryIndexID(), desc.GetPrimaryIndex().IndexDesc().KeyColumnIDs)
+	_ = desc.ForeachFamily(func(family *descpb.ColumnFamilyDescriptor) error {
+		fmt.Fprintf(&buf, "f%d%v,", family.ID, family.ColumnIDs)
+		return nil
+	})
+	return buf.String()
+}
+
+// checkUnchanged returns an error if the current descriptor of the published
+// table encodes rows differently than the one with which the table was
+// subscribed, in which case replicated KVs could be decoded incorrectly.
+// Schema changes are not replicated, so such a change stops the
+// subscription.
+func (t *subscribedTable) checkUnchanged(cur catalog.TableDescriptor) error {
+	if rowEncoding(cur) == rowEncoding(t.desc) {
+		return nil
+	}
+	return errors.WithHint(
+		pgerror.Newf(pgcode.FeatureNotSupported,
+			"the schema of published table %q changed on the publishing cluster", t.desc.GetName()),
+		"Schema changes are not replicated. Apply the change to the destination "+
+			"table and create the subscription again.",
+	)
+}
+
+// decode decodes a single KV of the table. It returns the decoded datums,
+// indexed like cols, and whether the KV is a tombstone.
+func (t *subscribedTable) decode(
+	ctx context.Context, kv roachpb.KeyValue,
+) (tree.Datums, bool, error) {
+	if !t.fetcherInitialized {
+		_, tenantID, err := keys.DecodeTenantPrefix(kv.Key)
+		if err != nil {
+			return nil, false, err
+		}
+		colIDs := make([]descpb.ColumnID, len(t.cols))
+		for i, col := range t.cols {
+			colIDs[i] = col.GetID()
+		}
+		var spec descpb.IndexFetchSpec
+		if err := rowenc.InitIndexFetchSpec(
+			&spec, keys.MakeSQLCodec(tenantID), t.desc, t.desc.GetPrimaryIndex(), colIDs,
+		); err != nil {
+			return nil, false, err
+		}
+		if err := t.fetcher.Init(ctx, row.FetcherInitArgs{
+			Alloc: &t.alloc,
+			Spec:  &spec,
+		}); err != nil {
+			return nil, false, err
+		}
+		// Virtual columns are not stored and hence never populated.
+		t.fetcher.IgnoreUnexpectedNulls = true
+		t.fetcherInitialized = true
+	}
+
+	t.kvFetcher.KVs = append(t.kvFetcher.KVs[:0], kv)
+	if err := t.fetcher.StartScanFrom(ctx, &t.kvFetcher, false /* traceKV */); err != nil {
+		return nil, false, err
+	}
+	encDatums, _, err := t.fetcher.NextRow(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	if encDatums == nil {
+		return nil, false, errors.AssertionFailedf("unexpected empty row for key %s", kv.Key)
+	}
+	datums := make(tree.Datums, len(encDatums))
+	for i := range encDatums {
+		if err := encDatums[i].EnsureDecoded(t.cols[i].GetType(), &t.alloc); err != nil {
+			return nil, false, err
+		}
+		datums[i] = encDatums[i].Datum
+	}
+	return datums, t.fetcher.RowIsDeleted(), nil
+}
+
+// rowChange describes all the changes made to a row of a published table at
+// a given timestamp.
+type rowChange struct {
+	table *subscribedTable
+	// deleted is set if the row was deleted.
+	deleted bool
+	// datums holds the new values of the columns in written, indexed like the
+	// table's cols.
+	datums  tree.Datums
+	written util.FastIntSet
+}
+
+// bufferedKVTypes are the types of the rows in which a subscriptionApplier
+// buffers KVs: the wall time and the logical part of the timestamp of a KV,
+// its key and its value.
+var bufferedKVTypes = []*types.T{types.Int, types.Int, types.Bytes, types.Bytes}
+
+// bufferedKVOrdering orders the buffered KVs by timestamp and key.
+var bufferedKVOrdering = colinfo.ColumnOrdering{
+	{ColIdx: 0, Direction: encoding.Ascending},
+	{ColIdx: 1, Direction: encoding.Ascending},
+	{ColIdx: 2, Direction: encoding.Ascending},
+}
+
+// subscriptionApplier buffers the KVs received from a replication stream and
+// applies them, in timestamp order, to the tables of the subscribing cluster
+// once the stream has been resolved past their timestamps. The buffered KVs
+// are accounted for and spill to disk once they exceed the work memory limit,
+// since a lot of them can be received between two resolved timestamps, for
+// instance during the initial scan of large tables.
+type subscriptionApplier struct {
+	execCfg            *sql.ExecutorConfig
+	user               username.SQLUsername
+	conflictResolution jobspb.LogicalReplicationDetails_ConflictResolution
+
+	tables map[descpb.ID]*subscribedTable
+
+	memMonitor  *mon.BytesMonitor
+	diskMonitor *mon.BytesMonitor
+	// buffered holds the KVs which have not been applied yet. spare is used
+	// to retain the KVs which remain buffered after a flush, after which the
+	// two containers are swapped.
+	buffered, spare *rowcontainer.DiskBackedRowContainer
+	scratch         rowenc.EncDatumRow
+	alloc           tree.DatumAlloc
+}
+
+func newSubscriptionApplier(
+	ctx context.Context,
+	p sql.JobExecContext,
+	details jobspb.LogicalReplicationDetails,
+	dbName string,
+	sourceTables []streampb.ReplicationStreamSpec_SourceTable,
+	destTables []catalog.TableDescriptor,
+) (*subscriptionApplier, error) {
+	execCfg := p.ExecCfg()
+	a := &subscriptionApplier{
+		execCfg:            execCfg,
+		user:               p.User(),
+		conflictResolution: details.ConflictResolution,
+		tables:             make(map[descpb.ID]*subscribedTable, len(sourceTables)),
+	}
+	for i, src := range sourceTables {
+		t, err := newSubscribedTable(src, dbName)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkDestinationTable(t.desc, destTables[i]); err != nil {
+			return nil, err
+		}
+		a.tables[t.desc.GetID()] = t
+	}
+
+	distSQLCfg := &execCfg.DistSQLSrv.ServerConfig
+	a.memMonitor = execinfra.NewLimitedMonitorNoFlowCtx(
+		ctx, distSQLCfg.ParentMemoryMonitor, distSQLCfg, p.SessionData(),
+		"logical-replication-buffer-limited",
+	)
+	a.diskMonitor = execinfra.NewMonitor(
+		ctx, distSQLCfg.ParentDiskMonitor, "logical-replication-buffer-disk",
+	)
+	evalCtx := &p.ExtendedEvalContext().Context
+	a.buffered, a.spare = &rowcontainer.DiskBackedRowContainer{}, &rowcontainer.DiskBackedRowContainer{}
+	for _, c := range []*rowcontainer.DiskBackedRowContainer{a.buffered, a.spare} {
+		c.Init(
+			bufferedKVOrdering, bufferedKVTypes, evalCtx,
+			distSQLCfg.TempStorage, a.memMonitor, a.diskMonitor,
+		)
+	}
+	a.scratch = make(rowenc.EncDatumRow, len(bufferedKVTypes))
+	return a, nil
+}
+
+// close releases the resources held by the applier.
+func (a *subscriptionApplier) close(ctx context.Context) {
+	a.buffered.Close(ctx)
+	a.spare.Close(ctx)
+	a.memMonitor.Stop(ctx)
+	a.diskMonitor.Stop(ctx)
+}
+
+// add buffers a KV received from the replication stream.
+func (a *subscriptionApplier) add(ctx context.Context, kv roachpb.KeyValue) error {
+	return a.addTo(ctx, a.buffered, kv)
+}
+
+func (a *subscriptionApplier) addTo(
+	ctx context.Context, c *rowcontainer.DiskBackedRowContainer, kv roachpb.KeyValue,
+) error {
+	a.scratch[0] = rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(kv.Value.Timestamp.WallTime)))
+	a.scratch[1] = rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(kv.Value.Timestamp.Logical)))
+	a.scratch[2] = rowenc.DatumToEncDatum(types.Bytes, tree.NewDBytes(tree.DBytes(kv.Key)))
+	a.scratch[3] = rowenc.DatumToEncDatum(types.Bytes, tree.NewDBytes(tree.DBytes(kv.Value.RawBytes)))
+	return c.AddRow(ctx, a.scratch)
+}
+
+// checkSourceTables returns an error if the schema of any of the published
+// tables changed since the subscription started.
+func (a *subscriptionApplier) checkSourceTables(
+	sourceTables []streampb.ReplicationStreamSpec_SourceTable,
+) error {
+	for i := range sourceTables {
+		desc := tabledesc.NewBuilder(&sourceTables[i].Desc).BuildImmutableTable()
+		t, ok := a.tables[desc.GetID()]
+		if !ok {
+			return errors.AssertionFailedf("unknown published table %q", desc.GetName())
+		}
+		if err := t.checkUnchanged(desc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeBuffered turns a buffered row back into a KV.
+func (a *subscriptionApplier) decodeBuffered(row rowenc.EncDatumRow) (roachpb.KeyValue, error) {
+	for i := range row {
+		if err := row[i].EnsureDecoded(bufferedKVTypes[i], &a.alloc); err != nil {
+			return roachpb.KeyValue{}, err
+		}
+	}
+	return roachpb.KeyValue{
+		Key: roachpb.Key(*row[2].Datum.(*tree.DBytes)),
+		Value: roachpb.Value{
+			RawBytes: []byte(*row[3].Datum.(*tree.DBytes)),
+			Timestamp: hlc.Timestamp{
+				WallTime: int64(*row[0].Datum.(*tree.DInt)),
+				Logical:  int32(*row[1].Datum.(*tree.DInt)),
+			},
+		},
+	}, nil
+}
+
+// flush applies all the buffered KVs with timestamps at or below resolved and
+// calls updateProgress in the transaction which applies the last of them.
+// The KVs are applied in timestamp order, in batches of at most
+// subscriptionApplyBatchSize row changes, each in its own transaction; if a
+// flush is interrupted, the changes will be applied again once the job
+// resumes from its last recorded progress, which is fine since applying them
+// is idempotent.
+func (a *subscriptionApplier) flush(
+	ctx context.Context,
+	resolved hlc.Timestamp,
+	updateProgress func(ctx context.Context, txn *kv.Txn) error,
+) error {
+	a.buffered.Sort(ctx)
+	iter := a.buffered.NewIterator(ctx)
+	defer iter.Close()
+
+	var d rowChangeDecoder
+	for iter.Rewind(); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		row, err := iter.Row()
+		if err != nil {
+			return err
+		}
+		kv, err := a.decodeBuffered(row)
+		if err != nil {
+			return err
+		}
+		if resolved.Less(kv.Value.Timestamp) {
+			if err := a.addTo(ctx, a.spare, kv); err != nil {
+				return err
+			}
+			continue
+		}
+		// All the KVs of a row change are adjacent, so the batch can be applied
+		// before the first KV of a new change is decoded.
+		if len(d.changes) >= subscriptionApplyBatchSize && d.startsNewChange(kv) {
+			if err := a.applyBatch(ctx, d.changes, nil /* updateProgress */); err != nil {
+				return err
+			}
+			d.changes = d.changes[:0]
+		}
+		if err := d.decode(ctx, a.tables, kv); err != nil {
+			return err
+		}
+	}
+	if err := a.applyBatch(ctx, d.changes, updateProgress); err != nil {
+		return err
+	}
+	if err := a.buffered.UnsafeReset(ctx); err != nil {
+		return err
+	}
+	a.buffered, a.spare = a.spare, a.buffered
+	return nil
+}
+
+// applyBatch applies the given row changes in a single transaction, in which
+// it also calls updateProgress if it is set.
+func (a *subscriptionApplier) applyBatch(
+	ctx context.Context,
+	batch []rowChange,
+	updateProgress func(ctx context.Context, txn *kv.Txn) error,
+) error {
+	return a.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		for i := range batch {
+			if err := a.apply(ctx, txn, &batch[i]); err != nil {
+				return err
+			}
+		}
+		if updateProgress == nil {
+			return nil
+		}
+		return updateProgress(ctx, txn)
+	})
+}
+
+// rowChangeDecoder turns KVs, received in timestamp and key order, into row
+// changes. All the KVs of a row with the same timestamp, one for each
+// modified column family, are merged into a single change.
+type rowChangeDecoder struct {
+	changes    []rowChange
+	lastRowKey roachpb.Key
+	lastTS     hlc.Timestamp
+}
+
+// startsNewChange returns whether the given KV is not part of the last
+// decoded row change.
+func (d *rowChangeDecoder) startsNewChange(kv roachpb.KeyValue) bool {
+	if d.lastRowKey == nil || kv.Value.Timestamp != d.lastTS {
+		return true
+	}
+	rowKey, err := keys.EnsureSafeSplitKey(kv.Key)
+	return err != nil || !bytes.Equal(rowKey, d.lastRowKey)
+}
+
+// decode decodes the given KV, which belongs to one of the given tables.
+func (d *rowChangeDecoder) decode(
+	ctx context.Context, tables map[descpb.ID]*subscribedTable, kv roachpb.KeyValue,
+) error {
+	rowKey, err := keys.EnsureSafeSplitKey(kv.Key)
+	if err != nil {
+		return err
+	}
+	tableKey, _, err := keys.DecodeTenantPrefix(rowKey)
+	if err != nil {
+		return err
+	}
+	_, tableID, err := keys.SystemSQLCodec.DecodeTablePrefix(tableKey)
+	if err != nil {
+		return err
+	}
+	t, ok := tables[descpb.ID(tableID)]
+	if !ok {
+		return errors.AssertionFailedf("received key %s of unknown table %d", kv.Key, tableID)
+	}
+	familyID, err := keys.DecodeFamilyKey(kv.Key)
+	if err != nil {
+		return err
+	}
+	datums, isDeleted, err := t.decode(ctx, kv)
+	if err != nil {
+		return errors.Wrapf(err, "decoding replicated row of table %s", t.dstName)
+	}
+
+	if len(d.changes) == 0 || !bytes.Equal(rowKey, d.lastRowKey) || kv.Value.Timestamp != d.lastTS {
+		d.changes = append(d.changes, rowChange{
+			table:  t,
+			datums: make(tree.Datums, len(t.cols)),
+		})
+		d.lastRowKey, d.lastTS = rowKey, kv.Value.Timestamp
+		for _, ord := range t.pkOrdinals {
+			d.changes[len(d.changes)-1].datums[ord] = datums[ord]
+		}
+	}
+	change := &d.changes[len(d.changes)-1]
+	if descpb.FamilyID(familyID) == 0 && isDeleted {
+		// Every row has a KV in family 0, so the row is deleted.
+		change.deleted = true
+		return nil
+	}
+	// If only a non-zero family was deleted, the values of its columns are
+	// NULL, which datums already reflects.
+	for _, ord := range t.familyOrdinals[descpb.FamilyID(familyID)] {
+		change.datums[ord] = datums[ord]
+		change.written.Add(ord)
+	}
+	return nil
+}
+
+// apply applies a single row change to the destination table.
+func (a *subscriptionApplier) apply(ctx context.Context, txn *kv.Txn, change *rowChange) error {
+	t := change.table
+	var stmt strings.Builder
+	var args []interface{}
+	if change.deleted {
+		if a.conflictResolution == jobspb.LogicalReplicationDetails_IGNORE {
+			// Replicated changes never modify the existing rows of the
+			// destination table, so deletes are ignored just like inserts of
+			// rows which already exist.
+			return nil
+		}
+		fmt.Fprintf(&stmt, "DELETE FROM %s WHERE ", t.dstName)
+		for i, ord := range t.pkOrdinals {
+			if i > 0 {
+				stmt.WriteString(" AND ")
+			}
+			args = append(args, change.datums[ord])
+			fmt.Fprintf(&stmt, "%s = $%d", t.colNames[ord], len(args))
+		}
+	} else {
+		var cols, placeholders []string
+		addCol := func(ord int) {
+			args = append(args, change.datums[ord])
+			cols = append(cols, t.colNames[ord])
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		for _, ord := range t.pkOrdinals {
+			// Computed key columns, such as the shard column of hash-sharded
+			// primary keys, are recomputed by the destination.
+			if !t.cols[ord].IsComputed() {
+				addCol(ord)
+			}
+		}
+		change.written.ForEach(addCol)
+		verb := "UPSERT"
+		if a.conflictResolution == jobspb.LogicalReplicationDetails_IGNORE {
+			verb = "INSERT"
+		}
+		fmt.Fprintf(&stmt, "%s INTO %s (%s) VALUES (%s)",
+			verb, t.dstName, strings.Join(cols, ", "), strings.Join(placeholders, ", "))
+		if a.conflictResolution == jobspb.LogicalReplicationDetails_IGNORE {
+			stmt.WriteString(" ON CONFLICT DO NOTHING")
+		}
+	}
+	_, err := a.execCfg.InternalExecutor.ExecEx(
+		ctx, "apply-subscription-row", txn,
+		sessiondata.InternalExecutorOverride{User: a.user}, stmt.String(), args...,
+	)
+	return errors.Wrapf(err, "applying replicated change to %s", t.dstName)
+}
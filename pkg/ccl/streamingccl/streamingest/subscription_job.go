@@ -0,0 +1,302 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingest
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streamclient"
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streampb"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/streaming"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// subscriptionResumer implements jobs.Resumer for the logical replication
+// jobs created by CREATE SUBSCRIPTION. The job consumes a replication stream
+// started for a publication on the publishing cluster, and applies the
+// replicated rows to the tables of the subscribing database with the same
+// schema and table names.
+type subscriptionResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &subscriptionResumer{}
+
+// subscriptionEvent is an event received on one of the partitions of the
+// replication stream.
+type subscriptionEvent struct {
+	streamingccl.Event
+	partition int
+}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *subscriptionResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	details := r.job.Details().(jobspb.LogicalReplicationDetails)
+	client, err := streamclient.NewStreamClient(streamingccl.StreamAddress(details.StreamAddress))
+	if err != nil {
+		return err
+	}
+	return errors.CombineErrors(r.replicate(ctx, p, client, details), client.Close())
+}
+
+func (r *subscriptionResumer) replicate(
+	ctx context.Context,
+	p sql.JobExecContext,
+	client streamclient.Client,
+	details jobspb.LogicalReplicationDetails,
+) error {
+	execCfg := p.ExecCfg()
+	streamID := streaming.StreamID(details.StreamID)
+
+	sourceTables, err := client.SourceTables(ctx, streamID)
+	if err != nil {
+		return err
+	}
+	var dbName string
+	var destTables []catalog.TableDescriptor
+	if err := sql.DescsTxn(ctx, execCfg, func(
+		ctx context.Context, txn *kv.Txn, col *descs.Collection,
+	) error {
+		_, db, err := col.GetImmutableDatabaseByID(
+			ctx, txn, details.ParentID, tree.DatabaseLookupFlags{Required: true},
+		)
+		if err != nil {
+			return err
+		}
+		dbName = db.GetName()
+		destTables = destTables[:0]
+		for _, src := range sourceTables {
+			tn := tree.MakeTableNameWithSchema(
+				tree.Name(dbName), tree.Name(src.SchemaName), tree.Name(src.Desc.Name),
+			)
+			_, dst, err := col.GetImmutableTableByName(ctx, txn, &tn, tree.ObjectLookupFlagsWithRequired())
+			if err != nil {
+				return err
+			}
+			destTables = append(destTables, dst)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	applier, err := newSubscriptionApplier(ctx, p, details, dbName, sourceTables, destTables)
+	if err != nil {
+		return err
+	}
+	defer applier.close(ctx)
+
+	topology, err := client.Plan(ctx, streamID)
+	if err != nil {
+		return err
+	}
+	progress := r.job.Progress()
+	replicatedTime := progress.GetLogicalReplication().ReplicatedTime
+	subscriptions := make([]streamclient.Subscription, 0, len(topology))
+	for _, partition := range topology {
+		sub, err := client.Subscribe(ctx, streamID, partition.SubscriptionToken, replicatedTime)
+		if err != nil {
+			return err
+		}
+		subscriptions = append(subscriptions, sub)
+	}
+
+	g := ctxgroup.WithContext(ctx)
+	eventCh := make(chan subscriptionEvent)
+	for i, sub := range subscriptions {
+		i, sub := i, sub
+		g.GoCtx(sub.Subscribe)
+		g.GoCtx(func(ctx context.Context) error {
+			for event := range sub.Events() {
+				select {
+				case eventCh <- subscriptionEvent{Event: event, partition: i}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return sub.Err()
+		})
+	}
+	g.GoCtx(func(ctx context.Context) error {
+		return r.applyEvents(ctx, execCfg, client, streamID, applier, len(subscriptions), replicatedTime, eventCh)
+	})
+	return g.Wait()
+}
+
+// applyEvents buffers the KVs received on all the partitions of the stream
+// and applies them whenever the minimum resolved timestamp across partitions
+// advances. It also periodically heartbeats the stream to keep it alive on
+// the publishing cluster.
+func (r *subscriptionResumer) applyEvents(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	client streamclient.Client,
+	streamID streaming.StreamID,
+	applier *subscriptionApplier,
+	numPartitions int,
+	replicatedTime hlc.Timestamp,
+	eventCh <-chan subscriptionEvent,
+) error {
+	resolved := make([]hlc.Timestamp, numPartitions)
+	heartbeatFrequency := func() time.Duration {
+		return streamingccl.StreamReplicationConsumerHeartbeatFrequency.Get(&execCfg.Settings.SV)
+	}
+	var timer timeutil.Timer
+	defer timer.Stop()
+	timer.Reset(heartbeatFrequency())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-timer.C:
+			timer.Read = true
+			timer.Reset(heartbeatFrequency())
+			status, err := client.Heartbeat(ctx, streamID, replicatedTime)
+			if err != nil {
+				return err
+			}
+			switch status.StreamStatus {
+			case streampb.StreamReplicationStatus_STREAM_ACTIVE:
+			case streampb.StreamReplicationStatus_UNKNOWN_STREAM_STATUS_RETRY:
+				log.Warningf(ctx, "replication stream %d has unknown stream status and will retry later", streamID)
+			default:
+				return streamingccl.NewStreamStatusErr(streamID, status.StreamStatus)
+			}
+
+		case event := <-eventCh:
+			if event.Event == nil {
+				continue
+			}
+			switch event.Type() {
+			case streamingccl.KVEvent:
+				kv := *event.GetKV()
+				if replicatedTime.Less(kv.Value.Timestamp) {
+					if err := applier.add(ctx, kv); err != nil {
+						return err
+					}
+				}
+			case streamingccl.SSTableEvent:
+				if err := addSSTable(ctx, applier, event.GetSSTable(), replicatedTime); err != nil {
+					return err
+				}
+			case streamingccl.CheckpointEvent:
+				resolved[event.partition].Forward(*event.GetResolved())
+				minResolved := resolved[0]
+				for _, ts := range resolved[1:] {
+					if ts.Less(minResolved) {
+						minResolved = ts
+					}
+				}
+				if !replicatedTime.Less(minResolved) {
+					continue
+				}
+				// The buffered KVs are decoded with the descriptors of the
+				// published tables as of the start of the subscription, which
+				// must still be valid. Besides, the stream only covers the
+				// primary indexes the tables had at that time.
+				sourceTables, err := client.SourceTables(ctx, streamID)
+				if err != nil {
+					return err
+				}
+				if err := applier.checkSourceTables(sourceTables); err != nil {
+					return err
+				}
+				if err := applier.flush(ctx, minResolved, func(ctx context.Context, txn *kv.Txn) error {
+					return r.job.Update(ctx, txn, func(
+						txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+					) error {
+						md.Progress.GetLogicalReplication().ReplicatedTime = minResolved
+						md.Progress.Progress = &jobspb.Progress_HighWater{HighWater: &minResolved}
+						ju.UpdateProgress(md.Progress)
+						return nil
+					})
+				}); err != nil {
+					return err
+				}
+				replicatedTime = minResolved
+			default:
+				return errors.Newf("unexpected event type %v in logical replication job %d",
+					event.Type(), r.job.ID())
+			}
+		}
+	}
+}
+
+// addSSTable buffers the KVs of an SSTable ingested on the publishing cluster
+// which are newer than replicatedTime.
+func addSSTable(
+	ctx context.Context,
+	applier *subscriptionApplier,
+	sst *roachpb.RangeFeedSSTable,
+	replicatedTime hlc.Timestamp,
+) error {
+	iter, err := storage.NewMemSSTIterator(sst.Data, true /* verify */)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	for iter.SeekGE(storage.MVCCKey{Key: sst.Span.Key}); ; iter.Next() {
+		if ok, err := iter.Valid(); err != nil {
+			return err
+		} else if !ok || !iter.UnsafeKey().Less(storage.MVCCKey{Key: sst.Span.EndKey}) {
+			return nil
+		}
+		key := iter.UnsafeKey()
+		if !replicatedTime.Less(key.Timestamp) {
+			continue
+		}
+		// The KV is copied when it is buffered, so there is no need to copy
+		// the unsafe key and value here.
+		if err := applier.add(ctx, roachpb.KeyValue{
+			Key: key.Key,
+			Value: roachpb.Value{
+				RawBytes:  iter.UnsafeValue(),
+				Timestamp: key.Timestamp,
+			},
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. It informs the
+// publishing cluster that the stream is no longer consumed.
+func (r *subscriptionResumer) OnFailOrCancel(ctx context.Context, _ interface{}) error {
+	details := r.job.Details().(jobspb.LogicalReplicationDetails)
+	client, err := streamclient.NewStreamClient(streamingccl.StreamAddress(details.StreamAddress))
+	if err == nil {
+		err = errors.CombineErrors(
+			client.Complete(ctx, streaming.StreamID(details.StreamID)), client.Close(),
+		)
+	}
+	if err != nil {
+		// The stream times out on the publishing cluster if it is no longer
+		// heartbeated, so there is no need to fail here.
+		log.Warningf(ctx, "failed to complete replication stream %d: %v", details.StreamID, err)
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingest
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl"
+	"github.com/cockroachdb/cockroach/pkg/ccl/streamingccl/streamclient"
+	"github.com/cockroachdb/cockroach/pkg/ccl/utilccl"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/errors"
+)
+
+const subscriptionOptConflictResolution = "conflict_resolution"
+
+var subscriptionOptionExpectValues = map[string]sql.KVStringOptValidate{
+	subscriptionOptConflictResolution: sql.KVStringOptRequireValue,
+}
+
+func checkStreamReplicationEnabled(p sql.PlanHookState, telemetryName string) error {
+	if p.SessionData().EnableStreamReplication {
+		return nil
+	}
+	return errors.WithTelemetry(
+		pgerror.WithCandidateCode(
+			errors.WithHint(
+				errors.Newf("stream replication is only supported experimentally"),
+				"You can enable stream replication by running `SET enable_experimental_stream_replication = true`.",
+			),
+			pgcode.FeatureNotSupported,
+		),
+		telemetryName,
+	)
+}
+
+// findSubscriptionJob returns the ID of the non-terminal job running the
+// subscription with the given name in the given database, if any.
+func findSubscriptionJob(
+	ctx context.Context, execCfg *sql.ExecutorConfig, txn *kv.Txn, dbID descpb.ID, name string,
+) (_ jobspb.JobID, found bool, retErr error) {
+	const stmt = `
+SELECT
+  id, payload
+FROM
+  system.jobs
+WHERE
+  status IN ` + jobs.NonTerminalStatusTupleString
+	it, err := execCfg.InternalExecutor.QueryIterator(ctx, "find-subscription-job", txn, stmt)
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() { retErr = errors.CombineErrors(retErr, it.Close()) }()
+
+	var ok bool
+	for ok, err = it.Next(ctx); ok; ok, err = it.Next(ctx) {
+		row := it.Cur()
+		payload, err := jobs.UnmarshalPayload(row[1])
+		if err != nil {
+			return 0, false, err
+		}
+		details := payload.GetLogicalReplication()
+		if details != nil && details.ParentID == dbID && details.SubscriptionName == name {
+			return jobspb.JobID(*row[0].(*tree.DInt)), true, nil
+		}
+	}
+	return 0, false, err
+}
+
+func createSubscriptionJobDescription(
+	p sql.PlanHookState, stmt *tree.CreateSubscription, connection string,
+) (string, error) {
+	cleanedConnection, err := cloud.SanitizeExternalStorageURI(connection, nil /* extraParams */)
+	if err != nil {
+		return "", err
+	}
+	sanitized := *stmt
+	sanitized.Connection = tree.NewDString(cleanedConnection)
+	ann := p.ExtendedEvalContext().Annotations
+	return tree.AsStringWithFQNames(&sanitized, ann), nil
+}
+
+func createSubscriptionPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	createStmt, ok := stmt.(*tree.CreateSubscription)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	if err := checkStreamReplicationEnabled(p, "replication.subscription.disabled"); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	connectionFn, err := p.TypeAsString(ctx, createStmt.Connection, "CREATE SUBSCRIPTION")
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	optsFn, err := p.TypeAsStringOpts(ctx, createStmt.Options, subscriptionOptionExpectValues)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, _ chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if err := utilccl.CheckEnterpriseEnabled(
+			p.ExecCfg().Settings, p.ExecCfg().LogicalClusterID(), p.ExecCfg().Organization(),
+			"CREATE SUBSCRIPTION",
+		); err != nil {
+			return err
+		}
+		if err := p.RequireAdminRole(ctx, "CREATE SUBSCRIPTION"); err != nil {
+			return err
+		}
+
+		connection, err := connectionFn()
+		if err != nil {
+			return err
+		}
+		opts, err := optsFn()
+		if err != nil {
+			return err
+		}
+		conflictResolution := jobspb.LogicalReplicationDetails_OVERWRITE
+		if v, ok := opts[subscriptionOptConflictResolution]; ok {
+			switch strings.ToLower(v) {
+			case "overwrite":
+			case "ignore":
+				conflictResolution = jobspb.LogicalReplicationDetails_IGNORE
+			default:
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					"unknown %s %q, valid values are 'overwrite' and 'ignore'",
+					subscriptionOptConflictResolution, v)
+			}
+		}
+
+		dbDesc, err := p.ExtendedEvalContext().Descs.GetImmutableDatabaseByName(
+			ctx, p.Txn(), p.CurrentDatabase(), tree.DatabaseLookupFlags{Required: true},
+		)
+		if err != nil {
+			return err
+		}
+		if _, found, err := findSubscriptionJob(
+			ctx, p.ExecCfg(), p.Txn(), dbDesc.GetID(), string(createStmt.Name),
+		); err != nil {
+			return err
+		} else if found {
+			return pgerror.Newf(pgcode.DuplicateObject,
+				"subscription %q already exists", createStmt.Name)
+		}
+
+		streamAddress := streamingccl.StreamAddress(connection)
+		streamURL, err := streamAddress.URL()
+		if err != nil {
+			return err
+		}
+		if streamURL.Scheme != "postgres" && streamURL.Scheme != "postgresql" {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"subscription connection must use the postgresql scheme, got %q", streamURL.Scheme)
+		}
+		*streamURL, err = maybeAddInlineSecurityCredentials(*streamURL)
+		if err != nil {
+			return err
+		}
+		streamAddress = streamingccl.StreamAddress(streamURL.String())
+
+		// Start the stream on the publishing cluster and check that every
+		// published table has a counterpart with the same schema on this side.
+		client, err := streamclient.NewStreamClient(streamAddress)
+		if err != nil {
+			return err
+		}
+		streamID, err := client.CreateForPublication(ctx, string(createStmt.Publication))
+		if err != nil {
+			return errors.CombineErrors(err, client.Close())
+		}
+		sourceTables, err := client.SourceTables(ctx, streamID)
+		if err != nil {
+			return errors.CombineErrors(err, client.Close())
+		}
+		if err := client.Close(); err != nil {
+			return err
+		}
+		for _, src := range sourceTables {
+			tn := tree.MakeTableNameWithSchema(
+				tree.Name(dbDesc.GetName()), tree.Name(src.SchemaName), tree.Name(src.Desc.Name),
+			)
+			_, dst, err := resolver.ResolveExistingTableObject(
+				ctx, p, &tn, tree.ObjectLookupFlagsWithRequiredTableKind(tree.ResolveRequireTableDesc),
+			)
+			if err != nil {
+				return errors.Wrapf(err, "resolving destination of published table %s.%s",
+					tree.Name(src.SchemaName), tree.Name(src.Desc.Name))
+			}
+			if err := checkDestinationTable(
+				tabledesc.NewBuilder(&src.Desc).BuildImmutableTable(), dst,
+			); err != nil {
+				return err
+			}
+		}
+
+		description, err := createSubscriptionJobDescription(p, createStmt, connection)
+		if err != nil {
+			return err
+		}
+		jr := jobs.Record{
+			Description: description,
+			Username:    p.User(),
+			Details: jobspb.LogicalReplicationDetails{
+				SubscriptionName:   string(createStmt.Name),
+				ParentID:           dbDesc.GetID(),
+				StreamAddress:      string(streamAddress),
+				Publication:        string(createStmt.Publication),
+				StreamID:           uint64(streamID),
+				ConflictResolution: conflictResolution,
+			},
+			Progress: jobspb.LogicalReplicationProgress{},
+		}
+		jobID := p.ExecCfg().JobRegistry.MakeJobID()
+		_, err = p.ExecCfg().JobRegistry.CreateAdoptableJobWithTxn(ctx, jr, jobID, p.Txn())
+		return err
+	}
+	return fn, nil, nil, false, nil
+}
+
+func dropSubscriptionPlanHook(
+	ctx context.Context, stmt tree.Statement, p sql.PlanHookState,
+) (sql.PlanHookRowFn, colinfo.ResultColumns, []sql.PlanNode, bool, error) {
+	dropStmt, ok := stmt.(*tree.DropSubscription)
+	if !ok {
+		return nil, nil, nil, false, nil
+	}
+	if err := checkStreamReplicationEnabled(p, "replication.subscription.disabled"); err != nil {
+		return nil, nil, nil, false, err
+	}
+
+	fn := func(ctx context.Context, _ []sql.PlanNode, _ chan<- tree.Datums) error {
+		ctx, span := tracing.ChildSpan(ctx, stmt.StatementTag())
+		defer span.Finish()
+
+		if err := p.RequireAdminRole(ctx, "DROP SUBSCRIPTION"); err != nil {
+			return err
+		}
+		dbDesc, err := p.ExtendedEvalContext().Descs.GetImmutableDatabaseByName(
+			ctx, p.Txn(), p.CurrentDatabase(), tree.DatabaseLookupFlags{Required: true},
+		)
+		if err != nil {
+			return err
+		}
+		jobID, found, err := findSubscriptionJob(
+			ctx, p.ExecCfg(), p.Txn(), dbDesc.GetID(), string(dropStmt.Name),
+		)
+		if err != nil {
+			return err
+		}
+		if !found {
+			if !dropStmt.IfExists {
+				return pgerror.Newf(pgcode.UndefinedObject,
+					"subscription %q does not exist", dropStmt.Name)
+			}
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				"subscription %q does not exist, skipping", dropStmt.Name,
+			))
+			return nil
+		}
+		// Canceling the job stops the replication and releases the stream on
+		// the publishing cluster.
+		return p.ExecCfg().JobRegistry.CancelRequested(ctx, p.Txn(), jobID)
+	}
+	return fn, nil, nil, false, nil
+}
+
+func init() {
+	sql.AddPlanHook("create subscription", createSubscriptionPlanHook)
+	sql.AddPlanHook("drop subscription", dropSubscriptionPlanHook)
+	jobs.RegisterConstructor(
+		jobspb.TypeLogicalReplication,
+		func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+			return &subscriptionResumer{job: job}
+		},
+	)
+}
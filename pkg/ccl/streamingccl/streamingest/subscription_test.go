@@ -0,0 +1,179 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package streamingest
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/jobutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// TestSubscriptionEndToEnd replicates a publication of one database into
+// another database of the same cluster.
+func TestSubscriptionEndToEnd(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	skip.UnderRace(t, "slow under race")
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{JobsTestingKnobs: jobs.NewTestingKnobsWithShortIntervals()},
+	})
+	defer s.Stopper().Stop(ctx)
+
+	// Pin a single connection so that the session variables below stick.
+	db.SetMaxOpenConns(1)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.ExecMultiple(t, strings.Split(srcClusterSetting+destClusterSetting, ";")...)
+
+	sqlDB.ExecMultiple(t,
+		`CREATE DATABASE src`,
+		`CREATE TABLE src.t (k INT PRIMARY KEY, v STRING, w INT AS (k * 2) STORED)`,
+		`CREATE TABLE src.unpublished (k INT PRIMARY KEY)`,
+		`INSERT INTO src.t (k, v) VALUES (1, 'a'), (2, 'b'), (3, 'c')`,
+		`INSERT INTO src.unpublished VALUES (1)`,
+		`USE src`,
+		`CREATE PUBLICATION pub FOR TABLE t`,
+		`CREATE DATABASE dst`,
+		`CREATE TABLE dst.t (k INT PRIMARY KEY, v STRING, w INT AS (k * 2) STORED)`,
+		`CREATE TABLE dst.unpublished (k INT PRIMARY KEY)`,
+		`USE dst`,
+	)
+
+	pgURL, cleanup := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(username.RootUser))
+	defer cleanup()
+	pgURL.Path = "src"
+
+	sqlDB.ExpectErr(t, `unknown conflict_resolution "merge"`,
+		`CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION pub WITH (conflict_resolution = 'merge')`,
+		pgURL.String())
+	sqlDB.ExpectErr(t, `subscription connection must use the postgresql scheme`,
+		`CREATE SUBSCRIPTION sub CONNECTION 'nodelocal://1/foo' PUBLICATION pub`)
+	sqlDB.ExpectErr(t, `publication "missing" does not exist`,
+		`CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION missing`, pgURL.String())
+
+	sqlDB.Exec(t, `CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION pub`, pgURL.String())
+	sqlDB.ExpectErr(t, `subscription "sub" already exists`,
+		`CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION pub`, pgURL.String())
+	var jobID jobspb.JobID
+	sqlDB.QueryRow(t,
+		`SELECT job_id FROM [SHOW JOBS] WHERE job_type = 'LOGICAL REPLICATION'`).Scan(&jobID)
+
+	compare := func() {
+		t.Helper()
+		sqlDB.CheckQueryResultsRetry(t, `SELECT * FROM dst.t ORDER BY k`,
+			sqlDB.QueryStr(t, `SELECT * FROM src.t ORDER BY k`))
+	}
+	compare()
+
+	sqlDB.ExecMultiple(t,
+		`INSERT INTO src.t (k, v) VALUES (4, 'd')`,
+		`UPDATE src.t SET v = 'bb' WHERE k = 2`,
+		`DELETE FROM src.t WHERE k = 1`,
+		`INSERT INTO src.unpublished VALUES (2)`,
+	)
+	compare()
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM dst.unpublished`, [][]string{{"0"}})
+
+	sqlDB.Exec(t, `DROP SUBSCRIPTION sub`)
+	jobutils.WaitForJobToCancel(t, sqlDB, jobID)
+	sqlDB.ExpectErr(t, `subscription "sub" does not exist`, `DROP SUBSCRIPTION sub`)
+}
+
+// TestSubscriptionConflictsAndSchemaChanges checks that replicated changes
+// honor conflict_resolution, and that a subscription fails once the schema
+// of a published table changes. All the KVs buffered by the subscription
+// spill to disk.
+func TestSubscriptionConflictsAndSchemaChanges(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	skip.UnderRace(t, "slow under race")
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			JobsTestingKnobs: jobs.NewTestingKnobsWithShortIntervals(),
+			DistSQL:          &execinfra.TestingKnobs{ForceDiskSpill: true},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+
+	db.SetMaxOpenConns(1)
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.ExecMultiple(t, strings.Split(srcClusterSetting+destClusterSetting, ";")...)
+
+	sqlDB.ExecMultiple(t,
+		`CREATE DATABASE src`,
+		`CREATE TABLE src.t (k INT PRIMARY KEY, v STRING)`,
+		`CREATE TABLE src.mismatch (k INT PRIMARY KEY, v STRING)`,
+		`INSERT INTO src.t VALUES (1, 'a'), (2, 'b'), (3, 'c')`,
+		`USE src`,
+		`CREATE PUBLICATION pub FOR TABLE t`,
+		`CREATE PUBLICATION mismatch FOR TABLE mismatch`,
+		`CREATE DATABASE dst`,
+		`CREATE TABLE dst.t (k INT PRIMARY KEY, v STRING)`,
+		`CREATE TABLE dst.mismatch (k INT PRIMARY KEY, v INT)`,
+		`INSERT INTO dst.t VALUES (1, 'local')`,
+		`USE dst`,
+	)
+
+	pgURL, cleanup := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(username.RootUser))
+	defer cleanup()
+	pgURL.Path = "src"
+
+	sqlDB.ExpectErr(t, `cannot replicate published table "mismatch" into table "mismatch": column "v" has type INT8 instead of STRING`,
+		`CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION mismatch`, pgURL.String())
+
+	sqlDB.Exec(t, `CREATE SUBSCRIPTION sub CONNECTION $1 PUBLICATION pub WITH (conflict_resolution = 'ignore')`,
+		pgURL.String())
+	var jobID jobspb.JobID
+	sqlDB.QueryRow(t,
+		`SELECT job_id FROM [SHOW JOBS] WHERE job_type = 'LOGICAL REPLICATION'`).Scan(&jobID)
+	sqlDB.CheckQueryResultsRetry(t, `SELECT * FROM dst.t ORDER BY k`,
+		[][]string{{"1", "local"}, {"2", "b"}, {"3", "c"}})
+
+	// Neither updates nor deletes of replicated rows modify the existing rows.
+	sqlDB.ExecMultiple(t,
+		`DELETE FROM src.t WHERE k = 1`,
+		`UPDATE src.t SET v = 'bb' WHERE k = 2`,
+		`INSERT INTO src.t VALUES (4, 'd')`,
+	)
+	sqlDB.CheckQueryResultsRetry(t, `SELECT * FROM dst.t ORDER BY k`,
+		[][]string{{"1", "local"}, {"2", "b"}, {"3", "c"}, {"4", "d"}})
+
+	sqlDB.Exec(t, `ALTER TABLE src.t ADD COLUMN w INT`)
+	testutils.SucceedsSoon(t, func() error {
+		var status, jobErr string
+		sqlDB.QueryRow(t, `SELECT status, error FROM [SHOW JOB $1]`, jobID).Scan(&status, &jobErr)
+		if status != string(jobs.StatusFailed) {
+			return errors.Newf("job %d is %s", jobID, status)
+		}
+		if !strings.Contains(jobErr, `the schema of published table "t" changed on the publishing cluster`) {
+			return errors.Newf("unexpected error: %s", jobErr)
+		}
+		return nil
+	})
+}
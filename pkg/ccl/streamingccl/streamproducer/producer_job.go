@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -54,6 +55,30 @@ func makeProducerJobRecord(
 	}
 }
 
+func makeProducerJobRecordForPublication(
+	registry *jobs.Registry,
+	publication string,
+	spans []*roachpb.Span,
+	tableIDs []descpb.ID,
+	timeout time.Duration,
+	user username.SQLUsername,
+	ptsID uuid.UUID,
+) jobs.Record {
+	return jobs.Record{
+		JobID:       registry.MakeJobID(),
+		Description: fmt.Sprintf("stream replication for publication %s", publication),
+		Username:    user,
+		Details: jobspb.StreamReplicationDetails{
+			ProtectedTimestampRecord: &ptsID,
+			Spans:                    spans,
+			TableIDs:                 tableIDs,
+		},
+		Progress: jobspb.StreamReplicationProgress{
+			Expiration: timeutil.Now().Add(timeout),
+		},
+	}
+}
+
 type producerJobResumer struct {
 	job *jobs.Job
 
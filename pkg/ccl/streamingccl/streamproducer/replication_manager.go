@@ -29,6 +29,14 @@ func (r *replicationStreamManagerImpl) StartReplicationStream(
 	return startReplicationStreamJob(evalCtx, txn, tenantID)
 }
 
+// StartReplicationStreamForPublication implements
+// streaming.ReplicationStreamManager interface.
+func (r *replicationStreamManagerImpl) StartReplicationStreamForPublication(
+	evalCtx *eval.Context, txn *kv.Txn, publication string,
+) (streaming.StreamID, error) {
+	return startReplicationStreamJobForPublication(evalCtx, txn, publication)
+}
+
 // UpdateReplicationStreamProgress implements streaming.ReplicationStreamManager interface.
 func (r *replicationStreamManagerImpl) UpdateReplicationStreamProgress(
 	evalCtx *eval.Context, streamID streaming.StreamID, frontier hlc.Timestamp, txn *kv.Txn,
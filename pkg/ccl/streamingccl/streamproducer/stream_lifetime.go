@@ -22,7 +22,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts/ptpb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/streaming"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
@@ -73,6 +77,74 @@ func startReplicationStreamJob(
 	return streaming.StreamID(jr.JobID), nil
 }
 
+// startReplicationStreamJobForPublication initializes a replication stream
+// producer job on the source cluster for the primary indexes of the tables in
+// the specified publication of the session's current database. The tables are
+// resolved once, when the stream is started.
+func startReplicationStreamJobForPublication(
+	evalCtx *eval.Context, txn *kv.Txn, publication string,
+) (streaming.StreamID, error) {
+	ctx := evalCtx.Ctx()
+	execConfig := evalCtx.Planner.ExecutorConfig().(*sql.ExecutorConfig)
+	hasAdminRole, err := evalCtx.SessionAccessor.HasAdminRole(ctx)
+	if err != nil {
+		return streaming.InvalidStreamID, err
+	}
+	if !hasAdminRole {
+		return streaming.InvalidStreamID, errors.New("admin role required to start stream replication jobs")
+	}
+
+	descsCol := execConfig.CollectionFactory.NewCollection(ctx, nil /* temporarySchemaProvider */)
+	defer descsCol.ReleaseAll(ctx)
+	_, tables, err := sql.GetPublicationTables(
+		ctx, txn, descsCol, evalCtx.SessionData().Database, publication,
+	)
+	if err != nil {
+		return streaming.InvalidStreamID, err
+	}
+	if len(tables) == 0 {
+		return streaming.InvalidStreamID, pgerror.Newf(pgcode.InvalidParameterValue,
+			"publication %q does not contain any tables", publication)
+	}
+
+	spans := make([]*roachpb.Span, 0, len(tables))
+	tableIDs := make(descpb.IDs, 0, len(tables))
+	for _, table := range tables {
+		if table.ContainsUserDefinedTypes() {
+			return streaming.InvalidStreamID, pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot replicate table %q: tables with user-defined types are not supported",
+				table.GetName())
+		}
+		span := table.PrimaryIndexSpan(execConfig.Codec)
+		spans = append(spans, &span)
+		tableIDs = append(tableIDs, table.GetID())
+	}
+
+	registry := execConfig.JobRegistry
+	timeout := streamingccl.StreamReplicationJobLivenessTimeout.Get(&evalCtx.Settings.SV)
+	ptsID := uuid.MakeV4()
+	jr := makeProducerJobRecordForPublication(
+		registry, publication, spans, tableIDs, timeout, evalCtx.SessionData().User(), ptsID,
+	)
+	if _, err := registry.CreateAdoptableJobWithTxn(ctx, jr, jr.JobID, txn); err != nil {
+		return streaming.InvalidStreamID, err
+	}
+
+	statementTime := hlc.Timestamp{
+		WallTime: evalCtx.GetStmtTimestamp().UnixNano(),
+	}
+	deprecatedSpansToProtect := make(roachpb.Spans, 0, len(spans))
+	for _, sp := range spans {
+		deprecatedSpansToProtect = append(deprecatedSpansToProtect, *sp)
+	}
+	pts := jobsprotectedts.MakeRecord(ptsID, int64(jr.JobID), statementTime,
+		deprecatedSpansToProtect, jobsprotectedts.Jobs, ptpb.MakeSchemaObjectsTarget(tableIDs))
+	if err := execConfig.ProtectedTimestampProvider.Protect(ctx, txn, pts); err != nil {
+		return streaming.InvalidStreamID, err
+	}
+	return streaming.StreamID(jr.JobID), nil
+}
+
 // updateReplicationStreamProgress updates the job progress for an active replication
 // stream specified by 'streamID' and returns error if the stream is no longer active.
 func updateReplicationStreamProgress(
@@ -168,7 +240,8 @@ func getReplicationStreamSpec(
 	planCtx := dsp.NewPlanningCtx(evalCtx.Ctx(), jobExecCtx.ExtendedEvalContext(),
 		nil /* planner */, noTxn, sql.DistributionTypeSystemTenantOnly)
 
-	replicatedSpans := j.Details().(jobspb.StreamReplicationDetails).Spans
+	details := j.Details().(jobspb.StreamReplicationDetails)
+	replicatedSpans := details.Spans
 	spans := make([]roachpb.Span, 0, len(replicatedSpans))
 	for _, span := range replicatedSpans {
 		spans = append(spans, *span)
@@ -198,6 +271,41 @@ func getReplicationStreamSpec(
 			},
 		})
 	}
+
+	if len(details.TableIDs) > 0 {
+		if res.SourceTables, err = getSourceTables(
+			evalCtx.Ctx(), jobExecCtx.ExecCfg(), txn, details.TableIDs,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// getSourceTables returns the current descriptors of the given tables along
+// with the names of their schemas.
+func getSourceTables(
+	ctx context.Context, execCfg *sql.ExecutorConfig, txn *kv.Txn, tableIDs []descpb.ID,
+) ([]streampb.ReplicationStreamSpec_SourceTable, error) {
+	descsCol := execCfg.CollectionFactory.NewCollection(ctx, nil /* temporarySchemaProvider */)
+	defer descsCol.ReleaseAll(ctx)
+	res := make([]streampb.ReplicationStreamSpec_SourceTable, 0, len(tableIDs))
+	for _, id := range tableIDs {
+		table, err := descsCol.GetImmutableTableByID(ctx, txn, id, tree.ObjectLookupFlagsWithRequired())
+		if err != nil {
+			return nil, err
+		}
+		schema, err := descsCol.GetImmutableSchemaByID(
+			ctx, txn, table.GetParentSchemaID(), tree.SchemaLookupFlags{Required: true},
+		)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, streampb.ReplicationStreamSpec_SourceTable{
+			SchemaName: schema.GetName(),
+			Desc:       *table.TableDesc(),
+		})
+	}
 	return res, nil
 }
 
@@ -67,6 +67,7 @@ FROM [ SELECT table_name FROM [ SHOW TABLES FROM crdb_internal ] ]
 WHERE
 table_name NOT IN (
 	-- allowlisted tables that don't need to be in debug zip
+	'backup_verifications',
 	'backward_dependencies',
 	'builtin_functions',
 	'cluster_contended_keys',
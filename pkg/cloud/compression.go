@@ -0,0 +1,161 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cloud
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4"
+)
+
+// CompressionCodec is a codec which files written to external storage can be
+// compressed with.
+type CompressionCodec string
+
+// The supported compression codecs.
+const (
+	NoCompression     CompressionCodec = ``
+	CompressionGzip   CompressionCodec = `gzip`
+	CompressionZstd   CompressionCodec = `zstd`
+	CompressionSnappy CompressionCodec = `snappy`
+	CompressionLZ4    CompressionCodec = `lz4`
+)
+
+// ParseCompressionCodec returns the codec with the given case-insensitive
+// name.
+func ParseCompressionCodec(name string) (CompressionCodec, error) {
+	switch codec := CompressionCodec(strings.ToLower(name)); codec {
+	case CompressionGzip, CompressionZstd, CompressionSnappy, CompressionLZ4:
+		return codec, nil
+	default:
+		return NoCompression, errors.Errorf(`unsupported compression codec %q`, name)
+	}
+}
+
+// Extension returns the extension of the names of files compressed with the
+// codec, including the leading dot.
+func (c CompressionCodec) Extension() string {
+	switch c {
+	case CompressionGzip:
+		return `.gz`
+	case CompressionZstd:
+		return `.zst`
+	case CompressionSnappy:
+		return `.sz`
+	case CompressionLZ4:
+		return `.lz4`
+	default:
+		return ``
+	}
+}
+
+// ValidateLevel returns an error if the codec does not support the given
+// compression level. The level 0 is always valid, and selects the default
+// level of the codec.
+func (c CompressionCodec) ValidateLevel(level int) error {
+	if level == 0 {
+		return nil
+	}
+	var min, max int
+	switch c {
+	case CompressionGzip:
+		min, max = gzip.BestSpeed, gzip.BestCompression
+	case CompressionZstd:
+		min, max = 1, 22
+	default:
+		return errors.Errorf(`compression codec %q does not support compression levels`, c)
+	}
+	if level < min || level > max {
+		return errors.Errorf(`compression level for %q must be between %d and %d, found %d`,
+			c, min, max, level)
+	}
+	return nil
+}
+
+// Compressor is a writer which compresses the data written to it. Close must
+// be called to finish the compressed stream, but does not close the
+// underlying writer.
+type Compressor interface {
+	io.WriteCloser
+	// Flush writes any buffered data to the underlying writer.
+	Flush() error
+	// Reset discards the state of the compressor, which then writes to w.
+	Reset(w io.Writer)
+}
+
+// NewCompressor returns a Compressor which writes the data compressed with the
+// codec at the given level to w. The level 0 selects the default level of the
+// codec.
+func NewCompressor(w io.Writer, codec CompressionCodec, level int) (Compressor, error) {
+	if err := codec.ValidateLevel(level); err != nil {
+		return nil, err
+	}
+	switch codec {
+	case CompressionGzip:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		z, err := gzip.NewWriterLevel(w, level)
+		if err != nil {
+			return nil, err
+		}
+		return z, nil
+	case CompressionZstd:
+		var opts []zstd.EOption
+		if level != 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		z, err := zstd.NewWriter(w, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return z, nil
+	case CompressionSnappy:
+		return snappy.NewBufferedWriter(w), nil
+	case CompressionLZ4:
+		return lz4.NewWriter(w), nil
+	default:
+		return nil, errors.Errorf(`unsupported compression codec %q`, codec)
+	}
+}
+
+// NewDecompressingReader returns a reader which decompresses the data read
+// from r with the codec.
+func NewDecompressingReader(r io.Reader, codec CompressionCodec) (io.ReadCloser, error) {
+	switch codec {
+	case NoCompression:
+		return ioutil.NopCloser(r), nil
+	case CompressionGzip:
+		z, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return z, nil
+	case CompressionZstd:
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	case CompressionSnappy:
+		return ioutil.NopCloser(snappy.NewReader(r)), nil
+	case CompressionLZ4:
+		return ioutil.NopCloser(lz4.NewReader(r)), nil
+	default:
+		return nil, errors.Errorf(`unsupported compression codec %q`, codec)
+	}
+}
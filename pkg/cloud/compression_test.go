@@ -0,0 +1,81 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cloud
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	data := []byte(strings.Repeat(`{"a": 1, "b": "some json value"}`+"\n", 1000))
+	for _, tc := range []struct {
+		codec CompressionCodec
+		level int
+		err   string
+	}{
+		{codec: CompressionGzip},
+		{codec: CompressionGzip, level: 9},
+		{codec: CompressionGzip, level: 10, err: `compression level for "gzip" must be between 1 and 9, found 10`},
+		{codec: CompressionZstd},
+		{codec: CompressionZstd, level: 1},
+		{codec: CompressionZstd, level: 19},
+		{codec: CompressionZstd, level: 23, err: `compression level for "zstd" must be between 1 and 22, found 23`},
+		{codec: CompressionSnappy},
+		{codec: CompressionSnappy, level: 1, err: `compression codec "snappy" does not support compression levels`},
+		{codec: CompressionLZ4},
+	} {
+		t.Run(fmt.Sprintf("%s/%d", tc.codec, tc.level), func(t *testing.T) {
+			var buf bytes.Buffer
+			c, err := NewCompressor(&buf, tc.codec, tc.level)
+			if tc.err != `` {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+
+			// A compressor can be reset and reused once a stream is finished.
+			for i := 0; i < 2; i++ {
+				buf.Reset()
+				c.Reset(&buf)
+				_, err = c.Write(data[:len(data)/2])
+				require.NoError(t, err)
+				require.NoError(t, c.Flush())
+				_, err = c.Write(data[len(data)/2:])
+				require.NoError(t, err)
+				require.NoError(t, c.Close())
+				require.Less(t, buf.Len(), len(data))
+
+				r, err := NewDecompressingReader(&buf, tc.codec)
+				require.NoError(t, err)
+				decompressed, err := ioutil.ReadAll(r)
+				require.NoError(t, err)
+				require.NoError(t, r.Close())
+				require.Equal(t, data, decompressed)
+			}
+		})
+	}
+
+	codec, err := ParseCompressionCodec(`ZSTD`)
+	require.NoError(t, err)
+	require.Equal(t, CompressionZstd, codec)
+	require.Equal(t, `.zst`, codec.Extension())
+	_, err = ParseCompressionCodec(`brotli`)
+	require.EqualError(t, err, `unsupported compression codec "brotli"`)
+}
@@ -722,7 +722,7 @@ var specs = []stmtSpec{
 	},
 	{
 		name:   "delete_stmt",
-		inline: []string{"opt_with_clause", "with_clause", "cte_list", "table_expr_opt_alias_idx", "table_name_opt_idx", "opt_where_clause", "where_clause", "returning_clause", "opt_sort_clause", "opt_limit_clause", "opt_only", "opt_descendant"},
+		inline: []string{"opt_with_clause", "with_clause", "cte_list", "table_expr_opt_alias_idx", "table_name_opt_idx", "opt_using_clause", "from_list", "opt_where_clause", "where_clause", "returning_clause", "opt_sort_clause", "opt_limit_clause", "opt_only", "opt_descendant"},
 		replace: map[string]string{
 			"relation_expr": "table_name",
 		},
@@ -1059,13 +1059,14 @@ var specs = []stmtSpec{
 		inline: []string{"opt_as_of_clause", "as_of_clause", "opt_with_restore_options"},
 		match:  []*regexp.Regexp{regexp.MustCompile("'FROM'")},
 		replace: map[string]string{
-			"a_expr": "timestamp",
+			"'WHERE' a_expr": "'WHERE' predicate",
+			"a_expr":         "timestamp",
 			"'WITH' 'OPTIONS' '(' kv_option_list ')'": "",
 			"targets":                                "( 'TABLE' table_pattern ( ( ',' table_pattern ) )* | 'DATABASE' database_name ( ( ',' database_name ) )* )",
 			"string_or_placeholder":                  "( ( subdirectory | 'LATEST' ) )",
 			"list_of_string_or_placeholder_opt_list": "( collectionURI | '(' localityURI ( ',' localityURI )* ')' )",
 		},
-		unlink: []string{"subdirectory", "timestamp", "collectionURI", "localityURI"},
+		unlink: []string{"subdirectory", "timestamp", "predicate", "collectionURI", "localityURI"},
 		exclude: []*regexp.Regexp{
 			regexp.MustCompile("'REPLICATION' 'STREAM' 'FROM'"),
 		},
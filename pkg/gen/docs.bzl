@@ -88,12 +88,14 @@ DOCS_SRCS = [
   "//docs/generated/sql/bnf:create_extension_stmt.bnf",
   "//docs/generated/sql/bnf:create_index_stmt.bnf",
   "//docs/generated/sql/bnf:create_inverted_index_stmt.bnf",
+  "//docs/generated/sql/bnf:create_publication_stmt.bnf",
   "//docs/generated/sql/bnf:create_role_stmt.bnf",
   "//docs/generated/sql/bnf:create_schedule_for_backup_stmt.bnf",
   "//docs/generated/sql/bnf:create_schema_stmt.bnf",
   "//docs/generated/sql/bnf:create_sequence_stmt.bnf",
   "//docs/generated/sql/bnf:create_stats_stmt.bnf",
   "//docs/generated/sql/bnf:create_stmt.bnf",
+  "//docs/generated/sql/bnf:create_subscription_stmt.bnf",
   "//docs/generated/sql/bnf:create_table_as_stmt.bnf",
   "//docs/generated/sql/bnf:create_table_stmt.bnf",
   "//docs/generated/sql/bnf:create_type.bnf",
@@ -109,11 +111,13 @@ DOCS_SRCS = [
   "//docs/generated/sql/bnf:drop_ddl_stmt.bnf",
   "//docs/generated/sql/bnf:drop_index.bnf",
   "//docs/generated/sql/bnf:drop_owned_by_stmt.bnf",
+  "//docs/generated/sql/bnf:drop_publication_stmt.bnf",
   "//docs/generated/sql/bnf:drop_role_stmt.bnf",
   "//docs/generated/sql/bnf:drop_schedule_stmt.bnf",
   "//docs/generated/sql/bnf:drop_schema.bnf",
   "//docs/generated/sql/bnf:drop_sequence_stmt.bnf",
   "//docs/generated/sql/bnf:drop_stmt.bnf",
+  "//docs/generated/sql/bnf:drop_subscription_stmt.bnf",
   "//docs/generated/sql/bnf:drop_table.bnf",
   "//docs/generated/sql/bnf:drop_type.bnf",
   "//docs/generated/sql/bnf:drop_view.bnf",
@@ -183,6 +187,7 @@ DOCS_SRCS = [
   "//docs/generated/sql/bnf:select_clause.bnf",
   "//docs/generated/sql/bnf:select_stmt.bnf",
   "//docs/generated/sql/bnf:set_cluster_setting.bnf",
+  "//docs/generated/sql/bnf:set_constraints_stmt.bnf",
   "//docs/generated/sql/bnf:set_csetting_stmt.bnf",
   "//docs/generated/sql/bnf:set_exprs_internal.bnf",
   "//docs/generated/sql/bnf:set_local_stmt.bnf",
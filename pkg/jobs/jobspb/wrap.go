@@ -47,6 +47,10 @@ var (
 	_ Details = ImportDetails{}
 	_ Details = StreamReplicationDetails{}
 	_ Details = RowLevelTTLDetails{}
+	_ Details = LogicalReplicationDetails{}
+	_ Details = BackupVerificationDetails{}
+	_ Details = BackupCompactionDetails{}
+	_ Details = RestoreRowsDetails{}
 )
 
 // ProgressDetails is a marker interface for job progress details proto structs.
@@ -65,6 +69,10 @@ var (
 	_ ProgressDetails = AutoSpanConfigReconciliationDetails{}
 	_ ProgressDetails = StreamReplicationProgress{}
 	_ ProgressDetails = RowLevelTTLProgress{}
+	_ ProgressDetails = LogicalReplicationProgress{}
+	_ ProgressDetails = BackupVerificationProgress{}
+	_ ProgressDetails = BackupCompactionProgress{}
+	_ ProgressDetails = RestoreRowsProgress{}
 )
 
 // Type returns the payload's job type.
@@ -129,6 +137,14 @@ func DetailsType(d isPayload_Details) Type {
 		return TypeStreamReplication
 	case *Payload_RowLevelTTL:
 		return TypeRowLevelTTL
+	case *Payload_LogicalReplication:
+		return TypeLogicalReplication
+	case *Payload_BackupVerification:
+		return TypeBackupVerification
+	case *Payload_BackupCompaction:
+		return TypeBackupCompaction
+	case *Payload_RestoreRows:
+		return TypeRestoreRows
 	default:
 		panic(errors.AssertionFailedf("Payload.Type called on a payload with an unknown details type: %T", d))
 	}
@@ -173,6 +189,14 @@ func WrapProgressDetails(details ProgressDetails) interface {
 		return &Progress_StreamReplication{StreamReplication: &d}
 	case RowLevelTTLProgress:
 		return &Progress_RowLevelTTL{RowLevelTTL: &d}
+	case LogicalReplicationProgress:
+		return &Progress_LogicalReplication{LogicalReplication: &d}
+	case BackupVerificationProgress:
+		return &Progress_BackupVerification{BackupVerification: &d}
+	case BackupCompactionProgress:
+		return &Progress_BackupCompaction{BackupCompaction: &d}
+	case RestoreRowsProgress:
+		return &Progress_RestoreRows{RestoreRows: &d}
 	default:
 		panic(errors.AssertionFailedf("WrapProgressDetails: unknown details type %T", d))
 	}
@@ -212,6 +236,14 @@ func (p *Payload) UnwrapDetails() Details {
 		return *d.StreamReplication
 	case *Payload_RowLevelTTL:
 		return *d.RowLevelTTL
+	case *Payload_LogicalReplication:
+		return *d.LogicalReplication
+	case *Payload_BackupVerification:
+		return *d.BackupVerification
+	case *Payload_BackupCompaction:
+		return *d.BackupCompaction
+	case *Payload_RestoreRows:
+		return *d.RestoreRows
 	default:
 		return nil
 	}
@@ -251,6 +283,14 @@ func (p *Progress) UnwrapDetails() ProgressDetails {
 		return *d.StreamReplication
 	case *Progress_RowLevelTTL:
 		return *d.RowLevelTTL
+	case *Progress_LogicalReplication:
+		return *d.LogicalReplication
+	case *Progress_BackupVerification:
+		return *d.BackupVerification
+	case *Progress_BackupCompaction:
+		return *d.BackupCompaction
+	case *Progress_RestoreRows:
+		return *d.RestoreRows
 	default:
 		return nil
 	}
@@ -303,6 +343,14 @@ func WrapPayloadDetails(details Details) interface {
 		return &Payload_StreamReplication{StreamReplication: &d}
 	case RowLevelTTLDetails:
 		return &Payload_RowLevelTTL{RowLevelTTL: &d}
+	case LogicalReplicationDetails:
+		return &Payload_LogicalReplication{LogicalReplication: &d}
+	case BackupVerificationDetails:
+		return &Payload_BackupVerification{BackupVerification: &d}
+	case BackupCompactionDetails:
+		return &Payload_BackupCompaction{BackupCompaction: &d}
+	case RestoreRowsDetails:
+		return &Payload_RestoreRows{RestoreRows: &d}
 	default:
 		panic(errors.AssertionFailedf("jobs.WrapPayloadDetails: unknown details type %T", d))
 	}
@@ -338,7 +386,7 @@ const (
 func (Type) SafeValue() {}
 
 // NumJobTypes is the number of jobs types.
-const NumJobTypes = 17
+const NumJobTypes = 21
 
 // MarshalJSONPB implements jsonpb.JSONPBMarshaller to  redact sensitive sink URI
 // parameters from ChangefeedDetails.
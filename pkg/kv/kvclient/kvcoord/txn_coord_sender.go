@@ -137,6 +137,10 @@ type TxnCoordSender struct {
 		// This field is only populated on rootTxns.
 		userPriority roachpb.UserPriority
 
+		// isoLevel is the txn's isolation level. It determines how the txn
+		// handles retryable errors. This field is only populated on rootTxns.
+		isoLevel kv.IsolationLevel
+
 		// commitWaitDeferred is set to true when the transaction commit-wait
 		// state is deferred and should not be run automatically. Instead, the
 		// caller of DeferCommitWait has assumed responsibility for performing
@@ -815,6 +819,24 @@ func (tc *TxnCoordSender) handleRetryableErrLocked(
 	errTxnID := pErr.GetTxn().ID
 	newTxn := roachpb.PrepareTransactionForRetry(ctx, pErr, tc.mu.userPriority, tc.clock)
 
+	// ReadCommitted transactions only need to retry the statement that
+	// encountered the error, so they don't start a new epoch and keep the
+	// writes of their previous statements. Instead, the transaction's timestamp
+	// is moved forward to the timestamp that the retry needs. The client is
+	// expected to roll back to a savepoint taken at the start of the statement
+	// before retrying it.
+	if tc.mu.isoLevel == kv.ReadCommitted && errTxnID == newTxn.ID {
+		retryTxn := tc.mu.txn.Clone()
+		retryTxn.Refresh(newTxn.WriteTimestamp)
+		retryTxn.UpgradePriority(newTxn.Priority)
+		retErr := roachpb.NewTransactionRetryWithProtoRefreshError(
+			pErr.String(), errTxnID, *retryTxn)
+		tc.mu.txnState = txnRetryableError
+		tc.mu.storedRetryableErr = retErr
+		tc.mu.txn.Update(retryTxn)
+		return retErr
+	}
+
 	// We'll pass a TransactionRetryWithProtoRefreshError up to the next layer.
 	retErr := roachpb.NewTransactionRetryWithProtoRefreshError(
 		pErr.String(),
@@ -1001,6 +1023,27 @@ func (tc *TxnCoordSender) SetUserPriority(pri roachpb.UserPriority) error {
 	return nil
 }
 
+// SetIsoLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) SetIsoLevel(isoLevel kv.IsolationLevel) error {
+	if tc.typ != kv.RootTxn {
+		return errors.AssertionFailedf("cannot set the isolation level of a non-root txn")
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.mu.active && isoLevel != tc.mu.isoLevel {
+		return errors.New("cannot change the isolation level of a running transaction")
+	}
+	tc.mu.isoLevel = isoLevel
+	return nil
+}
+
+// IsoLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) IsoLevel() kv.IsolationLevel {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.mu.isoLevel
+}
+
 // SetDebugName is part of the client.TxnSender interface.
 func (tc *TxnCoordSender) SetDebugName(name string) {
 	tc.mu.Lock()
@@ -1115,6 +1158,13 @@ func (tc *TxnCoordSender) IsSerializablePushAndRefreshNotPossible() bool {
 	tc.mu.Lock()
 	defer tc.mu.Unlock()
 
+	// ReadCommitted transactions don't need to refresh the reads of their
+	// previous statements, and retry the current statement if its reads
+	// cannot be refreshed.
+	if tc.mu.isoLevel == kv.ReadCommitted {
+		return false
+	}
+
 	isTxnPushed := tc.mu.txn.WriteTimestamp != tc.mu.txn.ReadTimestamp
 	refreshAttemptNotPossible := tc.interceptorAlloc.txnSpanRefresher.refreshInvalid ||
 		tc.mu.txn.CommitTimestampFixed
@@ -1300,6 +1350,37 @@ func (tc *TxnCoordSender) SetReadSeqNum(seq enginepb.TxnSeq) error {
 	return nil
 }
 
+// StepReadTimestamp is part of the TxnSender interface.
+func (tc *TxnCoordSender) StepReadTimestamp(ctx context.Context) error {
+	if tc.typ != kv.RootTxn {
+		return errors.AssertionFailedf("cannot step the read timestamp of a non-root txn")
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.mu.isoLevel != kv.ReadCommitted {
+		return errors.AssertionFailedf(
+			"cannot step the read timestamp of a %s txn", tc.mu.isoLevel)
+	}
+	if tc.mu.txnState != txnPending {
+		return errors.AssertionFailedf(
+			"cannot step the read timestamp of a txn in state %s", tc.mu.txnState)
+	}
+	if tc.mu.txn.CommitTimestampFixed {
+		return errors.AssertionFailedf(
+			"cannot step the read timestamp of a txn with a fixed commit timestamp")
+	}
+
+	// Read at the current time, with a new uncertainty interval. The observed
+	// timestamps are discarded, since they only bound the uncertainty of reads
+	// that are causally ordered after the txn's previous snapshot.
+	now := tc.clock.Now()
+	tc.mu.txn.Refresh(now)
+	tc.mu.txn.GlobalUncertaintyLimit.Forward(now.Add(tc.clock.MaxOffset().Nanoseconds(), 0))
+	tc.mu.txn.ObservedTimestamps = nil
+	tc.interceptorAlloc.txnSpanRefresher.resetRefreshSpansLocked(tc.mu.txn.ReadTimestamp)
+	return nil
+}
+
 // ConfigureStepping is part of the TxnSender interface.
 func (tc *TxnCoordSender) ConfigureStepping(
 	ctx context.Context, mode kv.SteppingMode,
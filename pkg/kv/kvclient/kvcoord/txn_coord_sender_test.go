@@ -2958,3 +2958,67 @@ func TestTxnCoordSenderSetFixedTimestamp(t *testing.T) {
 		})
 	}
 }
+
+// TestTxnCoordSenderReadCommitted verifies that a ReadCommitted transaction
+// reads from a new snapshot after stepping its read timestamp, and that
+// retryable errors don't restart it.
+func TestTxnCoordSenderReadCommitted(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	s := createTestDB(t)
+	defer s.Stop()
+
+	txn := kv.NewTxn(ctx, s.DB, 0 /* gatewayNodeID */)
+	require.NoError(t, txn.SetIsoLevel(kv.ReadCommitted))
+	require.Equal(t, kv.ReadCommitted, txn.IsoLevel())
+
+	// The writes committed by other transactions are only observed once the
+	// read timestamp is stepped.
+	kv1, err := txn.Get(ctx, "a")
+	require.NoError(t, err)
+	require.False(t, kv1.Exists())
+	require.NoError(t, s.DB.Put(ctx, "a", "1"))
+	kv1, err = txn.Get(ctx, "a")
+	require.NoError(t, err)
+	require.False(t, kv1.Exists())
+	require.NoError(t, txn.StepReadTimestamp(ctx))
+	kv1, err = txn.Get(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, kv1.Exists())
+
+	// The isolation level can't be changed once the transaction is running.
+	require.Regexp(t, "cannot change the isolation level of a running transaction",
+		txn.SetIsoLevel(kv.Serializable))
+
+	// A write-write conflict on a key read at an older snapshot returns a
+	// retryable error, which does not start a new epoch.
+	require.NoError(t, txn.Put(ctx, "c", "1"))
+	_, err = txn.Get(ctx, "b")
+	require.NoError(t, err)
+	require.NoError(t, s.DB.Put(ctx, "b", "1"))
+	err = txn.Put(ctx, "b", "2")
+	require.True(t, errors.HasType(err, (*roachpb.TransactionRetryWithProtoRefreshError)(nil)), "%+v", err)
+	require.Equal(t, enginepb.TxnEpoch(0), txn.Epoch())
+	require.NoError(t, txn.PrepareForPartialRetry(ctx))
+
+	// The transaction can retry the write at a new snapshot and commit, along
+	// with its writes from before the error.
+	require.NoError(t, txn.StepReadTimestamp(ctx))
+	kv1, err = txn.Get(ctx, "b")
+	require.NoError(t, err)
+	require.True(t, kv1.Exists())
+	require.NoError(t, txn.Put(ctx, "b", "2"))
+	require.NoError(t, txn.Commit(ctx))
+
+	for key, exp := range map[string]string{"b": "2", "c": "1"} {
+		kv1, err = s.DB.Get(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, []byte(exp), kv1.ValueBytes())
+	}
+
+	// Serializable transactions can't step their read timestamp.
+	txn = kv.NewTxn(ctx, s.DB, 0 /* gatewayNodeID */)
+	require.Regexp(t, "cannot step the read timestamp of a SERIALIZABLE txn",
+		txn.StepReadTimestamp(ctx))
+}
@@ -698,6 +698,20 @@ func (sr *txnSpanRefresher) epochBumpedLocked() {
 	sr.refreshedTimestamp.Reset()
 }
 
+// resetRefreshSpansLocked discards the refresh spans of the transaction after
+// its read timestamp was stepped forward to the provided timestamp by a
+// ReadCommitted transaction. The reads performed before the step are not
+// refreshed anymore, even if the transaction was pushed: each statement of a
+// ReadCommitted transaction may observe a different snapshot, so only the reads
+// of the current statement need to remain valid at the commit timestamp. SQL
+// locks the rows read by the constraint checks which rely on reads remaining
+// valid until the transaction commits.
+func (sr *txnSpanRefresher) resetRefreshSpansLocked(readTimestamp hlc.Timestamp) {
+	sr.refreshFootprint.clear()
+	sr.refreshInvalid = false
+	sr.refreshedTimestamp.Forward(readTimestamp)
+}
+
 // createSavepointLocked is part of the txnInterceptor interface.
 func (sr *txnSpanRefresher) createSavepointLocked(ctx context.Context, s *savepoint) {
 	s.refreshSpans = make([]roachpb.Span, len(sr.refreshFootprint.asSlice()))
@@ -16,6 +16,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
 )
 
 // MockTransactionalSender allows a function to be used as a TxnSender.
@@ -76,6 +77,19 @@ func (m *MockTransactionalSender) SetUserPriority(pri roachpb.UserPriority) erro
 	return nil
 }
 
+// SetIsoLevel is part of the TxnSender interface.
+func (m *MockTransactionalSender) SetIsoLevel(isoLevel IsolationLevel) error {
+	if isoLevel != Serializable {
+		panic("unimplemented")
+	}
+	return nil
+}
+
+// IsoLevel is part of the TxnSender interface.
+func (m *MockTransactionalSender) IsoLevel() IsolationLevel {
+	return Serializable
+}
+
 // SetDebugName is part of the TxnSender interface.
 func (m *MockTransactionalSender) SetDebugName(name string) {
 	m.txn.Name = name
@@ -193,6 +207,12 @@ func (m *MockTransactionalSender) Step(_ context.Context) error {
 // SetReadSeqNum is part of the TxnSender interface.
 func (m *MockTransactionalSender) SetReadSeqNum(_ enginepb.TxnSeq) error { return nil }
 
+// StepReadTimestamp is part of the TxnSender interface. The mock only runs
+// Serializable transactions, whose read timestamp cannot be stepped.
+func (m *MockTransactionalSender) StepReadTimestamp(context.Context) error {
+	return errors.AssertionFailedf("cannot step the read timestamp of a %s txn", m.IsoLevel())
+}
+
 // ConfigureStepping is part of the TxnSender interface.
 func (m *MockTransactionalSender) ConfigureStepping(context.Context, SteppingMode) SteppingMode {
 	// See Step() above.
@@ -12,6 +12,7 @@ package kv
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -115,6 +116,13 @@ type TxnSender interface {
 	// SetUserPriority sets the txn's priority.
 	SetUserPriority(roachpb.UserPriority) error
 
+	// SetIsoLevel sets the txn's isolation level. It can only be called
+	// before the txn has performed any operations.
+	SetIsoLevel(IsolationLevel) error
+
+	// IsoLevel returns the txn's isolation level.
+	IsoLevel() IsolationLevel
+
 	// SetDebugName sets the txn's debug name.
 	SetDebugName(name string)
 
@@ -277,6 +285,15 @@ type TxnSender interface {
 	// SetReadSeqNum sets the read sequence point for the current transaction.
 	SetReadSeqNum(seq enginepb.TxnSeq) error
 
+	// StepReadTimestamp moves the read timestamp of a ReadCommitted
+	// transaction forward to the current time, establishing a new snapshot
+	// for subsequent reads. The reads performed before the step do not need
+	// to be refreshed anymore if the transaction's timestamp is pushed.
+	//
+	// StepReadTimestamp() is called at the start of every statement of a
+	// ReadCommitted transaction. It returns an error for other transactions.
+	StepReadTimestamp(context.Context) error
+
 	// ConfigureStepping sets the sequencing point behavior.
 	//
 	// Note that a Sender is initially in the non-stepping mode,
@@ -346,6 +363,34 @@ const (
 	SteppingEnabled SteppingMode = true
 )
 
+// IsolationLevel is the isolation level of a transaction.
+type IsolationLevel int
+
+const (
+	// Serializable is the default isolation level. All of the reads of a
+	// serializable transaction observe the same snapshot, and the transaction
+	// is restarted if its reads cannot be validated at its commit timestamp.
+	Serializable IsolationLevel = iota
+
+	// ReadCommitted transactions establish a new read snapshot for every
+	// statement, and only validate the reads of the current statement if
+	// their timestamp is pushed. Retryable errors do not restart a
+	// ReadCommitted transaction: they only require the current statement to
+	// be retried, after rolling back to a savepoint taken at its start.
+	ReadCommitted
+)
+
+func (l IsolationLevel) String() string {
+	switch l {
+	case Serializable:
+		return "SERIALIZABLE"
+	case ReadCommitted:
+		return "READ COMMITTED"
+	default:
+		return fmt.Sprintf("IsolationLevel(%d)", int(l))
+	}
+}
+
 // SavepointToken represents a savepoint.
 type SavepointToken interface {
 	// Initial returns true if this savepoint has been created before performing
@@ -70,6 +70,7 @@ type Txn struct {
 		ID           uuid.UUID
 		debugName    string
 		userPriority roachpb.UserPriority
+		isoLevel     IsolationLevel
 
 		// previousIDs holds the set of all previous IDs that the Txn's Proto has
 		// had across transaction aborts. This allows us to determine if a given
@@ -298,6 +299,33 @@ func (txn *Txn) SetUserPriority(userPriority roachpb.UserPriority) error {
 	return txn.mu.sender.SetUserPriority(userPriority)
 }
 
+// SetIsoLevel sets the transaction's isolation level. Transactions default to
+// Serializable isolation. The isolation level must be set before any
+// operations are performed on the transaction.
+func (txn *Txn) SetIsoLevel(isoLevel IsolationLevel) error {
+	if txn.typ != RootTxn {
+		panic(errors.AssertionFailedf("SetIsoLevel() called on leaf txn"))
+	}
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	if txn.mu.isoLevel == isoLevel {
+		return nil
+	}
+	if err := txn.mu.sender.SetIsoLevel(isoLevel); err != nil {
+		return err
+	}
+	txn.mu.isoLevel = isoLevel
+	return nil
+}
+
+// IsoLevel returns the transaction's isolation level.
+func (txn *Txn) IsoLevel() IsolationLevel {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.isoLevel
+}
+
 // TestingSetPriority sets the transaction priority. It is intended for
 // internal (testing) use only.
 func (txn *Txn) TestingSetPriority(priority enginepb.TxnPriority) {
@@ -1027,6 +1055,37 @@ func (txn *Txn) PrepareForRetry(ctx context.Context) {
 	txn.handleRetryableErrLocked(ctx, retryErr)
 }
 
+// PrepareForPartialRetry is like PrepareForRetry, but it is used by
+// ReadCommitted transactions which only need to retry the statement that
+// encountered a retryable error instead of the whole transaction. The caller
+// is expected to roll back to a savepoint taken at the start of the statement
+// and to step the read timestamp before retrying it. Unlike PrepareForRetry,
+// the transaction's deadline is preserved.
+//
+// An error is returned if the retryable error requires the transaction to be
+// restarted from the beginning.
+func (txn *Txn) PrepareForPartialRetry(ctx context.Context) error {
+	if txn.typ != RootTxn {
+		panic(errors.AssertionFailedf("PrepareForPartialRetry() called on leaf txn"))
+	}
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+
+	retryErr := txn.mu.sender.GetTxnRetryableErr(ctx)
+	if retryErr == nil {
+		return nil
+	}
+	if txn.mu.isoLevel != ReadCommitted || retryErr.PrevTxnAborted() {
+		return errors.NewAssertionErrorWithWrappedErrf(retryErr,
+			"PrepareForPartialRetry() called for a retryable error that requires a restart")
+	}
+	log.VEventf(ctx, 2, "partially retrying transaction: %s because of a retryable error: %s",
+		txn.debugNameLocked(), retryErr)
+	txn.mu.sender.ClearTxnRetryableErr(ctx)
+	return nil
+}
+
 // IsRetryableErrMeantForTxn returns true if err is a retryable
 // error meant to restart this client transaction.
 func (txn *Txn) IsRetryableErrMeantForTxn(
@@ -1398,6 +1457,14 @@ func (txn *Txn) replaceRootSenderIfTxnAbortedLocked(
 		// We don't need a new transaction as a result of this error, but we may
 		// have a retryable error that should be cleared.
 		txn.mu.sender.ClearTxnRetryableErr(ctx)
+		// ReadCommitted transactions don't start a new epoch when they encounter
+		// a retryable error, since they usually only retry the statement that
+		// encountered it (see PrepareForPartialRetry). Retrying the whole
+		// transaction requires its previous writes to be discarded, so the
+		// epoch is bumped here.
+		if txn.mu.isoLevel == ReadCommitted {
+			txn.mu.sender.ManualRestart(ctx, txn.mu.userPriority, newTxn.WriteTimestamp)
+		}
 		return
 	}
 
@@ -1412,6 +1479,9 @@ func (txn *Txn) replaceRootSenderIfTxnAbortedLocked(
 	prevSteppingMode := txn.mu.sender.GetSteppingMode(ctx)
 	txn.mu.sender = txn.db.factory.RootTransactionalSender(newTxn, txn.mu.userPriority)
 	txn.mu.sender.ConfigureStepping(ctx, prevSteppingMode)
+	if err := txn.mu.sender.SetIsoLevel(txn.mu.isoLevel); err != nil {
+		log.Fatalf(ctx, "failed to set the isolation level of a new transaction: %v", err)
+	}
 }
 
 func (txn *Txn) recordPreviousTxnIDLocked(prevTxnID uuid.UUID) {
@@ -1563,6 +1633,19 @@ func (txn *Txn) SetReadSeqNum(seq enginepb.TxnSeq) error {
 	return txn.mu.sender.SetReadSeqNum(seq)
 }
 
+// StepReadTimestamp moves the read timestamp of a ReadCommitted transaction
+// forward to the current time, establishing a new read snapshot. See
+// TxnSender.StepReadTimestamp.
+func (txn *Txn) StepReadTimestamp(ctx context.Context) error {
+	if txn.typ != RootTxn {
+		return errors.WithContextTags(
+			errors.AssertionFailedf("StepReadTimestamp() called on leaf txn"), ctx)
+	}
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.StepReadTimestamp(ctx)
+}
+
 // ConfigureStepping configures step-wise execution in the
 // transaction.
 func (txn *Txn) ConfigureStepping(ctx context.Context, mode SteppingMode) (prevMode SteppingMode) {
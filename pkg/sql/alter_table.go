@@ -192,6 +192,14 @@ func (n *alterTableNode) startExec(params runParams) error {
 				return pgerror.Newf(pgcode.InvalidColumnDefinition,
 					"multiple primary keys for table %q are not allowed", tn.Object())
 			}
+			if t.ColumnDef.Unique.Deferrability.IsDeferrable() {
+				return errors.WithHint(
+					unimplemented.NewWithIssue(31632,
+						"adding a column marked as DEFERRABLE UNIQUE is unsupported"),
+					"add the column first, then run ALTER TABLE ... ADD CONSTRAINT to add a "+
+						"DEFERRABLE UNIQUE WITHOUT INDEX constraint on the column",
+				)
+			}
 			var err error
 			params.p.runWithOptions(resolveFlags{contextDatabaseID: n.tableDesc.ParentID}, func() {
 				err = params.p.addColumnImpl(params, n, tn, n.tableDesc, t)
@@ -224,6 +232,17 @@ func (n *alterTableNode) startExec(params runParams) error {
 					continue
 				}
 
+				if d.Deferrability.IsDeferrable() {
+					// A unique index cannot postpone its checks until the end of the
+					// transaction (see checkDeferrableUniqueConstraints).
+					return errors.WithHint(
+						unimplemented.NewWithIssue(31632,
+							"deferrable unique constraints can only be added without an index"),
+						"use ALTER TABLE ... ADD CONSTRAINT ... UNIQUE WITHOUT INDEX ... DEFERRABLE, "+
+							"and create an index on the same columns separately",
+					)
+				}
+
 				if d.PrimaryKey {
 					// Translate this operation into an ALTER PRIMARY KEY command.
 					alterPK := &tree.AlterTableAlterPrimaryKey{
@@ -342,6 +361,31 @@ func (n *alterTableNode) startExec(params runParams) error {
 						return err
 					}
 				}
+
+			case *tree.ExclusionConstraintTableDef:
+				tableName, err := params.p.getQualifiedTableName(params.ctx, n.tableDesc)
+				if err != nil {
+					return err
+				}
+				idx, err := makeExclusionIndexDescriptor(
+					params.ctx, params.ExecCfg().Settings, n.tableDesc, d, tableName, params.p.SemaCtx(),
+				)
+				if err != nil {
+					return err
+				}
+				idx.CreatedAtNanos = params.EvalContext().GetTxnTimestamp(time.Microsecond).UnixNano()
+				// The index is validated to not contain any conflicting rows once it
+				// has been backfilled, see SchemaChanger.validateIndexes.
+				if err := n.tableDesc.AddIndexMutationMaybeWithTempIndex(
+					params.ctx, &idx, descpb.DescriptorMutation_ADD, params.p.ExecCfg().Settings,
+				); err != nil {
+					return err
+				}
+				version := params.ExecCfg().Settings.Version.ActiveVersion(params.ctx)
+				if err := n.tableDesc.AllocateIDs(params.ctx, version); err != nil {
+					return err
+				}
+
 			case *tree.CheckConstraintTableDef:
 				var err error
 				params.p.runWithOptions(resolveFlags{contextDatabaseID: n.tableDesc.ParentID}, func() {
@@ -819,11 +863,11 @@ func (n *alterTableNode) startExec(params runParams) error {
 				return pgerror.Newf(pgcode.DuplicateObject,
 					"duplicate constraint name: %q", tree.ErrString(&t.NewName))
 			}
-			// If this is a unique or primary constraint, renames of the constraint
+			// If this is a unique, primary or exclusion constraint, renames of the constraint
 			// lead to renames of the underlying index. Ensure that no index with this
 			// new name exists. This is what postgres does.
 			switch details.Kind {
-			case descpb.ConstraintTypeUnique, descpb.ConstraintTypePK:
+			case descpb.ConstraintTypeUnique, descpb.ConstraintTypePK, descpb.ConstraintTypeExclusion:
 				if catalog.FindNonDropIndex(n.tableDesc, func(idx catalog.Index) bool {
 					return idx.GetName() == string(t.NewName)
 				}) != nil {
@@ -1476,6 +1520,20 @@ func validateConstraintNameIsNotUsed(
 			return false, pgerror.Newf(pgcode.DuplicateObject, "constraint with name %q already exists and is being dropped, try again later", name)
 		}
 		return false, pgerror.Newf(pgcode.DuplicateObject, "constraint with name %q already exists", name)
+	case *tree.ExclusionConstraintTableDef:
+		name = d.Name
+		hasIfNotExists = d.IfNotExists
+		// The constraint is backed by an index with the same name, so the name
+		// must not be used by any other index either.
+		if name == "" {
+			return false, nil
+		}
+		if idx, _ := tableDesc.FindIndexWithName(string(name)); idx != nil {
+			if d.IfNotExists {
+				return true, nil
+			}
+			return false, pgerror.Newf(pgcode.DuplicateObject, "constraint with name %q already exists", name)
+		}
 
 	default:
 		return false, errors.AssertionFailedf(
@@ -1396,7 +1396,7 @@ func (sc *SchemaChanger) validateIndexes(ctx context.Context) error {
 		return err
 	}
 
-	var forwardIndexes, invertedIndexes []catalog.Index
+	var forwardIndexes, invertedIndexes, exclusionIndexes []catalog.Index
 
 	for _, m := range tableDesc.AllMutations() {
 		if sc.mutationID != m.MutationID() {
@@ -1407,6 +1407,9 @@ func (sc *SchemaChanger) validateIndexes(ctx context.Context) error {
 		if idx == nil || idx.Dropped() || idx.IsTemporaryIndexForBackfill() {
 			continue
 		}
+		if idx.IsExclusion() {
+			exclusionIndexes = append(exclusionIndexes, idx)
+		}
 		switch idx.GetType() {
 		case descpb.IndexDescriptor_FORWARD:
 			forwardIndexes = append(forwardIndexes, idx)
@@ -1451,6 +1454,17 @@ func (sc *SchemaChanger) validateIndexes(ctx context.Context) error {
 	if err := grp.Wait(); err != nil {
 		return err
 	}
+	// The rows of the table must not conflict with each other under the
+	// EXCLUDE constraints backed by the new indexes.
+	for _, idx := range exclusionIndexes {
+		if err := runHistoricalTxn(ctx, func(
+			ctx context.Context, txn *kv.Txn, ie sqlutil.InternalExecutor,
+		) error {
+			return validateExclusionConstraint(ctx, tableDesc, idx, ie, txn)
+		}); err != nil {
+			return err
+		}
+	}
 	log.Info(ctx, "finished validating new indexes")
 	return nil
 }
@@ -177,6 +177,57 @@ func indexForDisplay(
 	return f.CloseAndGetString(), nil
 }
 
+// ExclusionConstraintForDisplay formats the EXCLUDE constraint backed by an
+// index as a SQL string, without the constraint name. For example:
+//
+//   EXCLUDE USING GIST (a WITH =, b WITH &&) WHERE (c > 0)
+//
+func ExclusionConstraintForDisplay(
+	ctx context.Context,
+	table catalog.TableDescriptor,
+	index *descpb.IndexDescriptor,
+	formatFlags tree.FmtFlags,
+	semaCtx *tree.SemaContext,
+	sessionData *sessiondata.SessionData,
+) (string, error) {
+	f := tree.NewFmtCtx(formatFlags)
+	f.WriteString("EXCLUDE ")
+	if f.HasFlags(tree.FmtPGCatalog) {
+		if index.Type == descpb.IndexDescriptor_INVERTED {
+			f.WriteString("USING gist ")
+		} else {
+			f.WriteString("USING btree ")
+		}
+	} else if index.Type == descpb.IndexDescriptor_INVERTED {
+		f.WriteString("USING GIST ")
+	}
+	f.WriteByte('(')
+	for i := range index.KeyColumnNames {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		f.FormatNameP(&index.KeyColumnNames[i])
+		f.WriteString(" WITH ")
+		f.WriteString(index.ExclusionOperators[i])
+	}
+	f.WriteByte(')')
+
+	if index.IsPartial() {
+		predFmtFlag := tree.FmtParsable
+		if f.HasFlags(tree.FmtPGCatalog) {
+			predFmtFlag = tree.FmtPGCatalog
+		}
+		pred, err := schemaexpr.FormatExprForDisplay(ctx, table, index.Predicate, semaCtx, sessionData, predFmtFlag)
+		if err != nil {
+			return "", err
+		}
+		f.WriteString(" WHERE (")
+		f.WriteString(pred)
+		f.WriteByte(')')
+	}
+	return f.CloseAndGetString(), nil
+}
+
 // FormatIndexElements formats the key columns an index. If the column is an
 // inaccessible computed column, the computed column expression is formatted.
 // Otherwise, the column name is formatted. Each column is separated by commas
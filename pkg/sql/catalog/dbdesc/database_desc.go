@@ -78,6 +78,11 @@ func (desc *immutable) DescriptorType() catalog.DescriptorType {
 	return catalog.Database
 }
 
+// SkipNamespace implements the descriptor interface.
+func (desc *immutable) SkipNamespace() bool {
+	return false
+}
+
 // DatabaseDesc implements the Descriptor interface.
 func (desc *immutable) DatabaseDesc() *descpb.DatabaseDescriptor {
 	return &desc.DatabaseDescriptor
@@ -276,6 +281,27 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 	if desc.IsMultiRegion() {
 		desc.validateMultiRegion(vea)
 	}
+
+	desc.validatePublications(vea)
+}
+
+// validatePublications checks that the publications defined in the database
+// have valid and unique names. Table IDs are not validated: tables which are
+// dropped are simply no longer published.
+func (desc *immutable) validatePublications(vea catalog.ValidationErrorAccumulator) {
+	names := make(map[string]struct{}, len(desc.Publications))
+	for i := range desc.Publications {
+		pub := &desc.Publications[i]
+		vea.Report(catalog.ValidateName(pub.Name, "publication"))
+		if _, ok := names[pub.Name]; ok {
+			vea.Report(errors.AssertionFailedf("duplicate publication name %q", pub.Name))
+		}
+		names[pub.Name] = struct{}{}
+		if pub.AllTables && len(pub.TableIDs) > 0 {
+			vea.Report(errors.AssertionFailedf(
+				"publication %q is defined for all tables but lists table IDs", pub.Name))
+		}
+	}
 }
 
 // validateMultiRegion performs checks specific to multi-region DBs.
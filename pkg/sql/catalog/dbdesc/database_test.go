@@ -113,6 +113,18 @@ func TestValidateDatabaseDesc(t *testing.T) {
 				Privileges:   catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
 			},
 		},
+		{
+			`duplicate publication name "pub"`,
+			descpb.DatabaseDescriptor{
+				Name:       "db",
+				ID:         200,
+				Privileges: catpb.NewBaseDatabasePrivilegeDescriptor(username.RootUserName()),
+				Publications: []descpb.DatabaseDescriptor_Publication{
+					{Name: "pub", TableIDs: []descpb.ID{201}},
+					{Name: "pub", AllTables: true},
+				},
+			},
+		},
 	}
 	for i, d := range testData {
 		t.Run(d.err, func(t *testing.T) {
@@ -80,6 +80,8 @@ const (
 	ConstraintTypeUnique ConstraintType = "UNIQUE"
 	// ConstraintTypeCheck identifies a CHECK constraint.
 	ConstraintTypeCheck ConstraintType = "CHECK"
+	// ConstraintTypeExclusion identifies an EXCLUDE constraint.
+	ConstraintTypeExclusion ConstraintType = "EXCLUDE"
 )
 
 // ConstraintDetail describes a constraint.
@@ -93,7 +95,7 @@ type ConstraintDetail struct {
 	Details      string
 	Unvalidated  bool
 
-	// Only populated for PK and Unique Constraints with an index.
+	// Only populated for PK, Exclusion and Unique Constraints with an index.
 	Index *IndexDescriptor
 
 	// Only populated for Unique Constraints without an index.
@@ -111,7 +113,7 @@ type ConstraintDetail struct {
 // type.
 func (c *ConstraintDetail) GetConstraintName() string {
 	switch c.Kind {
-	case ConstraintTypePK:
+	case ConstraintTypePK, ConstraintTypeExclusion:
 		return c.Index.Name
 	case ConstraintTypeUnique:
 		if c.Index != nil {
@@ -125,3 +127,35 @@ func (c *ConstraintDetail) GetConstraintName() string {
 	}
 	return ""
 }
+
+// Deferrability returns the deferrability of the constraint. Only foreign
+// keys and unique constraints without an index can be deferrable.
+func (c *ConstraintDetail) Deferrability() tree.ConstraintDeferrability {
+	switch {
+	case c.FK != nil:
+		return c.FK.Deferrability()
+	case c.UniqueWithoutIndexConstraint != nil:
+		return c.UniqueWithoutIndexConstraint.Deferrability()
+	}
+	return tree.ConstraintNotDeferrable
+}
+
+// Deferrability returns the deferrability of the foreign key constraint.
+func (f *ForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return makeConstraintDeferrability(f.Deferrable, f.InitiallyDeferred)
+}
+
+// Deferrability returns the deferrability of the unique constraint.
+func (u *UniqueWithoutIndexConstraint) Deferrability() tree.ConstraintDeferrability {
+	return makeConstraintDeferrability(u.Deferrable, u.InitiallyDeferred)
+}
+
+func makeConstraintDeferrability(deferrable, initiallyDeferred bool) tree.ConstraintDeferrability {
+	switch {
+	case initiallyDeferred:
+		return tree.ConstraintInitiallyDeferred
+	case deferrable:
+		return tree.ConstraintDeferrable
+	}
+	return tree.ConstraintNotDeferrable
+}
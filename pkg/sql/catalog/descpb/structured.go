@@ -77,6 +77,12 @@ type IndexID = catid.IndexID
 // ConstraintID is a custom type for TableDescriptor constraint IDs.
 type ConstraintID = catid.ConstraintID
 
+// TriggerID is a custom type for TableDescriptor trigger IDs.
+type TriggerID uint32
+
+// SafeValue implements the redact.SafeValue interface.
+func (TriggerID) SafeValue() {}
+
 // DescriptorVersion is a custom type for TableDescriptor Versions.
 type DescriptorVersion uint64
 
@@ -278,6 +284,46 @@ func (desc *TableDescriptor) Persistence() tree.Persistence {
 	return tree.PersistencePermanent
 }
 
+// TriggerActionTimeType allows the conversion between a
+// TableDescriptor_Trigger_ActionTime and a tree.TriggerActionTime.
+var TriggerActionTimeType = [...]tree.TriggerActionTime{
+	TableDescriptor_Trigger_BEFORE: tree.TriggerActionTimeBefore,
+	TableDescriptor_Trigger_AFTER:  tree.TriggerActionTimeAfter,
+}
+
+// TriggerActionTimeValue allows the conversion between a
+// tree.TriggerActionTime and a TableDescriptor_Trigger_ActionTime.
+var TriggerActionTimeValue = [...]TableDescriptor_Trigger_ActionTime{
+	tree.TriggerActionTimeBefore: TableDescriptor_Trigger_BEFORE,
+	tree.TriggerActionTimeAfter:  TableDescriptor_Trigger_AFTER,
+}
+
+// TriggerEventType allows the conversion between a
+// TableDescriptor_Trigger_Event and a tree.TriggerEvent.
+var TriggerEventType = [...]tree.TriggerEvent{
+	TableDescriptor_Trigger_INSERT: tree.TriggerEventInsert,
+	TableDescriptor_Trigger_UPDATE: tree.TriggerEventUpdate,
+	TableDescriptor_Trigger_DELETE: tree.TriggerEventDelete,
+}
+
+// TriggerEventValue allows the conversion between a tree.TriggerEvent and a
+// TableDescriptor_Trigger_Event.
+var TriggerEventValue = [...]TableDescriptor_Trigger_Event{
+	tree.TriggerEventInsert: TableDescriptor_Trigger_INSERT,
+	tree.TriggerEventUpdate: TableDescriptor_Trigger_UPDATE,
+	tree.TriggerEventDelete: TableDescriptor_Trigger_DELETE,
+}
+
+// HasEvent returns true if the trigger fires on the given event.
+func (t *TableDescriptor_Trigger) HasEvent(event TableDescriptor_Trigger_Event) bool {
+	for _, e := range t.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
 // ForEachPublicIndex is exported to provide low-overhead access to the set of
 // public indexes in a table descriptor for use in backup planning.
 //
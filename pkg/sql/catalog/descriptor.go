@@ -45,6 +45,9 @@ const (
 
 	// Schema is for schema descriptors.
 	Schema = "schema"
+
+	// Function is for function descriptors.
+	Function = "function"
 )
 
 // MutationPublicationFilter is used by MakeFirstMutationPublic to filter the
@@ -168,6 +171,9 @@ type Descriptor interface {
 	// DescriptorType returns the type of this descriptor (like relation, type,
 	// schema, database).
 	DescriptorType() DescriptorType
+	// SkipNamespace is true when a descriptor should not have a namespace
+	// record, as is the case for function descriptors.
+	SkipNamespace() bool
 	// GetAuditMode returns the audit mode for this descriptor. The audit mode
 	// describes what kind of auditing (logging) actions should be taken when
 	// this descriptor is modified.
@@ -666,6 +672,12 @@ type TableDescriptor interface {
 	// referenced by the returned checks are writable, but not necessarily public.
 	ActiveChecks() []descpb.TableDescriptor_CheckConstraint
 
+	// GetTriggers returns the row-level triggers defined on the table, sorted
+	// by name.
+	GetTriggers() []descpb.TableDescriptor_Trigger
+	// FindTriggerByName returns the trigger with the given name, if it exists.
+	FindTriggerByName(name string) (*descpb.TableDescriptor_Trigger, bool)
+
 	// GetLocalityConfig returns the locality config for this table, which
 	// describes the table's multi-region locality policy if one is set (e.g.
 	// GLOBAL or REGIONAL BY ROW).
@@ -809,6 +821,40 @@ type TypeDescriptor interface {
 	GetReferencingDescriptorID(refOrdinal int) descpb.ID
 }
 
+// FunctionDescriptor is an interface around the function descriptor types.
+type FunctionDescriptor interface {
+	Descriptor
+
+	// FuncDesc returns the backing protobuf for this function.
+	FuncDesc() *descpb.FunctionDescriptor
+
+	// GetReturnType returns the return type of the function.
+	GetReturnType() descpb.FunctionDescriptor_ReturnType
+
+	// GetFunctionBody returns the SQL body of the function.
+	GetFunctionBody() string
+
+	// IsStrict returns true if the function returns NULL when any of its
+	// arguments is NULL instead of being evaluated.
+	IsStrict() bool
+
+	// ArgTypes returns the types of the input parameters of the function, in
+	// order. OUT parameters are not included.
+	ArgTypes() []*types.T
+
+	// ReturnsTrigger returns true if the function was declared to return the
+	// TRIGGER pseudo-type, and can only be executed by triggers.
+	ReturnsTrigger() bool
+
+	// IsAggregate returns true if the function is a user-defined aggregate
+	// created with CREATE AGGREGATE.
+	IsAggregate() bool
+
+	// GetDependedOnBy returns the IDs of the tables with triggers which
+	// execute the function.
+	GetDependedOnBy() []descpb.ID
+}
+
 // TypeDescriptorResolver is an interface used during hydration of type
 // metadata in types.T's. It is similar to tree.TypeReferenceResolver, except
 // that it has the power to return TypeDescriptor, rather than only a
@@ -0,0 +1,69 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package descs
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// GetMutableFunctionByID returns a mutable function descriptor with
+// properties according to the provided lookup flags. RequireMutable is
+// ignored. Required is ignored, and an error is always returned if no
+// descriptor with the ID exists.
+func (tc *Collection) GetMutableFunctionByID(
+	ctx context.Context, txn *kv.Txn, funcID descpb.ID, flags tree.ObjectLookupFlags,
+) (*funcdesc.Mutable, error) {
+	flags.RequireMutable = true
+	desc, err := tc.getFunctionByID(ctx, txn, funcID, flags)
+	if err != nil {
+		return nil, err
+	}
+	return desc.(*funcdesc.Mutable), nil
+}
+
+// GetImmutableFunctionByID returns an immutable function descriptor with
+// properties according to the provided lookup flags. RequireMutable is
+// ignored. Required is ignored, and an error is always returned if no
+// descriptor with the ID exists.
+func (tc *Collection) GetImmutableFunctionByID(
+	ctx context.Context, txn *kv.Txn, funcID descpb.ID, flags tree.ObjectLookupFlags,
+) (catalog.FunctionDescriptor, error) {
+	flags.RequireMutable = false
+	return tc.getFunctionByID(ctx, txn, funcID, flags)
+}
+
+func (tc *Collection) getFunctionByID(
+	ctx context.Context, txn *kv.Txn, funcID descpb.ID, flags tree.ObjectLookupFlags,
+) (catalog.FunctionDescriptor, error) {
+	descs, err := tc.getDescriptorsByID(ctx, txn, flags.CommonLookupFlags, funcID)
+	if err != nil {
+		if errors.Is(err, catalog.ErrDescriptorNotFound) {
+			return nil, pgerror.Newf(
+				pgcode.UndefinedFunction, "function with ID %d does not exist", funcID)
+		}
+		return nil, err
+	}
+	fn, ok := descs[0].(catalog.FunctionDescriptor)
+	if !ok {
+		return nil, pgerror.Newf(
+			pgcode.UndefinedFunction, "function with ID %d does not exist", funcID)
+	}
+	return fn, nil
+}
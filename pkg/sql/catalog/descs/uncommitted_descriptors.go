@@ -87,6 +87,12 @@ func (u uncommittedDescriptor) GetID() descpb.ID {
 	return u.immutable.GetID()
 }
 
+// SkipNamespace is used by nstree.Map to avoid indexing descriptors which
+// have no namespace record by name.
+func (u uncommittedDescriptor) SkipNamespace() bool {
+	return u.immutable.SkipNamespace()
+}
+
 // checkOut is how the mutable descriptor should be accessed.
 func (u *uncommittedDescriptor) checkOut() catalog.MutableDescriptor {
 	if u.mutable == nil {
@@ -0,0 +1,683 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package funcdesc contains the concrete implementations of
+// catalog.FunctionDescriptor.
+package funcdesc
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+)
+
+var _ catalog.FunctionDescriptor = (*immutable)(nil)
+var _ catalog.FunctionDescriptor = (*Mutable)(nil)
+var _ catalog.MutableDescriptor = (*Mutable)(nil)
+
+// immutable wraps a Function descriptor and provides methods on it.
+type immutable struct {
+	descpb.FunctionDescriptor
+
+	// isUncommittedVersion is set to true if this descriptor was created from
+	// a copy of a Mutable with an uncommitted version.
+	isUncommittedVersion bool
+
+	// changes represents how the descriptor was changed after
+	// RunPostDeserializationChanges.
+	changes catalog.PostDeserializationChanges
+}
+
+// Mutable is a mutable reference to a FunctionDescriptor.
+type Mutable struct {
+	immutable
+
+	// ClusterVersion represents the version of the function descriptor read
+	// from the store.
+	ClusterVersion *immutable
+}
+
+// NewMutableFunctionDescriptor returns a Mutable for a new function with the
+// given name and signature, owned by the given user.
+func NewMutableFunctionDescriptor(
+	id descpb.ID,
+	parentID descpb.ID,
+	parentSchemaID descpb.ID,
+	name string,
+	params []descpb.FunctionDescriptor_Param,
+	returnType *types.T,
+	returnSet bool,
+	privs *catpb.PrivilegeDescriptor,
+) Mutable {
+	return Mutable{
+		immutable: immutable{
+			FunctionDescriptor: descpb.FunctionDescriptor{
+				Name:           name,
+				ID:             id,
+				ParentID:       parentID,
+				ParentSchemaID: parentSchemaID,
+				Params:         params,
+				ReturnType: descpb.FunctionDescriptor_ReturnType{
+					Type:      returnType,
+					ReturnSet: returnSet,
+				},
+				Lang:              descpb.FunctionDescriptor_SQL,
+				Volatility:        descpb.FunctionDescriptor_VOLATILE,
+				LeakProof:         false,
+				NullInputBehavior: descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT,
+				Privileges:        privs,
+				Version:           1,
+				ModificationTime:  hlc.Timestamp{},
+			},
+		},
+	}
+}
+
+// SafeMessage makes immutable a SafeMessager.
+func (desc *immutable) SafeMessage() string {
+	return formatSafeMessage("funcdesc.immutable", desc)
+}
+
+// SafeMessage makes Mutable a SafeMessager.
+func (desc *Mutable) SafeMessage() string {
+	return formatSafeMessage("funcdesc.Mutable", desc)
+}
+
+func formatSafeMessage(typeName string, desc catalog.FunctionDescriptor) string {
+	var buf redact.StringBuilder
+	buf.Printf(typeName + ": {")
+	catalog.FormatSafeDescriptorProperties(&buf, desc)
+	buf.Printf("}")
+	return buf.String()
+}
+
+var _ redact.SafeMessager = (*immutable)(nil)
+
+// GetDrainingNames implements the Descriptor interface. Functions are not
+// named in the namespace table and thus never have draining names.
+//
+// Deprecated: Do not use.
+func (desc *immutable) GetDrainingNames() []descpb.NameInfo {
+	return nil
+}
+
+// IsUncommittedVersion implements the Descriptor interface.
+func (desc *immutable) IsUncommittedVersion() bool {
+	return desc.isUncommittedVersion
+}
+
+// DescriptorType implements the Descriptor interface.
+func (desc *immutable) DescriptorType() catalog.DescriptorType {
+	return catalog.Function
+}
+
+// SkipNamespace implements the Descriptor interface. Functions are looked up
+// through the functions mapping of their parent schema rather than through
+// system.namespace.
+func (desc *immutable) SkipNamespace() bool {
+	return true
+}
+
+// GetAuditMode implements the Descriptor interface.
+func (desc *immutable) GetAuditMode() descpb.TableDescriptor_AuditMode {
+	return descpb.TableDescriptor_DISABLED
+}
+
+// Public implements the Descriptor interface.
+func (desc *immutable) Public() bool {
+	return desc.State == descpb.DescriptorState_PUBLIC
+}
+
+// Adding implements the Descriptor interface.
+func (desc *immutable) Adding() bool {
+	return false
+}
+
+// Dropped implements the Descriptor interface.
+func (desc *immutable) Dropped() bool {
+	return desc.State == descpb.DescriptorState_DROP
+}
+
+// Offline implements the Descriptor interface.
+func (desc *immutable) Offline() bool {
+	return desc.State == descpb.DescriptorState_OFFLINE
+}
+
+// DescriptorProto implements the Descriptor interface.
+func (desc *immutable) DescriptorProto() *descpb.Descriptor {
+	return &descpb.Descriptor{
+		Union: &descpb.Descriptor_Function{
+			Function: &desc.FunctionDescriptor,
+		},
+	}
+}
+
+// ByteSize implements the Descriptor interface.
+func (desc *immutable) ByteSize() int64 {
+	return int64(desc.Size())
+}
+
+// NewBuilder implements the Descriptor interface.
+func (desc *immutable) NewBuilder() catalog.DescriptorBuilder {
+	return newBuilder(&desc.FunctionDescriptor, desc.IsUncommittedVersion(), desc.changes)
+}
+
+// NewBuilder implements the Descriptor interface.
+//
+// It overrides the wrapper's implementation to deal with the fact that
+// mutable has overridden the definition of IsUncommittedVersion.
+func (desc *Mutable) NewBuilder() catalog.DescriptorBuilder {
+	return newBuilder(&desc.FunctionDescriptor, desc.IsUncommittedVersion(), desc.changes)
+}
+
+// GetReferencedDescIDs implements the Descriptor interface.
+func (desc *immutable) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
+	// The relations in DependsOn are deliberately left out: they do not hold
+	// back-references to the function and may be dropped independently of it.
+	// The tables in DependedOnBy, on the other hand, reference the function in
+	// their triggers. Aggregates reference their support functions, which hold
+	// back-references to them.
+	ids := catalog.MakeDescriptorIDSet(desc.GetID(), desc.GetParentID(), desc.GetParentSchemaID())
+	for _, id := range desc.DependedOnBy {
+		ids.Add(id)
+	}
+	for _, id := range desc.DependedOnByAggregates {
+		ids.Add(id)
+	}
+	if agg := desc.Aggregate; agg != nil {
+		ids.Add(agg.StateFuncID)
+		if agg.FinalFuncID != descpb.InvalidID {
+			ids.Add(agg.FinalFuncID)
+		}
+	}
+	return ids, nil
+}
+
+// ValidateSelf implements the Descriptor interface.
+func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
+	vea.Report(catalog.ValidateName(desc.GetName(), "function"))
+	if desc.GetID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid ID %d", desc.GetID()))
+	}
+	if desc.GetParentID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid parentID %d", desc.GetParentID()))
+	}
+	if desc.GetParentSchemaID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid parentSchemaID %d", desc.GetParentSchemaID()))
+	}
+
+	if desc.Privileges == nil {
+		vea.Report(errors.AssertionFailedf("privileges not set"))
+	} else {
+		vea.Report(catprivilege.Validate(*desc.Privileges, desc, privilege.Function))
+	}
+
+	if desc.ReturnType.Type == nil {
+		vea.Report(errors.AssertionFailedf("return type not set"))
+	}
+	for i, p := range desc.Params {
+		if p.Type == nil {
+			vea.Report(errors.AssertionFailedf("type not set for parameter %d", i))
+		}
+	}
+	if desc.LeakProof && desc.Volatility != descpb.FunctionDescriptor_IMMUTABLE {
+		vea.Report(errors.AssertionFailedf(
+			"leakproof is set for a non-immutable function with volatility %s", desc.Volatility))
+	}
+	if agg := desc.Aggregate; agg != nil {
+		if agg.StateFuncID == descpb.InvalidID {
+			vea.Report(errors.AssertionFailedf("aggregate state function not set"))
+		}
+		if agg.StateType == nil {
+			vea.Report(errors.AssertionFailedf("aggregate state type not set"))
+		}
+		if len(desc.FunctionBody) != 0 {
+			vea.Report(errors.AssertionFailedf("aggregate has a function body"))
+		}
+	} else if len(desc.FunctionBody) == 0 {
+		vea.Report(errors.AssertionFailedf("function body is empty"))
+	}
+}
+
+// ValidateCrossReferences implements the Descriptor interface.
+func (desc *immutable) ValidateCrossReferences(
+	vea catalog.ValidationErrorAccumulator, vdg catalog.ValidationDescGetter,
+) {
+	// Check that parent database exists.
+	dbDesc, err := vdg.GetDatabaseDescriptor(desc.GetParentID())
+	if err != nil {
+		vea.Report(err)
+	} else if dbDesc.Dropped() {
+		vea.Report(errors.AssertionFailedf("parent database %q (%d) is dropped",
+			dbDesc.GetName(), dbDesc.GetID()))
+	}
+
+	// Check that parent schema exists and, unless the function is being
+	// dropped, that it is present in the schema's functions mapping.
+	scDesc, err := vdg.GetSchemaDescriptor(desc.GetParentSchemaID())
+	if err != nil {
+		vea.Report(err)
+		return
+	}
+	if scDesc.Dropped() {
+		vea.Report(errors.AssertionFailedf("parent schema %q (%d) is dropped",
+			scDesc.GetName(), scDesc.GetID()))
+	}
+	if desc.Dropped() {
+		return
+	}
+	fn, _ := scDesc.GetFunction(desc.GetName())
+	found := false
+	for _, overload := range fn.Overloads {
+		if overload.ID == desc.GetID() {
+			found = true
+			break
+		}
+	}
+	if !found {
+		vea.Report(errors.AssertionFailedf("not present in parent schema [%d] functions mapping",
+			desc.GetParentSchemaID()))
+	}
+
+	// Check that the tables depending on this function have a trigger which
+	// references it.
+	for _, id := range desc.DependedOnBy {
+		vea.Report(desc.validateInboundTableRef(id, vdg))
+	}
+
+	// Check that the support functions of an aggregate exist and reference it,
+	// and that the aggregates depending on this function use it.
+	if agg := desc.Aggregate; agg != nil {
+		vea.Report(desc.validateOutboundSupportFuncRef(agg.StateFuncID, vdg))
+		if agg.FinalFuncID != descpb.InvalidID {
+			vea.Report(desc.validateOutboundSupportFuncRef(agg.FinalFuncID, vdg))
+		}
+	}
+	for _, id := range desc.DependedOnByAggregates {
+		vea.Report(desc.validateInboundAggregateRef(id, vdg))
+	}
+}
+
+func (desc *immutable) validateOutboundSupportFuncRef(
+	id descpb.ID, vdg catalog.ValidationDescGetter,
+) error {
+	fn, err := vdg.GetFunctionDescriptor(id)
+	if err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err, "invalid aggregate support function reference")
+	}
+	if fn.Dropped() {
+		return errors.AssertionFailedf("aggregate support function %q (%d) is dropped",
+			fn.GetName(), fn.GetID())
+	}
+	for _, aggID := range fn.FuncDesc().DependedOnByAggregates {
+		if aggID == desc.GetID() {
+			return nil
+		}
+	}
+	return errors.AssertionFailedf("aggregate support function %q (%d) has no back-reference to this aggregate",
+		fn.GetName(), fn.GetID())
+}
+
+func (desc *immutable) validateInboundAggregateRef(
+	id descpb.ID, vdg catalog.ValidationDescGetter,
+) error {
+	agg, err := vdg.GetFunctionDescriptor(id)
+	if err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err, "invalid depended-on-by aggregate back reference")
+	}
+	if agg.Dropped() {
+		return errors.AssertionFailedf("depended-on-by aggregate %q (%d) is dropped",
+			agg.GetName(), agg.GetID())
+	}
+	if a := agg.FuncDesc().Aggregate; a != nil && (a.StateFuncID == desc.GetID() || a.FinalFuncID == desc.GetID()) {
+		return nil
+	}
+	return errors.AssertionFailedf("depended-on-by aggregate %q (%d) does not use this function",
+		agg.GetName(), agg.GetID())
+}
+
+func (desc *immutable) validateInboundTableRef(
+	id descpb.ID, vdg catalog.ValidationDescGetter,
+) error {
+	tbl, err := vdg.GetTableDescriptor(id)
+	if err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err, "invalid depended-on-by relation back reference")
+	}
+	if tbl.Dropped() {
+		return errors.AssertionFailedf("depended-on-by relation %q (%d) is dropped",
+			tbl.GetName(), tbl.GetID())
+	}
+	for _, trigger := range tbl.GetTriggers() {
+		if trigger.FuncID == desc.GetID() {
+			return nil
+		}
+	}
+	return errors.AssertionFailedf("depended-on-by relation %q (%d) has no trigger referencing this function",
+		tbl.GetName(), tbl.GetID())
+}
+
+// ValidateTxnCommit implements the Descriptor interface.
+func (desc *immutable) ValidateTxnCommit(
+	_ catalog.ValidationErrorAccumulator, _ catalog.ValidationDescGetter,
+) {
+	// No-op.
+}
+
+// GetPostDeserializationChanges implements the Descriptor interface.
+func (desc *immutable) GetPostDeserializationChanges() catalog.PostDeserializationChanges {
+	return desc.changes
+}
+
+// HasConcurrentSchemaChanges implements the Descriptor interface.
+func (desc *immutable) HasConcurrentSchemaChanges() bool {
+	return desc.DeclarativeSchemaChangerState != nil &&
+		desc.DeclarativeSchemaChangerState.JobID != catpb.InvalidJobID
+}
+
+// FuncDesc implements the FunctionDescriptor interface.
+func (desc *immutable) FuncDesc() *descpb.FunctionDescriptor {
+	return &desc.FunctionDescriptor
+}
+
+// IsStrict implements the FunctionDescriptor interface.
+func (desc *immutable) IsStrict() bool {
+	switch desc.NullInputBehavior {
+	case descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT, descpb.FunctionDescriptor_STRICT:
+		return true
+	}
+	return false
+}
+
+// ArgTypes implements the FunctionDescriptor interface.
+func (desc *immutable) ArgTypes() []*types.T {
+	ret := make([]*types.T, 0, len(desc.Params))
+	for _, p := range desc.Params {
+		if p.Class != descpb.FunctionDescriptor_Param_OUT {
+			ret = append(ret, p.Type)
+		}
+	}
+	return ret
+}
+
+// ReturnsTrigger implements the FunctionDescriptor interface.
+func (desc *immutable) ReturnsTrigger() bool {
+	return desc.ReturnType.Trigger
+}
+
+// IsAggregate implements the FunctionDescriptor interface.
+func (desc *immutable) IsAggregate() bool {
+	return desc.Aggregate != nil
+}
+
+// MaybeIncrementVersion implements the MutableDescriptor interface.
+func (desc *Mutable) MaybeIncrementVersion() {
+	// Already incremented, no-op.
+	if desc.ClusterVersion == nil || desc.Version == desc.ClusterVersion.Version+1 {
+		return
+	}
+	desc.Version++
+	desc.ModificationTime = hlc.Timestamp{}
+}
+
+// SetDrainingNames implements the MutableDescriptor interface.
+//
+// Deprecated: Do not use.
+func (desc *Mutable) SetDrainingNames(names []descpb.NameInfo) {}
+
+// AddDrainingName implements the MutableDescriptor interface.
+//
+// Deprecated: Do not use.
+func (desc *Mutable) AddDrainingName(name descpb.NameInfo) {}
+
+// OriginalName implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalName() string {
+	if desc.ClusterVersion == nil {
+		return ""
+	}
+	return desc.ClusterVersion.Name
+}
+
+// OriginalID implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalID() descpb.ID {
+	if desc.ClusterVersion == nil {
+		return descpb.InvalidID
+	}
+	return desc.ClusterVersion.ID
+}
+
+// OriginalVersion implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalVersion() descpb.DescriptorVersion {
+	if desc.ClusterVersion == nil {
+		return 0
+	}
+	return desc.ClusterVersion.Version
+}
+
+// ImmutableCopy implements the MutableDescriptor interface.
+func (desc *Mutable) ImmutableCopy() catalog.Descriptor {
+	return desc.NewBuilder().BuildImmutable()
+}
+
+// IsNew implements the MutableDescriptor interface.
+func (desc *Mutable) IsNew() bool {
+	return desc.ClusterVersion == nil
+}
+
+// SetPublic implements the MutableDescriptor interface.
+func (desc *Mutable) SetPublic() {
+	desc.State = descpb.DescriptorState_PUBLIC
+	desc.OfflineReason = ""
+}
+
+// SetDropped implements the MutableDescriptor interface.
+func (desc *Mutable) SetDropped() {
+	desc.State = descpb.DescriptorState_DROP
+	desc.OfflineReason = ""
+}
+
+// SetOffline implements the MutableDescriptor interface.
+func (desc *Mutable) SetOffline(reason string) {
+	desc.State = descpb.DescriptorState_OFFLINE
+	desc.OfflineReason = reason
+}
+
+// SetDeclarativeSchemaChangerState implements the MutableDescriptor interface.
+func (desc *Mutable) SetDeclarativeSchemaChangerState(state *scpb.DescriptorState) {
+	desc.DeclarativeSchemaChangerState = state
+}
+
+// IsUncommittedVersion implements the Descriptor interface.
+func (desc *Mutable) IsUncommittedVersion() bool {
+	return desc.IsNew() || desc.GetVersion() != desc.ClusterVersion.GetVersion()
+}
+
+// SetVolatility sets the volatility attribute.
+func (desc *Mutable) SetVolatility(v descpb.FunctionDescriptor_Volatility) {
+	desc.Volatility = v
+}
+
+// SetLeakProof sets the leakproof attribute.
+func (desc *Mutable) SetLeakProof(v bool) {
+	desc.LeakProof = v
+}
+
+// SetNullInputBehavior sets the NullInputBehavior attribute.
+func (desc *Mutable) SetNullInputBehavior(v descpb.FunctionDescriptor_NullInputBehavior) {
+	desc.NullInputBehavior = v
+}
+
+// SetLang sets the function language.
+func (desc *Mutable) SetLang(v descpb.FunctionDescriptor_Language) {
+	desc.Lang = v
+}
+
+// SetFuncBody sets the function body.
+func (desc *Mutable) SetFuncBody(v string) {
+	desc.FunctionBody = v
+}
+
+// SetDependsOn sets the IDs of the relations referenced by the function body.
+func (desc *Mutable) SetDependsOn(ids []descpb.ID) {
+	desc.DependsOn = ids
+}
+
+// SetParams sets the function parameters.
+func (desc *Mutable) SetParams(params []descpb.FunctionDescriptor_Param) {
+	desc.Params = params
+}
+
+// SetReturnType sets the function return type.
+func (desc *Mutable) SetReturnType(returnType *types.T, returnSet bool) {
+	desc.ReturnType = descpb.FunctionDescriptor_ReturnType{Type: returnType, ReturnSet: returnSet}
+}
+
+// SetReturnsTrigger marks the function as a trigger function. Trigger
+// functions have no result type of their own and can only be executed by
+// triggers.
+func (desc *Mutable) SetReturnsTrigger() {
+	desc.ReturnType = descpb.FunctionDescriptor_ReturnType{Type: types.Void, Trigger: true}
+}
+
+// AddDependedOnBy adds a back-reference to a table with a trigger which
+// executes the function.
+func (desc *Mutable) AddDependedOnBy(id descpb.ID) {
+	for _, existing := range desc.DependedOnBy {
+		if existing == id {
+			return
+		}
+	}
+	desc.DependedOnBy = append(desc.DependedOnBy, id)
+}
+
+// RemoveDependedOnBy removes the back-reference to the given table.
+func (desc *Mutable) RemoveDependedOnBy(id descpb.ID) {
+	for i, existing := range desc.DependedOnBy {
+		if existing == id {
+			desc.DependedOnBy = append(desc.DependedOnBy[:i], desc.DependedOnBy[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetAggregate marks the function as a user-defined aggregate with the given
+// support functions, state type and initial condition.
+func (desc *Mutable) SetAggregate(agg descpb.FunctionDescriptor_Aggregate) {
+	desc.Aggregate = &agg
+}
+
+// AddDependedOnByAggregate adds a back-reference to an aggregate which uses
+// the function as its state transition or final function.
+func (desc *Mutable) AddDependedOnByAggregate(id descpb.ID) {
+	for _, existing := range desc.DependedOnByAggregates {
+		if existing == id {
+			return
+		}
+	}
+	desc.DependedOnByAggregates = append(desc.DependedOnByAggregates, id)
+}
+
+// RemoveDependedOnByAggregate removes the back-reference to the given
+// aggregate.
+func (desc *Mutable) RemoveDependedOnByAggregate(id descpb.ID) {
+	for i, existing := range desc.DependedOnByAggregates {
+		if existing == id {
+			desc.DependedOnByAggregates = append(desc.DependedOnByAggregates[:i], desc.DependedOnByAggregates[i+1:]...)
+			return
+		}
+	}
+}
+
+// ToOverload converts the function descriptor into a tree.Overload which
+// can be used during function resolution and planning.
+func ToOverload(desc catalog.FunctionDescriptor) *tree.Overload {
+	fd := desc.FuncDesc()
+	var argTypes tree.ArgTypes
+	var paramNames []string
+	for _, p := range fd.Params {
+		if p.Class == descpb.FunctionDescriptor_Param_OUT {
+			continue
+		}
+		argTypes = append(argTypes, tree.ArgTypes{{Name: p.Name, Typ: p.Type}}...)
+		paramNames = append(paramNames, p.Name)
+	}
+	ret := &tree.Overload{
+		Types:         argTypes,
+		ReturnType:    tree.FixedReturnType(fd.ReturnType.Type),
+		Volatility:    VolatilityToV(fd.Volatility, fd.LeakProof),
+		IsUDF:         true,
+		UDFBody:       fd.FunctionBody,
+		UDFParamNames: paramNames,
+		UDFStrict:     desc.IsStrict(),
+		UDFReturnsSet: fd.ReturnType.ReturnSet,
+		UDFTrigger:    fd.ReturnType.Trigger,
+		Oid:           catid.FuncIDToOID(desc.GetID()),
+	}
+	return ret
+}
+
+// VolatilityToV converts a function volatility to a volatility.V.
+func VolatilityToV(v descpb.FunctionDescriptor_Volatility, leakProof bool) volatility.V {
+	switch v {
+	case descpb.FunctionDescriptor_IMMUTABLE:
+		if leakProof {
+			return volatility.LeakProof
+		}
+		return volatility.Immutable
+	case descpb.FunctionDescriptor_STABLE:
+		return volatility.Stable
+	default:
+		return volatility.Volatile
+	}
+}
+
+// ToAggregateOverload converts the descriptor of a user-defined aggregate into
+// a tree.Overload, given the descriptors of its state transition function and
+// of its final function, which may be nil.
+func ToAggregateOverload(
+	desc, stateFunc, finalFunc catalog.FunctionDescriptor,
+) *tree.Overload {
+	fd := desc.FuncDesc()
+	agg := fd.Aggregate
+	var argTypes tree.ArgTypes
+	for _, p := range fd.Params {
+		argTypes = append(argTypes, tree.ArgTypes{{Name: p.Name, Typ: p.Type}}...)
+	}
+	udfAgg := &tree.UDFAggregate{
+		StateType: agg.StateType,
+		InitCond:  agg.InitCond,
+		StateFunc: ToOverload(stateFunc),
+	}
+	// The aggregate is as volatile as the most volatile of its support
+	// functions.
+	vol := udfAgg.StateFunc.Volatility
+	if finalFunc != nil {
+		udfAgg.FinalFunc = ToOverload(finalFunc)
+		if udfAgg.FinalFunc.Volatility > vol {
+			vol = udfAgg.FinalFunc.Volatility
+		}
+	}
+	return &tree.Overload{
+		Types:        argTypes,
+		ReturnType:   tree.FixedReturnType(fd.ReturnType.Type),
+		Volatility:   vol,
+		UDFAggregate: udfAgg,
+		Oid:          catid.FuncIDToOID(desc.GetID()),
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package funcdesc_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/nstree"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateFuncDesc(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+
+	const (
+		dbID     = 51
+		schemaID = 52
+		funcID   = 53
+	)
+	privs := catpb.NewBasePrivilegeDescriptor(username.AdminRoleName())
+	makeDB := func() catalog.Descriptor {
+		return dbdesc.NewBuilder(&descpb.DatabaseDescriptor{
+			Name:       "db",
+			ID:         dbID,
+			Schemas:    map[string]descpb.DatabaseDescriptor_SchemaInfo{"schema": {ID: schemaID}},
+			Privileges: privs,
+		}).BuildImmutable()
+	}
+	makeSchema := func(fnName string) catalog.Descriptor {
+		sc := descpb.SchemaDescriptor{
+			Name:       "schema",
+			ID:         schemaID,
+			ParentID:   dbID,
+			Privileges: privs,
+		}
+		if fnName != "" {
+			sc.Functions = map[string]descpb.SchemaDescriptor_Function{
+				fnName: {
+					Name:      fnName,
+					Overloads: []descpb.SchemaDescriptor_FunctionOverload{{ID: funcID}},
+				},
+			}
+		}
+		return schemadesc.NewBuilder(&sc).BuildImmutable()
+	}
+	makeFunc := func(modify func(*descpb.FunctionDescriptor)) descpb.FunctionDescriptor {
+		desc := descpb.FunctionDescriptor{
+			Name:           "f",
+			ID:             funcID,
+			ParentID:       dbID,
+			ParentSchemaID: schemaID,
+			Params: []descpb.FunctionDescriptor_Param{
+				{Name: "a", Type: types.Int},
+			},
+			ReturnType:   descpb.FunctionDescriptor_ReturnType{Type: types.Int},
+			FunctionBody: "SELECT a",
+			Privileges:   privs,
+		}
+		if modify != nil {
+			modify(&desc)
+		}
+		return desc
+	}
+
+	testData := []struct {
+		err    string
+		desc   descpb.FunctionDescriptor
+		schema catalog.Descriptor
+	}{
+		{
+			desc:   makeFunc(nil),
+			schema: makeSchema("f"),
+		},
+		{
+			err:    "empty function name",
+			desc:   makeFunc(func(d *descpb.FunctionDescriptor) { d.Name = "" }),
+			schema: makeSchema(""),
+		},
+		{
+			err:    "return type not set",
+			desc:   makeFunc(func(d *descpb.FunctionDescriptor) { d.ReturnType.Type = nil }),
+			schema: makeSchema("f"),
+		},
+		{
+			err:    "type not set for parameter 0",
+			desc:   makeFunc(func(d *descpb.FunctionDescriptor) { d.Params[0].Type = nil }),
+			schema: makeSchema("f"),
+		},
+		{
+			err:    "function body is empty",
+			desc:   makeFunc(func(d *descpb.FunctionDescriptor) { d.FunctionBody = "" }),
+			schema: makeSchema("f"),
+		},
+		{
+			err: "leakproof is set for a non-immutable function with volatility STABLE",
+			desc: makeFunc(func(d *descpb.FunctionDescriptor) {
+				d.LeakProof = true
+				d.Volatility = descpb.FunctionDescriptor_STABLE
+			}),
+			schema: makeSchema("f"),
+		},
+		{
+			err:    "not present in parent schema [52] functions mapping",
+			desc:   makeFunc(nil),
+			schema: makeSchema(""),
+		},
+		{
+			err: "referenced schema ID 500: referenced descriptor not found",
+			desc: makeFunc(func(d *descpb.FunctionDescriptor) {
+				d.ParentSchemaID = 500
+			}),
+			schema: makeSchema(""),
+		},
+	}
+
+	for i, test := range testData {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			var cb nstree.MutableCatalog
+			desc := funcdesc.NewBuilder(&test.desc).BuildImmutable()
+			cb.UpsertDescriptorEntry(desc)
+			cb.UpsertDescriptorEntry(makeDB())
+			cb.UpsertDescriptorEntry(test.schema)
+			const validateCrossReferences = catalog.ValidationLevelCrossReferences
+			results := cb.Validate(ctx, clusterversion.TestingClusterVersion, catalog.NoValidationTelemetry, validateCrossReferences, desc)
+			err := results.CombinedError()
+			if test.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			expectedErr := fmt.Sprintf("%s %q (%d): %s", desc.DescriptorType(), desc.GetName(), desc.GetID(), test.err)
+			require.EqualError(t, err, expectedErr)
+		})
+	}
+}
+
+func TestToOverload(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	desc := funcdesc.NewBuilder(&descpb.FunctionDescriptor{
+		Name:           "f",
+		ID:             100,
+		ParentID:       51,
+		ParentSchemaID: 52,
+		Params: []descpb.FunctionDescriptor_Param{
+			{Name: "a", Type: types.Int},
+			{Name: "b", Type: types.String, Class: descpb.FunctionDescriptor_Param_OUT},
+			{Type: types.Float},
+		},
+		ReturnType:        descpb.FunctionDescriptor_ReturnType{Type: types.Int, ReturnSet: true},
+		FunctionBody:      "SELECT a",
+		Volatility:        descpb.FunctionDescriptor_IMMUTABLE,
+		LeakProof:         true,
+		NullInputBehavior: descpb.FunctionDescriptor_STRICT,
+	}).BuildImmutableFunction()
+
+	ol := funcdesc.ToOverload(desc)
+	require.True(t, ol.IsUDF)
+	require.Equal(t, "SELECT a", ol.UDFBody)
+	require.Equal(t, []string{"a", ""}, ol.UDFParamNames)
+	require.True(t, ol.UDFStrict)
+	require.True(t, ol.UDFReturnsSet)
+	require.Equal(t, []*types.T{types.Int, types.Float}, ol.Types.Types())
+	require.Equal(t, types.Int, ol.FixedReturnType())
+	require.Equal(t, volatility.LeakProof, ol.Volatility)
+}
@@ -78,6 +78,12 @@ func GetIngestingDescriptorPrivileges(
 		if descCoverage == tree.RequestedDescriptors {
 			updatedPrivileges = catpb.NewBasePrivilegeDescriptor(user)
 		}
+	case catalog.FunctionDescriptor:
+		// Like types, we wipe the privileges on the function if the ingestion is
+		// not a cluster restore.
+		if descCoverage == tree.RequestedDescriptors {
+			updatedPrivileges = catpb.NewBasePrivilegeDescriptor(user)
+		}
 	case catalog.DatabaseDescriptor:
 		// If the ingestion is not a cluster restore we cannot know that the users
 		// on the ingesting cluster match the ones that were on the cluster that was
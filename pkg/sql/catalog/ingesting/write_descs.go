@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -53,6 +54,7 @@ func WriteDescriptors(
 	schemas []catalog.SchemaDescriptor,
 	tables []catalog.TableDescriptor,
 	types []catalog.TypeDescriptor,
+	functions []catalog.FunctionDescriptor,
 	descCoverage tree.DescriptorCoverage,
 	extra []roachpb.KeyValue,
 	inheritParentName string,
@@ -190,6 +192,30 @@ func WriteDescriptors(
 		b.CPut(catalogkeys.EncodeNameKey(codec, typ), typ.GetID(), nil)
 	}
 
+	// Write all function descriptors. Functions do not have namespace entries;
+	// they are instead added to the functions mapping of their parent schema.
+	for i := range functions {
+		fn := functions[i]
+		updatedPrivileges, err := GetIngestingDescriptorPrivileges(ctx, txn, descsCol, fn, user,
+			wroteDBs, wroteSchemas, descCoverage)
+		if err != nil {
+			return err
+		}
+		if updatedPrivileges != nil {
+			if mut, ok := fn.(*funcdesc.Mutable); ok {
+				mut.Privileges = updatedPrivileges
+			} else {
+				log.Fatalf(ctx, "wrong type for function %d, %T, expected Mutable",
+					fn.GetID(), fn)
+			}
+		}
+		if err := descsCol.WriteDescToBatch(
+			ctx, false /* kvTrace */, fn.(catalog.MutableDescriptor), b,
+		); err != nil {
+			return err
+		}
+	}
+
 	for _, kv := range extra {
 		b.InitPut(kv.Key, &kv.Value, false)
 	}
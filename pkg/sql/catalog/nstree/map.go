@@ -36,10 +36,12 @@ type EntryIterator func(entry catalog.NameEntry) error
 // tree with the same name or id, it will be removed.
 func (dt *Map) Upsert(d catalog.NameEntry) {
 	dt.maybeInitialize()
-	if replaced := dt.byName.upsert(d); replaced != nil {
-		dt.byID.delete(replaced.GetID())
+	if !skipNamespace(d) {
+		if replaced := dt.byName.upsert(d); replaced != nil {
+			dt.byID.delete(replaced.GetID())
+		}
 	}
-	if replaced := dt.byID.upsert(d); replaced != nil {
+	if replaced := dt.byID.upsert(d); replaced != nil && !skipNamespace(replaced) {
 		dt.byName.delete(replaced)
 	}
 }
@@ -49,12 +51,22 @@ func (dt *Map) Upsert(d catalog.NameEntry) {
 func (dt *Map) Remove(id descpb.ID) catalog.NameEntry {
 	dt.maybeInitialize()
 	if d := dt.byID.delete(id); d != nil {
-		dt.byName.delete(d)
+		if !skipNamespace(d) {
+			dt.byName.delete(d)
+		}
 		return d
 	}
 	return nil
 }
 
+// skipNamespace returns true if the entry is, or wraps, a descriptor which
+// does not have a namespace record, and should therefore not be indexed by
+// name.
+func skipNamespace(e catalog.NameEntry) bool {
+	d, ok := e.(interface{ SkipNamespace() bool })
+	return ok && d.SkipNamespace()
+}
+
 // GetByID gets a descriptor from the tree by id.
 func (dt *Map) GetByID(id descpb.ID) catalog.NameEntry {
 	if !dt.initialized() {
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -65,6 +66,7 @@ func TableDescs(
 		if err := rewriteSchemaChangerState(table, descriptorRewrites); err != nil {
 			return err
 		}
+		funcBodyDB := functionBodyDBName(table.ParentID, descriptorRewrites, overrideDB)
 
 		table.ID = tableRewrite.ID
 		table.UnexposedParentSchemaID = tableRewrite.ParentSchemaID
@@ -153,6 +155,26 @@ func TableDescs(
 					table.Name, dest)
 			}
 		}
+		for i := range table.Triggers {
+			trigger := &table.Triggers[i]
+			fnRewrite, ok := descriptorRewrites[trigger.FuncID]
+			if !ok {
+				// Tables whose trigger functions are not restored should have
+				// caused an error in allocateDescriptorRewrites.
+				return errors.AssertionFailedf(
+					"cannot restore %q because referenced function %d was not found",
+					table.Name, trigger.FuncID)
+			}
+			trigger.FuncID = fnRewrite.ID
+			if funcBodyDB != "" {
+				funcBody, err := rewriteQueryDBNames(trigger.FuncBody, funcBodyDB)
+				if err != nil {
+					return pgerror.Wrapf(err, pgcode.Syntax,
+						"failed to parse function body of trigger %q on %q", trigger.Name, table.Name)
+				}
+				trigger.FuncBody = funcBody
+			}
+		}
 		origRefs := table.DependedOnBy
 		table.DependedOnBy = nil
 		for _, ref := range origRefs {
@@ -235,11 +257,39 @@ func TableDescs(
 //
 // TODO: this AST traversal misses tables named in strings (#24556).
 func rewriteViewQueryDBNames(table *tabledesc.Mutable, newDB string) error {
-	stmt, err := parser.ParseOne(table.ViewQuery)
+	viewQuery, err := rewriteQueryDBNames(table.ViewQuery, newDB)
 	if err != nil {
 		return pgerror.Wrapf(err, pgcode.Syntax,
 			"failed to parse underlying query from view %q", table.Name)
 	}
+	table.ViewQuery = viewQuery
+	return nil
+}
+
+// functionBodyDBName returns the name of the database which the tables
+// referenced by the body of a function in the database with the given
+// (pre-rewrite) ID must now refer to, or the empty string if the database
+// names in the body do not need to be rewritten. The tables referenced by a
+// function body are restored along with the function, into the same database.
+func functionBodyDBName(
+	parentID descpb.ID, descriptorRewrites jobspb.DescRewriteMap, overrideDB string,
+) string {
+	if overrideDB != "" {
+		return overrideDB
+	}
+	if rw, ok := descriptorRewrites[parentID]; ok {
+		return rw.NewDBName
+	}
+	return ""
+}
+
+// rewriteQueryDBNames returns the passed statement with all non-empty db
+// qualifiers replaced with `newDB`.
+func rewriteQueryDBNames(query string, newDB string) (string, error) {
+	stmt, err := parser.ParseOne(query)
+	if err != nil {
+		return "", err
+	}
 	// Re-format to change all DB names to `newDB`.
 	f := tree.NewFmtCtx(
 		tree.FmtParsable,
@@ -254,8 +304,7 @@ func rewriteViewQueryDBNames(table *tabledesc.Mutable, newDB string) error {
 		}),
 	)
 	f.FormatNode(stmt.AST)
-	table.ViewQuery = f.CloseAndGetString()
-	return nil
+	return f.CloseAndGetString(), nil
 }
 
 // rewriteTypesInExpr rewrites all explicit ID type references in the input
@@ -374,7 +423,8 @@ func rewriteIDsInTypesT(typ *types.T, descriptorRewrites jobspb.DescRewriteMap)
 	if rw, ok := descriptorRewrites[tid]; ok {
 		newOID = catid.TypeIDToOID(rw.ID)
 	}
-	if typ.Family() != types.ArrayFamily {
+	// Domains do not have an array type.
+	if typ.Family() != types.ArrayFamily && !typ.IsDomain() {
 		tid, err = typedesc.GetUserDefinedArrayTypeDescID(typ)
 		if err != nil {
 			return err
@@ -458,6 +508,9 @@ func TypeDescs(types []*typedesc.Mutable, descriptorRewrites jobspb.DescRewriteM
 			if err := rewriteIDsInTypesT(typ.Alias, descriptorRewrites); err != nil {
 				return err
 			}
+		case descpb.TypeDescriptor_DOMAIN:
+			// The base type and the expressions of a domain cannot refer to other
+			// descriptors, so there is nothing to rewrite.
 		default:
 			return errors.AssertionFailedf("unknown type kind %s", t.String())
 		}
@@ -480,6 +533,24 @@ func SchemaDescs(schemas []*schemadesc.Mutable, descriptorRewrites jobspb.DescRe
 		sc.ID = rewrite.ID
 		sc.ParentID = rewrite.ParentID
 
+		// Remap the functions in the schema. Functions which are not being
+		// restored are removed from the mapping.
+		for name, fn := range sc.Functions {
+			overloads := fn.Overloads[:0]
+			for _, overload := range fn.Overloads {
+				if fnRewrite, ok := descriptorRewrites[overload.ID]; ok {
+					overload.ID = fnRewrite.ID
+					overloads = append(overloads, overload)
+				}
+			}
+			if len(overloads) == 0 {
+				delete(sc.Functions, name)
+				continue
+			}
+			fn.Overloads = overloads
+			sc.Functions[name] = fn
+		}
+
 		if err := rewriteSchemaChangerState(sc, descriptorRewrites); err != nil {
 			return err
 		}
@@ -487,6 +558,98 @@ func SchemaDescs(schemas []*schemadesc.Mutable, descriptorRewrites jobspb.DescRe
 	return nil
 }
 
+// FunctionDescs rewrites all ID's in the input slice of FunctionDescriptors
+// using the input ID rewrite mapping.
+func FunctionDescs(
+	functions []*funcdesc.Mutable, descriptorRewrites jobspb.DescRewriteMap, overrideDB string,
+) error {
+	for _, fn := range functions {
+		fnRewrite, ok := descriptorRewrites[fn.ID]
+		if !ok {
+			return errors.Errorf("missing rewrite for function %d", fn.ID)
+		}
+		// Reset the version and modification time on this new descriptor.
+		fn.Version = 1
+		fn.ModificationTime = hlc.Timestamp{}
+
+		if err := rewriteSchemaChangerState(fn, descriptorRewrites); err != nil {
+			return err
+		}
+
+		funcBodyDB := functionBodyDBName(fn.ParentID, descriptorRewrites, overrideDB)
+		fn.ID = fnRewrite.ID
+		fn.ParentSchemaID = fnRewrite.ParentSchemaID
+		fn.ParentID = fnRewrite.ParentID
+
+		if funcBodyDB != "" && fn.Aggregate == nil {
+			// Like view queries, the function body is stored with fully qualified
+			// table names which must now refer to the restored database.
+			// Aggregates have no body.
+			funcBody, err := rewriteQueryDBNames(fn.FunctionBody, funcBodyDB)
+			if err != nil {
+				return pgerror.Wrapf(err, pgcode.Syntax,
+					"failed to parse body of function %q", fn.Name)
+			}
+			fn.FunctionBody = funcBody
+		}
+
+		for i := range fn.Params {
+			if err := rewriteIDsInTypesT(fn.Params[i].Type, descriptorRewrites); err != nil {
+				return err
+			}
+		}
+		if fn.ReturnType.Type != nil {
+			if err := rewriteIDsInTypesT(fn.ReturnType.Type, descriptorRewrites); err != nil {
+				return err
+			}
+		}
+
+		for i, dest := range fn.DependsOn {
+			depRewrite, ok := descriptorRewrites[dest]
+			if !ok {
+				return errors.Errorf(
+					"cannot restore function %q because referenced table %d was not found",
+					fn.Name, dest)
+			}
+			fn.DependsOn[i] = depRewrite.ID
+		}
+		// Only the tables being restored keep their triggers on the function.
+		origRefs := fn.DependedOnBy
+		fn.DependedOnBy = nil
+		for _, ref := range origRefs {
+			if refRewrite, ok := descriptorRewrites[ref]; ok {
+				fn.DependedOnBy = append(fn.DependedOnBy, refRewrite.ID)
+			}
+		}
+
+		if agg := fn.Aggregate; agg != nil {
+			if err := rewriteIDsInTypesT(agg.StateType, descriptorRewrites); err != nil {
+				return err
+			}
+			for _, id := range []*descpb.ID{&agg.StateFuncID, &agg.FinalFuncID} {
+				if *id == descpb.InvalidID {
+					continue
+				}
+				funcRewrite, ok := descriptorRewrites[*id]
+				if !ok {
+					return errors.Errorf(
+						"cannot restore aggregate %q because support function %d was not found",
+						fn.Name, *id)
+				}
+				*id = funcRewrite.ID
+			}
+		}
+		origAggRefs := fn.DependedOnByAggregates
+		fn.DependedOnByAggregates = nil
+		for _, ref := range origAggRefs {
+			if refRewrite, ok := descriptorRewrites[ref]; ok {
+				fn.DependedOnByAggregates = append(fn.DependedOnByAggregates, refRewrite.ID)
+			}
+		}
+	}
+	return nil
+}
+
 // rewriteSchemaChangerState handles rewriting any references to IDs stored in
 // the descriptor's declarative schema changer state.
 func rewriteSchemaChangerState(
@@ -589,6 +752,19 @@ func DatabaseDescs(databases []*dbdesc.Mutable, descriptorRewrites jobspb.DescRe
 			return err
 		}
 		db.Schemas = newSchemas
+
+		// Rewrite the IDs of the tables included in the database's publications.
+		// Tables which are not being restored are no longer published.
+		for i := range db.Publications {
+			pub := &db.Publications[i]
+			tableIDs := pub.TableIDs[:0]
+			for _, id := range pub.TableIDs {
+				if rewrite, ok := descriptorRewrites[id]; ok {
+					tableIDs = append(tableIDs, rewrite.ID)
+				}
+			}
+			pub.TableIDs = tableIDs
+		}
 	}
 	return nil
 }
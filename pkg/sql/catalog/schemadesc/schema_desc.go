@@ -12,6 +12,7 @@ package schemadesc
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -25,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/iterutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 )
@@ -118,6 +120,11 @@ func (desc *immutable) DescriptorType() catalog.DescriptorType {
 	return catalog.Schema
 }
 
+// SkipNamespace implements the descriptor interface.
+func (desc *immutable) SkipNamespace() bool {
+	return false
+}
+
 // SchemaDesc implements the Descriptor interface.
 func (desc *immutable) SchemaDesc() *descpb.SchemaDescriptor {
 	return &desc.SchemaDescriptor
@@ -194,7 +201,13 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 // GetReferencedDescIDs returns the IDs of all descriptors referenced by
 // this descriptor, including itself.
 func (desc *immutable) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
-	return catalog.MakeDescriptorIDSet(desc.GetID(), desc.GetParentID()), nil
+	ret := catalog.MakeDescriptorIDSet(desc.GetID(), desc.GetParentID())
+	for _, fn := range desc.Functions {
+		for _, overload := range fn.Overloads {
+			ret.Add(overload.ID)
+		}
+	}
+	return ret, nil
 }
 
 // ValidateCrossReferences implements the catalog.Descriptor interface.
@@ -241,6 +254,21 @@ func (desc *immutable) ValidateCrossReferences(
 		vea.Report(errors.AssertionFailedf("not present in parent database [%d] schemas mapping",
 			desc.GetParentID()))
 	}
+
+	// Check that all functions exist and belong to this schema.
+	for name, fn := range desc.Functions {
+		for _, overload := range fn.Overloads {
+			funcDesc, err := vdg.GetFunctionDescriptor(overload.ID)
+			if err != nil {
+				vea.Report(err)
+				continue
+			}
+			if funcDesc.GetName() != name || funcDesc.GetParentSchemaID() != desc.GetID() {
+				vea.Report(errors.AssertionFailedf("function %q (%d) does not match functions mapping entry %q",
+					funcDesc.GetName(), funcDesc.GetID(), errors.Safe(name)))
+			}
+		}
+	}
 }
 
 // ValidateTxnCommit implements the catalog.Descriptor interface.
@@ -259,6 +287,34 @@ func (desc *immutable) GetDefaultPrivilegeDescriptor() catalog.DefaultPrivilegeD
 	return catprivilege.MakeDefaultPrivileges(defaultPrivilegeDescriptor)
 }
 
+// GetFunction implements the SchemaDescriptor interface.
+func (desc *immutable) GetFunction(name string) (descpb.SchemaDescriptor_Function, bool) {
+	fn, ok := desc.Functions[name]
+	return fn, ok
+}
+
+// ForEachFunctionOverload implements the SchemaDescriptor interface.
+func (desc *immutable) ForEachFunctionOverload(
+	fn func(overload descpb.SchemaDescriptor_FunctionOverload) error,
+) error {
+	names := make([]string, 0, len(desc.Functions))
+	for name := range desc.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, overload := range desc.Functions[name].Overloads {
+			if err := fn(overload); err != nil {
+				if iterutil.Done(err) {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // GetPostDeserializationChanges implements the Descriptor interface.
 func (desc *immutable) GetPostDeserializationChanges() catalog.PostDeserializationChanges {
 	return desc.changes
@@ -337,6 +393,40 @@ func (desc *Mutable) SetName(name string) {
 	desc.Name = name
 }
 
+// AddFunction adds a user-defined function overload to the schema's
+// functions mapping.
+func (desc *Mutable) AddFunction(name string, overload descpb.SchemaDescriptor_FunctionOverload) {
+	if desc.Functions == nil {
+		desc.Functions = make(map[string]descpb.SchemaDescriptor_Function)
+	}
+	fn := desc.Functions[name]
+	fn.Name = name
+	fn.Overloads = append(fn.Overloads, overload)
+	desc.Functions[name] = fn
+}
+
+// RemoveFunction removes the overload with the given function ID from the
+// schema's functions mapping. The mapping entry is removed entirely once its
+// last overload is gone.
+func (desc *Mutable) RemoveFunction(name string, id descpb.ID) {
+	fn, ok := desc.Functions[name]
+	if !ok {
+		return
+	}
+	overloads := fn.Overloads[:0]
+	for _, o := range fn.Overloads {
+		if o.ID != id {
+			overloads = append(overloads, o)
+		}
+	}
+	if len(overloads) == 0 {
+		delete(desc.Functions, name)
+		return
+	}
+	fn.Overloads = overloads
+	desc.Functions[name] = fn
+}
+
 // IsUncommittedVersion implements the Descriptor interface.
 func (desc *Mutable) IsUncommittedVersion() bool {
 	return desc.IsNew() || desc.GetVersion() != desc.ClusterVersion.GetVersion()
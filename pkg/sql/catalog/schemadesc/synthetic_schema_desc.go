@@ -60,6 +60,9 @@ func (p synthetic) GetPrivileges() *catpb.PrivilegeDescriptor {
 func (p synthetic) DescriptorType() catalog.DescriptorType {
 	return catalog.Schema
 }
+func (p synthetic) SkipNamespace() bool {
+	return false
+}
 func (p synthetic) GetAuditMode() descpb.TableDescriptor_AuditMode {
 	return descpb.TableDescriptor_DISABLED
 }
@@ -126,3 +129,16 @@ func (p synthetic) HasConcurrentSchemaChanges() bool {
 func (p synthetic) GetDefaultPrivilegeDescriptor() catalog.DefaultPrivilegeDescriptor {
 	return catprivilege.MakeDefaultPrivileges(catprivilege.MakeDefaultPrivilegeDescriptor(catpb.DefaultPrivilegeDescriptor_SCHEMA))
 }
+
+// GetFunction implements the catalog.SchemaDescriptor interface. Synthetic
+// schemas never contain user-defined functions.
+func (p synthetic) GetFunction(name string) (descpb.SchemaDescriptor_Function, bool) {
+	return descpb.SchemaDescriptor_Function{}, false
+}
+
+// ForEachFunctionOverload implements the catalog.SchemaDescriptor interface.
+func (p synthetic) ForEachFunctionOverload(
+	fn func(overload descpb.SchemaDescriptor_FunctionOverload) error,
+) error {
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// DomainValueName is the name by which the CHECK constraints of a domain
+// refer to the value being checked.
+const DomainValueName = "value"
+
+// ValidateDomainDefaultExpr type-checks the DEFAULT expression of a domain
+// over the given base type.
+func ValidateDomainDefaultExpr(
+	ctx context.Context, expr tree.Expr, baseType *types.T, semaCtx *tree.SemaContext,
+) (tree.TypedExpr, error) {
+	const context = "DEFAULT"
+	typedExpr, err := SanitizeVarFreeExpr(
+		ctx, expr, baseType, context, semaCtx, volatility.Volatile, true, /* allowAssignmentCast */
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNoUserDefinedTypes(typedExpr, context); err != nil {
+		return nil, err
+	}
+	return typedExpr, nil
+}
+
+// ValidateDomainCheckExpr type-checks the expression of a CHECK constraint of
+// a domain over the given base type. The expression refers to the value being
+// checked as VALUE, and cannot refer to any other column.
+func ValidateDomainCheckExpr(
+	ctx context.Context, expr tree.Expr, baseType *types.T, semaCtx *tree.SemaContext,
+) (tree.TypedExpr, error) {
+	expr, err := tree.SimpleVisit(expr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		switch t := expr.(type) {
+		case *tree.UnresolvedName:
+			if t.NumParts == 1 && !t.Star && t.Parts[0] == DomainValueName {
+				return false, tree.NewTypedOrdinalReference(0, baseType), nil
+			}
+			return false, nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist", tree.ErrString(t))
+		case *tree.Placeholder:
+			return false, nil, pgerror.Newf(pgcode.InvalidObjectDefinition,
+				"placeholders are not allowed in domain CHECK constraints")
+		}
+		return true, expr, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The VALUE is typed through the container of the semantic context. Save
+	// and restore the previous values of the fields which are modified.
+	defer semaCtx.Properties.Restore(semaCtx.Properties)
+	defer func(prev tree.IndexedVarContainer) { semaCtx.IVarContainer = prev }(semaCtx.IVarContainer)
+	ivh := tree.MakeTypesOnlyIndexedVarHelper([]*types.T{baseType})
+	semaCtx.IVarContainer = ivh.Container()
+	const context = "CHECK"
+	semaCtx.Properties.Require(context, tree.RejectSpecial|tree.RejectSubqueries)
+
+	typedExpr, err := tree.TypeCheck(ctx, expr, semaCtx, types.Any)
+	if err != nil {
+		return nil, err
+	}
+	if typ := typedExpr.ResolvedType(); typ.Family() != types.BoolFamily && typedExpr != tree.DNull {
+		return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+			"argument of CHECK must be type boolean, not type %s", typ.SQLStandardName())
+	}
+	if err := checkNoUserDefinedFunctions(typedExpr, context); err != nil {
+		return nil, err
+	}
+	if err := checkNoUserDefinedTypes(typedExpr, context); err != nil {
+		return nil, err
+	}
+	return typedExpr, nil
+}
+
+// checkNoUserDefinedTypes returns an error if the type-checked expression
+// refers to a user-defined type. The constraints of domains cannot refer to
+// other user-defined types, so that domains do not depend on other types.
+func checkNoUserDefinedTypes(typedExpr tree.TypedExpr, context string) error {
+	_, err := tree.SimpleVisit(typedExpr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if t, ok := expr.(tree.TypedExpr); ok && t.ResolvedType().UserDefined() {
+			return false, expr, pgerror.Newf(pgcode.FeatureNotSupported,
+				"user-defined types are not allowed in domain %s expressions", context)
+		}
+		return true, expr, nil
+	})
+	return err
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/transform"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -116,6 +117,50 @@ func DequalifyAndValidateExpr(
 	return tree.Serialize(typedExpr), typedExpr.ResolvedType(), colIDs, nil
 }
 
+// MakeRowFilterExpr returns the given boolean filter expression over the
+// columns of tableDesc, type-checked and normalized, with its column
+// references replaced by IndexedVars which refer to the ordinals of the
+// columns in cols. The expression can then be evaluated on rows of cols with a
+// RowIndexedVarContainer.
+//
+// The expression is validated like a partial index predicate, except that it
+// may contain stable functions, since it is not persisted. It may only refer to
+// columns in cols.
+func MakeRowFilterExpr(
+	ctx context.Context,
+	expr tree.Expr,
+	context string,
+	tableDesc catalog.TableDescriptor,
+	tn *tree.TableName,
+	cols []catalog.Column,
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+) (tree.TypedExpr, error) {
+	filter, _, _, err := DequalifyAndValidateExpr(
+		ctx, tableDesc, expr, types.Bool, context, semaCtx, volatility.Stable, tn,
+	)
+	if err != nil {
+		return nil, err
+	}
+	expr, err = parser.ParseExpr(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	nr := newNameResolver(evalCtx, tableDesc.GetID(), tn, cols)
+	nr.addIVarContainerToSemaCtx(semaCtx)
+	expr, err = nr.resolveNames(expr)
+	if err != nil {
+		return nil, err
+	}
+	typedExpr, err := tree.TypeCheck(ctx, expr, semaCtx, types.Bool)
+	if err != nil {
+		return nil, err
+	}
+	var txCtx transform.ExprTransformContext
+	return txCtx.NormalizeExpr(evalCtx, typedExpr)
+}
+
 // ExtractColumnIDs returns the set of column IDs within the given expression.
 func ExtractColumnIDs(
 	desc catalog.TableDescriptor, rootExpr tree.Expr,
@@ -435,6 +480,9 @@ func SanitizeVarFreeExpr(
 	if err != nil {
 		return nil, err
 	}
+	if err := checkNoUserDefinedFunctions(typedExpr, context); err != nil {
+		return nil, err
+	}
 
 	actualType := typedExpr.ResolvedType()
 	if !expectedType.Equivalent(actualType) && typedExpr != tree.DNull {
@@ -450,3 +498,17 @@ func SanitizeVarFreeExpr(
 	}
 	return typedExpr, nil
 }
+
+// checkNoUserDefinedFunctions returns an error if the type-checked expression
+// calls a user-defined function. References to user-defined functions are not
+// tracked for expressions stored in descriptors.
+func checkNoUserDefinedFunctions(typedExpr tree.TypedExpr, context string) error {
+	_, err := tree.SimpleVisit(typedExpr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if f, ok := expr.(*tree.FuncExpr); ok && f.ResolvedOverload() != nil && f.ResolvedOverload().IsUDF {
+			return false, expr, pgerror.Newf(pgcode.FeatureNotSupported,
+				"user-defined functions are not allowed in %s", context)
+		}
+		return true, expr, nil
+	})
+	return err
+}
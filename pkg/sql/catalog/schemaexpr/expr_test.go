@@ -14,9 +14,12 @@ import (
 	"context"
 	"testing"
 
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
@@ -199,3 +202,86 @@ func TestValidColumnReferences(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeRowFilterExpr(t *testing.T) {
+	ctx := context.Background()
+	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+
+	// Trick to get the init() for the builtins package to run.
+	_ = builtins.AllBuiltinNames
+
+	database := tree.Name("foo")
+	table := tree.Name("bar")
+	tn := tree.MakeTableNameWithSchema(database, tree.PublicSchemaName, table)
+
+	desc := testTableDesc(
+		string(table),
+		[]testCol{{"a", types.Bool}, {"b", types.Int}, {"c", types.String}},
+		nil, /* mutationColumns */
+	)
+	// The filter is evaluated on rows of the columns c and b.
+	cols := []catalog.Column{desc.PublicColumns()[2], desc.PublicColumns()[1]}
+	var mapping catalog.TableColMap
+	for i, col := range cols {
+		mapping.Set(col.GetID(), i)
+	}
+	row := tree.Datums{tree.NewDString("baz"), tree.NewDInt(3)}
+
+	testData := []struct {
+		expr          string
+		expectedValid bool
+		expected      tree.Datum
+	}{
+		{"b = 3", true, tree.DBoolTrue},
+		{"bar.b > 3 OR foo.bar.c = 'baz'", true, tree.DBoolTrue},
+		{"upper(c) = 'BAZ' AND b IS NULL", true, tree.DBoolFalse},
+		{"now() > '2020-01-01' AND b = 3", true, tree.DBoolTrue},
+		{"c < NULL", true, tree.DNull},
+
+		// Disallow columns not in the rows.
+		{"a", false, nil},
+		{"d = 1", false, nil},
+
+		// Disallow non-bool expressions, volatile functions and subqueries.
+		{"b", false, nil},
+		{"random() > 0.5", false, nil},
+		{"b IN (SELECT 1)", false, nil},
+	}
+
+	for _, d := range testData {
+		t.Run(d.expr, func(t *testing.T) {
+			expr, err := parser.ParseExpr(d.expr)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %s", d.expr, err)
+			}
+
+			semaCtx := tree.MakeSemaContext()
+			filter, err := schemaexpr.MakeRowFilterExpr(
+				ctx, expr, "test-row-filter", desc, &tn, cols, &evalCtx, &semaCtx,
+			)
+
+			if !d.expectedValid {
+				if err == nil {
+					t.Fatalf("%s: expected invalid expression, but was valid", d.expr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("%s: expected valid expression, but found error: %s", d.expr, err)
+			}
+
+			evalCtx.PushIVarContainer(&schemaexpr.RowIndexedVarContainer{
+				CurSourceRow: row, Cols: cols, Mapping: mapping,
+			})
+			defer evalCtx.PopIVarContainer()
+			res, err := eval.Expr(&evalCtx, filter)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %s", d.expr, err)
+			}
+			if res != d.expected {
+				t.Errorf("%s: expected %s, got %s", d.expr, d.expected, res)
+			}
+		})
+	}
+}
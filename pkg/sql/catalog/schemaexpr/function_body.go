@@ -0,0 +1,123 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// CompileScalarFunctionBody compiles the body of the given user-defined SQL
+// function into a scalar expression, which can be evaluated without planning
+// a query for each call. This is used to execute the support functions of
+// user-defined aggregates.
+//
+// The body must be a single SELECT statement which projects one expression
+// without any data source, like "SELECT $1 + $2". References to the
+// parameters of the function, either positional or by name, are replaced by
+// IndexedVars whose ordinals are the ordinals of the parameters. The returned
+// expression has the return type of the function.
+func CompileScalarFunctionBody(
+	ctx context.Context, o *tree.Overload, semaCtx *tree.SemaContext,
+) (tree.TypedExpr, error) {
+	if !o.IsUDF || o.UDFReturnsSet || o.UDFTrigger {
+		return nil, errors.AssertionFailedf("expected a scalar user-defined function")
+	}
+	stmt, err := parser.ParseOne(o.UDFBody)
+	if err != nil {
+		return nil, err
+	}
+	expr, ok := scalarFunctionBodyExpr(stmt.AST)
+	if !ok {
+		return nil, unimplemented.New("udf-aggregate-support-function-body",
+			"the body of a function used by an aggregate must be a single SELECT of an expression without a FROM clause")
+	}
+
+	argTypes := o.Types.Types()
+	expr, err = tree.SimpleVisit(expr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		switch t := expr.(type) {
+		case *tree.Placeholder:
+			if int(t.Idx) >= len(argTypes) {
+				return false, nil, pgerror.Newf(pgcode.UndefinedParameter, "there is no parameter %s", t)
+			}
+			return false, tree.NewTypedOrdinalReference(int(t.Idx), argTypes[t.Idx]), nil
+		case *tree.UnresolvedName:
+			if t.NumParts == 1 && !t.Star {
+				for i, name := range o.UDFParamNames {
+					if name != "" && name == t.Parts[0] {
+						return false, tree.NewTypedOrdinalReference(i, argTypes[i]), nil
+					}
+				}
+			}
+			return false, nil, pgerror.Newf(pgcode.UndefinedColumn,
+				"column %q does not exist", tree.ErrString(t))
+		}
+		return true, expr, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The IndexedVars are typed through the container of the semantic context.
+	// Save and restore the previous values of the fields which are modified.
+	defer semaCtx.Properties.Restore(semaCtx.Properties)
+	defer func(prev tree.IndexedVarContainer) { semaCtx.IVarContainer = prev }(semaCtx.IVarContainer)
+	ivh := tree.MakeTypesOnlyIndexedVarHelper(argTypes)
+	semaCtx.IVarContainer = ivh.Container()
+	const context = "aggregate support functions"
+	semaCtx.Properties.Require(context, tree.RejectSpecial|tree.RejectSubqueries)
+
+	retType := o.FixedReturnType()
+	typedExpr, err := tree.TypeCheck(ctx, expr, semaCtx, retType)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNoUserDefinedFunctions(typedExpr, context); err != nil {
+		return nil, err
+	}
+	if actualType := typedExpr.ResolvedType(); !actualType.Identical(retType) && typedExpr != tree.DNull {
+		if !cast.ValidCast(actualType, retType, cast.ContextAssignment) {
+			return nil, errors.WithDetailf(
+				pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"return type mismatch in function declared to return %s", retType.SQLStandardName()),
+				"Actual return type of function is %s.", actualType.SQLStandardName(),
+			)
+		}
+		typedExpr = tree.NewTypedCastExpr(typedExpr, retType)
+	}
+	return typedExpr, nil
+}
+
+// scalarFunctionBodyExpr returns the single projected expression of a SELECT
+// statement without any data source or other clauses.
+func scalarFunctionBodyExpr(stmt tree.Statement) (tree.Expr, bool) {
+	sel, ok := stmt.(*tree.Select)
+	if !ok || sel.With != nil || sel.OrderBy != nil || sel.Limit != nil || sel.Locking != nil {
+		return nil, false
+	}
+	clause, ok := sel.Select.(*tree.SelectClause)
+	if !ok || len(clause.Exprs) != 1 || clause.Distinct || clause.DistinctOn != nil ||
+		len(clause.From.Tables) != 0 || clause.From.AsOf.Expr != nil || clause.Where != nil ||
+		clause.GroupBy != nil || clause.Having != nil || clause.Window != nil || clause.TableSelect {
+		return nil, false
+	}
+	if _, ok := clause.Exprs[0].Expr.(tree.UnqualifiedStar); ok {
+		return nil, false
+	}
+	return clause.Exprs[0].Expr, true
+}
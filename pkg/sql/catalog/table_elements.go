@@ -137,6 +137,7 @@ type Index interface {
 	GetName() string
 	IsPartial() bool
 	IsUnique() bool
+	IsExclusion() bool
 	IsDisabled() bool
 	IsSharded() bool
 	IsCreatedExplicitly() bool
@@ -161,6 +162,13 @@ type Index interface {
 	GetKeyColumnName(columnOrdinal int) string
 	GetKeyColumnDirection(columnOrdinal int) descpb.IndexDescriptor_Direction
 
+	// GetExclusionOperator returns the operator with which the key column at
+	// the given ordinal is compared by the EXCLUDE constraint backed by the
+	// index.
+	//
+	// Panics if the index does not back an EXCLUDE constraint.
+	GetExclusionOperator(columnOrdinal int) string
+
 	CollectKeyColumnIDs() TableColSet
 	CollectKeySuffixColumnIDs() TableColSet
 	CollectPrimaryStoredColumnIDs() TableColSet
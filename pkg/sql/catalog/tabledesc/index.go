@@ -92,6 +92,11 @@ func (w index) IsUnique() bool {
 	return w.desc.Unique
 }
 
+// IsExclusion returns true iff the index backs an EXCLUDE constraint.
+func (w index) IsExclusion() bool {
+	return w.desc.IsExclusion()
+}
+
 // IsDisabled returns true iff the index is disabled.
 func (w index) IsDisabled() bool {
 	return w.desc.Disabled
@@ -285,6 +290,14 @@ func (w index) GetKeyColumnDirection(columnOrdinal int) descpb.IndexDescriptor_D
 	return w.desc.KeyColumnDirections[columnOrdinal]
 }
 
+// GetExclusionOperator returns the operator with which the key column at the
+// ordinal-th position is compared by the EXCLUDE constraint backed by the
+// index.
+// Panics if the index does not back an EXCLUDE constraint.
+func (w index) GetExclusionOperator(columnOrdinal int) string {
+	return w.desc.ExclusionOperators[columnOrdinal]
+}
+
 // NumPrimaryStoredColumns returns the number of columns which the index
 // stores in addition to the columns which are part of the primary key.
 // Returns 0 if the index isn't primary.
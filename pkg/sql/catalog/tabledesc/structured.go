@@ -83,6 +83,11 @@ func (desc *wrapper) DescriptorType() catalog.DescriptorType {
 	return catalog.Table
 }
 
+// SkipNamespace implements the descriptor interface.
+func (desc *wrapper) SkipNamespace() bool {
+	return false
+}
+
 // SetName implements the DescriptorProto interface.
 func (desc *Mutable) SetName(name string) {
 	desc.Name = name
@@ -147,7 +152,7 @@ func (desc *wrapper) IndexKeysPerRow(idx catalog.Index) int {
 //
 func BuildIndexName(tableDesc *Mutable, idx *descpb.IndexDescriptor) (string, error) {
 	// An index name has a segment for the table name, each key column, and a
-	// final word (either "idx" or "key").
+	// final word ("idx", "key" or "excl").
 	segments := make([]string, 0, len(idx.KeyColumnNames)+2)
 
 	// Add the table name segment.
@@ -187,6 +192,8 @@ func BuildIndexName(tableDesc *Mutable, idx *descpb.IndexDescriptor) (string, er
 	// Add the final segment.
 	if idx.Unique {
 		segments = append(segments, "key")
+	} else if idx.IsExclusion() {
+		segments = append(segments, "excl")
 	} else {
 		segments = append(segments, "idx")
 	}
@@ -705,7 +712,7 @@ func (desc *Mutable) allocateIndexIDs(columnNames map[string]descpb.ColumnID) er
 			}
 			idx.IndexDesc().Name = name
 		}
-		if idx.GetConstraintID() == 0 && idx.IsUnique() {
+		if idx.GetConstraintID() == 0 && (idx.IsUnique() || idx.IsExclusion()) {
 			idx.IndexDesc().ConstraintID = desc.NextConstraintID
 			desc.NextConstraintID++
 		}
@@ -1345,6 +1352,11 @@ func (desc *Mutable) DropConstraint(
 		}
 		return nil
 
+	case descpb.ConstraintTypeExclusion:
+		return unimplemented.NewWithIssueDetailf(42840, "drop-constraint-exclusion",
+			"cannot drop EXCLUDE constraint %q using ALTER TABLE DROP CONSTRAINT, use DROP INDEX CASCADE instead",
+			tree.ErrNameStringP(&detail.Index.Name))
+
 	case descpb.ConstraintTypeUnique:
 		if detail.Index != nil {
 			return unimplemented.NewWithIssueDetailf(42840, "drop-constraint-unique",
@@ -1475,7 +1487,7 @@ func (desc *Mutable) RenameConstraint(
 	renameFK func(*Mutable, *descpb.ForeignKeyConstraint, string) error,
 ) error {
 	switch detail.Kind {
-	case descpb.ConstraintTypePK:
+	case descpb.ConstraintTypePK, descpb.ConstraintTypeExclusion:
 		for _, tableRef := range desc.DependedOnBy {
 			if tableRef.IndexID != detail.Index.ID {
 				continue
@@ -1578,6 +1590,45 @@ func (desc *wrapper) FindFKByName(name string) (*descpb.ForeignKeyConstraint, er
 	return nil, fmt.Errorf("fk %q does not exist", name)
 }
 
+// FindTriggerByName implements the TableDescriptor interface.
+func (desc *wrapper) FindTriggerByName(name string) (*descpb.TableDescriptor_Trigger, bool) {
+	for i := range desc.Triggers {
+		if desc.Triggers[i].Name == name {
+			return &desc.Triggers[i], true
+		}
+	}
+	return nil, false
+}
+
+// AddTrigger adds a trigger to the table, allocating its ID. The list of
+// triggers is kept sorted by name, which is the order in which they fire.
+func (desc *Mutable) AddTrigger(trigger descpb.TableDescriptor_Trigger) {
+	if desc.NextTriggerID == 0 {
+		desc.NextTriggerID = 1
+	}
+	trigger.ID = desc.NextTriggerID
+	desc.NextTriggerID++
+	i := sort.Search(len(desc.Triggers), func(i int) bool {
+		return desc.Triggers[i].Name >= trigger.Name
+	})
+	desc.Triggers = append(desc.Triggers, descpb.TableDescriptor_Trigger{})
+	copy(desc.Triggers[i+1:], desc.Triggers[i:])
+	desc.Triggers[i] = trigger
+}
+
+// RemoveTrigger removes the trigger with the given name from the table, and
+// returns it.
+func (desc *Mutable) RemoveTrigger(name string) (descpb.TableDescriptor_Trigger, bool) {
+	for i := range desc.Triggers {
+		if desc.Triggers[i].Name == name {
+			trigger := desc.Triggers[i]
+			desc.Triggers = append(desc.Triggers[:i], desc.Triggers[i+1:]...)
+			return trigger, true
+		}
+	}
+	return descpb.TableDescriptor_Trigger{}, false
+}
+
 // IsPrimaryIndexDefaultRowID returns whether or not the table's primary
 // index is the default primary key on the hidden rowid column.
 func (desc *wrapper) IsPrimaryIndexDefaultRowID() bool {
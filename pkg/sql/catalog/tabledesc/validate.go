@@ -120,6 +120,10 @@ func (desc *wrapper) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
 	for _, ref := range desc.GetDependedOnBy() {
 		ids.Add(ref.ID)
 	}
+	// Add trigger functions.
+	for i := range desc.Triggers {
+		ids.Add(desc.Triggers[i].FuncID)
+	}
 	// Add sequence dependencies
 	return ids, nil
 }
@@ -188,6 +192,11 @@ func (desc *wrapper) ValidateCrossReferences(
 		vea.Report(desc.validateInboundTableRef(by, vdg))
 	}
 
+	// Check trigger functions.
+	for i := range desc.Triggers {
+		vea.Report(desc.validateTriggerFunctionRef(&desc.Triggers[i], vdg))
+	}
+
 	// For row-level TTL, only ascending PKs are permitted.
 	if desc.HasRowLevelTTL() {
 		pk := desc.GetPrimaryIndex()
@@ -290,6 +299,27 @@ func (desc *wrapper) validateOutboundTypeRef(id descpb.ID, vdg catalog.Validatio
 	return nil
 }
 
+func (desc *wrapper) validateTriggerFunctionRef(
+	trigger *descpb.TableDescriptor_Trigger, vdg catalog.ValidationDescGetter,
+) error {
+	fn, err := vdg.GetFunctionDescriptor(trigger.FuncID)
+	if err != nil {
+		return errors.NewAssertionErrorWithWrappedErrf(err,
+			"invalid function reference in trigger %q", trigger.Name)
+	}
+	if fn.Dropped() {
+		return errors.AssertionFailedf("function %q (%d) of trigger %q is dropped",
+			fn.GetName(), fn.GetID(), trigger.Name)
+	}
+	for _, id := range fn.GetDependedOnBy() {
+		if id == desc.GetID() {
+			return nil
+		}
+	}
+	return errors.AssertionFailedf("function %q (%d) of trigger %q has no corresponding depended-on-by back reference",
+		fn.GetName(), fn.GetID(), trigger.Name)
+}
+
 func (desc *wrapper) validateInboundTableRef(
 	by descpb.TableDescriptor_Reference, vdg catalog.ValidationDescGetter,
 ) error {
@@ -350,6 +380,10 @@ func (desc *wrapper) validateInboundTableRef(
 func (desc *wrapper) validateOutboundFK(
 	fk *descpb.ForeignKeyConstraint, vdg catalog.ValidationDescGetter,
 ) error {
+	if fk.InitiallyDeferred && !fk.Deferrable {
+		return errors.AssertionFailedf(
+			"foreign key %q is initially deferred but not deferrable", fk.Name)
+	}
 	referencedTable, err := vdg.GetTableDescriptor(fk.ReferencedTableID)
 	if err != nil {
 		return errors.Wrapf(err,
@@ -633,6 +667,7 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			return
 		}
 		desc.validateConstraintIDs(vea)
+		desc.validateTriggers(vea)
 	}
 
 	// Ensure that mutations cannot be queued if a primary key change, TTL change
@@ -762,6 +797,35 @@ func ValidateOnUpdate(desc catalog.TableDescriptor, errReportFn func(err error))
 	})
 }
 
+// validateTriggers validates that the triggers of the table have unique
+// names and IDs, and are sorted by name.
+func (desc *wrapper) validateTriggers(vea catalog.ValidationErrorAccumulator) {
+	ids := make(map[descpb.TriggerID]struct{}, len(desc.Triggers))
+	for i := range desc.Triggers {
+		trigger := &desc.Triggers[i]
+		vea.Report(catalog.ValidateName(trigger.Name, "trigger"))
+		if trigger.ID == 0 || trigger.ID >= desc.NextTriggerID {
+			vea.Report(errors.AssertionFailedf("trigger %q has invalid ID %d", trigger.Name, trigger.ID))
+		}
+		if _, ok := ids[trigger.ID]; ok {
+			vea.Report(errors.AssertionFailedf("trigger %q has duplicate ID %d", trigger.Name, trigger.ID))
+		}
+		ids[trigger.ID] = struct{}{}
+		if i > 0 && desc.Triggers[i-1].Name >= trigger.Name {
+			vea.Report(errors.AssertionFailedf("triggers are not sorted by unique name at %q", trigger.Name))
+		}
+		if len(trigger.Events) == 0 {
+			vea.Report(errors.AssertionFailedf("trigger %q has no events", trigger.Name))
+		}
+		if trigger.FuncID == descpb.InvalidID {
+			vea.Report(errors.AssertionFailedf("trigger %q has no function", trigger.Name))
+		}
+		if len(trigger.FuncBody) == 0 {
+			vea.Report(errors.AssertionFailedf("trigger %q has an empty function body", trigger.Name))
+		}
+	}
+}
+
 func (desc *wrapper) validateConstraintIDs(vea catalog.ValidationErrorAccumulator) {
 	if !vea.IsActive(ConstraintIDsAddedToTableDescsVersion) {
 		return
@@ -1031,6 +1095,12 @@ func (desc *wrapper) validateUniqueWithoutIndexConstraints(
 			seen.Add(int(colID))
 		}
 
+		if c.InitiallyDeferred && !c.Deferrable {
+			return errors.Newf(
+				"unique without index constraint %q is initially deferred but not deferrable", c.Name,
+			)
+		}
+
 		if c.IsPartial() {
 			expr, err := parser.ParseExpr(c.Predicate)
 			if err != nil {
@@ -1058,6 +1128,40 @@ func (desc *wrapper) validateUniqueWithoutIndexConstraints(
 // IDs are unique, and the family of the primary key is 0. This does not check
 // if indexes are unique (i.e. same set of columns, direction, and uniqueness)
 // as there are practical uses for them.
+// validateExclusionIndex validates the EXCLUDE constraint backed by an index.
+func validateExclusionIndex(idx catalog.Index) error {
+	if idx.Primary() || idx.IsUnique() {
+		return errors.Newf("EXCLUDE constraint %q must be backed by a non-unique secondary index",
+			idx.GetName())
+	}
+	if idx.NumKeyColumns() != len(idx.IndexDesc().ExclusionOperators) {
+		return errors.Newf("EXCLUDE constraint %q has %d operators but %d key columns",
+			idx.GetName(), len(idx.IndexDesc().ExclusionOperators), idx.NumKeyColumns())
+	}
+	if idx.GetPartitioning().NumImplicitColumns() > 0 {
+		return errors.Newf("EXCLUDE constraint %q cannot be implicitly partitioned", idx.GetName())
+	}
+	for i := 0; i < idx.NumKeyColumns(); i++ {
+		// Only the inverted column of an inverted index can be compared with &&.
+		isInverted := idx.GetType() == descpb.IndexDescriptor_INVERTED && i == idx.NumKeyColumns()-1
+		switch op := idx.GetExclusionOperator(i); op {
+		case "=":
+			if isInverted {
+				return errors.Newf("EXCLUDE constraint %q must compare inverted column %q with &&",
+					idx.GetName(), idx.GetKeyColumnName(i))
+			}
+		case "&&":
+			if !isInverted {
+				return errors.Newf("EXCLUDE constraint %q compares non-inverted column %q with &&",
+					idx.GetName(), idx.GetKeyColumnName(i))
+			}
+		default:
+			return errors.Newf("EXCLUDE constraint %q has invalid operator %q", idx.GetName(), op)
+		}
+	}
+	return nil
+}
+
 func (desc *wrapper) validateTableIndexes(
 	columnNames map[string]descpb.ColumnID, vea catalog.ValidationErrorAccumulator,
 ) error {
@@ -1208,6 +1312,12 @@ func (desc *wrapper) validateTableIndexes(
 			}
 		}
 
+		if idx.IsExclusion() {
+			if err := validateExclusionIndex(idx); err != nil {
+				return err
+			}
+		}
+
 		if !idx.IsMutation() {
 			if idx.IndexDesc().UseDeletePreservingEncoding {
 				return errors.Newf("public index %q is using the delete preserving encoding", idx.GetName())
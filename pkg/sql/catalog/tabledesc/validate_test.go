@@ -131,6 +131,8 @@ var validationMap = []struct {
 			"DeclarativeSchemaChangerState": {status: iSolemnlySwearThisFieldIsValidated},
 			"AutoStatsSettings":             {status: iSolemnlySwearThisFieldIsValidated},
 			"ForecastStats":                 {status: thisFieldReferencesNoObjects},
+			"Triggers":                      {status: iSolemnlySwearThisFieldIsValidated},
+			"NextTriggerID":                 {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
@@ -173,6 +175,7 @@ var validationMap = []struct {
 			"UseDeletePreservingEncoding": {status: thisFieldReferencesNoObjects},
 			"ConstraintID":                {status: iSolemnlySwearThisFieldIsValidated},
 			"CreatedAtNanos":              {status: thisFieldReferencesNoObjects},
+			"ExclusionOperators":          {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
@@ -222,17 +225,21 @@ var validationMap = []struct {
 			"OnUpdate":          {status: thisFieldReferencesNoObjects},
 			"Match":             {status: thisFieldReferencesNoObjects},
 			"ConstraintID":      {status: iSolemnlySwearThisFieldIsValidated},
+			"Deferrable":        {status: thisFieldReferencesNoObjects},
+			"InitiallyDeferred": {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
 		obj: descpb.UniqueWithoutIndexConstraint{},
 		fieldMap: map[string]validationStatusInfo{
-			"TableID":      {status: iSolemnlySwearThisFieldIsValidated},
-			"ColumnIDs":    {status: iSolemnlySwearThisFieldIsValidated},
-			"Name":         {status: thisFieldReferencesNoObjects},
-			"Validity":     {status: thisFieldReferencesNoObjects},
-			"Predicate":    {status: iSolemnlySwearThisFieldIsValidated},
-			"ConstraintID": {status: iSolemnlySwearThisFieldIsValidated},
+			"TableID":           {status: iSolemnlySwearThisFieldIsValidated},
+			"ColumnIDs":         {status: iSolemnlySwearThisFieldIsValidated},
+			"Name":              {status: thisFieldReferencesNoObjects},
+			"Validity":          {status: thisFieldReferencesNoObjects},
+			"Predicate":         {status: iSolemnlySwearThisFieldIsValidated},
+			"ConstraintID":      {status: iSolemnlySwearThisFieldIsValidated},
+			"Deferrable":        {status: thisFieldReferencesNoObjects},
+			"InitiallyDeferred": {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
@@ -272,6 +279,7 @@ var validationMap = []struct {
 			"RegionConfig":                  {status: iSolemnlySwearThisFieldIsValidated},
 			"DefaultPrivileges":             {status: iSolemnlySwearThisFieldIsValidated},
 			"DeclarativeSchemaChangerState": {status: thisFieldReferencesNoObjects},
+			"Publications":                  {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
@@ -288,6 +296,7 @@ var validationMap = []struct {
 			"Privileges":                    {status: iSolemnlySwearThisFieldIsValidated},
 			"DefaultPrivileges":             {status: iSolemnlySwearThisFieldIsValidated},
 			"DeclarativeSchemaChangerState": {status: thisFieldReferencesNoObjects},
+			"Functions":                     {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
@@ -143,6 +143,11 @@ func (v TableImplicitRecordType) DescriptorType() catalog.DescriptorType {
 	return catalog.Type
 }
 
+// SkipNamespace implements the Descriptor interface.
+func (v TableImplicitRecordType) SkipNamespace() bool {
+	return false
+}
+
 // GetAuditMode implements the Descriptor interface.
 func (v TableImplicitRecordType) GetAuditMode() descpb.TableDescriptor_AuditMode {
 	return descpb.TableDescriptor_DISABLED
@@ -128,7 +128,7 @@ func UserDefinedTypeOIDToID(oid oid.Oid) (descpb.ID, error) {
 
 // GetUserDefinedTypeDescID gets the type descriptor ID from a user defined type.
 func GetUserDefinedTypeDescID(t *types.T) (descpb.ID, error) {
-	return UserDefinedTypeOIDToID(t.Oid())
+	return UserDefinedTypeOIDToID(t.UserDefinedOID())
 }
 
 // GetUserDefinedArrayTypeDescID gets the ID of the array type descriptor from a user
@@ -306,6 +306,11 @@ func (desc *immutable) DescriptorType() catalog.DescriptorType {
 	return catalog.Type
 }
 
+// SkipNamespace implements the catalog.Descriptor interface.
+func (desc *immutable) SkipNamespace() bool {
+	return false
+}
+
 // MaybeIncrementVersion implements the MutableDescriptor interface.
 func (desc *Mutable) MaybeIncrementVersion() {
 	// Already incremented, no-op.
@@ -551,6 +556,14 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 		if desc.GetArrayTypeID() != descpb.InvalidID {
 			vea.Report(errors.AssertionFailedf("ALIAS type desc has array type ID %d", desc.GetArrayTypeID()))
 		}
+	case descpb.TypeDescriptor_DOMAIN:
+		if desc.RegionConfig != nil {
+			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
+		}
+		if desc.GetArrayTypeID() != descpb.InvalidID {
+			vea.Report(errors.AssertionFailedf("DOMAIN type desc has array type ID %d", desc.GetArrayTypeID()))
+		}
+		desc.validateDomain(vea)
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		vea.Report(errors.AssertionFailedf("invalid type descriptor: kind %s should never be serialized or validated", desc.Kind.String()))
 	default:
@@ -688,6 +701,31 @@ func (desc *immutable) ValidateCrossReferences(
 	}
 }
 
+// validateDomain validates the base type and the constraints of a domain.
+func (desc *immutable) validateDomain(vea catalog.ValidationErrorAccumulator) {
+	if desc.Domain == nil {
+		vea.Report(errors.AssertionFailedf("DOMAIN type desc has no domain"))
+		return
+	}
+	if desc.Domain.BaseType == nil {
+		vea.Report(errors.AssertionFailedf("DOMAIN type desc has nil base type"))
+	} else if desc.Domain.BaseType.UserDefined() {
+		vea.Report(errors.AssertionFailedf("DOMAIN type desc has user-defined base type %s",
+			desc.Domain.BaseType.SQLString()))
+	}
+	names := make(map[string]struct{}, len(desc.Domain.Checks))
+	for _, check := range desc.Domain.Checks {
+		if check.Name == "" {
+			vea.Report(errors.AssertionFailedf("domain check constraint %q has no name", check.Expr))
+			continue
+		}
+		if _, ok := names[check.Name]; ok {
+			vea.Report(errors.AssertionFailedf("duplicate domain check constraint name %q", check.Name))
+		}
+		names[check.Name] = struct{}{}
+	}
+}
+
 func (desc *immutable) validateMultiRegion(
 	dbDesc catalog.DatabaseDescriptor, vea catalog.ValidationErrorAccumulator,
 ) {
@@ -794,6 +832,12 @@ func (desc *immutable) MakeTypesT(
 			return nil, err
 		}
 		return desc.Alias, nil
+	case descpb.TypeDescriptor_DOMAIN:
+		typ := types.MakeDomain(desc.Domain.BaseType, catid.TypeIDToOID(desc.GetID()))
+		if err := desc.HydrateTypeInfoWithName(ctx, typ, name, res); err != nil {
+			return nil, err
+		}
+		return typ, nil
 	default:
 		return nil, errors.AssertionFailedf("unknown type kind %s", t.String())
 	}
@@ -886,6 +930,23 @@ func (desc *immutable) HydrateTypeInfoWithName(
 			}
 		}
 		return nil
+	case descpb.TypeDescriptor_DOMAIN:
+		if !typ.IsDomain() {
+			return errors.New("cannot hydrate a non-domain type with a domain type descriptor")
+		}
+		domain := &types.DomainMetadata{
+			NotNull:     desc.Domain.NotNull,
+			DefaultExpr: desc.Domain.DefaultExpr,
+			Checks:      make([]types.DomainCheck, len(desc.Domain.Checks)),
+		}
+		for i := range desc.Domain.Checks {
+			domain.Checks[i] = types.DomainCheck{
+				Name: desc.Domain.Checks[i].Name,
+				Expr: desc.Domain.Checks[i].Expr,
+			}
+		}
+		typ.TypeMeta.DomainData = domain
+		return nil
 	default:
 		return errors.AssertionFailedf("unknown type descriptor kind %s", desc.Kind)
 	}
@@ -954,6 +1015,16 @@ func (desc *immutable) IsCompatibleWith(other catalog.TypeDescriptor) error {
 			}
 		}
 		return nil
+	case descpb.TypeDescriptor_DOMAIN:
+		if other.GetKind() != desc.Kind {
+			return errors.Newf("%q of type %q is not compatible with type %q",
+				other.GetName(), other.GetKind(), desc.Kind)
+		}
+		// The base type and the constraints of the domains must be the same.
+		if !desc.Domain.Equal(other.TypeDesc().Domain) {
+			return errors.Newf("%q has a differing definition", other.GetName())
+		}
+		return nil
 	default:
 		return errors.Newf("compatibility comparison unsupported for type kind %s", desc.Kind.String())
 	}
@@ -1008,8 +1079,8 @@ func (desc *immutable) GetIDClosure() (map[descpb.ID]struct{}, error) {
 		for id := range children {
 			ret[id] = struct{}{}
 		}
-	} else {
-		// Otherwise, take the array type ID.
+	} else if desc.Kind != descpb.TypeDescriptor_DOMAIN {
+		// Otherwise, take the array type ID. Domains do not have array types.
 		ret[desc.ArrayTypeID] = struct{}{}
 	}
 	return ret, nil
@@ -1029,6 +1100,11 @@ func GetTypeDescriptorClosure(typ *types.T) (map[descpb.ID]struct{}, error) {
 	ret := map[descpb.ID]struct{}{
 		id: {},
 	}
+	if typ.IsDomain() {
+		// Domains do not have array types, and their base types are not
+		// user-defined.
+		return ret, nil
+	}
 	switch typ.Family() {
 	case types.ArrayFamily:
 		// If we have an array type, then collect all types in the contents.
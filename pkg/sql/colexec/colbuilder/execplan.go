@@ -201,6 +201,9 @@ func supportedNatively(spec *execinfrapb.ProcessorSpec) error {
 	case spec.Core.Ordinality != nil:
 		return nil
 
+	case spec.Core.Expand != nil:
+		return nil
+
 	case spec.Core.HashJoiner != nil:
 		if !spec.Core.HashJoiner.OnExpr.Empty() && spec.Core.HashJoiner.Type != descpb.InnerJoin {
 			return errors.Newf("can't plan vectorized non-inner hash joins with ON expressions")
@@ -305,6 +308,7 @@ func canWrap(mode sessiondatapb.VectorizeExecMode, spec *execinfrapb.ProcessorSp
 		// (#55408), so we fallback to the row-by-row engine.
 		return errChangeFrontierWrap
 	case spec.Core.Ordinality != nil:
+	case spec.Core.Expand != nil:
 	case spec.Core.BulkRowWriter != nil:
 	case spec.Core.InvertedFilterer != nil:
 	case spec.Core.InvertedJoiner != nil:
@@ -1036,6 +1040,14 @@ func NewColOperator(
 			)
 			result.ColumnTypes = appendOneType(spec.Input[0].ColumnTypes, types.Int)
 
+		case core.Expand != nil:
+			if err := checkNumIn(inputs, 1); err != nil {
+				return r, err
+			}
+			result.Root, result.ColumnTypes = colexec.NewExpandOp(
+				getStreamingAllocator(ctx, args), inputs[0].Root, spec.Input[0].ColumnTypes, core.Expand,
+			)
+
 		case core.HashJoiner != nil:
 			if err := checkNumIn(inputs, 2); err != nil {
 				return r, err
@@ -0,0 +1,127 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/colmem"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// expandOp is an operator that implements GROUPING SETS, ROLLUP and CUBE. For
+// every input batch it emits one output batch per grouping set. Each output
+// batch contains the input columns, followed by a copy of the grouping columns
+// (with the columns that are not part of the grouping set replaced by NULLs)
+// and by the ordinal of the grouping set.
+type expandOp struct {
+	colexecop.OneInputHelper
+
+	allocator    *colmem.Allocator
+	numInputCols int
+	outputTypes  []*types.T
+	groupingCols []uint32
+	// inGroupingSet[i][j] is true if the j-th grouping column is part of the
+	// i-th grouping set.
+	inGroupingSet [][]bool
+
+	// batch is the input batch currently being expanded, and nextSet is the
+	// ordinal of the next grouping set to emit for it.
+	batch   coldata.Batch
+	nextSet int
+	output  coldata.Batch
+}
+
+var _ colexecop.Operator = &expandOp{}
+
+// NewExpandOp returns a new expand operator.
+func NewExpandOp(
+	allocator *colmem.Allocator,
+	input colexecop.Operator,
+	inputTypes []*types.T,
+	spec *execinfrapb.ExpandSpec,
+) (colexecop.Operator, []*types.T) {
+	outputTypes := make([]*types.T, 0, len(inputTypes)+len(spec.GroupingColumns)+1)
+	outputTypes = append(outputTypes, inputTypes...)
+	for _, col := range spec.GroupingColumns {
+		outputTypes = append(outputTypes, inputTypes[col])
+	}
+	outputTypes = append(outputTypes, types.Int)
+	inGroupingSet := make([][]bool, len(spec.GroupingSets))
+	for i, set := range spec.GroupingSets {
+		inGroupingSet[i] = make([]bool, len(spec.GroupingColumns))
+		for _, idx := range set.Columns {
+			inGroupingSet[i][idx] = true
+		}
+	}
+	return &expandOp{
+		OneInputHelper: colexecop.MakeOneInputHelper(input),
+		allocator:      allocator,
+		numInputCols:   len(inputTypes),
+		outputTypes:    outputTypes,
+		groupingCols:   spec.GroupingColumns,
+		inGroupingSet:  inGroupingSet,
+	}, outputTypes
+}
+
+func (e *expandOp) Next() coldata.Batch {
+	if e.batch == nil || e.nextSet == len(e.inGroupingSet) {
+		e.batch = e.Input.Next()
+		e.nextSet = 0
+	}
+	n := e.batch.Length()
+	if n == 0 {
+		return coldata.ZeroBatch
+	}
+	set := e.nextSet
+	e.nextSet++
+
+	sel := e.batch.Selection()
+	// We don't limit the batches based on the memory footprint because the
+	// output batches have the same length as the input ones.
+	const maxBatchMemSize = math.MaxInt64
+	e.output, _ = e.allocator.ResetMaybeReallocate(
+		e.outputTypes, e.output, n, maxBatchMemSize,
+		true, /* desiredCapacitySufficient */
+	)
+	e.allocator.PerformOperation(e.output.ColVecs(), func() {
+		for i := 0; i < e.numInputCols; i++ {
+			e.output.ColVec(i).Copy(coldata.SliceArgs{
+				Src:       e.batch.ColVec(i),
+				Sel:       sel,
+				SrcEndIdx: n,
+			})
+		}
+		for i, col := range e.groupingCols {
+			outVec := e.output.ColVec(e.numInputCols + i)
+			if e.inGroupingSet[set][i] {
+				outVec.Copy(coldata.SliceArgs{
+					Src:       e.batch.ColVec(int(col)),
+					Sel:       sel,
+					SrcEndIdx: n,
+				})
+			} else {
+				outVec.Nulls().SetNullRange(0 /* startIdx */, n)
+			}
+		}
+		setIDs := e.output.ColVec(len(e.outputTypes) - 1).Int64()
+		// Bounds check elimination.
+		setIDs = setIDs[:n]
+		for i := range setIDs {
+			setIDs[i] = int64(set)
+		}
+		e.output.SetLength(n)
+	})
+	return e.output
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colexec
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/colexec/colexectestutils"
+	"github.com/cockroachdb/cockroach/pkg/sql/colexecop"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+func TestExpand(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	tcs := []struct {
+		spec     execinfrapb.ExpandSpec
+		tuples   colexectestutils.Tuples
+		expected colexectestutils.Tuples
+	}{
+		{
+			// ROLLUP (@1, @2).
+			spec: execinfrapb.ExpandSpec{
+				GroupingColumns: []uint32{0, 1},
+				GroupingSets: []execinfrapb.ExpandSpec_GroupingSet{
+					{Columns: []uint32{0, 1}},
+					{Columns: []uint32{0}},
+					{},
+				},
+			},
+			tuples: colexectestutils.Tuples{{1, 2}, {3, nil}},
+			expected: colexectestutils.Tuples{
+				{1, 2, 1, 2, 0},
+				{1, 2, 1, nil, 1},
+				{1, 2, nil, nil, 2},
+				{3, nil, 3, nil, 0},
+				{3, nil, 3, nil, 1},
+				{3, nil, nil, nil, 2},
+			},
+		},
+		{
+			// GROUPING SETS ((@2), (@1)), with the grouping columns in a
+			// different order than the input columns.
+			spec: execinfrapb.ExpandSpec{
+				GroupingColumns: []uint32{1, 0},
+				GroupingSets: []execinfrapb.ExpandSpec_GroupingSet{
+					{Columns: []uint32{0}},
+					{Columns: []uint32{1}},
+				},
+			},
+			tuples: colexectestutils.Tuples{{5, 6}, {7, 8}},
+			expected: colexectestutils.Tuples{
+				{5, 6, 6, nil, 0},
+				{5, 6, nil, 5, 1},
+				{7, 8, 8, nil, 0},
+				{7, 8, nil, 7, 1},
+			},
+		},
+		{
+			spec: execinfrapb.ExpandSpec{
+				GroupingColumns: []uint32{0},
+				GroupingSets: []execinfrapb.ExpandSpec_GroupingSet{
+					{Columns: []uint32{0}},
+				},
+			},
+			tuples:   colexectestutils.Tuples{},
+			expected: colexectestutils.Tuples{},
+		},
+	}
+
+	typs := []*types.T{types.Int, types.Int}
+	for _, tc := range tcs {
+		colexectestutils.RunTestsWithoutAllNullsInjection(t, testAllocator, []colexectestutils.Tuples{tc.tuples}, [][]*types.T{typs}, tc.expected, colexectestutils.UnorderedVerifier, func(input []colexecop.Operator) (colexecop.Operator, error) {
+			op, _ := NewExpandOp(testAllocator, input[0], typs, &tc.spec)
+			return op, nil
+		})
+	}
+}
@@ -921,6 +921,7 @@ func (s *Server) newConnExecutor(
 		portals:   make(map[string]PreparedPortal),
 	}
 	ex.extraTxnState.prepStmtsNamespaceMemAcc = ex.sessionMon.MakeBoundAccount()
+	ex.extraTxnState.deferredConstraints.init(ex.sessionMon.MakeBoundAccount())
 	ex.extraTxnState.descCollection = s.cfg.CollectionFactory.MakeCollection(ctx, descs.NewTemporarySchemaProvider(sdMutIterator.sds), ex.sessionMon)
 	ex.extraTxnState.txnRewindPos = -1
 	ex.extraTxnState.schemaChangeJobRecords = make(map[descpb.ID]*jobs.Record)
@@ -995,6 +996,7 @@ func (s *Server) newConnExecutorWithTxn(
 		txn.ReadTimestamp().GoTime(),
 		nil, /* historicalTimestamp */
 		roachpb.UnspecifiedUserPriority,
+		kv.Serializable,
 		tree.ReadWrite,
 		txn,
 		ex.transitionCtx,
@@ -1124,6 +1126,7 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 			ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc,
 		)
 		ex.extraTxnState.prepStmtsNamespaceMemAcc.Close(ctx)
+		ex.extraTxnState.deferredConstraints.close(ctx)
 		ex.extraTxnState.sqlCursors.closeAll()
 	}
 
@@ -1352,6 +1355,10 @@ type connExecutor struct {
 
 		schemaChangerState SchemaChangerState
 
+		// deferredConstraints tracks the violations of deferrable constraints
+		// whose checks are postponed until the transaction commits.
+		deferredConstraints DeferredConstraintState
+
 		// shouldCollectTxnExecutionStats specifies whether the statements in
 		// this transaction should collect execution stats.
 		shouldCollectTxnExecutionStats bool
@@ -1635,6 +1642,7 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 	ex.extraTxnState.schemaChangerState = SchemaChangerState{
 		mode: ex.sessionData().NewSchemaChangerMode,
 	}
+	ex.extraTxnState.deferredConstraints.reset(ctx)
 
 	for k := range ex.extraTxnState.schemaChangeJobRecords {
 		delete(ex.extraTxnState.schemaChangeJobRecords, k)
@@ -2564,9 +2572,14 @@ func (ex *connExecutor) setTransactionModes(
 			return err
 		}
 	}
-	if modes.Isolation != tree.UnspecifiedIsolation && modes.Isolation != tree.SerializableIsolation {
-		return errors.AssertionFailedf(
-			"unknown isolation level: %s", errors.Safe(modes.Isolation))
+	if modes.Isolation != tree.UnspecifiedIsolation {
+		level, err := ex.txnIsolationLevelToKV(modes.Isolation)
+		if err != nil {
+			return err
+		}
+		if err := ex.state.setIsolationLevel(level); err != nil {
+			return err
+		}
 	}
 	rwMode := modes.ReadWriteMode
 	if modes.AsOf.Expr != nil && asOfTs.IsEmpty() {
@@ -2610,6 +2623,29 @@ func (ex *connExecutor) txnPriorityWithSessionDefault(mode tree.UserPriority) ro
 	return txnPriorityToProto(mode)
 }
 
+// txnIsolationLevelToKV maps the given isolation level to the isolation level
+// of the KV transaction, using the session's default if it is unspecified.
+// READ COMMITTED transactions are upgraded to SERIALIZABLE unless they are
+// allowed by the sql.txn.read_committed_isolation.enabled cluster setting.
+func (ex *connExecutor) txnIsolationLevelToKV(
+	level tree.IsolationLevel,
+) (kv.IsolationLevel, error) {
+	if level == tree.UnspecifiedIsolation {
+		level = tree.IsolationLevel(ex.sessionData().DefaultTxnIsolationLevel)
+	}
+	switch level {
+	case tree.ReadCommittedIsolation:
+		if allowReadCommittedIsolation.Get(&ex.server.cfg.Settings.SV) {
+			return kv.ReadCommitted, nil
+		}
+		return kv.Serializable, nil
+	case tree.UnspecifiedIsolation, tree.SerializableIsolation:
+		return kv.Serializable, nil
+	default:
+		return kv.Serializable, errors.AssertionFailedf("unknown isolation level: %s", level)
+	}
+}
+
 // QualityOfService returns the QoSLevel session setting if the session
 // settings are populated, otherwise the default QoSLevel.
 func (ex *connExecutor) QualityOfService() sessiondatapb.QoSLevel {
@@ -2717,6 +2753,12 @@ func (ex *connExecutor) resetEvalCtx(evalCtx *extendedEvalContext, txn *kv.Txn,
 	evalCtx.PrepareOnly = false
 	evalCtx.SkipNormalize = false
 	evalCtx.SchemaChangerState = &ex.extraTxnState.schemaChangerState
+	// Internal executors never commit the transactions they run in, so they
+	// can't defer constraint checks.
+	evalCtx.DeferredConstraints = nil
+	if ex.executorType != executorTypeInternal {
+		evalCtx.DeferredConstraints = &ex.extraTxnState.deferredConstraints
+	}
 
 	// If we are retrying due to an unsatisfiable timestamp bound which is
 	// retriable, it means we were unable to serve the previous minimum timestamp
@@ -2785,7 +2827,7 @@ func (ex *connExecutor) resetPlanner(
 	p.cancelChecker.Reset(ctx)
 
 	p.semaCtx = tree.MakeSemaContext()
-	p.semaCtx.SearchPath = &ex.sessionData().SearchPath
+	p.semaCtx.SearchPath = makeFunctionResolver(&ex.sessionData().SearchPath, p)
 	p.semaCtx.Annotations = nil
 	p.semaCtx.TypeResolver = p
 	p.semaCtx.TableNameResolver = p
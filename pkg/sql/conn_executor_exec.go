@@ -662,6 +662,14 @@ func (ex *connExecutor) execStmtInOpenState(
 		return makeErrEvent(err)
 	}
 
+	// READ COMMITTED transactions read from a new snapshot for every statement,
+	// which is established here.
+	if ex.usesReadCommittedStmtSnapshots() {
+		if err := ex.state.mu.txn.StepReadTimestamp(ctx); err != nil {
+			return makeErrEvent(err)
+		}
+	}
+
 	if err := p.semaCtx.Placeholders.Assign(pinfo, stmt.NumPlaceholders); err != nil {
 		return makeErrEvent(err)
 	}
@@ -685,7 +693,12 @@ func (ex *connExecutor) execStmtInOpenState(
 		stmtCtx = ctx
 	}
 
-	if err := ex.dispatchToExecutionEngine(stmtCtx, p, res); err != nil {
+	if !os.ImplicitTxn.Get() && ex.usesReadCommittedStmtSnapshots() {
+		if err := ex.dispatchReadCommittedStmtToExecutionEngine(stmtCtx, p, res); err != nil {
+			stmtThresholdSpan.Finish()
+			return nil, nil, err
+		}
+	} else if err := ex.dispatchToExecutionEngine(stmtCtx, p, res); err != nil {
 		stmtThresholdSpan.Finish()
 		return nil, nil, err
 	}
@@ -941,6 +954,15 @@ func (ex *connExecutor) commitSQLTransactionInternal(ctx context.Context) error
 	ctx, sp := tracing.EnsureChildSpan(ctx, ex.server.cfg.AmbientCtx.Tracer, "commit sql txn")
 	defer sp.Finish()
 
+	if pending := ex.extraTxnState.deferredConstraints.takeAll(); len(pending) > 0 {
+		if err := validateDeferredChecks(
+			ctx, ex.state.mu.txn, &ex.extraTxnState.descCollection, ex.server.cfg.InternalExecutor, pending,
+		); err != nil {
+			return err
+		}
+		ex.extraTxnState.deferredConstraints.release(ctx, pending)
+	}
+
 	if err := ex.createJobs(ctx); err != nil {
 		return err
 	}
@@ -1008,6 +1030,84 @@ func (ex *connExecutor) rollbackSQLTransaction(
 	return eventTxnFinishAborted{}, nil
 }
 
+// usesReadCommittedStmtSnapshots returns whether the statements of the current
+// transaction each read from their own snapshot, which is the case for READ
+// COMMITTED transactions. Transactions with a fixed commit timestamp (for
+// example because of AS OF SYSTEM TIME) and the transactions of the internal
+// executor always read from the same snapshot.
+func (ex *connExecutor) usesReadCommittedStmtSnapshots() bool {
+	txn := ex.state.mu.txn
+	return txn.IsoLevel() == kv.ReadCommitted &&
+		!txn.CommitTimestampFixed() &&
+		ex.executorType != executorTypeInternal
+}
+
+// dispatchReadCommittedStmtToExecutionEngine is like dispatchToExecutionEngine,
+// but it is used for the statements of explicit READ COMMITTED transactions.
+// If the statement encounters a retryable error which doesn't require the
+// whole transaction to be restarted (for example a write-write conflict or a
+// failed read refresh), the statement is retried from a savepoint taken before
+// its execution, at a new read snapshot, instead of surfacing the error to the
+// client. The number of retries is bounded by the
+// max_retries_for_read_committed session variable.
+func (ex *connExecutor) dispatchReadCommittedStmtToExecutionEngine(
+	ctx context.Context, p *planner, res RestrictedCommandResult,
+) error {
+	txn := ex.state.mu.txn
+	savepoint, err := txn.CreateSavepoint(ctx)
+	if err != nil {
+		return err
+	}
+	epoch := txn.Epoch()
+	numDDL := ex.extraTxnState.numDDL
+	maxRetries := int(ex.sessionData().MaxRetriesForReadCommitted)
+	for attempt := 0; ; attempt++ {
+		bufferPos := res.BufferedResultsLen()
+		if err := ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
+			return err
+		}
+		stmtErr := res.Err()
+		if stmtErr == nil {
+			return txn.ReleaseSavepoint(ctx, savepoint)
+		}
+		// Only retryable errors which keep the transaction's writes can be
+		// retried at the statement level. Errors which restarted or aborted the
+		// transaction need to be handled like in SERIALIZABLE transactions. We
+		// also can't roll back over DDL.
+		var retryErr *roachpb.TransactionRetryWithProtoRefreshError
+		if !errors.As(stmtErr, &retryErr) || retryErr.PrevTxnAborted() ||
+			txn.Epoch() != epoch || ex.extraTxnState.numDDL != numDDL {
+			return nil
+		}
+		if attempt >= maxRetries {
+			res.SetError(errors.Wrapf(stmtErr,
+				"read committed retry limit exceeded; set by max_retries_for_read_committed=%d",
+				maxRetries))
+			return nil
+		}
+		// The results of the failed attempt need to be discarded before the
+		// statement can be retried.
+		if !res.TruncateBufferedResults(bufferPos) {
+			return nil
+		}
+		log.VEventf(ctx, 2, "retrying READ COMMITTED statement after error: %v", stmtErr)
+		res.SetError(nil)
+		if err := txn.RollbackToSavepoint(ctx, savepoint); err != nil {
+			return err
+		}
+		if err := txn.PrepareForPartialRetry(ctx); err != nil {
+			return err
+		}
+		if err := txn.StepReadTimestamp(ctx); err != nil {
+			return err
+		}
+		ex.state.mu.Lock()
+		ex.state.mu.autoRetryCounter++
+		ex.state.mu.autoRetryReason = stmtErr
+		ex.state.mu.Unlock()
+	}
+}
+
 // dispatchToExecutionEngine executes the statement, writes the result to res
 // and returns an event for the connection's state machine.
 //
@@ -1567,10 +1667,15 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		if err != nil {
 			return ex.makeErrEvent(err, s)
 		}
+		isoLevel, err := ex.txnIsolationLevelToKV(s.Modes.Isolation)
+		if err != nil {
+			return ex.makeErrEvent(err, s)
+		}
 		ex.sessionDataStack.PushTopClone()
 		return eventStartExplicitTxn,
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(s.Modes.UserPriority),
+				isoLevel,
 				mode,
 				sqlTs,
 				historicalTs,
@@ -1589,9 +1694,14 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		if err != nil {
 			return ex.makeErrEvent(err, s)
 		}
+		isoLevel, err := ex.txnIsolationLevelToKV(tree.UnspecifiedIsolation)
+		if err != nil {
+			return ex.makeErrEvent(err, s)
+		}
 		return eventStartImplicitTxn,
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(tree.UnspecifiedUserPriority),
+				isoLevel,
 				mode,
 				sqlTs,
 				historicalTs,
@@ -1619,9 +1729,14 @@ func (ex *connExecutor) beginImplicitTxn(
 	if err != nil {
 		return ex.makeErrEvent(err, ast)
 	}
+	isoLevel, err := ex.txnIsolationLevelToKV(tree.UnspecifiedIsolation)
+	if err != nil {
+		return ex.makeErrEvent(err, ast)
+	}
 	return eventStartImplicitTxn,
 		makeEventTxnStartPayload(
 			ex.txnPriorityWithSessionDefault(tree.UnspecifiedUserPriority),
+			isoLevel,
 			mode,
 			sqlTs,
 			historicalTs,
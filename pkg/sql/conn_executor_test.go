@@ -1449,6 +1449,175 @@ func TestInjectRetryErrors(t *testing.T) {
 	})
 }
 
+// TestReadCommittedStmtRetry verifies that a statement of a READ COMMITTED
+// transaction which encounters a retryable error is retried, instead of
+// returning the error to the client, up to max_retries_for_read_committed
+// times.
+func TestReadCommittedStmtRetry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	filter := newDynamicRequestFilter()
+	params := base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			Store: &kvserver.StoreTestingKnobs{
+				TestingRequestFilter: filter.filter,
+			},
+		},
+	}
+	s, db, _ := serverutils.StartServer(t, params)
+	defer s.Stopper().Stop(ctx)
+	defer db.Close()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, "CREATE TABLE kv (k INT PRIMARY KEY, v INT)")
+	sqlDB.Exec(t, "INSERT INTO kv VALUES (1, 0)")
+	var tableID descpb.ID
+	sqlDB.QueryRow(t, "SELECT 'kv'::REGCLASS::INT").Scan(&tableID)
+	execCfg := s.ExecutorConfig().(sql.ExecutorConfig)
+	tablePrefix := execCfg.Codec.TablePrefix(uint32(tableID))
+	tableSpan := roachpb.Span{Key: tablePrefix, EndKey: tablePrefix.PrefixEnd()}
+
+	// incrementWithRetryErrors increments the row from a READ COMMITTED
+	// transaction, while injecting the given number of retryable errors into
+	// its writes. It returns the error of the increment.
+	incrementWithRetryErrors := func(t *testing.T, numErrors int64, maxRetries int) error {
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.ExecContext(ctx, fmt.Sprintf("SET max_retries_for_read_committed = %d", maxRetries))
+		require.NoError(t, err)
+
+		tx, err := conn.BeginTx(ctx, &gosql.TxOptions{Isolation: gosql.LevelReadCommitted})
+		require.NoError(t, err)
+		var v int
+		require.NoError(t, tx.QueryRowContext(ctx, "SELECT v FROM kv WHERE k = 1").Scan(&v))
+
+		filter.setFilter(func(_ context.Context, ba roachpb.BatchRequest) *roachpb.Error {
+			if ba.Txn == nil {
+				return nil
+			}
+			put, ok := ba.GetArg(roachpb.Put)
+			if !ok || !tableSpan.ContainsKey(put.Header().Key) {
+				return nil
+			}
+			if atomic.AddInt64(&numErrors, -1) < 0 {
+				return nil
+			}
+			return roachpb.NewErrorWithTxn(roachpb.NewTransactionRetryError(
+				roachpb.RETRY_REASON_UNKNOWN, "injected by test"), ba.Txn)
+		})
+		defer filter.setFilter(nil)
+
+		if _, err := tx.ExecContext(ctx, "UPDATE kv SET v = v + 1 WHERE k = 1"); err != nil {
+			require.NoError(t, tx.Rollback())
+			return err
+		}
+		return tx.Commit()
+	}
+
+	t.Run("retry", func(t *testing.T) {
+		require.NoError(t, incrementWithRetryErrors(t, 3 /* numErrors */, 10 /* maxRetries */))
+		// The writes of the failed attempts were rolled back.
+		sqlDB.CheckQueryResults(t, "SELECT v FROM kv WHERE k = 1", [][]string{{"1"}})
+	})
+
+	t.Run("retry_limit_exceeded", func(t *testing.T) {
+		err := incrementWithRetryErrors(t, 3 /* numErrors */, 2 /* maxRetries */)
+		pqErr := (*pq.Error)(nil)
+		require.ErrorAs(t, err, &pqErr)
+		require.Equal(t, pgcode.SerializationFailure.String(), string(pqErr.Code))
+		require.Regexp(t, "read committed retry limit exceeded", pqErr.Message)
+		sqlDB.CheckQueryResults(t, "SELECT v FROM kv WHERE k = 1", [][]string{{"1"}})
+	})
+}
+
+// TestReadCommittedFKConcurrentParentDelete verifies that a parent row deleted
+// concurrently with the insert of a child row from a READ COMMITTED transaction
+// doesn't leave an orphaned child row behind, even if the delete read the child
+// table before the child row was inserted. The foreign key check of the insert
+// locks the parent row, so the delete observes the child row once the insert
+// commits.
+func TestReadCommittedFKConcurrentParentDelete(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	defer db.Close()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, "CREATE TABLE parent (p INT PRIMARY KEY)")
+	sqlDB.Exec(t, "CREATE TABLE child (c INT PRIMARY KEY, p INT REFERENCES parent (p))")
+
+	runReadCommitted := func(stmt string, args ...interface{}) error {
+		tx, err := db.BeginTx(ctx, &gosql.TxOptions{Isolation: gosql.LevelReadCommitted})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, stmt, args...); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		// Step the read timestamp before committing, which discards the
+		// refresh spans of the previous statement.
+		if _, err := tx.ExecContext(ctx, "SELECT count(*) FROM child"); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	}
+
+	for i, insertStmt := range []string{
+		// The insert fast path.
+		"INSERT INTO child VALUES ($1, $1)",
+		// The regular foreign key checks.
+		"INSERT INTO child SELECT $1, $1",
+	} {
+		t.Run(insertStmt, func(t *testing.T) {
+			p, other := 2*i, 2*i+1
+			sqlDB.Exec(t, "INSERT INTO parent VALUES ($1), ($2)", p, other)
+
+			// Lock the other parent row, so that the delete picks its read
+			// snapshot and then waits before reading the child table.
+			lockTx, err := db.BeginTx(ctx, nil /* opts */)
+			require.NoError(t, err)
+			_, err = lockTx.ExecContext(ctx, "SELECT * FROM parent WHERE p = $1 FOR UPDATE", other)
+			require.NoError(t, err)
+
+			deleteErrCh := make(chan error, 1)
+			go func() {
+				deleteErrCh <- runReadCommitted("DELETE FROM parent WHERE p IN ($1, $2)", p, other)
+			}()
+			testutils.SucceedsSoon(t, func() error {
+				var waiters int
+				sqlDB.QueryRow(t, `
+SELECT count(*) FROM crdb_internal.cluster_locks WHERE table_name = 'parent' AND NOT granted`,
+				).Scan(&waiters)
+				if waiters == 0 {
+					return errors.New("the delete is not waiting on the lock yet")
+				}
+				return nil
+			})
+
+			require.NoError(t, runReadCommitted(insertStmt, p))
+			require.NoError(t, lockTx.Commit())
+
+			deleteErr := <-deleteErrCh
+			pqErr := (*pq.Error)(nil)
+			require.ErrorAs(t, deleteErr, &pqErr)
+			require.Equal(t, pgcode.ForeignKeyViolation.String(), string(pqErr.Code), "%v", pqErr)
+		})
+	}
+
+	sqlDB.CheckQueryResults(t, `
+SELECT count(*) FROM child WHERE NOT EXISTS (SELECT 1 FROM parent WHERE parent.p = child.p)`,
+		[][]string{{"0"}},
+	)
+}
+
 func TestTrackOnlyUserOpenTransactionsAndActiveStatements(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
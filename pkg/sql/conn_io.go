@@ -752,6 +752,18 @@ type RestrictedCommandResult interface {
 	// to this CommandResult, will be flushed immediately to the client.
 	// This is currently used for sinkless changefeeds.
 	DisableBuffering()
+
+	// BufferedResultsLen returns the length of the results that have been
+	// buffered so far and not yet flushed to the client. It is used as a
+	// position to TruncateBufferedResults.
+	BufferedResultsLen() int
+
+	// TruncateBufferedResults discards the results buffered after the given
+	// position, along with the count of rows affected by the statement. It
+	// returns false if the results can't be discarded because some of them
+	// have already been flushed to the client. This is used to retry a
+	// statement of a READ COMMITTED transaction.
+	TruncateBufferedResults(idx int) bool
 }
 
 // DescribeResult represents the result of a Describe command (for either
@@ -951,6 +963,18 @@ func (r *streamingCommandResult) DisableBuffering() {
 	panic("cannot disable buffering here")
 }
 
+// BufferedResultsLen is part of the RestrictedCommandResult interface.
+func (r *streamingCommandResult) BufferedResultsLen() int {
+	// Results are streamed to the iterator as they are produced, so nothing
+	// is ever buffered.
+	return 0
+}
+
+// TruncateBufferedResults is part of the RestrictedCommandResult interface.
+func (r *streamingCommandResult) TruncateBufferedResults(int) bool {
+	return false
+}
+
 // SetError is part of the RestrictedCommandResult interface.
 func (r *streamingCommandResult) SetError(err error) {
 	r.err = err
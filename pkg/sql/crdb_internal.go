@@ -100,6 +100,7 @@ const CrdbInternalName = catconstants.CRDBInternalSchemaName
 var crdbInternal = virtualSchema{
 	name: CrdbInternalName,
 	tableDefs: map[descpb.ID]virtualSchemaDef{
+		catconstants.CrdbInternalBackupVerificationsTableID:         crdbInternalBackupVerificationsTable,
 		catconstants.CrdbInternalBackwardDependenciesTableID:        crdbInternalBackwardDependenciesTable,
 		catconstants.CrdbInternalBuildInfoTableID:                   crdbInternalBuildInfoTable,
 		catconstants.CrdbInternalBuiltinFunctionsTableID:            crdbInternalBuiltinFunctionsTable,
@@ -1024,6 +1025,84 @@ CREATE TABLE crdb_internal.jobs (
 	},
 }
 
+var crdbInternalBackupVerificationsTable = virtualSchemaTable{
+	comment: `results of backup verification jobs visible to the current user (KV scan)`,
+	schema: `
+CREATE TABLE crdb_internal.backup_verifications (
+  job_id         INT NOT NULL,
+  status         STRING NOT NULL,
+  description    STRING NOT NULL,
+  files_verified INT NOT NULL,
+  num_problems   INT NOT NULL,
+  problems       STRING[] NOT NULL
+)`,
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		isAdmin, err := p.HasAdminRole(ctx)
+		if err != nil {
+			return err
+		}
+		// Beware: we're querying system.jobs as root; we need to be careful to
+		// filter out the jobs that the current user is not able to see.
+		it, err := p.ExtendedEvalContext().ExecCfg.InternalExecutor.QueryIteratorEx(
+			ctx, "crdb-internal-backup-verifications-table", p.txn,
+			sessiondata.InternalExecutorOverride{User: username.RootUserName()},
+			`SELECT id, status, payload, progress FROM system.jobs ORDER BY id`)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := it.Close(); err != nil {
+				log.Warningf(ctx, "error closing an iterator: %v", err)
+			}
+		}()
+		for {
+			ok, err := it.Next(ctx)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+			r := it.Cur()
+			payload, err := jobs.UnmarshalPayload(r[2])
+			if err != nil || payload.Type() != jobspb.TypeBackupVerification {
+				continue
+			}
+			if !isAdmin && payload.UsernameProto.Decode() != p.User() {
+				continue
+			}
+			progress, err := jobs.UnmarshalProgress(r[3])
+			if err != nil {
+				return err
+			}
+			verification := progress.GetBackupVerification()
+			if verification == nil {
+				verification = &jobspb.BackupVerificationProgress{}
+			}
+			problems := tree.NewDArray(types.String)
+			for _, problem := range verification.Problems {
+				desc := problem.Layer
+				if problem.Path != "" {
+					desc += "/" + problem.Path
+				}
+				if err := problems.Append(tree.NewDString(desc + ": " + problem.Description)); err != nil {
+					return err
+				}
+			}
+			if err := addRow(
+				r[0],
+				r[1],
+				tree.NewDString(payload.Description),
+				tree.NewDInt(tree.DInt(verification.FilesVerified)),
+				tree.NewDInt(tree.DInt(verification.NumProblems)),
+				problems,
+			); err != nil {
+				return err
+			}
+		}
+	},
+}
+
 // execStatAvg is a helper for execution stats shown in virtual tables. Returns
 // NULL when the count is 0, or the mean of the given NumericStat.
 func execStatAvg(count int64, n roachpb.NumericStat) tree.Datum {
@@ -2439,6 +2518,24 @@ func writeCreateTypeDescRow(
 			tree.NewDString(tree.AsString(node)),      // create_statement
 			enumLabelsDatum,
 		)
+	case descpb.TypeDescriptor_DOMAIN:
+		name, err := tree.NewUnresolvedObjectName(2, [3]string{typeDesc.GetName(), sc}, 0)
+		if err != nil {
+			return false, err
+		}
+		node, err := makeCreateDomainStmt(name, typeDesc.TypeDesc().Domain)
+		if err != nil {
+			return false, err
+		}
+		return true, addRow(
+			tree.NewDInt(tree.DInt(db.GetID())),       // database_id
+			tree.NewDString(db.GetName()),             // database_name
+			tree.NewDString(sc),                       // schema_name
+			tree.NewDInt(tree.DInt(typeDesc.GetID())), // descriptor_id
+			tree.NewDString(typeDesc.GetName()),       // descriptor_name
+			tree.NewDString(tree.AsString(node)),      // create_statement
+			tree.DNull,                                // enum_members
+		)
 	case descpb.TypeDescriptor_MULTIREGION_ENUM:
 		// Multi-region enums are created implicitly, so we don't have create
 		// statements for them.
@@ -0,0 +1,371 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+type createAggregateNode struct {
+	n *tree.CreateAggregate
+}
+
+// CreateAggregate creates a user-defined aggregate function.
+// Privileges: CREATE on the schema.
+//   Notes: postgres also requires EXECUTE on the support functions.
+func (p *planner) CreateAggregate(ctx context.Context, n *tree.CreateAggregate) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE AGGREGATE",
+	); err != nil {
+		return nil, err
+	}
+	return &createAggregateNode{n: n}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE AGGREGATE performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createAggregateNode) ReadingOwnWrites() {}
+
+// aggregateOptions holds the validated options of a CREATE AGGREGATE
+// statement.
+type aggregateOptions struct {
+	stateFunc *tree.UnresolvedObjectName
+	stateType tree.ResolvableTypeReference
+	finalFunc *tree.UnresolvedObjectName
+	initCond  *string
+}
+
+func (n *createAggregateNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("aggregate"))
+
+	ctx := params.ctx
+	p := params.p
+	opts, err := validateAggregateOptions(n.n.Options)
+	if err != nil {
+		return err
+	}
+	db, sc, err := n.resolveSchema(params)
+	if err != nil {
+		return err
+	}
+	funcParams, argTypes, err := n.resolveParams(params)
+	if err != nil {
+		return err
+	}
+	if len(argTypes) == 0 {
+		return unimplemented.New("udf-aggregate-no-args",
+			"aggregates without arguments are not supported")
+	}
+	stateType, err := tree.ResolveType(ctx, opts.stateType, p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return err
+	}
+	if stateType.UserDefined() {
+		return unimplemented.New("udf-user-defined-type",
+			"user-defined types in function signatures are not supported")
+	}
+
+	// The state transition function takes the state followed by the arguments
+	// of the aggregate, and returns the next state.
+	stateFunc, err := p.lookupAggregateSupportFunction(
+		ctx, db, opts.stateFunc, append([]*types.T{stateType}, argTypes...),
+	)
+	if err != nil {
+		return err
+	}
+	if !stateFunc.GetReturnType().Type.Equivalent(stateType) {
+		return pgerror.Newf(pgcode.DatatypeMismatch,
+			"return type of transition function %s is not %s",
+			tree.Name(stateFunc.GetName()), stateType.SQLStandardName())
+	}
+	supportFuncs := []*funcdesc.Mutable{stateFunc}
+	returnType := stateType
+	var finalFunc *funcdesc.Mutable
+	if opts.finalFunc != nil {
+		finalFunc, err = p.lookupAggregateSupportFunction(ctx, db, opts.finalFunc, []*types.T{stateType})
+		if err != nil {
+			return err
+		}
+		supportFuncs = append(supportFuncs, finalFunc)
+		returnType = finalFunc.GetReturnType().Type
+	}
+	for _, fn := range supportFuncs {
+		if _, err := schemaexpr.CompileScalarFunctionBody(ctx, funcdesc.ToOverload(fn), &p.semaCtx); err != nil {
+			return errors.Wrapf(err, "cannot use function %s in an aggregate", tree.Name(fn.GetName()))
+		}
+	}
+
+	if opts.initCond != nil {
+		if _, _, err := tree.ParseAndRequireString(stateType, *opts.initCond, p.EvalContext()); err != nil {
+			return errors.Wrapf(err, "invalid initial value for aggregate state of type %s",
+				stateType.SQLStandardName())
+		}
+	} else if stateFunc.IsStrict() && !argTypes[0].Equivalent(stateType) {
+		// The first non-NULL input becomes the initial state of a strict
+		// transition function with a NULL initial state.
+		return pgerror.New(pgcode.InvalidFunctionDefinition,
+			"must not omit initial value when transition function is strict and transition type is not compatible with input type")
+	}
+
+	name := n.n.FuncName.Object()
+	existing, _ := sc.GetFunction(name)
+	for _, overload := range existing.Overloads {
+		if !overload.IsAggregate {
+			return pgerror.Newf(pgcode.DuplicateFunction,
+				"function %q already exists and is not an aggregate", name)
+		}
+		if funcArgTypesEquivalent(overload.ArgTypes, argTypes) {
+			return pgerror.Newf(pgcode.DuplicateFunction,
+				"function %q already exists with same argument types", name)
+		}
+	}
+
+	id, err := descidgen.GenerateUniqueDescID(ctx, params.ExecCfg().DB, params.ExecCfg().Codec)
+	if err != nil {
+		return err
+	}
+	privs := catpb.NewBasePrivilegeDescriptor(params.SessionData().User())
+	agg := funcdesc.NewMutableFunctionDescriptor(
+		id,
+		db.GetID(),
+		sc.GetID(),
+		name,
+		funcParams,
+		returnType,
+		false, /* returnSet */
+		privs,
+	)
+	aggDesc := descpb.FunctionDescriptor_Aggregate{
+		StateFuncID: stateFunc.GetID(),
+		StateType:   stateType,
+		InitCond:    opts.initCond,
+	}
+	if finalFunc != nil {
+		aggDesc.FinalFuncID = finalFunc.GetID()
+	}
+	agg.SetAggregate(aggDesc)
+
+	sc.AddFunction(name, descpb.SchemaDescriptor_FunctionOverload{
+		ID:          id,
+		ArgTypes:    argTypes,
+		ReturnType:  returnType,
+		IsAggregate: true,
+	})
+	if err := p.writeSchemaDesc(ctx, sc); err != nil {
+		return err
+	}
+	for _, fn := range supportFuncs {
+		fn.AddDependedOnByAggregate(id)
+		if err := p.writeFuncDesc(ctx, fn); err != nil {
+			return err
+		}
+	}
+	return p.createDescriptorWithID(
+		ctx,
+		nil, /* idKey */
+		id,
+		&agg,
+		tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+// validateAggregateOptions checks that the options of a CREATE AGGREGATE
+// statement do not conflict with each other, and that the required options
+// are set.
+func validateAggregateOptions(options tree.AggregateOptions) (aggregateOptions, error) {
+	var ret aggregateOptions
+	var seenStateFunc, seenStateType, seenFinalFunc, seenInitCond bool
+	checkSeen := func(seen *bool) error {
+		if *seen {
+			return pgerror.New(pgcode.Syntax, "conflicting or redundant options")
+		}
+		*seen = true
+		return nil
+	}
+	for _, option := range options {
+		var err error
+		switch t := option.(type) {
+		case *tree.AggregateStateFunc:
+			err = checkSeen(&seenStateFunc)
+			ret.stateFunc = t.Name
+		case *tree.AggregateStateType:
+			err = checkSeen(&seenStateType)
+			ret.stateType = t.Type
+		case *tree.AggregateFinalFunc:
+			err = checkSeen(&seenFinalFunc)
+			ret.finalFunc = t.Name
+		case tree.AggregateInitCond:
+			err = checkSeen(&seenInitCond)
+			initCond := string(t)
+			ret.initCond = &initCond
+		default:
+			err = errors.AssertionFailedf("unexpected aggregate option %T", t)
+		}
+		if err != nil {
+			return aggregateOptions{}, err
+		}
+	}
+	if !seenStateFunc {
+		return aggregateOptions{}, pgerror.New(pgcode.InvalidFunctionDefinition,
+			"aggregate sfunc must be specified")
+	}
+	if !seenStateType {
+		return aggregateOptions{}, pgerror.New(pgcode.InvalidFunctionDefinition,
+			"aggregate stype must be specified")
+	}
+	return ret, nil
+}
+
+// resolveSchema resolves the database and the schema in which the aggregate
+// is created, and checks that the user can create objects in the schema.
+func (n *createAggregateNode) resolveSchema(
+	params runParams,
+) (catalog.DatabaseDescriptor, *schemadesc.Mutable, error) {
+	db, scDesc, _, err := params.p.ResolveTargetObject(params.ctx, n.n.FuncName)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch scDesc.SchemaKind() {
+	case catalog.SchemaUserDefined, catalog.SchemaPublic:
+	default:
+		return nil, nil, pgerror.Newf(pgcode.InvalidSchemaName,
+			"cannot create aggregate in schema %q", scDesc.GetName())
+	}
+	if err := params.p.canCreateOnSchema(
+		params.ctx, scDesc.GetID(), db.GetID(), params.p.User(), checkPublicSchema,
+	); err != nil {
+		return nil, nil, err
+	}
+	mutDesc, err := params.p.Descriptors().GetMutableDescriptorByID(
+		params.ctx, params.p.txn, scDesc.GetID(),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	sc, ok := mutDesc.(*schemadesc.Mutable)
+	if !ok {
+		return nil, nil, pgerror.Newf(pgcode.InvalidSchemaName,
+			"cannot create aggregate in schema %q", scDesc.GetName())
+	}
+	return db, sc, nil
+}
+
+// resolveParams converts the arguments of the CREATE AGGREGATE statement into
+// function descriptor parameters. It also returns the types of the
+// parameters, which make up the aggregate's signature.
+func (n *createAggregateNode) resolveParams(
+	params runParams,
+) ([]descpb.FunctionDescriptor_Param, []*types.T, error) {
+	funcParams := make([]descpb.FunctionDescriptor_Param, len(n.n.Args))
+	argTypes := make([]*types.T, 0, len(n.n.Args))
+	for i := range n.n.Args {
+		arg := &n.n.Args[i]
+		if arg.Class != tree.FunctionArgIn {
+			return nil, nil, unimplemented.New("udf-arg-class",
+				fmt.Sprintf("%s arguments are not supported", arg.Class))
+		}
+		if arg.DefaultVal != nil {
+			return nil, nil, pgerror.New(pgcode.InvalidFunctionDefinition,
+				"aggregates cannot have default arguments")
+		}
+		typ, err := tree.ResolveType(params.ctx, arg.Type, params.p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return nil, nil, err
+		}
+		if typ.UserDefined() {
+			return nil, nil, unimplemented.New("udf-user-defined-type",
+				"user-defined types in function signatures are not supported")
+		}
+		funcParams[i] = descpb.FunctionDescriptor_Param{
+			Class: descpb.FunctionDescriptor_Param_IN,
+			Name:  string(arg.Name),
+			Type:  typ,
+		}
+		argTypes = append(argTypes, typ)
+	}
+	return funcParams, argTypes, nil
+}
+
+// lookupAggregateSupportFunction returns the user-defined function with the
+// given name and argument types, to be used as the state transition or final
+// function of an aggregate in the given database.
+func (p *planner) lookupAggregateSupportFunction(
+	ctx context.Context,
+	db catalog.DatabaseDescriptor,
+	name *tree.UnresolvedObjectName,
+	argTypes []*types.T,
+) (*funcdesc.Mutable, error) {
+	schemas, err := p.functionSchemas(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, sc := range schemas {
+		fn, ok := sc.GetFunction(name.Object())
+		if !ok {
+			continue
+		}
+		for _, overload := range fn.Overloads {
+			if overload.IsAggregate || !funcArgTypesEquivalent(overload.ArgTypes, argTypes) {
+				continue
+			}
+			desc, err := p.Descriptors().GetMutableFunctionByID(
+				ctx, p.txn, overload.ID, tree.ObjectLookupFlagsWithRequired(),
+			)
+			if err != nil {
+				return nil, err
+			}
+			if desc.GetParentID() != db.GetID() {
+				return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+					"cross-database references to function %s are not supported", tree.Name(desc.GetName()))
+			}
+			if desc.ReturnsTrigger() || desc.GetReturnType().ReturnSet {
+				return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"function %s cannot be used in an aggregate", tree.Name(desc.GetName()))
+			}
+			return desc, nil
+		}
+	}
+	typeNames := make([]string, len(argTypes))
+	for i, typ := range argTypes {
+		typeNames[i] = typ.SQLStandardName()
+	}
+	err = pgerror.Newf(pgcode.UndefinedFunction,
+		"function %s(%s) does not exist", tree.ErrString(name), strings.Join(typeNames, ", "))
+	if _, ok := tree.FunDefs[name.Object()]; ok {
+		err = errors.WithHint(err, "Only user-defined functions can be used in aggregates.")
+	}
+	return nil, err
+}
+
+func (*createAggregateNode) Next(runParams) (bool, error) { return false, nil }
+func (*createAggregateNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createAggregateNode) Close(context.Context)        {}
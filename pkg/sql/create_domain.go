@@ -0,0 +1,243 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/seqexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+)
+
+type createDomainNode struct {
+	n        *tree.CreateDomain
+	typeName *tree.TypeName
+	dbDesc   catalog.DatabaseDescriptor
+}
+
+// Use to satisfy the linter.
+var _ planNode = &createDomainNode{n: nil}
+
+// CreateDomain creates a domain type.
+func (p *planner) CreateDomain(ctx context.Context, n *tree.CreateDomain) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE DOMAIN",
+	); err != nil {
+		return nil, err
+	}
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("domain"))
+
+	// Resolve the desired new type name.
+	typeName, db, err := resolveNewTypeName(p.RunParams(ctx), n.TypeName)
+	if err != nil {
+		return nil, err
+	}
+	n.TypeName.SetAnnotation(&p.semaCtx.Annotations, typeName)
+	return &createDomainNode{
+		n:        n,
+		typeName: typeName,
+		dbDesc:   db,
+	}, nil
+}
+
+func (n *createDomainNode) startExec(params runParams) error {
+	schema, err := getCreateTypeParams(params, n.typeName, n.dbDesc)
+	if err != nil {
+		return err
+	}
+	domain, err := params.p.makeDomain(params.ctx, n.n, n.typeName)
+	if err != nil {
+		return err
+	}
+
+	id, err := descidgen.GenerateUniqueDescID(
+		params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec,
+	)
+	if err != nil {
+		return err
+	}
+	privs := catprivilege.CreatePrivilegesFromDefaultPrivileges(
+		n.dbDesc.GetDefaultPrivilegeDescriptor(),
+		schema.GetDefaultPrivilegeDescriptor(),
+		n.dbDesc.GetID(),
+		params.SessionData().User(),
+		privilege.Types,
+		n.dbDesc.GetPrivileges(),
+	)
+	// Unlike other user-defined types, domains do not have an implicit array
+	// type.
+	typeDesc := typedesc.NewBuilder(&descpb.TypeDescriptor{
+		Name:           n.typeName.Type(),
+		ID:             id,
+		ParentID:       n.dbDesc.GetID(),
+		ParentSchemaID: schema.GetID(),
+		Kind:           descpb.TypeDescriptor_DOMAIN,
+		Domain:         domain,
+		Version:        1,
+		Privileges:     privs,
+	}).BuildCreatedMutableType()
+	if err := params.p.createDescriptorWithID(
+		params.ctx,
+		catalogkeys.MakeObjectNameKey(params.ExecCfg().Codec, n.dbDesc.GetID(), schema.GetID(), n.typeName.Type()),
+		id,
+		typeDesc,
+		n.typeName.String(),
+	); err != nil {
+		return err
+	}
+
+	// Log the event.
+	return params.p.logEvent(params.ctx,
+		typeDesc.GetID(),
+		&eventpb.CreateType{
+			TypeName: n.typeName.FQString(),
+		})
+}
+
+// makeDomain validates the base type and the constraints of a CREATE DOMAIN
+// statement, and returns the domain to store in the type descriptor.
+func (p *planner) makeDomain(
+	ctx context.Context, n *tree.CreateDomain, typeName *tree.TypeName,
+) (*descpb.TypeDescriptor_Domain, error) {
+	baseType, err := tree.ResolveType(ctx, n.Type, p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return nil, err
+	}
+	switch baseType.Family() {
+	case types.AnyFamily, types.UnknownFamily, types.VoidFamily, types.TupleFamily:
+		return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+			"%q is not a valid base type for a domain", baseType.SQLStandardName())
+	case types.ArrayFamily:
+		return nil, unimplemented.NewWithIssue(27796, "domains over array types are not supported")
+	}
+	if baseType.UserDefined() {
+		return nil, unimplemented.NewWithIssue(27796, "domains over user-defined types are not supported")
+	}
+
+	domain := &descpb.TypeDescriptor_Domain{BaseType: baseType}
+	var sawNull bool
+	checkNames := make(map[string]struct{})
+	for _, c := range n.Constraints {
+		switch t := c.Qualification.(type) {
+		case *tree.ColumnDefault:
+			if domain.DefaultExpr != nil {
+				return nil, pgerror.New(pgcode.Syntax, "multiple default expressions")
+			}
+			typedExpr, err := schemaexpr.ValidateDomainDefaultExpr(ctx, t.Expr, baseType, &p.semaCtx)
+			if err != nil {
+				return nil, err
+			}
+			seqIdentifiers, err := seqexpr.GetUsedSequences(typedExpr)
+			if err != nil {
+				return nil, err
+			}
+			if len(seqIdentifiers) > 0 {
+				return nil, unimplemented.NewWithIssue(27796, "sequences are not supported in domain defaults")
+			}
+			s := tree.Serialize(typedExpr)
+			domain.DefaultExpr = &s
+
+		case tree.NotNullConstraint:
+			if sawNull {
+				return nil, pgerror.New(pgcode.Syntax, "conflicting NULL/NOT NULL constraints")
+			}
+			domain.NotNull = true
+
+		case tree.NullConstraint:
+			if domain.NotNull {
+				return nil, pgerror.New(pgcode.Syntax, "conflicting NULL/NOT NULL constraints")
+			}
+			sawNull = true
+
+		case *tree.ColumnCheckConstraint:
+			if _, err := schemaexpr.ValidateDomainCheckExpr(ctx, t.Expr, baseType, &p.semaCtx); err != nil {
+				return nil, err
+			}
+			name := string(c.Name)
+			if name == "" {
+				// Generate a name like Postgres does.
+				name = typeName.Type() + "_check"
+				for i := 1; ; i++ {
+					if _, ok := checkNames[name]; !ok {
+						break
+					}
+					name = fmt.Sprintf("%s_check%d", typeName.Type(), i)
+				}
+			} else if _, ok := checkNames[name]; ok {
+				return nil, pgerror.Newf(pgcode.DuplicateObject,
+					"constraint %q for domain %q already exists", name, typeName.Type())
+			}
+			checkNames[name] = struct{}{}
+			domain.Checks = append(domain.Checks, descpb.TypeDescriptor_Domain_Check{
+				Name: name,
+				Expr: tree.Serialize(t.Expr),
+			})
+		}
+	}
+	return domain, nil
+}
+
+// makeCreateDomainStmt returns a CREATE DOMAIN statement which recreates the
+// given domain.
+func makeCreateDomainStmt(
+	name *tree.UnresolvedObjectName, domain *descpb.TypeDescriptor_Domain,
+) (*tree.CreateDomain, error) {
+	node := &tree.CreateDomain{TypeName: name, Type: domain.BaseType}
+	if domain.DefaultExpr != nil {
+		expr, err := parser.ParseExpr(*domain.DefaultExpr)
+		if err != nil {
+			return nil, err
+		}
+		node.Constraints = append(node.Constraints, tree.NamedColumnQualification{
+			Qualification: &tree.ColumnDefault{Expr: expr},
+		})
+	}
+	if domain.NotNull {
+		node.Constraints = append(node.Constraints, tree.NamedColumnQualification{
+			Qualification: tree.NotNullConstraint{},
+		})
+	}
+	for _, c := range domain.Checks {
+		expr, err := parser.ParseExpr(c.Expr)
+		if err != nil {
+			return nil, err
+		}
+		node.Constraints = append(node.Constraints, tree.NamedColumnQualification{
+			Name:          tree.Name(c.Name),
+			Qualification: &tree.ColumnCheckConstraint{Expr: expr},
+		})
+	}
+	return node, nil
+}
+
+func (n *createDomainNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *createDomainNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *createDomainNode) Close(ctx context.Context)           {}
+func (n *createDomainNode) ReadingOwnWrites()                   {}
@@ -0,0 +1,322 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descidgen"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+type createFunctionNode struct {
+	cf *tree.CreateFunction
+	// functionBody is the body of the function, with all data sources fully
+	// qualified.
+	functionBody string
+	dbDesc       catalog.DatabaseDescriptor
+	scDesc       catalog.SchemaDescriptor
+	// depIDs holds the IDs of the relations referenced by the function body.
+	depIDs catalog.DescriptorIDSet
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createFunctionNode) ReadingOwnWrites() {}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("function"))
+
+	switch n.scDesc.SchemaKind() {
+	case catalog.SchemaUserDefined, catalog.SchemaPublic:
+	default:
+		return pgerror.Newf(pgcode.InvalidSchemaName,
+			"cannot create function in schema %q", n.scDesc.GetName())
+	}
+	mutDesc, err := params.p.Descriptors().GetMutableDescriptorByID(
+		params.ctx, params.p.txn, n.scDesc.GetID(),
+	)
+	if err != nil {
+		return err
+	}
+	sc, ok := mutDesc.(*schemadesc.Mutable)
+	if !ok {
+		return pgerror.Newf(pgcode.InvalidSchemaName,
+			"cannot create function in schema %q", n.scDesc.GetName())
+	}
+
+	funcParams, argTypes, err := n.resolveParams(params)
+	if err != nil {
+		return err
+	}
+	returnType, err := n.resolveReturnType(params)
+	if err != nil {
+		return err
+	}
+
+	name := n.cf.FuncName.Object()
+	existing, _ := sc.GetFunction(name)
+	for _, overload := range existing.Overloads {
+		if overload.IsAggregate {
+			// All the overloads of a function name must be either aggregates or
+			// regular functions.
+			return pgerror.Newf(pgcode.DuplicateFunction,
+				"function %q already exists and is an aggregate", name)
+		}
+		if !funcArgTypesEquivalent(overload.ArgTypes, argTypes) {
+			continue
+		}
+		if !n.cf.Replace {
+			return pgerror.Newf(pgcode.DuplicateFunction,
+				"function %q already exists with same argument types", name)
+		}
+		return n.replaceFunction(params, overload, funcParams, returnType)
+	}
+
+	id, err := descidgen.GenerateUniqueDescID(params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec)
+	if err != nil {
+		return err
+	}
+	privs := catpb.NewBasePrivilegeDescriptor(params.SessionData().User())
+	fn := funcdesc.NewMutableFunctionDescriptor(
+		id,
+		n.dbDesc.GetID(),
+		sc.GetID(),
+		name,
+		funcParams,
+		returnType,
+		n.cf.ReturnType.IsSet,
+		privs,
+	)
+	if n.returnsTrigger() {
+		fn.SetReturnsTrigger()
+	}
+	if err := n.setFuncOptions(&fn); err != nil {
+		return err
+	}
+
+	sc.AddFunction(name, descpb.SchemaDescriptor_FunctionOverload{
+		ID:         id,
+		ArgTypes:   argTypes,
+		ReturnType: returnType,
+		ReturnSet:  n.cf.ReturnType.IsSet,
+	})
+	if err := params.p.writeSchemaDesc(params.ctx, sc); err != nil {
+		return err
+	}
+	return params.p.createDescriptorWithID(
+		params.ctx,
+		nil, /* idKey */
+		id,
+		&fn,
+		tree.AsStringWithFQNames(n.cf, params.Ann()),
+	)
+}
+
+// replaceFunction overwrites the definition of an existing function with the
+// one in the CREATE OR REPLACE FUNCTION statement.
+func (n *createFunctionNode) replaceFunction(
+	params runParams,
+	overload descpb.SchemaDescriptor_FunctionOverload,
+	funcParams []descpb.FunctionDescriptor_Param,
+	returnType *types.T,
+) error {
+	fn, err := params.p.Descriptors().GetMutableFunctionByID(
+		params.ctx, params.p.txn, overload.ID, tree.ObjectLookupFlagsWithRequired(),
+	)
+	if err != nil {
+		return err
+	}
+	hasOwnership, err := params.p.HasOwnership(params.ctx, fn)
+	if err != nil {
+		return err
+	}
+	if !hasOwnership {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of function %s", tree.Name(fn.GetName()))
+	}
+	if !overload.ReturnType.Equivalent(returnType) || overload.ReturnSet != n.cf.ReturnType.IsSet ||
+		fn.ReturnsTrigger() != n.returnsTrigger() {
+		return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"cannot change return type of existing function")
+	}
+	if len(fn.DependedOnBy) > 0 {
+		// Triggers keep their own copy of the function body, so replacing the
+		// function would silently leave them running the old definition.
+		tbl, err := params.p.Descriptors().GetImmutableTableByID(
+			params.ctx, params.p.txn, fn.DependedOnBy[0], tree.ObjectLookupFlagsWithRequired(),
+		)
+		if err != nil {
+			return err
+		}
+		for _, trigger := range tbl.GetTriggers() {
+			if trigger.FuncID == fn.GetID() {
+				return pgerror.Newf(pgcode.DependentObjectsStillExist,
+					"cannot replace function %s because trigger %s on table %s depends on it",
+					tree.Name(fn.GetName()), tree.Name(trigger.Name), tree.Name(tbl.GetName()))
+			}
+		}
+		return errors.AssertionFailedf("table %q has no trigger depending on function %q",
+			tbl.GetName(), fn.GetName())
+	}
+
+	// All the options which are not specified in the statement are reset to
+	// their defaults, as the statement fully redefines the function.
+	fn.SetParams(funcParams)
+	fn.SetVolatility(descpb.FunctionDescriptor_VOLATILE)
+	fn.SetLeakProof(false)
+	fn.SetNullInputBehavior(descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT)
+	if err := n.setFuncOptions(fn); err != nil {
+		return err
+	}
+	return params.p.writeFuncDesc(params.ctx, fn)
+}
+
+// returnsTrigger returns true if the function is declared with RETURNS
+// TRIGGER.
+func (n *createFunctionNode) returnsTrigger() bool {
+	return tree.IsTriggerReturnType(n.cf.ReturnType.Type)
+}
+
+// resolveReturnType resolves the return type of the function. Trigger
+// functions are recorded as returning VOID.
+func (n *createFunctionNode) resolveReturnType(params runParams) (*types.T, error) {
+	if n.returnsTrigger() {
+		return types.Void, nil
+	}
+	returnType, err := tree.ResolveType(
+		params.ctx, n.cf.ReturnType.Type, params.p.semaCtx.GetTypeResolver(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if returnType.UserDefined() {
+		return nil, unimplemented.New("udf-user-defined-type",
+			"user-defined types in function signatures are not supported")
+	}
+	return returnType, nil
+}
+
+// resolveParams converts the arguments of the CREATE FUNCTION statement into
+// function descriptor parameters. It also returns the types of the input
+// parameters, which make up the function's signature.
+func (n *createFunctionNode) resolveParams(
+	params runParams,
+) ([]descpb.FunctionDescriptor_Param, []*types.T, error) {
+	funcParams := make([]descpb.FunctionDescriptor_Param, len(n.cf.Args))
+	argTypes := make([]*types.T, 0, len(n.cf.Args))
+	for i := range n.cf.Args {
+		arg := &n.cf.Args[i]
+		if arg.Class != tree.FunctionArgIn {
+			return nil, nil, unimplemented.New("udf-arg-class",
+				fmt.Sprintf("%s arguments are not supported", arg.Class))
+		}
+		if arg.DefaultVal != nil {
+			return nil, nil, unimplemented.New("udf-arg-default",
+				"default values for arguments are not supported")
+		}
+		typ, err := tree.ResolveType(params.ctx, arg.Type, params.p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return nil, nil, err
+		}
+		if typ.UserDefined() {
+			return nil, nil, unimplemented.New("udf-user-defined-type",
+				"user-defined types in function signatures are not supported")
+		}
+		funcParams[i] = descpb.FunctionDescriptor_Param{
+			Class: descpb.FunctionDescriptor_Param_IN,
+			Name:  string(arg.Name),
+			Type:  typ,
+		}
+		argTypes = append(argTypes, typ)
+	}
+	return funcParams, argTypes, nil
+}
+
+// setFuncOptions applies the options of the CREATE FUNCTION statement to the
+// function descriptor. The options have already been validated by the
+// optimizer when the statement was built.
+func (n *createFunctionNode) setFuncOptions(fn *funcdesc.Mutable) error {
+	for _, option := range n.cf.Options {
+		switch t := option.(type) {
+		case tree.FunctionVolatility:
+			switch t {
+			case tree.FunctionImmutable:
+				fn.SetVolatility(descpb.FunctionDescriptor_IMMUTABLE)
+			case tree.FunctionStable:
+				fn.SetVolatility(descpb.FunctionDescriptor_STABLE)
+			default:
+				fn.SetVolatility(descpb.FunctionDescriptor_VOLATILE)
+			}
+		case tree.FunctionLeakproof:
+			fn.SetLeakProof(bool(t))
+		case tree.FunctionNullInputBehavior:
+			switch t {
+			case tree.FunctionReturnsNullOnNullInput:
+				fn.SetNullInputBehavior(descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT)
+			case tree.FunctionStrict:
+				fn.SetNullInputBehavior(descpb.FunctionDescriptor_STRICT)
+			default:
+				fn.SetNullInputBehavior(descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT)
+			}
+		case tree.FunctionLanguage:
+			if t != tree.FunctionLangSQL {
+				return errors.AssertionFailedf("unexpected function language %s", t)
+			}
+			fn.SetLang(descpb.FunctionDescriptor_SQL)
+		case tree.FunctionBodyStr:
+			// The body is stored with its data sources fully qualified.
+			fn.SetFuncBody(n.functionBody)
+		default:
+			return errors.AssertionFailedf("unexpected function option %T", t)
+		}
+	}
+	if fn.LeakProof && fn.Volatility != descpb.FunctionDescriptor_IMMUTABLE {
+		return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"cannot set leakproof on function with non-immutable volatility: %s",
+			fn.Volatility.String())
+	}
+	depIDs := make([]descpb.ID, 0, n.depIDs.Len())
+	n.depIDs.ForEach(func(id descpb.ID) {
+		depIDs = append(depIDs, id)
+	})
+	fn.SetDependsOn(depIDs)
+	return nil
+}
+
+// writeFuncDesc writes an existing function descriptor to the store.
+func (p *planner) writeFuncDesc(ctx context.Context, fn *funcdesc.Mutable) error {
+	b := p.txn.NewBatch()
+	if err := p.Descriptors().WriteDescToBatch(
+		ctx, p.extendedEvalCtx.Tracing.KVTracingEnabled(), fn, b,
+	); err != nil {
+		return err
+	}
+	return p.txn.Run(ctx, b)
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createFunctionNode) Close(context.Context)        {}
@@ -650,7 +650,7 @@ func addUniqueWithoutIndexColumnTableDef(
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
-	if !sessionData.EnableUniqueWithoutIndexConstraints {
+	if !sessionData.EnableUniqueWithoutIndexConstraints && !d.Unique.Deferrability.IsDeferrable() {
 		return pgerror.New(pgcode.FeatureNotSupported,
 			"unique constraints without an index are not yet supported",
 		)
@@ -662,6 +662,7 @@ func addUniqueWithoutIndexColumnTableDef(
 		string(d.Unique.ConstraintName),
 		[]string{string(d.Name)},
 		"", /* predicate */
+		d.Unique.Deferrability,
 		ts,
 		validationBehavior,
 	); err != nil {
@@ -684,7 +685,10 @@ func addUniqueWithoutIndexTableDef(
 	validationBehavior tree.ValidationBehavior,
 	semaCtx *tree.SemaContext,
 ) error {
-	if !sessionData.EnableUniqueWithoutIndexConstraints {
+	// Deferrable unique constraints are always enforced without an index (see
+	// checkDeferrableUniqueConstraints), so they are not gated behind the
+	// experimental setting.
+	if !sessionData.EnableUniqueWithoutIndexConstraints && !d.Deferrability.IsDeferrable() {
 		return pgerror.New(pgcode.FeatureNotSupported,
 			"unique constraints without an index are not yet supported",
 		)
@@ -718,7 +722,7 @@ func addUniqueWithoutIndexTableDef(
 		colNames[i] = string(d.Columns[i].Column)
 	}
 	if err := ResolveUniqueWithoutIndexConstraint(
-		ctx, desc, string(d.Name), colNames, predicate, ts, validationBehavior,
+		ctx, desc, string(d.Name), colNames, predicate, d.Deferrability, ts, validationBehavior,
 	); err != nil {
 		return err
 	}
@@ -738,6 +742,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	constraintName string,
 	colNames []string,
 	predicate string,
+	deferrability tree.ConstraintDeferrability,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
@@ -791,12 +796,14 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:         constraintName,
-		TableID:      tbl.ID,
-		ColumnIDs:    columnIDs,
-		Predicate:    predicate,
-		Validity:     validity,
-		ConstraintID: tbl.NextConstraintID,
+		Name:              constraintName,
+		TableID:           tbl.ID,
+		ColumnIDs:         columnIDs,
+		Predicate:         predicate,
+		Validity:          validity,
+		ConstraintID:      tbl.NextConstraintID,
+		Deferrable:        deferrability.IsDeferrable(),
+		InitiallyDeferred: deferrability == tree.ConstraintInitiallyDeferred,
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
@@ -1052,6 +1059,8 @@ func ResolveFK(
 		OnUpdate:            descpb.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               descpb.CompositeKeyMatchMethodValue[d.Match],
 		ConstraintID:        tbl.NextConstraintID,
+		Deferrable:          d.Deferrability.IsDeferrable(),
+		InitiallyDeferred:   d.Deferrability == tree.ConstraintInitiallyDeferred,
 	}
 	tbl.NextConstraintID++
 	if ts == NewTable {
@@ -1251,6 +1260,38 @@ func NewTableDescOptionBypassLocalityOnNonMultiRegionDatabaseCheck() NewTableDes
 	}
 }
 
+// checkDeferrableUniqueConstraints returns an error if the given CREATE TABLE
+// statement contains a DEFERRABLE unique constraint that would be enforced by
+// a unique index. A unique index rejects duplicate keys on every write, so it
+// cannot postpone its checks until the end of the transaction. Like ALTER
+// TABLE, CREATE TABLE only supports deferrable UNIQUE WITHOUT INDEX
+// constraints.
+func checkDeferrableUniqueConstraints(n *tree.CreateTable) error {
+	for _, def := range n.Defs {
+		switch d := def.(type) {
+		case *tree.ColumnTableDef:
+			if d.Unique.IsUnique && !d.Unique.WithoutIndex && d.Unique.Deferrability.IsDeferrable() {
+				return errors.WithHint(
+					unimplemented.NewWithIssue(31632,
+						"deferrable unique constraints can only be added without an index"),
+					"use UNIQUE WITHOUT INDEX ... DEFERRABLE, "+
+						"and create an index on the same column separately",
+				)
+			}
+		case *tree.UniqueConstraintTableDef:
+			if !d.PrimaryKey && !d.WithoutIndex && d.Deferrability.IsDeferrable() {
+				return errors.WithHint(
+					unimplemented.NewWithIssue(31632,
+						"deferrable unique constraints can only be added without an index"),
+					"use CONSTRAINT ... UNIQUE WITHOUT INDEX ... DEFERRABLE, "+
+						"and create an index on the same columns separately",
+				)
+			}
+		}
+	}
+	return nil
+}
+
 // NewTableDesc creates a table descriptor from a CreateTable statement.
 //
 // txn and vt can be nil if the table to be created does not contain references
@@ -1299,6 +1340,10 @@ func NewTableDesc(
 	persistence tree.Persistence,
 	inOpts ...NewTableDescOption,
 ) (*tabledesc.Mutable, error) {
+	if err := checkDeferrableUniqueConstraints(n); err != nil {
+		return nil, err
+	}
+
 	// Used to delay establishing Column/Sequence dependency until ColumnIDs have
 	// been populated.
 	cdd := make([]*tabledesc.ColumnDefDescs, len(n.Defs))
@@ -1977,6 +2022,21 @@ func NewTableDesc(
 					return nil, err
 				}
 			}
+		case *tree.ExclusionConstraintTableDef:
+			if d.Name != "" {
+				if idx, _ := desc.FindIndexWithName(d.Name.String()); idx != nil {
+					return nil, pgerror.Newf(pgcode.DuplicateRelation, "duplicate index name: %q", d.Name)
+				}
+			}
+			idx, err := makeExclusionIndexDescriptor(ctx, st, &desc, d, &n.Table, semaCtx)
+			if err != nil {
+				return nil, err
+			}
+			idx.Version = indexEncodingVersion
+			if err := desc.AddSecondaryIndex(idx); err != nil {
+				return nil, err
+			}
+
 		case *tree.CheckConstraintTableDef, *tree.ForeignKeyConstraintTableDef, *tree.FamilyTableDef:
 			// pass, handled below.
 
@@ -2159,7 +2219,8 @@ func NewTableDesc(
 				}
 			}
 
-		case *tree.IndexTableDef, *tree.FamilyTableDef, *tree.LikeTableDef:
+		case *tree.IndexTableDef, *tree.FamilyTableDef, *tree.LikeTableDef,
+			*tree.ExclusionConstraintTableDef:
 			// Pass, handled above.
 
 		case *tree.CheckConstraintTableDef:
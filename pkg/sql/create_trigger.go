@@ -0,0 +1,85 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
+)
+
+type createTriggerNode struct {
+	ct      *tree.CreateTrigger
+	tableID descpb.ID
+	funcID  descpb.ID
+	// functionBody is the body of the trigger function, with all data sources
+	// fully qualified.
+	functionBody string
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE TRIGGER performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createTriggerNode) ReadingOwnWrites() {}
+
+func (n *createTriggerNode) startExec(params runParams) error {
+	telemetry.Inc(sqltelemetry.SchemaChangeCreateCounter("trigger"))
+
+	tbl, err := params.p.Descriptors().GetMutableTableVersionByID(
+		params.ctx, n.tableID, params.p.txn,
+	)
+	if err != nil {
+		return err
+	}
+	name := string(n.ct.Name)
+	if _, ok := tbl.FindTriggerByName(name); ok {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"trigger %s for relation %s already exists", n.ct.Name, tree.Name(tbl.GetName()))
+	}
+	fn, err := params.p.Descriptors().GetMutableFunctionByID(
+		params.ctx, params.p.txn, n.funcID, tree.ObjectLookupFlagsWithRequired(),
+	)
+	if err != nil {
+		return err
+	}
+	if !fn.ReturnsTrigger() {
+		return errors.AssertionFailedf("function %q does not return trigger", fn.GetName())
+	}
+
+	events := make([]descpb.TableDescriptor_Trigger_Event, len(n.ct.Events))
+	for i, e := range n.ct.Events {
+		events[i] = descpb.TriggerEventValue[e]
+	}
+	tbl.AddTrigger(descpb.TableDescriptor_Trigger{
+		Name:       name,
+		ActionTime: descpb.TriggerActionTimeValue[n.ct.ActionTime],
+		Events:     events,
+		FuncID:     fn.GetID(),
+		FuncBody:   n.functionBody,
+	})
+	fn.AddDependedOnBy(tbl.GetID())
+	if err := params.p.writeFuncDesc(params.ctx, fn); err != nil {
+		return err
+	}
+	return params.p.writeSchemaChange(
+		params.ctx, tbl, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.ct, params.Ann()),
+	)
+}
+
+func (*createTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (*createTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createTriggerNode) Close(context.Context)        {}
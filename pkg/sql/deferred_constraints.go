@@ -0,0 +1,545 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+)
+
+// constraintCheckMode is the checking mode of a deferrable constraint, as set
+// by SET CONSTRAINTS.
+type constraintCheckMode int8
+
+const (
+	// constraintCheckDefault indicates that the constraint is checked according
+	// to its INITIALLY DEFERRED / INITIALLY IMMEDIATE declaration.
+	constraintCheckDefault constraintCheckMode = iota
+	// constraintCheckImmediate indicates that the constraint is checked at the
+	// end of each statement.
+	constraintCheckImmediate
+	// constraintCheckDeferred indicates that the constraint is checked when the
+	// transaction commits.
+	constraintCheckDeferred
+)
+
+// deferredConstraintKey identifies a constraint on a table.
+type deferredConstraintKey struct {
+	tableID descpb.ID
+	name    string
+}
+
+// deferredCheck contains the rows which violated a deferred constraint when
+// the check queries of the statements which modified them ran. They are
+// checked again when the transaction commits, against the state of the tables
+// at that time; the rest of the tables was already checked by those queries,
+// so validating the constraint does not require scanning them.
+type deferredCheck struct {
+	key deferredConstraintKey
+	// fkInbound is set for the rows of the referenced table of a foreign key
+	// constraint which were updated or deleted.
+	fkInbound bool
+	// keys contains the values of the constraint columns of the rows, in the
+	// order of the constraint columns, without duplicates.
+	keys []tree.Datums
+	seen map[string]struct{}
+	// memUsage is the memory accounted for the keys.
+	memUsage int64
+}
+
+// DeferredConstraintState is the per-transaction state for deferrable
+// constraints. It tracks the modes set by SET CONSTRAINTS and the violations
+// whose checks were postponed until the transaction commits.
+type DeferredConstraintState struct {
+	// allMode is the mode set by SET CONSTRAINTS ALL.
+	allMode constraintCheckMode
+	// modes contains the modes set by SET CONSTRAINTS for specific
+	// constraints. They override allMode; SET CONSTRAINTS ALL clears them.
+	modes map[deferredConstraintKey]constraintCheckMode
+	// pending contains the checks that must be run before the transaction
+	// commits, in the order in which they were first deferred.
+	pending []*deferredCheck
+	// acc accounts for the memory used by the pending checks.
+	acc mon.BoundAccount
+}
+
+// init initializes the memory account of the state. It must be called before
+// the state is used.
+func (s *DeferredConstraintState) init(acc mon.BoundAccount) {
+	s.acc = acc
+}
+
+// reset clears the state at the end of a transaction.
+func (s *DeferredConstraintState) reset(ctx context.Context) {
+	s.acc.Clear(ctx)
+	*s = DeferredConstraintState{acc: s.acc}
+}
+
+// close releases the memory account of the state.
+func (s *DeferredConstraintState) close(ctx context.Context) {
+	s.acc.Close(ctx)
+}
+
+// isDeferred returns whether the check for the given constraint should be
+// postponed until the transaction commits.
+func (s *DeferredConstraintState) isDeferred(
+	key deferredConstraintKey, deferrability tree.ConstraintDeferrability,
+) bool {
+	if !deferrability.IsDeferrable() {
+		return false
+	}
+	mode := s.allMode
+	if m, ok := s.modes[key]; ok {
+		mode = m
+	}
+	switch mode {
+	case constraintCheckImmediate:
+		return false
+	case constraintCheckDeferred:
+		return true
+	default:
+		return deferrability == tree.ConstraintInitiallyDeferred
+	}
+}
+
+// deferCheck records that the row with the given values of the columns of the
+// given constraint must be checked again before the transaction commits.
+func (s *DeferredConstraintState) deferCheck(
+	ctx context.Context, key deferredConstraintKey, fkInbound bool, vals tree.Datums,
+) error {
+	var c *deferredCheck
+	for _, p := range s.pending {
+		if p.key == key && p.fkInbound == fkInbound {
+			c = p
+			break
+		}
+	}
+	if c == nil {
+		c = &deferredCheck{key: key, fkInbound: fkInbound, seen: make(map[string]struct{})}
+		s.pending = append(s.pending, c)
+	}
+	tuple := &tree.DTuple{D: vals}
+	encoded := tree.AsStringWithFlags(tuple, tree.FmtParsable)
+	if _, ok := c.seen[encoded]; ok {
+		return nil
+	}
+	size := int64(len(encoded)) + int64(tuple.Size())
+	if err := s.acc.Grow(ctx, size); err != nil {
+		return err
+	}
+	c.seen[encoded] = struct{}{}
+	c.keys = append(c.keys, vals)
+	c.memUsage += size
+	return nil
+}
+
+// release releases the memory used by the given checks, which were removed
+// from the state.
+func (s *DeferredConstraintState) release(ctx context.Context, checks []*deferredCheck) {
+	for _, c := range checks {
+		s.acc.Shrink(ctx, c.memUsage)
+		c.memUsage = 0
+	}
+}
+
+// setMode sets the checking mode for the given constraints. If keys is nil,
+// the mode applies to all constraints.
+func (s *DeferredConstraintState) setMode(
+	keys []deferredConstraintKey, mode constraintCheckMode,
+) {
+	if keys == nil {
+		s.allMode = mode
+		s.modes = nil
+		return
+	}
+	if s.modes == nil {
+		s.modes = make(map[deferredConstraintKey]constraintCheckMode, len(keys))
+	}
+	for _, k := range keys {
+		s.modes[k] = mode
+	}
+}
+
+// takeImmediate removes and returns the pending checks of the constraints
+// which are no longer deferred. This is used after SET CONSTRAINTS ...
+// IMMEDIATE, which must run any outstanding checks right away.
+func (s *DeferredConstraintState) takeImmediate() []*deferredCheck {
+	var immediate []*deferredCheck
+	pending := s.pending[:0]
+	for _, c := range s.pending {
+		mode := s.allMode
+		if m, ok := s.modes[c.key]; ok {
+			mode = m
+		}
+		if mode == constraintCheckImmediate {
+			immediate = append(immediate, c)
+		} else {
+			pending = append(pending, c)
+		}
+	}
+	s.pending = pending
+	return immediate
+}
+
+// takeAll removes and returns all pending checks.
+func (s *DeferredConstraintState) takeAll() []*deferredCheck {
+	pending := s.pending
+	s.pending = nil
+	return pending
+}
+
+// deferredCheckBatchSize is the number of rows checked by each of the queries
+// run by validateDeferredChecks.
+const deferredCheckBatchSize = 100
+
+// validateDeferredChecks checks the rows of the given deferred foreign key and
+// unique constraint checks against the current contents of their tables, as
+// seen by txn. Constraints that were dropped since their check was deferred
+// are skipped.
+func validateDeferredChecks(
+	ctx context.Context,
+	txn *kv.Txn,
+	descsCol *descs.Collection,
+	ie sqlutil.InternalExecutor,
+	checks []*deferredCheck,
+) error {
+	flags := tree.ObjectLookupFlags{
+		CommonLookupFlags: tree.CommonLookupFlags{Required: true, IncludeDropped: true},
+	}
+	for _, check := range checks {
+		tab, err := descsCol.GetImmutableTableByID(ctx, txn, check.key.tableID, flags)
+		if err != nil {
+			return err
+		}
+		if tab.Dropped() {
+			continue
+		}
+		info, err := tab.GetConstraintInfo()
+		if err != nil {
+			return err
+		}
+		c, ok := info[check.key.name]
+		if !ok {
+			continue
+		}
+		log.VEventf(ctx, 2, "validating %d rows of deferred constraint %q on table %q",
+			len(check.keys), check.key.name, tab.GetName())
+		for i := 0; i < len(check.keys); i += deferredCheckBatchSize {
+			batch := check.keys[i:]
+			if len(batch) > deferredCheckBatchSize {
+				batch = batch[:deferredCheckBatchSize]
+			}
+			switch {
+			case c.FK != nil:
+				target, err := descsCol.GetImmutableTableByID(ctx, txn, c.FK.ReferencedTableID, flags)
+				if err != nil {
+					return err
+				}
+				if err := validateDeferredForeignKey(
+					ctx, txn, ie, tab, target, c.FK, check.fkInbound, batch,
+				); err != nil {
+					return err
+				}
+			case c.UniqueWithoutIndexConstraint != nil:
+				if err := validateDeferredUnique(
+					ctx, txn, ie, tab, c.UniqueWithoutIndexConstraint, batch,
+				); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateDeferredForeignKey checks that the rows of the origin table which
+// have the given values in the columns of the foreign key have a matching row
+// in the referenced table. It generates a query of the form:
+//
+//   SELECT s.a, s.b FROM [<id of origin> AS s]@{IGNORE_FOREIGN_KEYS}
+//   WHERE ((s.a = $1 AND s.b = $2) OR ...)
+//   AND NOT EXISTS (
+//     SELECT 1 FROM [<id of referenced> AS t] WHERE t.x = s.a AND t.y = s.b
+//   )
+//   LIMIT 1
+//
+// The values are those of the inserted or updated rows of the origin table,
+// or of the updated or deleted rows of the referenced table if fkInbound is
+// set. Either way, the constraint is only violated if there is a row of the
+// origin table with these values but no row of the referenced table.
+func validateDeferredForeignKey(
+	ctx context.Context,
+	txn *kv.Txn,
+	ie sqlutil.InternalExecutor,
+	srcTable catalog.TableDescriptor,
+	targetTable catalog.TableDescriptor,
+	fk *descpb.ForeignKeyConstraint,
+	fkInbound bool,
+	keys []tree.Datums,
+) error {
+	srcCols, err := srcTable.NamesForColumnIDs(fk.OriginColumnIDs)
+	if err != nil {
+		return err
+	}
+	targetCols, err := targetTable.NamesForColumnIDs(fk.ReferencedColumnIDs)
+	if err != nil {
+		return err
+	}
+	qualifiedSrcCols := make([]string, len(srcCols))
+	qualifiedTargetCols := make([]string, len(targetCols))
+	on := make([]string, len(srcCols))
+	for i := range srcCols {
+		qualifiedSrcCols[i] = fmt.Sprintf("s.%s", tree.NameString(srcCols[i]))
+		qualifiedTargetCols[i] = fmt.Sprintf("t.%s", tree.NameString(targetCols[i]))
+		on[i] = fmt.Sprintf("%s = %s", qualifiedTargetCols[i], qualifiedSrcCols[i])
+	}
+	var args []interface{}
+	var disjuncts, targetDisjuncts []string
+	for _, vals := range keys {
+		var nulls int
+		for _, d := range vals {
+			if d == tree.DNull {
+				nulls++
+			}
+		}
+		// Keys with NULLs never violate a MATCH SIMPLE constraint, nor keys
+		// which are all NULL a MATCH FULL constraint.
+		if nulls == len(vals) || (nulls > 0 && fk.Match != descpb.ForeignKeyReference_FULL) {
+			continue
+		}
+		conjuncts := make([]string, len(vals))
+		targetConjuncts := make([]string, len(vals))
+		for i, d := range vals {
+			if d == tree.DNull {
+				conjuncts[i] = fmt.Sprintf("%s IS NULL", qualifiedSrcCols[i])
+				targetConjuncts[i] = fmt.Sprintf("%s IS NULL", qualifiedTargetCols[i])
+				continue
+			}
+			args = append(args, d)
+			conjuncts[i] = fmt.Sprintf("%s = $%d", qualifiedSrcCols[i], len(args))
+			targetConjuncts[i] = fmt.Sprintf("%s = $%d", qualifiedTargetCols[i], len(args))
+		}
+		disjuncts = append(disjuncts, "("+strings.Join(conjuncts, " AND ")+")")
+		targetDisjuncts = append(targetDisjuncts, "("+strings.Join(targetConjuncts, " AND ")+")")
+	}
+	if len(disjuncts) == 0 {
+		return nil
+	}
+	if txn.IsoLevel() == kv.ReadCommitted {
+		if err := lockDeferredCheckRows(
+			ctx, txn, ie, srcTable.GetID(), "s", strings.Join(disjuncts, " OR "), args,
+		); err != nil {
+			return err
+		}
+		if err := lockDeferredCheckRows(
+			ctx, txn, ie, targetTable.GetID(), "t", strings.Join(targetDisjuncts, " OR "), args,
+		); err != nil {
+			return err
+		}
+	}
+	query := fmt.Sprintf(
+		`SELECT %[1]s FROM [%[2]d AS s]@{IGNORE_FOREIGN_KEYS}
+		 WHERE (%[3]s)
+		 AND NOT EXISTS (SELECT 1 FROM [%[4]d AS t] WHERE %[5]s)
+		 LIMIT 1`,
+		strings.Join(qualifiedSrcCols, ", "), // 1
+		srcTable.GetID(),                     // 2
+		strings.Join(disjuncts, " OR "),      // 3
+		targetTable.GetID(),                  // 4
+		strings.Join(on, " AND "),            // 5
+	)
+	values, err := ie.QueryRowEx(ctx, "validate deferred fk constraint", txn,
+		sessiondata.NodeUserSessionDataOverride, query, args...)
+	if err != nil {
+		return err
+	}
+	if values.Len() == 0 {
+		return nil
+	}
+
+	var msg, details bytes.Buffer
+	if fkInbound {
+		// Generate an error of the form:
+		//   ERROR:  update or delete on table "parent" violates foreign key
+		//           constraint "child_p_fkey" on table "child"
+		//   DETAIL: Key (p)=(1) is still referenced from table "child".
+		msg.WriteString("update or delete on table ")
+		lexbase.EncodeEscapedSQLIdent(&msg, targetTable.GetName())
+		msg.WriteString(" violates foreign key constraint ")
+		lexbase.EncodeEscapedSQLIdent(&msg, fk.Name)
+		msg.WriteString(" on table ")
+		lexbase.EncodeEscapedSQLIdent(&msg, srcTable.GetName())
+		fmt.Fprintf(&details, "Key (%s)=(%s) is still referenced from table ",
+			strings.Join(targetCols, ", "), formatDeferredCheckValues(values))
+		lexbase.EncodeEscapedSQLIdent(&details, srcTable.GetName())
+		details.WriteByte('.')
+	} else {
+		// Generate an error of the form:
+		//   ERROR:  insert or update on table "child" violates foreign key
+		//           constraint "child_p_fkey"
+		//   DETAIL: Key (p)=(2) is not present in table "parent".
+		msg.WriteString("insert or update on table ")
+		lexbase.EncodeEscapedSQLIdent(&msg, srcTable.GetName())
+		msg.WriteString(" violates foreign key constraint ")
+		lexbase.EncodeEscapedSQLIdent(&msg, fk.Name)
+		hasNull := false
+		for _, d := range values {
+			hasNull = hasNull || d == tree.DNull
+		}
+		if hasNull {
+			details.WriteString("MATCH FULL does not allow mixing of null and nonnull key values.")
+		} else {
+			fmt.Fprintf(&details, "Key (%s)=(%s) is not present in table ",
+				strings.Join(srcCols, ", "), formatDeferredCheckValues(values))
+			lexbase.EncodeEscapedSQLIdent(&details, targetTable.GetName())
+			details.WriteByte('.')
+		}
+	}
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.ForeignKeyViolation, "%s", msg.String()), fk.Name,
+		),
+		details.String(),
+	)
+}
+
+// validateDeferredUnique checks that there is at most one row of the table
+// with each of the given values in the columns of the unique constraint. It
+// generates a query of the form:
+//
+//   SELECT a, b FROM [<id of table> AS tbl]
+//   WHERE ((a = $1 AND b = $2) OR ...) AND (<predicate>)
+//   GROUP BY a, b HAVING count(*) > 1
+//   LIMIT 1
+func validateDeferredUnique(
+	ctx context.Context,
+	txn *kv.Txn,
+	ie sqlutil.InternalExecutor,
+	tab catalog.TableDescriptor,
+	uc *descpb.UniqueWithoutIndexConstraint,
+	keys []tree.Datums,
+) error {
+	colNames, err := tab.NamesForColumnIDs(uc.ColumnIDs)
+	if err != nil {
+		return err
+	}
+	cols := make([]string, len(colNames))
+	for i, n := range colNames {
+		cols[i] = tree.NameString(n)
+	}
+	var args []interface{}
+	var disjuncts []string
+	for _, vals := range keys {
+		// Keys with NULLs never conflict with each other.
+		hasNull := false
+		for _, d := range vals {
+			hasNull = hasNull || d == tree.DNull
+		}
+		if hasNull {
+			continue
+		}
+		conjuncts := make([]string, len(vals))
+		for i, d := range vals {
+			args = append(args, d)
+			conjuncts[i] = fmt.Sprintf("%s = $%d", cols[i], len(args))
+		}
+		disjuncts = append(disjuncts, "("+strings.Join(conjuncts, " AND ")+")")
+	}
+	if len(disjuncts) == 0 {
+		return nil
+	}
+	where := "(" + strings.Join(disjuncts, " OR ") + ")"
+	if uc.Predicate != "" {
+		where = fmt.Sprintf("%s AND (%s)", where, uc.Predicate)
+	}
+	if txn.IsoLevel() == kv.ReadCommitted {
+		if err := lockDeferredCheckRows(ctx, txn, ie, tab.GetID(), "tbl", where, args); err != nil {
+			return err
+		}
+	}
+	query := fmt.Sprintf(
+		`SELECT %[1]s FROM [%[2]d AS tbl] WHERE %[3]s GROUP BY %[1]s HAVING count(*) > 1 LIMIT 1`,
+		strings.Join(cols, ", "), // 1
+		tab.GetID(),              // 2
+		where,                    // 3
+	)
+	values, err := ie.QueryRowEx(ctx, "validate deferred unique constraint", txn,
+		sessiondata.NodeUserSessionDataOverride, query, args...)
+	if err != nil {
+		return err
+	}
+	if values.Len() == 0 {
+		return nil
+	}
+	// Generate an error of the form:
+	//   ERROR:  duplicate key value violates unique constraint "foo"
+	//   DETAIL: Key (k)=(2) already exists.
+	var msg bytes.Buffer
+	msg.WriteString("duplicate key value violates unique constraint ")
+	lexbase.EncodeEscapedSQLIdent(&msg, uc.Name)
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.UniqueViolation, "%s", msg.String()), uc.Name,
+		),
+		fmt.Sprintf("Key (%s)=(%s) already exists.",
+			strings.Join(colNames, ", "), formatDeferredCheckValues(values)),
+	)
+}
+
+// lockDeferredCheckRows locks the rows of the given table which match the given
+// filter, in which the table is aliased as alias. This is done under READ
+// COMMITTED isolation before validating a deferred constraint, for the same
+// reasons as the locking of the rows read by the constraint checks of each
+// statement (see execbuilder.buildCheckQuery): the rows can't be modified by
+// concurrent transactions until the transaction commits, and the validation
+// waits for the transactions which already modified them.
+func lockDeferredCheckRows(
+	ctx context.Context,
+	txn *kv.Txn,
+	ie sqlutil.InternalExecutor,
+	tableID descpb.ID,
+	alias string,
+	filter string,
+	args []interface{},
+) error {
+	query := fmt.Sprintf(
+		`SELECT 1 FROM [%d AS %s] WHERE %s FOR UPDATE`, tableID, alias, filter,
+	)
+	_, err := ie.ExecEx(ctx, "lock deferred constraint rows", txn,
+		sessiondata.NodeUserSessionDataOverride, query, args...)
+	return err
+}
+
+// formatDeferredCheckValues formats the values of a row returned by the
+// queries which validate deferred checks.
+func formatDeferredCheckValues(values tree.Datums) string {
+	strs := make([]string, len(values))
+	for i, d := range values {
+		strs[i] = d.String()
+	}
+	return strings.Join(strs, ", ")
+}
@@ -15,6 +15,7 @@ import (
 	"sync"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -51,7 +52,7 @@ type deleteRun struct {
 
 	// partialIndexDelValsOffset is the offset of partial index delete
 	// indicators in the source values. It is equal to the number of fetched
-	// columns.
+	// columns plus the number of passthrough columns.
 	partialIndexDelValsOffset int
 
 	// rowIdxToRetIdx is the mapping from the columns returned by the deleter
@@ -60,6 +61,11 @@ type deleteRun struct {
 	// of the mutation. Otherwise, the value at the i-th index refers to the
 	// index of the resultRowBuffer where the i-th column is to be returned.
 	rowIdxToRetIdx []int
+
+	// numPassthrough is the number of columns in addition to the set of
+	// columns of the target table being returned, that we must pass through
+	// from the input node.
+	numPassthrough int
 }
 
 var _ mutationPlanNode = &deleteNode{}
@@ -73,7 +79,10 @@ func (d *deleteNode) startExec(params runParams) error {
 			params.EvalContext().Mon.MakeBoundAccount(),
 			colinfo.ColTypeInfoFromResCols(d.columns))
 	}
-	return d.run.td.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV)
+	if err := d.run.td.init(params.ctx, params.p.txn, params.EvalContext(), &params.EvalContext().Settings.SV); err != nil {
+		return err
+	}
+	return d.run.td.initAfterTriggers(params.EvalContext(), descpb.TableDescriptor_Trigger_DELETE)
 }
 
 // Next is required because batchedPlanNode inherits from planNode, but
@@ -165,11 +174,15 @@ func (d *deleteNode) processSourceRow(params runParams, sourceVals tree.Datums)
 			return err
 		}
 
-		// Truncate sourceVals so that it no longer includes partial index
-		// predicate values.
-		sourceVals = sourceVals[:d.run.partialIndexDelValsOffset]
 	}
 
+	// The passthrough values, if any, follow the fetched columns. Truncate
+	// sourceVals so that it no longer includes the passthrough values or
+	// partial index predicate values.
+	numFetchCols := len(d.run.td.rd.FetchCols)
+	passthroughValues := sourceVals[numFetchCols : numFetchCols+d.run.numPassthrough]
+	sourceVals = sourceVals[:numFetchCols]
+
 	// Queue the deletion in the KV batch.
 	if err := d.run.td.row(params.ctx, sourceVals, pm, d.run.traceKV); err != nil {
 		return err
@@ -190,6 +203,10 @@ func (d *deleteNode) processSourceRow(params runParams, sourceVals tree.Datums)
 			}
 		}
 
+		// The columns in the RETURNING clause that refer to other tables (from
+		// the USING clause of the delete) are returned last.
+		copy(resultValues[len(resultValues)-d.run.numPassthrough:], passthroughValues)
+
 		if _, err := d.run.td.rows.AddRow(params.ctx, resultValues); err != nil {
 			return err
 		}
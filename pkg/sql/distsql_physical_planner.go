@@ -39,6 +39,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfra/execopnode"
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
@@ -381,6 +382,7 @@ func (dsp *DistSQLPlanner) mustWrapNode(planCtx *PlanningCtx, node planNode) boo
 	switch n := node.(type) {
 	// Keep these cases alphabetized, please!
 	case *distinctNode:
+	case *expandNode:
 	case *exportNode:
 	case *filterNode:
 	case *groupNode:
@@ -554,6 +556,15 @@ func checkSupportForPlanNode(node planNode) (distRecommendation, error) {
 		// always number each row in order.
 		return cannotDistribute, nil
 
+	case *expandNode:
+		// Every row is expanded independently, so the expansion can happen on
+		// any node.
+		rec, err := checkSupportForPlanNode(n.source)
+		if err != nil {
+			return cannotDistribute, err
+		}
+		return rec.compose(canDistribute), nil
+
 	case *projectSetNode:
 		return checkSupportForPlanNode(n.source)
 
@@ -1691,11 +1702,22 @@ func (dsp *DistSQLPlanner) addAggregators(
 	aggregations := make([]execinfrapb.AggregatorSpec_Aggregation, len(n.funcs))
 	argumentsColumnTypes := make([][]*types.T, len(n.funcs))
 	for i, fholder := range n.funcs {
-		funcIdx, err := execinfrapb.GetAggregateFuncIdx(fholder.funcName)
-		if err != nil {
-			return err
+		if fholder.userDefined != nil {
+			aggregations[i].Func = execinfrapb.UserDefined
+			var err error
+			aggregations[i].UserDefined, err = makeUserDefinedAggregateSpec(
+				fholder.userDefined, fholder.resultType, planCtx,
+			)
+			if err != nil {
+				return err
+			}
+		} else {
+			funcIdx, err := execinfrapb.GetAggregateFuncIdx(fholder.funcName)
+			if err != nil {
+				return err
+			}
+			aggregations[i].Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 		}
-		aggregations[i].Func = execinfrapb.AggregatorSpec_Func(funcIdx)
 		aggregations[i].Distinct = fholder.isDistinct
 		for _, renderIdx := range fholder.argRenderIdxs {
 			aggregations[i].ColIdx = append(aggregations[i].ColIdx, uint32(p.PlanToStreamColMap[renderIdx]))
@@ -1727,6 +1749,32 @@ func (dsp *DistSQLPlanner) addAggregators(
 	})
 }
 
+// makeUserDefinedAggregateSpec creates the specification of a call to a
+// user-defined aggregate with the given result type.
+func makeUserDefinedAggregateSpec(
+	info *exec.UserDefinedAggInfo, resultType *types.T, planCtx *PlanningCtx,
+) (*execinfrapb.AggregatorSpec_UserDefinedAggregate, error) {
+	spec := &execinfrapb.AggregatorSpec_UserDefinedAggregate{
+		StateType:        info.StateType,
+		ResultType:       resultType,
+		TransitionStrict: info.TransitionStrict,
+		FinalStrict:      info.FinalStrict,
+	}
+	var err error
+	if info.InitCond != tree.DNull {
+		if spec.InitCond, err = physicalplan.MakeExpression(info.InitCond, planCtx, nil /* indexVarMap */); err != nil {
+			return nil, err
+		}
+	}
+	if spec.Transition, err = physicalplan.MakeExpression(info.Transition, planCtx, nil /* indexVarMap */); err != nil {
+		return nil, err
+	}
+	if spec.Final, err = physicalplan.MakeExpression(info.Final, planCtx, nil /* indexVarMap */); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
 // planAggregators plans the aggregator processors. An evaluator stage is added
 // if necessary.
 // Invariants assumed:
@@ -2163,6 +2211,10 @@ func (dsp *DistSQLPlanner) planAggregators(
 			argTypes[j] = inputTypes[c]
 		}
 		copy(argTypes[len(agg.ColIdx):], info.argumentsColumnTypes[i])
+		if agg.Func == execinfrapb.UserDefined {
+			finalOutTypes[i] = agg.UserDefined.ResultType
+			continue
+		}
 		var err error
 		_, returnTyp, err := execagg.GetAggregateInfo(agg.Func, argTypes...)
 		if err != nil {
@@ -2993,6 +3045,9 @@ func (dsp *DistSQLPlanner) createPhysPlanForPlanNode(
 	case *ordinalityNode:
 		plan, err = dsp.createPlanForOrdinality(ctx, planCtx, n)
 
+	case *expandNode:
+		plan, err = dsp.createPlanForExpand(ctx, planCtx, n)
+
 	case *projectSetNode:
 		plan, err = dsp.createPlanForProjectSet(ctx, planCtx, n)
 
@@ -3399,6 +3454,51 @@ func (dsp *DistSQLPlanner) createPlanForOrdinality(
 	return plan, nil
 }
 
+func (dsp *DistSQLPlanner) createPlanForExpand(
+	ctx context.Context, planCtx *PlanningCtx, n *expandNode,
+) (*PhysicalPlan, error) {
+	plan, err := dsp.createPhysPlanForPlanNode(ctx, planCtx, n.source)
+	if err != nil {
+		return nil, err
+	}
+
+	inputTypes := plan.GetResultTypes()
+	spec := &execinfrapb.ExpandSpec{
+		GroupingColumns: make([]uint32, len(n.groupingCols)),
+		GroupingSets:    make([]execinfrapb.ExpandSpec_GroupingSet, len(n.groupingSets)),
+	}
+	outputTypes := make([]*types.T, len(inputTypes), len(inputTypes)+len(n.groupingCols)+1)
+	copy(outputTypes, inputTypes)
+	for i, col := range n.groupingCols {
+		streamCol := plan.PlanToStreamColMap[col]
+		spec.GroupingColumns[i] = uint32(streamCol)
+		outputTypes = append(outputTypes, inputTypes[streamCol])
+	}
+	outputTypes = append(outputTypes, types.Int)
+	for i, set := range n.groupingSets {
+		cols := make([]uint32, len(set))
+		for j, idx := range set {
+			cols[j] = uint32(idx)
+		}
+		spec.GroupingSets[i].Columns = cols
+	}
+
+	// Each row is expanded independently, so the expand processors are planned
+	// on every stream of the input.
+	plan.AddNoGroupingStage(
+		execinfrapb.ProcessorCoreUnion{Expand: spec},
+		execinfrapb.PostProcessSpec{},
+		outputTypes,
+		execinfrapb.Ordering{},
+	)
+
+	// Add the grouping set and grouping set ID columns to PlanToStreamColMap.
+	for i := 0; i <= len(n.groupingCols); i++ {
+		plan.PlanToStreamColMap = append(plan.PlanToStreamColMap, len(inputTypes)+i)
+	}
+	return plan, nil
+}
+
 func createProjectSetSpec(
 	planCtx *PlanningCtx, n *projectSetPlanningInfo, indexVarMap []int,
 ) (*execinfrapb.ProjectSetSpec, error) {
@@ -3989,6 +4089,8 @@ func checkScanParallelizationIfLocal(
 				return true, nil
 			case *ordinalityNode:
 				return true, nil
+			case *expandNode:
+				return true, nil
 			case *renderNode:
 				// Only support projections since render expressions might be
 				// handled via a wrapped row-by-row processor.
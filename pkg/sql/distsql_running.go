@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/rpc"
 	"github.com/cockroachdb/cockroach/pkg/rpc/nodedialer"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/colflow"
 	"github.com/cockroachdb/cockroach/pkg/sql/contention"
 	"github.com/cockroachdb/cockroach/pkg/sql/contentionpb"
@@ -1550,6 +1551,7 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 			planner,
 			evalCtx,
 			recv,
+			nil, /* resultWriter */
 		); err != nil {
 			recv.SetError(err)
 			return false
@@ -1572,6 +1574,30 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 	}
 
 	for i := range plan.checkPlans {
+		check := &plan.checkPlans[i].Check
+		// The check query of a deferrable constraint returns the rows which
+		// violate it. They are either queued, to be checked again when the
+		// transaction commits, or turned into an error right away.
+		var resultWriter rowResultWriter
+		if check.Deferrability.IsDeferrable() {
+			deferred := planner.extendedEvalCtx.DeferredConstraints
+			key := deferredConstraintKey{tableID: descpb.ID(check.TableID), name: check.ConstraintName}
+			if deferred != nil && !planner.extendedEvalCtx.TxnImplicit &&
+				deferred.isDeferred(key, check.Deferrability) {
+				log.VEventf(ctx, 2, "deferring check query %d out of %d", i+1, len(plan.checkPlans))
+				resultWriter = NewCallbackResultWriter(func(ctx context.Context, row tree.Datums) error {
+					keyVals := make(tree.Datums, len(check.KeyOrdinals))
+					for j, ord := range check.KeyOrdinals {
+						keyVals[j] = row[ord]
+					}
+					return deferred.deferCheck(ctx, key, check.FKInbound, keyVals)
+				})
+			} else {
+				resultWriter = NewCallbackResultWriter(func(ctx context.Context, row tree.Datums) error {
+					return check.MkErr(row)
+				})
+			}
+		}
 		log.VEventf(ctx, 2, "executing check query %d out of %d", i+1, len(plan.checkPlans))
 		if err := dsp.planAndRunPostquery(
 			ctx,
@@ -1579,6 +1605,7 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 			planner,
 			evalCtxFactory(),
 			recv,
+			resultWriter,
 		); err != nil {
 			recv.SetError(err)
 			return false
@@ -1588,13 +1615,16 @@ func (dsp *DistSQLPlanner) PlanAndRunCascadesAndChecks(
 	return true
 }
 
-// planAndRunPostquery runs a cascade or check query.
+// planAndRunPostquery runs a cascade or check query. The rows returned by the
+// query are passed to resultWriter, if it is set; otherwise, the query must
+// not return any rows.
 func (dsp *DistSQLPlanner) planAndRunPostquery(
 	ctx context.Context,
 	postqueryPlan planMaybePhysical,
 	planner *planner,
 	evalCtx *extendedEvalContext,
 	recv *DistSQLReceiver,
+	resultWriter rowResultWriter,
 ) error {
 	postqueryMonitor := mon.NewMonitor(
 		"postquery",
@@ -1637,11 +1667,16 @@ func (dsp *DistSQLPlanner) planAndRunPostquery(
 
 	postqueryRecv := recv.clone()
 	defer postqueryRecv.Release()
-	// TODO(yuzefovich): at the moment, errOnlyResultWriter is sufficient here,
-	// but it may not be the case when we support cascades through the optimizer.
-	postqueryResultWriter := &errOnlyResultWriter{}
-	postqueryRecv.resultWriter = postqueryResultWriter
-	postqueryRecv.batchWriter = postqueryResultWriter
+	if resultWriter != nil {
+		postqueryRecv.resultWriter = resultWriter
+	} else {
+		// TODO(yuzefovich): at the moment, errOnlyResultWriter is sufficient
+		// here, but it may not be the case when we support cascades through the
+		// optimizer.
+		postqueryResultWriter := &errOnlyResultWriter{}
+		postqueryRecv.resultWriter = postqueryResultWriter
+		postqueryRecv.batchWriter = postqueryResultWriter
+	}
 	dsp.Run(ctx, postqueryPlanCtx, planner.txn, postqueryPhysPlan, postqueryRecv, evalCtx, nil /* finishedSetupFn */)()
 	return postqueryRecv.resultWriter.Err()
 }
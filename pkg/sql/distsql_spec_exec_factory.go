@@ -518,13 +518,23 @@ func (e *distSQLSpecExecFactory) constructAggregators(
 		i := len(groupCols) + j
 		spec := &aggregationSpecs[i]
 		agg := &aggregations[j]
+		funcName := agg.FuncName
+		if agg.UserDefined != nil {
+			funcName = execinfrapb.UserDefined.String()
+		}
 		argumentsColumnTypes[i], err = populateAggFuncSpec(
-			spec, agg.FuncName, agg.Distinct, agg.ArgCols,
+			spec, funcName, agg.Distinct, agg.ArgCols,
 			agg.ConstArgs, agg.Filter, planCtx, physPlan,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if agg.UserDefined != nil {
+			spec.UserDefined, err = makeUserDefinedAggregateSpec(agg.UserDefined, agg.ResultType, planCtx)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 	if err := e.dsp.planAggregators(
 		planCtx,
@@ -638,6 +648,15 @@ func (e *distSQLSpecExecFactory) ConstructOrdinality(
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: ordinality")
 }
 
+func (e *distSQLSpecExecFactory) ConstructExpand(
+	input exec.Node,
+	groupingCols []exec.NodeColumnOrdinal,
+	groupingSets [][]int,
+	setIDColName string,
+) (exec.Node, error) {
+	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: expand")
+}
+
 func (e *distSQLSpecExecFactory) ConstructIndexJoin(
 	input exec.Node,
 	table cat.Table,
@@ -854,7 +873,7 @@ func (e *distSQLSpecExecFactory) ConstructPlan(
 	root exec.Node,
 	subqueries []exec.Subquery,
 	cascades []exec.Cascade,
-	checks []exec.Node,
+	checks []exec.Check,
 	rootRowCount int64,
 ) (exec.Plan, error) {
 	if len(subqueries) != 0 {
@@ -993,6 +1012,7 @@ func (e *distSQLSpecExecFactory) ConstructDelete(
 	table cat.Table,
 	fetchCols exec.TableColumnOrdinalSet,
 	returnCols exec.TableColumnOrdinalSet,
+	passthrough colinfo.ResultColumns,
 	autoCommit bool,
 ) (exec.Node, error) {
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: delete")
@@ -1034,6 +1054,18 @@ func (e *distSQLSpecExecFactory) ConstructCreateView(
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: create view")
 }
 
+func (e *distSQLSpecExecFactory) ConstructCreateFunction(
+	schema cat.Schema, cf *tree.CreateFunction, functionBody string, deps opt.ViewDeps,
+) (exec.Node, error) {
+	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: create function")
+}
+
+func (e *distSQLSpecExecFactory) ConstructCreateTrigger(
+	table cat.Table, ct *tree.CreateTrigger, function *tree.Overload, functionBody string,
+) (exec.Node, error) {
+	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: create trigger")
+}
+
 func (e *distSQLSpecExecFactory) ConstructSequenceSelect(sequence cat.Sequence) (exec.Node, error) {
 	return nil, unimplemented.NewWithIssue(47473, "experimental opt-driven distsql planning: sequence select")
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -36,6 +37,7 @@ type dropCascadeState struct {
 	toDeleteByID            map[descpb.ID]*toDelete
 	allTableObjectsToDelete []*tabledesc.Mutable
 	typesToDelete           []*typedesc.Mutable
+	functionsToDelete       []*funcdesc.Mutable
 
 	droppedNames []string
 }
@@ -66,6 +68,22 @@ func (d *dropCascadeState) collectObjectsInSchema(
 	for i := range names {
 		d.objectNamesToDelete = append(d.objectNamesToDelete, &names[i])
 	}
+	// User-defined functions are not in the namespace table, collect them
+	// through the schema's functions mapping instead.
+	if err := schema.ForEachFunctionOverload(func(overload descpb.SchemaDescriptor_FunctionOverload) error {
+		fn, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, overload.ID, tree.ObjectLookupFlags{
+			CommonLookupFlags: tree.CommonLookupFlags{
+				IncludeOffline: true,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		d.functionsToDelete = append(d.functionsToDelete, fn)
+		return nil
+	}); err != nil {
+		return err
+	}
 	d.schemasToDelete = append(d.schemasToDelete, schemaWithDbDesc{schema: schema, dbDesc: db})
 	return nil
 }
@@ -219,6 +237,31 @@ func (d *dropCascadeState) dropAllCollectedObjects(ctx context.Context, p *plann
 		}
 	}
 
+	// Finally delete all of the functions. Their parent schemas are being
+	// dropped as well, so there's no need to update their functions mappings.
+	// Aggregates in other schemas which use the dropped functions are dropped
+	// as well.
+	for _, fn := range d.functionsToDelete {
+		d.droppedNames = append(d.droppedNames, fn.GetName())
+		if fn.Dropped() {
+			// The aggregate was already dropped along with one of its support
+			// functions.
+			continue
+		}
+		if err := p.dropTriggersUsingFunction(ctx, fn, "" /* jobDesc */); err != nil {
+			return err
+		}
+		if err := p.dropAggregatesUsingFunction(ctx, fn, "" /* jobDesc */); err != nil {
+			return err
+		}
+		if err := p.removeAggregateBackReferences(ctx, fn); err != nil {
+			return err
+		}
+		if err := p.dropFunctionImpl(ctx, fn, "" /* jobDesc */); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
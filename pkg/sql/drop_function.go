@@ -0,0 +1,487 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+type dropFunctionNode struct {
+	n tree.Statement
+	// aggregates is true if the node drops user-defined aggregates, for DROP
+	// AGGREGATE.
+	aggregates bool
+	// toDrop holds the functions to be dropped, along with the schemas whose
+	// functions mapping must be updated.
+	toDrop []functionToDrop
+}
+
+type functionToDrop struct {
+	sc *schemadesc.Mutable
+	fn *funcdesc.Mutable
+}
+
+// dropAggregateNode drops user-defined aggregates. It only differs from
+// dropFunctionNode by its name in EXPLAIN output.
+type dropAggregateNode struct {
+	dropFunctionNode
+}
+
+// Use to satisfy the linter.
+var _ planNode = &dropFunctionNode{n: nil}
+
+// DropFunction drops user-defined functions.
+// Privileges: ownership of the function.
+//   Notes: postgres requires ownership of the function.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+	node := &dropFunctionNode{n: n}
+	if err := p.collectFunctionsToDrop(ctx, node, n.Functions, n.IfExists, n.DropBehavior); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// DropAggregate drops user-defined aggregates.
+// Privileges: ownership of the aggregate.
+//   Notes: postgres requires ownership of the aggregate.
+func (p *planner) DropAggregate(ctx context.Context, n *tree.DropAggregate) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP AGGREGATE",
+	); err != nil {
+		return nil, err
+	}
+	node := &dropAggregateNode{dropFunctionNode{n: n, aggregates: true}}
+	if err := p.collectFunctionsToDrop(
+		ctx, &node.dropFunctionNode, n.Aggregates, n.IfExists, n.DropBehavior,
+	); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// collectFunctionsToDrop resolves the functions, or aggregates if
+// node.aggregates is set, of a DROP FUNCTION or DROP AGGREGATE statement and
+// checks that they can be dropped.
+func (p *planner) collectFunctionsToDrop(
+	ctx context.Context,
+	node *dropFunctionNode,
+	objs tree.FuncObjs,
+	ifExists bool,
+	behavior tree.DropBehavior,
+) error {
+	isAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return err
+	}
+
+	kind := "function"
+	if node.aggregates {
+		kind = "aggregate"
+	}
+	seen := make(map[descpb.ID]struct{})
+	for i := range objs {
+		obj := &objs[i]
+		matches, err := p.matchFunctionOverloads(ctx, obj.FuncName, obj.Args)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			if ifExists {
+				continue
+			}
+			return pgerror.Newf(pgcode.UndefinedFunction,
+				"%s %s does not exist", kind, tree.AsString(obj))
+		}
+		if len(matches) > 1 {
+			return pgerror.Newf(pgcode.AmbiguousFunction,
+				"%s name %q is not unique", kind, obj.FuncName.Object())
+		}
+		m := matches[0]
+		if _, ok := seen[m.overload.ID]; ok {
+			continue
+		}
+		seen[m.overload.ID] = struct{}{}
+		fn, err := p.Descriptors().GetMutableFunctionByID(
+			ctx, p.txn, m.overload.ID, tree.ObjectLookupFlagsWithRequired(),
+		)
+		if err != nil {
+			return err
+		}
+		if fn.IsAggregate() != node.aggregates {
+			if node.aggregates {
+				return pgerror.Newf(pgcode.WrongObjectType,
+					"function %s is not an aggregate", tree.AsString(obj))
+			}
+			return errors.WithHint(
+				pgerror.Newf(pgcode.WrongObjectType,
+					"%s is an aggregate function", tree.AsString(obj)),
+				"Use DROP AGGREGATE to drop aggregate functions.",
+			)
+		}
+		hasOwnership, err := p.HasOwnership(ctx, fn)
+		if err != nil {
+			return err
+		}
+		if !(isAdmin || hasOwnership) {
+			return pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be owner of %s %s", kind, tree.Name(fn.GetName()))
+		}
+		if behavior != tree.DropCascade {
+			if len(fn.DependedOnBy) > 0 {
+				return errors.WithHint(
+					pgerror.Newf(pgcode.DependentObjectsStillExist,
+						"cannot drop function %s because other objects depend on it", tree.Name(fn.GetName())),
+					"use DROP ... CASCADE to drop the dependent triggers too",
+				)
+			}
+			if len(fn.DependedOnByAggregates) > 0 {
+				return errors.WithHint(
+					pgerror.Newf(pgcode.DependentObjectsStillExist,
+						"cannot drop function %s because other objects depend on it", tree.Name(fn.GetName())),
+					"use DROP ... CASCADE to drop the dependent aggregates too",
+				)
+			}
+		}
+		node.toDrop = append(node.toDrop, functionToDrop{sc: m.sc, fn: fn})
+	}
+	return nil
+}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	if n.aggregates {
+		telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("aggregate"))
+	} else {
+		telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("function"))
+	}
+
+	ctx := params.ctx
+	p := params.p
+	jobDesc := tree.AsStringWithFQNames(n.n, params.Ann())
+	for _, d := range n.toDrop {
+		if d.fn.Dropped() {
+			// The aggregate was already dropped along with one of its support
+			// functions.
+			continue
+		}
+		if err := p.dropTriggersUsingFunction(ctx, d.fn, jobDesc); err != nil {
+			return err
+		}
+		if err := p.dropAggregatesUsingFunction(ctx, d.fn, jobDesc); err != nil {
+			return err
+		}
+		if err := p.removeAggregateBackReferences(ctx, d.fn); err != nil {
+			return err
+		}
+		d.sc.RemoveFunction(d.fn.GetName(), d.fn.GetID())
+		if err := p.writeSchemaDesc(ctx, d.sc); err != nil {
+			return err
+		}
+		if err := p.dropFunctionImpl(ctx, d.fn, jobDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropTriggersUsingFunction drops the triggers on the tables which depend on
+// the given trigger function, and clears its back-references.
+func (p *planner) dropTriggersUsingFunction(
+	ctx context.Context, fn *funcdesc.Mutable, jobDesc string,
+) error {
+	for _, id := range fn.DependedOnBy {
+		tbl, err := p.Descriptors().GetMutableTableVersionByID(ctx, id, p.txn)
+		if err != nil {
+			return err
+		}
+		if tbl.Dropped() {
+			continue
+		}
+		var names []string
+		for i := range tbl.Triggers {
+			if tbl.Triggers[i].FuncID == fn.GetID() {
+				names = append(names, tbl.Triggers[i].Name)
+			}
+		}
+		for _, name := range names {
+			tbl.RemoveTrigger(name)
+		}
+		if err := p.writeSchemaChange(ctx, tbl, descpb.InvalidMutationID, jobDesc); err != nil {
+			return err
+		}
+	}
+	fn.DependedOnBy = nil
+	return nil
+}
+
+// dropAggregatesUsingFunction drops the user-defined aggregates which use the
+// given function as their state transition or final function, and clears its
+// back-references.
+func (p *planner) dropAggregatesUsingFunction(
+	ctx context.Context, fn *funcdesc.Mutable, jobDesc string,
+) error {
+	flags := tree.ObjectLookupFlags{
+		CommonLookupFlags: tree.CommonLookupFlags{Required: true, IncludeDropped: true},
+	}
+	aggIDs := append([]descpb.ID(nil), fn.DependedOnByAggregates...)
+	for _, id := range aggIDs {
+		agg, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, id, flags)
+		if err != nil {
+			return err
+		}
+		if agg.Dropped() {
+			continue
+		}
+		if err := p.removeAggregateBackReferences(ctx, agg); err != nil {
+			return err
+		}
+		scDesc, err := p.Descriptors().GetMutableDescriptorByID(ctx, p.txn, agg.GetParentSchemaID())
+		if err != nil {
+			return err
+		}
+		// The schema's functions mapping doesn't need to be updated if the
+		// schema is being dropped too.
+		if sc, ok := scDesc.(*schemadesc.Mutable); ok && !sc.Dropped() {
+			sc.RemoveFunction(agg.GetName(), agg.GetID())
+			if err := p.writeSchemaDesc(ctx, sc); err != nil {
+				return err
+			}
+		}
+		if err := p.dropFunctionImpl(ctx, agg, jobDesc); err != nil {
+			return err
+		}
+	}
+	fn.DependedOnByAggregates = nil
+	return nil
+}
+
+// removeAggregateBackReferences removes the back-references to the given
+// user-defined aggregate from its support functions. It is a no-op if fn is
+// not an aggregate.
+func (p *planner) removeAggregateBackReferences(ctx context.Context, fn *funcdesc.Mutable) error {
+	agg := fn.Aggregate
+	if agg == nil {
+		return nil
+	}
+	flags := tree.ObjectLookupFlags{
+		CommonLookupFlags: tree.CommonLookupFlags{Required: true, IncludeDropped: true},
+	}
+	for _, id := range []descpb.ID{agg.StateFuncID, agg.FinalFuncID} {
+		if id == descpb.InvalidID {
+			continue
+		}
+		supportFn, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, id, flags)
+		if err != nil {
+			return err
+		}
+		supportFn.RemoveDependedOnByAggregate(fn.GetID())
+		if supportFn.Dropped() {
+			continue
+		}
+		if err := p.writeFuncDesc(ctx, supportFn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropFunctionImpl marks the function descriptor as dropped and queues a
+// schema change job which deletes the descriptor once all leases on it have
+// been released. The caller is responsible for removing the function from its
+// parent schema's functions mapping.
+func (p *planner) dropFunctionImpl(
+	ctx context.Context, fn *funcdesc.Mutable, jobDesc string,
+) error {
+	fn.SetDropped()
+	if err := p.writeFuncDesc(ctx, fn); err != nil {
+		return err
+	}
+
+	record, recordExists := p.extendedEvalCtx.SchemaChangeJobRecords[fn.ID]
+	if recordExists {
+		record.AppendDescription(jobDesc)
+		log.Infof(ctx, "job %d: updated job's specification for change on function %d", record.JobID, fn.ID)
+		return nil
+	}
+	jobRecord := jobs.Record{
+		JobID:         p.extendedEvalCtx.ExecCfg.JobRegistry.MakeJobID(),
+		Description:   jobDesc,
+		Username:      p.User(),
+		DescriptorIDs: descpb.IDs{fn.ID},
+		Details: jobspb.SchemaChangeDetails{
+			DescID: fn.ID,
+			// The version distinction for database jobs doesn't matter for
+			// function jobs.
+			FormatVersion: jobspb.DatabaseJobFormatVersion,
+		},
+		Progress:      jobspb.SchemaChangeProgress{},
+		NonCancelable: true,
+	}
+	p.extendedEvalCtx.SchemaChangeJobRecords[fn.ID] = &jobRecord
+	log.Infof(ctx, "queued new schema change job %d for function %d", jobRecord.JobID, fn.ID)
+	return nil
+}
+
+// functionOverloadMatch is a function overload found by
+// matchFunctionOverloads, along with the schema it belongs to.
+type functionOverloadMatch struct {
+	sc       *schemadesc.Mutable
+	overload descpb.SchemaDescriptor_FunctionOverload
+}
+
+// matchFunctionOverloads returns the overloads of the user-defined function
+// with the given name whose signature matches args. If args is nil, all
+// overloads with the given name match. Only the first schema on the search
+// path which contains a matching overload is considered, unless the name is
+// qualified with a schema.
+func (p *planner) matchFunctionOverloads(
+	ctx context.Context, name *tree.UnresolvedObjectName, args tree.FuncArgs,
+) ([]functionOverloadMatch, error) {
+	var argTypes []*types.T
+	if args != nil {
+		var err error
+		if argTypes, err = p.resolveFuncArgTypes(ctx, args); err != nil {
+			return nil, err
+		}
+	}
+	schemas, err := p.functionSchemas(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var matches []functionOverloadMatch
+	for _, sc := range schemas {
+		fn, ok := sc.GetFunction(name.Object())
+		if !ok {
+			continue
+		}
+		for _, overload := range fn.Overloads {
+			if args == nil || funcArgTypesEquivalent(overload.ArgTypes, argTypes) {
+				matches = append(matches, functionOverloadMatch{sc: sc, overload: overload})
+			}
+		}
+		if len(matches) > 0 {
+			break
+		}
+	}
+	return matches, nil
+}
+
+// functionSchemas returns the mutable descriptors of the schemas in which a
+// function with the given name is looked up: either the schema it is
+// qualified with, or the schemas on the search path of the database it is
+// qualified with, or the current database otherwise. Schemas which cannot
+// contain functions, like virtual schemas, are skipped.
+func (p *planner) functionSchemas(
+	ctx context.Context, name *tree.UnresolvedObjectName,
+) ([]*schemadesc.Mutable, error) {
+	tn := name.ToTableName()
+	dbName := p.CurrentDatabase()
+	if tn.ExplicitCatalog {
+		dbName = tn.Catalog()
+	}
+	db, err := p.Descriptors().GetImmutableDatabaseByName(ctx, p.txn, dbName,
+		tree.DatabaseLookupFlags{Required: true})
+	if err != nil {
+		return nil, err
+	}
+	var scNames []string
+	if tn.ExplicitSchema {
+		scNames = []string{tn.Schema()}
+	} else {
+		iter := p.SessionData().SearchPath.Iter()
+		for scName, ok := iter.Next(); ok; scName, ok = iter.Next() {
+			scNames = append(scNames, scName)
+		}
+	}
+	var ret []*schemadesc.Mutable
+	for _, scName := range scNames {
+		sc, err := p.Descriptors().GetMutableSchemaByName(
+			ctx, p.txn, db, scName, tree.SchemaLookupFlags{},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if sc == nil || sc.SchemaKind() != catalog.SchemaUserDefined && sc.SchemaKind() != catalog.SchemaPublic {
+			continue
+		}
+		if mut, ok := sc.(*schemadesc.Mutable); ok {
+			ret = append(ret, mut)
+		}
+	}
+	return ret, nil
+}
+
+// resolveFuncArgTypes resolves the types of the input arguments of a function
+// signature. OUT arguments are not part of a function's signature and are
+// skipped.
+func (p *planner) resolveFuncArgTypes(
+	ctx context.Context, args tree.FuncArgs,
+) ([]*types.T, error) {
+	ret := make([]*types.T, 0, len(args))
+	for i := range args {
+		if args[i].Class == tree.FunctionArgOut {
+			continue
+		}
+		typ, err := tree.ResolveType(ctx, args[i].Type, p.semaCtx.GetTypeResolver())
+		if err != nil {
+			return nil, err
+		}
+		if typ.UserDefined() {
+			return nil, unimplemented.New("udf-user-defined-type",
+				"user-defined types in function signatures are not supported")
+		}
+		ret = append(ret, typ)
+	}
+	return ret, nil
+}
+
+// funcArgTypesEquivalent returns true if the two lists of argument types are
+// pairwise equivalent.
+func funcArgTypesEquivalent(a, b []*types.T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equivalent(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *dropFunctionNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *dropFunctionNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *dropFunctionNode) Close(ctx context.Context)           {}
+func (n *dropFunctionNode) ReadingOwnWrites()                   {}
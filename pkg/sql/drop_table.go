@@ -264,6 +264,15 @@ func (p *planner) dropTableImpl(
 		}
 	}
 
+	// Remove the back-references from the trigger functions.
+	for i := range tableDesc.Triggers {
+		if err := p.removeTriggerFunctionBackReference(
+			ctx, tableDesc.GetID(), tableDesc.Triggers[i].FuncID,
+		); err != nil {
+			return droppedViews, err
+		}
+	}
+
 	// Drop sequences that the columns of the table own.
 	for _, col := range tableDesc.PublicColumns() {
 		if err := p.dropSequencesOwnedByCol(ctx, col, !droppingParent, behavior); err != nil {
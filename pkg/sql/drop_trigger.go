@@ -0,0 +1,121 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/errors"
+)
+
+type dropTriggerNode struct {
+	n *tree.DropTrigger
+	// tbl is the table from which the trigger is dropped. It is nil if the
+	// trigger does not exist and IF EXISTS was specified.
+	tbl *tabledesc.Mutable
+}
+
+// DropTrigger drops a trigger from a table.
+// Privileges: CREATE on table.
+//   Notes: postgres requires ownership of the table.
+func (p *planner) DropTrigger(ctx context.Context, n *tree.DropTrigger) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+
+	tn := n.Table.ToTableName()
+	_, tbl, err := p.ResolveMutableTableDescriptor(ctx, &tn, !n.IfExists, tree.ResolveRequireTableDesc)
+	if err != nil {
+		return nil, err
+	}
+	if tbl == nil {
+		return &dropTriggerNode{n: n}, nil
+	}
+	if err := p.CheckPrivilege(ctx, tbl, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if _, ok := tbl.FindTriggerByName(string(n.Name)); !ok {
+		if n.IfExists {
+			return &dropTriggerNode{n: n}, nil
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"trigger %s for table %s does not exist", n.Name, tree.Name(tbl.GetName()))
+	}
+	return &dropTriggerNode{n: n, tbl: tbl}, nil
+}
+
+func (n *dropTriggerNode) startExec(params runParams) error {
+	if n.tbl == nil {
+		return nil
+	}
+	telemetry.Inc(sqltelemetry.SchemaChangeDropCounter("trigger"))
+
+	if err := params.p.removeTrigger(params.ctx, n.tbl, string(n.n.Name)); err != nil {
+		return err
+	}
+	return params.p.writeSchemaChange(
+		params.ctx, n.tbl, descpb.InvalidMutationID, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+// removeTrigger removes the trigger with the given name from the table, and
+// removes the back-reference to the table from the trigger function if no
+// other trigger on the table uses it. The caller is responsible for writing
+// the table descriptor.
+func (p *planner) removeTrigger(ctx context.Context, tbl *tabledesc.Mutable, name string) error {
+	trigger, ok := tbl.RemoveTrigger(name)
+	if !ok {
+		return errors.AssertionFailedf("trigger %q not found on table %q", name, tbl.GetName())
+	}
+	for i := range tbl.Triggers {
+		if tbl.Triggers[i].FuncID == trigger.FuncID {
+			return nil
+		}
+	}
+	return p.removeTriggerFunctionBackReference(ctx, tbl.GetID(), trigger.FuncID)
+}
+
+// removeTriggerFunctionBackReference removes the back-reference to the given
+// table from a trigger function.
+func (p *planner) removeTriggerFunctionBackReference(
+	ctx context.Context, tableID descpb.ID, funcID descpb.ID,
+) error {
+	fn, err := p.Descriptors().GetMutableFunctionByID(
+		ctx, p.txn, funcID, tree.ObjectLookupFlags{
+			CommonLookupFlags: tree.CommonLookupFlags{Required: true, IncludeDropped: true},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if fn.Dropped() {
+		return nil
+	}
+	fn.RemoveDependedOnBy(tableID)
+	return p.writeFuncDesc(ctx, fn)
+}
+
+func (*dropTriggerNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropTriggerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropTriggerNode) Close(context.Context)        {}
+func (*dropTriggerNode) ReadingOwnWrites()            {}
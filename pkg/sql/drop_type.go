@@ -29,32 +29,47 @@ import (
 )
 
 type dropTypeNode struct {
-	n      *tree.DropType
 	toDrop map[descpb.ID]*typedesc.Mutable
 }
 
 // Use to satisfy the linter.
-var _ planNode = &dropTypeNode{n: nil}
+var _ planNode = &dropTypeNode{}
 
 func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, error) {
+	return p.dropTypes(ctx, "DROP TYPE", n.Names, n.IfExists, n.DropBehavior, false /* domainsOnly */)
+}
+
+// DropDomain drops domain types. It is the same as DROP TYPE, except that
+// all of the types must be domains.
+func (p *planner) DropDomain(ctx context.Context, n *tree.DropDomain) (planNode, error) {
+	return p.dropTypes(ctx, "DROP DOMAIN", n.Names, n.IfExists, n.DropBehavior, true /* domainsOnly */)
+}
+
+func (p *planner) dropTypes(
+	ctx context.Context,
+	stmt string,
+	names []*tree.UnresolvedObjectName,
+	ifExists bool,
+	behavior tree.DropBehavior,
+	domainsOnly bool,
+) (planNode, error) {
 	if err := checkSchemaChangeEnabled(
 		ctx,
 		p.ExecCfg(),
-		"DROP TYPE",
+		stmt,
 	); err != nil {
 		return nil, err
 	}
 
 	node := &dropTypeNode{
-		n:      n,
 		toDrop: make(map[descpb.ID]*typedesc.Mutable),
 	}
-	if n.DropBehavior == tree.DropCascade {
-		return nil, unimplemented.NewWithIssue(51480, "DROP TYPE CASCADE is not yet supported")
+	if behavior == tree.DropCascade {
+		return nil, unimplemented.NewWithIssue(51480, stmt+" CASCADE is not yet supported")
 	}
-	for _, name := range n.Names {
+	for _, name := range names {
 		// Resolve the desired type descriptor.
-		_, typeDesc, err := p.ResolveMutableTypeDescriptor(ctx, name, !n.IfExists)
+		_, typeDesc, err := p.ResolveMutableTypeDescriptor(ctx, name, !ifExists)
 		if err != nil {
 			return nil, err
 		}
@@ -65,6 +80,9 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 		if _, ok := node.toDrop[typeDesc.ID]; ok {
 			continue
 		}
+		if domainsOnly && typeDesc.Kind != descpb.TypeDescriptor_DOMAIN {
+			return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a domain", name)
+		}
 		switch typeDesc.Kind {
 		case descpb.TypeDescriptor_ALIAS:
 			// The implicit array types are not directly droppable.
@@ -93,9 +111,15 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 		}
 
 		// Check if we can drop the type.
-		if err := p.canDropTypeDesc(ctx, typeDesc, n.DropBehavior); err != nil {
+		if err := p.canDropTypeDesc(ctx, typeDesc, behavior); err != nil {
 			return nil, err
 		}
+		node.toDrop[typeDesc.ID] = typeDesc
+
+		// Domains do not have array types.
+		if typeDesc.Kind == descpb.TypeDescriptor_DOMAIN {
+			continue
+		}
 
 		// Get the array type that needs to be dropped as well.
 		mutArrayDesc, err := p.Descriptors().GetMutableTypeVersionByID(ctx, p.txn, typeDesc.ArrayTypeID)
@@ -103,11 +127,10 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 			return nil, err
 		}
 		// Ensure that we can drop the array type as well.
-		if err := p.canDropTypeDesc(ctx, mutArrayDesc, n.DropBehavior); err != nil {
+		if err := p.canDropTypeDesc(ctx, mutArrayDesc, behavior); err != nil {
 			return nil, err
 		}
-		// Record these descriptors for deletion.
-		node.toDrop[typeDesc.ID] = typeDesc
+		// Record the array type for deletion.
 		node.toDrop[mutArrayDesc.ID] = mutArrayDesc
 	}
 	return node, nil
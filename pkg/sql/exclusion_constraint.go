@@ -0,0 +1,216 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// makeExclusionIndexDescriptor returns the descriptor of the index backing the
+// given EXCLUDE constraint. The columns compared with "=" make up the prefix
+// of the index key, in the order they are listed in the constraint. The column
+// compared with "&&", if any, is the last key column and the inverted column
+// of an inverted index. Conflicting rows are found by the mutation checks
+// built in optbuilder, which can use the index to look them up.
+//
+// The returned descriptor has no ID, version or creation time set.
+func makeExclusionIndexDescriptor(
+	ctx context.Context,
+	st *cluster.Settings,
+	desc *tabledesc.Mutable,
+	d *tree.ExclusionConstraintTableDef,
+	tableName *tree.TableName,
+	semaCtx *tree.SemaContext,
+) (descpb.IndexDescriptor, error) {
+	if desc.IsPartitionAllBy() {
+		return descpb.IndexDescriptor{}, unimplemented.NewWithIssue(46657,
+			"EXCLUDE constraints are not supported on tables with PARTITION ALL BY or LOCALITY REGIONAL BY ROW")
+	}
+
+	var columns tree.IndexElemList
+	var operators []string
+	var overlaps *tree.ExclusionElem
+	for i := range d.Elems {
+		elem := &d.Elems[i]
+		switch elem.Operator.Symbol {
+		case treecmp.EQ:
+			columns = append(columns, tree.IndexElem{Column: elem.Column, Direction: tree.Ascending})
+			operators = append(operators, elem.Operator.Symbol.String())
+		case treecmp.Overlaps:
+			if overlaps != nil {
+				return descpb.IndexDescriptor{}, unimplemented.NewWithIssue(46657,
+					"EXCLUDE constraints with more than one column compared with && are not supported")
+			}
+			overlaps = elem
+		default:
+			return descpb.IndexDescriptor{}, errors.AssertionFailedf(
+				"unexpected operator %s in EXCLUDE constraint", elem.Operator)
+		}
+	}
+	if overlaps != nil {
+		if !d.Inverted {
+			return descpb.IndexDescriptor{}, errors.WithHint(
+				pgerror.New(pgcode.WrongObjectType,
+					"operator && is not supported by access method btree"),
+				"use EXCLUDE USING GIST",
+			)
+		}
+		columns = append(columns, tree.IndexElem{Column: overlaps.Column, Direction: tree.Ascending})
+		operators = append(operators, overlaps.Operator.Symbol.String())
+	}
+	if err := validateColumnsAreAccessible(desc, columns); err != nil {
+		return descpb.IndexDescriptor{}, err
+	}
+
+	idx := descpb.IndexDescriptor{
+		Name:               string(d.Name),
+		ExclusionOperators: operators,
+	}
+	if err := idx.FillColumns(columns); err != nil {
+		return descpb.IndexDescriptor{}, err
+	}
+	if overlaps != nil {
+		col, err := desc.FindColumnWithName(overlaps.Column)
+		if err != nil {
+			return descpb.IndexDescriptor{}, err
+		}
+		// Of the types with an inverted index, only arrays support &&.
+		if col.GetType().Family() != types.ArrayFamily {
+			return descpb.IndexDescriptor{}, pgerror.Newf(pgcode.DatatypeMismatch,
+				"column %s of type %s cannot be compared with && in an EXCLUDE constraint",
+				tree.ErrNameString(col.GetName()), col.GetType().SQLString())
+		}
+		idx.Type = descpb.IndexDescriptor_INVERTED
+		if err := populateInvertedIndexDescriptor(
+			ctx, st, col, &idx, columns[len(columns)-1],
+		); err != nil {
+			return descpb.IndexDescriptor{}, err
+		}
+	}
+
+	if d.Predicate != nil {
+		expr, err := schemaexpr.ValidatePartialIndexPredicate(
+			ctx, desc, d.Predicate, tableName, semaCtx,
+		)
+		if err != nil {
+			return descpb.IndexDescriptor{}, err
+		}
+		idx.Predicate = expr
+	}
+	return idx, nil
+}
+
+// conflictingRowQuery returns a query which returns one of the rows of the
+// table that conflicts with another row under the given EXCLUDE constraint, if
+// there is any. The names of the columns returned by the query are returned as
+// well.
+func conflictingRowQuery(
+	tableDesc catalog.TableDescriptor, idx catalog.Index,
+) (sql string, colNames []string) {
+	// Only the rows which satisfy the predicate of a partial constraint are
+	// compared with each other.
+	where := ""
+	if idx.IsPartial() {
+		where = fmt.Sprintf(" WHERE (%s)", idx.GetPredicate())
+	}
+
+	on := make([]string, 0, idx.NumKeyColumns()+1)
+	colNames = make([]string, idx.NumKeyColumns())
+	sel := make([]string, idx.NumKeyColumns())
+	for i := range colNames {
+		colNames[i] = idx.GetKeyColumnName(i)
+		col := tree.NameString(colNames[i])
+		sel[i] = "t1." + col
+		on = append(on, fmt.Sprintf("t1.%[1]s %[2]s t2.%[1]s", col, idx.GetExclusionOperator(i)))
+	}
+
+	// Prevent rows from conflicting with themselves.
+	primaryIdx := tableDesc.GetPrimaryIndex()
+	pk := make([]string, primaryIdx.NumKeyColumns())
+	for i := range pk {
+		pk[i] = fmt.Sprintf("t1.%[1]s != t2.%[1]s", tree.NameString(primaryIdx.GetKeyColumnName(i)))
+	}
+	on = append(on, fmt.Sprintf("(%s)", strings.Join(pk, " OR ")))
+
+	return fmt.Sprintf(
+		`SELECT %[1]s FROM (SELECT * FROM [%[2]d AS t]%[3]s) AS t1 `+
+			`JOIN (SELECT * FROM [%[2]d AS t]%[3]s) AS t2 ON %[4]s LIMIT 1`,
+		strings.Join(sel, ", "),   // 1
+		tableDesc.GetID(),         // 2
+		where,                     // 3
+		strings.Join(on, " AND "), // 4
+	), colNames
+}
+
+// validateExclusionConstraint verifies that no two rows of the table conflict
+// with each other under the EXCLUDE constraint backed by the given index.
+func validateExclusionConstraint(
+	ctx context.Context,
+	tableDesc catalog.TableDescriptor,
+	idx catalog.Index,
+	ie sqlutil.InternalExecutor,
+	txn *kv.Txn,
+) error {
+	query, colNames := conflictingRowQuery(tableDesc, idx)
+
+	log.Infof(ctx, "validating exclusion constraint %q (%q [%v]) with query %q",
+		idx.GetName(),
+		tableDesc.GetName(),
+		colNames,
+		query,
+	)
+
+	values, err := ie.QueryRowEx(ctx, "validate exclusion constraint", txn,
+		sessiondata.NodeUserSessionDataOverride, query)
+	if err != nil {
+		return err
+	}
+	if values.Len() > 0 {
+		valuesStr := make([]string, len(values))
+		for i := range values {
+			valuesStr[i] = values[i].String()
+		}
+		// Note: this error message mirrors the message produced by Postgres
+		// when it fails to add an exclusion constraint due to conflicting rows.
+		return errors.WithDetail(
+			pgerror.WithConstraintName(
+				pgerror.Newf(
+					pgcode.ExclusionViolation, "could not create exclusion constraint %q", idx.GetName(),
+				),
+				idx.GetName(),
+			),
+			fmt.Sprintf(
+				"Key (%s)=(%s) conflicts with another key.",
+				strings.Join(colNames, ", "), strings.Join(valuesStr, ", "),
+			),
+		)
+	}
+	return nil
+}
@@ -30,7 +30,7 @@ func constructPlan(
 	root exec.Node,
 	subqueries []exec.Subquery,
 	cascades []exec.Cascade,
-	checks []exec.Node,
+	checks []exec.Check,
 	rootRowCount int64,
 ) (exec.Plan, error) {
 	res := &planComponents{}
@@ -78,7 +78,8 @@ func constructPlan(
 	if len(checks) > 0 {
 		res.checkPlans = make([]checkPlan, len(checks))
 		for i := range checks {
-			assignPlan(&res.checkPlans[i].plan, checks[i])
+			res.checkPlans[i].Check = checks[i]
+			assignPlan(&res.checkPlans[i].plan, checks[i].Node)
 		}
 	}
 
@@ -159,6 +159,17 @@ var defaultIntSize = func() *settings.IntSetting {
 	return s
 }()
 
+// allowReadCommittedIsolation controls whether transactions can use the READ
+// COMMITTED isolation level. If disabled, transactions which ask for READ
+// COMMITTED are upgraded to SERIALIZABLE.
+var allowReadCommittedIsolation = settings.RegisterBoolSetting(
+	settings.TenantWritable,
+	"sql.txn.read_committed_isolation.enabled",
+	"set to true to allow transactions to use the READ COMMITTED isolation level if specified by "+
+		"BEGIN/SET commands; if false, they are upgraded to SERIALIZABLE",
+	true,
+).WithPublic()
+
 const allowCrossDatabaseFKsSetting = "sql.cross_db_fks.enabled"
 
 var allowCrossDatabaseFKs = settings.RegisterBoolSetting(
@@ -2903,6 +2914,14 @@ func (m *sessionDataMutator) SetDefaultTransactionPriority(val tree.UserPriority
 	m.data.DefaultTxnPriority = int64(val)
 }
 
+func (m *sessionDataMutator) SetDefaultTransactionIsolationLevel(val tree.IsolationLevel) {
+	m.data.DefaultTxnIsolationLevel = int64(val)
+}
+
+func (m *sessionDataMutator) SetMaxRetriesForReadCommitted(val int32) {
+	m.data.MaxRetriesForReadCommitted = val
+}
+
 func (m *sessionDataMutator) SetDefaultTransactionReadOnly(val bool) {
 	m.data.DefaultTxnReadOnly = val
 }
@@ -103,6 +103,12 @@ func GetAggregateConstructor(
 		argTypes[len(aggInfo.ColIdx)+j] = d.ResolvedType()
 		arguments[j] = d
 	}
+	if aggInfo.Func == execinfrapb.UserDefined {
+		constructor, outputType, err = getUserDefinedAggregateInfo(
+			evalCtx, semaCtx, aggInfo.UserDefined, argTypes,
+		)
+		return
+	}
 	constructor, outputType, err = GetAggregateInfo(aggInfo.Func, argTypes...)
 	return
 }
@@ -0,0 +1,174 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execagg
+
+import (
+	"context"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// getUserDefinedAggregateInfo returns the aggregate constructor and the
+// return type of a user-defined aggregate with the given argument types.
+//
+// The expressions of the state transition and final functions are
+// deserialized once and shared by all aggregate functions created by the
+// constructor. This is safe because the aggregate functions of a processor
+// are never used concurrently.
+func getUserDefinedAggregateInfo(
+	evalCtx *eval.Context,
+	semaCtx *tree.SemaContext,
+	spec *execinfrapb.AggregatorSpec_UserDefinedAggregate,
+	argTypes []*types.T,
+) (AggregateConstructor, *types.T, error) {
+	if spec == nil {
+		return nil, nil, errors.AssertionFailedf("missing user-defined aggregate spec")
+	}
+	if len(argTypes) == 0 {
+		return nil, nil, errors.AssertionFailedf("user-defined aggregate needs at least 1 input")
+	}
+	initCond := tree.Datum(tree.DNull)
+	if !spec.InitCond.Empty() {
+		var h execinfrapb.ExprHelper
+		// Pass nil types and row - there are no variables in the expression.
+		if err := h.Init(spec.InitCond, nil /* types */, semaCtx, evalCtx); err != nil {
+			return nil, nil, err
+		}
+		d, err := h.Eval(nil /* row */)
+		if err != nil {
+			return nil, nil, err
+		}
+		initCond = d
+	}
+	transitionTypes := append([]*types.T{spec.StateType}, argTypes...)
+	s := &userDefinedAggregateShared{
+		initCond:         initCond,
+		transitionStrict: spec.TransitionStrict,
+		finalStrict:      spec.FinalStrict,
+		transitionRow:    make(rowenc.EncDatumRow, len(transitionTypes)),
+		finalRow:         make(rowenc.EncDatumRow, 1),
+	}
+	if err := s.transition.Init(spec.Transition, transitionTypes, semaCtx, evalCtx); err != nil {
+		return nil, nil, err
+	}
+	if err := s.final.Init(spec.Final, []*types.T{spec.StateType}, semaCtx, evalCtx); err != nil {
+		return nil, nil, err
+	}
+	constructor := func(*eval.Context, tree.Datums) eval.AggregateFunc {
+		agg := &userDefinedAggregate{shared: s}
+		agg.Reset(context.TODO())
+		return agg
+	}
+	return constructor, spec.ResultType, nil
+}
+
+// userDefinedAggregateShared contains the state of a user-defined aggregate
+// which is shared between the aggregate functions of all groups.
+type userDefinedAggregateShared struct {
+	initCond         tree.Datum
+	transition       execinfrapb.ExprHelper
+	transitionStrict bool
+	final            execinfrapb.ExprHelper
+	finalStrict      bool
+
+	// transitionRow and finalRow are scratch rows for evaluating the
+	// transition and final expressions.
+	transitionRow rowenc.EncDatumRow
+	finalRow      rowenc.EncDatumRow
+}
+
+// userDefinedAggregate computes a user-defined aggregate created with CREATE
+// AGGREGATE, following the semantics of Postgres: the state starts as the
+// initial condition, and is updated with the state transition function for
+// each input row. If the transition function is strict, rows with any NULL
+// argument are skipped, and if there is no initial condition, the first row
+// provides the state. The result is computed from the final state by the
+// final function.
+type userDefinedAggregate struct {
+	shared *userDefinedAggregateShared
+	state  tree.Datum
+	// noState is true if the initial condition is NULL and no input row has
+	// been accumulated yet.
+	noState bool
+}
+
+var _ eval.AggregateFunc = &userDefinedAggregate{}
+
+// Add is part of the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Add(
+	_ context.Context, firstArg tree.Datum, otherArgs ...tree.Datum,
+) error {
+	s := a.shared
+	if s.transitionStrict {
+		if firstArg == tree.DNull {
+			return nil
+		}
+		for _, arg := range otherArgs {
+			if arg == tree.DNull {
+				return nil
+			}
+		}
+		if a.noState {
+			a.state = firstArg
+			a.noState = false
+			return nil
+		}
+		if a.state == tree.DNull {
+			// A strict function is not called with a NULL state.
+			return nil
+		}
+	}
+	a.noState = false
+	row := s.transitionRow
+	row[0] = rowenc.DatumToEncDatum(s.transition.Types[0], a.state)
+	row[1] = rowenc.DatumToEncDatum(s.transition.Types[1], firstArg)
+	for i, arg := range otherArgs {
+		row[i+2] = rowenc.DatumToEncDatum(s.transition.Types[i+2], arg)
+	}
+	state, err := s.transition.Eval(row)
+	if err != nil {
+		return err
+	}
+	a.state = state
+	return nil
+}
+
+// Result is part of the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Result() (tree.Datum, error) {
+	s := a.shared
+	if s.finalStrict && a.state == tree.DNull {
+		return tree.DNull, nil
+	}
+	s.finalRow[0] = rowenc.DatumToEncDatum(s.final.Types[0], a.state)
+	return s.final.Eval(s.finalRow)
+}
+
+// Reset is part of the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Reset(context.Context) {
+	a.state = a.shared.initCond
+	a.noState = a.state == tree.DNull
+}
+
+// Close is part of the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Close(context.Context) {}
+
+// Size is part of the eval.AggregateFunc interface.
+func (a *userDefinedAggregate) Size() int64 {
+	return sizeOfUserDefinedAggregate
+}
+
+const sizeOfUserDefinedAggregate = int64(unsafe.Sizeof(userDefinedAggregate{}))
@@ -384,6 +384,25 @@ func (d *DistinctSpec) summary() (string, []string) {
 	return "Distinct", details
 }
 
+// summary implements the diagramCellType interface.
+func (e *ExpandSpec) summary() (string, []string) {
+	var buf bytes.Buffer
+	for i, set := range e.GroupingSets {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteByte('(')
+		for j, idx := range set.Columns {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			fmt.Fprintf(&buf, "@%d", e.GroupingColumns[idx]+1)
+		}
+		buf.WriteByte(')')
+	}
+	return "Expand", []string{fmt.Sprintf("Grouping Sets: %s", buf.String())}
+}
+
 // summary implements the diagramCellType interface.
 func (o *OrdinalitySpec) summary() (string, []string) {
 	return "Ordinality", []string{}
@@ -41,6 +41,11 @@ func (a AggregatorSpec_Aggregation) Equals(b AggregatorSpec_Aggregation) bool {
 	if a.Func != b.Func || a.Distinct != b.Distinct {
 		return false
 	}
+	if a.UserDefined != nil || b.UserDefined != nil {
+		// Calls to user-defined aggregates are never considered equal, since
+		// the Func does not identify the aggregate.
+		return false
+	}
 	if a.FilterColIdx == nil {
 		if b.FilterColIdx != nil {
 			return false
@@ -0,0 +1,50 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// expandNode represents a node that replicates each row of its child node
+// once for each grouping set of a GROUP BY with GROUPING SETS, ROLLUP or CUBE.
+// Every output row contains the source columns, followed by a copy of each
+// grouping column (which is NULL if the column is not part of the current
+// grouping set), followed by the ordinal of the grouping set.
+type expandNode struct {
+	source planNode
+
+	// groupingCols are the indexes of the grouping columns in the source.
+	groupingCols []int
+
+	// groupingSets contains, for each grouping set, the indexes (into
+	// groupingCols) of the columns that are part of the set.
+	groupingSets [][]int
+
+	columns colinfo.ResultColumns
+}
+
+func (n *expandNode) startExec(runParams) error {
+	panic("expandNode can't be run in local mode")
+}
+
+func (n *expandNode) Next(params runParams) (bool, error) {
+	panic("expandNode can't be run in local mode")
+}
+
+func (n *expandNode) Values() tree.Datums {
+	panic("expandNode can't be run in local mode")
+}
+
+func (n *expandNode) Close(ctx context.Context) { n.source.Close(ctx) }
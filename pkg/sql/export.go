@@ -77,6 +77,8 @@ const (
 	exportFilePatternPart = "%part%"
 	exportGzipCodec       = "gzip"
 	exportSnappyCodec     = "snappy"
+	exportZstdCodec       = "zstd"
+	exportLZ4Codec        = "lz4"
 	csvSuffix             = "csv"
 	parquetSuffix         = "parquet"
 )
@@ -221,8 +223,12 @@ func (ef *execFactory) ConstructExport(
 		switch {
 		case strings.EqualFold(name, exportGzipCodec):
 			codec = roachpb.IOFileFormat_Gzip
-		case strings.EqualFold(name, exportSnappyCodec) && fileSuffix == parquetSuffix:
+		case strings.EqualFold(name, exportSnappyCodec):
 			codec = roachpb.IOFileFormat_Snappy
+		case strings.EqualFold(name, exportZstdCodec) && fileSuffix == csvSuffix:
+			codec = roachpb.IOFileFormat_Zstd
+		case strings.EqualFold(name, exportLZ4Codec) && fileSuffix == csvSuffix:
+			codec = roachpb.IOFileFormat_LZ4
 		default:
 			return nil, pgerror.Newf(pgcode.InvalidParameterValue,
 				"unsupported compression codec %s for %s file format", name, fileSuffix)
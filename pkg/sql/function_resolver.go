@@ -0,0 +1,124 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+)
+
+// functionResolver is a search path which also resolves user-defined
+// functions stored in the schemas of the current database. Builtin functions
+// take precedence over user-defined functions with the same name.
+type functionResolver struct {
+	*sessiondata.SearchPath
+	p *planner
+}
+
+var _ tree.CustomFunctionDefinitionResolver = &functionResolver{}
+
+// makeFunctionResolver returns a search path for the planner's semantic
+// context which resolves both builtin and user-defined functions.
+func makeFunctionResolver(sp *sessiondata.SearchPath, p *planner) *functionResolver {
+	return &functionResolver{SearchPath: sp, p: p}
+}
+
+// Resolve implements the tree.CustomFunctionDefinitionResolver interface.
+// User-defined functions are only resolved from names qualified with a
+// schema, which tree.ResolveFunction constructs from the search path for
+// unqualified names.
+func (r *functionResolver) Resolve(name string) *tree.FunctionDefinition {
+	if def, ok := tree.FunDefs[name]; ok {
+		return def
+	}
+	scName, fnName, ok := strings.Cut(name, ".")
+	if !ok || r.p.txn == nil || r.p.CurrentDatabase() == "" {
+		return nil
+	}
+	ctx := r.p.EvalContext().Context
+	db, err := r.p.Descriptors().GetImmutableDatabaseByName(
+		ctx, r.p.txn, r.p.CurrentDatabase(), tree.DatabaseLookupFlags{},
+	)
+	if err != nil || db == nil {
+		return nil
+	}
+	sc, err := r.p.Descriptors().GetImmutableSchemaByName(
+		ctx, r.p.txn, db, scName, tree.SchemaLookupFlags{},
+	)
+	if err != nil || sc == nil {
+		return nil
+	}
+	fn, ok := sc.GetFunction(fnName)
+	if !ok || len(fn.Overloads) == 0 {
+		return nil
+	}
+	props := tree.FunctionProperties{
+		// NULL arguments of strict functions are handled when the function
+		// body is inlined.
+		NullableArgs: true,
+	}
+	overloads := make([]tree.Overload, 0, len(fn.Overloads))
+	for _, o := range fn.Overloads {
+		desc, err := r.p.Descriptors().GetImmutableFunctionByID(
+			ctx, r.p.txn, o.ID, tree.ObjectLookupFlags{
+				CommonLookupFlags: tree.CommonLookupFlags{Required: true, AvoidLeased: true},
+			},
+		)
+		if err != nil {
+			return nil
+		}
+		if desc.GetReturnType().ReturnSet {
+			props.Class = tree.GeneratorClass
+		}
+		if !desc.IsAggregate() {
+			overloads = append(overloads, *funcdesc.ToOverload(desc))
+			continue
+		}
+		// NULL arguments of user-defined aggregates are handled by the
+		// aggregate according to the strictness of its state function.
+		props.Class = tree.AggregateClass
+		overload, err := r.aggregateOverload(ctx, desc)
+		if err != nil {
+			return nil
+		}
+		overloads = append(overloads, *overload)
+	}
+	return tree.NewFunctionDefinition(fn.Name, &props, overloads)
+}
+
+// aggregateOverload looks up the support functions of the given user-defined
+// aggregate and returns its overload.
+func (r *functionResolver) aggregateOverload(
+	ctx context.Context, desc catalog.FunctionDescriptor,
+) (*tree.Overload, error) {
+	flags := tree.ObjectLookupFlags{
+		CommonLookupFlags: tree.CommonLookupFlags{Required: true, AvoidLeased: true},
+	}
+	agg := desc.FuncDesc().Aggregate
+	stateFunc, err := r.p.Descriptors().GetImmutableFunctionByID(ctx, r.p.txn, agg.StateFuncID, flags)
+	if err != nil {
+		return nil, err
+	}
+	var finalFunc catalog.FunctionDescriptor
+	if agg.FinalFuncID != descpb.InvalidID {
+		finalFunc, err = r.p.Descriptors().GetImmutableFunctionByID(ctx, r.p.txn, agg.FinalFuncID, flags)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return funcdesc.ToAggregateOverload(desc, stateFunc, finalFunc), nil
+}
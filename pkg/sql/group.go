@@ -14,7 +14,9 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
 // A groupNode implements the planNode interface and handles the grouping logic.
@@ -74,6 +76,10 @@ type aggregateFuncHolder struct {
 	arguments tree.Datums
 	// isDistinct indicates whether only distinct values are aggregated.
 	isDistinct bool
+	// userDefined is set if the function is a user-defined aggregate, in
+	// which case resultType is the type of its result.
+	userDefined *exec.UserDefinedAggInfo
+	resultType  *types.T
 }
 
 // newAggregateFuncHolder creates an aggregateFuncHolder.
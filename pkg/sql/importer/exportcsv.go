@@ -12,7 +12,6 @@ package importer
 
 import (
 	"bytes"
-	"compress/gzip"
 	"context"
 	"fmt"
 	"strings"
@@ -41,7 +40,8 @@ const (
 // and csv writer, encapsulating the internals to make
 // exporting oblivious for the consumers.
 type csvExporter struct {
-	compressor *gzip.Writer
+	codec      cloud.CompressionCodec
+	compressor cloud.Compressor
 	buf        *bytes.Buffer
 	csvWriter  *csv.Writer
 }
@@ -96,38 +96,51 @@ func (c *csvExporter) FileName(spec execinfrapb.ExportSpec, part string) string
 	}
 
 	fileName := strings.Replace(pattern, exportFilePatternPart, part, -1)
-	// TODO: add suffix based on compressor type
-	if c.compressor != nil {
-		fileName += ".gz"
-	}
-	return fileName
+	return fileName + c.codec.Extension()
 }
 
-func newCSVExporter(sp execinfrapb.ExportSpec) *csvExporter {
-	buf := bytes.NewBuffer([]byte{})
-	var exporter *csvExporter
-	switch sp.Format.Compression {
+// exportCompressionCodec returns the codec which exported files are compressed
+// with.
+func exportCompressionCodec(
+	compression roachpb.IOFileFormat_Compression,
+) (cloud.CompressionCodec, error) {
+	switch compression {
+	case roachpb.IOFileFormat_Auto, roachpb.IOFileFormat_None:
+		return cloud.NoCompression, nil
 	case roachpb.IOFileFormat_Gzip:
-		{
-			writer := gzip.NewWriter(buf)
-			exporter = &csvExporter{
-				compressor: writer,
-				buf:        buf,
-				csvWriter:  csv.NewWriter(writer),
-			}
-		}
+		return cloud.CompressionGzip, nil
+	case roachpb.IOFileFormat_Zstd:
+		return cloud.CompressionZstd, nil
+	case roachpb.IOFileFormat_Snappy:
+		return cloud.CompressionSnappy, nil
+	case roachpb.IOFileFormat_LZ4:
+		return cloud.CompressionLZ4, nil
 	default:
-		{
-			exporter = &csvExporter{
-				buf:       buf,
-				csvWriter: csv.NewWriter(buf),
-			}
+		return cloud.NoCompression, errors.Errorf("unsupported export compression %s", compression)
+	}
+}
+
+func newCSVExporter(sp execinfrapb.ExportSpec) (*csvExporter, error) {
+	codec, err := exportCompressionCodec(sp.Format.Compression)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer([]byte{})
+	exporter := &csvExporter{
+		codec:     codec,
+		buf:       buf,
+		csvWriter: csv.NewWriter(buf),
+	}
+	if codec != cloud.NoCompression {
+		if exporter.compressor, err = cloud.NewCompressor(buf, codec, 0 /* level */); err != nil {
+			return nil, err
 		}
+		exporter.csvWriter = csv.NewWriter(exporter.compressor)
 	}
 	if sp.Format.Csv.Comma != 0 {
 		exporter.csvWriter.Comma = sp.Format.Csv.Comma
 	}
-	return exporter
+	return exporter, nil
 }
 
 func newCSVWriterProcessor(
@@ -188,7 +201,10 @@ func (sp *csvWriter) Run(ctx context.Context) {
 
 		alloc := &tree.DatumAlloc{}
 
-		writer := newCSVExporter(sp.spec)
+		writer, err := newCSVExporter(sp.spec)
+		if err != nil {
+			return err
+		}
 
 		var nullsAs string
 		if sp.spec.Format.Csv.NullEncoding != nil {
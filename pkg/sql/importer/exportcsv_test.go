@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/config"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -383,6 +384,56 @@ func TestExportOrderCompressed(t *testing.T) {
 	}
 }
 
+func TestExportCompressionCodecs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	dir, cleanupDir := testutils.TempDir(t)
+	defer cleanupDir()
+
+	srv, db, _ := serverutils.StartServer(t, base.TestServerArgs{ExternalIODir: dir})
+	defer srv.Stopper().Stop(context.Background())
+	sqlDB := sqlutils.MakeSQLRunner(db)
+
+	sqlDB.Exec(t, `CREATE TABLE foo (i INT PRIMARY KEY, x INT)`)
+	sqlDB.Exec(t, `INSERT INTO foo VALUES (1, 12), (2, 22), (3, 32)`)
+
+	for _, codec := range []cloud.CompressionCodec{
+		cloud.CompressionZstd, cloud.CompressionSnappy, cloud.CompressionLZ4,
+	} {
+		t.Run(string(codec), func(t *testing.T) {
+			sqlDB.Exec(t, fmt.Sprintf(
+				`EXPORT INTO CSV 'nodelocal://0/%[1]s' WITH compression = %[1]s FROM SELECT * FROM foo`, codec))
+			compressed := readFileByGlob(t, filepath.Join(dir, string(codec), exportFilePattern+codec.Extension()))
+
+			r, err := cloud.NewDecompressingReader(bytes.NewReader(compressed), codec)
+			require.NoError(t, err)
+			defer r.Close()
+			content, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, "1,12\n2,22\n3,32\n", string(content))
+		})
+	}
+
+	// The exported files can be imported back, with the codec detected from
+	// their extension.
+	for _, codec := range []cloud.CompressionCodec{
+		cloud.CompressionGzip, cloud.CompressionZstd, cloud.CompressionSnappy, cloud.CompressionLZ4,
+	} {
+		t.Run("import/"+string(codec), func(t *testing.T) {
+			dest := "roundtrip_" + string(codec)
+			sqlDB.Exec(t, fmt.Sprintf(
+				`EXPORT INTO CSV 'nodelocal://0/%s' WITH compression = %s FROM SELECT * FROM foo`, dest, codec))
+			sqlDB.Exec(t, fmt.Sprintf(`CREATE TABLE %s (i INT PRIMARY KEY, x INT)`, dest))
+			sqlDB.Exec(t, fmt.Sprintf(`IMPORT INTO %[1]s CSV DATA ('nodelocal://0/%[1]s/*')`, dest))
+			sqlDB.CheckQueryResults(t, fmt.Sprintf(`SELECT * FROM %s ORDER BY i`, dest),
+				sqlDB.QueryStr(t, `SELECT * FROM foo ORDER BY i`))
+		})
+	}
+
+	sqlDB.ExpectErr(t, `unsupported compression codec zstd for parquet file format`,
+		`EXPORT INTO PARQUET 'nodelocal://0/parquet' WITH compression = zstd FROM SELECT * FROM foo`)
+}
+
 func TestExportShow(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
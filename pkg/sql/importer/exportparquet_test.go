@@ -25,7 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
-	"github.com/cockroachdb/cockroach/pkg/sql/importer"
+	"github.com/cockroachdb/cockroach/pkg/sql/parquetenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/randgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -139,7 +139,7 @@ func validateParquetFile(
 				require.Equal(t, ok, false)
 				continue
 			}
-			parquetCol, err := importer.NewParquetColumn(test.cols[j].Typ, "", false)
+			parquetCol, err := parquetenc.NewColumn(test.cols[j].Typ, "", false)
 			if err != nil {
 				return err
 			}
@@ -255,7 +255,7 @@ func TestRandomParquetExports(t *testing.T) {
 					require.NoError(t, err)
 
 					for _, col := range cols {
-						_, err := importer.NewParquetColumn(col.Typ, "", false)
+						_, err := parquetenc.NewColumn(col.Typ, "", false)
 						if err != nil {
 							_, err = sqlDB.DB.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s DROP COLUMN %s`, tableName, col.Name))
 							if err != nil {
@@ -23,6 +23,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/importer"
+	"github.com/cockroachdb/cockroach/pkg/sql/parquetenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/randgen"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -301,7 +302,7 @@ func TestImportAvroLogicalTypes(t *testing.T) {
 	execParams := []logicalAvroExec{{
 		name: "stringed",
 		encoder: func(datum tree.Datum, avroTypes string) (interface{}, error) {
-			val := importer.RoundtripStringer(datum)
+			val := parquetenc.RoundtripStringer(datum)
 			if val == "NULL" {
 				return nil, nil
 			}
@@ -324,6 +324,12 @@ func decompressingReader(
 		return gzip.NewReader(in)
 	case roachpb.IOFileFormat_Bzip:
 		return ioutil.NopCloser(bzip2.NewReader(in)), nil
+	case roachpb.IOFileFormat_Snappy:
+		return cloud.NewDecompressingReader(in, cloud.CompressionSnappy)
+	case roachpb.IOFileFormat_Zstd:
+		return cloud.NewDecompressingReader(in, cloud.CompressionZstd)
+	case roachpb.IOFileFormat_LZ4:
+		return cloud.NewDecompressingReader(in, cloud.CompressionLZ4)
 	default:
 		return ioutil.NopCloser(in), nil
 	}
@@ -340,6 +346,12 @@ func guessCompressionFromName(
 		return roachpb.IOFileFormat_Gzip
 	case strings.HasSuffix(name, ".bz2") || strings.HasSuffix(name, ".bz"):
 		return roachpb.IOFileFormat_Bzip
+	case strings.HasSuffix(name, ".sz"):
+		return roachpb.IOFileFormat_Snappy
+	case strings.HasSuffix(name, ".zst"):
+		return roachpb.IOFileFormat_Zstd
+	case strings.HasSuffix(name, ".lz4"):
+		return roachpb.IOFileFormat_LZ4
 	default:
 		if parsed, err := url.Parse(name); err == nil && parsed.Path != name {
 			return guessCompressionFromName(parsed.Path, hint)
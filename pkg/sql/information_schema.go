@@ -1243,6 +1243,14 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 				tbNameStr := tree.NewDString(table.GetName())
 
 				for conName, c := range conInfo {
+					// Like Postgres, exclude EXCLUDE constraints, which are not part
+					// of the SQL standard.
+					if c.Kind == descpb.ConstraintTypeExclusion {
+						continue
+					}
+					deferrability := c.Deferrability()
+					isDeferrable := yesOrNoDatum(deferrability.IsDeferrable())
+					initiallyDeferred := yesOrNoDatum(deferrability == tree.ConstraintInitiallyDeferred)
 					if err := addRow(
 						dbNameStr,                       // constraint_catalog
 						scNameStr,                       // constraint_schema
@@ -1251,8 +1259,8 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 						scNameStr,                       // table_schema
 						tbNameStr,                       // table_name
 						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						isDeferrable,                    // is_deferrable
+						initiallyDeferred,               // initially_deferred
 					); err != nil {
 						return err
 					}
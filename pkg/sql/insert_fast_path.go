@@ -14,6 +14,8 @@ import (
 	"context"
 	"sync"
 
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
@@ -70,6 +72,11 @@ type insertFastPathRun struct {
 
 	// fkBatch accumulates the FK existence checks.
 	fkBatch roachpb.BatchRequest
+	// fkKeyLocking is the locking strength of the FK existence checks. Under
+	// READ COMMITTED isolation, the referenced rows are locked so that they
+	// can't be deleted before the transaction commits (see
+	// execbuilder.buildCheckQuery).
+	fkKeyLocking lock.Strength
 	// fkSpanInfo keeps track of information for each fkBatch.Request entry.
 	fkSpanInfo []insertFastPathFKSpanInfo
 
@@ -191,6 +198,7 @@ func (r *insertFastPathRun) addFKChecks(
 		r.fkBatch.Requests = append(r.fkBatch.Requests, roachpb.RequestUnion{})
 		r.fkBatch.Requests[reqIdx].MustSetInner(&roachpb.ScanRequest{
 			RequestHeader: roachpb.RequestHeaderFromSpan(span),
+			KeyLocking:    r.fkKeyLocking,
 		})
 		r.fkSpanInfo = append(r.fkSpanInfo, insertFastPathFKSpanInfo{
 			check:  c,
@@ -240,6 +248,9 @@ func (n *insertFastPathNode) startExec(params runParams) error {
 	}
 
 	if len(n.run.fkChecks) > 0 {
+		if params.p.Txn().IsoLevel() == kv.ReadCommitted {
+			n.run.fkKeyLocking = lock.Exclusive
+		}
 		for i := range n.run.fkChecks {
 			if err := n.run.fkChecks[i].init(params); err != nil {
 				return err
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 )
 
 // Table is an interface to a database table, exposing only the information
@@ -137,6 +138,22 @@ type Table interface {
 	// i < UniqueCount.
 	Unique(i UniqueOrdinal) UniqueConstraint
 
+	// ExclusionConstraintCount returns the number of EXCLUDE constraints that
+	// must be enforced by mutations of this table.
+	ExclusionConstraintCount() int
+
+	// ExclusionConstraint returns the ith EXCLUDE constraint of this table,
+	// where i < ExclusionConstraintCount.
+	ExclusionConstraint(i int) ExclusionConstraint
+
+	// TriggerCount returns the number of row-level triggers defined on this
+	// table.
+	TriggerCount() int
+
+	// Trigger returns the ith trigger defined on this table, where
+	// i < TriggerCount. Triggers are returned in the order in which they fire.
+	Trigger(i int) Trigger
+
 	// Zone returns a table's zone.
 	Zone() Zone
 
@@ -260,6 +277,10 @@ type ForeignKeyConstraint interface {
 	// UpdateReferenceAction returns the action to be performed if the foreign key
 	// constraint would be violated by an update.
 	UpdateReferenceAction() tree.ReferenceAction
+
+	// Deferrability returns whether the checks for the constraint can be
+	// postponed until the end of the transaction.
+	Deferrability() tree.ConstraintDeferrability
 }
 
 // UniqueConstraint represents a uniqueness constraint. UniqueConstraints may
@@ -305,6 +326,46 @@ type UniqueConstraint interface {
 	// satisfied when building functional dependencies for the table. This enables
 	// additional optimizations, such as omission of uniqueness checks.
 	UniquenessGuaranteedByAnotherIndex() bool
+
+	// Deferrability returns whether the checks for the constraint can be
+	// postponed until the end of the transaction. Only constraints that are not
+	// enforced by an index can be deferrable.
+	Deferrability() tree.ConstraintDeferrability
+}
+
+// ExclusionConstraint represents an EXCLUDE constraint, which guarantees that
+// no two rows of a table conflict with each other. Two rows conflict if each of
+// the columns of the constraint compares true using the operator of that
+// column. For example, the following statement creates a constraint which
+// guarantees that no room is booked twice for the same time slot:
+//   CREATE TABLE bookings (
+//     room INT, slots INT[], EXCLUDE USING GIST (room WITH =, slots WITH &&)
+//   )
+// In order to enforce the constraint, the optimizer must add a check as a
+// postquery to any query that inserts into or updates its columns.
+type ExclusionConstraint interface {
+	// Name of the exclusion constraint.
+	Name() string
+
+	// TableID returns the stable identifier of the table on which this
+	// constraint is defined.
+	TableID() StableID
+
+	// ColumnCount returns the number of columns in this constraint.
+	ColumnCount() int
+
+	// ColumnOrdinal returns the table column ordinal of the ith column in this
+	// constraint.
+	ColumnOrdinal(tab Table, i int) int
+
+	// Operator returns the operator with which the ith column of two rows is
+	// compared. It is either treecmp.EQ or treecmp.Overlaps.
+	Operator(i int) treecmp.ComparisonOperatorSymbol
+
+	// Predicate returns the partial predicate expression and true if the
+	// constraint only applies to the rows satisfying the predicate. Otherwise,
+	// the empty string and false are returned.
+	Predicate() (string, bool)
 }
 
 // UniqueOrdinal identifies a unique constraint (in the context of a Table).
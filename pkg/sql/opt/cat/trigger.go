@@ -0,0 +1,44 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import "github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+
+// Trigger is an interface to a row-level table trigger, exposing only the
+// information needed by the query optimizer.
+type Trigger interface {
+	// Name is the name of the trigger. It is unique within the table.
+	Name() tree.Name
+
+	// ActionTime returns whether the trigger fires before or after the row is
+	// modified.
+	ActionTime() tree.TriggerActionTime
+
+	// HasEvent returns true if the trigger fires for the given kind of row
+	// modification.
+	HasEvent(event tree.TriggerEvent) bool
+
+	// Body is the SQL body of the trigger function. References to the NEW and
+	// OLD rows are column references qualified with "new" and "old".
+	Body() string
+}
+
+// HasTriggers returns true if the table has a trigger with the given action
+// time that fires for the given event.
+func HasTriggers(tab Table, actionTime tree.TriggerActionTime, event tree.TriggerEvent) bool {
+	for i, n := 0, tab.TriggerCount(); i < n; i++ {
+		t := tab.Trigger(i)
+		if t.ActionTime() == actionTime && t.HasEvent(event) {
+			return true
+		}
+	}
+	return false
+}
@@ -95,15 +95,19 @@ func FormatTable(cat Catalog, tab Table, tp treeprinter.Node) {
 	}
 
 	for i := 0; i < tab.UniqueCount(); i++ {
-		var withoutIndexStr string
+		var withoutIndexStr, deferrableStr string
 		uniq := tab.Unique(i)
 		if uniq.WithoutIndex() {
 			withoutIndexStr = "WITHOUT INDEX "
 		}
+		if d := uniq.Deferrability(); d.IsDeferrable() {
+			deferrableStr = " " + d.String()
+		}
 		c := child.Childf(
-			"UNIQUE %s%s",
+			"UNIQUE %s%s%s",
 			withoutIndexStr,
 			formatCols(tab, tab.Unique(i).ColumnCount(), tab.Unique(i).ColumnOrdinal),
+			deferrableStr,
 		)
 		if pred, isPartial := uniq.Predicate(); isPartial {
 			c.Childf("WHERE %s", pred)
@@ -220,6 +224,10 @@ func formatCatalogFKRef(
 		fmt.Fprintf(&extra, " ON DELETE %s", action.String())
 	}
 
+	if d := fkRef.Deferrability(); d.IsDeferrable() {
+		fmt.Fprintf(&extra, " %s", d)
+	}
+
 	tp.Childf(
 		"%s %s FOREIGN KEY %v %s REFERENCES %v %s%s",
 		title,
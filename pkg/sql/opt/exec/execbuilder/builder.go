@@ -11,6 +11,7 @@
 package execbuilder
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
@@ -67,7 +68,7 @@ type Builder struct {
 
 	// checks accumulates check queries that are run after the main query and
 	// any cascades.
-	checks []exec.Node
+	checks []exec.Check
 
 	// nameGen is used to generate names for the tables that will be created for
 	// each relational subexpression when evalCtx.SessionData.SaveTablesPrefix is
@@ -279,6 +280,13 @@ func (b *Builder) boundedStaleness() bool {
 		b.evalCtx.AsOfSystemTime.BoundedStaleness
 }
 
+// isReadCommitted returns true if this query runs in a READ COMMITTED
+// transaction.
+func (b *Builder) isReadCommitted() bool {
+	return b.evalCtx != nil && b.evalCtx.Txn != nil &&
+		b.evalCtx.Txn.IsoLevel() == kv.ReadCommitted
+}
+
 // mdVarContainer is an IndexedVarContainer implementation used by BuildScalar -
 // it maps indexed vars to columns in the metadata.
 type mdVarContainer struct {
@@ -295,6 +295,11 @@ func (cb *cascadeBuilder) planCascade(
 
 	// 5. Execbuild the optimized expression.
 	eb := New(execFactory, &o, factory.Memo(), cb.b.catalog, optimizedExpr, evalCtx, allowAutoCommit)
+	// Under READ COMMITTED isolation, the cascade locks the rows it reads, for
+	// the same reasons as the constraint checks (see buildCheckQuery). This
+	// prevents it from missing the referencing rows written by concurrent
+	// transactions.
+	eb.forceForUpdateLocking = eb.isReadCommitted()
 	if bufferRef != nil {
 		// Set up the With binding.
 		eb.addBuiltWithExpr(cascadeInputWithID, bufferColMap, bufferRef)
@@ -33,9 +33,12 @@ func (b *Builder) buildMutationInput(
 	mutExpr, inputExpr memo.RelExpr, colList opt.ColList, p *memo.MutationPrivate,
 ) (execPlan, error) {
 	if b.shouldApplyImplicitLockingToMutationInput(mutExpr) {
-		// Re-entrance is not possible because mutations are never nested.
+		// Mutations are never nested, but the locking may already be forced by
+		// a cascade under READ COMMITTED isolation, so restore the previous
+		// value rather than resetting it.
+		prev := b.forceForUpdateLocking
 		b.forceForUpdateLocking = true
-		defer func() { b.forceForUpdateLocking = false }()
+		defer func() { b.forceForUpdateLocking = prev }()
 	}
 
 	input, err := b.buildRelational(inputExpr)
@@ -169,6 +172,11 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 			return execPlan{}, false, nil
 		}
 		fk := tab.OutboundForeignKey(c.FKOrdinal)
+		if fk.Deferrability().IsDeferrable() {
+			// Deferrable FK checks may need to be postponed until commit, which
+			// the fast path doesn't support.
+			return execPlan{}, false, nil
+		}
 		lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
 		if !isLookupJoin || lookupJoin.JoinType != opt.AntiJoinOp {
 			// Not a lookup anti-join.
@@ -490,8 +498,14 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	//
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
-	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PartialIndexDelCols))
+	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PassthroughCols)+len(del.PartialIndexDelCols))
 	colList = appendColsWhenPresent(colList, del.FetchCols)
+	// The RETURNING clause of the Delete can refer to the columns in any of the
+	// USING tables. As a result, the Delete may need to passthrough those
+	// columns so the projection above can use them.
+	if del.NeedResults() {
+		colList = append(colList, del.PassthroughCols...)
+	}
 	colList = appendColsWhenPresent(colList, del.PartialIndexDelCols)
 
 	input, err := b.buildMutationInput(del, del.Input, colList, &del.MutationPrivate)
@@ -504,11 +518,22 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	tab := md.Table(del.Table)
 	fetchColOrds := ordinalSetFromColList(del.FetchCols)
 	returnColOrds := ordinalSetFromColList(del.ReturnCols)
+
+	// Construct the result columns for the passthrough set.
+	var passthroughCols colinfo.ResultColumns
+	if del.NeedResults() {
+		for _, passthroughCol := range del.PassthroughCols {
+			colMeta := b.mem.Metadata().ColumnMeta(passthroughCol)
+			passthroughCols = append(passthroughCols, colinfo.ResultColumn{Name: colMeta.Alias, Typ: colMeta.Type})
+		}
+	}
+
 	node, err := b.factory.ConstructDelete(
 		input.root,
 		tab,
 		fetchColOrds,
 		returnColOrds,
+		passthroughCols,
 		b.allowAutoCommit && len(del.FKChecks) == 0 && len(del.FKCascades) == 0,
 	)
 	if err != nil {
@@ -549,6 +574,10 @@ func (b *Builder) tryBuildDeleteRange(del *memo.DeleteExpr) (_ execPlan, ok bool
 	}
 
 	tab := b.mem.Metadata().Table(del.Table)
+	if cat.HasTriggers(tab, tree.TriggerActionTimeAfter, tree.TriggerEventDelete) {
+		// AFTER DELETE triggers need the values of the deleted rows.
+		return execPlan{}, false, nil
+	}
 	if tab.DeletableIndexCount() > 1 {
 		// Any secondary index prevents fast path, because separate delete batches
 		// must be formulated to delete rows from them.
@@ -677,7 +706,7 @@ func mutationOutputColMap(mutation memo.RelExpr) opt.ColMap {
 }
 
 // buildUniqueChecks builds uniqueness check queries. These check queries are
-// used to enforce UNIQUE WITHOUT INDEX constraints.
+// used to enforce UNIQUE WITHOUT INDEX and EXCLUDE constraints.
 //
 // The checks consist of queries that will only return rows if a constraint is
 // violated. Those queries are each wrapped in an ErrorIfRows operator, which
@@ -687,23 +716,38 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 	for i := range checks {
 		c := &checks[i]
 		// Construct the query that returns uniqueness violations.
-		query, err := b.buildRelational(c.Check)
+		query, err := b.buildCheckQuery(c.Check)
 		if err != nil {
 			return err
 		}
-		// Wrap the query in an error node.
+		keyOrdinals := checkKeyOrdinals(query, c.KeyCols)
 		mkErr := func(row tree.Datums) error {
-			keyVals := make(tree.Datums, len(c.KeyCols))
-			for i, col := range c.KeyCols {
-				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
+			keyVals := checkKeyVals(row, keyOrdinals)
+			if c.Exclusion {
+				return mkExclusionCheckErr(md, c, keyVals)
 			}
 			return mkUniqueCheckErr(md, c, keyVals)
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		if c.Exclusion {
+			// Exclusion constraints are never deferrable.
+			ec := md.TableMeta(c.Table).Table.ExclusionConstraint(c.CheckOrdinal)
+			check, err := b.buildCheck(query, mkErr, keyOrdinals, tree.ConstraintNotDeferrable)
+			if err != nil {
+				return err
+			}
+			check.TableID = ec.TableID()
+			check.ConstraintName = ec.Name()
+			b.checks = append(b.checks, check)
+			continue
+		}
+		uc := md.TableMeta(c.Table).Table.Unique(c.CheckOrdinal)
+		check, err := b.buildCheck(query, mkErr, keyOrdinals, uc.Deferrability())
 		if err != nil {
 			return err
 		}
-		b.checks = append(b.checks, node)
+		check.TableID = uc.TableID()
+		check.ConstraintName = uc.Name()
+		b.checks = append(b.checks, check)
 	}
 	return nil
 }
@@ -713,27 +757,100 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 	for i := range checks {
 		c := &checks[i]
 		// Construct the query that returns FK violations.
-		query, err := b.buildRelational(c.Check)
+		query, err := b.buildCheckQuery(c.Check)
 		if err != nil {
 			return err
 		}
-		// Wrap the query in an error node.
+		keyOrdinals := checkKeyOrdinals(query, c.KeyCols)
 		mkErr := func(row tree.Datums) error {
-			keyVals := make(tree.Datums, len(c.KeyCols))
-			for i, col := range c.KeyCols {
-				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
-			}
-			return mkFKCheckErr(md, c, keyVals)
+			return mkFKCheckErr(md, c, checkKeyVals(row, keyOrdinals))
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		fk := fkCheckConstraint(md, c)
+		check, err := b.buildCheck(query, mkErr, keyOrdinals, fk.Deferrability())
 		if err != nil {
 			return err
 		}
-		b.checks = append(b.checks, node)
+		check.TableID = fk.OriginTableID()
+		check.ConstraintName = fk.Name()
+		check.FKInbound = !c.FKOutbound
+		b.checks = append(b.checks, check)
 	}
 	return nil
 }
 
+// buildCheckQuery builds the query of a constraint check. Under READ COMMITTED
+// isolation, each statement reads from its own snapshot and doesn't see the
+// uncommitted writes of concurrent transactions, so, like Postgres does, the
+// query locks the rows it reads as a SELECT ... FOR UPDATE would. Concurrent
+// transactions then can't modify these rows until the transaction commits, and
+// the query waits for those which already wrote rows it reads. If it finds a
+// row committed after the statement's snapshot, the statement is retried from
+// a new snapshot.
+func (b *Builder) buildCheckQuery(check memo.RelExpr) (execPlan, error) {
+	if b.isReadCommitted() {
+		prev := b.forceForUpdateLocking
+		b.forceForUpdateLocking = true
+		defer func() { b.forceForUpdateLocking = prev }()
+	}
+	return b.buildRelational(check)
+}
+
+// buildCheck builds the exec.Check for the given query, which returns the rows
+// violating a constraint. The query of a constraint which is not deferrable is
+// wrapped in an ErrorIfRows operator, which will throw the error generated by
+// mkErr if the query returns any rows. The query of a deferrable constraint is
+// left as is, since its violations may have to be queued until the transaction
+// commits; mkErr is then run by the execution engine if they are not.
+func (b *Builder) buildCheck(
+	query execPlan,
+	mkErr exec.MkErrFn,
+	keyOrdinals []exec.NodeColumnOrdinal,
+	deferrability tree.ConstraintDeferrability,
+) (exec.Check, error) {
+	if deferrability.IsDeferrable() {
+		return exec.Check{
+			Node:          query.root,
+			Deferrability: deferrability,
+			MkErr:         mkErr,
+			KeyOrdinals:   keyOrdinals,
+		}, nil
+	}
+	node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+	if err != nil {
+		return exec.Check{}, err
+	}
+	return exec.Check{Node: node, Deferrability: deferrability}, nil
+}
+
+// checkKeyOrdinals returns the ordinals of the given key columns in the output
+// of a check query.
+func checkKeyOrdinals(query execPlan, keyCols opt.ColList) []exec.NodeColumnOrdinal {
+	keyOrdinals := make([]exec.NodeColumnOrdinal, len(keyCols))
+	for i, col := range keyCols {
+		keyOrdinals[i] = query.getNodeColumnOrdinal(col)
+	}
+	return keyOrdinals
+}
+
+// checkKeyVals returns the values of the key columns in a row returned by a
+// check query.
+func checkKeyVals(row tree.Datums, keyOrdinals []exec.NodeColumnOrdinal) tree.Datums {
+	keyVals := make(tree.Datums, len(keyOrdinals))
+	for i, ord := range keyOrdinals {
+		keyVals[i] = row[ord]
+	}
+	return keyVals
+}
+
+// fkCheckConstraint returns the foreign key constraint verified by the given
+// FK check.
+func fkCheckConstraint(md *opt.Metadata, c *memo.FKChecksItem) cat.ForeignKeyConstraint {
+	if c.FKOutbound {
+		return md.TableMeta(c.OriginTable).Table.OutboundForeignKey(c.FKOrdinal)
+	}
+	return md.TableMeta(c.ReferencedTable).Table.InboundForeignKey(c.FKOrdinal)
+}
+
 // mkUniqueCheckErr generates a user-friendly error describing a uniqueness
 // violation. The keyVals are the values that correspond to the
 // cat.UniqueConstraint columns.
@@ -776,6 +893,47 @@ func mkUniqueCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.D
 	)
 }
 
+// mkExclusionCheckErr generates a user-friendly error describing an exclusion
+// constraint violation. The keyVals are the values that correspond to the
+// cat.ExclusionConstraint columns.
+func mkExclusionCheckErr(md *opt.Metadata, c *memo.UniqueChecksItem, keyVals tree.Datums) error {
+	tabMeta := md.TableMeta(c.Table)
+	ec := tabMeta.Table.ExclusionConstraint(c.CheckOrdinal)
+	constraintName := ec.Name()
+	var msg, details bytes.Buffer
+
+	// Generate an error of the form:
+	//   ERROR:  conflicting key value violates exclusion constraint "foo"
+	//   DETAIL: Key (k)=(2) conflicts with an existing key.
+	msg.WriteString("conflicting key value violates exclusion constraint ")
+	lexbase.EncodeEscapedSQLIdent(&msg, constraintName)
+
+	details.WriteString("Key (")
+	for i := 0; i < ec.ColumnCount(); i++ {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		col := tabMeta.Table.Column(ec.ColumnOrdinal(tabMeta.Table, i))
+		details.WriteString(string(col.ColName()))
+	}
+	details.WriteString(")=(")
+	for i, d := range keyVals {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(d.String())
+	}
+	details.WriteString(") conflicts with an existing key.")
+
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.ExclusionViolation, "%s", msg.String()),
+			constraintName,
+		),
+		details.String(),
+	)
+}
+
 // mkFKCheckErr generates a user-friendly error describing a foreign key
 // violation. The keyVals are the values that correspond to the
 // cat.ForeignKeyConstraint columns.
@@ -911,6 +1069,12 @@ func (b *Builder) canAutoCommit(rel memo.RelExpr) bool {
 
 	switch rel.Op() {
 	case opt.InsertOp, opt.UpsertOp, opt.UpdateOp, opt.DeleteOp:
+		// AFTER triggers run their functions once all rows have been written, so
+		// the transaction cannot be committed together with the last batch.
+		tab := b.mem.Metadata().Table(rel.Private().(*memo.MutationPrivate).Table)
+		if hasAfterTriggers(tab) {
+			return false
+		}
 		// Check that there aren't any more mutations in the input.
 		// TODO(radu): this can go away when all mutations are under top-level
 		// With ops.
@@ -931,6 +1095,16 @@ func (b *Builder) canAutoCommit(rel memo.RelExpr) bool {
 	}
 }
 
+// hasAfterTriggers returns true if the table has any AFTER triggers.
+func hasAfterTriggers(tab cat.Table) bool {
+	for i, n := 0, tab.TriggerCount(); i < n; i++ {
+		if tab.Trigger(i).ActionTime() == tree.TriggerActionTimeAfter {
+			return true
+		}
+	}
+	return false
+}
+
 // forUpdateLocking is the row-level locking mode used by mutations during their
 // initial row scan, when such locking is deemed desirable. The locking mode is
 // equivalent that used by a SELECT ... FOR UPDATE statement.
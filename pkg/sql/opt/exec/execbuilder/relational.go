@@ -239,6 +239,9 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.OrdinalityExpr:
 		ep, err = b.buildOrdinality(t)
 
+	case *memo.ExpandExpr:
+		ep, err = b.buildExpand(t)
+
 	case *memo.MergeJoinExpr:
 		ep, err = b.buildMergeJoin(t)
 
@@ -275,6 +278,12 @@ func (b *Builder) buildRelational(e memo.RelExpr) (execPlan, error) {
 	case *memo.CreateViewExpr:
 		ep, err = b.buildCreateView(t)
 
+	case *memo.CreateFunctionExpr:
+		ep, err = b.buildCreateFunction(t)
+
+	case *memo.CreateTriggerExpr:
+		ep, err = b.buildCreateTrigger(t)
+
 	case *memo.WithExpr:
 		ep, err = b.buildWith(t)
 
@@ -937,12 +946,68 @@ func (b *Builder) buildApplyJoin(join memo.RelExpr) (execPlan, error) {
 	}
 
 	// Now, the cool part! We set up an ApplyJoinPlanRightSideFn which plans the
-	// right side given a particular left side row. We do this planning in a
-	// separate memo, but we use the same exec.Factory.
-	//
+	// right side given a particular left side row.
+	planRightSideFn := b.makeApplyJoinPlanRightSideFn(
+		rightExpr, &rightRequiredProps, leftBoundColMap, withExprs,
+	)
+
+	// The right plan will always produce the columns in the presentation, in
+	// the same order.
+	var rightOutputCols opt.ColMap
+	for i := range rightRequiredProps.Presentation {
+		rightOutputCols.Set(int(rightRequiredProps.Presentation[i].ID), i)
+	}
+	allCols := joinOutputMap(leftPlan.outputCols, rightOutputCols)
+
+	var onExpr tree.TypedExpr
+	if len(*filters) != 0 {
+		scalarCtx := buildScalarCtx{
+			ivh:     tree.MakeIndexedVarHelper(nil /* container */, numOutputColsInMap(allCols)),
+			ivarMap: allCols,
+		}
+		onExpr, err = b.buildScalar(&scalarCtx, filters)
+		if err != nil {
+			return execPlan{}, err
+		}
+	}
+
+	var outputCols opt.ColMap
+	if !joinType.ShouldIncludeRightColsInOutput() {
+		outputCols = leftPlan.outputCols
+	} else {
+		outputCols = allCols
+	}
+
+	ep := execPlan{outputCols: outputCols}
+
+	ep.root, err = b.factory.ConstructApplyJoin(
+		joinType,
+		leftPlan.root,
+		b.presentationToResultColumns(rightRequiredProps.Presentation),
+		onExpr,
+		planRightSideFn,
+	)
+	if err != nil {
+		return execPlan{}, err
+	}
+	return ep, nil
+}
+
+// makeApplyJoinPlanRightSideFn returns an ApplyJoinPlanRightSideFn which plans
+// the right side of an apply join given a particular left side row. Each
+// column of leftBoundColMap is replaced by the value at the mapped ordinal in
+// the left row. We do this planning in a separate memo, but we use the same
+// exec.Factory. The withExprs are pre-populated in the execbuilder of the right
+// side.
+func (b *Builder) makeApplyJoinPlanRightSideFn(
+	rightExpr memo.RelExpr,
+	rightRequiredProps *physical.Required,
+	leftBoundColMap opt.ColMap,
+	withExprs []builtWithExpr,
+) exec.ApplyJoinPlanRightSideFn {
 	// Note: we put o outside of the function so we allocate it only once.
 	var o xform.Optimizer
-	planRightSideFn := func(ef exec.Factory, leftRow tree.Datums) (exec.Plan, error) {
+	return func(ef exec.Factory, leftRow tree.Datums) (exec.Plan, error) {
 		o.Init(b.evalCtx, b.catalog)
 		f := o.Factory()
 
@@ -971,7 +1036,7 @@ func (b *Builder) buildApplyJoin(join memo.RelExpr) (execPlan, error) {
 			}
 			return f.CopyAndReplaceDefault(e, replaceFn)
 		}
-		f.CopyAndReplace(rightExpr, &rightRequiredProps, replaceFn)
+		f.CopyAndReplace(rightExpr, rightRequiredProps, replaceFn)
 
 		newRightSide, err := o.Optimize()
 		if err != nil {
@@ -994,47 +1059,6 @@ func (b *Builder) buildApplyJoin(join memo.RelExpr) (execPlan, error) {
 		}
 		return plan, nil
 	}
-
-	// The right plan will always produce the columns in the presentation, in
-	// the same order.
-	var rightOutputCols opt.ColMap
-	for i := range rightRequiredProps.Presentation {
-		rightOutputCols.Set(int(rightRequiredProps.Presentation[i].ID), i)
-	}
-	allCols := joinOutputMap(leftPlan.outputCols, rightOutputCols)
-
-	var onExpr tree.TypedExpr
-	if len(*filters) != 0 {
-		scalarCtx := buildScalarCtx{
-			ivh:     tree.MakeIndexedVarHelper(nil /* container */, numOutputColsInMap(allCols)),
-			ivarMap: allCols,
-		}
-		onExpr, err = b.buildScalar(&scalarCtx, filters)
-		if err != nil {
-			return execPlan{}, err
-		}
-	}
-
-	var outputCols opt.ColMap
-	if !joinType.ShouldIncludeRightColsInOutput() {
-		outputCols = leftPlan.outputCols
-	} else {
-		outputCols = allCols
-	}
-
-	ep := execPlan{outputCols: outputCols}
-
-	ep.root, err = b.factory.ConstructApplyJoin(
-		joinType,
-		leftPlan.root,
-		b.presentationToResultColumns(rightRequiredProps.Presentation),
-		onExpr,
-		planRightSideFn,
-	)
-	if err != nil {
-		return execPlan{}, err
-	}
-	return ep, nil
 }
 
 // makePresentation creates a Presentation that contains the given columns, in
@@ -1314,6 +1338,18 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 			agg = aggDistinct.Input
 		}
 
+		if udAgg, ok := agg.(*memo.UserDefinedAggExpr); ok {
+			info, err := b.buildUserDefinedAggInfo(udAgg, &input)
+			if err != nil {
+				return execPlan{}, err
+			}
+			info.Distinct = distinct
+			info.Filter = filterOrd
+			aggInfos[i] = info
+			ep.outputCols.Set(int(item.Col), len(groupingColIdx)+i)
+			continue
+		}
+
 		name, _ := memo.FindAggregateOverload(agg)
 
 		// Accumulate variable arguments in argCols and constant arguments in
@@ -1365,6 +1401,39 @@ func (b *Builder) buildGroupBy(groupBy memo.RelExpr) (execPlan, error) {
 	return ep, nil
 }
 
+// buildUserDefinedAggInfo builds the AggInfo of a call to a user-defined
+// aggregate. The arguments of the aggregate must be variables.
+func (b *Builder) buildUserDefinedAggInfo(
+	udAgg *memo.UserDefinedAggExpr, input *execPlan,
+) (exec.AggInfo, error) {
+	argCols := make([]exec.NodeColumnOrdinal, len(udAgg.Args))
+	for i, arg := range udAgg.Args {
+		variable, ok := arg.(*memo.VariableExpr)
+		if !ok {
+			return exec.AggInfo{}, errors.AssertionFailedf("only VariableOp args supported")
+		}
+		argCols[i] = input.getNodeColumnOrdinal(variable.Col)
+	}
+	uda := udAgg.Overload.UDFAggregate
+	finalStrict := false
+	if uda.FinalFunc != nil {
+		finalStrict = uda.FinalFunc.UDFStrict
+	}
+	return exec.AggInfo{
+		FuncName:   udAgg.Name,
+		ResultType: udAgg.Typ,
+		ArgCols:    argCols,
+		UserDefined: &exec.UserDefinedAggInfo{
+			StateType:        uda.StateType,
+			InitCond:         udAgg.InitCond,
+			Transition:       udAgg.Transition,
+			TransitionStrict: uda.StateFunc.UDFStrict,
+			Final:            udAgg.Final,
+			FinalStrict:      finalStrict,
+		},
+	}, nil
+}
+
 func (b *Builder) buildDistinct(distinct memo.RelExpr) (execPlan, error) {
 	private := distinct.Private().(*memo.GroupingPrivate)
 
@@ -1686,6 +1755,45 @@ func (b *Builder) buildOrdinality(ord *memo.OrdinalityExpr) (execPlan, error) {
 	return execPlan{root: node, outputCols: outputCols}, nil
 }
 
+func (b *Builder) buildExpand(expand *memo.ExpandExpr) (execPlan, error) {
+	input, err := b.buildRelational(expand.Input)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	groupingCols := make([]exec.NodeColumnOrdinal, len(expand.InCols))
+	for i, col := range expand.InCols {
+		groupingCols[i] = input.getNodeColumnOrdinal(col)
+	}
+	groupingSets := make([][]int, len(expand.GroupingSets))
+	for i, set := range expand.GroupingSets {
+		groupingSets[i] = make([]int, 0, set.Len())
+		for j, col := range expand.InCols {
+			if set.Contains(col) {
+				groupingSets[i] = append(groupingSets[i], j)
+			}
+		}
+	}
+	setIDColName := b.mem.Metadata().ColumnMeta(expand.SetIDCol).Alias
+
+	node, err := b.factory.ConstructExpand(input.root, groupingCols, groupingSets, setIDColName)
+	if err != nil {
+		return execPlan{}, err
+	}
+
+	// The grouping set columns follow the input columns, and the grouping set
+	// ID column is ordered at the end of the list.
+	outputCols := input.outputCols.Copy()
+	ord := input.numOutputCols()
+	for _, col := range expand.OutCols {
+		outputCols.Set(int(col), ord)
+		ord++
+	}
+	outputCols.Set(int(expand.SetIDCol), ord)
+
+	return execPlan{root: node, outputCols: outputCols}, nil
+}
+
 func (b *Builder) buildIndexJoin(join *memo.IndexJoinExpr) (execPlan, error) {
 	input, err := b.buildRelational(join.Input)
 	if err != nil {
@@ -2658,6 +2766,7 @@ var boundedStalenessAllowList = map[opt.Operator]struct{}{
 	opt.OffsetOp:           {},
 	opt.SortOp:             {},
 	opt.OrdinalityOp:       {},
+	opt.ExpandOp:           {},
 	opt.Max1RowOp:          {},
 	opt.ProjectSetOp:       {},
 	opt.WindowOp:           {},
@@ -11,9 +11,12 @@
 package execbuilder
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -67,6 +70,7 @@ func init() {
 		// Subquery operators.
 		opt.ExistsOp:   (*Builder).buildExistsSubquery,
 		opt.SubqueryOp: (*Builder).buildSubquery,
+		opt.UDFOp:      (*Builder).buildUDF,
 	}
 
 	for _, op := range opt.BoolOperators {
@@ -618,6 +622,92 @@ func (b *Builder) buildSubquery(
 	), nil
 }
 
+// buildUDF builds a call to a user-defined function which was not inlined by
+// the InlineUDF rule, for example because the rule was disabled. The call is
+// run as a subquery: an apply join binds the arguments to the parameters of the
+// function, and its right side plans the body with the argument values. Like
+// other correlated subqueries, calls with arguments which refer to columns of
+// the enclosing query cannot be executed.
+func (b *Builder) buildUDF(ctx *buildScalarCtx, scalar opt.ScalarExpr) (tree.TypedExpr, error) {
+	udf := scalar.(*memo.UDFExpr)
+	var argProps props.Shared
+	for i := range udf.Args {
+		memo.BuildSharedProps(udf.Args[i], &argProps, b.evalCtx)
+	}
+	if !argProps.OuterCols.Empty() {
+		return nil, b.decorrelationError()
+	}
+
+	// Build a single row with the arguments, in the parameter columns.
+	args := make([]tree.TypedExpr, len(udf.Args))
+	for i := range udf.Args {
+		var err error
+		args[i], err = b.buildScalar(&buildScalarCtx{}, udf.Args[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	input, err := b.constructValues([][]tree.TypedExpr{args}, udf.Params)
+	if err != nil {
+		return nil, err
+	}
+	if udf.Overload.UDFStrict && len(args) > 0 {
+		// The body of a strict function is not evaluated, and the call returns
+		// NULL, when any of the arguments is NULL.
+		ivh := tree.MakeIndexedVarHelper(nil /* container */, len(args))
+		var filter tree.TypedExpr
+		for i := range args {
+			arg := ivh.IndexedVarWithType(i, args[i].ResolvedType())
+			var notNull tree.TypedExpr
+			if arg.ResolvedType().Family() == types.TupleFamily {
+				notNull = tree.NewTypedComparisonExpr(
+					treecmp.MakeComparisonOperator(treecmp.IsDistinctFrom), arg, tree.DNull,
+				)
+			} else {
+				notNull = tree.NewTypedIsNotNullExpr(arg)
+			}
+			if filter == nil {
+				filter = notNull
+			} else {
+				filter = tree.NewTypedAndExpr(filter, notNull)
+			}
+		}
+		input.root, err = b.factory.ConstructFilter(input.root, filter, exec.OutputOrdering{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var bodyRequiredProps physical.Required
+	bodyRequiredProps.Presentation = b.makePresentation(udf.Body.Relational().OutputCols)
+	var paramColMap opt.ColMap
+	for i, col := range udf.Params {
+		paramColMap.Set(int(col), i)
+	}
+	withExprs := make([]builtWithExpr, len(b.withExprs))
+	copy(withExprs, b.withExprs)
+	join, err := b.factory.ConstructApplyJoin(
+		descpb.InnerJoin,
+		input.root,
+		b.presentationToResultColumns(bodyRequiredProps.Presentation),
+		nil, /* onCond */
+		b.makeApplyJoinPlanRightSideFn(udf.Body, &bodyRequiredProps, paramColMap, withExprs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	// Only keep the result of the body, which follows the parameter columns.
+	root, err := b.factory.ConstructSimpleProject(
+		join, []exec.NodeColumnOrdinal{exec.NodeColumnOrdinal(len(udf.Params))}, exec.OutputOrdering{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return b.addSubquery(
+		exec.SubqueryOneRow, udf.Typ, root, nil /* originalExpr */, 1, /* rowCount */
+	), nil
+}
+
 // addSubquery adds an entry to b.subqueries and creates a tree.Subquery
 // expression node associated with it.
 func (b *Builder) addSubquery(
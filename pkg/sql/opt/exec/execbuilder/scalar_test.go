@@ -0,0 +1,125 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package execbuilder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/explain"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/props/physical"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/testutils/testcat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+)
+
+// TestBuildUDF tests that a call to a user-defined function which was not
+// inlined, because the InlineUDF rule was disabled, is built as a subquery
+// which binds the arguments with an apply join.
+func TestBuildUDF(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	evalCtx := eval.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	catalog := testcat.New()
+	if _, err := catalog.ExecuteDDL("CREATE TABLE t (a INT PRIMARY KEY)"); err != nil {
+		t.Fatal(err)
+	}
+
+	// build builds the projection of a call to a function which returns its
+	// argument, either with a constant argument or with the column of a scan.
+	build := func(correlated bool) (string, error) {
+		var o xform.Optimizer
+		o.Init(&evalCtx, catalog)
+		o.DisableOptimizations()
+		f := o.Factory()
+		md := f.Metadata()
+
+		param := md.AddColumn("x", types.Int)
+		bodyOut := md.AddColumn("x", types.Int)
+		body := f.ConstructProject(
+			f.ConstructValues(memo.ScalarListWithEmptyTuple, &memo.ValuesPrivate{
+				Cols: opt.ColList{},
+				ID:   md.NextUniqueID(),
+			}),
+			memo.ProjectionsExpr{f.ConstructProjectionsItem(f.ConstructVariable(param), bodyOut)},
+			opt.ColSet{},
+		)
+
+		var input memo.RelExpr
+		var arg opt.ScalarExpr
+		if correlated {
+			tab := md.AddTable(catalog.Table(tree.NewUnqualifiedTableName("t")), tree.NewUnqualifiedTableName("t"))
+			input = f.ConstructScan(&memo.ScanPrivate{Table: tab, Cols: opt.MakeColSet(tab.ColumnID(0))})
+			arg = f.ConstructVariable(tab.ColumnID(0))
+		} else {
+			input = f.ConstructValues(memo.ScalarListWithEmptyTuple, &memo.ValuesPrivate{
+				Cols: opt.ColList{},
+				ID:   md.NextUniqueID(),
+			})
+			arg = f.ConstructConst(tree.NewDInt(1), types.Int)
+		}
+		udf := f.ConstructUDF(
+			memo.ScalarListExpr{arg},
+			body,
+			&memo.UDFPrivate{Name: "f", Typ: types.Int, Params: opt.ColList{param}, Overload: &tree.Overload{}},
+		)
+		if udf.Op() != opt.UDFOp {
+			t.Fatalf("expected the UDF not to be inlined, got %s", udf.Op())
+		}
+		out := md.AddColumn("f", types.Int)
+		root := f.ConstructProject(
+			input, memo.ProjectionsExpr{f.ConstructProjectionsItem(udf, out)}, opt.ColSet{},
+		)
+		f.Memo().SetRoot(root, &physical.Required{
+			Presentation: physical.Presentation{{Alias: "f", ID: out}},
+		})
+		expr, err := o.Optimize()
+		if err != nil {
+			return "", err
+		}
+
+		ef := explain.NewFactory(exec.StubFactory{})
+		plan, err := New(ef, &o, f.Memo(), catalog, expr, &evalCtx, false /* allowAutoCommit */).Build()
+		if err != nil {
+			return "", err
+		}
+		ob := explain.NewOutputBuilder(explain.Flags{})
+		if err := explain.Emit(plan.(*explain.Plan), ob, func(cat.Table, cat.Index, exec.ScanParams) string {
+			return ""
+		}); err != nil {
+			return "", err
+		}
+		return ob.BuildString(), nil
+	}
+
+	res, err := build(false /* correlated */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, node := range []string{"• subquery", "• apply join"} {
+		if !strings.Contains(res, node) {
+			t.Errorf("expected the plan to contain %q, got:\n%s", node, res)
+		}
+	}
+
+	if _, err := build(true /* correlated */); err == nil ||
+		!strings.Contains(err.Error(), "could not decorrelate subquery") {
+		t.Errorf("expected a decorrelation error, got %v", err)
+	}
+}
@@ -73,6 +73,28 @@ func (b *Builder) buildCreateView(cv *memo.CreateViewExpr) (execPlan, error) {
 	return execPlan{root: root}, err
 }
 
+func (b *Builder) buildCreateFunction(cf *memo.CreateFunctionExpr) (execPlan, error) {
+	schema := b.mem.Metadata().Schema(cf.Schema)
+	root, err := b.factory.ConstructCreateFunction(
+		schema,
+		cf.Syntax,
+		cf.FunctionBody,
+		cf.Deps,
+	)
+	return execPlan{root: root}, err
+}
+
+func (b *Builder) buildCreateTrigger(ct *memo.CreateTriggerExpr) (execPlan, error) {
+	table := b.mem.Metadata().Table(ct.Table)
+	root, err := b.factory.ConstructCreateTrigger(
+		table,
+		ct.Syntax,
+		ct.Function,
+		ct.FunctionBody,
+	)
+	return execPlan{root: root}, err
+}
+
 func (b *Builder) buildExplainOpt(explain *memo.ExplainExpr) (execPlan, error) {
 	fmtFlags := memo.ExprFmtHideAll
 	switch {
@@ -283,14 +283,17 @@ var nodeNames = [...]string{
 	cancelSessionsOp:       "cancel sessions",
 	controlJobsOp:          "control jobs",
 	controlSchedulesOp:     "control schedules",
+	createFunctionOp:       "create function",
 	createStatisticsOp:     "create statistics",
 	createTableOp:          "create table",
 	createTableAsOp:        "create table as",
+	createTriggerOp:        "create trigger",
 	createViewOp:           "create view",
 	deleteOp:               "delete",
 	deleteRangeOp:          "delete range",
 	distinctOp:             "distinct",
 	errorIfRowsOp:          "error if rows",
+	expandOp:               "expand",
 	explainOp:              "explain",
 	explainOptOp:           "explain",
 	exportOp:               "export",
@@ -574,6 +577,29 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 			a.Aggregations, nil /* groupCols */, nil /* groupColOrdering */, true, /* isScalar */
 		)
 
+	case expandOp:
+		a := n.args.(*expandArgs)
+		inputCols := a.Input.Columns()
+		var buf bytes.Buffer
+		for i, set := range a.GroupingSets {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteByte('(')
+			for j, idx := range set {
+				if j > 0 {
+					buf.WriteString(", ")
+				}
+				if len(inputCols) > 0 {
+					buf.WriteString(inputCols[a.GroupingCols[idx]].Name)
+				} else {
+					buf.WriteString("_")
+				}
+			}
+			buf.WriteByte(')')
+		}
+		ob.Attr("grouping sets", buf.String())
+
 	case distinctOp:
 		a := n.args.(*distinctArgs)
 		inputCols := a.Input.Columns()
@@ -870,6 +896,8 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 		createTableOp,
 		createTableAsOp,
 		createViewOp,
+		createFunctionOp,
+		createTriggerOp,
 		sequenceSelectOp,
 		saveTableOp,
 		errorIfRowsOp,
@@ -129,7 +129,7 @@ func (f *Factory) ConstructPlan(
 	root exec.Node,
 	subqueries []exec.Subquery,
 	cascades []exec.Cascade,
-	checks []exec.Node,
+	checks []exec.Check,
 	rootRowCount int64,
 ) (exec.Plan, error) {
 	p := &Plan{
@@ -139,7 +139,7 @@ func (f *Factory) ConstructPlan(
 		Checks:     make([]*Node, len(checks)),
 	}
 	for i := range checks {
-		p.Checks[i] = checks[i].(*Node)
+		p.Checks[i] = checks[i].Node.(*Node)
 	}
 
 	wrappedSubqueries := append([]exec.Subquery(nil), subqueries...)
@@ -152,9 +152,9 @@ func (f *Factory) ConstructPlan(
 			wrappedCascades[i].Buffer = wrappedCascades[i].Buffer.(*Node).WrappedNode()
 		}
 	}
-	wrappedChecks := make([]exec.Node, len(checks))
+	wrappedChecks := append([]exec.Check(nil), checks...)
 	for i := range wrappedChecks {
-		wrappedChecks[i] = checks[i].(*Node).WrappedNode()
+		wrappedChecks[i].Node = wrappedChecks[i].Node.(*Node).WrappedNode()
 	}
 	var err error
 	p.WrappedPlan, err = f.wrappedFactory.ConstructPlan(
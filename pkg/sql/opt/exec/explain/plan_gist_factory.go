@@ -29,7 +29,7 @@ import (
 )
 
 func init() {
-	if numOperators != 58 {
+	if numOperators != 61 {
 		// If this error occurs please make sure the new op is the last one in order
 		// to not invalidate existing plan gists/hashes. If we are just adding an
 		// operator at the end there's no need to update version below and we can
@@ -129,7 +129,7 @@ func (f *PlanGistFactory) ConstructPlan(
 	root exec.Node,
 	subqueries []exec.Subquery,
 	cascades []exec.Cascade,
-	checks []exec.Node,
+	checks []exec.Check,
 	rootRowCount int64,
 ) (exec.Plan, error) {
 	plan, err := f.wrappedFactory.ConstructPlan(root, subqueries, cascades, checks, rootRowCount)
@@ -94,6 +94,17 @@ func getResultColumns(
 			Typ:  types.Int,
 		}), nil
 
+	case expandOp:
+		a := args.(*expandArgs)
+		others := make([]colinfo.ResultColumn, 0, len(a.GroupingCols)+1)
+		if inputs[0] != nil {
+			for _, col := range a.GroupingCols {
+				others = append(others, inputs[0][col])
+			}
+		}
+		others = append(others, colinfo.ResultColumn{Name: a.SetIDColName, Typ: types.Int})
+		return appendColumns(inputs[0], others...), nil
+
 	case groupByOp:
 		a := args.(*groupByArgs)
 		return groupByColumns(inputs[0], a.GroupCols, a.Aggregations), nil
@@ -188,8 +199,9 @@ func getResultColumns(
 		}
 		return colinfo.ShowTraceColumns, nil
 
-	case createTableOp, createTableAsOp, createViewOp, controlJobsOp, controlSchedulesOp,
-		cancelQueriesOp, cancelSessionsOp, createStatisticsOp, errorIfRowsOp, deleteRangeOp:
+	case createTableOp, createTableAsOp, createViewOp, createFunctionOp, createTriggerOp,
+		controlJobsOp, controlSchedulesOp, cancelQueriesOp, cancelSessionsOp, createStatisticsOp,
+		errorIfRowsOp, deleteRangeOp:
 		// These operations produce no columns.
 		return nil, nil
 
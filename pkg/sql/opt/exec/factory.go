@@ -152,6 +152,37 @@ type AggInfo struct {
 	// Filter is the index of the column, if any, which should be used as the
 	// FILTER condition for the aggregate. If there is no filter, Filter is -1.
 	Filter NodeColumnOrdinal
+
+	// UserDefined is set if the aggregate is a user-defined aggregate created
+	// with CREATE AGGREGATE, in which case FuncName is the name of the
+	// aggregate.
+	UserDefined *UserDefinedAggInfo
+}
+
+// UserDefinedAggInfo represents the information about a user-defined
+// aggregate that must be passed through to the execution engine.
+type UserDefinedAggInfo struct {
+	// StateType is the type of the aggregate state.
+	StateType *types.T
+
+	// InitCond is the initial value of the state, or DNull.
+	InitCond tree.Datum
+
+	// Transition computes the next state. IndexedVar 0 refers to the current
+	// state, and the following IndexedVars refer to the arguments.
+	Transition tree.TypedExpr
+
+	// TransitionStrict is true if the state transition function returns NULL
+	// on NULL input, in which case input rows with any NULL argument are
+	// skipped.
+	TransitionStrict bool
+
+	// Final computes the result of the aggregate from IndexedVar 0, the final
+	// state.
+	Final tree.TypedExpr
+
+	// FinalStrict is true if the final function returns NULL on NULL input.
+	FinalStrict bool
 }
 
 // WindowInfo represents the information about a window function that must be
@@ -247,6 +278,44 @@ type Cascade struct {
 	) (Plan, error)
 }
 
+// Check describes a constraint check query (e.g. a foreign key or uniqueness
+// check). The query is run after the main query and all cascades; it doesn't
+// return results but generates an error if the constraint is violated.
+//
+// The query of a deferrable constraint instead returns the rows which violate
+// the constraint, so that they can be queued until the transaction commits.
+// MkErr generates the error for such a row when the check is not deferred.
+type Check struct {
+	// Node is the root of the check query.
+	Node Node
+
+	// TableID is the ID of the table that owns the constraint. For foreign key
+	// checks, this is the origin (referencing) table.
+	TableID cat.StableID
+
+	// ConstraintName is the name of the constraint being checked.
+	ConstraintName string
+
+	// Deferrability indicates whether the check can be postponed until the end
+	// of the transaction, and whether it is postponed by default.
+	Deferrability tree.ConstraintDeferrability
+
+	// MkErr generates the error for a row returned by Node. It is only set for
+	// deferrable constraints.
+	MkErr MkErrFn
+
+	// KeyOrdinals are the ordinals of the columns returned by Node which hold
+	// the values of the constraint columns, in the order of the constraint
+	// columns. For foreign key checks, these are the columns of the origin
+	// table for outbound checks, and those of the referenced table for inbound
+	// checks. It is only set for deferrable constraints.
+	KeyOrdinals []NodeColumnOrdinal
+
+	// FKInbound is set for foreign key checks run because rows of the
+	// referenced table were updated or deleted.
+	FKInbound bool
+}
+
 // InsertFastPathFKCheck contains information about a foreign key check to be
 // performed by the insert fast-path (see ConstructInsertFastPath). It
 // identifies the index into which we can perform the lookup.
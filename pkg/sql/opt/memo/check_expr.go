@@ -334,6 +334,23 @@ func (m *Memo) CheckExpr(e opt.Expr) {
 			panic(errors.AssertionFailedf("with ordering can only be specified with forced materialization"))
 		}
 
+	case *ExpandExpr:
+		if len(t.InCols) != len(t.OutCols) {
+			panic(errors.AssertionFailedf("mismatched number of Expand input and output columns"))
+		}
+		if len(t.GroupingSets) == 0 {
+			panic(errors.AssertionFailedf("Expand must have at least one grouping set"))
+		}
+		inCols := t.InCols.ToSet()
+		if !inCols.SubsetOf(t.Input.Relational().OutputCols) {
+			panic(errors.AssertionFailedf("Expand grouping columns %v are not input columns", inCols))
+		}
+		for _, set := range t.GroupingSets {
+			if !set.SubsetOf(inCols) {
+				panic(errors.AssertionFailedf("grouping set %v is not a subset of %v", set, inCols))
+			}
+		}
+
 	case *WithScanExpr:
 		// Verify the input columns exist in the binding.
 		binding := m.Metadata().WithBinding(t.With)
@@ -643,6 +643,29 @@ func (f *WindowFrame) String() string {
 	return bld.String()
 }
 
+// GroupingSets is the list of grouping sets produced by an Expand operator.
+// Each grouping set is a subset of the Expand's input grouping columns. The
+// same set can appear more than once, in which case the groups for that set
+// are produced once for each occurrence.
+type GroupingSets []opt.ColSet
+
+func (g GroupingSets) String() string {
+	var bld strings.Builder
+	for i, set := range g {
+		if i > 0 {
+			bld.WriteByte(' ')
+		}
+		bld.WriteString(set.String())
+	}
+	return bld.String()
+}
+
+// OutColSet returns the subset of the Expand's OutCols which hold the values
+// of the columns in the i-th grouping set.
+func (e *ExpandPrivate) OutColSet(i int) opt.ColSet {
+	return opt.TranslateColSet(e.GroupingSets[i], e.InCols, e.OutCols)
+}
+
 // IsCanonical returns true if the ScanPrivate indicates an original unaltered
 // primary index Scan operator (i.e. unconstrained and not limited).
 func (s *ScanPrivate) IsCanonical() bool {
@@ -213,7 +213,8 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 		*WindowExpr, *OpaqueRelExpr, *OpaqueMutationExpr, *OpaqueDDLExpr,
 		*AlterTableSplitExpr, *AlterTableUnsplitExpr, *AlterTableUnsplitAllExpr,
 		*AlterTableRelocateExpr, *AlterRangeRelocateExpr, *ControlJobsExpr, *CancelQueriesExpr,
-		*CancelSessionsExpr, *CreateViewExpr, *ExportExpr:
+		*CancelSessionsExpr, *CreateViewExpr, *CreateFunctionExpr, *CreateTriggerExpr,
+		*ExportExpr:
 		fmt.Fprintf(f.Buffer, "%v", e.Op())
 		FormatPrivate(f, e.Private(), required)
 
@@ -320,6 +321,16 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			tp.Childf("error: \"%s\"", private.ErrorOnDup)
 		}
 
+	// Special-case handling for Expand; print the input grouping columns, the
+	// corresponding output columns and the grouping sets.
+	case *ExpandExpr:
+		if !f.HasFlags(ExprFmtHideColumns) {
+			f.formatColList(e, tp, "grouping columns:", t.InCols)
+			f.formatColList(e, tp, "grouping set columns:", t.OutCols)
+			f.formatColList(e, tp, "grouping set id:", opt.ColList{t.SetIDCol})
+		}
+		tp.Childf("grouping sets: %s", t.GroupingSets)
+
 	case *TopKExpr:
 		if !f.HasFlags(ExprFmtHidePhysProps) && !t.Ordering.Any() {
 			tp.Childf("internal-ordering: %s", t.Ordering)
@@ -673,6 +684,18 @@ func (f *ExprFmtCtx) formatRelational(e RelExpr, tp treeprinter.Node) {
 			n.Child(f.Buffer.String())
 		}
 
+	case *CreateFunctionExpr:
+		tp.Child(t.FunctionBody)
+
+		n := tp.Child("dependencies")
+		for _, dep := range t.Deps {
+			name := dep.DataSource.Name()
+			n.Child(name.String())
+		}
+
+	case *CreateTriggerExpr:
+		tp.Child(t.FunctionBody)
+
 	case *CreateStatisticsExpr:
 		tp.Child(t.Syntax.String())
 
@@ -1094,6 +1117,19 @@ func (f *ExprFmtCtx) formatScalarPrivate(scalar opt.ScalarExpr) {
 
 	case *UniqueChecksItem:
 		tab := f.Memo.metadata.TableMeta(t.Table)
+		if t.Exclusion {
+			constraint := tab.Table.ExclusionConstraint(t.CheckOrdinal)
+			fmt.Fprintf(f.Buffer, ": %s EXCLUDE (", tab.Alias.ObjectName)
+			for i := 0; i < constraint.ColumnCount(); i++ {
+				if i > 0 {
+					f.Buffer.WriteString(", ")
+				}
+				col := tab.Table.Column(constraint.ColumnOrdinal(tab.Table, i))
+				fmt.Fprintf(f.Buffer, "%s WITH %s", col.ColName(), constraint.Operator(i))
+			}
+			f.Buffer.WriteByte(')')
+			break
+		}
 		constraint := tab.Table.Unique(t.CheckOrdinal)
 		fmt.Fprintf(f.Buffer, ": %s(", tab.Alias.ObjectName)
 		for i := 0; i < constraint.ColumnCount(); i++ {
@@ -1512,6 +1548,12 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 	case *FunctionPrivate:
 		fmt.Fprintf(f.Buffer, " %s", t.Name)
 
+	case *UDFPrivate:
+		fmt.Fprintf(f.Buffer, " %s", t.Name)
+
+	case *UserDefinedAggPrivate:
+		fmt.Fprintf(f.Buffer, " %s", t.Name)
+
 	case *WindowsItemPrivate:
 		fmt.Fprintf(f.Buffer, " frame=%q", &t.Frame)
 
@@ -1556,6 +1598,14 @@ func FormatPrivate(f *ExprFmtCtx, private interface{}, physProps *physical.Requi
 		schema := f.Memo.Metadata().Schema(t.Schema)
 		fmt.Fprintf(f.Buffer, " %s.%s", schema.Name(), t.ViewName)
 
+	case *CreateFunctionPrivate:
+		schema := f.Memo.Metadata().Schema(t.Schema)
+		fmt.Fprintf(f.Buffer, " %s.%s", schema.Name(), t.Syntax.FuncName.Object())
+
+	case *CreateTriggerPrivate:
+		tab := f.Memo.Metadata().Table(t.Table)
+		fmt.Fprintf(f.Buffer, " %s ON %s", t.Syntax.Name, tab.Name())
+
 	case *JoinPrivate:
 		// Nothing to show; flags are shown separately.
 
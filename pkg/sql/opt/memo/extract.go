@@ -132,6 +132,16 @@ func ExtractAggInputColumns(e opt.ScalarExpr) opt.ColSet {
 		panic(errors.AssertionFailedf("not an Aggregate"))
 	}
 
+	if udAgg, ok := e.(*UserDefinedAggExpr); ok {
+		// The arguments of a user-defined aggregate are held in a list.
+		for _, arg := range udAgg.Args {
+			if variable, ok := arg.(*VariableExpr); ok {
+				res.Add(variable.Col)
+			}
+		}
+		return res
+	}
+
 	for i, n := 0, e.ChildCount(); i < n; i++ {
 		if variable, ok := e.Child(i).(*VariableExpr); ok {
 			res.Add(variable.Col)
@@ -614,6 +614,13 @@ func (h *hasher) HashWindowFrame(val WindowFrame) {
 	h.HashInt(int(val.FrameExclusion))
 }
 
+func (h *hasher) HashGroupingSets(val GroupingSets) {
+	for _, set := range val {
+		h.HashInt(set.Len())
+		h.HashColSet(set)
+	}
+}
+
 func (h *hasher) HashTupleOrdinal(val TupleOrdinal) {
 	h.HashUint64(uint64(val))
 }
@@ -1029,6 +1036,18 @@ func (h *hasher) IsWindowFrameEqual(l, r WindowFrame) bool {
 		l.FrameExclusion == r.FrameExclusion
 }
 
+func (h *hasher) IsGroupingSetsEqual(l, r GroupingSets) bool {
+	if len(l) != len(r) {
+		return false
+	}
+	for i := range l {
+		if !l[i].Equals(r[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 func (h *hasher) IsTupleOrdinalEqual(l, r TupleOrdinal) bool {
 	return l == r
 }
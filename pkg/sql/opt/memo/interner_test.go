@@ -491,6 +491,14 @@ func TestInterner(t *testing.T) {
 			},
 		}},
 
+		{hashFn: in.hasher.HashGroupingSets, eqFn: in.hasher.IsGroupingSetsEqual, variations: []testVariation{
+			{val1: GroupingSets{}, val2: GroupingSets{}, equal: true},
+			{val1: GroupingSets{opt.MakeColSet(1, 2), opt.ColSet{}}, val2: GroupingSets{opt.MakeColSet(1, 2), opt.ColSet{}}, equal: true},
+			{val1: GroupingSets{opt.MakeColSet(1, 2), opt.ColSet{}}, val2: GroupingSets{opt.ColSet{}, opt.MakeColSet(1, 2)}, equal: false},
+			{val1: GroupingSets{opt.MakeColSet(1), opt.ColSet{}}, val2: GroupingSets{opt.MakeColSet(1)}, equal: false},
+			{val1: GroupingSets{opt.MakeColSet(1, 2)}, val2: GroupingSets{opt.MakeColSet(1), opt.MakeColSet(2)}, equal: false},
+		}},
+
 		{hashFn: in.hasher.HashTupleOrdinal, eqFn: in.hasher.IsTupleOrdinalEqual, variations: []testVariation{
 			{val1: TupleOrdinal(0), val2: TupleOrdinal(0), equal: true},
 			{val1: TupleOrdinal(0), val2: TupleOrdinal(1), equal: false},
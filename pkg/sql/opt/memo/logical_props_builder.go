@@ -1275,6 +1275,74 @@ func (b *logicalPropsBuilder) buildOrdinalityProps(ord *OrdinalityExpr, rel *pro
 	}
 }
 
+func (b *logicalPropsBuilder) buildExpandProps(expand *ExpandExpr, rel *props.Relational) {
+	BuildSharedProps(expand, &rel.Shared, b.evalCtx)
+
+	inputProps := expand.Input.Relational()
+
+	// Output Columns
+	// --------------
+	// Output columns are the input columns, plus the grouping set columns and
+	// the grouping set id column.
+	rel.OutputCols = inputProps.OutputCols.Union(expand.OutCols.ToSet())
+	rel.OutputCols.Add(expand.SetIDCol)
+
+	// Not Null Columns
+	// ----------------
+	// Input columns inherit the not null property from the input. The grouping
+	// set id column is never null. A grouping set column is only not null if
+	// its input column is not null and it is part of every grouping set.
+	rel.NotNullCols = inputProps.NotNullCols.Copy()
+	rel.NotNullCols.Add(expand.SetIDCol)
+	for i, inCol := range expand.InCols {
+		if !inputProps.NotNullCols.Contains(inCol) {
+			continue
+		}
+		inAllSets := true
+		for _, set := range expand.GroupingSets {
+			if !set.Contains(inCol) {
+				inAllSets = false
+				break
+			}
+		}
+		if inAllSets {
+			rel.NotNullCols.Add(expand.OutCols[i])
+		}
+	}
+
+	// Outer Columns
+	// -------------
+	// Outer columns were already derived by BuildSharedProps.
+
+	// Functional Dependencies
+	// -----------------------
+	// Expand is a cross product of the input with the grouping sets, so start
+	// with the input FDs and add the grouping set id column as the key of the
+	// other side of the product. Each grouping set column is determined by its
+	// input column and the grouping set id.
+	rel.FuncDeps.CopyFrom(&inputProps.FuncDeps)
+	var setFDs props.FuncDepSet
+	setIDCols := opt.MakeColSet(expand.SetIDCol)
+	setFDs.AddStrictKey(setIDCols, setIDCols)
+	rel.FuncDeps.MakeProduct(&setFDs)
+	for i, inCol := range expand.InCols {
+		rel.FuncDeps.AddSynthesizedCol(opt.MakeColSet(inCol, expand.SetIDCol), expand.OutCols[i])
+	}
+	rel.FuncDeps.MakeNotNull(rel.NotNullCols)
+
+	// Cardinality
+	// -----------
+	// Each input row is produced once per grouping set.
+	numSets := uint32(len(expand.GroupingSets))
+	rel.Cardinality = inputProps.Cardinality.Product(props.Cardinality{Min: numSets, Max: numSets})
+
+	// Statistics
+	// ----------
+	if !b.disableStats {
+		b.sb.buildExpand(expand, rel)
+	}
+}
+
 func (b *logicalPropsBuilder) buildWindowProps(window *WindowExpr, rel *props.Relational) {
 	BuildSharedProps(window, &rel.Shared, b.evalCtx)
 
@@ -1485,6 +1553,18 @@ func (b *logicalPropsBuilder) buildCreateViewProps(cv *CreateViewExpr, rel *prop
 	BuildSharedProps(cv, &rel.Shared, b.evalCtx)
 }
 
+func (b *logicalPropsBuilder) buildCreateFunctionProps(
+	cf *CreateFunctionExpr, rel *props.Relational,
+) {
+	BuildSharedProps(cf, &rel.Shared, b.evalCtx)
+}
+
+func (b *logicalPropsBuilder) buildCreateTriggerProps(
+	ct *CreateTriggerExpr, rel *props.Relational,
+) {
+	BuildSharedProps(ct, &rel.Shared, b.evalCtx)
+}
+
 func (b *logicalPropsBuilder) buildFiltersItemProps(item *FiltersItem, scalar *props.Scalar) {
 	BuildSharedProps(item.Condition, &scalar.Shared, b.evalCtx)
 
@@ -1617,6 +1697,34 @@ func BuildSharedProps(e opt.Expr, shared *props.Shared, evalCtx *eval.Context) {
 	case *FunctionExpr:
 		shared.VolatilitySet.Add(t.Overload.Volatility)
 
+	case *UserDefinedAggExpr:
+		shared.VolatilitySet.Add(t.Overload.Volatility)
+
+	case *UDFExpr:
+		// The UDF will be inlined as a subquery. Its body refers to the
+		// parameter columns as outer columns, but they are bound by the call,
+		// so they are not outer columns of the UDF expression.
+		shared.HasSubquery = true
+		shared.VolatilitySet.Add(t.Overload.Volatility)
+		BuildSharedProps(&t.Args, shared, evalCtx)
+		if hasOuterCols(&t.Args) {
+			shared.HasCorrelatedSubquery = true
+		}
+		body := &t.Body.Relational().Shared
+		outerCols := body.OuterCols.Difference(t.Params.ToSet())
+		if !outerCols.Empty() {
+			shared.OuterCols.UnionWith(outerCols)
+			shared.HasCorrelatedSubquery = true
+		}
+		if body.HasPlaceholder {
+			shared.HasPlaceholder = true
+		}
+		shared.VolatilitySet.UnionWith(body.VolatilitySet)
+		if body.CanMutate {
+			shared.CanMutate = true
+		}
+		return
+
 	case *CastExpr, *AssignmentCastExpr:
 		from := e.Child(0).(opt.ScalarExpr).DataType()
 		to := e.Private().(*types.T)
@@ -1706,6 +1814,8 @@ func hasOuterCols(e opt.Expr) bool {
 		return !t.Relational().OuterCols.Empty()
 	case ScalarPropsExpr:
 		return !t.ScalarProps().Shared.OuterCols.Empty()
+	case *UDFExpr:
+		return !getOuterCols(t).Empty()
 	}
 
 	for i, n := 0, e.ChildCount(); i < n; i++ {
@@ -1727,6 +1837,10 @@ func getOuterCols(e opt.Expr) opt.ColSet {
 		return t.Relational().OuterCols
 	case ScalarPropsExpr:
 		return t.ScalarProps().Shared.OuterCols
+	case *UDFExpr:
+		res := getOuterCols(&t.Args)
+		res.UnionWith(t.Body.Relational().OuterCols.Difference(t.Params.ToSet()))
+		return res
 	}
 
 	var res opt.ColSet
@@ -461,6 +461,9 @@ func (sb *statisticsBuilder) colStat(colSet opt.ColSet, e RelExpr) *props.Column
 	case opt.OrdinalityOp:
 		return sb.colStatOrdinality(colSet, e.(*OrdinalityExpr))
 
+	case opt.ExpandOp:
+		return sb.colStatExpand(colSet, e.(*ExpandExpr))
+
 	case opt.WindowOp:
 		return sb.colStatWindow(colSet, e.(*WindowExpr))
 
@@ -2281,6 +2284,80 @@ func (sb *statisticsBuilder) colStatOrdinality(
 	return colStat
 }
 
+// +--------+
+// | Expand |
+// +--------+
+
+func (sb *statisticsBuilder) buildExpand(expand *ExpandExpr, relProps *props.Relational) {
+	s := &relProps.Stats
+	if zeroCardinality := s.Init(relProps); zeroCardinality {
+		// Short cut if cardinality is 0.
+		return
+	}
+	s.Available = sb.availabilityFromInput(expand)
+
+	// Each input row is produced once per grouping set.
+	inputStats := &expand.Input.Relational().Stats
+	s.RowCount = inputStats.RowCount * float64(len(expand.GroupingSets))
+
+	sb.finalizeFromCardinality(relProps)
+}
+
+func (sb *statisticsBuilder) colStatExpand(
+	colSet opt.ColSet, expand *ExpandExpr,
+) *props.ColumnStatistic {
+	relProps := expand.Relational()
+	s := &relProps.Stats
+	numSets := float64(len(expand.GroupingSets))
+	outCols := expand.OutCols.ToSet()
+
+	colStat, _ := s.ColStats.Add(colSet)
+
+	// Map the requested grouping set columns to the corresponding input
+	// columns, and calculate the statistics from the input.
+	inputCols := colSet.Difference(outCols)
+	inputCols.Remove(expand.SetIDCol)
+	inputCols.UnionWith(opt.TranslateColSet(colSet.Intersection(outCols), expand.OutCols, expand.InCols))
+	var inputColStat *props.ColumnStatistic
+	if inputCols.Empty() {
+		colStat.DistinctCount = 1
+		colStat.NullCount = 0
+	} else {
+		inputColStat = sb.colStatFromChild(inputCols, expand, 0 /* childIdx */)
+		colStat.DistinctCount = inputColStat.DistinctCount
+		colStat.NullCount = inputColStat.NullCount * numSets
+		colStat.AvgSize = inputColStat.AvgSize
+	}
+
+	// Every distinct value of the input columns can appear once per grouping
+	// set in the grouping set columns.
+	if colSet.Intersects(outCols) || colSet.Contains(expand.SetIDCol) {
+		colStat.DistinctCount *= numSets
+	}
+	if colSet.Contains(expand.SetIDCol) {
+		colStat.AvgSize += defaultColSize
+	}
+
+	// If only grouping set columns are requested, all of them are NULL in the
+	// rows produced for grouping sets that contain none of them.
+	if inputColStat != nil && colSet.SubsetOf(outCols) {
+		colStat.NullCount = 0
+		for i := range expand.GroupingSets {
+			if colSet.Intersects(expand.OutColSet(i)) {
+				colStat.NullCount += inputColStat.NullCount
+			} else {
+				colStat.NullCount += s.RowCount / numSets
+			}
+		}
+	}
+
+	if colSet.Intersects(relProps.NotNullCols) {
+		colStat.NullCount = 0
+	}
+	sb.finalizeFromRowCountAndDistinctCounts(colStat, s)
+	return colStat
+}
+
 // +------------+
 // |   Window   |
 // +------------+
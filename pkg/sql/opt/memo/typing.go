@@ -157,6 +157,9 @@ func FindWindowOverload(e opt.ScalarExpr) (name string, overload *tree.Overload)
 // FindAggregateOverload finds an aggregate function overload that matches the
 // given aggregate function expression. It panics if no match can be found.
 func FindAggregateOverload(e opt.ScalarExpr) (name string, overload *tree.Overload) {
+	if udAgg, ok := e.(*UserDefinedAggExpr); ok {
+		return udAgg.Name, udAgg.Overload
+	}
 	name = opt.AggregateOpReverseMap[e.Op()]
 	_, overload, ok := FindFunction(e, name)
 	if ok {
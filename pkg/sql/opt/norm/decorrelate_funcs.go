@@ -62,6 +62,12 @@ func (c *CustomFuncs) deriveHasHoistableSubquery(scalar opt.ScalarExpr) bool {
 		// WHERE clause, it will be transformed to an Exists operator, so this case
 		// only occurs when the Any is nested, in a projection, etc.
 		return !t.Input.Relational().OuterCols.Empty()
+
+	case *memo.UDFExpr:
+		// The body of a UDF which was not inlined is planned by the execbuilder
+		// with the argument values, so only subqueries in the arguments can be
+		// hoisted.
+		return c.deriveHasHoistableSubquery(&t.Args)
 	}
 
 	// If HasHoistableSubquery is true for any child, then it's true for this
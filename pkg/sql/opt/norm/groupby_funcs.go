@@ -80,7 +80,11 @@ func (c *CustomFuncs) CanRemoveAggDistinctForKeys(
 		return false
 	}
 	inputFDs := &input.Relational().FuncDeps
-	variable := agg.Child(0).(*memo.VariableExpr)
+	variable, ok := agg.Child(0).(*memo.VariableExpr)
+	if !ok {
+		// The arguments of a user-defined aggregate are held in a list.
+		return false
+	}
 	cols := c.AddColToSet(private.GroupingCols, variable.Col)
 	return inputFDs.ColsAreStrictKey(cols)
 }
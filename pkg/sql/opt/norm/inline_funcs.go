@@ -393,3 +393,30 @@ func (c *CustomFuncs) InlineConstVar(f memo.FiltersExpr) memo.FiltersExpr {
 	}
 	return result
 }
+
+// InlineUDF returns a subquery which evaluates the body of a user-defined
+// function with its parameter columns bound to the given arguments. See the
+// InlineUDF rule for more details.
+func (c *CustomFuncs) InlineUDF(
+	args memo.ScalarListExpr, body memo.RelExpr, udfPrivate *memo.UDFPrivate,
+) opt.ScalarExpr {
+	projections := make(memo.ProjectionsExpr, len(args))
+	for i := range args {
+		projections[i] = c.f.ConstructProjectionsItem(args[i], udfPrivate.Params[i])
+	}
+	input := c.f.ConstructProject(c.ConstructNoColsRow(), projections, opt.ColSet{})
+	if udfPrivate.Overload.UDFStrict && len(args) > 0 {
+		filters := make(memo.FiltersExpr, len(args))
+		for i := range args {
+			filters[i] = c.f.ConstructFiltersItem(c.f.ConstructIsNot(
+				c.f.ConstructVariable(udfPrivate.Params[i]), memo.NullSingleton,
+			))
+		}
+		input = c.f.ConstructSelect(input, filters)
+	}
+	join := c.f.ConstructInnerJoinApply(input, body, memo.TrueFilter, memo.EmptyJoinPrivate)
+	return c.f.ConstructSubquery(
+		c.f.ConstructProject(join, memo.EmptyProjectionsExpr, body.Relational().OutputCols),
+		&memo.SubqueryPrivate{},
+	)
+}
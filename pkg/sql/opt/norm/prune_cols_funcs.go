@@ -15,6 +15,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/props"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 )
 
@@ -145,6 +146,17 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		}
 	}
 
+	// AFTER triggers are passed the values of the visible columns of the
+	// modified rows, so all of them must be fetched.
+	if op != opt.UpsertOp && hasAfterTriggers(tabMeta.Table, op) {
+		for i, n := 0, tabMeta.Table.ColumnCount(); i < n; i++ {
+			col := tabMeta.Table.Column(i)
+			if col.Kind() == cat.Ordinary && col.Visibility() == cat.Visible {
+				cols.Add(tabMeta.MetaID.ColumnID(i))
+			}
+		}
+	}
+
 	switch op {
 	case opt.UpdateOp, opt.UpsertOp:
 		// Determine set of target table columns that need to be updated.
@@ -689,3 +701,13 @@ func (c *CustomFuncs) NeededColMapLeft(needed opt.ColSet, set *memo.SetPrivate)
 func (c *CustomFuncs) NeededColMapRight(needed opt.ColSet, set *memo.SetPrivate) opt.ColSet {
 	return opt.TranslateColSetStrict(needed, set.OutCols, set.RightCols)
 }
+
+// hasAfterTriggers returns true if the table has AFTER triggers which fire for
+// the given mutation operator.
+func hasAfterTriggers(tab cat.Table, op opt.Operator) bool {
+	event := tree.TriggerEventDelete
+	if op == opt.UpdateOp {
+		event = tree.TriggerEventUpdate
+	}
+	return cat.HasTriggers(tab, tree.TriggerActionTimeAfter, event)
+}
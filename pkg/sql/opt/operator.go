@@ -330,7 +330,7 @@ func AggregateIgnoresNulls(op Operator) bool {
 		return true
 
 	case ArrayAggOp, ConcatAggOp, ConstAggOp, CountRowsOp, FirstAggOp, JsonAggOp,
-		JsonbAggOp, JsonObjectAggOp, JsonbObjectAggOp:
+		JsonbAggOp, JsonObjectAggOp, JsonbObjectAggOp, UserDefinedAggOp:
 		return false
 
 	default:
@@ -355,7 +355,7 @@ func AggregateIsNullOnEmpty(op Operator) bool {
 		RegressionSXYOp, RegressionSYYOp:
 		return true
 
-	case CountOp, CountRowsOp, RegressionCountOp:
+	case CountOp, CountRowsOp, RegressionCountOp, UserDefinedAggOp:
 		return false
 
 	default:
@@ -388,6 +388,11 @@ func AggregateIsNeverNullOnNonNullInput(op Operator) bool {
 		// These aggregations return NULL if they are given a single not-NULL input.
 		return false
 
+	case UserDefinedAggOp:
+		// The support functions of a user-defined aggregate can return NULL for
+		// any input.
+		return false
+
 	default:
 		panic(errors.AssertionFailedf("unhandled op %s", redact.Safe(op)))
 	}
@@ -435,7 +440,7 @@ func AggregatesCanMerge(inner, outer Operator) bool {
 		SqrDiffOp, STCollectOp, StdDevOp, StringAggOp, VarianceOp, StdDevPopOp,
 		VarPopOp, CovarPopOp, CovarSampOp, RegressionAvgXOp, RegressionAvgYOp,
 		RegressionInterceptOp, RegressionR2Op, RegressionSlopeOp, RegressionSXXOp,
-		RegressionSXYOp, RegressionSYYOp, RegressionCountOp:
+		RegressionSXYOp, RegressionSYYOp, RegressionCountOp, UserDefinedAggOp:
 		return false
 
 	default:
@@ -457,7 +462,7 @@ func AggregateIgnoresDuplicates(op Operator) bool {
 		VarPopOp, JsonObjectAggOp, JsonbObjectAggOp, STCollectOp, CovarPopOp,
 		CovarSampOp, RegressionAvgXOp, RegressionAvgYOp, RegressionInterceptOp,
 		RegressionR2Op, RegressionSlopeOp, RegressionSXXOp, RegressionSXYOp,
-		RegressionSYYOp, RegressionCountOp:
+		RegressionSYYOp, RegressionCountOp, UserDefinedAggOp:
 		return false
 
 	default:
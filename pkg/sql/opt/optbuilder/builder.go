@@ -140,6 +140,11 @@ type Builder struct {
 	// query contains a correlated subquery.
 	isCorrelated bool
 
+	// udfStack contains the OIDs of the user-defined functions whose bodies
+	// are currently being built. It is used to detect recursive functions,
+	// which cannot be inlined.
+	udfStack []oid.Oid
+
 	// areAllTableMutationsSimpleInserts maps from each table mutated by the
 	// statement to true if all mutations of that table are simple inserts
 	// (without ON CONFLICT) or false otherwise. All mutated tables will have an
@@ -269,6 +274,7 @@ func (b *Builder) buildStmt(
 		// A blocklist of statements that can't be used from inside a view.
 		switch stmt := stmt.(type) {
 		case *tree.Delete, *tree.Insert, *tree.Update, *tree.CreateTable, *tree.CreateView,
+			*tree.CreateFunction, *tree.CreateTrigger,
 			*tree.Split, *tree.Unsplit, *tree.Relocate, *tree.RelocateRange,
 			*tree.ControlJobs, *tree.ControlSchedules, *tree.CancelQueries, *tree.CancelSessions:
 			panic(pgerror.Newf(
@@ -305,6 +311,12 @@ func (b *Builder) buildStmt(
 	case *tree.CreateView:
 		return b.buildCreateView(stmt, inScope)
 
+	case *tree.CreateFunction:
+		return b.buildCreateFunction(stmt, inScope)
+
+	case *tree.CreateTrigger:
+		return b.buildCreateTrigger(stmt, inScope)
+
 	case *tree.Explain:
 		return b.buildExplain(stmt, inScope)
 
@@ -0,0 +1,175 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+func (b *Builder) buildCreateFunction(cf *tree.CreateFunction, inScope *scope) (outScope *scope) {
+	b.DisableMemoReuse = true
+	tn := cf.FuncName.ToTableName()
+	sch, _ := b.resolveSchemaForCreate(&tn)
+	schID := b.factory.Metadata().AddSchema(sch)
+
+	body := validateFunctionOptions(cf.Options)
+
+	if tree.IsTriggerReturnType(cf.ReturnType.Type) {
+		return b.buildCreateTriggerFunction(cf, schID, body)
+	}
+
+	// Synthesize a column for each parameter, so that the parameters can be
+	// referenced in the body of the function.
+	paramScope := b.allocScope()
+	for i := range cf.Args {
+		arg := &cf.Args[i]
+		if arg.Class != tree.FunctionArgIn {
+			panic(unimplemented.New("udf-arg-class",
+				fmt.Sprintf("%s arguments are not supported", arg.Class)))
+		}
+		if arg.DefaultVal != nil {
+			panic(unimplemented.New("udf-arg-default",
+				"default values for arguments are not supported"))
+		}
+		typ := b.resolveFunctionType(arg.Type)
+		b.synthesizeColumn(paramScope, scopeColName(arg.Name), typ, nil /* expr */, nil /* scalar */)
+	}
+	retType := b.resolveFunctionType(cf.ReturnType.Type)
+
+	stmt, err := parser.ParseOne(body)
+	if err != nil {
+		panic(err)
+	}
+
+	// We build the function body to:
+	//  - check the body semantically, including that it returns a value of the
+	//    declared return type,
+	//  - get the fully resolved names into the AST, and
+	//  - collect the dependencies of the function in b.viewDeps.
+	// The result is not otherwise used.
+	b.trackViewDeps = true
+	b.qualifyDataSourceNamesInAST = true
+	defer func() {
+		b.trackViewDeps = false
+		b.viewDeps = nil
+		b.qualifyDataSourceNamesInAST = false
+	}()
+	b.buildFunctionBody(
+		cf.FuncName.Object(), stmt.AST, paramScope, retType, !cf.ReturnType.IsSet, /* singleRow */
+	)
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateFunction(
+		&memo.CreateFunctionPrivate{
+			Schema:       schID,
+			Syntax:       cf,
+			FunctionBody: tree.AsStringWithFlags(stmt.AST, tree.FmtParsable),
+			Deps:         b.viewDeps,
+		},
+	)
+	return outScope
+}
+
+// buildCreateTriggerFunction builds a CREATE FUNCTION statement for a
+// function declared with RETURNS TRIGGER. The body of a trigger function
+// references the NEW and OLD rows of the table the trigger is defined on, so
+// it cannot be built until a trigger is created with it. Here it is only
+// checked that the body can be parsed.
+func (b *Builder) buildCreateTriggerFunction(
+	cf *tree.CreateFunction, schID opt.SchemaID, body string,
+) (outScope *scope) {
+	if len(cf.Args) != 0 {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition,
+			"trigger functions cannot have declared arguments"))
+	}
+	if cf.ReturnType.IsSet {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition,
+			"trigger functions cannot return a set"))
+	}
+	stmt, err := parser.ParseOne(body)
+	if err != nil {
+		panic(err)
+	}
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateFunction(
+		&memo.CreateFunctionPrivate{
+			Schema:       schID,
+			Syntax:       cf,
+			FunctionBody: tree.AsStringWithFlags(stmt.AST, tree.FmtParsable),
+		},
+	)
+	return outScope
+}
+
+// resolveFunctionType resolves a type in the signature of a function.
+func (b *Builder) resolveFunctionType(ref tree.ResolvableTypeReference) *types.T {
+	typ, err := tree.ResolveType(b.ctx, ref, b.semaCtx.GetTypeResolver())
+	if err != nil {
+		panic(err)
+	}
+	if typ.UserDefined() {
+		panic(unimplemented.New("udf-user-defined-type",
+			"user-defined types in function signatures are not supported"))
+	}
+	return typ
+}
+
+// validateFunctionOptions checks that the options of a CREATE FUNCTION
+// statement are supported and do not conflict with each other, and returns
+// the body of the function.
+func validateFunctionOptions(options tree.FunctionOptions) string {
+	var seenVolatility, seenLeakproof, seenNullInput, seenLang, seenBody bool
+	var body string
+	checkSeen := func(seen *bool) {
+		if *seen {
+			panic(pgerror.New(pgcode.Syntax, "conflicting or redundant options"))
+		}
+		*seen = true
+	}
+	for _, option := range options {
+		switch t := option.(type) {
+		case tree.FunctionVolatility:
+			checkSeen(&seenVolatility)
+		case tree.FunctionLeakproof:
+			checkSeen(&seenLeakproof)
+		case tree.FunctionNullInputBehavior:
+			checkSeen(&seenNullInput)
+		case tree.FunctionLanguage:
+			checkSeen(&seenLang)
+			if t != tree.FunctionLangSQL {
+				panic(unimplemented.New("udf-language",
+					fmt.Sprintf("language %q is not supported", string(t))))
+			}
+		case tree.FunctionBodyStr:
+			checkSeen(&seenBody)
+			body = string(t)
+		default:
+			panic(errors.AssertionFailedf("unexpected function option %T", t))
+		}
+	}
+	if !seenLang {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition, "no language specified"))
+	}
+	if !seenBody {
+		panic(pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified"))
+	}
+	return body
+}
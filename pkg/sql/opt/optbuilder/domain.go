@@ -0,0 +1,102 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/volatility"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// domainCheckOverload is the overload of the functions which check the
+// constraints of a domain. The checks are not strict, since a NULL value must
+// be checked against the NOT NULL constraint of the domain.
+var domainCheckOverload = &tree.Overload{Volatility: volatility.Immutable}
+
+// buildAssignmentCast builds an assignment cast of the given scalar expression
+// to the given type. If the type is a domain, its constraints are enforced on
+// the result.
+func (b *Builder) buildAssignmentCast(input opt.ScalarExpr, typ *types.T) opt.ScalarExpr {
+	if typ.IsDomain() {
+		return b.buildDomainCoercion(input, typ, true /* assignment */)
+	}
+	return b.factory.ConstructAssignmentCast(input, typ)
+}
+
+// buildDomainCoercion builds the coercion of the given scalar expression to
+// the given domain type. If assignment is true, the input is converted to the
+// base type of the domain with an assignment cast, otherwise an explicit cast
+// is used.
+//
+// If the domain has constraints, the converted value is passed to a function
+// which checks them. The body of the function projects a call to the
+// crdb_internal.domain_not_null and crdb_internal.domain_check builtins, which
+// return their input or raise an error if a constraint is violated. Similar to
+// other functions, the call is inlined as a subquery by the InlineUDF
+// normalization rule, so the input is only evaluated once even though it is
+// referenced by every constraint.
+func (b *Builder) buildDomainCoercion(
+	input opt.ScalarExpr, typ *types.T, assignment bool,
+) opt.ScalarExpr {
+	domain := typ.TypeMeta.DomainData
+	if domain == nil {
+		panic(errors.AssertionFailedf("domain type %s is not hydrated", typ.DomainOID()))
+	}
+	var value opt.ScalarExpr
+	if assignment {
+		value = b.factory.ConstructAssignmentCast(input, typ)
+	} else {
+		value = b.factory.ConstructCast(input, typ)
+	}
+	if !domain.NotNull && len(domain.Checks) == 0 {
+		return value
+	}
+
+	name := typ.TypeMeta.Name.Basename()
+	paramScope := b.allocScope()
+	param := b.synthesizeColumn(
+		paramScope, scopeColName(schemaexpr.DomainValueName), typ, nil /* expr */, nil, /* scalar */
+	)
+	var result tree.Expr = param
+	if domain.NotNull {
+		result = &tree.FuncExpr{
+			Func:  tree.WrapFunction("crdb_internal.domain_not_null"),
+			Exprs: tree.Exprs{result, tree.NewDString(name)},
+		}
+	}
+	for _, c := range domain.Checks {
+		expr, err := parser.ParseExpr(c.Expr)
+		if err != nil {
+			panic(err)
+		}
+		result = &tree.FuncExpr{
+			Func: tree.WrapFunction("crdb_internal.domain_check"),
+			Exprs: tree.Exprs{
+				result, &tree.ParenExpr{Expr: expr}, tree.NewDString(name), tree.NewDString(c.Name),
+			},
+		}
+	}
+	stmt := &tree.Select{Select: &tree.SelectClause{
+		Exprs: tree.SelectExprs{{Expr: result}},
+	}}
+	body := b.buildFunctionBody(name, stmt, paramScope, typ, false /* singleRow */)
+	return b.factory.ConstructUDF(memo.ScalarListExpr{value}, body, &memo.UDFPrivate{
+		Name:     name,
+		Typ:      typ,
+		Params:   opt.ColList{param.id},
+		Overload: domainCheckOverload,
+	})
+}
@@ -34,6 +34,20 @@ package optbuilder
 //   pre-projection:  k+3 (as col1), v*2 (as col2)
 //   aggregation:     group by col1, calculate MIN(col2) (as col3)
 //   post-projection: 1 + col3
+//
+// If the GROUP BY clause contains GROUPING SETS, ROLLUP or CUBE, an Expand
+// operator is added between the pre-projection and the aggregation. It emits
+// each input row once for every grouping set, with a copy of each grouping
+// column that is NULL when the column is not part of the grouping set, and the
+// ordinal of the grouping set. The aggregation then groups on these copies and
+// on the grouping set ordinal. For example:
+//   SELECT k, MIN(v) FROM kv GROUP BY ROLLUP (k)
+//
+//   pre-projection:  k (as col1), v (as col2)
+//   expansion:       grouping sets (col1), (); copy col1 (as col3), grouping
+//                    set ordinal (as col4)
+//   aggregation:     group by col3, col4, calculate MIN(col2) (as col5)
+//   post-projection: col3, col5
 
 import (
 	"context"
@@ -45,6 +59,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -100,6 +115,16 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// expand is non-nil if the GROUP BY clause contains GROUPING SETS, ROLLUP
+	// or CUBE. In that case the grouping columns in aggOutScope (and in
+	// groupStrs) are the OutCols of the Expand operator rather than the
+	// grouping columns in aggInScope.
+	expand *memo.ExpandPrivate
+
+	// setIDCol is the column containing the ordinal of the grouping set that
+	// produced each row. It is only set if expand is non-nil.
+	setIDCol scopeColumn
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -279,6 +304,39 @@ func (a *aggregateInfo) ResolvedType() *types.T {
 var _ tree.Expr = &aggregateInfo{}
 var _ tree.TypedExpr = &aggregateInfo{}
 
+// groupingInfo stores information about a GROUPING function call.
+type groupingInfo struct {
+	*tree.GroupingExpr
+
+	// args are the resolved arguments of the GROUPING call.
+	args []tree.TypedExpr
+}
+
+// Walk is part of the tree.Expr interface.
+func (g *groupingInfo) Walk(v tree.Visitor) tree.Expr {
+	return g
+}
+
+// TypeCheck is part of the tree.Expr interface.
+func (g *groupingInfo) TypeCheck(
+	ctx context.Context, semaCtx *tree.SemaContext, desired *types.T,
+) (tree.TypedExpr, error) {
+	return g, nil
+}
+
+// Eval is part of the tree.TypedExpr interface.
+func (g *groupingInfo) Eval(_ tree.ExprEvaluator) (tree.Datum, error) {
+	panic(errors.AssertionFailedf("groupingInfo must be replaced before evaluation"))
+}
+
+// ResolvedType is part of the tree.TypedExpr interface.
+func (g *groupingInfo) ResolvedType() *types.T {
+	return types.Int
+}
+
+var _ tree.Expr = &groupingInfo{}
+var _ tree.TypedExpr = &groupingInfo{}
+
 func (b *Builder) needsAggregation(sel *tree.SelectClause, scope *scope) bool {
 	// We have an aggregation if:
 	//  - we have a GROUP BY, or
@@ -334,22 +392,90 @@ func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, projectionsScope,
 	// The "from" columns are visible to any grouping expressions.
 	b.buildGroupingList(sel.GroupBy, sel.Exprs, projectionsScope, fromScope)
 
+	if g.expand != nil {
+		b.buildGroupingSetColumns(g)
+		return
+	}
+
 	// Copy the grouping columns to the aggOutScope.
 	g.aggOutScope.appendColumns(g.groupingCols())
 }
 
-// buildAggregation builds the aggregation operators and constructs the
-// GroupBy expression. Returns the output scope for the aggregation operation.
-func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (outScope *scope) {
-	g := fromScope.groupby
-
+// buildGroupingSetColumns synthesizes the columns produced by the Expand
+// operator for a GROUP BY with grouping sets: a copy of each grouping column,
+// which is NULL when the column is not part of the grouping set of the row,
+// and a column with the ordinal of the grouping set. The copies are added to
+// the aggOutScope and replace the original grouping columns in groupStrs, so
+// that references to grouping expressions in the SELECT list, HAVING and
+// ORDER BY clauses observe the NULLs.
+func (b *Builder) buildGroupingSetColumns(g *groupby) {
+	md := b.factory.Metadata()
 	groupingCols := g.groupingCols()
 
-	// Build ColSet of grouping columns.
+	// The same column can appear more than once in the grouping columns (e.g.
+	// GROUP BY ROLLUP (a, t.a)), but it is only expanded once.
+	outCols := make([]scopeColumn, 0, len(groupingCols))
+	outColByInCol := make(map[opt.ColumnID]int, len(groupingCols))
+	for i := range groupingCols {
+		inCol := &groupingCols[i]
+		if _, ok := outColByInCol[inCol.id]; ok {
+			continue
+		}
+		outColByInCol[inCol.id] = len(outCols)
+		outCol := *inCol
+		outCol.id = md.AddColumn(inCol.name.MetadataName(), inCol.typ)
+		outCol.scalar = nil
+		outCols = append(outCols, outCol)
+		g.expand.InCols = append(g.expand.InCols, inCol.id)
+		g.expand.OutCols = append(g.expand.OutCols, outCol.id)
+	}
+	g.aggOutScope.appendColumns(outCols)
+
+	for exprStr, col := range g.groupStrs {
+		g.groupStrs[exprStr] = &outCols[outColByInCol[col.id]]
+	}
+
+	g.setIDCol = scopeColumn{
+		name: scopeColName("grouping_set"),
+		typ:  types.Int,
+	}
+	g.setIDCol.id = md.AddColumn(g.setIDCol.name.MetadataName(), types.Int)
+	g.expand.SetIDCol = g.setIDCol.id
+}
+
+// constructExpand wraps the input of the aggregation with an Expand operator
+// if the GROUP BY clause contains grouping sets. Otherwise, it returns the
+// input unchanged.
+func (b *Builder) constructExpand(g *groupby, input memo.RelExpr) memo.RelExpr {
+	if g.expand == nil {
+		return input
+	}
+	return b.factory.ConstructExpand(input, g.expand)
+}
+
+// aggregationGroupingCols returns the set of columns that the GroupBy operator
+// groups on.
+func (g *groupby) aggregationGroupingCols() opt.ColSet {
 	var groupingColSet opt.ColSet
+	if g.expand != nil {
+		groupingColSet = g.expand.OutCols.ToSet()
+		groupingColSet.Add(g.expand.SetIDCol)
+		return groupingColSet
+	}
+	groupingCols := g.groupingCols()
 	for i := range groupingCols {
 		groupingColSet.Add(groupingCols[i].id)
 	}
+	return groupingColSet
+}
+
+// buildAggregation builds the aggregation operators and constructs the
+// GroupBy expression. Returns the output scope for the aggregation operation.
+func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (outScope *scope) {
+	g := fromScope.groupby
+
+	// Build ColSet of grouping columns.
+	groupingColSet := g.aggregationGroupingCols()
 
 	// If there are any aggregates that are ordering sensitive, build the
 	// aggregations as window functions over each group.
@@ -385,7 +511,11 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 
 		// Construct the aggregate function from its name and arguments and store
 		// it in the corresponding scope column.
-		aggCols[i].scalar = b.constructAggregate(agg.def.Name, args)
+		if agg.def.Overload.UDFAggregate != nil {
+			aggCols[i].scalar = b.buildUserDefinedAgg(&aggInfos[i], args)
+		} else {
+			aggCols[i].scalar = b.constructAggregate(agg.def.Name, args)
+		}
 
 		// Wrap the aggregate function with an AggDistinct operator if DISTINCT
 		// was specified in the query.
@@ -431,7 +561,7 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	b.constructProjectForScope(fromScope, g.aggInScope)
 
 	g.aggOutScope.expr = b.constructGroupBy(
-		g.aggInScope.expr,
+		b.constructExpand(g, g.aggInScope.expr),
 		groupingColSet,
 		aggCols,
 		g.aggInScope.ordering,
@@ -507,15 +637,124 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
-	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+	if groupBy.HasGroupingSets() {
+		// The grouping sets of the GROUP BY clause are the cross product of the
+		// grouping sets of each of its items. For example:
+		//   GROUP BY a, ROLLUP (b, c)
+		// has the grouping sets (a, b, c), (a, b) and (a).
+		sets := memo.GroupingSets{opt.ColSet{}}
+		for _, e := range groupBy {
+			itemSets := b.buildGroupingSets(e, selects, projectionsScope, fromScope)
+			if len(sets)*len(itemSets) > maxGroupingSets {
+				panic(errTooManyGroupingSets)
+			}
+			product := make(memo.GroupingSets, 0, len(sets)*len(itemSets))
+			for _, set := range sets {
+				for _, itemSet := range itemSets {
+					product = append(product, set.Union(itemSet))
+				}
+			}
+			sets = product
+		}
+		g.expand = &memo.ExpandPrivate{GroupingSets: sets}
+	} else {
+		for _, e := range groupBy {
+			b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		}
 	}
 	g.buildingGroupingCols = false
 }
 
+const (
+	// maxGroupingSets is the maximum number of grouping sets in a GROUP BY
+	// clause. This is the same limit as in Postgres.
+	maxGroupingSets = 4096
+
+	// maxCubeElements is the maximum number of elements in a CUBE clause. This
+	// is the same limit as in Postgres.
+	maxCubeElements = 12
+)
+
+var errTooManyGroupingSets = pgerror.Newf(pgcode.StatementTooComplex,
+	"too many grouping sets present (maximum %d)", maxGroupingSets,
+)
+
+// buildGroupingSets builds the grouping columns of a GROUP BY item in a GROUP
+// BY clause with grouping sets, and returns the grouping sets of the item:
+//
+//  - a plain expression or a parenthesized list of expressions forms a single
+//    grouping set.
+//  - ROLLUP (e1, e2, ..., en) has the grouping sets (e1, e2, ..., en),
+//    (e1, e2, ..., en-1), ..., (e1) and ().
+//  - CUBE (e1, e2, ..., en) has a grouping set for each subset of its
+//    elements.
+//  - GROUPING SETS (s1, s2, ..., sn) has the grouping sets of each of its
+//    elements, which can themselves be ROLLUP, CUBE or GROUPING SETS clauses.
+//
+// Duplicate grouping sets are preserved, as they produce duplicate groups.
+func (b *Builder) buildGroupingSets(
+	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) []opt.ColSet {
+	buildElems := func(exprs tree.Exprs) []opt.ColSet {
+		elems := make([]opt.ColSet, len(exprs))
+		for i, e := range exprs {
+			elems[i] = b.buildGrouping(e, selects, projectionsScope, fromScope, fromScope.groupby.aggInScope)
+		}
+		return elems
+	}
+
+	switch t := groupBy.(type) {
+	case *tree.RollupExpr:
+		elems := buildElems(t.Exprs)
+		sets := make([]opt.ColSet, len(elems)+1)
+		for i := range sets {
+			for _, elem := range elems[:len(elems)-i] {
+				sets[i].UnionWith(elem)
+			}
+		}
+		return sets
+
+	case *tree.CubeExpr:
+		if len(t.Exprs) > maxCubeElements {
+			panic(pgerror.Newf(pgcode.TooManyColumns,
+				"CUBE is limited to %d elements", maxCubeElements,
+			))
+		}
+		elems := buildElems(t.Exprs)
+		n := len(elems)
+		sets := make([]opt.ColSet, 0, 1<<n)
+		for mask := 1<<n - 1; mask >= 0; mask-- {
+			var set opt.ColSet
+			for i, elem := range elems {
+				if mask&(1<<(n-1-i)) != 0 {
+					set.UnionWith(elem)
+				}
+			}
+			sets = append(sets, set)
+		}
+		return sets
+
+	case *tree.GroupingSetsExpr:
+		var sets []opt.ColSet
+		for _, e := range t.Exprs {
+			sets = append(sets, b.buildGroupingSets(e, selects, projectionsScope, fromScope)...)
+			if len(sets) > maxGroupingSets {
+				panic(errTooManyGroupingSets)
+			}
+		}
+		return sets
+
+	default:
+		return []opt.ColSet{
+			b.buildGrouping(groupBy, selects, projectionsScope, fromScope, fromScope.groupby.aggInScope),
+		}
+	}
+}
+
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
-// groupStrs and to the aggInScope.
+// groupStrs and to the aggInScope. Returns the set of grouping columns for the
+// expression.
 //
 //
 // groupBy          The given GROUP BY expression.
@@ -529,7 +768,7 @@ func (b *Builder) buildGroupingList(
 //                  as the aggregate function arguments.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+) (cols opt.ColSet) {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 	alias := ""
@@ -627,7 +866,8 @@ func (b *Builder) buildGrouping(
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			cols.Add(col.id)
 			continue
 		}
 
@@ -637,7 +877,9 @@ func (b *Builder) buildGrouping(
 		col := aggInScope.addColumn(scopeColName(tree.Name(alias)), e)
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
 		fromScope.groupby.groupStrs[exprStr] = col
+		cols.Add(col.id)
 	}
+	return cols
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -690,6 +932,17 @@ func (b *Builder) buildAggregateFunction(
 ) *aggregateInfo {
 	tempScopeColsBefore := len(tempScope.cols)
 
+	if def.Overload.UDFAggregate != nil {
+		if b.insideViewDef {
+			panic(unimplemented.New("udf-aggregate-in-view",
+				"user-defined aggregates are not supported in views"))
+		}
+		if f.OrderBy != nil {
+			panic(unimplemented.New("udf-aggregate-order-by",
+				"ORDER BY is not supported in calls to user-defined aggregates"))
+		}
+	}
+
 	info := aggregateInfo{
 		FuncExpr: f,
 		def:      *def,
@@ -891,6 +1144,108 @@ func isSQLFn(def *tree.FunctionDefinition) bool {
 	return def.Class == tree.SQLClass
 }
 
+// maxGroupingArgs is the maximum number of arguments of a GROUPING function
+// call, so that the result fits in a 32-bit integer. This is the same limit as
+// in Postgres.
+const maxGroupingArgs = 31
+
+// buildGroupingFunc builds a GROUPING function call. GROUPING returns a bit
+// mask with a bit set for each argument that is not part of the grouping set
+// of the current row; the last argument corresponds to the least significant
+// bit. For example, for:
+//
+//   SELECT GROUPING(a, b) FROM t GROUP BY ROLLUP (a, b)
+//
+// GROUPING returns 0 for the grouping set (a, b), 1 for (a) and 3 for ().
+//
+// The arguments must match grouping expressions of the query level in which
+// GROUPING appears (or of an enclosing query level, if GROUPING is used in a
+// subquery). The result is built as a CASE expression on the grouping set
+// ordinal produced by the Expand operator.
+func (b *Builder) buildGroupingFunc(
+	info *groupingInfo, inScope *scope, colRefs *opt.ColSet,
+) opt.ScalarExpr {
+	var g *groupby
+	for s := inScope; s != nil; s = s.parent {
+		if s.groupby != nil {
+			g = s.groupby
+			break
+		}
+	}
+	if g == nil {
+		if b.subquery != nil {
+			// Subqueries are built before the grouping of the enclosing query, so
+			// GROUPING cannot refer to an outer query level.
+			panic(unimplementedWithIssueDetailf(46280, "grouping-subquery",
+				"GROUPING in a subquery is not supported"))
+		}
+		panic(errInvalidGroupingArgs)
+	}
+
+	// Find the grouping column corresponding to each argument.
+	argCols := make([]opt.ColumnID, len(info.args))
+	for i, arg := range info.args {
+		col, ok := g.groupStrs[symbolicExprStr(arg)]
+		if !ok {
+			panic(errInvalidGroupingArgs)
+		}
+		argCols[i] = col.id
+	}
+
+	if g.expand == nil {
+		// All grouping columns are part of the single grouping set.
+		return b.factory.ConstructConstVal(tree.NewDInt(0), types.Int)
+	}
+
+	// Compute the result for each grouping set.
+	masks := make([]int, len(g.expand.GroupingSets))
+	for i := range g.expand.GroupingSets {
+		outCols := g.expand.OutColSet(i)
+		for _, col := range argCols {
+			masks[i] <<= 1
+			if !outCols.Contains(col) {
+				masks[i] |= 1
+			}
+		}
+	}
+	allSame := true
+	for i := range masks {
+		if masks[i] != masks[0] {
+			allSame = false
+			break
+		}
+	}
+	if allSame {
+		return b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(masks[0])), types.Int)
+	}
+
+	var setID opt.ScalarExpr
+	if g == inScope.groupby {
+		setID = b.factory.ConstructVariable(g.setIDCol.id)
+		if colRefs != nil {
+			colRefs.Add(g.setIDCol.id)
+		}
+	} else {
+		// GROUPING refers to an enclosing query level, so the grouping set
+		// ordinal is an outer column.
+		setID = b.finishBuildScalarRef(&g.setIDCol, inScope, nil /* outScope */, nil /* outCol */, colRefs)
+	}
+	last := len(masks) - 1
+	whens := make(memo.ScalarListExpr, 0, last)
+	for i := 0; i < last; i++ {
+		whens = append(whens, b.factory.ConstructWhen(
+			b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(i)), types.Int),
+			b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(masks[i])), types.Int),
+		))
+	}
+	orElse := b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(masks[last])), types.Int)
+	return b.factory.ConstructCase(setID, whens, orElse)
+}
+
+var errInvalidGroupingArgs = pgerror.New(pgcode.Grouping,
+	"arguments to GROUPING must be grouping expressions of the associated query level",
+)
+
 func newGroupingError(name tree.Name) error {
 	return pgerror.Newf(pgcode.Grouping,
 		"column \"%s\" must appear in the GROUP BY clause or be used in an aggregate function",
@@ -903,6 +1258,13 @@ func newGroupingError(name tree.Name) error {
 // table. In that case, we can allow col as an "implicit" grouping column, even
 // if it is not specified in the query.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.expand != nil {
+		// A column that is functionally dependent on the grouping columns is not
+		// necessarily dependent on the grouping columns of a grouping set, so
+		// implicit grouping columns are not allowed with grouping sets. This
+		// matches Postgres.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
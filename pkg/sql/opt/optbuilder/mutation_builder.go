@@ -348,27 +348,31 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	// Build a distinct on to ensure there is at most one row in the joined output
 	// for every row in the table.
 	if fromClausePresent {
-		var pkCols opt.ColSet
-
-		// We need to ensure that the join has a maximum of one row for every row
-		// in the table and we ensure this by constructing a distinct on the primary
-		// key columns.
-		primaryIndex := mb.tab.Index(cat.PrimaryIndex)
-		for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
-			// If the primary key column is hidden, then we don't need to use it
-			// for the distinct on.
-			// TODO(radu): this logic seems fragile, is it assuming that only an
-			// implicit `rowid` column can be a hidden PK column?
-			if col := primaryIndex.Column(i); col.Visibility() != cat.Hidden {
-				pkCols.Add(mb.fetchColIDs[col.Ordinal()])
-			}
-		}
+		mb.buildDistinctOnPrimaryKey()
+	}
+}
 
-		if !pkCols.Empty() {
-			mb.outScope = mb.b.buildDistinctOn(
-				pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+// buildDistinctOnPrimaryKey wraps mb.outScope in a distinct on the primary key
+// columns of the fetched rows. It is used when the table being mutated is
+// joined with the tables of an UPDATE ... FROM or DELETE ... USING clause, to
+// ensure that the join has a maximum of one row for every row in the table.
+func (mb *mutationBuilder) buildDistinctOnPrimaryKey() {
+	var pkCols opt.ColSet
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
+		// If the primary key column is hidden, then we don't need to use it
+		// for the distinct on.
+		// TODO(radu): this logic seems fragile, is it assuming that only an
+		// implicit `rowid` column can be a hidden PK column?
+		if col := primaryIndex.Column(i); col.Visibility() != cat.Hidden {
+			pkCols.Add(mb.fetchColIDs[col.Ordinal()])
 		}
 	}
+
+	if !pkCols.Empty() {
+		mb.outScope = mb.b.buildDistinctOn(
+			pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+	}
 }
 
 // buildInputForDelete constructs a Select expression from the fields in
@@ -381,9 +385,17 @@ func (mb *mutationBuilder) buildInputForUpdate(
 //   LIMIT <limit>
 //
 // All columns from the table to update are added to fetchColList.
+// If a USING clause is defined, we build out each of the table
+// expressions required and JOIN them together with the table being
+// deleted from, the same way as the FROM clause of an UPDATE.
 // TODO(andyk): Do needed column analysis to project fewer columns if possible.
 func (mb *mutationBuilder) buildInputForDelete(
-	inScope *scope, texpr tree.TableExpr, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
+	inScope *scope,
+	texpr tree.TableExpr,
+	using tree.TableExprs,
+	where *tree.Where,
+	limit *tree.Limit,
+	orderBy tree.OrderBy,
 ) {
 	var indexFlags *tree.IndexFlags
 	if source, ok := texpr.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
@@ -411,7 +423,34 @@ func (mb *mutationBuilder) buildInputForDelete(
 		noRowLocking,
 		inScope,
 	)
-	mb.outScope = mb.fetchScope
+
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	// If there is a USING clause present, we must join all the tables
+	// together with the table being deleted from.
+	usingClausePresent := len(using) > 0
+	if usingClausePresent {
+		usingScope := mb.b.buildFromTables(using, noRowLocking, inScope)
+
+		// Check that the same table name is not used multiple times.
+		mb.b.validateJoinTableNames(mb.fetchScope, usingScope)
+
+		// The USING table columns can be accessed by the RETURNING clause of
+		// the query and so we have to make them accessible.
+		mb.extraAccessibleCols = usingScope.cols
+
+		// Add the columns in the USING scope. See buildInputForUpdate.
+		mb.outScope = mb.fetchScope.replace()
+		mb.outScope.appendColumnsFromScope(mb.fetchScope)
+		mb.outScope.appendColumnsFromScope(usingScope)
+
+		left := mb.fetchScope.expr
+		right := usingScope.expr
+		mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter, memo.EmptyJoinPrivate)
+	} else {
+		mb.outScope = mb.fetchScope
+	}
 
 	// WHERE
 	mb.b.buildWhere(where, mb.outScope)
@@ -430,8 +469,12 @@ func (mb *mutationBuilder) buildInputForDelete(
 
 	mb.outScope = projectionsScope
 
-	// Set list of columns that will be fetched by the input expression.
-	mb.setFetchColIDs(mb.outScope.cols)
+	// Build a distinct on to ensure there is at most one row in the joined
+	// output for every row in the table, since each row can only be deleted
+	// once.
+	if usingClausePresent {
+		mb.buildDistinctOnPrimaryKey()
+	}
 }
 
 // addTargetColsByName adds one target column for each of the names in the given
@@ -1052,6 +1095,13 @@ func (mb *mutationBuilder) parseDefaultExpr(colID opt.ColumnID) tree.Expr {
 	col := mb.tab.Column(ord)
 	exprStr := col.DefaultExprStr()
 
+	// If the column has no default expression, but its type is a domain with a
+	// default expression, use the default of the domain.
+	if typ := col.DatumType(); exprStr == "" && typ.IsDomain() &&
+		typ.TypeMeta.DomainData != nil && typ.TypeMeta.DomainData.DefaultExpr != nil {
+		exprStr = *typ.TypeMeta.DomainData.DefaultExpr
+	}
+
 	// If no default expression, return NULL or a default value.
 	if exprStr == "" {
 		if col.IsMutation() && !col.IsNullable() {
@@ -1264,7 +1314,7 @@ func (mb *mutationBuilder) addAssignmentCasts(srcCols opt.OptionalColList) {
 
 		// Create the cast expression.
 		variable := mb.b.factory.ConstructVariable(colID)
-		cast := mb.b.factory.ConstructAssignmentCast(variable, targetType)
+		cast := mb.b.buildAssignmentCast(variable, targetType)
 
 		// Lazily create the new scope.
 		if projectionScope == nil {
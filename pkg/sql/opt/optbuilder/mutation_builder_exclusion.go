@@ -0,0 +1,212 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// buildExclusionChecksForInsert builds exclusion check queries for an insert or
+// an upsert. These check queries are used to enforce EXCLUDE constraints.
+// Exclusion constraints cannot be used as arbiters, so a check is planned for
+// every constraint.
+func (mb *mutationBuilder) buildExclusionChecksForInsert() {
+	for i, n := 0, mb.tab.ExclusionConstraintCount(); i < n; i++ {
+		if check, ok := mb.buildExclusionCheck(i); ok {
+			mb.uniqueChecks = append(mb.uniqueChecks, check)
+		}
+	}
+}
+
+// buildExclusionChecksForUpdate builds exclusion check queries for an update.
+// These check queries are used to enforce EXCLUDE constraints.
+func (mb *mutationBuilder) buildExclusionChecksForUpdate() {
+	for i, n := 0, mb.tab.ExclusionConstraintCount(); i < n; i++ {
+		// If this constraint doesn't include the updated columns we don't need to
+		// plan a check.
+		if !mb.exclusionColsUpdated(i) {
+			continue
+		}
+		// The insertion check works for updates too; see
+		// buildUniqueChecksForUpdate.
+		if check, ok := mb.buildExclusionCheck(i); ok {
+			mb.uniqueChecks = append(mb.uniqueChecks, check)
+		}
+	}
+}
+
+// exclusionColsUpdated returns true if any of the columns of the given
+// exclusion constraint, or any of the columns referenced by its predicate, are
+// being updated (according to updateColIDs).
+func (mb *mutationBuilder) exclusionColsUpdated(exclusionOrdinal int) bool {
+	ec := mb.tab.ExclusionConstraint(exclusionOrdinal)
+
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		if ord := ec.ColumnOrdinal(mb.tab, i); mb.updateColIDs[ord] != 0 {
+			return true
+		}
+	}
+
+	if _, isPartial := ec.Predicate(); isPartial {
+		pred := mb.parseExclusionConstraintPredicateExpr(exclusionOrdinal)
+		typedPred := mb.fetchScope.resolveAndRequireType(pred, types.Bool)
+
+		var predCols opt.ColSet
+		mb.b.buildScalar(typedPred, mb.fetchScope, nil, nil, &predCols)
+		for colID, ok := predCols.Next(0); ok; colID, ok = predCols.Next(colID + 1) {
+			ord := mb.md.ColumnMeta(colID).Table.ColumnOrdinal(colID)
+			if mb.updateColIDs[ord] != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// buildExclusionCheck builds the check query for the given exclusion
+// constraint. The query is a semi join of the new rows with the existing rows
+// of the table, which returns the new rows that conflict with another row:
+//
+//   SELECT new.a, new.b FROM new
+//   WHERE EXISTS (
+//     SELECT * FROM tab
+//     WHERE new.a = tab.a AND new.b && tab.b AND new.pk != tab.pk
+//   )
+//
+// The comparison with the "&&" operator can be served by the inverted index
+// backing the constraint. Returns false if no check is needed because at least
+// one of the constrained columns is always NULL in the new rows.
+func (mb *mutationBuilder) buildExclusionCheck(
+	exclusionOrdinal int,
+) (_ memo.UniqueChecksItem, ok bool) {
+	f := mb.b.factory
+	ec := mb.tab.ExclusionConstraint(exclusionOrdinal)
+
+	// A NULL never conflicts with anything, so no check is needed if one of
+	// the columns is always NULL, like when this mutation is the result of a
+	// SET NULL cascade action.
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		ord := ec.ColumnOrdinal(mb.tab, i)
+		if memo.OutputColumnIsAlwaysNull(mb.outScope.expr, mb.mapToReturnColID(ord)) {
+			return memo.UniqueChecksItem{}, false
+		}
+	}
+
+	// Find the primary key columns, which are used to prevent rows from
+	// conflicting with themselves.
+	primaryOrds := getIndexLaxKeyOrdinals(mb.tab.Index(cat.PrimaryIndex))
+
+	// Build a self semi-join, with the new values on the left and the existing
+	// values on the right. The scan is built by the unique check helper, which
+	// ignores any UNIQUE WITHOUT INDEX constraints.
+	h := &mb.uniqueCheckHelper
+	*h = uniqueCheckHelper{mb: mb}
+	scanScope, scanOrdinals := h.buildTableScan()
+	withScanScope, _ := mb.buildCheckInputScan(
+		checkInputScanNewVals, scanOrdinals, false, /* isFK */
+	)
+
+	// Build the join filters:
+	//   (new_a = existing_a) AND (new_b && existing_b) AND ...
+	semiJoinFilters := make(memo.FiltersExpr, 0, ec.ColumnCount()+3)
+	for i, n := 0, ec.ColumnCount(); i < n; i++ {
+		ord := ec.ColumnOrdinal(mb.tab, i)
+		newVal := f.ConstructVariable(withScanScope.cols[ord].id)
+		existingVal := f.ConstructVariable(scanScope.cols[ord].id)
+		var cmp opt.ScalarExpr
+		switch ec.Operator(i) {
+		case treecmp.EQ:
+			cmp = f.ConstructEq(newVal, existingVal)
+		case treecmp.Overlaps:
+			cmp = f.ConstructOverlaps(newVal, existingVal)
+		default:
+			panic(errors.AssertionFailedf(
+				"unexpected operator %s in exclusion constraint %s", ec.Operator(i), ec.Name(),
+			))
+		}
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(cmp))
+	}
+
+	// If the constraint is partial, only the rows that satisfy the predicate
+	// can conflict with each other. Filter both sides of the join by the
+	// predicate.
+	if _, isPartial := ec.Predicate(); isPartial {
+		pred := mb.parseExclusionConstraintPredicateExpr(exclusionOrdinal)
+
+		typedPred := withScanScope.resolveAndRequireType(pred, types.Bool)
+		withScanPred := mb.b.buildScalar(typedPred, withScanScope, nil, nil, nil)
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(withScanPred))
+
+		typedPred = scanScope.resolveAndRequireType(pred, types.Bool)
+		scanPred := mb.b.buildScalar(typedPred, scanScope, nil, nil, nil)
+		semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(scanPred))
+	}
+
+	// Prevent rows from matching themselves in the semi join:
+	//    (new_pk1 != existing_pk1) OR (new_pk2 != existing_pk2) OR ...
+	var pkFilter opt.ScalarExpr
+	for i, ok := primaryOrds.Next(0); ok; i, ok = primaryOrds.Next(i + 1) {
+		pkFilterLocal := f.ConstructNe(
+			f.ConstructVariable(withScanScope.cols[i].id),
+			f.ConstructVariable(scanScope.cols[i].id),
+		)
+		if pkFilter == nil {
+			pkFilter = pkFilterLocal
+		} else {
+			pkFilter = f.ConstructOr(pkFilter, pkFilterLocal)
+		}
+	}
+	semiJoinFilters = append(semiJoinFilters, f.ConstructFiltersItem(pkFilter))
+
+	semiJoin := f.ConstructSemiJoin(withScanScope.expr, scanScope.expr, semiJoinFilters, memo.EmptyJoinPrivate)
+
+	// Collect the key columns that will be shown in the error message if there
+	// is a conflict. They are listed in the same order as the columns of the
+	// constraint.
+	keyCols := make(opt.ColList, ec.ColumnCount())
+	for i := range keyCols {
+		keyCols[i] = withScanScope.cols[ec.ColumnOrdinal(mb.tab, i)].id
+	}
+	project := f.ConstructProject(semiJoin, nil /* projections */, keyCols.ToSet())
+
+	return f.ConstructUniqueChecksItem(project, &memo.UniqueChecksItemPrivate{
+		Table:        mb.tabID,
+		CheckOrdinal: exclusionOrdinal,
+		Exclusion:    true,
+		KeyCols:      keyCols,
+		OpName:       mb.opName,
+	}), true
+}
+
+// parseExclusionConstraintPredicateExpr parses the predicate of the given
+// partial exclusion constraint. This function panics if the exclusion
+// constraint at the given ordinal is not partial.
+func (mb *mutationBuilder) parseExclusionConstraintPredicateExpr(exclusionOrdinal int) tree.Expr {
+	predStr, isPartial := mb.tab.ExclusionConstraint(exclusionOrdinal).Predicate()
+	if !isPartial {
+		panic(errors.AssertionFailedf(
+			"exclusion constraint at ordinal %d is not a partial exclusion constraint", exclusionOrdinal,
+		))
+	}
+	expr, err := parser.ParseExpr(predStr)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// buildBeforeTriggers applies the BEFORE triggers of the target table which
+// fire for the given event, in the order in which they are defined on the
+// table. Each trigger function body is joined to the mutation input with an
+// apply join, using the values of the NEW and OLD rows as outer columns:
+//
+//   SELECT <input cols>, <body cols>
+//   FROM <input> INNER JOIN LATERAL (<body> LIMIT 1) ON true
+//
+// Input rows for which the body returns no row are skipped. For INSERT and
+// UPDATE, the row returned by the body replaces the NEW row, so its columns
+// become the insert or update columns of the mutation.
+//
+// buildBeforeTriggers must be called after the insert (or update) columns
+// with explicit and default values have been added to the input, and before
+// the computed columns are added.
+func (mb *mutationBuilder) buildBeforeTriggers(event tree.TriggerEvent) {
+	if !cat.HasTriggers(mb.tab, tree.TriggerActionTimeBefore, event) {
+		return
+	}
+	ords := triggerColumnOrdinals(mb.tab)
+	for i, n := 0, mb.tab.TriggerCount(); i < n; i++ {
+		trigger := mb.tab.Trigger(i)
+		if trigger.ActionTime() != tree.TriggerActionTimeBefore || !trigger.HasEvent(event) {
+			continue
+		}
+		stmt, err := parser.ParseOne(trigger.Body())
+		if err != nil {
+			panic(err)
+		}
+		newCols, oldCols := mb.triggerRowCols(event, ords)
+		returnsRow := event != tree.TriggerEventDelete
+		bodyScope := mb.b.buildTriggerBody(
+			mb.tab, ords, stmt.AST, newCols, oldCols, true /* selectOnly */, returnsRow,
+		)
+		body := mb.b.factory.ConstructLimit(
+			bodyScope.expr,
+			mb.b.factory.ConstructConst(tree.NewDInt(1), types.Int),
+			bodyScope.makeOrderingChoice(),
+		)
+
+		outScope := mb.outScope.replace()
+		outScope.appendColumnsFromScope(mb.outScope)
+		if returnsRow {
+			colIDs := mb.insertColIDs
+			if event == tree.TriggerEventUpdate {
+				colIDs = mb.updateColIDs
+			}
+			for j, ord := range ords {
+				tabCol := mb.tab.Column(ord)
+				if tabCol.IsComputed() {
+					// Values returned for computed columns are ignored, since the
+					// columns are computed from the other columns of the row.
+					continue
+				}
+				col := bodyScope.cols[j]
+				col.name = scopeColName(tabCol.ColName()).WithMetadataName(
+					string(tabCol.ColName()) + "_" + string(trigger.Name()),
+				)
+				col.table = tree.TableName{}
+				outScope.cols = append(outScope.cols, col)
+				if colIDs[ord] == 0 {
+					tabColID := mb.tabID.ColumnID(ord)
+					mb.targetColList = append(mb.targetColList, tabColID)
+					mb.targetColSet.Add(tabColID)
+				}
+				colIDs[ord] = col.id
+			}
+		}
+		outScope.expr = mb.b.factory.ConstructInnerJoinApply(
+			mb.outScope.expr, body, memo.TrueFilter, memo.EmptyJoinPrivate,
+		)
+		mb.outScope = outScope
+
+		// Make sure that references to the table columns, such as those in
+		// computed column expressions, refer to the values returned by the
+		// trigger.
+		mb.disambiguateColumns()
+	}
+}
+
+// triggerRowCols returns the columns holding the values of the NEW and OLD
+// rows for the table columns in ords. Where a row has no value for a column,
+// such as the OLD row of an INSERT, a NULL column is projected.
+func (mb *mutationBuilder) triggerRowCols(
+	event tree.TriggerEvent, ords []int,
+) (newCols, oldCols opt.ColList) {
+	newCols = make(opt.ColList, len(ords))
+	oldCols = make(opt.ColList, len(ords))
+	for i, ord := range ords {
+		switch event {
+		case tree.TriggerEventInsert:
+			newCols[i] = mb.insertColIDs[ord]
+		case tree.TriggerEventUpdate:
+			newCols[i] = mb.updateColIDs[ord]
+			if newCols[i] == 0 {
+				newCols[i] = mb.fetchColIDs[ord]
+			}
+			oldCols[i] = mb.fetchColIDs[ord]
+		case tree.TriggerEventDelete:
+			oldCols[i] = mb.fetchColIDs[ord]
+		}
+	}
+
+	var projectionScope *scope
+	nullCol := func(ord int) opt.ColumnID {
+		if projectionScope == nil {
+			projectionScope = mb.outScope.replace()
+			projectionScope.appendColumnsFromScope(mb.outScope)
+		}
+		typ := mb.tab.Column(ord).DatumType()
+		return mb.b.synthesizeColumn(
+			projectionScope, scopeColName(""), typ, nil /* expr */, mb.b.factory.ConstructNull(typ),
+		).id
+	}
+	for i, ord := range ords {
+		if newCols[i] == 0 {
+			newCols[i] = nullCol(ord)
+		}
+		if oldCols[i] == 0 {
+			oldCols[i] = nullCol(ord)
+		}
+	}
+	if projectionScope != nil {
+		projectionScope.expr = mb.b.constructProject(mb.outScope.expr, projectionScope.cols)
+		mb.outScope = projectionScope
+	}
+	return newCols, oldCols
+}
@@ -119,6 +119,9 @@ func (b *Builder) buildScalar(
 	case *windowInfo:
 		return b.finishBuildScalarRef(t.col, inScope, outScope, outCol, colRefs)
 
+	case *groupingInfo:
+		out = b.buildGroupingFunc(t, inScope, colRefs)
+
 	case *tree.AndExpr:
 		left := b.buildScalar(reType(t.TypedLeft(), types.Bool), inScope, nil, nil, colRefs)
 		right := b.buildScalar(reType(t.TypedRight(), types.Bool), inScope, nil, nil, colRefs)
@@ -271,7 +274,11 @@ func (b *Builder) buildScalar(
 	case *tree.CastExpr:
 		texpr := t.Expr.(tree.TypedExpr)
 		arg := b.buildScalar(texpr, inScope, nil, nil, colRefs)
-		out = b.factory.ConstructCast(arg, t.ResolvedType())
+		if typ := t.ResolvedType(); typ.IsDomain() {
+			out = b.buildDomainCoercion(arg, typ, false /* assignment */)
+		} else {
+			out = b.factory.ConstructCast(arg, typ)
+		}
 
 	case *tree.CoalesceExpr:
 		args := make(memo.ScalarListExpr, len(t.Exprs))
@@ -535,6 +542,10 @@ func (b *Builder) buildFunction(
 		panic(errors.AssertionFailedf("window function should have been replaced"))
 	}
 
+	if f.ResolvedOverload().IsUDF {
+		return b.buildUDF(f, def, inScope, outScope, outCol, colRefs)
+	}
+
 	args := make(memo.ScalarListExpr, len(f.Exprs))
 	for i, pexpr := range f.Exprs {
 		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
@@ -673,6 +684,12 @@ func (b *Builder) checkSubqueryOuterCols(
 			!subqueryOuterCols.SubsetOf(inScope.groupby.aggOutScope.colSet()) {
 			subqueryOuterCols.DifferenceWith(inScope.groupby.aggOutScope.colSet())
 			colID, _ := subqueryOuterCols.Next(0)
+			if e := inScope.groupby.expand; e != nil && e.InCols.ToSet().Contains(colID) {
+				// The grouping set columns replace the grouping columns only after
+				// the subquery has been built.
+				panic(unimplementedWithIssueDetailf(46280, "grouping-sets-subquery",
+					"subqueries referencing grouping columns of GROUPING SETS, ROLLUP or CUBE are not supported"))
+			}
 			col := inScope.getColumn(colID)
 			name := col.name.ReferenceName()
 			panic(pgerror.Newf(
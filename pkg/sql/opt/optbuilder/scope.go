@@ -30,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treewindow"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/redact"
 )
@@ -1039,6 +1040,9 @@ func (s *scope) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
 			break
 		}
 
+	case *tree.GroupingExpr:
+		expr = s.replaceGrouping(t)
+
 	case *tree.ArrayFlatten:
 		if sub, ok := t.Subquery.(*tree.Subquery); ok {
 			// Copy the ArrayFlatten expression so that the tree isn't mutated.
@@ -1233,6 +1237,50 @@ func (s *scope) replaceAggregate(f *tree.FuncExpr, def *tree.FunctionDefinition)
 	return s.builder.buildAggregateFunction(f, &private, tempScope, s)
 }
 
+// replaceGrouping returns a groupingInfo that can be used to replace a
+// GROUPING function call. The arguments are resolved here, but they are only
+// matched against the grouping expressions when the groupingInfo is built,
+// once the grouping columns are known.
+func (s *scope) replaceGrouping(g *tree.GroupingExpr) tree.Expr {
+	if s.builder.semaCtx.Properties.IsSet(tree.RejectAggregates) {
+		// GROUPING is not allowed in this context (e.g. WHERE). Type checking
+		// returns an error that mentions the context.
+		_, err := g.TypeCheck(s.builder.ctx, s.builder.semaCtx, types.Int)
+		panic(err)
+	}
+	switch s.context {
+	case exprKindWhere, exprKindOn, exprKindLateralJoin:
+		panic(pgerror.Newf(pgcode.Grouping,
+			"grouping operations are not allowed in %s", s.context,
+		))
+	}
+	if s.builder.semaCtx.Properties.IsSet(tree.RejectNestedAggregates) {
+		panic(pgerror.Newf(pgcode.Grouping,
+			"aggregate function calls cannot contain grouping operations",
+		))
+	}
+	if len(g.Exprs) > maxGroupingArgs {
+		panic(pgerror.Newf(pgcode.TooManyArguments,
+			"GROUPING must have fewer than %d arguments", maxGroupingArgs+1,
+		))
+	}
+
+	// We need to save and restore the previous value of the field in
+	// semaCtx in case we are recursively called within a subquery
+	// context.
+	defer s.builder.semaCtx.Properties.Restore(s.builder.semaCtx.Properties)
+	s.builder.semaCtx.Properties.Require("GROUPING", tree.RejectSpecial)
+
+	info := &groupingInfo{
+		GroupingExpr: g,
+		args:         make([]tree.TypedExpr, len(g.Exprs)),
+	}
+	for i, e := range g.Exprs {
+		info.args[i] = s.resolveType(e, types.Any)
+	}
+	return info
+}
+
 func (s *scope) lookupWindowDef(name tree.Name) *tree.WindowDef {
 	for i := range s.windowDefs {
 		if s.windowDefs[i].Name == name {
@@ -1295,6 +1343,10 @@ func (s *scope) replaceWindowFn(f *tree.FuncExpr, def *tree.FunctionDefinition)
 	}
 
 	f = typedFunc.(*tree.FuncExpr)
+	if f.ResolvedOverload().UDFAggregate != nil {
+		panic(unimplemented.New("udf-aggregate-window",
+			"user-defined aggregates cannot be used as window functions"))
+	}
 
 	// We will be performing type checking on expressions from PARTITION BY and
 	// ORDER BY clauses below, and we need the semantic context to know that we
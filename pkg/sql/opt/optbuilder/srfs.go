@@ -101,6 +101,13 @@ func (b *Builder) buildZip(exprs tree.Exprs, inScope *scope) (outScope *scope) {
 			if def, err = funcExpr.Func.Resolve(b.semaCtx.SearchPath); err != nil {
 				panic(err)
 			}
+			if o := funcExpr.ResolvedOverload(); o.IsUDF && o.UDFReturnsSet {
+				if len(exprs) != 1 {
+					panic(unimplemented.New("udf-rows-from",
+						"set-returning user-defined functions are not supported in ROWS FROM"))
+				}
+				return b.buildSetReturningUDF(funcExpr, def, inScope)
+			}
 		}
 
 		var outCol *scopeColumn
@@ -0,0 +1,222 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// triggerNewRowName and triggerOldRowName are the names by which the body of
+// a trigger function refers to the new and old versions of the modified row.
+var (
+	triggerNewRowName = tree.MakeUnqualifiedTableName("new")
+	triggerOldRowName = tree.MakeUnqualifiedTableName("old")
+)
+
+func (b *Builder) buildCreateTrigger(ct *tree.CreateTrigger, inScope *scope) (outScope *scope) {
+	b.DisableMemoReuse = true
+	tn := ct.Table.ToTableName()
+	tab, _ := b.resolveTable(&tn, privilege.CREATE)
+	if tab.IsVirtualTable() || tab.IsMaterializedView() {
+		panic(pgerror.Newf(pgcode.WrongObjectType, "%q is not a table", tree.ErrString(&tn)))
+	}
+	tabID := b.factory.Metadata().AddTable(tab, &tn)
+
+	overload := b.resolveTriggerFunction(ct.FuncName)
+	stmt, err := parser.ParseOne(overload.UDFBody)
+	if err != nil {
+		panic(err)
+	}
+
+	// Build the body with the NEW and OLD rows in scope, to check it
+	// semantically and to get the fully resolved names into the AST. The
+	// result is not otherwise used.
+	ords := triggerColumnOrdinals(tab)
+	colScope := b.allocScope()
+	newCols := make(opt.ColList, len(ords))
+	oldCols := make(opt.ColList, len(ords))
+	for i, ord := range ords {
+		col := tab.Column(ord)
+		newCols[i] = b.synthesizeColumn(colScope, scopeColName(col.ColName()), col.DatumType(), nil, nil).id
+		oldCols[i] = b.synthesizeColumn(colScope, scopeColName(col.ColName()), col.DatumType(), nil, nil).id
+	}
+	b.qualifyDataSourceNamesInAST = true
+	defer func() { b.qualifyDataSourceNamesInAST = false }()
+	var returnsRow bool
+	for _, e := range ct.Events {
+		returnsRow = returnsRow || e != tree.TriggerEventDelete
+	}
+	b.buildTriggerBody(
+		tab, ords, stmt.AST, newCols, oldCols,
+		ct.ActionTime == tree.TriggerActionTimeBefore, /* selectOnly */
+		ct.ActionTime == tree.TriggerActionTimeBefore && returnsRow, /* returnsRow */
+	)
+
+	outScope = b.allocScope()
+	outScope.expr = b.factory.ConstructCreateTrigger(
+		&memo.CreateTriggerPrivate{
+			Table:        tabID,
+			Syntax:       ct,
+			Function:     overload,
+			FunctionBody: tree.AsStringWithFlags(stmt.AST, tree.FmtParsable),
+		},
+	)
+	return outScope
+}
+
+// resolveTriggerFunction resolves the function executed by a trigger. It must
+// be a user-defined function which was declared with RETURNS TRIGGER.
+func (b *Builder) resolveTriggerFunction(name *tree.UnresolvedObjectName) *tree.Overload {
+	ref := tree.ResolvableFunctionReference{FunctionReference: name.ToUnresolvedName()}
+	def, err := ref.Resolve(b.semaCtx.SearchPath)
+	if err != nil {
+		panic(err)
+	}
+	for _, o := range def.Definition {
+		overload := o.(*tree.Overload)
+		if overload.IsUDF && len(overload.Types.Types()) == 0 {
+			if !overload.UDFTrigger {
+				panic(pgerror.Newf(pgcode.InvalidObjectDefinition,
+					"function %s must return type trigger", def.Name))
+			}
+			return overload
+		}
+	}
+	panic(pgerror.Newf(pgcode.UndefinedFunction, "function %s() does not exist", def.Name))
+}
+
+// triggerColumnOrdinals returns the ordinals of the table columns which can be
+// referenced through the NEW and OLD rows in the body of a trigger function.
+// These are the public, visible columns of the table.
+func triggerColumnOrdinals(tab cat.Table) []int {
+	ords := make([]int, 0, tab.ColumnCount())
+	for i, n := 0, tab.ColumnCount(); i < n; i++ {
+		col := tab.Column(i)
+		if col.Kind() == cat.Ordinary && col.Visibility() == cat.Visible {
+			ords = append(ords, i)
+		}
+	}
+	return ords
+}
+
+// buildTriggerBody builds the body of a trigger function. The columns in
+// newCols and oldCols hold the values of the NEW and OLD rows for the table
+// columns in ords, and are referenced by the body as outer columns.
+//
+// If selectOnly is true, the body must be a SELECT statement. If returnsRow is
+// true, the body must also return a row with the structure of the table: one
+// column for each column in ords, which can be assigned to the table column.
+// In that case the output columns of the returned scope are cast to the types
+// of the table columns.
+func (b *Builder) buildTriggerBody(
+	tab cat.Table,
+	ords []int,
+	stmt tree.Statement,
+	newCols, oldCols opt.ColList,
+	selectOnly, returnsRow bool,
+) (bodyScope *scope) {
+	if _, ok := stmt.(*tree.Select); selectOnly && !ok {
+		panic(unimplemented.New("trigger-body",
+			"only a single SELECT statement is supported in the body of a BEFORE trigger function"))
+	}
+
+	rowScope := b.allocScope()
+	md := b.factory.Metadata()
+	addRow := func(rowName tree.TableName, cols opt.ColList) {
+		for i, ord := range ords {
+			rowScope.cols = append(rowScope.cols, scopeColumn{
+				name:  scopeColName(tab.Column(ord).ColName()),
+				table: rowName,
+				typ:   md.ColumnMeta(cols[i]).Type,
+				id:    cols[i],
+			})
+		}
+	}
+	addRow(triggerNewRowName, newCols)
+	addRow(triggerOldRowName, oldCols)
+
+	// The body is not part of any subquery which is currently being built, so
+	// the row columns must not be recorded as outer columns of the subquery.
+	// Also save any CTEs above the boundary, as in buildStmtAtRoot.
+	subq := b.subquery
+	b.subquery = nil
+	defer func() { b.subquery = subq }()
+	prevCTEs := b.ctes
+	b.ctes = nil
+	defer b.semaCtx.Properties.Restore(b.semaCtx.Properties)
+	var desiredTypes []*types.T
+	if returnsRow {
+		desiredTypes = triggerBodyTypes(tab, ords)
+	}
+	bodyScope = b.buildStmt(stmt, desiredTypes, rowScope.push())
+	bodyScope.expr = b.buildWiths(bodyScope.expr, b.ctes)
+	b.ctes = prevCTEs
+
+	if !returnsRow {
+		return bodyScope
+	}
+	bodyScope.removeHiddenCols()
+	if len(bodyScope.cols) != len(ords) {
+		panic(errors.WithDetailf(
+			pgerror.New(pgcode.DatatypeMismatch,
+				"returned row structure does not match the structure of the triggering table"),
+			"Number of returned columns (%d) does not match expected column count (%d).",
+			len(bodyScope.cols), len(ords),
+		))
+	}
+	var castScope *scope
+	for i, ord := range ords {
+		col := &bodyScope.cols[i]
+		targetType := tab.Column(ord).DatumType()
+		if col.typ.Identical(targetType) {
+			continue
+		}
+		if !cast.ValidCast(col.typ, targetType, cast.ContextAssignment) {
+			panic(errors.WithDetailf(
+				pgerror.New(pgcode.DatatypeMismatch,
+					"returned row structure does not match the structure of the triggering table"),
+				"Returned type %s does not match expected type %s in column %d.",
+				col.typ.SQLStandardName(), targetType.SQLStandardName(), i+1,
+			))
+		}
+		if castScope == nil {
+			castScope = bodyScope.replace()
+			castScope.appendColumnsFromScope(bodyScope)
+		}
+		scalar := b.buildAssignmentCast(b.factory.ConstructVariable(col.id), targetType)
+		b.populateSynthesizedColumn(&castScope.cols[i], scalar)
+	}
+	if castScope != nil {
+		castScope.expr = b.constructProject(bodyScope.expr, castScope.cols)
+		bodyScope = castScope
+	}
+	return bodyScope
+}
+
+// triggerBodyTypes returns the types of the table columns in ords.
+func triggerBodyTypes(tab cat.Table, ords []int) []*types.T {
+	typs := make([]*types.T, len(ords))
+	for i, ord := range ords {
+		typs[i] = tab.Column(ord).DatumType()
+	}
+	return typs
+}
@@ -0,0 +1,272 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/cast"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// buildUDF builds a call to a user-defined function with a SQL body. The body
+// of the function is built as a relational expression, and the call is
+// represented by a UDF expression which is inlined as a subquery by the
+// InlineUDF normalization rule.
+//
+// See Builder.buildStmt for a description of the remaining input and
+// return values.
+func (b *Builder) buildUDF(
+	f *tree.FuncExpr,
+	def *tree.FunctionDefinition,
+	inScope, outScope *scope,
+	outCol *scopeColumn,
+	colRefs *opt.ColSet,
+) (out opt.ScalarExpr) {
+	o := f.ResolvedOverload()
+	if o.UDFReturnsSet {
+		panic(unimplemented.New("udf-setof",
+			"set-returning user-defined functions are only supported in the FROM clause"))
+	}
+
+	args := make(memo.ScalarListExpr, len(f.Exprs))
+	for i, pexpr := range f.Exprs {
+		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
+	}
+
+	params, body := b.buildUDFBody(def.Name, o, true /* singleRow */)
+	out = b.factory.ConstructUDF(args, body, &memo.UDFPrivate{
+		Name:     def.Name,
+		Typ:      f.ResolvedType(),
+		Params:   params,
+		Overload: o,
+	})
+	return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+}
+
+// buildSetReturningUDF builds a call to a set-returning user-defined function
+// in the FROM clause. The arguments of the call are projected as the
+// parameter columns of the function, and the body of the function is joined
+// to them with an apply join. The output column of the returned scope is
+// named after the function.
+func (b *Builder) buildSetReturningUDF(
+	f *tree.FuncExpr, def *tree.FunctionDefinition, inScope *scope,
+) (outScope *scope) {
+	o := f.ResolvedOverload()
+	args := make(memo.ScalarListExpr, len(f.Exprs))
+	for i, pexpr := range f.Exprs {
+		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, nil)
+	}
+	params, body := b.buildUDFBody(def.Name, o, false /* singleRow */)
+
+	// Bind the arguments to the parameter columns.
+	projections := make(memo.ProjectionsExpr, len(params))
+	for i := range params {
+		projections[i] = b.factory.ConstructProjectionsItem(args[i], params[i])
+	}
+	input := b.factory.ConstructProject(
+		b.factory.ConstructValues(memo.ScalarListWithEmptyTuple, &memo.ValuesPrivate{
+			Cols: opt.ColList{},
+			ID:   b.factory.Metadata().NextUniqueID(),
+		}),
+		projections,
+		opt.ColSet{},
+	)
+	if o.UDFStrict && len(params) > 0 {
+		filters := make(memo.FiltersExpr, len(params))
+		for i := range params {
+			filters[i] = b.factory.ConstructFiltersItem(b.factory.ConstructIsNot(
+				b.factory.ConstructVariable(params[i]), memo.NullSingleton,
+			))
+		}
+		input = b.factory.ConstructSelect(input, filters)
+	}
+
+	outScope = inScope.push()
+	resultCol := body.Relational().OutputCols.SingleColumn()
+	outScope.cols = append(outScope.cols, scopeColumn{
+		name: scopeColName(tree.Name(def.Name)),
+		typ:  b.factory.Metadata().ColumnMeta(resultCol).Type,
+		id:   resultCol,
+	})
+	join := b.factory.ConstructInnerJoinApply(input, body, memo.TrueFilter, memo.EmptyJoinPrivate)
+	outScope.expr = b.factory.ConstructProject(join, memo.EmptyProjectionsExpr, opt.MakeColSet(resultCol))
+	outScope.singleSRFColumn = true
+	return outScope
+}
+
+// buildUDFBody builds the body of a user-defined function. It returns the
+// parameter columns of the function, which the body refers to as outer
+// columns, and the relational expression of the body, which has a single
+// output column with the return type of the function. If singleRow is true,
+// the body is limited to its first row.
+func (b *Builder) buildUDFBody(
+	name string, o *tree.Overload, singleRow bool,
+) (params opt.ColList, body memo.RelExpr) {
+	if o.UDFTrigger {
+		panic(pgerror.New(pgcode.FeatureNotSupported,
+			"trigger functions can only be called as triggers"))
+	}
+	if b.insideViewDef {
+		panic(unimplemented.New("udf-in-view",
+			"user-defined functions are not supported in views"))
+	}
+	for _, id := range b.udfStack {
+		if id == o.Oid {
+			panic(unimplemented.New("udf-recursion",
+				"recursive user-defined functions are not supported"))
+		}
+	}
+	b.udfStack = append(b.udfStack, o.Oid)
+	defer func() { b.udfStack = b.udfStack[:len(b.udfStack)-1] }()
+
+	// The function definition may change between executions, so the memo
+	// cannot be reused.
+	b.DisableMemoReuse = true
+
+	stmt, err := parser.ParseOne(o.UDFBody)
+	if err != nil {
+		panic(err)
+	}
+	paramScope := b.allocScope()
+	argTypes := o.Types.Types()
+	params = make(opt.ColList, len(argTypes))
+	for i, typ := range argTypes {
+		var paramName tree.Name
+		if i < len(o.UDFParamNames) {
+			paramName = tree.Name(o.UDFParamNames[i])
+		}
+		params[i] = b.synthesizeColumn(paramScope, scopeColName(paramName), typ, nil, nil).id
+	}
+	retType := o.FixedReturnType()
+	return params, b.buildFunctionBody(name, stmt.AST, paramScope, retType, singleRow)
+}
+
+// buildFunctionBody builds the body of a SQL function, given a scope with the
+// parameter columns of the function. Positional parameter references like $1
+// in the body refer to the parameter columns. The body must return a single
+// column which can be assigned to the return type of the function; the result
+// is cast to the return type if necessary.
+func (b *Builder) buildFunctionBody(
+	name string, stmt tree.Statement, paramScope *scope, retType *types.T, singleRow bool,
+) memo.RelExpr {
+	if _, ok := stmt.(*tree.Select); !ok {
+		panic(unimplemented.New("udf-body",
+			"only a single SELECT statement is supported in the body of a function"))
+	}
+	stmt, _ = tree.SimpleStmtVisit(stmt, func(expr tree.Expr) (bool, tree.Expr, error) {
+		p, ok := expr.(*tree.Placeholder)
+		if !ok {
+			return true, expr, nil
+		}
+		if int(p.Idx) >= len(paramScope.cols) {
+			// SimpleStmtVisit does not propagate errors, so panic directly.
+			panic(pgerror.Newf(pgcode.UndefinedParameter, "there is no parameter %s", p))
+		}
+		return false, &paramScope.cols[p.Idx], nil
+	})
+
+	// The body is not part of any subquery which is currently being built, so
+	// the parameter columns must not be recorded as outer columns of the
+	// subquery. Also save any CTEs above the boundary, as in buildStmtAtRoot.
+	subq := b.subquery
+	b.subquery = nil
+	defer func() { b.subquery = subq }()
+	prevCTEs := b.ctes
+	b.ctes = nil
+	defer b.semaCtx.Properties.Restore(b.semaCtx.Properties)
+	bodyScope := b.buildStmt(stmt, []*types.T{retType}, paramScope.push())
+	bodyScope.expr = b.buildWiths(bodyScope.expr, b.ctes)
+	b.ctes = prevCTEs
+
+	bodyScope.removeHiddenCols()
+	if len(bodyScope.cols) != 1 {
+		panic(errors.WithDetail(
+			pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"return type mismatch in function declared to return %s", retType.SQLStandardName()),
+			"Function's final statement must return exactly one column.",
+		))
+	}
+
+	body := bodyScope.expr
+	if singleRow {
+		body = b.factory.ConstructLimit(
+			body,
+			b.factory.ConstructConst(tree.NewDInt(1), types.Int),
+			bodyScope.makeOrderingChoice(),
+		)
+	}
+
+	resultCol := bodyScope.cols[0]
+	if !resultCol.typ.Identical(retType) {
+		if !cast.ValidCast(resultCol.typ, retType, cast.ContextAssignment) {
+			panic(errors.WithDetailf(
+				pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"return type mismatch in function declared to return %s", retType.SQLStandardName()),
+				"Actual return type of function %s is %s.", name, resultCol.typ.SQLStandardName(),
+			))
+		}
+		castScope := bodyScope.push()
+		scalar := b.buildAssignmentCast(b.factory.ConstructVariable(resultCol.id), retType)
+		castCol := b.synthesizeColumn(castScope, scopeColName(""), retType, nil /* expr */, scalar)
+		resultCol = *castCol
+	}
+	return b.constructProject(body, []scopeColumn{resultCol})
+}
+
+// buildUserDefinedAgg constructs a call to a user-defined aggregate created
+// with CREATE AGGREGATE. The bodies of the state transition and final
+// functions are compiled into scalar expressions which are evaluated by the
+// aggregators for each input row and for each group, respectively.
+func (b *Builder) buildUserDefinedAgg(agg *aggregateInfo, args memo.ScalarListExpr) opt.ScalarExpr {
+	o := agg.def.Overload
+	uda := o.UDFAggregate
+
+	// The definitions of the support functions may change between executions,
+	// so the memo cannot be reused.
+	b.DisableMemoReuse = true
+
+	transition, err := schemaexpr.CompileScalarFunctionBody(b.ctx, uda.StateFunc, b.semaCtx)
+	if err != nil {
+		panic(err)
+	}
+	// Without a final function, the result of the aggregate is the final
+	// state.
+	final := tree.TypedExpr(tree.NewTypedOrdinalReference(0, uda.StateType))
+	if uda.FinalFunc != nil {
+		final, err = schemaexpr.CompileScalarFunctionBody(b.ctx, uda.FinalFunc, b.semaCtx)
+		if err != nil {
+			panic(err)
+		}
+	}
+	initCond := tree.Datum(tree.DNull)
+	if uda.InitCond != nil {
+		initCond, _, err = tree.ParseAndRequireString(uda.StateType, *uda.InitCond, b.evalCtx)
+		if err != nil {
+			panic(err)
+		}
+	}
+	return b.factory.ConstructUserDefinedAgg(args, &memo.UserDefinedAggPrivate{
+		Name:       agg.def.Name,
+		Typ:        agg.ResolvedType(),
+		Overload:   o,
+		Transition: transition,
+		Final:      final,
+		InitCond:   initCond,
+	})
+}
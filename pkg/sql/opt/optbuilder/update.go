@@ -292,6 +292,9 @@ func (mb *mutationBuilder) addUpdateCols(exprs tree.UpdateExprs) {
 // operator containing any computed columns that need to be updated. This
 // includes write-only mutation columns that are computed.
 func (mb *mutationBuilder) addSynthesizedColsForUpdate() {
+	// Run any BEFORE UPDATE triggers, which may modify the updated rows.
+	mb.buildBeforeTriggers(tree.TriggerEventUpdate)
+
 	// Allow mutation columns to be referenced by other computed mutation
 	// columns (otherwise the scope will raise an error if a mutation column
 	// is referenced). These do not need to be set back to true again because
@@ -344,6 +347,8 @@ func (mb *mutationBuilder) buildUpdate(returning tree.ReturningExprs) {
 
 	mb.buildUniqueChecksForUpdate()
 
+	mb.buildExclusionChecksForUpdate()
+
 	mb.buildFKChecksForUpdate()
 
 	private := mb.makeMutationPrivate(returning != nil)
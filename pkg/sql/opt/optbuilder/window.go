@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treewindow"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -267,12 +268,16 @@ func (b *Builder) buildAggregationAsWindow(
 	}
 
 	// Initialize the aggregate expression.
-	aggregateExpr := g.aggInScope.expr
+	aggregateExpr := b.constructExpand(g, g.aggInScope.expr)
 
 	// frames accumulates the set of distinct window frames we're computing over
 	// so that we can group functions over the same partition and ordering.
 	frames := make([]memo.WindowExpr, 0, len(g.aggs))
 	for i, agg := range g.aggs {
+		if agg.def.Overload.UDFAggregate != nil {
+			panic(unimplemented.New("udf-aggregate-ordered",
+				"user-defined aggregates cannot be combined with ordered aggregations"))
+		}
 		fn := b.constructAggregate(agg.def.Name, argLists[i])
 		if filterCols[i] != 0 {
 			fn = b.factory.ConstructAggFilter(
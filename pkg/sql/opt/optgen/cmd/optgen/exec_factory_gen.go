@@ -80,7 +80,7 @@ func (g *execFactoryGen) genExecFactory() {
 	g.w.writeIndent("root Node,\n")
 	g.w.writeIndent("subqueries []Subquery,\n")
 	g.w.writeIndent("cascades []Cascade,\n")
-	g.w.writeIndent("checks []Node,\n")
+	g.w.writeIndent("checks []Check,\n")
 	g.w.writeIndent("rootRowCount int64,\n")
 	g.w.unnest(") (Plan, error)\n")
 
@@ -113,7 +113,7 @@ func (g *execFactoryGen) genStubFactory() {
 	g.w.writeIndent("root Node,\n")
 	g.w.writeIndent("subqueries []Subquery,\n")
 	g.w.writeIndent("cascades []Cascade,\n")
-	g.w.writeIndent("checks []Node,\n")
+	g.w.writeIndent("checks []Check,\n")
 	g.w.writeIndent("rootRowCount int64,\n")
 	g.w.unnest(") (Plan, error) {\n")
 	g.w.nestIndent("return struct{}{}, nil\n")
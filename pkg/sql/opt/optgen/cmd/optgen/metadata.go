@@ -206,6 +206,7 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"ScanFlags":           {fullName: "memo.ScanFlags", passByVal: true},
 		"JoinFlags":           {fullName: "memo.JoinFlags", passByVal: true},
 		"WindowFrame":         {fullName: "memo.WindowFrame", passByVal: true},
+		"GroupingSets":        {fullName: "memo.GroupingSets", passByVal: true},
 		"FKCascades":          {fullName: "memo.FKCascades", passByVal: true},
 		"ExplainOptions":      {fullName: "tree.ExplainOptions", passByVal: true},
 		"StatementReturnType": {fullName: "tree.StatementReturnType", passByVal: true},
@@ -221,6 +222,8 @@ func newMetadata(compiled *lang.CompiledExpr, pkg string) *metadata {
 		"Statement":           {fullName: "tree.Statement", isInterface: true},
 		"Subquery":            {fullName: "tree.Subquery", isPointer: true, usePointerIntern: true},
 		"CreateTable":         {fullName: "tree.CreateTable", isPointer: true, usePointerIntern: true},
+		"CreateFunction":      {fullName: "tree.CreateFunction", isPointer: true, usePointerIntern: true},
+		"CreateTrigger":       {fullName: "tree.CreateTrigger", isPointer: true, usePointerIntern: true},
 		"CreateStats":         {fullName: "tree.CreateStats", isPointer: true, usePointerIntern: true},
 		"TableName":           {fullName: "tree.TableName", isPointer: true, usePointerIntern: true},
 		"Constraint":          {fullName: "constraint.Constraint", isPointer: true, usePointerIntern: true},
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
@@ -222,7 +223,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		switch def := def.(type) {
 		case *tree.UniqueConstraintTableDef:
 			if def.WithoutIndex {
-				tab.addUniqueConstraint(def.Name, def.Columns, def.Predicate, def.WithoutIndex)
+				tab.addUniqueConstraint(
+					def.Name, def.Columns, def.Predicate, def.WithoutIndex, def.Deferrability,
+				)
 			} else if !def.PrimaryKey {
 				tab.addIndex(&def.IndexTableDef, uniqueIndex)
 			}
@@ -230,6 +233,9 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 		case *tree.IndexTableDef:
 			tab.addIndex(def, nonUniqueIndex)
 
+		case *tree.ExclusionConstraintTableDef:
+			tab.addExclusionConstraint(def)
+
 		case *tree.FamilyTableDef:
 			tab.addFamily(def)
 
@@ -241,6 +247,7 @@ func (tc *Catalog) CreateTable(stmt *tree.CreateTable) *Table {
 						tree.IndexElemList{{Column: def.Name}},
 						nil, /* predicate */
 						def.Unique.WithoutIndex,
+						def.Unique.Deferrability,
 					)
 				} else {
 					tab.addIndex(
@@ -535,13 +542,18 @@ func (tc *Catalog) resolveFK(tab *Table, d *tree.ForeignKeyConstraintTableDef) {
 		matchMethod:              d.Match,
 		deleteAction:             d.Actions.Delete,
 		updateAction:             d.Actions.Update,
+		deferrability:            d.Deferrability,
 	}
 	tab.outboundFKs = append(tab.outboundFKs, fk)
 	targetTable.inboundFKs = append(targetTable.inboundFKs, fk)
 }
 
 func (tt *Table) addUniqueConstraint(
-	name tree.Name, columns tree.IndexElemList, predicate tree.Expr, withoutIndex bool,
+	name tree.Name,
+	columns tree.IndexElemList,
+	predicate tree.Expr,
+	withoutIndex bool,
+	deferrability tree.ConstraintDeferrability,
 ) {
 	// We don't currently use unique constraints with an index (those are already
 	// tracked with unique indexes), so don't bother adding them.
@@ -562,6 +574,7 @@ func (tt *Table) addUniqueConstraint(
 		columnOrdinals: cols,
 		withoutIndex:   withoutIndex,
 		validated:      true,
+		deferrability:  deferrability,
 	}
 	// Add partial unique constraint predicate.
 	if predicate != nil {
@@ -580,6 +593,54 @@ func (tt *Table) addUniqueConstraint(
 	tt.uniqueConstraints = append(tt.uniqueConstraints, u)
 }
 
+// addExclusionConstraint adds an EXCLUDE constraint to the table, along with
+// the index backing it. The column compared with && is the last column of the
+// index, which is inverted.
+func (tt *Table) addExclusionConstraint(def *tree.ExclusionConstraintTableDef) {
+	var elems []tree.ExclusionElem
+	var overlaps []tree.ExclusionElem
+	for _, elem := range def.Elems {
+		if elem.Operator.Symbol == treecmp.Overlaps {
+			overlaps = append(overlaps, elem)
+		} else {
+			elems = append(elems, elem)
+		}
+	}
+	elems = append(elems, overlaps...)
+
+	name := string(def.Name)
+	if name == "" {
+		var buf bytes.Buffer
+		buf.WriteString(string(tt.TabName.ObjectName))
+		for i := range elems {
+			buf.WriteRune('_')
+			buf.WriteString(string(elems[i].Column))
+		}
+		buf.WriteString("_excl")
+		name = buf.String()
+	}
+
+	c := ExclusionConstraint{
+		name:  name,
+		tabID: tt.TabID,
+	}
+	idxDef := tree.IndexTableDef{
+		Name:      tree.Name(name),
+		Inverted:  len(overlaps) > 0,
+		Predicate: def.Predicate,
+	}
+	for _, elem := range elems {
+		c.columnOrdinals = append(c.columnOrdinals, tt.FindOrdinal(string(elem.Column)))
+		c.operators = append(c.operators, elem.Operator.Symbol)
+		idxDef.Columns = append(idxDef.Columns, tree.IndexElem{Column: elem.Column})
+	}
+	if def.Predicate != nil {
+		c.predicate = tree.Serialize(def.Predicate)
+	}
+	tt.addIndex(&idxDef, nonUniqueIndex)
+	tt.exclusionConstraints = append(tt.exclusionConstraints, c)
+}
+
 func (tt *Table) addColumn(def *tree.ColumnTableDef) {
 	ordinal := len(tt.Columns)
 	nullable := !def.PrimaryKey.IsPrimaryKey && def.Nullable.Nullability != tree.NotNull
@@ -701,7 +762,9 @@ func (tt *Table) addIndexWithVersion(
 ) *Index {
 	// Add a unique constraint if this is a primary or unique index.
 	if typ != nonUniqueIndex {
-		tt.addUniqueConstraint(def.Name, def.Columns, def.Predicate, false /* withoutIndex */)
+		tt.addUniqueConstraint(
+			def.Name, def.Columns, def.Predicate, false /* withoutIndex */, tree.ConstraintNotDeferrable,
+		)
 	}
 
 	idx := &Index{
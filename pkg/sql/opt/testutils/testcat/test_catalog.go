@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/stats"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/treeprinter"
@@ -660,6 +661,7 @@ type Table struct {
 	Stats      TableStats
 	Checks     []cat.CheckConstraint
 	Families   []*Family
+	Triggers   []*Trigger
 	IsVirtual  bool
 	IsSystem   bool
 	Catalog    *Catalog
@@ -675,6 +677,8 @@ type Table struct {
 
 	uniqueConstraints []UniqueConstraint
 
+	exclusionConstraints []ExclusionConstraint
+
 	// partitionBy is the partitioning clause that corresponds to the primary
 	// index. Used to initialize the partitioning for the primary index.
 	partitionBy *tree.PartitionBy
@@ -822,6 +826,26 @@ func (tt *Table) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &tt.uniqueConstraints[i]
 }
 
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (tt *Table) ExclusionConstraintCount() int {
+	return len(tt.exclusionConstraints)
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (tt *Table) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	return &tt.exclusionConstraints[i]
+}
+
+// TriggerCount is part of the cat.Table interface.
+func (tt *Table) TriggerCount() int {
+	return len(tt.Triggers)
+}
+
+// Trigger is part of the cat.Table interface.
+func (tt *Table) Trigger(i int) cat.Trigger {
+	return tt.Triggers[i]
+}
+
 // Zone is part of the cat.Table interface.
 func (tt *Table) Zone() cat.Zone {
 	zone := zonepb.DefaultZoneConfig()
@@ -1222,10 +1246,11 @@ type ForeignKeyConstraint struct {
 	originColumnOrdinals     []int
 	referencedColumnOrdinals []int
 
-	validated    bool
-	matchMethod  tree.CompositeKeyMatchMethod
-	deleteAction tree.ReferenceAction
-	updateAction tree.ReferenceAction
+	validated     bool
+	matchMethod   tree.CompositeKeyMatchMethod
+	deleteAction  tree.ReferenceAction
+	updateAction  tree.ReferenceAction
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &ForeignKeyConstraint{}
@@ -1293,6 +1318,11 @@ func (fk *ForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction {
 	return fk.updateAction
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *ForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // UniqueConstraint implements cat.UniqueConstraint. See that interface
 // for more information on the fields.
 type UniqueConstraint struct {
@@ -1302,6 +1332,7 @@ type UniqueConstraint struct {
 	predicate      string
 	withoutIndex   bool
 	validated      bool
+	deferrability  tree.ConstraintDeferrability
 }
 
 var _ cat.UniqueConstraint = &UniqueConstraint{}
@@ -1347,12 +1378,65 @@ func (u *UniqueConstraint) Validated() bool {
 	return u.validated
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *UniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return u.deferrability
+}
+
 // UniquenessGuaranteedByAnotherIndex is part of the cat.UniqueConstraint
 // interface.
 func (u *UniqueConstraint) UniquenessGuaranteedByAnotherIndex() bool {
 	return false
 }
 
+// ExclusionConstraint implements cat.ExclusionConstraint. See that interface
+// for more information on the fields.
+type ExclusionConstraint struct {
+	name           string
+	tabID          cat.StableID
+	columnOrdinals []int
+	operators      []treecmp.ComparisonOperatorSymbol
+	predicate      string
+}
+
+var _ cat.ExclusionConstraint = &ExclusionConstraint{}
+
+// Name is part of the cat.ExclusionConstraint interface.
+func (c *ExclusionConstraint) Name() string {
+	return c.name
+}
+
+// TableID is part of the cat.ExclusionConstraint interface.
+func (c *ExclusionConstraint) TableID() cat.StableID {
+	return c.tabID
+}
+
+// ColumnCount is part of the cat.ExclusionConstraint interface.
+func (c *ExclusionConstraint) ColumnCount() int {
+	return len(c.columnOrdinals)
+}
+
+// ColumnOrdinal is part of the cat.ExclusionConstraint interface.
+func (c *ExclusionConstraint) ColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != c.tabID {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to ColumnOrdinal (expected %d)",
+			tab.ID(), c.tabID,
+		))
+	}
+	return c.columnOrdinals[i]
+}
+
+// Operator is part of the cat.ExclusionConstraint interface.
+func (c *ExclusionConstraint) Operator(i int) treecmp.ComparisonOperatorSymbol {
+	return c.operators[i]
+}
+
+// Predicate is part of the cat.ExclusionConstraint interface.
+func (c *ExclusionConstraint) Predicate() (string, bool) {
+	return c.predicate, c.predicate != ""
+}
+
 // Sequence implements the cat.Sequence interface for testing purposes.
 type Sequence struct {
 	SeqID      cat.StableID
@@ -1446,3 +1530,38 @@ func (tf *Family) ColumnCount() int {
 func (tf *Family) Column(i int) cat.FamilyColumn {
 	return tf.Columns[i]
 }
+
+// Trigger implements the cat.Trigger interface for testing purposes.
+type Trigger struct {
+	TrigName       string
+	TrigActionTime tree.TriggerActionTime
+	TrigEvents     tree.TriggerEvents
+	TrigBody       string
+}
+
+var _ cat.Trigger = &Trigger{}
+
+// Name is part of the cat.Trigger interface.
+func (tt *Trigger) Name() tree.Name {
+	return tree.Name(tt.TrigName)
+}
+
+// ActionTime is part of the cat.Trigger interface.
+func (tt *Trigger) ActionTime() tree.TriggerActionTime {
+	return tt.TrigActionTime
+}
+
+// HasEvent is part of the cat.Trigger interface.
+func (tt *Trigger) HasEvent(event tree.TriggerEvent) bool {
+	for _, e := range tt.TrigEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Body is part of the cat.Trigger interface.
+func (tt *Trigger) Body() string {
+	return tt.TrigBody
+}
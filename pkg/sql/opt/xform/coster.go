@@ -526,6 +526,9 @@ func (c *coster) ComputeCost(candidate memo.RelExpr, required *physical.Required
 	case opt.OrdinalityOp:
 		cost = c.computeOrdinalityCost(candidate.(*memo.OrdinalityExpr))
 
+	case opt.ExpandOp:
+		cost = c.computeExpandCost(candidate.(*memo.ExpandExpr))
+
 	case opt.ProjectSetOp:
 		cost = c.computeProjectSetCost(candidate.(*memo.ProjectSetExpr))
 
@@ -1292,6 +1295,15 @@ func (c *coster) computeOrdinalityCost(ord *memo.OrdinalityExpr) memo.Cost {
 	return cost
 }
 
+func (c *coster) computeExpandCost(expand *memo.ExpandExpr) memo.Cost {
+	// Add the CPU cost of emitting the rows. Each grouping set column also has
+	// to be copied (or nulled out) for every output row.
+	rowCount := memo.Cost(expand.Relational().Stats.RowCount)
+	cost := rowCount * cpuCostFactor
+	cost += rowCount * memo.Cost(len(expand.OutCols)) * cpuCostFactor
+	return cost
+}
+
 func (c *coster) computeProjectSetCost(projectSet *memo.ProjectSetExpr) memo.Cost {
 	// Add the CPU cost of emitting the rows.
 	cost := memo.Cost(projectSet.Relational().Stats.RowCount) * cpuCostFactor
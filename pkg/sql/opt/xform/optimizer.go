@@ -971,6 +971,9 @@ func (o *Optimizer) disableRules(probability float64) {
 		// supports distinct on an empty column set.
 		int(opt.EliminateDistinctNoColumns),
 		int(opt.EliminateEnsureDistinctNoColumns),
+		// Needed to prevent "could not decorrelate subquery" errors for calls
+		// to user-defined functions with arguments which refer to columns.
+		int(opt.InlineUDF),
 	)
 
 	for i := opt.RuleName(1); i < opt.NumRuleNames; i++ {
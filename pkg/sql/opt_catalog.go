@@ -706,6 +706,8 @@ type optTable struct {
 
 	uniqueConstraints []optUniqueConstraint
 
+	exclusionConstraints []optExclusionConstraint
+
 	outboundFKs []optForeignKeyConstraint
 	inboundFKs  []optForeignKeyConstraint
 
@@ -843,12 +845,13 @@ func newOptTable(
 	for i := range ot.desc.GetUniqueWithoutIndexConstraints() {
 		u := &ot.desc.GetUniqueWithoutIndexConstraints()[i]
 		ot.uniqueConstraints = append(ot.uniqueConstraints, optUniqueConstraint{
-			name:         u.Name,
-			table:        ot.ID(),
-			columns:      u.ColumnIDs,
-			predicate:    u.Predicate,
-			withoutIndex: true,
-			validity:     u.Validity,
+			name:          u.Name,
+			table:         ot.ID(),
+			columns:       u.ColumnIDs,
+			predicate:     u.Predicate,
+			withoutIndex:  true,
+			validity:      u.Validity,
+			deferrability: u.Deferrability(),
 		})
 	}
 
@@ -933,6 +936,17 @@ func newOptTable(
 				})
 			}
 		}
+
+		// EXCLUDE constraints are enforced as soon as the index backing them is
+		// writable, so that rows written while the index is being backfilled
+		// are checked as well. Temporary indexes used by the backfill are
+		// skipped, since they back the same constraint.
+		if idx.IsExclusion() && !idx.DeleteOnly() && !idx.IsTemporaryIndexForBackfill() {
+			ot.exclusionConstraints = append(ot.exclusionConstraints, optExclusionConstraint{
+				table: ot.ID(),
+				index: idx,
+			})
+		}
 	}
 
 	_ = ot.desc.ForeachOutboundFK(func(fk *descpb.ForeignKeyConstraint) error {
@@ -946,6 +960,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability(),
 		})
 		return nil
 	})
@@ -960,6 +975,7 @@ func newOptTable(
 			match:             fk.Match,
 			deleteAction:      fk.OnDelete,
 			updateAction:      fk.OnUpdate,
+			deferrability:     fk.Deferrability(),
 		})
 		return nil
 	})
@@ -1231,6 +1247,26 @@ func (ot *optTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	return &ot.uniqueConstraints[i]
 }
 
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (ot *optTable) ExclusionConstraintCount() int {
+	return len(ot.exclusionConstraints)
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (ot *optTable) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	return &ot.exclusionConstraints[i]
+}
+
+// TriggerCount is part of the cat.Table interface.
+func (ot *optTable) TriggerCount() int {
+	return len(ot.desc.GetTriggers())
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optTable) Trigger(i int) cat.Trigger {
+	return &optTrigger{desc: &ot.desc.GetTriggers()[i]}
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optTable) Zone() cat.Zone {
 	return ot.zone
@@ -1703,6 +1739,34 @@ func (oi *optFamily) Table() cat.Table {
 	return oi.tab
 }
 
+// optTrigger is a wrapper around descpb.TableDescriptor_Trigger that
+// implements cat.Trigger.
+type optTrigger struct {
+	desc *descpb.TableDescriptor_Trigger
+}
+
+var _ cat.Trigger = &optTrigger{}
+
+// Name is part of the cat.Trigger interface.
+func (ot *optTrigger) Name() tree.Name {
+	return tree.Name(ot.desc.Name)
+}
+
+// ActionTime is part of the cat.Trigger interface.
+func (ot *optTrigger) ActionTime() tree.TriggerActionTime {
+	return descpb.TriggerActionTimeType[ot.desc.ActionTime]
+}
+
+// HasEvent is part of the cat.Trigger interface.
+func (ot *optTrigger) HasEvent(event tree.TriggerEvent) bool {
+	return ot.desc.HasEvent(descpb.TriggerEventValue[event])
+}
+
+// Body is part of the cat.Trigger interface.
+func (ot *optTrigger) Body() string {
+	return ot.desc.FuncBody
+}
+
 // optUniqueConstraint implements cat.UniqueConstraint and represents a
 // unique constraint.
 type optUniqueConstraint struct {
@@ -1712,8 +1776,9 @@ type optUniqueConstraint struct {
 	columns   []descpb.ColumnID
 	predicate string
 
-	withoutIndex bool
-	validity     descpb.ConstraintValidity
+	withoutIndex  bool
+	validity      descpb.ConstraintValidity
+	deferrability tree.ConstraintDeferrability
 
 	uniquenessGuaranteedByAnotherIndex bool
 }
@@ -1763,6 +1828,11 @@ func (u *optUniqueConstraint) Validated() bool {
 	return u.validity == descpb.ConstraintValidity_Validated
 }
 
+// Deferrability is part of the cat.UniqueConstraint interface.
+func (u *optUniqueConstraint) Deferrability() tree.ConstraintDeferrability {
+	return u.deferrability
+}
+
 // UniquenessGuaranteedByAnotherIndex is part of the cat.UniqueConstraint
 // interface. It is a hack to make unique hash sharded index work before issue
 // #75070 is resolved. Be sure to remove `ignoreUniquenessCheck` field from
@@ -1771,6 +1841,56 @@ func (u *optUniqueConstraint) UniquenessGuaranteedByAnotherIndex() bool {
 	return u.uniquenessGuaranteedByAnotherIndex
 }
 
+// optExclusionConstraint implements cat.ExclusionConstraint and represents an
+// EXCLUDE constraint backed by an index.
+type optExclusionConstraint struct {
+	table cat.StableID
+	index catalog.Index
+}
+
+var _ cat.ExclusionConstraint = &optExclusionConstraint{}
+
+// Name is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Name() string {
+	return e.index.GetName()
+}
+
+// TableID is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) TableID() cat.StableID {
+	return e.table
+}
+
+// ColumnCount is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnCount() int {
+	return e.index.NumKeyColumns()
+}
+
+// ColumnOrdinal is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) ColumnOrdinal(tab cat.Table, i int) int {
+	if tab.ID() != e.table {
+		panic(errors.AssertionFailedf(
+			"invalid table %d passed to ColumnOrdinal (expected %d)",
+			tab.ID(), e.table,
+		))
+	}
+	optTab := convertTableToOptTable(tab)
+	ord, _ := optTab.lookupColumnOrdinal(e.index.GetKeyColumnID(i))
+	return ord
+}
+
+// Operator is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Operator(i int) treecmp.ComparisonOperatorSymbol {
+	if e.index.GetExclusionOperator(i) == treecmp.Overlaps.String() {
+		return treecmp.Overlaps
+	}
+	return treecmp.EQ
+}
+
+// Predicate is part of the cat.ExclusionConstraint interface.
+func (e *optExclusionConstraint) Predicate() (string, bool) {
+	return e.index.GetPredicate(), e.index.IsPartial()
+}
+
 // optForeignKeyConstraint implements cat.ForeignKeyConstraint and represents a
 // foreign key relationship. Both the origin and the referenced table store the
 // same optForeignKeyConstraint (as an outbound and inbound reference,
@@ -1784,10 +1904,11 @@ type optForeignKeyConstraint struct {
 	referencedTable   cat.StableID
 	referencedColumns []descpb.ColumnID
 
-	validity     descpb.ConstraintValidity
-	match        descpb.ForeignKeyReference_Match
-	deleteAction catpb.ForeignKeyAction
-	updateAction catpb.ForeignKeyAction
+	validity      descpb.ConstraintValidity
+	match         descpb.ForeignKeyReference_Match
+	deleteAction  catpb.ForeignKeyAction
+	updateAction  catpb.ForeignKeyAction
+	deferrability tree.ConstraintDeferrability
 }
 
 var _ cat.ForeignKeyConstraint = &optForeignKeyConstraint{}
@@ -1859,6 +1980,11 @@ func (fk *optForeignKeyConstraint) UpdateReferenceAction() tree.ReferenceAction
 	return descpb.ForeignKeyReferenceActionType[fk.updateAction]
 }
 
+// Deferrability is part of the cat.ForeignKeyConstraint interface.
+func (fk *optForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return fk.deferrability
+}
+
 // optVirtualTable is similar to optTable but is used with virtual tables.
 type optVirtualTable struct {
 	desc catalog.TableDescriptor
@@ -2156,6 +2282,26 @@ func (ot *optVirtualTable) Unique(i cat.UniqueOrdinal) cat.UniqueConstraint {
 	panic(errors.AssertionFailedf("no unique constraints"))
 }
 
+// ExclusionConstraintCount is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionConstraintCount() int {
+	return 0
+}
+
+// ExclusionConstraint is part of the cat.Table interface.
+func (ot *optVirtualTable) ExclusionConstraint(i int) cat.ExclusionConstraint {
+	panic(errors.AssertionFailedf("no exclusion constraints"))
+}
+
+// TriggerCount is part of the cat.Table interface.
+func (ot *optVirtualTable) TriggerCount() int {
+	return 0
+}
+
+// Trigger is part of the cat.Table interface.
+func (ot *optVirtualTable) Trigger(i int) cat.Trigger {
+	panic(errors.AssertionFailedf("no triggers"))
+}
+
 // Zone is part of the cat.Table interface.
 func (ot *optVirtualTable) Zone() cat.Zone {
 	panic(errors.AssertionFailedf("no zone"))
@@ -2420,7 +2566,7 @@ func collectTypes(col catalog.Column) (descpb.IDs, error) {
 		}
 	}
 	if typ := col.GetType(); typ != nil && typ.UserDefined() {
-		visitor.OIDs[typ.Oid()] = struct{}{}
+		visitor.OIDs[typ.UserDefinedOID()] = struct{}{}
 	}
 
 	ids := make(descpb.IDs, 0, len(visitor.OIDs))
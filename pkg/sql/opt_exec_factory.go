@@ -33,6 +33,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/explain"
 	"github.com/cockroachdb/cockroach/pkg/sql/row"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/catid"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treewindow"
@@ -495,6 +496,8 @@ func (ef *execFactory) addAggregations(n *groupNode, aggregations []exec.AggInfo
 			agg.Distinct,
 		)
 		f.filterRenderIdx = int(agg.Filter)
+		f.userDefined = agg.UserDefined
+		f.resultType = agg.ResultType
 
 		n.funcs = append(n.funcs, f)
 	}
@@ -589,6 +592,34 @@ func (ef *execFactory) ConstructOrdinality(input exec.Node, colName string) (exe
 	}, nil
 }
 
+// ConstructExpand is part of the exec.Factory interface.
+func (ef *execFactory) ConstructExpand(
+	input exec.Node,
+	groupingCols []exec.NodeColumnOrdinal,
+	groupingSets [][]int,
+	setIDColName string,
+) (exec.Node, error) {
+	plan := input.(planNode)
+	inputColumns := planColumns(plan)
+	cols := make(colinfo.ResultColumns, 0, len(inputColumns)+len(groupingCols)+1)
+	cols = append(cols, inputColumns...)
+	groupingColIdxs := make([]int, len(groupingCols))
+	for i, col := range groupingCols {
+		cols = append(cols, inputColumns[col])
+		groupingColIdxs[i] = int(col)
+	}
+	cols = append(cols, colinfo.ResultColumn{
+		Name: setIDColName,
+		Typ:  types.Int,
+	})
+	return &expandNode{
+		source:       plan,
+		groupingCols: groupingColIdxs,
+		groupingSets: groupingSets,
+		columns:      cols,
+	}, nil
+}
+
 // ConstructIndexJoin is part of the exec.Factory interface.
 func (ef *execFactory) ConstructIndexJoin(
 	input exec.Node,
@@ -1114,7 +1145,7 @@ func (ef *execFactory) ConstructPlan(
 	root exec.Node,
 	subqueries []exec.Subquery,
 	cascades []exec.Cascade,
-	checks []exec.Node,
+	checks []exec.Check,
 	rootRowCount int64,
 ) (exec.Plan, error) {
 	// No need to spool at the root.
@@ -1654,6 +1685,7 @@ func (ef *execFactory) ConstructDelete(
 	table cat.Table,
 	fetchColOrdSet exec.TableColumnOrdinalSet,
 	returnColOrdSet exec.TableColumnOrdinalSet,
+	passthrough colinfo.ResultColumns,
 	autoCommit bool,
 ) (exec.Node, error) {
 	// Derive table and column descriptors.
@@ -1681,7 +1713,8 @@ func (ef *execFactory) ConstructDelete(
 		source: input.(planNode),
 		run: deleteRun{
 			td:                        tableDeleter{rd: rd, alloc: ef.planner.alloc},
-			partialIndexDelValsOffset: len(rd.FetchCols),
+			partialIndexDelValsOffset: len(rd.FetchCols) + len(passthrough),
+			numPassthrough:            len(passthrough),
 		},
 	}
 
@@ -1691,6 +1724,8 @@ func (ef *execFactory) ConstructDelete(
 		// Delete returns the non-mutation columns specified, in the same
 		// order they are defined in the table.
 		del.columns = colinfo.ResultColumnsFromColumns(tabDesc.GetID(), returnCols)
+		// Add the passthrough columns to the returning columns.
+		del.columns = append(del.columns, passthrough...)
 
 		del.run.rowIdxToRetIdx = row.ColMapping(rd.FetchCols, returnCols)
 		del.run.rowsNeeded = true
@@ -1836,6 +1871,56 @@ func (ef *execFactory) ConstructCreateView(
 	}, nil
 }
 
+// ConstructCreateFunction is part of the exec.Factory interface.
+func (ef *execFactory) ConstructCreateFunction(
+	schema cat.Schema, cf *tree.CreateFunction, functionBody string, deps opt.ViewDeps,
+) (exec.Node, error) {
+
+	if err := checkSchemaChangeEnabled(
+		ef.planner.EvalContext().Context,
+		ef.planner.ExecCfg(),
+		"CREATE FUNCTION",
+	); err != nil {
+		return nil, err
+	}
+
+	var depIDs catalog.DescriptorIDSet
+	for _, d := range deps {
+		desc, err := getDescForDataSource(d.DataSource)
+		if err != nil {
+			return nil, err
+		}
+		depIDs.Add(desc.GetID())
+	}
+
+	return &createFunctionNode{
+		cf:           cf,
+		functionBody: functionBody,
+		dbDesc:       schema.(*optSchema).database,
+		scDesc:       schema.(*optSchema).schema,
+		depIDs:       depIDs,
+	}, nil
+}
+
+// ConstructCreateTrigger is part of the exec.Factory interface.
+func (ef *execFactory) ConstructCreateTrigger(
+	table cat.Table, ct *tree.CreateTrigger, function *tree.Overload, functionBody string,
+) (exec.Node, error) {
+	if err := checkSchemaChangeEnabled(
+		ef.planner.EvalContext().Context,
+		ef.planner.ExecCfg(),
+		"CREATE TRIGGER",
+	); err != nil {
+		return nil, err
+	}
+	return &createTriggerNode{
+		ct:           ct,
+		tableID:      table.(*optTable).desc.GetID(),
+		funcID:       catid.FuncOIDToID(function.Oid),
+		functionBody: functionBody,
+	}, nil
+}
+
 // ConstructSequenceSelect is part of the exec.Factory interface.
 func (ef *execFactory) ConstructSequenceSelect(sequence cat.Sequence) (exec.Node, error) {
 	return ef.planner.SequenceSelectNode(sequence.(*optSequence).desc)
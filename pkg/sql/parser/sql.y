@@ -599,6 +599,12 @@ func (u *sqlSymUnion) idxElem() tree.IndexElem {
 func (u *sqlSymUnion) idxElems() tree.IndexElemList {
     return u.val.(tree.IndexElemList)
 }
+func (u *sqlSymUnion) exclusionElem() tree.ExclusionElem {
+    return u.val.(tree.ExclusionElem)
+}
+func (u *sqlSymUnion) exclusionElems() tree.ExclusionElemList {
+    return u.val.(tree.ExclusionElemList)
+}
 func (u *sqlSymUnion) dropBehavior() tree.DropBehavior {
     return u.val.(tree.DropBehavior)
 }
@@ -683,6 +689,9 @@ func (u *sqlSymUnion) transactionModes() tree.TransactionModes {
 func (u *sqlSymUnion) compositeKeyMatchMethod() tree.CompositeKeyMatchMethod {
   return u.val.(tree.CompositeKeyMatchMethod)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+  return u.val.(tree.ConstraintDeferrability)
+}
 func (u *sqlSymUnion) referenceAction() tree.ReferenceAction {
     return u.val.(tree.ReferenceAction)
 }
@@ -778,6 +787,42 @@ func (u *sqlSymUnion) cursorScrollOption() tree.CursorScrollOption {
 func (u *sqlSymUnion) cursorStmt() tree.CursorStmt {
     return u.val.(tree.CursorStmt)
 }
+func (u *sqlSymUnion) functionArgs() tree.FuncArgs {
+    return u.val.(tree.FuncArgs)
+}
+func (u *sqlSymUnion) functionArg() tree.FuncArg {
+    return u.val.(tree.FuncArg)
+}
+func (u *sqlSymUnion) functionArgClass() tree.FuncArgClass {
+    return u.val.(tree.FuncArgClass)
+}
+func (u *sqlSymUnion) functionOptions() tree.FunctionOptions {
+    return u.val.(tree.FunctionOptions)
+}
+func (u *sqlSymUnion) functionOption() tree.FunctionOption {
+    return u.val.(tree.FunctionOption)
+}
+func (u *sqlSymUnion) triggerActionTime() tree.TriggerActionTime {
+    return u.val.(tree.TriggerActionTime)
+}
+func (u *sqlSymUnion) triggerEvent() tree.TriggerEvent {
+    return u.val.(tree.TriggerEvent)
+}
+func (u *sqlSymUnion) triggerEvents() tree.TriggerEvents {
+    return u.val.(tree.TriggerEvents)
+}
+func (u *sqlSymUnion) functionObjs() tree.FuncObjs {
+    return u.val.(tree.FuncObjs)
+}
+func (u *sqlSymUnion) functionObj() tree.FuncObj {
+    return u.val.(tree.FuncObj)
+}
+func (u *sqlSymUnion) aggregateOptions() tree.AggregateOptions {
+    return u.val.(tree.AggregateOptions)
+}
+func (u *sqlSymUnion) aggregateOption() tree.AggregateOption {
+    return u.val.(tree.AggregateOption)
+}
 func (u *sqlSymUnion) asTenantClause() tree.TenantID {
     return u.val.(tree.TenantID)
 }
@@ -808,7 +853,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %token <str> BUCKET_COUNT
 %token <str> BOOLEAN BOTH BOX2D BUNDLE BY
 
-%token <str> CACHE CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
+%token <str> CACHE CALLED CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK CLOSE
 %token <str> CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMENTS COMMIT
 %token <str> COMMITTED COMPACT COMPLETE COMPLETIONS CONCAT CONCURRENTLY CONFIGURATION CONFIGURATIONS CONFIGURE
@@ -820,16 +865,16 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEBUG_PAUSE_ON DEC DECIMAL DEFAULT DEFAULTS
 %token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DESC DESTINATION DETACHED
-%token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
+%token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP DROPPED_COLUMNS
 
-%token <str> ELSE ENCODING ENCRYPTED ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
+%token <str> EACH ELSE ENCODING ENCRYPTED ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
 %token <str> EXPERIMENTAL_FINGERPRINTS EXPERIMENTAL_REPLICA
 %token <str> EXPERIMENTAL_AUDIT EXPERIMENTAL_RELOCATE
 %token <str> EXPIRATION EXPLAIN EXPORT EXTENSION EXTRACT EXTRACT_DURATION
 
 %token <str> FAILURE FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
-%token <str> FILES FILTER
+%token <str> FILES FILTER FINALFUNC
 %token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE FORCE_INDEX FORCE_ZIGZAG
 %token <str> FOREIGN FORWARD FREEZE FROM FULL FUNCTION FUNCTIONS
 
@@ -837,14 +882,14 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %token <str> GEOMETRYCOLLECTION GEOMETRYCOLLECTIONM GEOMETRYCOLLECTIONZ GEOMETRYCOLLECTIONZM
 %token <str> GLOBAL GOAL GRANT GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HASH HEADER HIGH HISTOGRAM HOLD HOUR
+%token <str> HAVING HASH HEADER HIGH HISTOGRAM HISTORY HOLD HOUR
 
 %token <str> IDENTITY
-%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCLUDE
+%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMMUTABLE IMPORT IN INCLUDE
 %token <str> INCLUDING INCREMENT INCREMENTAL INCREMENTAL_LOCATION
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
-%token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INITIALLY
-%token <str> INNER INSENSITIVE INSERT INT INTEGER
+%token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INITCOND INJECT INITIALLY
+%token <str> INNER INOUT INPUT INSENSITIVE INSERT INT INTEGER
 %token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED IS ISERROR ISNULL ISOLATION
 
 %token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
@@ -852,7 +897,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %token <str> KEY KEYS KMS KV
 
 %token <str> LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
-%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT
+%token <str> LEADING LEAKPROOF LEASE LEAST LEFT LESS LEVEL LIKE LIMIT
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
 %token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
@@ -872,23 +917,23 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACEMENT PLACING
 %token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
 %token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
-%token <str> PROCEDURAL PUBLIC PUBLICATION
+%token <str> PROCEDURAL PROCEDURE PUBLIC PUBLICATION
 
 %token <str> QUERIES QUERY QUOTE
 
 %token <str> RANGE RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> RELATIVE RELOCATE REMOVE_PATH RENAME REPEATABLE REPLACE REPLICATION
-%token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESUME RETURNING RETRY REVISION_HISTORY
+%token <str> RELEASE RESET RESTART RESTORE RESTRICT RESTRICTED RESUME RETURNING RETURNS RETRY REVISION_HISTORY
 %token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS RSHIFT RULE RUNNING
 
 %token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCROLL SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
-%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETS SETTING SETTINGS
-%token <str> SHARE SHOW SIMILAR SIMPLE SKIP SKIP_LOCALITIES_CHECK SKIP_MISSING_FOREIGN_KEYS
+%token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETOF SETS SETTING SETTINGS
+%token <str> SFUNC SHARE SHOW SIMILAR SIMPLE SKIP SKIP_LOCALITIES_CHECK SKIP_MISSING_FOREIGN_KEYS
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 %token <str> SQLLOGIN
 
-%token <str> START STATE STATISTICS STATUS STDIN STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING SUPER
+%token <str> STABLE START STATE STATISTICS STATUS STDIN STREAM STRICT STRING STORAGE STORE STORED STORING STYPE SUBSTRING SUPER
 %token <str> SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENTS
 
 %token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TENANTS TESTING_RELOCATE TEXT THEN
@@ -901,7 +946,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %token <str> UPDATE UPSERT UNSET UNTIL USE USER USERS USING UUID
 
 %token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIEWACTIVITY VIEWACTIVITYREDACTED
-%token <str> VIEWCLUSTERSETTING VIRTUAL VISIBLE VOTERS
+%token <str> VIEWCLUSTERSETTING VIRTUAL VISIBLE VOLATILE VOTERS
 
 %token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRITE
 
@@ -1045,10 +1090,16 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.Statement> create_ddl_stmt
 %type <tree.Statement> create_database_stmt
 %type <tree.Statement> create_extension_stmt
+%type <tree.Statement> create_func_stmt
+%type <tree.Statement> create_domain_stmt
+%type <tree.Statement> create_aggregate_stmt
+%type <tree.Statement> create_trigger_stmt
 %type <tree.Statement> create_index_stmt
 %type <tree.Statement> create_role_stmt
 %type <tree.Statement> create_schedule_for_backup_stmt
 %type <tree.Statement> create_schema_stmt
+%type <tree.Statement> create_publication_stmt
+%type <tree.Statement> create_subscription_stmt
 %type <tree.Statement> create_table_stmt
 %type <tree.Statement> create_table_as_stmt
 %type <tree.Statement> create_view_stmt
@@ -1069,8 +1120,14 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.Statement> drop_index_stmt
 %type <tree.Statement> drop_role_stmt
 %type <tree.Statement> drop_schema_stmt
+%type <tree.Statement> drop_publication_stmt
+%type <tree.Statement> drop_subscription_stmt
 %type <tree.Statement> drop_table_stmt
 %type <tree.Statement> drop_type_stmt
+%type <tree.Statement> drop_func_stmt
+%type <tree.Statement> drop_domain_stmt
+%type <tree.Statement> drop_aggregate_stmt
+%type <tree.Statement> drop_trigger_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
 
@@ -1109,6 +1166,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt set_or_reset_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -1184,7 +1242,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 
 %type <[]string> opt_incremental
 %type <tree.KVOption> kv_option
-%type <[]tree.KVOption> kv_option_list opt_with_options var_set_list opt_with_schedule_options
+%type <[]tree.KVOption> kv_option_list opt_with_options opt_subscription_options var_set_list opt_with_schedule_options
 %type <*tree.BackupOptions> opt_with_backup_options backup_options backup_options_list
 %type <*tree.RestoreOptions> opt_with_restore_options restore_options restore_options_list
 %type <tree.ShowBackupDetails> show_backup_details
@@ -1250,7 +1308,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <str> family_name opt_family_name table_alias_name constraint_name target_name zone_name partition_name collation_name
 %type <str> db_object_name_component
 %type <*tree.UnresolvedObjectName> table_name db_name standalone_index_name sequence_name type_name view_name db_object_name simple_db_object_name complex_db_object_name
-%type <[]*tree.UnresolvedObjectName> type_name_list
+%type <[]*tree.UnresolvedObjectName> type_name_list constraint_name_list
 %type <str> schema_name opt_in_schema
 %type <tree.ObjectNamePrefix>  qualifiable_schema_name opt_schema_name wildcard_pattern
 %type <tree.ObjectNamePrefixList> schema_name_list
@@ -1291,7 +1349,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.NameList> name_list privilege_list
 %type <[]int32> opt_array_bounds
 %type <tree.From> from_clause
-%type <tree.TableExprs> from_list rowsfrom_list opt_from_list
+%type <tree.TableExprs> from_list rowsfrom_list opt_from_list opt_using_clause
 %type <tree.TablePatterns> table_pattern_list
 %type <tree.TableNames> table_name_list opt_locked_rels
 %type <tree.Exprs> expr_list opt_expr_list tuple1_ambiguous_values tuple1_unambiguous_values
@@ -1308,7 +1366,6 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <*tree.Limit> select_limit opt_select_limit
 %type <tree.TableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
-%type <empty> opt_using_clause
 %type <tree.RefreshDataOption> opt_clear_data
 
 %type <[]tree.SequenceOption> sequence_option_list opt_sequence_option_list
@@ -1333,6 +1390,26 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <bool> opt_unique opt_concurrently opt_cluster opt_without_index
 %type <bool> opt_index_access_method
 
+%type <bool> opt_or_replace opt_return_set
+%type <tree.FuncArgs> opt_func_arg_with_default_list func_arg_with_default_list
+%type <tree.FuncArg> func_arg_with_default func_arg
+%type <tree.FuncArgClass> func_arg_class
+%type <tree.ResolvableTypeReference> func_return_type func_type
+%type <tree.TriggerActionTime> trigger_action_time
+%type <tree.TriggerEvent> trigger_event
+%type <tree.TriggerEvents> trigger_event_list
+%type <tree.FunctionOptions> opt_create_func_opt_list create_func_opt_list
+%type <tree.FunctionOption> create_func_opt_item common_func_opt_item
+%type <*tree.UnresolvedObjectName> func_create_name
+%type <str> param_name func_as
+%type <tree.FuncObjs> function_with_argtypes_list
+%type <tree.FuncObj> function_with_argtypes
+%type <tree.FuncObjs> aggregate_with_argtypes_list
+%type <tree.FuncObj> aggregate_with_argtypes
+%type <tree.FuncArgs> aggregate_args
+%type <tree.AggregateOptions> aggregate_option_list
+%type <tree.AggregateOption> aggregate_option
+
 %type <*tree.Limit> limit_clause offset_clause opt_limit_clause
 %type <tree.Expr> select_fetch_first_value
 %type <empty> row_or_rows
@@ -1350,7 +1427,7 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.NameList> opt_storing
 %type <*tree.ColumnTableDef> column_def
 %type <tree.TableDef> table_elem
-%type <tree.Expr> where_clause opt_where_clause
+%type <tree.Expr> where_clause opt_where_clause opt_exclude_where
 %type <*tree.ArraySubscript> array_subscript
 %type <tree.Expr> opt_slice_bound
 %type <*tree.IndexFlags> opt_index_flags
@@ -1374,6 +1451,8 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <bool> opt_ordinality opt_compact
 %type <*tree.Order> sortby
 %type <tree.IndexElem> index_elem index_elem_options create_as_param
+%type <tree.ExclusionElemList> exclude_elem_list
+%type <tree.ExclusionElem> exclude_elem
 %type <tree.TableExpr> table_ref numeric_table_ref func_table
 %type <tree.Exprs> rowsfrom_list
 %type <tree.Expr> rowsfrom_item
@@ -1433,9 +1512,14 @@ func (u *sqlSymUnion) asTenantClause() tree.TenantID {
 %type <tree.TableDef> family_def
 %type <[]tree.NamedColumnQualification> col_qual_list create_as_col_qual_list
 %type <tree.NamedColumnQualification> col_qualification create_as_col_qualification
+%type <[]tree.NamedColumnQualification> opt_domain_constraint_list
+%type <tree.NamedColumnQualification> domain_constraint
+%type <tree.ColumnQualification> domain_constraint_elem
 %type <tree.ColumnQualification> col_qualification_elem create_as_col_qualification_elem
 %type <tree.CompositeKeyMatchMethod> key_match
 %type <tree.ReferenceActions> reference_actions
+%type <tree.ConstraintDeferrability> opt_deferrable
+%type <bool> set_constraints_mode
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
 %type <tree.Expr> func_application func_expr_common_subexpr special_function
@@ -3100,6 +3184,11 @@ opt_with_schedule_options:
 // RESTORE SYSTEM USERS FROM <location...>
 //         [ AS OF SYSTEM TIME <expr> ]
 //         [ WITH <option> [= <value>] [, ...] ]
+// or
+// RESTORE TABLE <tablename> FROM <location...>
+//         [ AS OF SYSTEM TIME <expr> ]
+//         WHERE <predicate> [UPSERT] INTO <tablename>
+//         [ WITH <option> [= <value>] [, ...] ]
 //
 // Targets:
 //    TABLE <pattern> [, ...]
@@ -3120,6 +3209,7 @@ opt_with_schedule_options:
 //    skip_localities_check: ignore difference of zone configuration between restore cluster and backup cluster
 //    debug_pause_on: describes the events that the job should pause itself on for debugging purposes.
 //    new_db_name: renames the restored database. only applies to database restores
+//    dropped_columns: with WHERE ... INTO, only restore the columns dropped since the backup
 // %SeeAlso: BACKUP, WEBDOCS/restore.html
 restore_stmt:
   RESTORE FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
@@ -3160,6 +3250,54 @@ restore_stmt:
       Options: *($8.restoreOptions()),
     }
   }
+| RESTORE targets FROM list_of_string_or_placeholder_opt_list opt_as_of_clause WHERE a_expr INTO table_name opt_with_restore_options
+  {
+    $$.val = &tree.Restore{
+      Targets: $2.targetList(),
+      From: $4.listOfStringOrPlaceholderOptList(),
+      AsOf: $5.asOfClause(),
+      Where: tree.NewWhere(tree.AstWhere, $7.expr()),
+      Into: $9.unresolvedObjectName(),
+      Options: *($10.restoreOptions()),
+    }
+  }
+| RESTORE targets FROM list_of_string_or_placeholder_opt_list opt_as_of_clause WHERE a_expr UPSERT INTO table_name opt_with_restore_options
+  {
+    $$.val = &tree.Restore{
+      Targets: $2.targetList(),
+      From: $4.listOfStringOrPlaceholderOptList(),
+      AsOf: $5.asOfClause(),
+      Where: tree.NewWhere(tree.AstWhere, $7.expr()),
+      Into: $10.unresolvedObjectName(),
+      UpsertInto: true,
+      Options: *($11.restoreOptions()),
+    }
+  }
+| RESTORE targets FROM string_or_placeholder IN list_of_string_or_placeholder_opt_list opt_as_of_clause WHERE a_expr INTO table_name opt_with_restore_options
+  {
+    $$.val = &tree.Restore{
+      Targets: $2.targetList(),
+      Subdir: $4.expr(),
+      From: $6.listOfStringOrPlaceholderOptList(),
+      AsOf: $7.asOfClause(),
+      Where: tree.NewWhere(tree.AstWhere, $9.expr()),
+      Into: $11.unresolvedObjectName(),
+      Options: *($12.restoreOptions()),
+    }
+  }
+| RESTORE targets FROM string_or_placeholder IN list_of_string_or_placeholder_opt_list opt_as_of_clause WHERE a_expr UPSERT INTO table_name opt_with_restore_options
+  {
+    $$.val = &tree.Restore{
+      Targets: $2.targetList(),
+      Subdir: $4.expr(),
+      From: $6.listOfStringOrPlaceholderOptList(),
+      AsOf: $7.asOfClause(),
+      Where: tree.NewWhere(tree.AstWhere, $9.expr()),
+      Into: $12.unresolvedObjectName(),
+      UpsertInto: true,
+      Options: *($13.restoreOptions()),
+    }
+  }
 | RESTORE SYSTEM USERS FROM list_of_string_or_placeholder_opt_list opt_as_of_clause opt_with_restore_options
   {
     $$.val = &tree.Restore{
@@ -3314,6 +3452,10 @@ restore_options:
   {
     $$.val = &tree.RestoreOptions{AsTenant: $3.expr()}
   }
+| DROPPED_COLUMNS
+  {
+    $$.val = &tree.RestoreOptions{DroppedColumns: true}
+  }
 
 import_format:
   name
@@ -3764,7 +3906,7 @@ comment_text:
 // %Text:
 // CREATE DATABASE, CREATE TABLE, CREATE INDEX, CREATE TABLE AS,
 // CREATE USER, CREATE VIEW, CREATE SEQUENCE, CREATE STATISTICS,
-// CREATE ROLE, CREATE TYPE, CREATE EXTENSION
+// CREATE ROLE, CREATE TYPE, CREATE EXTENSION, CREATE FUNCTION
 create_stmt:
   create_role_stmt     // EXTEND WITH HELP: CREATE ROLE
 | create_ddl_stmt      // help texts in sub-rule
@@ -3772,6 +3914,8 @@ create_stmt:
 | create_schedule_for_backup_stmt   // EXTEND WITH HELP: CREATE SCHEDULE FOR BACKUP
 | create_changefeed_stmt
 | create_extension_stmt  // EXTEND WITH HELP: CREATE EXTENSION
+| create_publication_stmt  // EXTEND WITH HELP: CREATE PUBLICATION
+| create_subscription_stmt // EXTEND WITH HELP: CREATE SUBSCRIPTION
 | create_unsupported   {}
 | CREATE error         // SHOW HELP: CREATE
 
@@ -3795,30 +3939,416 @@ create_extension_stmt:
   }
 | CREATE EXTENSION error // SHOW HELP: CREATE EXTENSION
 
+// %Help: CREATE PUBLICATION - define a new publication
+// %Category: DDL
+// %Text:
+// CREATE PUBLICATION <name> [ FOR TABLE <tablename> [, ...] | FOR ALL TABLES ]
+// %SeeAlso: DROP PUBLICATION, CREATE SUBSCRIPTION
+create_publication_stmt:
+  CREATE PUBLICATION name
+  {
+    $$.val = &tree.CreatePublication{Name: tree.Name($3)}
+  }
+| CREATE PUBLICATION name FOR TABLE table_name_list
+  {
+    $$.val = &tree.CreatePublication{Name: tree.Name($3), Tables: $6.tableNames()}
+  }
+| CREATE PUBLICATION name FOR ALL TABLES
+  {
+    $$.val = &tree.CreatePublication{Name: tree.Name($3), AllTables: true}
+  }
+| CREATE PUBLICATION error // SHOW HELP: CREATE PUBLICATION
+
+// %Help: CREATE SUBSCRIPTION - define a new subscription
+// %Category: CCL
+// %Text:
+// CREATE SUBSCRIPTION <name> CONNECTION <uri> PUBLICATION <publication>
+//    [ WITH ( <option> [= <value>] [, ...] ) ]
+//
+// Options:
+//    conflict_resolution = 'overwrite' | 'ignore'
+// %SeeAlso: DROP SUBSCRIPTION, CREATE PUBLICATION
+create_subscription_stmt:
+  CREATE SUBSCRIPTION name CONNECTION string_or_placeholder PUBLICATION name opt_subscription_options
+  {
+    $$.val = &tree.CreateSubscription{
+      Name: tree.Name($3),
+      Connection: $5.expr(),
+      Publication: tree.Name($7),
+      Options: $8.kvOptions(),
+    }
+  }
+| CREATE SUBSCRIPTION error // SHOW HELP: CREATE SUBSCRIPTION
+
+opt_subscription_options:
+  WITH '(' kv_option_list ')'
+  {
+    $$.val = $3.kvOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
+
+// %Help: CREATE FUNCTION - define a new function
+// %Category: DDL
+// %Text:
+// CREATE [OR REPLACE] FUNCTION <name> ( [ [ <argmode> ] [ <argname> ] <argtype> [ { DEFAULT | = } <default_expr> ] [, ...] ] )
+//    RETURNS [ SETOF ] <rettype>
+//    { LANGUAGE <lang_name>
+//      | { IMMUTABLE | STABLE | VOLATILE }
+//      | [ NOT ] LEAKPROOF
+//      | { CALLED ON NULL INPUT | RETURNS NULL ON NULL INPUT | STRICT }
+//      | AS '<definition>'
+//    } ...
+// %SeeAlso: DROP FUNCTION
+create_func_stmt:
+  CREATE opt_or_replace FUNCTION func_create_name '(' opt_func_arg_with_default_list ')' RETURNS opt_return_set func_return_type opt_create_func_opt_list
+  {
+    $$.val = &tree.CreateFunction{
+      Replace: $2.bool(),
+      FuncName: $4.unresolvedObjectName(),
+      Args: $6.functionArgs(),
+      ReturnType: tree.FuncReturnType{
+        Type: $10.typeReference(),
+        IsSet: $9.bool(),
+      },
+      Options: $11.functionOptions(),
+    }
+  }
+| CREATE opt_or_replace FUNCTION error // SHOW HELP: CREATE FUNCTION
+
+func_create_name:
+  db_object_name
+
+// %Help: CREATE AGGREGATE - define a new aggregate function
+// %Category: DDL
+// %Text:
+// CREATE AGGREGATE <name> ( { * | [ <argname> ] <argtype> [, ...] } ) (
+//    SFUNC = <sfunc>,
+//    STYPE = <state_data_type>
+//    [ , FINALFUNC = <ffunc> ]
+//    [ , INITCOND = <initial_condition> ]
+// )
+// %SeeAlso: DROP AGGREGATE, CREATE FUNCTION
+create_aggregate_stmt:
+  CREATE AGGREGATE func_create_name aggregate_args '(' aggregate_option_list ')'
+  {
+    $$.val = &tree.CreateAggregate{
+      FuncName: $3.unresolvedObjectName(),
+      Args: $4.functionArgs(),
+      Options: $6.aggregateOptions(),
+    }
+  }
+| CREATE AGGREGATE error // SHOW HELP: CREATE AGGREGATE
+
+aggregate_args:
+  '(' '*' ')'
+  {
+    $$.val = tree.FuncArgs{}
+  }
+| '(' func_arg_with_default_list ')'
+  {
+    $$.val = $2.functionArgs()
+  }
+
+aggregate_option_list:
+  aggregate_option
+  {
+    $$.val = tree.AggregateOptions{$1.aggregateOption()}
+  }
+| aggregate_option_list ',' aggregate_option
+  {
+    $$.val = append($1.aggregateOptions(), $3.aggregateOption())
+  }
+
+aggregate_option:
+  SFUNC '=' db_object_name
+  {
+    $$.val = &tree.AggregateStateFunc{Name: $3.unresolvedObjectName()}
+  }
+| STYPE '=' typename
+  {
+    $$.val = &tree.AggregateStateType{Type: $3.typeReference()}
+  }
+| FINALFUNC '=' db_object_name
+  {
+    $$.val = &tree.AggregateFinalFunc{Name: $3.unresolvedObjectName()}
+  }
+| INITCOND '=' SCONST
+  {
+    $$.val = tree.AggregateInitCond($3)
+  }
+| INITCOND '=' numeric_only
+  {
+    $$.val = tree.AggregateInitCond($3.numVal().String())
+  }
+
+opt_func_arg_with_default_list:
+  func_arg_with_default_list
+| /* EMPTY */
+  {
+    $$.val = tree.FuncArgs{}
+  }
+
+func_arg_with_default_list:
+  func_arg_with_default
+  {
+    $$.val = tree.FuncArgs{$1.functionArg()}
+  }
+| func_arg_with_default_list ',' func_arg_with_default
+  {
+    $$.val = append($1.functionArgs(), $3.functionArg())
+  }
+
+func_arg_with_default:
+  func_arg
+| func_arg DEFAULT a_expr
+  {
+    arg := $1.functionArg()
+    arg.DefaultVal = $3.expr()
+    $$.val = arg
+  }
+| func_arg '=' a_expr
+  {
+    arg := $1.functionArg()
+    arg.DefaultVal = $3.expr()
+    $$.val = arg
+  }
+
+func_arg:
+  func_arg_class param_name func_type
+  {
+    $$.val = tree.FuncArg{
+      Name: tree.Name($2),
+      Type: $3.typeReference(),
+      Class: $1.functionArgClass(),
+    }
+  }
+| param_name func_arg_class func_type
+  {
+    $$.val = tree.FuncArg{
+      Name: tree.Name($1),
+      Type: $3.typeReference(),
+      Class: $2.functionArgClass(),
+    }
+  }
+| param_name func_type
+  {
+    $$.val = tree.FuncArg{
+      Name: tree.Name($1),
+      Type: $2.typeReference(),
+      Class: tree.FunctionArgIn,
+    }
+  }
+| func_arg_class func_type
+  {
+    $$.val = tree.FuncArg{
+      Type: $2.typeReference(),
+      Class: $1.functionArgClass(),
+    }
+  }
+| func_type
+  {
+    $$.val = tree.FuncArg{
+      Type: $1.typeReference(),
+      Class: tree.FunctionArgIn,
+    }
+  }
+
+func_arg_class:
+  IN
+  {
+    $$.val = tree.FunctionArgIn
+  }
+| OUT
+  {
+    $$.val = tree.FunctionArgOut
+  }
+| INOUT
+  {
+    $$.val = tree.FunctionArgInOut
+  }
+| IN OUT
+  {
+    $$.val = tree.FunctionArgInOut
+  }
+| VARIADIC
+  {
+    $$.val = tree.FunctionArgVariadic
+  }
+
+func_type:
+  typename
+
+func_return_type:
+  func_type
+
+opt_return_set:
+  SETOF
+  {
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+param_name:
+  type_function_name
+
+opt_create_func_opt_list:
+  create_func_opt_list
+| /* EMPTY */
+  {
+    $$.val = tree.FunctionOptions{}
+  }
+
+create_func_opt_list:
+  create_func_opt_item
+  {
+    $$.val = tree.FunctionOptions{$1.functionOption()}
+  }
+| create_func_opt_list create_func_opt_item
+  {
+    $$.val = append($1.functionOptions(), $2.functionOption())
+  }
+
+create_func_opt_item:
+  AS func_as
+  {
+    $$.val = tree.FunctionBodyStr($2)
+  }
+| LANGUAGE non_reserved_word_or_sconst
+  {
+    $$.val = tree.FunctionLanguage(strings.ToLower($2))
+  }
+| WINDOW error
+  {
+    return unimplementedWithIssueDetail(sqllex, 17511, "create window function")
+  }
+| common_func_opt_item
+
+common_func_opt_item:
+  CALLED ON NULL INPUT
+  {
+    $$.val = tree.FunctionCalledOnNullInput
+  }
+| RETURNS NULL ON NULL INPUT
+  {
+    $$.val = tree.FunctionReturnsNullOnNullInput
+  }
+| STRICT
+  {
+    $$.val = tree.FunctionStrict
+  }
+| IMMUTABLE
+  {
+    $$.val = tree.FunctionImmutable
+  }
+| STABLE
+  {
+    $$.val = tree.FunctionStable
+  }
+| VOLATILE
+  {
+    $$.val = tree.FunctionVolatile
+  }
+| LEAKPROOF
+  {
+    $$.val = tree.FunctionLeakproof(true)
+  }
+| NOT LEAKPROOF
+  {
+    $$.val = tree.FunctionLeakproof(false)
+  }
+
+func_as:
+  SCONST
+
+// %Help: CREATE TRIGGER - define a new trigger
+// %Category: DDL
+// %Text:
+// CREATE TRIGGER <name> { BEFORE | AFTER } { INSERT | UPDATE | DELETE } [ OR ... ]
+//    ON <tablename> FOR [ EACH ] ROW EXECUTE { FUNCTION | PROCEDURE } <funcname> ( )
+// %SeeAlso: DROP TRIGGER, CREATE FUNCTION
+create_trigger_stmt:
+  CREATE TRIGGER name trigger_action_time trigger_event_list ON table_name FOR opt_each ROW EXECUTE function_or_procedure db_object_name '(' ')'
+  {
+    $$.val = &tree.CreateTrigger{
+      Name: tree.Name($3),
+      ActionTime: $4.triggerActionTime(),
+      Events: $5.triggerEvents(),
+      Table: $7.unresolvedObjectName(),
+      FuncName: $13.unresolvedObjectName(),
+    }
+  }
+| CREATE TRIGGER error // SHOW HELP: CREATE TRIGGER
+
+trigger_action_time:
+  BEFORE
+  {
+    $$.val = tree.TriggerActionTimeBefore
+  }
+| AFTER
+  {
+    $$.val = tree.TriggerActionTimeAfter
+  }
+
+trigger_event_list:
+  trigger_event
+  {
+    $$.val = tree.TriggerEvents{$1.triggerEvent()}
+  }
+| trigger_event_list OR trigger_event
+  {
+    $$.val = append($1.triggerEvents(), $3.triggerEvent())
+  }
+
+trigger_event:
+  INSERT
+  {
+    $$.val = tree.TriggerEventInsert
+  }
+| UPDATE
+  {
+    $$.val = tree.TriggerEventUpdate
+  }
+| DELETE
+  {
+    $$.val = tree.TriggerEventDelete
+  }
+| TRUNCATE
+  {
+    return unimplementedWithIssueDetail(sqllex, 28296, "create trigger truncate")
+  }
+
+opt_each:
+  EACH {}
+| /* EMPTY */ {}
+
+function_or_procedure:
+  FUNCTION {}
+| PROCEDURE {}
+
 create_unsupported:
   CREATE ACCESS METHOD error { return unimplemented(sqllex, "create access method") }
-| CREATE AGGREGATE error { return unimplementedWithIssueDetail(sqllex, 74775, "create aggregate") }
 | CREATE CAST error { return unimplemented(sqllex, "create cast") }
 | CREATE CONSTRAINT TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create constraint") }
 | CREATE CONVERSION error { return unimplemented(sqllex, "create conversion") }
 | CREATE DEFAULT CONVERSION error { return unimplemented(sqllex, "create def conv") }
 | CREATE FOREIGN TABLE error { return unimplemented(sqllex, "create foreign table") }
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
-| CREATE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
-| CREATE OR REPLACE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
 | CREATE OPERATOR error { return unimplementedWithIssue(sqllex, 65017) }
-| CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
 | CREATE opt_or_replace RULE error { return unimplemented(sqllex, "create rule") }
 | CREATE SERVER error { return unimplemented(sqllex, "create server") }
-| CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TABLESPACE error { return unimplementedWithIssueDetail(sqllex, 54113, "create tablespace") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
-| CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create trigger") }
 
 opt_or_replace:
-  OR REPLACE {}
-| /* EMPTY */ {}
+  OR REPLACE { $$.val = true }
+| /* EMPTY */ { $$.val = false }
 
 opt_trusted:
   TRUSTED {}
@@ -3830,24 +4360,18 @@ opt_procedural:
 
 drop_unsupported:
   DROP ACCESS METHOD error { return unimplemented(sqllex, "drop access method") }
-| DROP AGGREGATE error { return unimplementedWithIssueDetail(sqllex, 74775, "drop aggregate") }
 | DROP CAST error { return unimplemented(sqllex, "drop cast") }
 | DROP COLLATION error { return unimplemented(sqllex, "drop collation") }
 | DROP CONVERSION error { return unimplemented(sqllex, "drop conversion") }
-| DROP DOMAIN error { return unimplementedWithIssueDetail(sqllex, 27796, "drop") }
 | DROP EXTENSION IF EXISTS name error { return unimplementedWithIssueDetail(sqllex, 74777, "drop extension if exists") }
 | DROP EXTENSION name error { return unimplementedWithIssueDetail(sqllex, 74777, "drop extension") }
 | DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
-| DROP FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "drop function") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
-| DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
 | DROP RULE error { return unimplemented(sqllex, "drop rule") }
 | DROP SERVER error { return unimplemented(sqllex, "drop server") }
-| DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
-| DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
 create_ddl_stmt:
   create_database_stmt // EXTEND WITH HELP: CREATE DATABASE
@@ -3858,6 +4382,10 @@ create_ddl_stmt:
 // Error case for both CREATE TABLE and CREATE TABLE ... AS in one
 | CREATE opt_persistence_temp_table TABLE error   // SHOW HELP: CREATE TABLE
 | create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
+| create_domain_stmt   // EXTEND WITH HELP: CREATE DOMAIN
+| create_func_stmt     // EXTEND WITH HELP: CREATE FUNCTION
+| create_aggregate_stmt // EXTEND WITH HELP: CREATE AGGREGATE
+| create_trigger_stmt  // EXTEND WITH HELP: CREATE TRIGGER
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
 
@@ -4036,7 +4564,7 @@ opt_changefeed_sink:
 
 // %Help: DELETE - delete rows from a table
 // %Category: DML
-// %Text: DELETE FROM <tablename> [WHERE <expr>]
+// %Text: DELETE FROM <tablename> [USING <exprs...>] [WHERE <expr>]
 //               [ORDER BY <exprs...>]
 //               [LIMIT <expr>]
 //               [RETURNING <exprs...>]
@@ -4047,6 +4575,7 @@ delete_stmt:
     $$.val = &tree.Delete{
       With: $1.with(),
       Table: $4.tblExpr(),
+      Using: $5.tblExprs(),
       Where: tree.NewWhere(tree.AstWhere, $6.expr()),
       OrderBy: $7.orderBy(),
       Limit: $8.limit(),
@@ -4056,8 +4585,14 @@ delete_stmt:
 | opt_with_clause DELETE error // SHOW HELP: DELETE
 
 opt_using_clause:
-  USING from_list { return unimplementedWithIssueDetail(sqllex, 40963, "delete using") }
-| /* EMPTY */ { }
+  USING from_list
+  {
+    $$.val = $2.tblExprs()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.TableExprs{}
+  }
 
 
 // %Help: DISCARD - reset the session to its initial state
@@ -4078,14 +4613,46 @@ discard_stmt:
 // %Category: Group
 // %Text:
 // DROP DATABASE, DROP INDEX, DROP TABLE, DROP VIEW, DROP SEQUENCE,
-// DROP USER, DROP ROLE, DROP TYPE
+// DROP USER, DROP ROLE, DROP TYPE, DROP FUNCTION
 drop_stmt:
   drop_ddl_stmt      // help texts in sub-rule
 | drop_role_stmt     // EXTEND WITH HELP: DROP ROLE
 | drop_schedule_stmt // EXTEND WITH HELP: DROP SCHEDULES
+| drop_publication_stmt  // EXTEND WITH HELP: DROP PUBLICATION
+| drop_subscription_stmt // EXTEND WITH HELP: DROP SUBSCRIPTION
 | drop_unsupported   {}
 | DROP error         // SHOW HELP: DROP
 
+// %Help: DROP PUBLICATION - remove a publication
+// %Category: DDL
+// %Text: DROP PUBLICATION [IF EXISTS] <name> [, ...]
+// %SeeAlso: CREATE PUBLICATION
+drop_publication_stmt:
+  DROP PUBLICATION name_list
+  {
+    $$.val = &tree.DropPublication{Names: $3.nameList(), IfExists: false}
+  }
+| DROP PUBLICATION IF EXISTS name_list
+  {
+    $$.val = &tree.DropPublication{Names: $5.nameList(), IfExists: true}
+  }
+| DROP PUBLICATION error // SHOW HELP: DROP PUBLICATION
+
+// %Help: DROP SUBSCRIPTION - remove a subscription
+// %Category: CCL
+// %Text: DROP SUBSCRIPTION [IF EXISTS] <name>
+// %SeeAlso: CREATE SUBSCRIPTION
+drop_subscription_stmt:
+  DROP SUBSCRIPTION name
+  {
+    $$.val = &tree.DropSubscription{Name: tree.Name($3), IfExists: false}
+  }
+| DROP SUBSCRIPTION IF EXISTS name
+  {
+    $$.val = &tree.DropSubscription{Name: tree.Name($5), IfExists: true}
+  }
+| DROP SUBSCRIPTION error // SHOW HELP: DROP SUBSCRIPTION
+
 drop_ddl_stmt:
   drop_database_stmt // EXTEND WITH HELP: DROP DATABASE
 | drop_index_stmt    // EXTEND WITH HELP: DROP INDEX
@@ -4094,6 +4661,10 @@ drop_ddl_stmt:
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
 | drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 | drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
+| drop_domain_stmt   // EXTEND WITH HELP: DROP DOMAIN
+| drop_func_stmt     // EXTEND WITH HELP: DROP FUNCTION
+| drop_aggregate_stmt // EXTEND WITH HELP: DROP AGGREGATE
+| drop_trigger_stmt  // EXTEND WITH HELP: DROP TRIGGER
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
@@ -4228,6 +4799,144 @@ drop_type_stmt:
   }
 | DROP TYPE error // SHOW HELP: DROP TYPE
 
+// %Help: DROP DOMAIN - remove a domain type
+// %Category: DDL
+// %Text: DROP DOMAIN [IF EXISTS] <type_name> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE DOMAIN
+drop_domain_stmt:
+  DROP DOMAIN type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropDomain{
+      Names: $3.unresolvedObjectNames(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP DOMAIN IF EXISTS type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropDomain{
+      Names: $5.unresolvedObjectNames(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP DOMAIN error // SHOW HELP: DROP DOMAIN
+
+// %Help: DROP FUNCTION - remove a function
+// %Category: DDL
+// %Text: DROP FUNCTION [IF EXISTS] <name> [ ( [ [ <argmode> ] [ <argname> ] <argtype> [, ...] ] ) ] [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE FUNCTION
+drop_func_stmt:
+  DROP FUNCTION function_with_argtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $3.functionObjs(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP FUNCTION IF EXISTS function_with_argtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $5.functionObjs(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP FUNCTION error // SHOW HELP: DROP FUNCTION
+
+// %Help: DROP AGGREGATE - remove an aggregate function
+// %Category: DDL
+// %Text: DROP AGGREGATE [IF EXISTS] <name> ( { * | [ <argname> ] <argtype> [, ...] } ) [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE AGGREGATE
+drop_aggregate_stmt:
+  DROP AGGREGATE aggregate_with_argtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropAggregate{
+      Aggregates: $3.functionObjs(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP AGGREGATE IF EXISTS aggregate_with_argtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropAggregate{
+      Aggregates: $5.functionObjs(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP AGGREGATE error // SHOW HELP: DROP AGGREGATE
+
+// %Help: DROP TRIGGER - remove a trigger
+// %Category: DDL
+// %Text: DROP TRIGGER [IF EXISTS] <name> ON <tablename> [CASCADE | RESTRICT]
+// %SeeAlso: CREATE TRIGGER
+drop_trigger_stmt:
+  DROP TRIGGER name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($3),
+      Table: $5.unresolvedObjectName(),
+      IfExists: false,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP TRIGGER IF EXISTS name ON table_name opt_drop_behavior
+  {
+    $$.val = &tree.DropTrigger{
+      Name: tree.Name($5),
+      Table: $7.unresolvedObjectName(),
+      IfExists: true,
+      DropBehavior: $8.dropBehavior(),
+    }
+  }
+| DROP TRIGGER error // SHOW HELP: DROP TRIGGER
+
+function_with_argtypes_list:
+  function_with_argtypes
+  {
+    $$.val = tree.FuncObjs{$1.functionObj()}
+  }
+| function_with_argtypes_list ',' function_with_argtypes
+  {
+    $$.val = append($1.functionObjs(), $3.functionObj())
+  }
+
+aggregate_with_argtypes_list:
+  aggregate_with_argtypes
+  {
+    $$.val = tree.FuncObjs{$1.functionObj()}
+  }
+| aggregate_with_argtypes_list ',' aggregate_with_argtypes
+  {
+    $$.val = append($1.functionObjs(), $3.functionObj())
+  }
+
+aggregate_with_argtypes:
+  db_object_name aggregate_args
+  {
+    $$.val = tree.FuncObj{
+      FuncName: $1.unresolvedObjectName(),
+      Args: $2.functionArgs(),
+    }
+  }
+
+function_with_argtypes:
+  db_object_name '(' opt_func_arg_with_default_list ')'
+  {
+    $$.val = tree.FuncObj{
+      FuncName: $1.unresolvedObjectName(),
+      Args: $3.functionArgs(),
+    }
+  }
+| db_object_name
+  {
+    $$.val = tree.FuncObj{
+      FuncName: $1.unresolvedObjectName(),
+    }
+  }
+
 target_types:
   type_name_list
   {
@@ -4501,17 +5210,22 @@ alter_changefeed_cmd:
     }
   }
 
-// %Help: ALTER BACKUP - alter an existing backup's encryption keys
+// %Help: ALTER BACKUP - alter an existing backup's encryption keys or incremental layers
 // %Category: CCL
 // %Text:
 // ALTER BACKUP <location...>
 //        [ ADD NEW_KMS = <kms...> ]
 //        [ WITH OLD_KMS = <kms...> ]
+// ALTER BACKUP <location...>
+//        COMPACT [ BETWEEN <timestamp> AND <timestamp> ]
 // Locations:
 //    "[scheme]://[host]/[path to backup]?[parameters]"
 //
 // KMS:
 //    "[kms_provider]://[kms_host]/[master_key_identifier]?[parameters]" : add new kms keys to backup
+//
+// COMPACT merges the incremental layers of the backup, or those which end
+// between the given timestamps, into a single new incremental layer.
 alter_backup_stmt:
   ALTER BACKUP string_or_placeholder alter_backup_cmds
   {
@@ -4547,6 +5261,17 @@ alter_backup_cmd:
       KMSInfo:	$2.backupKMS(),
     }
 	}
+|	COMPACT
+	{
+    $$.val = &tree.AlterBackupCompact{}
+	}
+|	COMPACT BETWEEN string_or_placeholder AND string_or_placeholder
+	{
+    $$.val = &tree.AlterBackupCompact{
+      Start:	$3.expr(),
+      End:	$5.expr(),
+    }
+	}
 
 backup_kms:
 	NEW_KMS '=' string_or_placeholder_opt_list WITH OLD_KMS '=' string_or_placeholder_opt_list
@@ -4915,7 +5640,7 @@ use_stmt:
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 
 // SET SESSION / SET LOCAL / SET CLUSTER SETTING
 preparable_set_stmt:
@@ -5136,6 +5861,45 @@ set_local_stmt:
   }
 | SET LOCAL error  // SHOW HELP: SET LOCAL
 
+// %Help: SET CONSTRAINTS - set when constraints are checked
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS { ALL | <name> [, ...] } { DEFERRED | IMMEDIATE }
+//
+// Only constraints declared DEFERRABLE can be deferred until the end of
+// the current transaction.
+// %SeeAlso: SET TRANSACTION
+set_constraints_stmt:
+  SET CONSTRAINTS ALL set_constraints_mode
+  {
+    $$.val = &tree.SetConstraints{Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS constraint_name_list set_constraints_mode
+  {
+    $$.val = &tree.SetConstraints{Names: $3.unresolvedObjectNames(), Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
+set_constraints_mode:
+  DEFERRED
+  {
+    $$.val = true
+  }
+| IMMEDIATE
+  {
+    $$.val = false
+  }
+
+constraint_name_list:
+  db_object_name
+  {
+    $$.val = []*tree.UnresolvedObjectName{$1.unresolvedObjectName()}
+  }
+| constraint_name_list ',' db_object_name
+  {
+    $$.val = append($1.unresolvedObjectNames(), $3.unresolvedObjectName())
+  }
+
 // %Help: SET TRANSACTION - configure the transaction settings
 // %Category: Txn
 // %Text:
@@ -5283,11 +6047,11 @@ var_list:
 iso_level:
   READ UNCOMMITTED
   {
-    $$.val = tree.SerializableIsolation
+    $$.val = tree.ReadCommittedIsolation
   }
 | READ COMMITTED
   {
-    $$.val = tree.SerializableIsolation
+    $$.val = tree.ReadCommittedIsolation
   }
 | SNAPSHOT
   {
@@ -5731,7 +6495,7 @@ show_histogram_stmt:
 
 // %Help: SHOW BACKUP - list backup contents
 // %Category: CCL
-// %Text: SHOW BACKUP [SCHEMAS|FILES|RANGES] <location>
+// %Text: SHOW BACKUP [SCHEMAS|SCHEMA HISTORY|FILES|RANGES] <location>
 // %SeeAlso: WEBDOCS/show-backup.html
 show_backup_stmt:
   SHOW BACKUPS IN string_or_placeholder_opt_list
@@ -5802,6 +6566,10 @@ show_backup_details:
   {
     $$.val = tree.BackupSchemaDetails
   }
+| SCHEMA HISTORY
+  {
+    $$.val = tree.BackupSchemaHistoryDetails
+  }
 | FILES
 	{
 	$$.val = tree.BackupFileDetails
@@ -7634,6 +8402,22 @@ col_qualification_elem:
   {
     $$.val = &tree.ColumnCheckConstraint{Expr: $3.expr()}
   }
+| DEFERRABLE
+  {
+    $$.val = tree.ColumnConstraintDeferrability{Deferred: true}
+  }
+| NOT DEFERRABLE
+  {
+    $$.val = tree.ColumnConstraintDeferrability{}
+  }
+| INITIALLY DEFERRED
+  {
+    $$.val = tree.ColumnConstraintDeferrability{Initially: true, Deferred: true}
+  }
+| INITIALLY IMMEDIATE
+  {
+    $$.val = tree.ColumnConstraintDeferrability{Initially: true}
+  }
 | DEFAULT b_expr
   {
     $$.val = &tree.ColumnDefault{Expr: $2.expr()}
@@ -7772,6 +8556,10 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability().IsDeferrable() {
+      return setErr(sqllex, pgerror.New(pgcode.FeatureNotSupported,
+        "CHECK constraints cannot be marked DEFERRABLE"))
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
@@ -7787,6 +8575,7 @@ constraint_elem:
         PartitionByIndex: $7.partitionByIndex(),
         Predicate: $9.expr(),
       },
+      Deferrability: $8.constraintDeferrability(),
     }
   }
 | PRIMARY KEY '(' index_params ')' opt_hash_sharded opt_with_storage_parameter_list
@@ -7810,11 +8599,49 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrability: $11.constraintDeferrability(),
+    }
+  }
+| EXCLUDE opt_index_access_method '(' exclude_elem_list ')' opt_exclude_where
+  {
+    $$.val = &tree.ExclusionConstraintTableDef{
+      Inverted: $2.bool(),
+      Elems: $4.exclusionElems(),
+      Predicate: $6.expr(),
     }
   }
-| EXCLUDE USING error
+
+exclude_elem_list:
+  exclude_elem
   {
-    return unimplementedWithIssueDetail(sqllex, 46657, "add constraint exclude using")
+    $$.val = tree.ExclusionElemList{$1.exclusionElem()}
+  }
+| exclude_elem_list ',' exclude_elem
+  {
+    $$.val = append($1.exclusionElems(), $3.exclusionElem())
+  }
+
+exclude_elem:
+  name WITH all_op
+  {
+    /* FORCE DOC */
+    op, ok := $3.op().(treecmp.ComparisonOperator)
+    if !ok || (op.Symbol != treecmp.EQ && op.Symbol != treecmp.Overlaps) {
+      return unimplementedWithIssueDetail(sqllex, 46657, fmt.Sprintf("exclude using operator %s", $3.op()))
+    }
+    $$.val = tree.ExclusionElem{Column: tree.Name($1), Operator: op}
+  }
+
+// Unlike other predicates, the predicate of an EXCLUDE constraint must be
+// enclosed in parentheses, as in Postgres.
+opt_exclude_where:
+  WHERE '(' a_expr ')'
+  {
+    $$.val = $3.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
   }
 
 
@@ -7923,12 +8750,12 @@ create_as_col_qualification_elem:
   }
 
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */ { $$.val = tree.ConstraintNotDeferrable }
+| DEFERRABLE { $$.val = tree.ConstraintDeferrable }
+| DEFERRABLE INITIALLY DEFERRED { $$.val = tree.ConstraintInitiallyDeferred }
+| DEFERRABLE INITIALLY IMMEDIATE { $$.val = tree.ConstraintDeferrable }
+| INITIALLY DEFERRED { $$.val = tree.ConstraintInitiallyDeferred }
+| INITIALLY IMMEDIATE { $$.val = tree.ConstraintNotDeferrable }
 
 storing:
   COVERING
@@ -8547,8 +9374,70 @@ create_type_stmt:
 | CREATE TYPE type_name '(' error         { return unimplementedWithIssueDetail(sqllex, 27793, "base") }
   // Shell types, gateway to define base types using the previous syntax.
 | CREATE TYPE type_name                   { return unimplementedWithIssueDetail(sqllex, 27793, "shell") }
-  // Domain types.
-| CREATE DOMAIN type_name error           { return unimplementedWithIssueDetail(sqllex, 27796, "create") }
+
+// %Help: CREATE DOMAIN - create a domain type
+// %Category: DDL
+// %Text:
+// CREATE DOMAIN <type_name> [AS] <type>
+//    [ DEFAULT <expr> ]
+//    [ [ CONSTRAINT <constraint_name> ] { NOT NULL | NULL | CHECK ( <expr> ) } ... ]
+// %SeeAlso: DROP DOMAIN, CREATE TYPE
+create_domain_stmt:
+  CREATE DOMAIN type_name typename opt_domain_constraint_list
+  {
+    $$.val = &tree.CreateDomain{
+      TypeName: $3.unresolvedObjectName(),
+      Type: $4.typeReference(),
+      Constraints: $5.colQuals(),
+    }
+  }
+| CREATE DOMAIN type_name AS typename opt_domain_constraint_list
+  {
+    $$.val = &tree.CreateDomain{
+      TypeName: $3.unresolvedObjectName(),
+      Type: $5.typeReference(),
+      Constraints: $6.colQuals(),
+    }
+  }
+| CREATE DOMAIN error // SHOW HELP: CREATE DOMAIN
+
+opt_domain_constraint_list:
+  /* EMPTY */
+  {
+    $$.val = []tree.NamedColumnQualification(nil)
+  }
+| opt_domain_constraint_list domain_constraint
+  {
+    $$.val = append($1.colQuals(), $2.colQual())
+  }
+
+domain_constraint:
+  CONSTRAINT constraint_name domain_constraint_elem
+  {
+    $$.val = tree.NamedColumnQualification{Name: tree.Name($2), Qualification: $3.colQualElem()}
+  }
+| domain_constraint_elem
+  {
+    $$.val = tree.NamedColumnQualification{Qualification: $1.colQualElem()}
+  }
+| DEFAULT b_expr
+  {
+    $$.val = tree.NamedColumnQualification{Qualification: &tree.ColumnDefault{Expr: $2.expr()}}
+  }
+
+domain_constraint_elem:
+  NOT NULL
+  {
+    $$.val = tree.NotNullConstraint{}
+  }
+| NULL
+  {
+    $$.val = tree.NullConstraint{}
+  }
+| CHECK '(' a_expr ')'
+  {
+    $$.val = &tree.ColumnCheckConstraint{Expr: $3.expr()}
+  }
 
 opt_enum_val_list:
   enum_val_list
@@ -10557,11 +11446,23 @@ group_by_list:
 // Note the '(' is required as CUBE and ROLLUP rely on setting precedence
 // of CUBE and ROLLUP below that of '(', so that they shift in these rules
 // rather than reducing the conflicting unreserved_keyword rule.
+//
+// An empty grouping set is written as '(' ')', which is parsed as an empty
+// tuple by a_expr.
 group_by_item:
   a_expr { $$.val = $1.expr() }
-| ROLLUP '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "rollup") }
-| CUBE '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "cube") }
-| GROUPING SETS error { return unimplementedWithIssueDetail(sqllex, 46280, "grouping sets") }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.RollupExpr{Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.CubeExpr{Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSetsExpr{Exprs: $4.exprs()}
+  }
 
 having_clause:
   HAVING a_expr
@@ -12492,7 +13393,10 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingExpr{Exprs: $3.exprs()}
+  }
 
 func_application:
   func_name '(' ')'
@@ -14066,6 +14970,7 @@ unreserved_keyword:
 | BUNDLE
 | BY
 | CACHE
+| CALLED
 | CANCEL
 | CANCELQUERY
 | CASCADE
@@ -14117,6 +15022,8 @@ unreserved_keyword:
 | DOMAIN
 | DOUBLE
 | DROP
+| DROPPED_COLUMNS
+| EACH
 | ENCODING
 | ENCRYPTED
 | ENCRYPTION_PASSPHRASE
@@ -14139,6 +15046,7 @@ unreserved_keyword:
 | FAILURE
 | FILES
 | FILTER
+| FINALFUNC
 | FIRST
 | FOLLOWING
 | FORCE
@@ -14164,10 +15072,12 @@ unreserved_keyword:
 | HEADER
 | HIGH
 | HISTOGRAM
+| HISTORY
 | HOLD
 | HOUR
 | IDENTITY
 | IMMEDIATE
+| IMMUTABLE
 | IMPORT
 | INCLUDE
 | INCLUDING
@@ -14176,7 +15086,9 @@ unreserved_keyword:
 | INCREMENTAL_LOCATION
 | INDEXES
 | INHERITS
+| INITCOND
 | INJECT
+| INPUT
 | INSERT
 | INTO_DB
 | INVERTED
@@ -14193,6 +15105,7 @@ unreserved_keyword:
 | LATEST
 | LC_COLLATE
 | LC_CTYPE
+| LEAKPROOF
 | LEASE
 | LESS
 | LEVEL
@@ -14293,6 +15206,7 @@ unreserved_keyword:
 | PRIOR
 | PRIORITY
 | PRIVILEGES
+| PROCEDURE
 | PUBLIC
 | PUBLICATION
 | QUERIES
@@ -14325,6 +15239,7 @@ unreserved_keyword:
 | RESTRICTED
 | RESUME
 | RETRY
+| RETURNS
 | REVISION_HISTORY
 | REVOKE
 | ROLE
@@ -14340,6 +15255,7 @@ unreserved_keyword:
 | SCROLL
 | SETTING
 | SETTINGS
+| STABLE
 | STATUS
 | SAVEPOINT
 | SCANS
@@ -14357,6 +15273,7 @@ unreserved_keyword:
 | SESSIONS
 | SET
 | SETS
+| SFUNC
 | SHARE
 | SHOW
 | SIMPLE
@@ -14381,6 +15298,7 @@ unreserved_keyword:
 | STORING
 | STREAM
 | STRICT
+| STYPE
 | SUBSCRIPTION
 | SUPER
 | SURVIVE
@@ -14427,6 +15345,7 @@ unreserved_keyword:
 | VIEWACTIVITYREDACTED
 | VIEWCLUSTERSETTING
 | VISIBLE
+| VOLATILE
 | VOTERS
 | WITHIN
 | WITHOUT
@@ -14467,6 +15386,7 @@ col_name_keyword:
 | IF
 | IFERROR
 | IFNULL
+| INOUT
 | INT
 | INTEGER
 | INTERVAL
@@ -14482,6 +15402,7 @@ col_name_keyword:
 | PRECISION
 | REAL
 | ROW
+| SETOF
 | SMALLINT
 | STRING
 | SUBSTRING
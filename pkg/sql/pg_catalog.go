@@ -795,7 +795,6 @@ var (
 
 	// Avoid unused warning for constants.
 	_ = conTypeTrigger
-	_ = conTypeExclusion
 
 	fkActionNone       = tree.NewDString("a")
 	fkActionRestrict   = tree.NewDString("r")
@@ -963,6 +962,10 @@ func populateTableConstraints(
 				}
 				f.WriteString(strings.Join(colNames, ", "))
 				f.WriteByte(')')
+				if d := con.UniqueWithoutIndexConstraint.Deferrability(); d.IsDeferrable() {
+					f.WriteByte(' ')
+					f.WriteString(d.String())
+				}
 				if con.UniqueWithoutIndexConstraint.Validity != descpb.ConstraintValidity_Validated {
 					f.WriteString(" NOT VALID")
 				}
@@ -980,6 +983,22 @@ func populateTableConstraints(
 			}
 			condef = tree.NewDString(f.CloseAndGetString())
 
+		case descpb.ConstraintTypeExclusion:
+			conoid = h.ExclusionConstraintOid(db.GetID(), scName, table.GetID(), con.Index.ID)
+			contype = conTypeExclusion
+			conindid = h.IndexOid(table.GetID(), con.Index.ID)
+			var err error
+			if conkey, err = colIDArrayToDatum(con.Index.KeyColumnIDs); err != nil {
+				return err
+			}
+			def, err := catformat.ExclusionConstraintForDisplay(
+				ctx, table, con.Index, tree.FmtPGCatalog, p.SemaCtx(), p.SessionData(),
+			)
+			if err != nil {
+				return err
+			}
+			condef = tree.NewDString(def)
+
 		case descpb.ConstraintTypeCheck:
 			conoid = h.CheckConstraintOid(db.GetID(), scName, table.GetID(), con.CheckConstraint)
 			contype = conTypeCheck
@@ -999,13 +1018,16 @@ func populateTableConstraints(
 			condef = tree.NewDString(fmt.Sprintf("CHECK ((%s))%s", displayExpr, validity))
 		}
 
+		deferrability := con.Deferrability()
+		condeferrable := tree.MakeDBool(tree.DBool(deferrability.IsDeferrable()))
+		condeferred := tree.MakeDBool(tree.DBool(deferrability == tree.ConstraintInitiallyDeferred))
 		if err := addRow(
 			conoid,               // oid
 			dNameOrNull(conName), // conname
 			namespaceOid,         // connamespace
 			contype,              // contype
-			tree.DBoolFalse,      // condeferrable
-			tree.DBoolFalse,      // condeferred
+			condeferrable,        // condeferrable
+			condeferred,          // condeferred
 			tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -1057,6 +1079,14 @@ func (r oneAtATimeSchemaResolver) getTableByID(id descpb.ID) (catalog.TableDescr
 	return table, nil
 }
 
+func (r oneAtATimeSchemaResolver) getFunctionByID(
+	id descpb.ID,
+) (catalog.FunctionDescriptor, error) {
+	return r.p.Descriptors().GetImmutableFunctionByID(
+		r.ctx, r.p.txn, id, tree.ObjectLookupFlagsWithRequired(),
+	)
+}
+
 func (r oneAtATimeSchemaResolver) getSchemaByID(id descpb.ID) (catalog.SchemaDescriptor, error) {
 	return r.p.Descriptors().Direct().MustGetSchemaDescByID(r.ctx, r.p.txn, id)
 }
@@ -1663,6 +1693,13 @@ func getOIDFromConstraint(
 				tableID,
 				constraint.Index,
 			)
+		} else if constraint.Kind == descpb.ConstraintTypeExclusion {
+			oid = hasher.ExclusionConstraintOid(
+				dbID,
+				schemaName,
+				tableID,
+				constraint.Index.ID,
+			)
 		} else {
 			oid = hasher.UniqueConstraintOid(
 				dbID,
@@ -2907,23 +2944,38 @@ func addPGTypeRow(
 	if cat == typCategoryPseudo {
 		typType = typTypePseudo
 	}
+	typNotNull := tree.DBoolFalse
+	typBaseType := oidZero
+	typDefault := tree.DNull
+	if typ.IsDomain() {
+		// Domains do not have an array type.
+		typType = typTypeDomain
+		typArray = oidZero
+		typBaseType = tree.NewDOid(typ.Oid())
+		if domain := typ.TypeMeta.DomainData; domain != nil {
+			typNotNull = tree.MakeDBool(tree.DBool(domain.NotNull))
+			if domain.DefaultExpr != nil {
+				typDefault = tree.NewDString(*domain.DefaultExpr)
+			}
+		}
+	}
 	typname := typ.PGName()
 	typDelim := tree.NewDString(typ.Delimiter())
 	return addRow(
-		tree.NewDOid(typ.Oid()), // oid
-		tree.NewDName(typname),  // typname
-		nspOid,                  // typnamespace
-		owner,                   // typowner
-		typLen(typ),             // typlen
-		typByVal(typ),           // typbyval (is it fixedlen or not)
-		typType,                 // typtype
-		cat,                     // typcategory
-		tree.DBoolFalse,         // typispreferred
-		tree.DBoolTrue,          // typisdefined
-		typDelim,                // typdelim
-		oidZero,                 // typrelid
-		typElem,                 // typelem
-		typArray,                // typarray
+		typOid(typ),            // oid
+		tree.NewDName(typname), // typname
+		nspOid,                 // typnamespace
+		owner,                  // typowner
+		typLen(typ),            // typlen
+		typByVal(typ),          // typbyval (is it fixedlen or not)
+		typType,                // typtype
+		cat,                    // typcategory
+		tree.DBoolFalse,        // typispreferred
+		tree.DBoolTrue,         // typisdefined
+		typDelim,               // typdelim
+		oidZero,                // typrelid
+		typElem,                // typelem
+		typArray,               // typarray
 
 		// regproc references
 		h.RegProc(builtinPrefix+"in"),   // typinput
@@ -2936,13 +2988,13 @@ func addPGTypeRow(
 
 		tree.DNull,      // typalign
 		tree.DNull,      // typstorage
-		tree.DBoolFalse, // typnotnull
-		oidZero,         // typbasetype
+		typNotNull,      // typnotnull
+		typBaseType,     // typbasetype
 		negOneVal,       // typtypmod
 		zeroVal,         // typndims
 		typColl(typ, h), // typcollation
 		tree.DNull,      // typdefaultbin
-		tree.DNull,      // typdefault
+		typDefault,      // typdefault
 		tree.DNull,      // typacl
 	)
 }
@@ -3574,12 +3626,32 @@ var pgCatalogStatUserFunctionsTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTable = virtualSchemaTable{
-	comment: "pg_publication was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublication,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `publications
+https://www.postgresql.org/docs/14/catalog-pg-publication.html`,
+	schema: vtable.PgCatalogPublication,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, false, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				for i := range db.DatabaseDesc().Publications {
+					pub := &db.DatabaseDesc().Publications[i]
+					if err := addRow(
+						h.PublicationOid(db.GetID(), pub.Name),    // oid
+						tree.NewDName(pub.Name),                   // pubname
+						h.UserOid(pub.OwnerProto.Decode()),        // pubowner
+						tree.MakeDBool(tree.DBool(pub.AllTables)), // puballtables
+						tree.DBoolTrue,                            // pubinsert
+						tree.DBoolTrue,                            // pubupdate
+						tree.DBoolTrue,                            // pubdelete
+						tree.DBoolFalse,                           // pubtruncate
+						tree.DBoolFalse,                           // pubviaroot
+					); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogAmprocTable = virtualSchemaTable{
@@ -3734,12 +3806,23 @@ var pgCatalogStatXactUserTablesTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTablesTable = virtualSchemaTable{
-	comment: "pg_publication_tables was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationTables,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `published tables
+https://www.postgresql.org/docs/14/view-pg-publication-tables.html`,
+	schema: vtable.PgCatalogPublicationTables,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachPublishedTable(ctx, p, dbContext, func(
+			db catalog.DatabaseDescriptor,
+			pub *descpb.DatabaseDescriptor_Publication,
+			scName string,
+			table catalog.TableDescriptor,
+		) error {
+			return addRow(
+				tree.NewDName(pub.Name),        // pubname
+				tree.NewDName(scName),          // schemaname
+				tree.NewDName(table.GetName()), // tablename
+			)
+		})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogStatProgressClusterTable = virtualSchemaTable{
@@ -4013,12 +4096,54 @@ var pgCatalogStatReplicationTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationRelTable = virtualSchemaTable{
-	comment: "pg_publication_rel was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationRel,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `relations included in publications
+https://www.postgresql.org/docs/14/catalog-pg-publication-rel.html`,
+	schema: vtable.PgCatalogPublicationRel,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachPublishedTable(ctx, p, dbContext, func(
+			db catalog.DatabaseDescriptor,
+			pub *descpb.DatabaseDescriptor_Publication,
+			_ string,
+			table catalog.TableDescriptor,
+		) error {
+			if pub.AllTables {
+				// Postgres only lists relations which were explicitly added to
+				// the publication.
+				return nil
+			}
+			pubOid := h.PublicationOid(db.GetID(), pub.Name)
+			return addRow(
+				h.PublicationRelOid(pubOid, table.GetID()), // oid
+				pubOid,                  // prpubid
+				tableOid(table.GetID()), // prrelid
+			)
+		})
 	},
-	unimplemented: true,
+}
+
+// forEachPublishedTable calls fn for every table which is currently included
+// in a publication of one of the databases in scope.
+func forEachPublishedTable(
+	ctx context.Context,
+	p *planner,
+	dbContext catalog.DatabaseDescriptor,
+	fn func(catalog.DatabaseDescriptor, *descpb.DatabaseDescriptor_Publication, string, catalog.TableDescriptor) error,
+) error {
+	return forEachTableDesc(ctx, p, dbContext, hideVirtual, func(
+		db catalog.DatabaseDescriptor, scName string, table catalog.TableDescriptor,
+	) error {
+		for i := range db.DatabaseDesc().Publications {
+			pub := &db.DatabaseDesc().Publications[i]
+			if !publicationIncludesTable(pub, table) {
+				continue
+			}
+			if err := fn(db, pub, scName, table); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 var pgCatalogAvailableExtensionVersionsTable = virtualSchemaTable{
@@ -4034,7 +4159,7 @@ var pgCatalogAvailableExtensionVersionsTable = virtualSchemaTable{
 // object identifiers for types are not arbitrary, but instead need to be kept in
 // sync with Postgres.
 func typOid(typ *types.T) tree.Datum {
-	return tree.NewDOid(typ.Oid())
+	return tree.NewDOid(typ.UserDefinedOID())
 }
 
 func typLen(typ *types.T) *tree.DInt {
@@ -4245,16 +4370,15 @@ func init() {
 // are 32 bits and that they are stable across accesses.
 //
 // The type has a few layers of methods:
-// - write<go_type> methods write concrete types to the underlying running hash.
-// - write<db_object> methods account for single database objects like TableDescriptors
-//   or IndexDescriptors in the running hash. These methods aim to write information
-//   that would uniquely fingerprint the object to the hash using the first layer of
-//   methods.
-// - <DB_Object>Oid methods use the second layer of methods to construct a unique
-//   object identifier for the provided database object. This object identifier will
-//   be returned as a *tree.DInt, and the running hash will be reset. These are the
-//   only methods that are part of the oidHasher's external facing interface.
-//
+//   - write<go_type> methods write concrete types to the underlying running hash.
+//   - write<db_object> methods account for single database objects like TableDescriptors
+//     or IndexDescriptors in the running hash. These methods aim to write information
+//     that would uniquely fingerprint the object to the hash using the first layer of
+//     methods.
+//   - <DB_Object>Oid methods use the second layer of methods to construct a unique
+//     object identifier for the provided database object. This object identifier will
+//     be returned as a *tree.DInt, and the running hash will be reset. These are the
+//     only methods that are part of the oidHasher's external facing interface.
 type oidHasher struct {
 	h hash.Hash32
 }
@@ -4316,6 +4440,9 @@ const (
 	rewriteTypeTag
 	dbSchemaRoleTypeTag
 	castTypeTag
+	publicationTypeTag
+	publicationRelTypeTag
+	exclusionConstraintTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -4435,6 +4562,17 @@ func (h oidHasher) UniqueConstraintOid(
 	return h.getOid()
 }
 
+func (h oidHasher) ExclusionConstraintOid(
+	dbID descpb.ID, scName string, tableID descpb.ID, indexID descpb.IndexID,
+) *tree.DOid {
+	h.writeTypeTag(exclusionConstraintTypeTag)
+	h.writeDB(dbID)
+	h.writeSchema(scName)
+	h.writeTable(tableID)
+	h.writeIndex(indexID)
+	return h.getOid()
+}
+
 func (h oidHasher) BuiltinOid(name string, builtin *tree.Overload) *tree.DOid {
 	h.writeTypeTag(functionTypeTag)
 	h.writeStr(name)
@@ -4498,6 +4636,24 @@ func (h oidHasher) DBSchemaRoleOid(
 	return h.getOid()
 }
 
+// PublicationOid creates an OID for the publication with the given name in
+// the given database.
+func (h oidHasher) PublicationOid(dbID descpb.ID, name string) *tree.DOid {
+	h.writeTypeTag(publicationTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(name)
+	return h.getOid()
+}
+
+// PublicationRelOid creates an OID for the membership of a table in a
+// publication.
+func (h oidHasher) PublicationRelOid(pubOid *tree.DOid, tableID descpb.ID) *tree.DOid {
+	h.writeTypeTag(publicationRelTypeTag)
+	h.writeOID(pubOid)
+	h.writeUInt32(uint32(tableID))
+	return h.getOid()
+}
+
 func tableOid(id descpb.ID) *tree.DOid {
 	return tree.NewDOid(oid.Oid(id))
 }
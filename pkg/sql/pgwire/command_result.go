@@ -247,6 +247,34 @@ func (r *commandResult) DisableBuffering() {
 	r.bufferingDisabled = true
 }
 
+// BufferedResultsLen is part of the sql.RestrictedCommandResult interface.
+func (r *commandResult) BufferedResultsLen() int {
+	r.assertNotReleased()
+	return r.conn.writerState.buf.Len()
+}
+
+// TruncateBufferedResults is part of the sql.RestrictedCommandResult
+// interface.
+func (r *commandResult) TruncateBufferedResults(idx int) bool {
+	r.assertNotReleased()
+	if r.conn.writerState.fi.lastFlushed >= r.pos {
+		// Some of the results of this command have already been sent.
+		return false
+	}
+	if idx < 0 || idx > r.conn.writerState.buf.Len() {
+		return false
+	}
+	// Forget about the start of this command if it was registered after the
+	// truncation point, in the same way as clientConnLock.RTrim.
+	cmdStarts := &r.conn.writerState.fi.cmdStarts
+	for !cmdStarts.empty() && cmdStarts.getLast().idx > idx {
+		cmdStarts.removeLast()
+	}
+	r.conn.writerState.buf.Truncate(idx)
+	r.rowsAffected = 0
+	return true
+}
+
 // BufferParamStatusUpdate is part of the sql.RestrictedCommandResult interface.
 func (r *commandResult) BufferParamStatusUpdate(param string, val string) {
 	r.buffer.paramStatusUpdates = append(
@@ -104,6 +104,11 @@ type extendedEvalContext struct {
 
 	SchemaChangerState *SchemaChangerState
 
+	// DeferredConstraints is the state of deferrable constraint checks in the
+	// current transaction. It is nil if checks cannot be deferred, in which
+	// case they are always run immediately.
+	DeferredConstraints *DeferredConstraintState
+
 	statementPreparer statementPreparer
 }
 
@@ -350,7 +355,7 @@ func newInternalPlanner(
 	p.isInternalPlanner = true
 
 	p.semaCtx = tree.MakeSemaContext()
-	p.semaCtx.SearchPath = &sd.SearchPath
+	p.semaCtx.SearchPath = makeFunctionResolver(&sd.SearchPath, p)
 	p.semaCtx.TypeResolver = p
 	p.semaCtx.DateStyle = sd.GetDateStyle()
 	p.semaCtx.IntervalStyle = sd.GetIntervalStyle()
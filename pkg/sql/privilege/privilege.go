@@ -74,6 +74,8 @@ const (
 	Type ObjectType = "type"
 	// Sequence represents a sequence object.
 	Sequence ObjectType = "sequence"
+	// Function represents a function object.
+	Function ObjectType = "function"
 )
 
 // Predefined sets of privileges.
@@ -85,6 +87,9 @@ var (
 	TablePrivileges  = List{ALL, CREATE, DROP, SELECT, INSERT, DELETE, UPDATE, ZONECONFIG}
 	SchemaPrivileges = List{ALL, CREATE, USAGE}
 	TypePrivileges   = List{ALL, USAGE}
+	// FunctionPrivileges only contains ALL until EXECUTE is supported;
+	// functions can be called by anyone who can resolve them.
+	FunctionPrivileges = List{ALL}
 	// SequencePrivileges is appended with TablePrivileges as well. This is because
 	// before v22.2 we treated Sequences the same as Tables. This is to avoid making
 	// certain privileges unavailable after upgrade migration.
@@ -278,6 +283,8 @@ func GetValidPrivilegesForObject(objectType ObjectType) List {
 		return TypePrivileges
 	case Sequence:
 		return SequencePrivileges
+	case Function:
+		return FunctionPrivileges
 	case Any:
 		return AllPrivileges
 	default:
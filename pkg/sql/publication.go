@@ -0,0 +1,238 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+type createPublicationNode struct {
+	n      *tree.CreatePublication
+	dbDesc *dbdesc.Mutable
+}
+
+// CreatePublication creates a publication in the current database.
+// Privileges: admin.
+//   Notes: postgres requires the CREATE privilege on the database and
+//          ownership of the published tables.
+func (p *planner) CreatePublication(
+	ctx context.Context, n *tree.CreatePublication,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(ctx, p.ExecCfg(), "CREATE PUBLICATION"); err != nil {
+		return nil, err
+	}
+	if err := p.RequireAdminRole(ctx, "CREATE PUBLICATION"); err != nil {
+		return nil, err
+	}
+	dbDesc, err := p.Descriptors().GetMutableDatabaseByName(
+		ctx, p.txn, p.CurrentDatabase(), tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &createPublicationNode{n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *createPublicationNode) startExec(params runParams) error {
+	if findPublication(n.dbDesc, string(n.n.Name)) != nil {
+		return pgerror.Newf(pgcode.DuplicateObject, "publication %q already exists", n.n.Name)
+	}
+
+	pub := descpb.DatabaseDescriptor_Publication{
+		Name:       string(n.n.Name),
+		OwnerProto: params.p.User().EncodeProto(),
+		AllTables:  n.n.AllTables,
+	}
+	seen := make(map[descpb.ID]struct{}, len(n.n.Tables))
+	for i := range n.n.Tables {
+		tn := &n.n.Tables[i]
+		_, table, err := resolver.ResolveExistingTableObject(
+			params.ctx, params.p, tn, tree.ObjectLookupFlagsWithRequiredTableKind(tree.ResolveRequireTableDesc),
+		)
+		if err != nil {
+			return err
+		}
+		if table.GetParentID() != n.dbDesc.GetID() {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"cannot add table %q from another database to publication %q", tn.Table(), n.n.Name)
+		}
+		if table.IsTemporary() {
+			return pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot add temporary table %q to publication %q", tn.Table(), n.n.Name)
+		}
+		if _, ok := seen[table.GetID()]; ok {
+			continue
+		}
+		seen[table.GetID()] = struct{}{}
+		pub.TableIDs = append(pub.TableIDs, table.GetID())
+	}
+
+	n.dbDesc.Publications = append(n.dbDesc.Publications, pub)
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (*createPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (*createPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createPublicationNode) Close(context.Context)        {}
+
+type dropPublicationNode struct {
+	n      *tree.DropPublication
+	dbDesc *dbdesc.Mutable
+}
+
+// DropPublication drops publications from the current database.
+// Privileges: admin.
+func (p *planner) DropPublication(ctx context.Context, n *tree.DropPublication) (planNode, error) {
+	if err := checkSchemaChangeEnabled(ctx, p.ExecCfg(), "DROP PUBLICATION"); err != nil {
+		return nil, err
+	}
+	if err := p.RequireAdminRole(ctx, "DROP PUBLICATION"); err != nil {
+		return nil, err
+	}
+	dbDesc, err := p.Descriptors().GetMutableDatabaseByName(
+		ctx, p.txn, p.CurrentDatabase(), tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &dropPublicationNode{n: n, dbDesc: dbDesc}, nil
+}
+
+func (n *dropPublicationNode) startExec(params runParams) error {
+	dropped := false
+	for _, name := range n.n.Names {
+		pubs := n.dbDesc.Publications
+		idx := -1
+		for i := range pubs {
+			if pubs[i].Name == string(name) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			if !n.n.IfExists {
+				return pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", name)
+			}
+			params.p.BufferClientNotice(params.ctx, pgnotice.Newf(
+				"publication %q does not exist, skipping", name,
+			))
+			continue
+		}
+		n.dbDesc.Publications = append(pubs[:idx:idx], pubs[idx+1:]...)
+		dropped = true
+	}
+	if !dropped {
+		return nil
+	}
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (*dropPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropPublicationNode) Close(context.Context)        {}
+
+// findPublication returns the publication with the given name in the given
+// database, or nil if there is none.
+func findPublication(
+	db catalog.DatabaseDescriptor, name string,
+) *descpb.DatabaseDescriptor_Publication {
+	pubs := db.DatabaseDesc().Publications
+	for i := range pubs {
+		if pubs[i].Name == name {
+			return &pubs[i]
+		}
+	}
+	return nil
+}
+
+// publicationIncludesTable returns whether the given table is currently
+// published by pub.
+func publicationIncludesTable(
+	pub *descpb.DatabaseDescriptor_Publication, table catalog.TableDescriptor,
+) bool {
+	if !table.IsTable() || table.IsVirtualTable() || table.IsTemporary() || table.Dropped() {
+		return false
+	}
+	if pub.AllTables {
+		return true
+	}
+	for _, id := range pub.TableIDs {
+		if id == table.GetID() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPublicationTables returns the database with the given name and the
+// descriptors of the tables which are currently included in its publication
+// with the given name. Tables which have been dropped since they were added to
+// the publication are omitted.
+func GetPublicationTables(
+	ctx context.Context, txn *kv.Txn, descsCol *descs.Collection, dbName string, pubName string,
+) (catalog.DatabaseDescriptor, []catalog.TableDescriptor, error) {
+	db, err := descsCol.GetImmutableDatabaseByName(
+		ctx, txn, dbName, tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub := findPublication(db, pubName)
+	if pub == nil {
+		return nil, nil, pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", pubName)
+	}
+
+	var candidates []catalog.TableDescriptor
+	if pub.AllTables {
+		candidates, err = descsCol.GetAllTableDescriptorsInDatabase(ctx, txn, db.GetID())
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		flags := tree.ObjectLookupFlags{
+			CommonLookupFlags: tree.CommonLookupFlags{Required: true, IncludeDropped: true},
+		}
+		for _, id := range pub.TableIDs {
+			table, err := descsCol.GetImmutableTableByID(ctx, txn, id, flags)
+			if err != nil {
+				if errors.Is(err, catalog.ErrDescriptorNotFound) {
+					continue
+				}
+				return nil, nil, err
+			}
+			candidates = append(candidates, table)
+		}
+	}
+
+	tables := make([]catalog.TableDescriptor, 0, len(candidates))
+	for _, table := range candidates {
+		if publicationIncludesTable(pub, table) {
+			tables = append(tables, table)
+		}
+	}
+	return db, tables, nil
+}
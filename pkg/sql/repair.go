@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descbuilder"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemadesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -165,13 +166,19 @@ func (p *planner) UnsafeUpsertDescriptor(
 			sc.Version = newVersion
 			objectType = privilege.Schema
 		}
+		//nolint:descriptormarshal
+		if fn := desc.GetFunction(); fn != nil {
+			fn.ID = newID
+			fn.Version = newVersion
+			objectType = privilege.Function
+		}
 	}
 	if objectType == privilege.Any {
 		return pgerror.Newf(pgcode.InvalidObjectDefinition, "invalid new descriptor %+v", desc)
 	}
 
 	// Update the mutable descriptor with the new proto.
-	tbl, db, typ, schema := descpb.FromDescriptorWithMVCCTimestamp(&desc, newModTime)
+	tbl, db, typ, schema, fn := descpb.FromDescriptorWithMVCCTimestamp(&desc, newModTime)
 	switch md := mut.(type) {
 	case *tabledesc.Mutable:
 		if objectType != privilege.Table {
@@ -193,6 +200,11 @@ func (p *planner) UnsafeUpsertDescriptor(
 			return pgerror.Newf(pgcode.InvalidObjectDefinition, "cannot replace type descriptor with %s", objectType)
 		}
 		md.TypeDescriptor = *typ
+	case *funcdesc.Mutable:
+		if objectType != privilege.Function {
+			return pgerror.Newf(pgcode.InvalidObjectDefinition, "cannot replace function descriptor with %s", objectType)
+		}
+		md.FunctionDescriptor = *fn
 	case nil:
 		b := descbuilder.NewBuilderWithMVCCTimestamp(&desc, newModTime)
 		if b == nil {
@@ -496,6 +508,9 @@ func (p *planner) UnsafeUpsertNamespaceEntry(
 			invalid = parentID == descpb.InvalidID || parentSchemaID != descpb.InvalidID
 		case catalog.DatabaseDescriptor:
 			invalid = parentID != descpb.InvalidID || parentSchemaID != descpb.InvalidID
+		case catalog.FunctionDescriptor:
+			return pgerror.Newf(pgcode.InvalidCatalogName,
+				"functions do not have namespace entries, cannot name function %d", descID)
 		default:
 			// The public schema does not have a descriptor.
 			if descID == keys.PublicSchemaID {
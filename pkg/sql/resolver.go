@@ -821,6 +821,7 @@ type internalLookupCtx struct {
 	tbIDs       []descpb.ID
 	typDescs    map[descpb.ID]catalog.TypeDescriptor
 	typIDs      []descpb.ID
+	fnDescs     map[descpb.ID]catalog.FunctionDescriptor
 }
 
 // GetSchemaName looks up a schema with the given id in the LookupContext.
@@ -885,6 +886,7 @@ func newInternalLookupCtx(
 
 	tbDescs := make(map[descpb.ID]catalog.TableDescriptor)
 	typDescs := make(map[descpb.ID]catalog.TypeDescriptor)
+	fnDescs := make(map[descpb.ID]catalog.FunctionDescriptor)
 	var tbIDs, typIDs, dbIDs, schemaIDs []descpb.ID
 
 	// Record descriptors for name lookups.
@@ -909,6 +911,8 @@ func newInternalLookupCtx(
 				// Only make the type visible for iteration if the prefix was included.
 				typIDs = append(typIDs, desc.GetID())
 			}
+		case catalog.FunctionDescriptor:
+			fnDescs[desc.GetID()] = desc
 		case catalog.SchemaDescriptor:
 			schemaDescs[desc.GetID()] = desc
 			if prefix == nil || prefix.GetID() == desc.GetParentID() {
@@ -930,6 +934,7 @@ func newInternalLookupCtx(
 		tbIDs:       tbIDs,
 		dbIDs:       dbIDs,
 		typIDs:      typIDs,
+		fnDescs:     fnDescs,
 	}
 }
 
@@ -959,6 +964,14 @@ func (l *internalLookupCtx) getTypeByID(id descpb.ID) (catalog.TypeDescriptor, e
 	return typ, nil
 }
 
+func (l *internalLookupCtx) getFunctionByID(id descpb.ID) (catalog.FunctionDescriptor, error) {
+	fn, ok := l.fnDescs[id]
+	if !ok {
+		return nil, pgerror.Newf(pgcode.UndefinedFunction, "function with ID %d does not exist", id)
+	}
+	return fn, nil
+}
+
 func (l *internalLookupCtx) getSchemaByID(id descpb.ID) (catalog.SchemaDescriptor, error) {
 	sc, ok := l.schemaDescs[id]
 	if !ok {
@@ -1187,4 +1200,5 @@ type simpleSchemaResolver interface {
 	getDatabaseByID(id descpb.ID) (catalog.DatabaseDescriptor, error)
 	getSchemaByID(id descpb.ID) (catalog.SchemaDescriptor, error)
 	getTableByID(id descpb.ID) (catalog.TableDescriptor, error)
+	getFunctionByID(id descpb.ID) (catalog.FunctionDescriptor, error)
 }
@@ -0,0 +1,163 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rowexec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/execstats"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// expandProcessor is the processor that implements GROUPING SETS, ROLLUP and
+// CUBE. It emits each input row once for every grouping set, followed by a
+// copy of the grouping columns (with the columns that are not part of the
+// grouping set replaced by NULLs) and by the ordinal of the grouping set.
+type expandProcessor struct {
+	execinfra.ProcessorBase
+
+	input execinfra.RowSource
+	spec  *execinfrapb.ExpandSpec
+
+	// inGroupingSet[i][j] is true if the j-th grouping column is part of the
+	// i-th grouping set.
+	inGroupingSet [][]bool
+	// setIDs contains the encoded ordinal of each grouping set.
+	setIDs   []rowenc.EncDatum
+	nullCols []rowenc.EncDatum
+
+	// inputRow is the input row currently being expanded, and nextSet is the
+	// ordinal of the next grouping set to emit for it.
+	inputRow rowenc.EncDatumRow
+	nextSet  int
+	outRow   rowenc.EncDatumRow
+}
+
+var _ execinfra.Processor = &expandProcessor{}
+var _ execinfra.RowSource = &expandProcessor{}
+
+const expandProcName = "expand"
+
+func newExpandProcessor(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec *execinfrapb.ExpandSpec,
+	input execinfra.RowSource,
+	post *execinfrapb.PostProcessSpec,
+	output execinfra.RowReceiver,
+) (execinfra.RowSourcedProcessor, error) {
+	ctx := flowCtx.EvalCtx.Ctx()
+	e := &expandProcessor{
+		input:         input,
+		spec:          spec,
+		inGroupingSet: make([][]bool, len(spec.GroupingSets)),
+		setIDs:        make([]rowenc.EncDatum, len(spec.GroupingSets)),
+		nullCols:      make([]rowenc.EncDatum, len(spec.GroupingColumns)),
+	}
+
+	inputTypes := input.OutputTypes()
+	colTypes := make([]*types.T, 0, len(inputTypes)+len(spec.GroupingColumns)+1)
+	colTypes = append(colTypes, inputTypes...)
+	for i, col := range spec.GroupingColumns {
+		colTypes = append(colTypes, inputTypes[col])
+		e.nullCols[i] = rowenc.DatumToEncDatum(inputTypes[col], tree.DNull)
+	}
+	colTypes = append(colTypes, types.Int)
+	for i, set := range spec.GroupingSets {
+		e.inGroupingSet[i] = make([]bool, len(spec.GroupingColumns))
+		for _, idx := range set.Columns {
+			e.inGroupingSet[i][idx] = true
+		}
+		e.setIDs[i] = rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(i)))
+	}
+	e.outRow = make(rowenc.EncDatumRow, len(colTypes))
+
+	if err := e.Init(
+		e,
+		post,
+		colTypes,
+		flowCtx,
+		processorID,
+		output,
+		nil, /* memMonitor */
+		execinfra.ProcStateOpts{
+			InputsToDrain: []execinfra.RowSource{e.input},
+		},
+	); err != nil {
+		return nil, err
+	}
+
+	if execstats.ShouldCollectStats(ctx, flowCtx.CollectStats) {
+		e.input = newInputStatCollector(e.input)
+		e.ExecStatsForTrace = e.execStatsForTrace
+	}
+
+	return e, nil
+}
+
+// Start is part of the RowSource interface.
+func (e *expandProcessor) Start(ctx context.Context) {
+	ctx = e.StartInternal(ctx, expandProcName)
+	e.input.Start(ctx)
+}
+
+// Next is part of the RowSource interface.
+func (e *expandProcessor) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	for e.State == execinfra.StateRunning {
+		if e.inputRow == nil || e.nextSet == len(e.spec.GroupingSets) {
+			row, meta := e.input.Next()
+			if meta != nil {
+				if meta.Err != nil {
+					e.MoveToDraining(nil /* err */)
+				}
+				return nil, meta
+			}
+			if row == nil {
+				e.MoveToDraining(nil /* err */)
+				break
+			}
+			e.inputRow = row
+			e.nextSet = 0
+		}
+
+		set := e.nextSet
+		e.nextSet++
+		n := copy(e.outRow, e.inputRow)
+		for i, col := range e.spec.GroupingColumns {
+			if e.inGroupingSet[set][i] {
+				e.outRow[n+i] = e.inputRow[col]
+			} else {
+				e.outRow[n+i] = e.nullCols[i]
+			}
+		}
+		e.outRow[len(e.outRow)-1] = e.setIDs[set]
+		if outRow := e.ProcessRowHelper(e.outRow); outRow != nil {
+			return outRow, nil
+		}
+	}
+	return nil, e.DrainHelper()
+}
+
+// execStatsForTrace implements ProcessorBase.ExecStatsForTrace.
+func (e *expandProcessor) execStatsForTrace() *execinfrapb.ComponentStats {
+	is, ok := getInputStats(e.input)
+	if !ok {
+		return nil
+	}
+	return &execinfrapb.ComponentStats{
+		Inputs: []execinfrapb.InputStats{is},
+		Output: e.OutputHelper.Stats(),
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rowexec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/testutils/distsqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+)
+
+func TestExpand(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	v := [15]rowenc.EncDatum{}
+	for i := range v {
+		v[i] = rowenc.DatumToEncDatum(types.Int, tree.NewDInt(tree.DInt(i)))
+	}
+	null := rowenc.DatumToEncDatum(types.Int, tree.DNull)
+
+	testCases := []struct {
+		spec     execinfrapb.ExpandSpec
+		input    rowenc.EncDatumRows
+		expected rowenc.EncDatumRows
+	}{
+		{
+			// ROLLUP (@1, @2).
+			spec: execinfrapb.ExpandSpec{
+				GroupingColumns: []uint32{0, 1},
+				GroupingSets: []execinfrapb.ExpandSpec_GroupingSet{
+					{Columns: []uint32{0, 1}},
+					{Columns: []uint32{0}},
+					{},
+				},
+			},
+			input: rowenc.EncDatumRows{
+				{v[1], v[2]},
+				{v[3], v[4]},
+			},
+			expected: rowenc.EncDatumRows{
+				{v[1], v[2], v[1], v[2], v[0]},
+				{v[1], v[2], v[1], null, v[1]},
+				{v[1], v[2], null, null, v[2]},
+				{v[3], v[4], v[3], v[4], v[0]},
+				{v[3], v[4], v[3], null, v[1]},
+				{v[3], v[4], null, null, v[2]},
+			},
+		},
+		{
+			// GROUPING SETS ((@2), (@1)), with the grouping columns in a
+			// different order than the input columns.
+			spec: execinfrapb.ExpandSpec{
+				GroupingColumns: []uint32{1, 0},
+				GroupingSets: []execinfrapb.ExpandSpec_GroupingSet{
+					{Columns: []uint32{0}},
+					{Columns: []uint32{1}},
+				},
+			},
+			input: rowenc.EncDatumRows{
+				{v[5], v[6]},
+				{null, v[7]},
+			},
+			expected: rowenc.EncDatumRows{
+				{v[5], v[6], v[6], null, v[0]},
+				{v[5], v[6], null, v[5], v[1]},
+				{null, v[7], v[7], null, v[0]},
+				{null, v[7], null, null, v[1]},
+			},
+		},
+		{
+			spec: execinfrapb.ExpandSpec{
+				GroupingColumns: []uint32{0},
+				GroupingSets: []execinfrapb.ExpandSpec_GroupingSet{
+					{Columns: []uint32{0}},
+				},
+			},
+			input:    rowenc.EncDatumRows{},
+			expected: nil,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run("", func(t *testing.T) {
+			spec := c.spec
+
+			in := distsqlutils.NewRowBuffer(types.TwoIntCols, c.input, distsqlutils.RowBufferArgs{})
+			out := &distsqlutils.RowBuffer{}
+
+			st := cluster.MakeTestingClusterSettings()
+			evalCtx := eval.MakeTestingEvalContext(st)
+			defer evalCtx.Stop(context.Background())
+			flowCtx := execinfra.FlowCtx{
+				Cfg:     &execinfra.ServerConfig{Settings: st},
+				EvalCtx: &evalCtx,
+			}
+
+			e, err := newExpandProcessor(&flowCtx, 0 /* processorID */, &spec, in, &execinfrapb.PostProcessSpec{}, out)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			e.Run(context.Background())
+			if !out.ProducerClosed() {
+				t.Fatalf("output RowReceiver not closed")
+			}
+			var res rowenc.EncDatumRows
+			for {
+				row := out.NextNoMeta(t).Copy()
+				if row == nil {
+					break
+				}
+				res = append(res, row)
+			}
+
+			typs := make([]*types.T, 2+len(spec.GroupingColumns)+1)
+			for i := range typs {
+				typs[i] = types.Int
+			}
+			if result := res.String(typs); result != c.expected.String(typs) {
+				t.Errorf("invalid results: %s, expected %s", result, c.expected.String(typs))
+			}
+		})
+	}
+}
@@ -171,6 +171,12 @@ func NewProcessor(
 		}
 		return newOrdinalityProcessor(flowCtx, processorID, core.Ordinality, inputs[0], post, outputs[0])
 	}
+	if core.Expand != nil {
+		if err := checkNumInOut(inputs, outputs, 1, 1); err != nil {
+			return nil, err
+		}
+		return newExpandProcessor(flowCtx, processorID, core.Expand, inputs[0], post, outputs[0])
+	}
 	if core.Aggregator != nil {
 		if err := checkNumInOut(inputs, outputs, 1, 1); err != nil {
 			return nil, err
@@ -587,6 +587,8 @@ func (b *builderState) ResolveEnumType(
 	case descpb.TypeDescriptor_ENUM:
 		b.ensureDescriptor(typ.GetID())
 		b.mustOwn(typ.GetID())
+	case descpb.TypeDescriptor_DOMAIN:
+		panic(scerrors.NotImplementedErrorf(nil /* n */, "domain type %q", typ.GetName()))
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		// Implicit record types are not directly modifiable.
 		panic(pgerror.Newf(pgcode.DependentObjectsStillExist,
@@ -845,6 +847,19 @@ func (b *builderState) ensureDescriptor(id catid.DescID) {
 		ers:             &elementResultSet{b: b},
 		elementIndexMap: map[string]int{},
 	}
+	if c.desc.DescriptorType() == catalog.Function {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"function %q (%d)", c.desc.GetName(), c.desc.GetID()))
+	}
+	if tbl, ok := c.desc.(catalog.TableDescriptor); ok && len(tbl.GetTriggers()) > 0 {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"table %q (%d) with triggers", c.desc.GetName(), c.desc.GetID()))
+	}
+	if tbl, ok := c.desc.(catalog.TableDescriptor); ok &&
+		catalog.FindNonDropIndex(tbl, catalog.Index.IsExclusion) != nil {
+		panic(scerrors.NotImplementedErrorf(nil, /* n */
+			"table %q (%d) with EXCLUDE constraints", c.desc.GetName(), c.desc.GetID()))
+	}
 	// Collect privileges
 	if !c.hasOwnership {
 		var err error
@@ -903,6 +918,12 @@ func (b *builderState) ensureDescriptor(id catid.DescID) {
 		for _, objectID := range objectIDs {
 			c.backrefs.Add(objectID)
 		}
+		// User-defined functions aren't named in the namespace table either,
+		// they are referenced by the schema's functions mapping.
+		_ = d.ForEachFunctionOverload(func(overload descpb.SchemaDescriptor_FunctionOverload) error {
+			c.backrefs.Add(overload.ID)
+			return nil
+		})
 	default:
 		b.ensureDescriptor(c.desc.GetParentID())
 		db := b.descCache[c.desc.GetParentID()].desc
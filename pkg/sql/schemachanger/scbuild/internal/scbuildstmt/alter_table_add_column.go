@@ -78,6 +78,9 @@ func alterTableAddColumn(
 				"UNIQUE WITHOUT INDEX constraint on the column",
 		))
 	}
+	if d.Unique.Deferrability.IsDeferrable() {
+		panic(scerrors.NotImplementedErrorf(d, "contains deferrable unique constraint"))
+	}
 	if d.PrimaryKey.IsPrimaryKey {
 		publicTargets := b.QueryByID(tbl.TableID).Filter(
 			func(_ scpb.Status, target scpb.TargetStatus, _ scpb.Element) bool {
@@ -135,7 +138,7 @@ func alterTableAddColumn(
 
 	spec.colType.TypeT = b.ResolveTypeRef(d.Type)
 	if spec.colType.TypeT.Type.UserDefined() {
-		typeID, err := typedesc.UserDefinedTypeOIDToID(spec.colType.TypeT.Type.Oid())
+		typeID, err := typedesc.UserDefinedTypeOIDToID(spec.colType.TypeT.Type.UserDefinedOID())
 		if err != nil {
 			panic(err)
 		}
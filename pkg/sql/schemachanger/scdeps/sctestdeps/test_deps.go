@@ -563,7 +563,7 @@ func (s *TestState) mustReadMutableDescriptor(id descpb.ID) (catalog.MutableDesc
 	}
 	mut := c.NewBuilder().BuildExistingMutable()
 	pb := u.NewBuilder().BuildImmutable().DescriptorProto()
-	tbl, db, typ, sc := descpb.FromDescriptorWithMVCCTimestamp(pb, s.mvccTimestamp())
+	tbl, db, typ, sc, _ := descpb.FromDescriptorWithMVCCTimestamp(pb, s.mvccTimestamp())
 	switch m := mut.(type) {
 	case *tabledesc.Mutable:
 		m.TableDescriptor = *tbl
@@ -5085,6 +5085,54 @@ value if you rely on the HLC for accuracy.`,
 		},
 	),
 
+	"crdb_internal.domain_not_null": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categorySystemInfo,
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"value", types.Any}, {"domain", types.String}},
+			ReturnType: tree.IdentityReturnType(0),
+			Fn: func(ctx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				if args[0] == tree.DNull {
+					return nil, pgerror.Newf(pgcode.NotNullViolation,
+						"domain %s does not allow null values", tree.MustBeDString(args[1]))
+				}
+				return args[0], nil
+			},
+			Info:       "This function is used internally to enforce the NOT NULL constraint of a domain.",
+			Volatility: volatility.Immutable,
+		},
+	),
+
+	"crdb_internal.domain_check": makeBuiltin(
+		tree.FunctionProperties{
+			Category:     categorySystemInfo,
+			NullableArgs: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"value", types.Any},
+				{"ok", types.Bool},
+				{"domain", types.String},
+				{"constraint", types.String},
+			},
+			ReturnType: tree.IdentityReturnType(0),
+			Fn: func(ctx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				// Like table CHECK constraints, a domain CHECK constraint is
+				// only violated if it evaluates to false.
+				if ok, isBool := args[1].(*tree.DBool); isBool && !bool(*ok) {
+					return nil, pgerror.Newf(pgcode.CheckViolation,
+						"value for domain %s violates check constraint %q",
+						tree.MustBeDString(args[2]), tree.MustBeDString(args[3]))
+				}
+				return args[0], nil
+			},
+			Info:       "This function is used internally to enforce a CHECK constraint of a domain.",
+			Volatility: volatility.Immutable,
+		},
+	),
+
 	"crdb_internal.notice": makeBuiltin(
 		tree.FunctionProperties{
 			Category: categorySystemInfo,
@@ -147,6 +147,36 @@ var replicationBuiltins = map[string]builtinDefinition{
 		},
 	),
 
+	"crdb_internal.start_replication_stream_for_publication": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categoryStreamIngestion,
+			DistsqlBlocklist: true,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"publication", types.String},
+			},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(evalCtx *eval.Context, args tree.Datums) (tree.Datum, error) {
+				mgr, err := streaming.GetReplicationStreamManager(evalCtx)
+				if err != nil {
+					return nil, err
+				}
+				publication := string(tree.MustBeDString(args[0]))
+				jobID, err := mgr.StartReplicationStreamForPublication(evalCtx, evalCtx.Txn, publication)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDInt(tree.DInt(jobID)), err
+			},
+			Info: "This function can be used on the producer side to start a replication stream for " +
+				"the tables of the specified publication in the current database. The returned stream " +
+				"ID uniquely identifies created stream. The caller must periodically invoke " +
+				"crdb_internal.heartbeat_stream() function to notify that the replication is still ongoing.",
+			Volatility: volatility.Volatile,
+		},
+	),
+
 	"crdb_internal.replication_stream_progress": makeBuiltin(
 		tree.FunctionProperties{
 			Category:         categoryStreamIngestion,
@@ -30,6 +30,17 @@ func TypeIDToOID(id DescID) oid.Oid {
 	return oid.Oid(id) + oidext.CockroachPredefinedOIDMax
 }
 
+// FuncIDToOID converts a function descriptor ID into a function OID.
+func FuncIDToOID(id DescID) oid.Oid {
+	return oid.Oid(id) + oidext.CockroachPredefinedOIDMax
+}
+
+// FuncOIDToID converts a function OID into a function descriptor ID. It is
+// the inverse of FuncIDToOID.
+func FuncOIDToID(o oid.Oid) DescID {
+	return DescID(o - oidext.CockroachPredefinedOIDMax)
+}
+
 // ColumnID is a custom type for Column IDs.
 type ColumnID uint32
 
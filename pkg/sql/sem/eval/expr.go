@@ -406,6 +406,13 @@ func (e *evaluator) EvalNullIfExpr(expr *tree.NullIfExpr) (tree.Datum, error) {
 
 func (e *evaluator) EvalFuncExpr(expr *tree.FuncExpr) (tree.Datum, error) {
 	fn := expr.ResolvedOverload()
+	if fn.IsUDF {
+		// User-defined functions are inlined by the optimizer, and cannot be
+		// evaluated directly.
+		return nil, errors.AssertionFailedf(
+			"cannot evaluate user-defined function %s", expr.Func.String(),
+		)
+	}
 	if fn.FnWithExprs != nil {
 		return fn.FnWithExprs.(FnWithExprsOverload)(e.ctx(), expr.Exprs)
 	}
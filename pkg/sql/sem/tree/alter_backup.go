@@ -70,6 +70,30 @@ func (node *AlterBackupKMS) Format(ctx *FmtCtx) {
 	ctx.FormatNode(&node.KMSInfo.OldKMSURI)
 }
 
+func (node *AlterBackupCompact) alterBackupCmd() {}
+
+var _ AlterBackupCmd = &AlterBackupCompact{}
+
+// AlterBackupCompact represents a COMPACT alter_backup_cmd, which merges
+// incremental layers of a backup into a single layer.
+type AlterBackupCompact struct {
+	// Start and End optionally bound the end times of the incremental layers to
+	// be compacted. They are either both set or both nil.
+	Start Expr
+	End   Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterBackupCompact) Format(ctx *FmtCtx) {
+	ctx.WriteString(" COMPACT")
+	if node.Start != nil {
+		ctx.WriteString(" BETWEEN ")
+		ctx.FormatNode(node.Start)
+		ctx.WriteString(" AND ")
+		ctx.FormatNode(node.End)
+	}
+}
+
 // BackupKMS represents possible options used when altering a backup KMS
 type BackupKMS struct {
 	NewKMSURI StringOrPlaceholderOptList
@@ -133,6 +133,7 @@ type RestoreOptions struct {
 	NewDBName                 Expr
 	IncrementalStorage        StringOrPlaceholderOptList
 	AsTenant                  Expr
+	DroppedColumns            bool
 }
 
 var _ NodeFormatter = &RestoreOptions{}
@@ -159,6 +160,14 @@ type Restore struct {
 	// ... FROM 'from' IN 'subdir'...`. Alternatively, restore_planning.go will set
 	// it for the query `RESTORE ... FROM 'from' IN LATEST...`
 	Subdir Expr
+
+	// Where is set for `RESTORE TABLE ... WHERE <predicate> INTO <table>`, which
+	// restores the rows of a single table which satisfy the predicate into
+	// Into. Into is created by the RESTORE, unless UpsertInto is set, in which
+	// case the rows are upserted into the existing table Into.
+	Where      *Where
+	Into       *UnresolvedObjectName
+	UpsertInto bool
 }
 
 var _ Statement = &Restore{}
@@ -185,6 +194,15 @@ func (node *Restore) Format(ctx *FmtCtx) {
 		ctx.WriteString(" ")
 		ctx.FormatNode(&node.AsOf)
 	}
+	if node.Where != nil {
+		ctx.WriteString(" ")
+		ctx.FormatNode(node.Where)
+		if node.UpsertInto {
+			ctx.WriteString(" UPSERT")
+		}
+		ctx.WriteString(" INTO ")
+		ctx.FormatNode(node.Into)
+	}
 	if !node.Options.IsDefault() {
 		ctx.WriteString(" WITH ")
 		ctx.FormatNode(&node.Options)
@@ -404,6 +422,11 @@ func (o *RestoreOptions) Format(ctx *FmtCtx) {
 		ctx.WriteString("tenant = ")
 		ctx.FormatNode(o.AsTenant)
 	}
+
+	if o.DroppedColumns {
+		maybeAddSep()
+		ctx.WriteString("dropped_columns")
+	}
 }
 
 // CombineWith merges other backup options into this backup options struct.
@@ -499,6 +522,14 @@ func (o *RestoreOptions) CombineWith(other *RestoreOptions) error {
 		return errors.New("tenant option specified multiple times")
 	}
 
+	if o.DroppedColumns {
+		if other.DroppedColumns {
+			return errors.New("dropped_columns specified multiple times")
+		}
+	} else {
+		o.DroppedColumns = other.DroppedColumns
+	}
+
 	return nil
 }
 
@@ -517,5 +548,6 @@ func (o RestoreOptions) IsDefault() bool {
 		o.DebugPauseOn == options.DebugPauseOn &&
 		o.NewDBName == options.NewDBName &&
 		cmp.Equal(o.IncrementalStorage, options.IncrementalStorage) &&
-		o.AsTenant == options.AsTenant
+		o.AsTenant == options.AsTenant &&
+		o.DroppedColumns == options.DroppedColumns
 }
@@ -28,7 +28,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree/treecmp"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/pretty"
 	"github.com/cockroachdb/errors"
 	"golang.org/x/text/language"
@@ -362,6 +364,44 @@ func (node *CreateType) String() string {
 	return AsString(node)
 }
 
+// CreateDomain represents a CREATE DOMAIN statement.
+type CreateDomain struct {
+	TypeName *UnresolvedObjectName
+	Type     ResolvableTypeReference
+	// Constraints are the DEFAULT, NULL, NOT NULL and CHECK clauses of the
+	// domain, in the order in which they were specified.
+	Constraints []NamedColumnQualification
+}
+
+var _ Statement = &CreateDomain{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateDomain) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE DOMAIN ")
+	ctx.FormatNode(node.TypeName)
+	ctx.WriteString(" AS ")
+	ctx.FormatTypeReference(node.Type)
+	for _, c := range node.Constraints {
+		if c.Name != "" {
+			ctx.WriteString(" CONSTRAINT ")
+			ctx.FormatNode(&c.Name)
+		}
+		switch t := c.Qualification.(type) {
+		case *ColumnDefault:
+			ctx.WriteString(" DEFAULT ")
+			ctx.FormatNode(t.Expr)
+		case NotNullConstraint:
+			ctx.WriteString(" NOT NULL")
+		case NullConstraint:
+			ctx.WriteString(" NULL")
+		case *ColumnCheckConstraint:
+			ctx.WriteString(" CHECK (")
+			ctx.FormatNode(t.Expr)
+			ctx.WriteByte(')')
+		}
+	}
+}
+
 // TableDef represents a column, index or constraint definition within a CREATE
 // TABLE statement.
 type TableDef interface {
@@ -376,6 +416,7 @@ func (*IndexTableDef) tableDef()                {}
 func (*FamilyTableDef) tableDef()               {}
 func (*ForeignKeyConstraintTableDef) tableDef() {}
 func (*CheckConstraintTableDef) tableDef()      {}
+func (*ExclusionConstraintTableDef) tableDef()  {}
 func (*LikeTableDef) tableDef()                 {}
 
 // TableDefs represents a list of table definitions.
@@ -438,6 +479,7 @@ type ColumnTableDef struct {
 		IsUnique       bool
 		WithoutIndex   bool
 		ConstraintName Name
+		Deferrability  ConstraintDeferrability
 	}
 	DefaultExpr struct {
 		Expr           Expr
@@ -454,6 +496,7 @@ type ColumnTableDef struct {
 		ConstraintName Name
 		Actions        ReferenceActions
 		Match          CompositeKeyMatchMethod
+		Deferrability  ConstraintDeferrability
 	}
 	Computed struct {
 		Computed bool
@@ -515,7 +558,13 @@ func NewColumnTableDef(
 		IsSerial: isSerial,
 	}
 	d.Nullable.Nullability = SilentNull
+	// deferrability points to the deferrability of the last UNIQUE or
+	// REFERENCES qualification, if it was the previous qualification.
+	var deferrability *ConstraintDeferrability
+	var prevQualification ColumnQualification
 	for _, c := range qualifications {
+		prevDeferrability := deferrability
+		deferrability = nil
 		switch t := c.Qualification.(type) {
 		case ColumnCollation:
 			locale := string(t)
@@ -622,6 +671,22 @@ func NewColumnTableDef(
 			d.Unique.IsUnique = true
 			d.Unique.WithoutIndex = t.WithoutIndex
 			d.Unique.ConstraintName = c.Name
+			deferrability = &d.Unique.Deferrability
+		case ColumnConstraintDeferrability:
+			if prevDeferrability == nil {
+				switch prevQualification.(type) {
+				case PrimaryKeyConstraint, ShardedPrimaryKeyConstraint:
+					return nil, unimplemented.NewWithIssue(31632,
+						"deferrable primary key constraints are not supported")
+				}
+				return nil, pgerror.Newf(pgcode.Syntax,
+					"misplaced %s clause for column %q", t, name)
+			}
+			var err error
+			if *prevDeferrability, err = t.apply(*prevDeferrability); err != nil {
+				return nil, err
+			}
+			deferrability = prevDeferrability
 		case *ColumnCheckConstraint:
 			d.CheckExprs = append(d.CheckExprs, ColumnTableDefCheckExpr{
 				Expr:           t.Expr,
@@ -637,6 +702,7 @@ func NewColumnTableDef(
 			d.References.ConstraintName = c.Name
 			d.References.Actions = t.Actions
 			d.References.Match = t.Match
+			deferrability = &d.References.Deferrability
 		case *ColumnComputedDef:
 			if d.GeneratedIdentity.IsGeneratedAsIdentity {
 				return nil, pgerror.Newf(pgcode.Syntax,
@@ -656,6 +722,7 @@ func NewColumnTableDef(
 		default:
 			return nil, errors.AssertionFailedf("unexpected column qualification: %T", c)
 		}
+		prevQualification = c.Qualification
 	}
 
 	return d, nil
@@ -748,6 +815,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			if node.Unique.WithoutIndex {
 				ctx.WriteString(" WITHOUT INDEX")
 			}
+			ctx.FormatNode(&node.Unique.Deferrability)
 		}
 	}
 	if node.HasDefaultExpr() {
@@ -807,6 +875,7 @@ func (node *ColumnTableDef) Format(ctx *FmtCtx) {
 			ctx.WriteString(node.References.Match.String())
 		}
 		ctx.FormatNode(&node.References.Actions)
+		ctx.FormatNode(&node.References.Deferrability)
 	}
 	if node.IsComputed() {
 		ctx.WriteString(" AS (")
@@ -863,21 +932,22 @@ type ColumnQualification interface {
 	columnQualification()
 }
 
-func (ColumnCollation) columnQualification()             {}
-func (*ColumnDefault) columnQualification()              {}
-func (*ColumnOnUpdate) columnQualification()             {}
-func (NotNullConstraint) columnQualification()           {}
-func (NullConstraint) columnQualification()              {}
-func (HiddenConstraint) columnQualification()            {}
-func (PrimaryKeyConstraint) columnQualification()        {}
-func (ShardedPrimaryKeyConstraint) columnQualification() {}
-func (UniqueConstraint) columnQualification()            {}
-func (*ColumnCheckConstraint) columnQualification()      {}
-func (*ColumnComputedDef) columnQualification()          {}
-func (*ColumnFKConstraint) columnQualification()         {}
-func (*ColumnFamilyConstraint) columnQualification()     {}
-func (*GeneratedAlwaysAsIdentity) columnQualification()  {}
-func (*GeneratedByDefAsIdentity) columnQualification()   {}
+func (ColumnCollation) columnQualification()               {}
+func (*ColumnDefault) columnQualification()                {}
+func (*ColumnOnUpdate) columnQualification()               {}
+func (NotNullConstraint) columnQualification()             {}
+func (NullConstraint) columnQualification()                {}
+func (HiddenConstraint) columnQualification()              {}
+func (PrimaryKeyConstraint) columnQualification()          {}
+func (ShardedPrimaryKeyConstraint) columnQualification()   {}
+func (UniqueConstraint) columnQualification()              {}
+func (*ColumnCheckConstraint) columnQualification()        {}
+func (*ColumnComputedDef) columnQualification()            {}
+func (*ColumnFKConstraint) columnQualification()           {}
+func (*ColumnFamilyConstraint) columnQualification()       {}
+func (*GeneratedAlwaysAsIdentity) columnQualification()    {}
+func (*GeneratedByDefAsIdentity) columnQualification()     {}
+func (ColumnConstraintDeferrability) columnQualification() {}
 
 // ColumnCollation represents a COLLATE clause for a column.
 type ColumnCollation string
@@ -934,6 +1004,56 @@ type ColumnCheckConstraint struct {
 	Expr Expr
 }
 
+// ColumnConstraintDeferrability represents one of DEFERRABLE, NOT DEFERRABLE,
+// INITIALLY DEFERRED or INITIALLY IMMEDIATE on a column. It applies to the
+// UNIQUE or REFERENCES qualification that precedes it.
+type ColumnConstraintDeferrability struct {
+	// Initially is set for INITIALLY DEFERRED and INITIALLY IMMEDIATE.
+	Initially bool
+	// Deferred is set for DEFERRABLE and INITIALLY DEFERRED.
+	Deferred bool
+}
+
+func (c ColumnConstraintDeferrability) String() string {
+	switch {
+	case c.Initially && c.Deferred:
+		return "INITIALLY DEFERRED"
+	case c.Initially:
+		return "INITIALLY IMMEDIATE"
+	case c.Deferred:
+		return "DEFERRABLE"
+	default:
+		return "NOT DEFERRABLE"
+	}
+}
+
+// apply combines the attribute with the given deferrability, following the
+// Postgres rules: INITIALLY DEFERRED implies DEFERRABLE.
+func (c ColumnConstraintDeferrability) apply(
+	d ConstraintDeferrability,
+) (ConstraintDeferrability, error) {
+	switch {
+	case c.Initially && c.Deferred:
+		return ConstraintInitiallyDeferred, nil
+	case c.Initially:
+		if d == ConstraintInitiallyDeferred {
+			return ConstraintDeferrable, nil
+		}
+		return d, nil
+	case c.Deferred:
+		if d == ConstraintNotDeferrable {
+			return ConstraintDeferrable, nil
+		}
+		return d, nil
+	default:
+		if d == ConstraintInitiallyDeferred {
+			return d, pgerror.New(pgcode.InvalidTableDefinition,
+				"constraint declared INITIALLY DEFERRED must be DEFERRABLE")
+		}
+		return ConstraintNotDeferrable, nil
+	}
+}
+
 // ColumnFKConstraint represents a FK-constaint on a column.
 type ColumnFKConstraint struct {
 	Table   TableName
@@ -1022,14 +1142,16 @@ type ConstraintTableDef interface {
 func (*UniqueConstraintTableDef) constraintTableDef()     {}
 func (*ForeignKeyConstraintTableDef) constraintTableDef() {}
 func (*CheckConstraintTableDef) constraintTableDef()      {}
+func (*ExclusionConstraintTableDef) constraintTableDef()  {}
 
 // UniqueConstraintTableDef represents a unique constraint within a CREATE
 // TABLE statement.
 type UniqueConstraintTableDef struct {
 	IndexTableDef
-	PrimaryKey   bool
-	WithoutIndex bool
-	IfNotExists  bool
+	PrimaryKey    bool
+	WithoutIndex  bool
+	Deferrability ConstraintDeferrability
+	IfNotExists   bool
 }
 
 // SetName implements the TableDef interface.
@@ -1074,6 +1196,7 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionByIndex != nil {
 		ctx.FormatNode(node.PartitionByIndex)
 	}
+	ctx.FormatNode(&node.Deferrability)
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
@@ -1147,15 +1270,59 @@ func (c CompositeKeyMatchMethod) String() string {
 	return compositeKeyMatchMethodName[c]
 }
 
+// ConstraintDeferrability describes whether the checks for a constraint can
+// be postponed until the end of the transaction, and whether they are by
+// default.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	// ConstraintNotDeferrable indicates that the constraint is checked at the
+	// end of every statement. This is the default.
+	ConstraintNotDeferrable ConstraintDeferrability = iota
+	// ConstraintDeferrable indicates that the constraint is checked at the end
+	// of every statement unless it is deferred with SET CONSTRAINTS.
+	ConstraintDeferrable
+	// ConstraintInitiallyDeferred indicates that the constraint is checked when
+	// the transaction commits unless it is made immediate with SET
+	// CONSTRAINTS.
+	ConstraintInitiallyDeferred
+)
+
+var constraintDeferrabilityName = [...]string{
+	ConstraintNotDeferrable:     "NOT DEFERRABLE",
+	ConstraintDeferrable:        "DEFERRABLE",
+	ConstraintInitiallyDeferred: "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (d ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[d]
+}
+
+// IsDeferrable returns true if the constraint was declared DEFERRABLE.
+func (d ConstraintDeferrability) IsDeferrable() bool {
+	return d != ConstraintNotDeferrable
+}
+
+// Format implements the NodeFormatter interface. NOT DEFERRABLE is omitted
+// since it is the default.
+func (d *ConstraintDeferrability) Format(ctx *FmtCtx) {
+	if d.IsDeferrable() {
+		ctx.WriteByte(' ')
+		ctx.WriteString(d.String())
+	}
+}
+
 // ForeignKeyConstraintTableDef represents a FOREIGN KEY constraint in the AST.
 type ForeignKeyConstraintTableDef struct {
-	Name        Name
-	Table       TableName
-	FromCols    NameList
-	ToCols      NameList
-	Actions     ReferenceActions
-	Match       CompositeKeyMatchMethod
-	IfNotExists bool
+	Name          Name
+	Table         TableName
+	FromCols      NameList
+	ToCols        NameList
+	Actions       ReferenceActions
+	Match         CompositeKeyMatchMethod
+	Deferrability ConstraintDeferrability
+	IfNotExists   bool
 }
 
 // Format implements the NodeFormatter interface.
@@ -1186,6 +1353,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(&node.Deferrability)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1232,6 +1400,79 @@ func (node *CheckConstraintTableDef) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// ExclusionConstraintTableDef represents an EXCLUDE constraint within a CREATE
+// TABLE statement.
+type ExclusionConstraintTableDef struct {
+	Name Name
+	// Inverted is set if the constraint was declared USING GIST or USING GIN,
+	// in which case it is backed by an inverted index.
+	Inverted    bool
+	Elems       ExclusionElemList
+	Predicate   Expr
+	IfNotExists bool
+}
+
+// SetName implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetName(name Name) {
+	node.Name = name
+}
+
+// SetIfNotExists implements the ConstraintTableDef interface.
+func (node *ExclusionConstraintTableDef) SetIfNotExists() {
+	node.IfNotExists = true
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionConstraintTableDef) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		if node.IfNotExists {
+			ctx.WriteString("IF NOT EXISTS ")
+		}
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.WriteString("EXCLUDE ")
+	if node.Inverted {
+		ctx.WriteString("USING GIST ")
+	}
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Elems)
+	ctx.WriteByte(')')
+	if node.Predicate != nil {
+		ctx.WriteString(" WHERE (")
+		ctx.FormatNode(node.Predicate)
+		ctx.WriteByte(')')
+	}
+}
+
+// ExclusionElem is a single column of an EXCLUDE constraint, along with the
+// operator used to compare it against the other rows of the table.
+type ExclusionElem struct {
+	Column   Name
+	Operator treecmp.ComparisonOperator
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ExclusionElem) Format(ctx *FmtCtx) {
+	ctx.FormatNode(&node.Column)
+	ctx.WriteString(" WITH ")
+	ctx.WriteString(node.Operator.String())
+}
+
+// ExclusionElemList is a list of ExclusionElem.
+type ExclusionElemList []ExclusionElem
+
+// Format implements the NodeFormatter interface.
+func (l *ExclusionElemList) Format(ctx *FmtCtx) {
+	for i := range *l {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*l)[i])
+	}
+}
+
 // FamilyTableDef represents a family definition within a CREATE TABLE
 // statement.
 type FamilyTableDef struct {
@@ -1552,19 +1793,19 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 // inline with their columns and makes them table-level constraints, stored in
 // n.Defs. For example, the foreign key constraint in
 //
-//     CREATE TABLE foo (a INT REFERENCES bar(a))
+//	CREATE TABLE foo (a INT REFERENCES bar(a))
 //
 // gets pulled into a top-level constraint like:
 //
-//     CREATE TABLE foo (a INT, FOREIGN KEY (a) REFERENCES bar(a))
+//	CREATE TABLE foo (a INT, FOREIGN KEY (a) REFERENCES bar(a))
 //
 // Similarly, the CHECK constraint in
 //
-//    CREATE TABLE foo (a INT CHECK (a < 1), b INT)
+//	CREATE TABLE foo (a INT CHECK (a < 1), b INT)
 //
 // gets pulled into a top-level constraint like:
 //
-//    CREATE TABLE foo (a INT, b INT, CHECK (a < 1))
+//	CREATE TABLE foo (a INT, b INT, CHECK (a < 1))
 //
 // Note that some SQL databases require that a constraint attached to a column
 // to refer only to the column it is attached to. We follow Postgres' behavior,
@@ -1572,10 +1813,9 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 // constraints. For example, the following table definition is accepted in
 // CockroachDB and Postgres, but not necessarily other SQL databases:
 //
-//    CREATE TABLE foo (a INT CHECK (a < b), b INT)
+//	CREATE TABLE foo (a INT CHECK (a < b), b INT)
 //
 // Unique constraints are not hoisted.
-//
 func (node *CreateTable) HoistConstraints() {
 	for _, d := range node.Defs {
 		if col, ok := d.(*ColumnTableDef); ok {
@@ -1594,12 +1834,13 @@ func (node *CreateTable) HoistConstraints() {
 					targetCol = append(targetCol, col.References.Col)
 				}
 				node.Defs = append(node.Defs, &ForeignKeyConstraintTableDef{
-					Table:    *col.References.Table,
-					FromCols: NameList{col.Name},
-					ToCols:   targetCol,
-					Name:     col.References.ConstraintName,
-					Actions:  col.References.Actions,
-					Match:    col.References.Match,
+					Table:         *col.References.Table,
+					FromCols:      NameList{col.Name},
+					ToCols:        targetCol,
+					Name:          col.References.ConstraintName,
+					Actions:       col.References.Actions,
+					Match:         col.References.Match,
+					Deferrability: col.References.Deferrability,
 				})
 				col.References.Table = nil
 			}
@@ -1915,9 +1915,12 @@ func (e *MultipleResultsError) Error() string {
 // MaybeWrapError updates non-nil error depending on the FuncExpr to provide
 // more context.
 func (expr *FuncExpr) MaybeWrapError(err error) error {
-	// If we are facing an explicit error, propagate it unchanged.
+	// If we are facing an explicit error, propagate it unchanged. Errors
+	// from the enforcement of domain constraints are also propagated unchanged,
+	// since the functions are an implementation detail.
 	fName := expr.Func.String()
-	if fName == `crdb_internal.force_error` {
+	switch fName {
+	case `crdb_internal.force_error`, `crdb_internal.domain_not_null`, `crdb_internal.domain_check`:
 		return err
 	}
 	// Otherwise, wrap it with context.
@@ -722,6 +722,21 @@ func (node *CoalesceExpr) Format(ctx *FmtCtx) {
 	ctx.WriteByte(')')
 }
 
+// GroupingExpr represents a GROUPING(...) expression, which returns a bit mask
+// indicating which of its arguments are not included in the grouping set of
+// the current output row. It can only be used in queries with a GROUP BY
+// clause, and is replaced with a column reference during optimization.
+type GroupingExpr struct {
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("GROUPING(")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DefaultVal represents the DEFAULT expression.
 type DefaultVal struct{}
 
@@ -1708,6 +1723,10 @@ func (node *TupleStar) String() string        { return AsString(node) }
 func (node *AnnotateTypeExpr) String() string { return AsString(node) }
 func (node *UnaryExpr) String() string        { return AsString(node) }
 func (node DefaultVal) String() string        { return AsString(node) }
+func (node *GroupingExpr) String() string     { return AsString(node) }
+func (node *RollupExpr) String() string       { return AsString(node) }
+func (node *CubeExpr) String() string         { return AsString(node) }
+func (node *GroupingSetsExpr) String() string { return AsString(node) }
 func (node PartitionMaxVal) String() string   { return AsString(node) }
 func (node PartitionMinVal) String() string   { return AsString(node) }
 func (node *Placeholder) String() string      { return AsString(node) }
@@ -153,6 +153,51 @@ type Overload struct {
 	// DistSQL. One example is when the type information for function arguments
 	// cannot be recovered.
 	DistsqlBlocklist bool
+
+	// IsUDF is set to true when this is the overload of a user-defined
+	// function. User-defined functions have no builtin implementation; the
+	// optimizer inlines UDFBody in place of the function call. The remaining
+	// UDF fields are only set when IsUDF is true.
+	IsUDF bool
+	// UDFBody is the SQL body of the user-defined function.
+	UDFBody string
+	// UDFParamNames are the names of the input parameters of the
+	// user-defined function, in order. Unnamed parameters have an empty name.
+	UDFParamNames []string
+	// UDFStrict is true if the user-defined function returns NULL when any of
+	// its arguments is NULL instead of being evaluated.
+	UDFStrict bool
+	// UDFReturnsSet is true if the user-defined function was declared with
+	// RETURNS SETOF.
+	UDFReturnsSet bool
+	// UDFTrigger is true if the user-defined function was declared with
+	// RETURNS TRIGGER. Such functions can only be executed by triggers.
+	UDFTrigger bool
+
+	// UDFAggregate is set when this is the overload of a user-defined
+	// aggregate function created with CREATE AGGREGATE. IsUDF is false for
+	// such overloads, since they are planned as aggregations.
+	UDFAggregate *UDFAggregate
+}
+
+// UDFAggregate describes a user-defined aggregate function. The aggregate
+// keeps a state value which is updated for each input row by calling its
+// state transition function, and its result is computed from the final state
+// by its final function.
+type UDFAggregate struct {
+	// StateType is the type of the state value.
+	StateType *types.T
+	// InitCond is the string representation of the initial state value, or
+	// nil if the initial state is NULL.
+	InitCond *string
+	// StateFunc is the overload of the state transition function. It takes
+	// the current state followed by the arguments of the aggregate, and
+	// returns the next state.
+	StateFunc *Overload
+	// FinalFunc is the overload of the final function, which takes the final
+	// state. It is nil if the aggregate has no final function, in which case
+	// the result of the aggregate is the final state.
+	FinalFunc *Overload
 }
 
 // params implements the overloadImpl interface.
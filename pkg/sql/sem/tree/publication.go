@@ -0,0 +1,92 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CreatePublication represents a CREATE PUBLICATION statement.
+type CreatePublication struct {
+	Name Name
+	// AllTables is set for CREATE PUBLICATION ... FOR ALL TABLES.
+	AllTables bool
+	Tables    TableNames
+}
+
+var _ Statement = &CreatePublication{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreatePublication) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE PUBLICATION ")
+	ctx.FormatNode(&node.Name)
+	if node.AllTables {
+		ctx.WriteString(" FOR ALL TABLES")
+	} else if len(node.Tables) > 0 {
+		ctx.WriteString(" FOR TABLE ")
+		ctx.FormatNode(&node.Tables)
+	}
+}
+
+// DropPublication represents a DROP PUBLICATION statement.
+type DropPublication struct {
+	Names    NameList
+	IfExists bool
+}
+
+var _ Statement = &DropPublication{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropPublication) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP PUBLICATION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Names)
+}
+
+// CreateSubscription represents a CREATE SUBSCRIPTION statement.
+type CreateSubscription struct {
+	Name        Name
+	Connection  Expr
+	Publication Name
+	Options     KVOptions
+}
+
+var _ Statement = &CreateSubscription{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateSubscription) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SUBSCRIPTION ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" CONNECTION ")
+	ctx.FormatNode(node.Connection)
+	ctx.WriteString(" PUBLICATION ")
+	ctx.FormatNode(&node.Publication)
+	if node.Options != nil {
+		ctx.WriteString(" WITH (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteString(")")
+	}
+}
+
+// DropSubscription represents a DROP SUBSCRIPTION statement.
+type DropSubscription struct {
+	Name     Name
+	IfExists bool
+}
+
+var _ Statement = &DropSubscription{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropSubscription) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP SUBSCRIPTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+}
@@ -697,6 +697,59 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// RollupExpr represents a ROLLUP clause inside GROUP BY. ROLLUP (a, b, c) is
+// equivalent to GROUPING SETS ((a, b, c), (a, b), (a), ()).
+type RollupExpr struct {
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RollupExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("ROLLUP (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
+// CubeExpr represents a CUBE clause inside GROUP BY. CUBE (a, b) is
+// equivalent to GROUPING SETS ((a, b), (a), (b), ()).
+type CubeExpr struct {
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CubeExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("CUBE (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
+// GroupingSetsExpr represents a GROUPING SETS clause inside GROUP BY. Each
+// element is a grouping set: either an expression, a parenthesized list of
+// expressions (possibly empty), or a nested ROLLUP, CUBE or GROUPING SETS
+// clause.
+type GroupingSetsExpr struct {
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSetsExpr) Format(ctx *FmtCtx) {
+	ctx.WriteString("GROUPING SETS (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
+// HasGroupingSets returns true if the GROUP BY clause contains a ROLLUP, CUBE
+// or GROUPING SETS clause.
+func (node GroupBy) HasGroupingSets() bool {
+	for _, e := range node {
+		switch e.(type) {
+		case *RollupExpr, *CubeExpr, *GroupingSetsExpr:
+			return true
+		}
+	}
+	return false
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
@@ -91,6 +91,32 @@ func (node *SetClusterSetting) Format(ctx *FmtCtx) {
 	}
 }
 
+// SetConstraints represents a SET CONSTRAINTS statement.
+type SetConstraints struct {
+	// Names is empty for SET CONSTRAINTS ALL.
+	Names    []*UnresolvedObjectName
+	Deferred bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *SetConstraints) Format(ctx *FmtCtx) {
+	ctx.WriteString("SET CONSTRAINTS ")
+	if len(node.Names) == 0 {
+		ctx.WriteString("ALL")
+	}
+	for i, n := range node.Names {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(n)
+	}
+	if node.Deferred {
+		ctx.WriteString(" DEFERRED")
+	} else {
+		ctx.WriteString(" IMMEDIATE")
+	}
+}
+
 // SetTransaction represents a SET TRANSACTION statement.
 type SetTransaction struct {
 	Modes TransactionModes
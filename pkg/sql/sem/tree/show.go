@@ -85,6 +85,9 @@ const (
 	BackupFileDetails
 	// BackupSchemaDetails identifies a SHOW BACKUP SCHEMAS statement.
 	BackupSchemaDetails
+	// BackupSchemaHistoryDetails identifies a SHOW BACKUP SCHEMA HISTORY
+	// statement.
+	BackupSchemaHistoryDetails
 )
 
 // TODO (msbutler): 22.2 after removing old style show backup syntax, rename
@@ -115,6 +118,8 @@ func (node *ShowBackup) Format(ctx *FmtCtx) {
 		ctx.WriteString("FILES ")
 	case BackupSchemaDetails:
 		ctx.WriteString("SCHEMAS ")
+	case BackupSchemaHistoryDetails:
+		ctx.WriteString("SCHEMA HISTORY ")
 	}
 
 	if node.From {
@@ -861,6 +861,13 @@ var (
 	errPrivateFunction     = pgerror.New(pgcode.ReservedName, "function reserved for internal use")
 )
 
+var (
+	errInvalidGroupingUsage = pgerror.New(pgcode.Grouping,
+		"GROUPING can only appear in a query with a GROUP BY clause")
+	errInvalidGroupingSetUsage = pgerror.New(pgcode.Syntax,
+		"ROLLUP, CUBE and GROUPING SETS can only appear within a GROUP BY clause")
+)
+
 // NewAggInAggError creates an error for the case when an aggregate function is
 // contained within another aggregate function.
 func NewAggInAggError() error {
@@ -1105,6 +1112,14 @@ func (expr *FuncExpr) TypeCheck(
 	}
 	overloadImpl := fns[0].(*Overload)
 
+	// User-defined functions are planned as subqueries, so they are rejected
+	// wherever subqueries are.
+	if overloadImpl.IsUDF && semaCtx != nil &&
+		semaCtx.Properties.required.rejectFlags&RejectSubqueries != 0 {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"user-defined functions are not allowed in %s", semaCtx.Properties.required.context)
+	}
+
 	if expr.IsWindowFunctionApplication() {
 		// Make sure the window function application is of either a built-in window
 		// function or of a builtin aggregate function.
@@ -1474,6 +1489,40 @@ func (expr DefaultVal) TypeCheck(
 	return nil, errInvalidDefaultUsage
 }
 
+// TypeCheck implements the Expr interface. GROUPING is replaced by the
+// optimizer before type checking when it is used in a valid context, so
+// reaching this method always results in an error.
+func (expr *GroupingExpr) TypeCheck(
+	_ context.Context, sc *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	if sc != nil && sc.Properties.required.rejectFlags&RejectAggregates != 0 {
+		return nil, pgerror.Newf(pgcode.Grouping,
+			"grouping operations are not allowed in %s", sc.Properties.required.context)
+	}
+	return nil, errInvalidGroupingUsage
+}
+
+// TypeCheck implements the Expr interface.
+func (expr *RollupExpr) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errInvalidGroupingSetUsage
+}
+
+// TypeCheck implements the Expr interface.
+func (expr *CubeExpr) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errInvalidGroupingSetUsage
+}
+
+// TypeCheck implements the Expr interface.
+func (expr *GroupingSetsExpr) TypeCheck(
+	_ context.Context, _ *SemaContext, desired *types.T,
+) (TypedExpr, error) {
+	return nil, errInvalidGroupingSetUsage
+}
+
 // TypeCheck implements the Expr interface.
 func (expr PartitionMinVal) TypeCheck(
 	_ context.Context, _ *SemaContext, desired *types.T,
@@ -1606,8 +1655,10 @@ func (expr *Placeholder) TypeCheck(
 	//
 	// The width of a placeholder value is not known during Prepare, so we
 	// remove type modifiers from the desired type so that a value of any width
-	// will fit within the placeholder type.
-	desired = desired.WithoutTypeModifiers()
+	// will fit within the placeholder type. Similarly, a placeholder never has a
+	// domain type, so that the constraints of the domain are enforced when the
+	// value is cast or assigned to the domain.
+	desired = desired.DomainBaseType().WithoutTypeModifiers()
 	if typ, ok, err := semaCtx.Placeholders.Type(expr.Idx); err != nil {
 		return expr, err
 	} else if ok {
@@ -181,7 +181,7 @@ func (ctx *FmtCtx) FormatTypeReference(ref ResolvableTypeReference) {
 				ctx.WriteByte('_')
 				return
 			} else if ctx.HasFlags(fmtStaticallyFormatUserDefinedTypes) {
-				idRef := OIDTypeReference{OID: t.Oid()}
+				idRef := OIDTypeReference{OID: t.UserDefinedOID()}
 				ctx.WriteString(idRef.SQLString())
 				return
 			}
@@ -280,7 +280,7 @@ func (v *TypeCollectorVisitor) VisitPre(expr Expr) (bool, Expr) {
 	switch t := expr.(type) {
 	case Datum:
 		if t.ResolvedType().UserDefined() {
-			v.OIDs[t.ResolvedType().Oid()] = struct{}{}
+			v.OIDs[t.ResolvedType().UserDefinedOID()] = struct{}{}
 		}
 	case *IsOfTypeExpr:
 		for _, ref := range t.Types {
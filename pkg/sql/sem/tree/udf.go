@@ -0,0 +1,427 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+
+// CreateFunction represents a CREATE FUNCTION statement.
+type CreateFunction struct {
+	Replace    bool
+	FuncName   *UnresolvedObjectName
+	Args       FuncArgs
+	ReturnType FuncReturnType
+	Options    FunctionOptions
+}
+
+var _ Statement = &CreateFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	ctx.WriteString("FUNCTION ")
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteByte('(')
+	ctx.FormatNode(&node.Args)
+	ctx.WriteString(") RETURNS ")
+	if node.ReturnType.IsSet {
+		ctx.WriteString("SETOF ")
+	}
+	ctx.FormatTypeReference(node.ReturnType.Type)
+	// The function body is always printed last, regardless of where it
+	// appeared in the original statement.
+	var body *FunctionBodyStr
+	for _, option := range node.Options {
+		if b, ok := option.(FunctionBodyStr); ok {
+			body = &b
+			continue
+		}
+		ctx.WriteByte(' ')
+		ctx.FormatNode(option)
+	}
+	if body != nil {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(*body)
+	}
+}
+
+// FuncReturnType represents the return type of a function.
+type FuncReturnType struct {
+	Type ResolvableTypeReference
+	// IsSet is true if the function was declared to return a SETOF type.
+	IsSet bool
+}
+
+// FuncArgClass indicates the mode of a function argument.
+type FuncArgClass int
+
+// FuncArgClass values.
+const (
+	FunctionArgIn FuncArgClass = iota
+	FunctionArgOut
+	FunctionArgInOut
+	FunctionArgVariadic
+)
+
+var funcArgClassName = [...]string{
+	FunctionArgIn:       "IN",
+	FunctionArgOut:      "OUT",
+	FunctionArgInOut:    "INOUT",
+	FunctionArgVariadic: "VARIADIC",
+}
+
+func (c FuncArgClass) String() string {
+	return funcArgClassName[c]
+}
+
+// FuncArg represents an argument in the argument list of a CREATE FUNCTION
+// or DROP FUNCTION statement.
+type FuncArg struct {
+	// Name is the name of the argument. It is empty if the argument was
+	// declared without a name.
+	Name  Name
+	Type  ResolvableTypeReference
+	Class FuncArgClass
+	// DefaultVal is the default value of the argument, or nil if the argument
+	// does not have a default.
+	DefaultVal Expr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArg) Format(ctx *FmtCtx) {
+	if node.Class != FunctionArgIn {
+		ctx.WriteString(node.Class.String())
+		ctx.WriteByte(' ')
+	}
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	ctx.FormatTypeReference(node.Type)
+	if node.DefaultVal != nil {
+		ctx.WriteString(" DEFAULT ")
+		ctx.FormatNode(node.DefaultVal)
+	}
+}
+
+// FuncArgs represents a list of function arguments.
+type FuncArgs []FuncArg
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArgs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// FunctionOption is an interface representing the options of a CREATE
+// FUNCTION statement, such as its volatility or its body.
+type FunctionOption interface {
+	NodeFormatter
+	functionOption()
+}
+
+func (FunctionNullInputBehavior) functionOption() {}
+func (FunctionVolatility) functionOption()        {}
+func (FunctionLeakproof) functionOption()         {}
+func (FunctionLanguage) functionOption()          {}
+func (FunctionBodyStr) functionOption()           {}
+
+// FunctionOptions represents a list of function options.
+type FunctionOptions []FunctionOption
+
+// FunctionNullInputBehavior represents the behavior of a function when it is
+// called with NULL arguments.
+type FunctionNullInputBehavior int
+
+// FunctionNullInputBehavior values.
+const (
+	// FunctionCalledOnNullInput indicates that the function is evaluated even
+	// if some of its arguments are NULL. This is the default.
+	FunctionCalledOnNullInput FunctionNullInputBehavior = iota
+	// FunctionReturnsNullOnNullInput indicates that the function returns NULL
+	// without being evaluated if any of its arguments are NULL.
+	FunctionReturnsNullOnNullInput
+	// FunctionStrict is a synonym of FunctionReturnsNullOnNullInput.
+	FunctionStrict
+)
+
+// Format implements the NodeFormatter interface.
+func (node FunctionNullInputBehavior) Format(ctx *FmtCtx) {
+	switch node {
+	case FunctionCalledOnNullInput:
+		ctx.WriteString("CALLED ON NULL INPUT")
+	case FunctionReturnsNullOnNullInput:
+		ctx.WriteString("RETURNS NULL ON NULL INPUT")
+	case FunctionStrict:
+		ctx.WriteString("STRICT")
+	}
+}
+
+// FunctionVolatility represents the volatility of a function.
+type FunctionVolatility int
+
+// FunctionVolatility values.
+const (
+	// FunctionVolatile is the default volatility of a function.
+	FunctionVolatile FunctionVolatility = iota
+	FunctionImmutable
+	FunctionStable
+)
+
+// Format implements the NodeFormatter interface.
+func (node FunctionVolatility) Format(ctx *FmtCtx) {
+	switch node {
+	case FunctionVolatile:
+		ctx.WriteString("VOLATILE")
+	case FunctionImmutable:
+		ctx.WriteString("IMMUTABLE")
+	case FunctionStable:
+		ctx.WriteString("STABLE")
+	}
+}
+
+// FunctionLeakproof indicates whether a function is leakproof.
+type FunctionLeakproof bool
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLeakproof) Format(ctx *FmtCtx) {
+	if !node {
+		ctx.WriteString("NOT ")
+	}
+	ctx.WriteString("LEAKPROOF")
+}
+
+// FunctionLanguage is the language of a function's body.
+type FunctionLanguage string
+
+// FunctionLangSQL is the only language currently supported for function
+// bodies.
+const FunctionLangSQL FunctionLanguage = "sql"
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLanguage) Format(ctx *FmtCtx) {
+	ctx.WriteString("LANGUAGE ")
+	ctx.WriteString(string(node))
+}
+
+// FunctionBodyStr is the string body of a function, as given in the AS clause.
+type FunctionBodyStr string
+
+// Format implements the NodeFormatter interface.
+func (node FunctionBodyStr) Format(ctx *FmtCtx) {
+	ctx.WriteString("AS ")
+	if ctx.HasFlags(FmtAnonymize) || ctx.HasFlags(FmtHideConstants) {
+		// The body may contain both identifiers and constants, so it is
+		// elided in its entirety.
+		ctx.WriteString("'_'")
+		return
+	}
+	lexbase.EncodeSQLString(&ctx.Buffer, string(node))
+}
+
+// FuncObj represents a function, with an optional argument list, named in a
+// DROP FUNCTION statement.
+type FuncObj struct {
+	FuncName *UnresolvedObjectName
+	// Args is the argument list of the function. It is nil if the argument
+	// list was omitted, in which case the name must identify a unique
+	// function.
+	Args FuncArgs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObj) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.FuncName)
+	if node.Args != nil {
+		ctx.WriteByte('(')
+		ctx.FormatNode(&node.Args)
+		ctx.WriteByte(')')
+	}
+}
+
+// FuncObjs is a list of FuncObj.
+type FuncObjs []FuncObj
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObjs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// DropFunction represents a DROP FUNCTION statement.
+type DropFunction struct {
+	Functions    FuncObjs
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropFunction{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP FUNCTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Functions)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// CreateAggregate represents a CREATE AGGREGATE statement.
+type CreateAggregate struct {
+	FuncName *UnresolvedObjectName
+	// Args is the argument list of the aggregate. It is empty for aggregates
+	// which take no arguments, which are written with a '*' argument list.
+	Args    FuncArgs
+	Options AggregateOptions
+}
+
+var _ Statement = &CreateAggregate{}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateAggregate) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE AGGREGATE ")
+	ctx.FormatNode(node.FuncName)
+	formatAggregateArgs(ctx, node.Args)
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Options)
+	ctx.WriteByte(')')
+}
+
+// formatAggregateArgs formats the argument list of an aggregate, which is
+// written as (*) if the aggregate takes no arguments.
+func formatAggregateArgs(ctx *FmtCtx, args FuncArgs) {
+	ctx.WriteByte('(')
+	if len(args) == 0 {
+		ctx.WriteByte('*')
+	} else {
+		ctx.FormatNode(&args)
+	}
+	ctx.WriteByte(')')
+}
+
+// AggregateOption is an interface for the options in the definition of a
+// CREATE AGGREGATE statement.
+type AggregateOption interface {
+	aggregateOption()
+	NodeFormatter
+}
+
+func (*AggregateStateFunc) aggregateOption() {}
+func (*AggregateStateType) aggregateOption() {}
+func (*AggregateFinalFunc) aggregateOption() {}
+func (AggregateInitCond) aggregateOption()   {}
+
+// AggregateOptions is a list of AggregateOption.
+type AggregateOptions []AggregateOption
+
+// Format implements the NodeFormatter interface.
+func (node *AggregateOptions) Format(ctx *FmtCtx) {
+	for i, option := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(option)
+	}
+}
+
+// AggregateStateFunc is the SFUNC option of an aggregate, which names its
+// state transition function.
+type AggregateStateFunc struct {
+	Name *UnresolvedObjectName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AggregateStateFunc) Format(ctx *FmtCtx) {
+	ctx.WriteString("SFUNC = ")
+	ctx.FormatNode(node.Name)
+}
+
+// AggregateStateType is the STYPE option of an aggregate, which is the type
+// of its state value.
+type AggregateStateType struct {
+	Type ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AggregateStateType) Format(ctx *FmtCtx) {
+	ctx.WriteString("STYPE = ")
+	ctx.FormatTypeReference(node.Type)
+}
+
+// AggregateFinalFunc is the FINALFUNC option of an aggregate, which names the
+// function computing its result from the final state.
+type AggregateFinalFunc struct {
+	Name *UnresolvedObjectName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AggregateFinalFunc) Format(ctx *FmtCtx) {
+	ctx.WriteString("FINALFUNC = ")
+	ctx.FormatNode(node.Name)
+}
+
+// AggregateInitCond is the INITCOND option of an aggregate, which is the
+// string representation of its initial state value.
+type AggregateInitCond string
+
+// Format implements the NodeFormatter interface.
+func (node AggregateInitCond) Format(ctx *FmtCtx) {
+	ctx.WriteString("INITCOND = ")
+	if ctx.HasFlags(FmtHideConstants) {
+		ctx.WriteString("'_'")
+		return
+	}
+	lexbase.EncodeSQLString(&ctx.Buffer, string(node))
+}
+
+// DropAggregate represents a DROP AGGREGATE statement.
+type DropAggregate struct {
+	// Aggregates are the aggregates to drop. Their argument lists are never
+	// nil.
+	Aggregates   FuncObjs
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+var _ Statement = &DropAggregate{}
+
+// Format implements the NodeFormatter interface.
+func (node *DropAggregate) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP AGGREGATE ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	for i := range node.Aggregates {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(node.Aggregates[i].FuncName)
+		formatAggregateArgs(ctx, node.Aggregates[i].Args)
+	}
+	if node.DropBehavior != DropDefault {
+		ctx.WriteByte(' ')
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
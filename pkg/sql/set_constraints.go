@@ -0,0 +1,149 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// SetConstraints implements the SET CONSTRAINTS statement.
+// See https://www.postgresql.org/docs/current/sql-set-constraints.html for
+// details.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	state := p.extendedEvalCtx.DeferredConstraints
+	if state == nil || p.extendedEvalCtx.TxnImplicit {
+		// Like Postgres, the statement has no effect outside of a transaction
+		// block, but the constraint names are still checked.
+		p.BufferClientNotice(ctx, pgnotice.NewWithSeverityf(
+			"WARNING", "SET CONSTRAINTS can only be used in transaction blocks",
+		))
+		state = nil
+	}
+
+	var keys []deferredConstraintKey
+	if n.Names != nil {
+		keys = make([]deferredConstraintKey, 0, len(n.Names))
+		for _, name := range n.Names {
+			resolved, err := p.resolveDeferrableConstraint(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, resolved...)
+		}
+	}
+
+	if state == nil {
+		return newZeroNode(nil /* columns */), nil
+	}
+
+	mode := constraintCheckImmediate
+	if n.Deferred {
+		mode = constraintCheckDeferred
+	}
+	state.setMode(keys, mode)
+	if mode == constraintCheckImmediate {
+		immediate := state.takeImmediate()
+		if err := validateDeferredChecks(
+			ctx, p.txn, p.Descriptors(), p.ExecCfg().InternalExecutor, immediate,
+		); err != nil {
+			return nil, err
+		}
+		state.release(ctx, immediate)
+	}
+	return newZeroNode(nil /* columns */), nil
+}
+
+// resolveDeferrableConstraint returns all the constraints matching the given
+// name. An unqualified name is looked up in the schemas on the search path, in
+// order, stopping at the first schema which contains a matching constraint.
+func (p *planner) resolveDeferrableConstraint(
+	ctx context.Context, name *tree.UnresolvedObjectName,
+) ([]deferredConstraintKey, error) {
+	dbName := p.CurrentDatabase()
+	if name.HasExplicitCatalog() {
+		dbName = name.Catalog()
+	}
+	db, err := p.Descriptors().GetImmutableDatabaseByName(
+		ctx, p.txn, dbName, tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := p.Descriptors().GetAllTableDescriptorsInDatabase(ctx, p.txn, db.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	var schemaNames []string
+	if name.HasExplicitSchema() {
+		schemaNames = []string{name.Schema()}
+	} else {
+		iter := p.CurrentSearchPath().Iter()
+		for scName, ok := iter.Next(); ok; scName, ok = iter.Next() {
+			schemaNames = append(schemaNames, scName)
+		}
+	}
+
+	constraintName := name.Object()
+	for _, scName := range schemaNames {
+		sc, err := p.Descriptors().GetImmutableSchemaByName(
+			ctx, p.txn, db, scName, tree.SchemaLookupFlags{},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if sc == nil {
+			continue
+		}
+		var keys []deferredConstraintKey
+		for _, tab := range tables {
+			if tab.GetParentSchemaID() != sc.GetID() || !tab.IsTable() || tab.Dropped() {
+				continue
+			}
+			if err := checkConstraintDeferrable(tab, constraintName); err != nil {
+				if pgerror.GetPGCode(err) == pgcode.UndefinedObject {
+					continue
+				}
+				return nil, err
+			}
+			keys = append(keys, deferredConstraintKey{tableID: tab.GetID(), name: constraintName})
+		}
+		if len(keys) > 0 {
+			return keys, nil
+		}
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject, "constraint %q does not exist", constraintName)
+}
+
+// checkConstraintDeferrable returns an error if the given table doesn't have a
+// deferrable constraint with the given name.
+func checkConstraintDeferrable(tab catalog.TableDescriptor, constraintName string) error {
+	info, err := tab.GetConstraintInfo()
+	if err != nil {
+		return err
+	}
+	c, ok := info[constraintName]
+	if !ok {
+		return pgerror.Newf(pgcode.UndefinedObject, "constraint %q does not exist", constraintName)
+	}
+	if !c.Deferrability().IsDeferrable() {
+		return pgerror.Newf(
+			pgcode.WrongObjectType, "constraint %q is not deferrable", constraintName,
+		)
+	}
+	return nil
+}
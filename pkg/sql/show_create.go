@@ -138,6 +138,11 @@ func ShowCreateTable(
 	for _, idx := range desc.PublicNonPrimaryIndexes() {
 		// Showing the primary index is handled above.
 
+		// Indexes backing EXCLUDE constraints are shown as constraints below.
+		if idx.IsExclusion() {
+			continue
+		}
+
 		// Build the PARTITION BY clause.
 		var partitionBuf bytes.Buffer
 		if err := ShowCreatePartitioning(
@@ -192,6 +197,10 @@ func ShowCreateTable(
 		}
 	}
 
+	if err := showTriggers(tn, desc, lCtx, f); err != nil {
+		return "", err
+	}
+
 	return f.CloseAndGetString(), nil
 }
 
@@ -18,6 +18,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catformat"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
@@ -254,6 +255,52 @@ func formatViewQueryTypesForDisplay(
 
 // showComments prints out the COMMENT statements sufficient to populate a
 // table's comments, including its index and column comments.
+// showTriggers appends the CREATE TRIGGER statements for the triggers of the
+// table to f.
+func showTriggers(
+	tn *tree.TableName, table catalog.TableDescriptor, lCtx simpleSchemaResolver, f *tree.FmtCtx,
+) error {
+	triggers := table.GetTriggers()
+	for i := range triggers {
+		t := &triggers[i]
+		fn, err := lCtx.getFunctionByID(t.FuncID)
+		if err != nil {
+			return err
+		}
+		db, err := lCtx.getDatabaseByID(fn.GetParentID())
+		if err != nil {
+			return err
+		}
+		scName := tree.PublicSchema
+		if fn.GetParentSchemaID() != keys.PublicSchemaID {
+			sc, err := lCtx.getSchemaByID(fn.GetParentSchemaID())
+			if err != nil {
+				return err
+			}
+			scName = sc.GetName()
+		}
+		fnName, err := tree.NewUnresolvedObjectName(
+			3, [3]string{fn.GetName(), scName, db.GetName()}, tree.NoAnnotation,
+		)
+		if err != nil {
+			return err
+		}
+		events := make(tree.TriggerEvents, len(t.Events))
+		for j, e := range t.Events {
+			events[j] = descpb.TriggerEventType[e]
+		}
+		f.WriteString(";\n")
+		f.FormatNode(&tree.CreateTrigger{
+			Name:       tree.Name(t.Name),
+			ActionTime: descpb.TriggerActionTimeType[t.ActionTime],
+			Events:     events,
+			Table:      tn.ToUnresolvedObjectName(),
+			FuncName:   fnName,
+		})
+	}
+	return nil
+}
+
 func showComments(
 	tn *tree.TableName, table catalog.TableDescriptor, tc *tableComments, buf *bytes.Buffer,
 ) error {
@@ -385,6 +432,10 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	if d := fk.Deferrability(); d.IsDeferrable() {
+		buf.WriteByte(' ')
+		buf.WriteString(d.String())
+	}
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
@@ -631,6 +682,10 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		if d := c.Deferrability(); d.IsDeferrable() {
+			f.WriteString(" ")
+			f.WriteString(d.String())
+		}
 		if c.IsPartial() {
 			f.WriteString(" WHERE ")
 			pred, err := schemaexpr.FormatExprForDisplay(ctx, desc, c.Predicate, semaCtx, sessionData, tree.FmtParsable)
@@ -643,6 +698,21 @@ func showConstraintClause(
 			f.WriteString(" NOT VALID")
 		}
 	}
+	for _, idx := range desc.PublicNonPrimaryIndexes() {
+		if !idx.IsExclusion() {
+			continue
+		}
+		f.WriteString(",\n\tCONSTRAINT ")
+		formatQuoteNames(&f.Buffer, idx.GetName())
+		f.WriteString(" ")
+		excl, err := catformat.ExclusionConstraintForDisplay(
+			ctx, desc, idx.IndexDesc(), tree.FmtSimple, semaCtx, sessionData,
+		)
+		if err != nil {
+			return err
+		}
+		f.WriteString(excl)
+	}
 	f.WriteString("\n)")
 	return nil
 }
@@ -598,7 +598,7 @@ func (sc *TableStatisticsCache) parseStats(
 			) error {
 				resolver := descs.NewDistSQLTypeResolver(descriptors, txn)
 				var err error
-				res.HistogramData.ColumnType, err = resolver.ResolveTypeByOID(ctx, typ.Oid())
+				res.HistogramData.ColumnType, err = resolver.ResolveTypeByOID(ctx, typ.UserDefinedOID())
 				return err
 			}); err != nil {
 				return nil, err
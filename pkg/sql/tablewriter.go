@@ -145,6 +145,9 @@ type tableWriterBase struct {
 	forceProductionBatchSizes bool
 	// sv settings values for cluster settings
 	sv *settings.Values
+	// afterTriggers, if set, buffers the modified rows for the AFTER triggers
+	// of the table, which are run in finalize.
+	afterTriggers *afterTriggers
 }
 
 var maxBatchBytes = settings.RegisterByteSizeSetting(
@@ -211,6 +214,8 @@ func (tb *tableWriterBase) finalize(ctx context.Context) (err error) {
 	// for response processing when finalizing.
 	tb.rowsWritten += int64(tb.currentBatchSize)
 	if tb.autoCommit == autoCommitEnabled &&
+		// The AFTER triggers must run before the transaction commits.
+		tb.afterTriggers == nil &&
 		// We can only auto commit if the rows written guardrail is disabled or
 		// we haven't exceeded the specified limit (the optimizer is responsible
 		// for making sure that there is exactly one mutation before enabling
@@ -232,7 +237,13 @@ func (tb *tableWriterBase) finalize(ctx context.Context) (err error) {
 	if err != nil {
 		return row.ConvertBatchError(ctx, tb.desc, tb.b)
 	}
-	return tb.tryDoResponseAdmission(ctx)
+	if err := tb.tryDoResponseAdmission(ctx); err != nil {
+		return err
+	}
+	if tb.afterTriggers != nil {
+		return tb.afterTriggers.run(ctx)
+	}
+	return nil
 }
 
 func (tb *tableWriterBase) tryDoResponseAdmission(ctx context.Context) error {
@@ -274,4 +285,8 @@ func (tb *tableWriterBase) close(ctx context.Context) {
 		tb.rows.Close(ctx)
 		tb.rows = nil
 	}
+	if tb.afterTriggers != nil {
+		tb.afterTriggers.close(ctx)
+		tb.afterTriggers = nil
+	}
 }
@@ -52,7 +52,15 @@ func (td *tableDeleter) row(
 	ctx context.Context, values tree.Datums, pm row.PartialIndexUpdateHelper, traceKV bool,
 ) error {
 	td.currentBatchSize++
-	return td.rd.DeleteRow(ctx, td.b, values, pm, traceKV)
+	if err := td.rd.DeleteRow(ctx, td.b, values, pm, traceKV); err != nil {
+		return err
+	}
+	if td.afterTriggers != nil {
+		return td.afterTriggers.addRow(
+			ctx, nil /* newValues */, catalog.TableColMap{}, values, td.rd.FetchColIDtoRowIndex,
+		)
+	}
+	return nil
 }
 
 // deleteIndex runs the kv operations necessary to delete all kv entries in the
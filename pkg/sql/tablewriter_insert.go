@@ -44,7 +44,15 @@ func (ti *tableInserter) row(
 	ctx context.Context, values tree.Datums, pm row.PartialIndexUpdateHelper, traceKV bool,
 ) error {
 	ti.currentBatchSize++
-	return ti.ri.InsertRow(ctx, ti.b, values, pm, false /* overwrite */, traceKV)
+	if err := ti.ri.InsertRow(ctx, ti.b, values, pm, false /* overwrite */, traceKV); err != nil {
+		return err
+	}
+	if ti.afterTriggers != nil {
+		return ti.afterTriggers.addRow(
+			ctx, values, ti.ri.InsertColIDtoRowIndex, nil /* oldValues */, catalog.TableColMap{},
+		)
+	}
+	return nil
 }
 
 // tableDesc is part of the tableWriter interface.
@@ -0,0 +1,236 @@
+// Copyright 2022 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowcontainer"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// afterTriggers buffers the rows modified by a tableWriter for the AFTER
+// triggers of the table which fire for the mutation. The trigger functions are
+// run for each buffered row, in the order in which the triggers are defined on
+// the table, once all rows have been written.
+type afterTriggers struct {
+	evalCtx *eval.Context
+
+	// cols are the columns of the table which can be referenced by the
+	// trigger functions through the NEW and OLD rows.
+	cols []catalog.Column
+
+	// stmts contains the statements which run the function of each trigger.
+	stmts []afterTriggerStmt
+
+	// rows contains, for each modified row, the values of cols in the NEW row
+	// followed by their values in the OLD row.
+	rows *rowcontainer.RowContainer
+
+	// scratch is used to prepare rows for the row container.
+	scratch tree.Datums
+}
+
+// afterTriggerStmt is the body of a trigger function, with the references to
+// the columns of the NEW and OLD rows replaced by placeholders.
+type afterTriggerStmt struct {
+	sql string
+	// argOrds maps each placeholder in sql to the ordinal of its value in a
+	// buffered row.
+	argOrds []int
+}
+
+// initAfterTriggers prepares the tableWriter to buffer the modified rows for
+// the AFTER triggers of the table which fire for the given event. It is a
+// no-op if there are no such triggers.
+func (tb *tableWriterBase) initAfterTriggers(
+	evalCtx *eval.Context, event descpb.TableDescriptor_Trigger_Event,
+) error {
+	var triggers []*descpb.TableDescriptor_Trigger
+	all := tb.desc.GetTriggers()
+	for i := range all {
+		if t := &all[i]; t.ActionTime == descpb.TableDescriptor_Trigger_AFTER && t.HasEvent(event) {
+			triggers = append(triggers, t)
+		}
+	}
+	if len(triggers) == 0 {
+		return nil
+	}
+
+	at := &afterTriggers{evalCtx: evalCtx, cols: tb.desc.VisibleColumns()}
+	at.stmts = make([]afterTriggerStmt, len(triggers))
+	for i, t := range triggers {
+		stmt, err := at.makeStatement(t)
+		if err != nil {
+			return err
+		}
+		at.stmts[i] = stmt
+	}
+	colTypes := make([]*types.T, 2*len(at.cols))
+	for i, col := range at.cols {
+		colTypes[i] = col.GetType()
+		colTypes[len(at.cols)+i] = col.GetType()
+	}
+	at.rows = rowcontainer.NewRowContainer(
+		evalCtx.Mon.MakeBoundAccount(), colinfo.ColTypeInfoFromColTypes(colTypes),
+	)
+	at.scratch = make(tree.Datums, len(colTypes))
+	tb.afterTriggers = at
+	return nil
+}
+
+// makeStatement returns the body of the given trigger's function, with the
+// references to the columns of the NEW and OLD rows replaced by typed
+// placeholders.
+func (at *afterTriggers) makeStatement(
+	t *descpb.TableDescriptor_Trigger,
+) (_ afterTriggerStmt, err error) {
+	stmt, err := parser.ParseOne(t.FuncBody)
+	if err != nil {
+		return afterTriggerStmt{}, err
+	}
+	var res afterTriggerStmt
+	placeholders := make(map[int]tree.PlaceholderIdx)
+	colOrdinal := make(map[string]int, len(at.cols))
+	for i, col := range at.cols {
+		colOrdinal[col.GetName()] = i
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if err, ok = r.(error); !ok {
+				panic(r)
+			}
+		}
+	}()
+	// SimpleStmtVisit does not propagate errors returned by the visitor, so
+	// errors are raised with a panic and recovered above.
+	newStmt, err := tree.SimpleStmtVisit(stmt.AST, func(expr tree.Expr) (bool, tree.Expr, error) {
+		name, ok := expr.(*tree.UnresolvedName)
+		if !ok || name.NumParts != 2 {
+			return true, expr, nil
+		}
+		var offset int
+		switch name.Parts[1] {
+		case "new":
+		case "old":
+			offset = len(at.cols)
+		default:
+			return true, expr, nil
+		}
+		if name.Star {
+			panic(unimplemented.New("trigger-row-star",
+				"references to all columns of the NEW or OLD row are not supported"))
+		}
+		ord, ok := colOrdinal[name.Parts[0]]
+		if !ok {
+			panic(pgerror.Newf(pgcode.UndefinedColumn,
+				"record %q has no field %q", name.Parts[1], name.Parts[0]))
+		}
+		idx, ok := placeholders[offset+ord]
+		if !ok {
+			idx = tree.PlaceholderIdx(len(res.argOrds))
+			placeholders[offset+ord] = idx
+			res.argOrds = append(res.argOrds, offset+ord)
+		}
+		var typ tree.ResolvableTypeReference = at.cols[ord].GetType()
+		if at.cols[ord].GetType().UserDefined() {
+			typ = &tree.OIDTypeReference{OID: at.cols[ord].GetType().UserDefinedOID()}
+		}
+		return false, &tree.AnnotateTypeExpr{
+			Expr:       &tree.Placeholder{Idx: idx},
+			Type:       typ,
+			SyntaxMode: tree.AnnotateShort,
+		}, nil
+	})
+	if err != nil {
+		return afterTriggerStmt{}, err
+	}
+	res.sql = tree.AsStringWithFlags(newStmt, tree.FmtParsable)
+	return res, nil
+}
+
+// addRow buffers a modified row. newValues and oldValues hold the values of
+// the NEW and OLD rows, and are mapped to the table columns by newColMap and
+// oldColMap. A nil newValues (or oldValues) indicates that the mutation has
+// no NEW (or OLD) row, in which case all its values are NULL.
+func (at *afterTriggers) addRow(
+	ctx context.Context,
+	newValues tree.Datums,
+	newColMap catalog.TableColMap,
+	oldValues tree.Datums,
+	oldColMap catalog.TableColMap,
+) error {
+	fill := func(offset int, values tree.Datums, colMap catalog.TableColMap) {
+		for i, col := range at.cols {
+			at.scratch[offset+i] = tree.DNull
+			if values == nil {
+				continue
+			}
+			if idx, ok := colMap.Get(col.GetID()); ok {
+				at.scratch[offset+i] = values[idx]
+			}
+		}
+	}
+	fill(0, newValues, newColMap)
+	fill(len(at.cols), oldValues, oldColMap)
+	_, err := at.rows.AddRow(ctx, at.scratch)
+	return err
+}
+
+// run runs the trigger functions for all buffered rows, within the
+// transaction of the mutation.
+func (at *afterTriggers) run(ctx context.Context) error {
+	for i, n := 0, at.rows.Len(); i < n; i++ {
+		row := at.rows.At(i)
+		for j := range at.stmts {
+			if err := at.runStatement(ctx, &at.stmts[j], row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (at *afterTriggers) runStatement(
+	ctx context.Context, stmt *afterTriggerStmt, row tree.Datums,
+) (retErr error) {
+	args := make([]interface{}, len(stmt.argOrds))
+	for i, ord := range stmt.argOrds {
+		args[i] = row[ord]
+	}
+	it, err := at.evalCtx.Planner.QueryIteratorEx(
+		ctx, "trigger", sessiondata.NoSessionDataOverride, stmt.sql, args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { retErr = errors.CombineErrors(retErr, it.Close()) }()
+	var ok bool
+	for ok, err = it.Next(ctx); ok; ok, err = it.Next(ctx) {
+	}
+	return err
+}
+
+func (at *afterTriggers) close(ctx context.Context) {
+	at.rows.Close(ctx)
+}
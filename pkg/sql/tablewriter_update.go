@@ -57,7 +57,18 @@ func (tu *tableUpdater) rowForUpdate(
 	traceKV bool,
 ) (tree.Datums, error) {
 	tu.currentBatchSize++
-	return tu.ru.UpdateRow(ctx, tu.b, oldValues, updateValues, pm, traceKV)
+	newValues, err := tu.ru.UpdateRow(ctx, tu.b, oldValues, updateValues, pm, traceKV)
+	if err != nil {
+		return nil, err
+	}
+	if tu.afterTriggers != nil {
+		if err := tu.afterTriggers.addRow(
+			ctx, newValues, tu.ru.FetchColIDtoRowIndex, oldValues, tu.ru.FetchColIDtoRowIndex,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return newValues, nil
 }
 
 // tableDesc is part of the tableWriter interface.
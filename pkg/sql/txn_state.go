@@ -158,6 +158,8 @@ const (
 //   and should be fixed to this timestamp.
 // priority: The transaction's priority. Pass roachpb.UnspecifiedUserPriority if the txn arg is
 //   not nil.
+// isoLevel: The transaction's isolation level. Pass kv.Serializable if the txn
+//   arg is not nil.
 // readOnly: The read-only character of the new txn.
 // txn: If not nil, this txn will be used instead of creating a new txn. If so,
 //   all the other arguments need to correspond to the attributes of this txn
@@ -171,6 +173,7 @@ func (ts *txnState) resetForNewSQLTxn(
 	sqlTimestamp time.Time,
 	historicalTimestamp *hlc.Timestamp,
 	priority roachpb.UserPriority,
+	isoLevel kv.IsolationLevel,
 	readOnly tree.ReadWriteMode,
 	txn *kv.Txn,
 	tranCtx transitionCtx,
@@ -217,10 +220,16 @@ func (ts *txnState) resetForNewSQLTxn(
 		if err := ts.setPriorityLocked(priority); err != nil {
 			panic(err)
 		}
+		if err := ts.setIsolationLevelLocked(isoLevel); err != nil {
+			panic(err)
+		}
 	} else {
 		if priority != roachpb.UnspecifiedUserPriority {
 			panic(errors.AssertionFailedf("unexpected priority when using an existing txn: %s", priority))
 		}
+		if isoLevel != kv.Serializable {
+			panic(errors.AssertionFailedf("unexpected isolation level when using an existing txn: %s", isoLevel))
+		}
 		ts.mu.txn = txn
 	}
 	txnID = ts.mu.txn.ID()
@@ -332,6 +341,21 @@ func (ts *txnState) setPriorityLocked(userPriority roachpb.UserPriority) error {
 	return nil
 }
 
+func (ts *txnState) setIsolationLevel(isoLevel kv.IsolationLevel) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.setIsolationLevelLocked(isoLevel)
+}
+
+func (ts *txnState) setIsolationLevelLocked(isoLevel kv.IsolationLevel) error {
+	if isoLevel != ts.mu.txn.IsoLevel() &&
+		(ts.mu.txn.Sender().HasPerformedReads() || ts.mu.txn.Sender().HasPerformedWrites()) {
+		return pgerror.New(pgcode.ActiveSQLTransaction,
+			"SET TRANSACTION ISOLATION LEVEL must be called before any query")
+	}
+	return ts.mu.txn.SetIsoLevel(isoLevel)
+}
+
 func (ts *txnState) setReadOnlyMode(mode tree.ReadWriteMode) error {
 	switch mode {
 	case tree.UnspecifiedReadWriteMode:
@@ -196,6 +196,28 @@ type UserDefinedTypeMetadata struct {
 
 	// enumData is non-nil iff the metadata is for an ENUM type.
 	EnumData *EnumMetadata
+
+	// DomainData is non-nil iff the metadata is for a domain type.
+	DomainData *DomainMetadata
+}
+
+// DomainMetadata is metadata about a domain needed to enforce its
+// constraints.
+type DomainMetadata struct {
+	// NotNull is true if the domain does not allow NULL values.
+	NotNull bool
+	// DefaultExpr is the serialized default expression of the domain, or nil
+	// if the domain does not have a default.
+	DefaultExpr *string
+	// Checks are the CHECK constraints of the domain.
+	Checks []DomainCheck
+}
+
+// DomainCheck is a CHECK constraint of a domain. The serialized expression
+// refers to the value being checked as VALUE.
+type DomainCheck struct {
+	Name string
+	Expr string
 }
 
 // EnumMetadata is metadata about an ENUM needed for evaluation.
@@ -1097,6 +1119,18 @@ func MakeEnum(typeOID, arrayTypeOID oid.Oid) *T {
 	}}
 }
 
+// MakeDomain constructs a new instance of a domain type over the given base
+// type. The returned type has the family and OID of the base type, so that
+// values of the domain are handled like values of its base type, and records
+// the OID of the domain in its persistent user-defined type metadata.
+func MakeDomain(base *T, domainOID oid.Oid) *T {
+	typ := &T{InternalType: base.InternalType}
+	typ.InternalType.UDTMetadata = &PersistentUserDefinedTypeMetadata{
+		DomainOID: &domainOID,
+	}
+	return typ
+}
+
 // MakeArray constructs a new instance of an ArrayFamily type with the given
 // element type (which may itself be an ArrayFamily type).
 func MakeArray(typ *T) *T {
@@ -1350,12 +1384,53 @@ func (t *T) UserDefinedArrayOID() oid.Oid {
 	return t.InternalType.UDTMetadata.ArrayTypeOID
 }
 
+// IsDomain returns whether or not t is a domain type.
+func (t *T) IsDomain() bool {
+	return t.DomainOID() != 0
+}
+
+// DomainOID returns the OID of the domain type if t is a domain, and 0
+// otherwise. Note that Oid returns the OID of the base type of a domain.
+func (t *T) DomainOID() oid.Oid {
+	if t.InternalType.UDTMetadata == nil || t.InternalType.UDTMetadata.DomainOID == nil {
+		return 0
+	}
+	return *t.InternalType.UDTMetadata.DomainOID
+}
+
+// UserDefinedOID returns the OID which identifies the user-defined type t.
+// It is the same as Oid, except for domains, for which it returns the OID of
+// the domain rather than the OID of its base type.
+func (t *T) UserDefinedOID() oid.Oid {
+	if t.IsDomain() {
+		return t.DomainOID()
+	}
+	return t.Oid()
+}
+
+// DomainBaseType returns the base type of the domain type t. It returns t
+// if t is not a domain.
+func (t *T) DomainBaseType() *T {
+	if !t.IsDomain() {
+		return t
+	}
+	base := &T{InternalType: t.InternalType}
+	base.InternalType.UDTMetadata = nil
+	return base
+}
+
 // RemapUserDefinedTypeOIDs is used to remap OIDs stored within a types.T
 // that is a user defined type. The newArrayOID argument is ignored if the
 // input type is an Array type. It mutates the input types.T and should only
 // be used when type is known to not be shared. If the input oid values are
 // 0 then the RemapUserDefinedTypeOIDs has no effect.
 func RemapUserDefinedTypeOIDs(t *T, newOID, newArrayOID oid.Oid) {
+	if t.IsDomain() {
+		if newOID != 0 {
+			t.InternalType.UDTMetadata.DomainOID = &newOID
+		}
+		return
+	}
 	if newOID != 0 {
 		t.InternalType.Oid = newOID
 	}
@@ -1364,9 +1439,10 @@ func RemapUserDefinedTypeOIDs(t *T, newOID, newArrayOID oid.Oid) {
 	}
 }
 
-// UserDefined returns whether or not t is a user defined type.
+// UserDefined returns whether or not t is a user defined type. Domain types
+// are user defined even though their OID is the OID of their base type.
 func (t *T) UserDefined() bool {
-	return IsOIDUserDefinedType(t.Oid())
+	return IsOIDUserDefinedType(t.Oid()) || t.IsDomain()
 }
 
 // IsOIDUserDefinedType returns whether or not o corresponds to a user
@@ -1516,6 +1592,9 @@ func (t *T) Name() string {
 //   int4[]       _int4
 //
 func (t *T) PGName() string {
+	if t.IsDomain() && t.TypeMeta.Name != nil {
+		return t.TypeMeta.Name.Basename()
+	}
 	name, ok := oidext.TypeName(t.Oid())
 	if ok {
 		return strings.ToLower(name)
@@ -1565,6 +1644,9 @@ func (t *T) TelemetryName() string {
 // This function is full of special cases. See backend/utils/adt/format_type.c
 // in Postgres.
 func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
+	if t.IsDomain() && t.TypeMeta.Name != nil {
+		return t.TypeMeta.Name.Basename()
+	}
 	var buf strings.Builder
 	switch t.Family() {
 	case AnyFamily:
@@ -1759,6 +1841,9 @@ func (t *T) InformationSchemaName() string {
 // reproduce the type via parsing the string as a type. It is used in error
 // messages and also to produce the output of SHOW CREATE.
 func (t *T) SQLString() string {
+	if t.IsDomain() && t.TypeMeta.Name != nil {
+		return t.TypeMeta.Name.FQName()
+	}
 	switch t.Family() {
 	case BitFamily:
 		o := t.Oid()
@@ -2065,6 +2150,10 @@ func (t *InternalType) Identical(other *InternalType) bool {
 		if t.UDTMetadata.ArrayTypeOID != other.UDTMetadata.ArrayTypeOID {
 			return false
 		}
+		if (t.UDTMetadata.DomainOID == nil) != (other.UDTMetadata.DomainOID == nil) ||
+			(t.UDTMetadata.DomainOID != nil && *t.UDTMetadata.DomainOID != *other.UDTMetadata.DomainOID) {
+			return false
+		}
 	} else if t.UDTMetadata != nil {
 		return false
 	} else if other.UDTMetadata != nil {
@@ -249,6 +249,9 @@ func (v *planVisitor) visitInternal(plan planNode, name string) {
 	case *ordinalityNode:
 		n.source = v.visit(n.source)
 
+	case *expandNode:
+		n.source = v.visit(n.source)
+
 	case *spoolNode:
 		n.source = v.visit(n.source)
 
@@ -368,25 +371,34 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&commentOnSchemaNode{}):              "comment on schema",
 	reflect.TypeOf(&controlJobsNode{}):                  "control jobs",
 	reflect.TypeOf(&controlSchedulesNode{}):             "control schedules",
+	reflect.TypeOf(&createAggregateNode{}):              "create aggregate",
 	reflect.TypeOf(&createDatabaseNode{}):               "create database",
 	reflect.TypeOf(&createExtensionNode{}):              "create extension",
+	reflect.TypeOf(&createFunctionNode{}):               "create function",
 	reflect.TypeOf(&createIndexNode{}):                  "create index",
+	reflect.TypeOf(&createPublicationNode{}):            "create publication",
 	reflect.TypeOf(&createSequenceNode{}):               "create sequence",
 	reflect.TypeOf(&createSchemaNode{}):                 "create schema",
 	reflect.TypeOf(&createStatsNode{}):                  "create statistics",
 	reflect.TypeOf(&createTableNode{}):                  "create table",
+	reflect.TypeOf(&createTriggerNode{}):                "create trigger",
 	reflect.TypeOf(&createTypeNode{}):                   "create type",
+	reflect.TypeOf(&createDomainNode{}):                 "create domain",
 	reflect.TypeOf(&CreateRoleNode{}):                   "create user/role",
 	reflect.TypeOf(&createViewNode{}):                   "create view",
 	reflect.TypeOf(&delayedNode{}):                      "virtual table",
 	reflect.TypeOf(&deleteNode{}):                       "delete",
 	reflect.TypeOf(&deleteRangeNode{}):                  "delete range",
 	reflect.TypeOf(&distinctNode{}):                     "distinct",
+	reflect.TypeOf(&dropAggregateNode{}):                "drop aggregate",
 	reflect.TypeOf(&dropDatabaseNode{}):                 "drop database",
+	reflect.TypeOf(&dropFunctionNode{}):                 "drop function",
 	reflect.TypeOf(&dropIndexNode{}):                    "drop index",
+	reflect.TypeOf(&dropPublicationNode{}):              "drop publication",
 	reflect.TypeOf(&dropSequenceNode{}):                 "drop sequence",
 	reflect.TypeOf(&dropSchemaNode{}):                   "drop schema",
 	reflect.TypeOf(&dropTableNode{}):                    "drop table",
+	reflect.TypeOf(&dropTriggerNode{}):                  "drop trigger",
 	reflect.TypeOf(&dropTypeNode{}):                     "drop type",
 	reflect.TypeOf(&DropRoleNode{}):                     "drop user/role",
 	reflect.TypeOf(&dropViewNode{}):                     "drop view",
@@ -394,6 +406,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&explainPlanNode{}):                  "explain plan",
 	reflect.TypeOf(&explainVecNode{}):                   "explain vectorized",
 	reflect.TypeOf(&explainDDLNode{}):                   "explain ddl",
+	reflect.TypeOf(&expandNode{}):                       "expand",
 	reflect.TypeOf(&exportNode{}):                       "export",
 	reflect.TypeOf(&fetchNode{}):                        "fetch",
 	reflect.TypeOf(&filterNode{}):                       "filter",
@@ -46,6 +46,15 @@ type ReplicationStreamManager interface {
 		tenantID uint64,
 	) (StreamID, error)
 
+	// StartReplicationStreamForPublication starts a stream replication job for
+	// the tables of the specified publication in the current database on the
+	// producer side.
+	StartReplicationStreamForPublication(
+		evalCtx *eval.Context,
+		txn *kv.Txn,
+		publication string,
+	) (StreamID, error)
+
 	// UpdateReplicationStreamProgress updates the progress of a replication stream on the producer side.
 	UpdateReplicationStreamProgress(
 		evalCtx *eval.Context,
@@ -101,6 +101,11 @@ func InjectDescriptors(
 		}
 		// Inject the namespace entries.
 		for _, d := range others {
+			//nolint:descriptormarshal
+			if d.GetFunction() != nil {
+				// Functions do not have namespace entries.
+				continue
+			}
 			id, _, name, _, _, err := descpb.GetDescriptorMetadata(d)
 			if err != nil {
 				return err
@@ -123,6 +128,8 @@ func getDescriptorParentAndSchema(d *descpb.Descriptor) (parent, schema descpb.I
 		return d.Schema.ParentID, 0
 	case *descpb.Descriptor_Type:
 		return d.Type.ParentID, d.Type.ParentSchemaID
+	case *descpb.Descriptor_Function:
+		return d.Function.ParentID, d.Function.ParentSchemaID
 	case *descpb.Descriptor_Table:
 		schema := d.Table.UnexposedParentSchemaID
 		// Descriptors from prior to 20.1 carry a 0 schema ID.
@@ -143,6 +150,8 @@ func resetVersionAndModificationTime(d *descpb.Descriptor) {
 		d.Schema.Version = 1
 	case *descpb.Descriptor_Type:
 		d.Type.Version = 1
+	case *descpb.Descriptor_Function:
+		d.Function.Version = 1
 	case *descpb.Descriptor_Table:
 		d.Table.Version = 1
 	}
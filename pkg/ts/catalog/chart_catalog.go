@@ -1408,6 +1408,20 @@ var charts = []sectionDescription{
 					"changefeed.flush.messages_pushback_nanos",
 				},
 			},
+			{
+				Title: "Quota Memory",
+				Metrics: []string{
+					"changefeed.quota.memory_limit",
+					"changefeed.quota.memory_usage",
+				},
+			},
+			{
+				Title: "Quota Time Spent Waiting",
+				Metrics: []string{
+					"changefeed.quota.throughput_pushback_nanos",
+					"changefeed.quota.flush_wait_nanos",
+				},
+			},
 		},
 	},
 	{
@@ -2968,10 +2982,13 @@ var charts = []sectionDescription{
 				Metrics: []string{
 					"jobs.auto_create_stats.currently_running",
 					"jobs.backup.currently_running",
+					"jobs.backup_compaction.currently_running",
+					"jobs.backup_verification.currently_running",
 					"jobs.changefeed.currently_running",
 					"jobs.create_stats.currently_running",
 					"jobs.import.currently_running",
 					"jobs.restore.currently_running",
+					"jobs.restore_rows.currently_running",
 					"jobs.schema_change.currently_running",
 					"jobs.new_schema_change.currently_running",
 					"jobs.schema_change_gc.currently_running",
@@ -2981,6 +2998,7 @@ var charts = []sectionDescription{
 					"jobs.auto_span_config_reconciliation.currently_running",
 					"jobs.auto_sql_stats_compaction.currently_running",
 					"jobs.stream_replication.currently_running",
+					"jobs.logical_replication.currently_running",
 				},
 			},
 			{
@@ -2990,12 +3008,16 @@ var charts = []sectionDescription{
 					"jobs.auto_span_config_reconciliation.currently_idle",
 					"jobs.auto_sql_stats_compaction.currently_idle",
 					"jobs.backup.currently_idle",
+					"jobs.backup_compaction.currently_idle",
+					"jobs.backup_verification.currently_idle",
 					"jobs.changefeed.currently_idle",
 					"jobs.create_stats.currently_idle",
 					"jobs.import.currently_idle",
+					"jobs.logical_replication.currently_idle",
 					"jobs.migration.currently_idle",
 					"jobs.new_schema_change.currently_idle",
 					"jobs.restore.currently_idle",
+					"jobs.restore_rows.currently_idle",
 					"jobs.schema_change.currently_idle",
 					"jobs.schema_change_gc.currently_idle",
 					"jobs.stream_ingestion.currently_idle",
@@ -3027,6 +3049,30 @@ var charts = []sectionDescription{
 				},
 				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
 			},
+			{
+				Title: "Backup Compaction",
+				Metrics: []string{
+					"jobs.backup_compaction.fail_or_cancel_completed",
+					"jobs.backup_compaction.fail_or_cancel_failed",
+					"jobs.backup_compaction.fail_or_cancel_retry_error",
+					"jobs.backup_compaction.resume_completed",
+					"jobs.backup_compaction.resume_failed",
+					"jobs.backup_compaction.resume_retry_error",
+				},
+				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
+			},
+			{
+				Title: "Backup Verification",
+				Metrics: []string{
+					"jobs.backup_verification.fail_or_cancel_completed",
+					"jobs.backup_verification.fail_or_cancel_failed",
+					"jobs.backup_verification.fail_or_cancel_retry_error",
+					"jobs.backup_verification.resume_completed",
+					"jobs.backup_verification.resume_failed",
+					"jobs.backup_verification.resume_retry_error",
+				},
+				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
+			},
 			{
 				Title: "Changefeed",
 				Metrics: []string{
@@ -3075,6 +3121,18 @@ var charts = []sectionDescription{
 				},
 				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
 			},
+			{
+				Title: "Restore Rows",
+				Metrics: []string{
+					"jobs.restore_rows.fail_or_cancel_completed",
+					"jobs.restore_rows.fail_or_cancel_failed",
+					"jobs.restore_rows.fail_or_cancel_retry_error",
+					"jobs.restore_rows.resume_completed",
+					"jobs.restore_rows.resume_failed",
+					"jobs.restore_rows.resume_retry_error",
+				},
+				Rate: DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
+			},
 			{
 				Title: "Schema Change",
 				Metrics: []string{
@@ -3145,6 +3203,17 @@ var charts = []sectionDescription{
 					"jobs.stream_replication.resume_retry_error",
 				},
 			},
+			{
+				Title: "Logical Replication",
+				Metrics: []string{
+					"jobs.logical_replication.fail_or_cancel_completed",
+					"jobs.logical_replication.fail_or_cancel_failed",
+					"jobs.logical_replication.fail_or_cancel_retry_error",
+					"jobs.logical_replication.resume_completed",
+					"jobs.logical_replication.resume_failed",
+					"jobs.logical_replication.resume_retry_error",
+				},
+			},
 			{
 				Title: "Long Running Migrations",
 				Metrics: []string{
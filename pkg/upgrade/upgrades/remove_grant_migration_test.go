@@ -89,7 +89,7 @@ func TestRemoveGrantMigration(t *testing.T) {
 				require.NoError(t, err)
 				var desc descpb.Descriptor
 				require.NoError(t, protoutil.Unmarshal(encoded, &desc))
-				tableDescriptor, databaseDescriptor, typeDescriptor, schemaDescriptor := descpb.FromDescriptorWithMVCCTimestamp(
+				tableDescriptor, databaseDescriptor, typeDescriptor, schemaDescriptor, _ := descpb.FromDescriptorWithMVCCTimestamp(
 					&desc,
 					hlc.Timestamp{WallTime: timeutil.Now().UnixNano()},
 				)